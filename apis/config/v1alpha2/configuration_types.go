@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
 )
@@ -43,6 +44,24 @@ type Configuration struct {
 	// unsuspended, they will start immediately.
 	ManageJobsWithoutQueueName bool `json:"manageJobsWithoutQueueName"`
 
+	// ManagedJobsNamespaceSelector limits the namespaces whose jobs without
+	// the annotation kueue.x-k8s.io/queue-name are managed, when
+	// manageJobsWithoutQueueName is true, to the ones whose labels match the
+	// selector. The jobs with a queue name are managed in any namespace.
+	// Defaults to a selector that excludes the kube-system namespace and the
+	// namespace in which Kueue is deployed.
+	// +optional
+	ManagedJobsNamespaceSelector *metav1.LabelSelector `json:"managedJobsNamespaceSelector,omitempty"`
+
+	// DefaultLocalQueue is the name of the LocalQueue that Kueue assigns to the
+	// jobs created without the annotation kueue.x-k8s.io/queue-name, in the
+	// namespaces that have a LocalQueue with this name. A namespace can set
+	// its own default LocalQueue with the annotation
+	// kueue.x-k8s.io/default-queue-name, which takes precedence.
+	// Not set by default.
+	// +optional
+	DefaultLocalQueue string `json:"defaultLocalQueue,omitempty"`
+
 	// InternalCertManagement is configuration for internalCertManagement
 	InternalCertManagement *InternalCertManagement `json:"internalCertManagement,omitempty"`
 
@@ -52,9 +71,250 @@ type Configuration struct {
 	// started job has all pods running (ready).
 	WaitForPodsReady *WaitForPodsReady `json:"waitForPodsReady,omitempty"`
 
+	// RequeuingBackoff is configuration to delay the admission attempts of
+	// workloads that repeatedly can't be admitted, so that they don't
+	// dominate every scheduling cycle.
+	RequeuingBackoff *RequeuingBackoff `json:"requeuingBackoff,omitempty"`
+
+	// QueueVisibility is configuration to expose the pending workloads at the
+	// head of the ClusterQueues in their status.
+	QueueVisibility *QueueVisibility `json:"queueVisibility,omitempty"`
+
+	// VisibilityServer is configuration for the API server that serves the
+	// pending workloads of the ClusterQueues and LocalQueues on demand.
+	VisibilityServer *VisibilityServer `json:"visibilityServer,omitempty"`
+
+	// ExtendedResources is configuration for the admission of workloads that
+	// request extended resources, such as nvidia.com/gpu.
+	ExtendedResources *ExtendedResources `json:"extendedResources,omitempty"`
+
+	// Resources is configuration for how Kueue accounts for the resources
+	// that the workloads request.
+	Resources *Resources `json:"resources,omitempty"`
+
+	// LocalQueueValidation is configuration for the validation of the
+	// LocalQueues against their ClusterQueues.
+	LocalQueueValidation *LocalQueueValidation `json:"localQueueValidation,omitempty"`
+
+	// TopologyAwareScheduling is configuration for the admission of pod sets
+	// into the domains of the Topologies of the ResourceFlavors.
+	TopologyAwareScheduling *TopologyAwareScheduling `json:"topologyAwareScheduling,omitempty"`
+
+	// FlavorCapacity is configuration to report, in the status of the
+	// ResourceFlavors, the capacity of the nodes that back them.
+	FlavorCapacity *FlavorCapacity `json:"flavorCapacity,omitempty"`
+
+	// QuotaAutoSizing is configuration for the ClusterQueue quotas declared as
+	// a percentage of the capacity of the nodes of their flavors.
+	QuotaAutoSizing *QuotaAutoSizing `json:"quotaAutoSizing,omitempty"`
+
+	// UnreliableFlavors is configuration for the eviction of the workloads
+	// whose pods are disrupted because the nodes of an unreliable
+	// ResourceFlavor are reclaimed.
+	UnreliableFlavors *UnreliableFlavors `json:"unreliableFlavors,omitempty"`
+
+	// UnschedulableEviction is configuration for the eviction of the admitted
+	// workloads whose pods can't be scheduled because the nodes of their
+	// flavors are gone.
+	UnschedulableEviction *UnschedulableEviction `json:"unschedulableEviction,omitempty"`
+
+	// ProvisioningRequest is configuration for the controller of the
+	// AdmissionChecks that provision capacity with cluster-autoscaler
+	// ProvisioningRequests.
+	ProvisioningRequest *ProvisioningRequest `json:"provisioningRequest,omitempty"`
+
+	// PodIntegration is configuration for the admission of plain Pods, and
+	// groups of Pods, that set the annotation kueue.x-k8s.io/queue-name.
+	PodIntegration *PodIntegration `json:"podIntegration,omitempty"`
+
+	// ObjectRetentionPolicies is configuration for the deletion of the
+	// objects that Kueue manages once they are no longer needed.
+	ObjectRetentionPolicies *ObjectRetentionPolicies `json:"objectRetentionPolicies,omitempty"`
+
 	// ClientConnection provides additional configuration options for Kubernetes
 	// API server client.
 	ClientConnection *ClientConnection `json:"clientConnection,omitempty"`
+
+	// Integrations provides configuration options for the integrations of
+	// Kueue with job frameworks.
+	Integrations *Integrations `json:"integrations,omitempty"`
+
+	// AdmissionScope limits the jobs that Kueue queues to the ones that
+	// request some resources, so that Kueue can be adopted gradually in
+	// clusters where only some resources, such as accelerators, are scarce.
+	AdmissionScope *AdmissionScope `json:"admissionScope,omitempty"`
+
+	// Accounting is configuration for the periodic export of the quota used
+	// by the admitted workloads, for chargeback.
+	Accounting *Accounting `json:"accounting,omitempty"`
+
+	// AdmissionAudit is configuration for recording the reasoning of every
+	// admission decision of the scheduler.
+	AdmissionAudit *AdmissionAudit `json:"admissionAudit,omitempty"`
+
+	// Scheduler is configuration for the scheduling cycles.
+	Scheduler *Scheduler `json:"scheduler,omitempty"`
+
+	// CacheVerification is configuration for the periodic verification of
+	// the quota usage held in memory against the admitted workloads.
+	CacheVerification *CacheVerification `json:"cacheVerification,omitempty"`
+
+	// ClusterQueueValidation is configuration for the validation of the
+	// updates of the ClusterQueues.
+	ClusterQueueValidation *ClusterQueueValidation `json:"clusterQueueValidation,omitempty"`
+
+	// Pprof is configuration for the server of the profiling data.
+	Pprof *Pprof `json:"pprof,omitempty"`
+
+	// Logging is configuration for the logs. The fields that are set take
+	// precedence over the zap flags.
+	Logging *Logging `json:"logging,omitempty"`
+}
+
+type LoggingFormat string
+
+const (
+	LoggingFormatJSON    LoggingFormat = "json"
+	LoggingFormatConsole LoggingFormat = "console"
+)
+
+type Logging struct {
+	// Format is the encoding of the logs, json or console.
+	// Defaults to the encoding of the --zap-encoder flag.
+	// +optional
+	Format *LoggingFormat `json:"format,omitempty"`
+
+	// Verbosity is the verbosity of the logs. The messages logged at a
+	// higher V-level are dropped.
+	// Defaults to the level of the --zap-log-level flag.
+	// +optional
+	Verbosity *int32 `json:"verbosity,omitempty"`
+
+	// LoggerVerbosity overrides the verbosity of the loggers with the given
+	// names, and of the loggers nested under them, for example, scheduler or
+	// scheduler.preemption. The most specific name takes precedence.
+	// +optional
+	LoggerVerbosity map[string]int32 `json:"loggerVerbosity,omitempty"`
+}
+
+type Pprof struct {
+	// Enable when true, indicates that Kueue serves the profiling data of the
+	// Go runtime, in the format of the net/http/pprof package, at
+	// /debug/pprof/. The endpoints are not authenticated, so the address
+	// shouldn't be reachable from outside of the cluster.
+	// Defaults to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// BindAddress is the address that the profiling server binds to.
+	// Defaults to :8083.
+	// +optional
+	BindAddress *string `json:"bindAddress,omitempty"`
+}
+
+type AdmissionScope struct {
+	// ResourcePrefixes are the prefixes of the names of the resources, such
+	// as nvidia.com/, that make a job subject to queueing. The jobs whose pods
+	// don't request any resource whose name starts with one of the prefixes
+	// bypass Kueue, even if they have a queue name: they are neither
+	// suspended nor queued.
+	// If empty, all the jobs are subject to queueing.
+	ResourcePrefixes []string `json:"resourcePrefixes,omitempty"`
+}
+
+type Accounting struct {
+	// Enable when true, indicates that Kueue exports, at every interval, a
+	// report with the quota that each workload used in the interval, per
+	// podSet, resource and flavor, to the sink. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// Interval is the period covered by each report. Defaults to 1h.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Sink is where the reports are written. Exactly one of its fields must
+	// be set.
+	Sink AccountingSink `json:"sink"`
+}
+
+type AccountingSink struct {
+	// ConfigMap is the name of a ConfigMap, in the namespace of Kueue, whose
+	// report.csv key holds the last report.
+	// +optional
+	ConfigMap *string `json:"configMap,omitempty"`
+
+	// URL is an HTTP endpoint to which each report is posted as JSON.
+	// +optional
+	URL *string `json:"url,omitempty"`
+
+	// Directory is a path in which each report is written as a CSV file,
+	// such as a volume backed by an object store bucket.
+	// +optional
+	Directory *string `json:"directory,omitempty"`
+}
+
+type CacheVerification struct {
+	// Enable when true, indicates that Kueue compares, at every interval,
+	// the workloads and quota usage of the ClusterQueues held in memory with
+	// the admitted workloads, and repairs the differences that persist for
+	// two intervals, such as the ones caused by missed events. It defaults
+	// to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// Interval is the period between verifications. Defaults to 5m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+type AdmissionAudit struct {
+	// Enable when true, indicates that the scheduler emits an
+	// AdmissionDecision event for every workload that it admits, skips or
+	// preempts for, with the assigned flavors, the borrowed quota and the
+	// preempted workloads in the kueue.x-k8s.io/admission-decision
+	// annotation of the event. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// DecisionLogPath is a file to which the scheduler also appends each
+	// decision as a JSON line. Events expire, so the file allows keeping the
+	// decisions for as long as needed.
+	// +optional
+	DecisionLogPath *string `json:"decisionLogPath,omitempty"`
+}
+
+type Scheduler struct {
+	// Shards is the number of shards in which the ClusterQueues are split,
+	// by the root of their hierarchy of cohorts. As the ClusterQueues of
+	// different shards don't share quota, the scheduler runs the cycles of
+	// the shards concurrently. It can only be greater than 1 if neither
+	// waitForPodsReady nor topologyAwareScheduling are enabled, as they make
+	// the admissions in different cohorts depend on each other.
+	// Defaults to 1.
+	// +optional
+	Shards *int32 `json:"shards,omitempty"`
+
+	// CohortConcurrency is the number of hierarchies of cohorts whose head
+	// workloads are nominated and admitted concurrently in each scheduling
+	// cycle, each with the part of the snapshot of the cache for the
+	// hierarchy. As for shards, it can only be greater than 1 if neither
+	// waitForPodsReady nor topologyAwareScheduling are enabled.
+	// Defaults to 1.
+	// +optional
+	CohortConcurrency *int32 `json:"cohortConcurrency,omitempty"`
+
+	// AdmissionPatchWorkers is the maximum number of admission patches that
+	// the scheduler applies to the apiserver concurrently, so that the cycles
+	// with many admissions are not dominated by the API round-trips. The
+	// admissions don't wait for their patches to be applied, they are assumed
+	// in the cache before.
+	// Defaults to 8.
+	// +optional
+	AdmissionPatchWorkers *int32 `json:"admissionPatchWorkers,omitempty"`
+
+	// FieldManager is the field manager with which the scheduler applies the
+	// admissions and preemptions of the workloads. Set it when another
+	// controller applies changes to the workloads with the default manager.
+	// Defaults to kueue-admission.
+	// +optional
+	FieldManager *string `json:"fieldManager,omitempty"`
 }
 
 type WaitForPodsReady struct {
@@ -72,6 +332,248 @@ type WaitForPodsReady struct {
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
+type RequeuingBackoff struct {
+	// Enable when true, indicates that a workload that couldn't be admitted
+	// is not considered again for admission until its backoff expires. The
+	// backoff grows exponentially with the number of consecutive failed
+	// admission attempts. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// BaseDelay is the backoff after the first failed admission attempt.
+	// It doubles with every subsequent failed attempt. Defaults to 1s.
+	// +optional
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the maximum backoff. Defaults to 10min.
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+
+	// Jitter is the maximum fraction of the backoff that is randomly added
+	// to it, to spread the retries of workloads that failed at the same time.
+	// Defaults to 0.1.
+	// +optional
+	Jitter *float64 `json:"jitter,omitempty"`
+
+	// BackoffLimitCount is the number of times that a workload can be
+	// evicted and requeued, because its pods didn't become ready within the
+	// timeout of waitForPodsReady or because an admission check requested a
+	// retry, before it's deactivated. There is no limit if not set.
+	// +optional
+	BackoffLimitCount *int32 `json:"backoffLimitCount,omitempty"`
+
+	// DeactivatedJobPolicy is what Kueue does with the job of a workload
+	// that is deactivated for exceeding the backoffLimitCount. With Keep,
+	// the job stays suspended until the workload is reactivated. With Fail,
+	// the job is marked as failed, if its integration supports it, and kept
+	// otherwise. With Delete, the job is deleted. Defaults to Keep.
+	// +optional
+	DeactivatedJobPolicy *DeactivatedJobPolicy `json:"deactivatedJobPolicy,omitempty"`
+}
+
+type DeactivatedJobPolicy string
+
+const (
+	// DeactivatedJobKeep keeps the job suspended.
+	DeactivatedJobKeep DeactivatedJobPolicy = "Keep"
+
+	// DeactivatedJobFail marks the job as failed.
+	DeactivatedJobFail DeactivatedJobPolicy = "Fail"
+
+	// DeactivatedJobDelete deletes the job.
+	DeactivatedJobDelete DeactivatedJobPolicy = "Delete"
+)
+
+type QueueVisibility struct {
+	// Enable when true, indicates that the ClusterQueues expose their top
+	// pending workloads, with their positions and priorities, in
+	// .status.pendingWorkloadsStatus. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// MaxCount is the maximum number of pending workloads exposed in the
+	// status of each ClusterQueue. Defaults to 10.
+	// +optional
+	MaxCount *int32 `json:"maxCount,omitempty"`
+
+	// UpdateInterval is the minimum time between two updates of the pending
+	// workloads in the status of a ClusterQueue. Defaults to 5s.
+	// +optional
+	UpdateInterval *metav1.Duration `json:"updateInterval,omitempty"`
+}
+
+type VisibilityServer struct {
+	// Enable when true, indicates that Kueue runs an aggregated API server for
+	// the visibility.kueue.x-k8s.io API group, which serves the pending
+	// workloads of the ClusterQueues and LocalQueues directly from the
+	// in-memory queues. It requires the APIService of the group to be
+	// installed. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// Port is the port that the visibility server serves at.
+	// Defaults to 8082.
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
+type ExtendedResources struct {
+	// ValidateNodes when true, indicates that a flavor can only be assigned to
+	// an extended resource if any of the nodes selected by the flavor's
+	// nodeSelector exposes the resource in its allocatable resources.
+	// Kueue watches the Nodes to track their labels and extended resources.
+	// It defaults to false.
+	ValidateNodes bool `json:"validateNodes,omitempty"`
+}
+
+type Resources struct {
+	// ExcludeResourcePrefixes are the prefixes of the names of the resources
+	// that Kueue ignores in the requests of the workloads, such as
+	// "hugepages-" or the extended resources of a vendor. The ClusterQueues
+	// don't need to define quotas for them, and the workloads requesting them
+	// are admitted regardless. The exclusion is applied before the
+	// transformations, so an excluded resource isn't transformed either.
+	ExcludeResourcePrefixes []string `json:"excludeResourcePrefixes,omitempty"`
+
+	// Transformations are rules that replace, or complement, the requests of
+	// a resource with requests of other resources when Kueue computes the
+	// requests of the workloads, so that quotas can be expressed in normalized
+	// units. For example, a MIG slice can be accounted as a fraction of
+	// nvidia.com/gpu, or ephemeral-storage can be ignored. The transformations
+	// are applied to the requests of each pod, and only once, so the outputs
+	// of a transformation are not transformed again. Each input resource can
+	// have at most one transformation.
+	Transformations []ResourceTransformation `json:"transformations,omitempty"`
+}
+
+type ResourceTransformationStrategy string
+
+const (
+	// Retain keeps the requests of the input resource, in addition to the
+	// outputs.
+	Retain ResourceTransformationStrategy = "Retain"
+	// Replace removes the requests of the input resource, leaving only the
+	// outputs.
+	Replace ResourceTransformationStrategy = "Replace"
+)
+
+type ResourceTransformation struct {
+	// Input is the name of the resource that is transformed.
+	Input corev1.ResourceName `json:"input"`
+
+	// Strategy is whether the requests of the input resource are kept
+	// (Retain) or removed (Replace). Defaults to Retain.
+	// +optional
+	Strategy *ResourceTransformationStrategy `json:"strategy,omitempty"`
+
+	// Outputs are the quantities of resources that each unit of the input
+	// resource is accounted as. The outputs are added to the requests of the
+	// pod and rounded up, except for cpu, which is accounted in millicores.
+	// A transformation without outputs, and the Replace strategy, removes
+	// the input resource from the requests.
+	// +optional
+	Outputs corev1.ResourceList `json:"outputs,omitempty"`
+}
+
+type LocalQueueValidation struct {
+	// NamespaceSelector when true, indicates that the creation of a
+	// LocalQueue is rejected if its ClusterQueue exists and the
+	// namespaceSelector of the ClusterQueue doesn't match the namespace of the
+	// LocalQueue. Existing LocalQueues are not affected. It defaults to false,
+	// so that clusters can fix their queues before enabling it.
+	NamespaceSelector bool `json:"namespaceSelector,omitempty"`
+}
+
+type ClusterQueueValidation struct {
+	// QuotaUpdateWarnings when true, indicates that an update of the quotas
+	// of a ClusterQueue is answered with a warning that lists the admitted
+	// workloads that wouldn't fit in the new quotas. The update is not
+	// rejected. It defaults to false.
+	QuotaUpdateWarnings bool `json:"quotaUpdateWarnings,omitempty"`
+}
+
+type TopologyAwareScheduling struct {
+	// Enable when true, indicates that the pod sets that request a topology
+	// are admitted into a single domain of the Topology of the assigned
+	// flavor, when there is enough capacity in the nodes of the domain.
+	// Kueue watches the Nodes and the Topologies to track the domains.
+	// If false, the topology requests are ignored. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+}
+
+type FlavorCapacity struct {
+	// Enable when true, indicates that Kueue watches the Nodes and reports in
+	// the status of each ResourceFlavor the number of ready nodes that match
+	// its nodeSelector and taints, and the sum of their allocatable
+	// resources. The NodesAvailable condition is False, and a warning event
+	// is emitted, when no node matches the flavor. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+}
+
+type QuotaAutoSizing struct {
+	// Enable when true, indicates that Kueue watches the Nodes and computes
+	// the min quota of the ClusterQueue flavors that set minPercentage from
+	// the allocatable capacity of the nodes selected by the flavor,
+	// recomputing it as nodes join or leave. If false, such quotas are 0. It
+	// defaults to false.
+	Enable bool `json:"enable,omitempty"`
+}
+
+type UnreliableFlavors struct {
+	// Enable when true, indicates that Kueue watches the Pods and evicts the
+	// workloads whose pods are disrupted because their node, selected by a
+	// ResourceFlavor marked as unreliable, is reclaimed. The evicted
+	// workloads are requeued without the flavor for the number of retries
+	// that the flavor configures. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+}
+
+type UnschedulableEviction struct {
+	// Enable when true, indicates that Kueue watches the Pods and evicts the
+	// admitted workloads whose pods are unschedulable for longer than the
+	// timeout, when no node matches the nodeSelector of a flavor assigned to
+	// them anymore, for example after a scale-down or a change of the node
+	// labels. The workloads are requeued without the flavor for their next
+	// admission. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+
+	// Timeout is the time that a pod has to be unschedulable before its
+	// workload is evicted. Defaults to 5min.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+type ProvisioningRequest struct {
+	// Enable when true, indicates that Kueue runs the controller of the
+	// AdmissionChecks with the kueue.x-k8s.io/provisioning-request
+	// controllerName. The controller creates a ProvisioningRequest for each
+	// workload that reserves quota with such a check, and marks the check
+	// Ready once cluster-autoscaler provisions the capacity. It requires the
+	// ProvisioningRequest API to be installed. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+}
+
+type PodIntegration struct {
+	// Enable when true, indicates that the Pods that set the annotation
+	// kueue.x-k8s.io/queue-name, and aren't owned by a batch/v1.Job, are
+	// created with a scheduling gate, which Kueue removes once their Workload
+	// is admitted. The Deployments and StatefulSets that set the annotation
+	// have their Pods managed the same way, with a Workload per Pod or a
+	// single Workload per StatefulSet, respectively. It requires Kubernetes
+	// 1.27 or newer. It defaults to false.
+	Enable bool `json:"enable,omitempty"`
+}
+
+type ObjectRetentionPolicies struct {
+	// Workloads is the retention policy of the Workloads.
+	Workloads *WorkloadRetentionPolicy `json:"workloads,omitempty"`
+}
+
+type WorkloadRetentionPolicy struct {
+	// AfterFinished is the time that a Workload is kept after it finishes,
+	// before Kueue deletes it. If not set, the finished Workloads are not
+	// deleted.
+	// +optional
+	AfterFinished *metav1.Duration `json:"afterFinished,omitempty"`
+}
+
 type InternalCertManagement struct {
 
 	// Enable controls whether to enable internal cert management or not.
@@ -96,3 +598,20 @@ type ClientConnection struct {
 	// Burst allows extra queries to accumulate when a client is exceeding its rate.
 	Burst *int32 `json:"burst,omitempty"`
 }
+
+type Integrations struct {
+	// Frameworks are the names of the job frameworks that Kueue manages.
+	// Possible values are "batch/job", "kubeflow.org/mpijob",
+	// "kubeflow.org/tfjob", "kubeflow.org/pytorchjob", "kubeflow.org/xgboostjob",
+	// "ray.io/rayjob" and "ray.io/raycluster". The CRDs of the frameworks must
+	// be installed when Kueue starts.
+	// Defaults to "batch/job" when the integrations are not set.
+	Frameworks []string `json:"frameworks,omitempty"`
+
+	// ExternalFrameworks are the kinds of the custom jobs, in the format
+	// "Kind.version.group", that Kueue manages through a generic adapter.
+	// The jobs opt in with the kueue.x-k8s.io/queue-name label, and describe
+	// their pod sets with the kueue.x-k8s.io/pod-sets annotation. Kueue needs
+	// to be granted access to the kinds.
+	ExternalFrameworks []string `json:"externalFrameworks,omitempty"`
+}
@@ -19,6 +19,7 @@ package v1alpha2
 import (
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
@@ -30,12 +31,27 @@ const (
 	DefaultWebhookServiceName     = "kueue-webhook-service"
 	DefaultWebhookSecretName      = "kueue-webhook-server-cert"
 	DefaultWebhookPort            = 9443
+	DefaultVisibilityServerPort   = 8082
 	DefaultHealthProbeBindAddress = ":8081"
 	DefaultMetricsBindAddress     = ":8080"
+	DefaultPprofBindAddress       = ":8083"
 	DefaultLeaderElectionID       = "c1f6bfd2.kueue.x-k8s.io"
 	DefaultClientConnectionQPS    = 20.0
 	DefaultClientConnectionBurst  = 30
+	DefaultJobFrameworkName       = "batch/job"
 	defaultPodsReadyTimeout       = 5 * time.Minute
+	defaultUnschedulableTimeout   = 5 * time.Minute
+	defaultRequeuingBaseDelay     = time.Second
+	defaultRequeuingMaxDelay      = 10 * time.Minute
+	defaultRequeuingJitter        = 0.1
+	defaultQueueVisibilityCount   = 10
+	defaultQueueVisibilityPeriod  = 5 * time.Second
+	defaultAccountingInterval     = time.Hour
+	defaultCacheVerifyInterval    = 5 * time.Minute
+	defaultLeaderElectionLock     = "leases"
+	defaultLeaseDuration          = 15 * time.Second
+	defaultRenewDeadline          = 10 * time.Second
+	defaultRetryPeriod            = 2 * time.Second
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
@@ -50,6 +66,9 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if cfg.Namespace == nil {
 		cfg.Namespace = pointer.String(DefaultNamespace)
 	}
+	if cfg.ManagedJobsNamespaceSelector == nil {
+		cfg.ManagedJobsNamespaceSelector = defaultManagedJobsNamespaceSelector(*cfg.Namespace)
+	}
 	if cfg.Webhook.Port == nil {
 		cfg.Webhook.Port = pointer.Int(DefaultWebhookPort)
 	}
@@ -59,9 +78,8 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if len(cfg.Health.HealthProbeBindAddress) == 0 {
 		cfg.Health.HealthProbeBindAddress = DefaultHealthProbeBindAddress
 	}
-	if cfg.LeaderElection != nil && cfg.LeaderElection.LeaderElect != nil &&
-		*cfg.LeaderElection.LeaderElect && len(cfg.LeaderElection.ResourceName) == 0 {
-		cfg.LeaderElection.ResourceName = DefaultLeaderElectionID
+	if cfg.LeaderElection != nil && cfg.LeaderElection.LeaderElect != nil && *cfg.LeaderElection.LeaderElect {
+		setLeaderElectionDefaults(cfg)
 	}
 	if cfg.InternalCertManagement == nil {
 		cfg.InternalCertManagement = &InternalCertManagement{}
@@ -89,4 +107,95 @@ func SetDefaults_Configuration(cfg *Configuration) {
 	if cfg.WaitForPodsReady != nil && cfg.WaitForPodsReady.Timeout == nil {
 		cfg.WaitForPodsReady.Timeout = &metav1.Duration{Duration: defaultPodsReadyTimeout}
 	}
+	if cfg.UnschedulableEviction != nil && cfg.UnschedulableEviction.Timeout == nil {
+		cfg.UnschedulableEviction.Timeout = &metav1.Duration{Duration: defaultUnschedulableTimeout}
+	}
+	if cfg.Integrations == nil {
+		cfg.Integrations = &Integrations{
+			Frameworks: []string{DefaultJobFrameworkName},
+		}
+	}
+	if cfg.RequeuingBackoff != nil {
+		if cfg.RequeuingBackoff.BaseDelay == nil {
+			cfg.RequeuingBackoff.BaseDelay = &metav1.Duration{Duration: defaultRequeuingBaseDelay}
+		}
+		if cfg.RequeuingBackoff.MaxDelay == nil {
+			cfg.RequeuingBackoff.MaxDelay = &metav1.Duration{Duration: defaultRequeuingMaxDelay}
+		}
+		if cfg.RequeuingBackoff.Jitter == nil {
+			cfg.RequeuingBackoff.Jitter = pointer.Float64(defaultRequeuingJitter)
+		}
+		if cfg.RequeuingBackoff.DeactivatedJobPolicy == nil {
+			policy := DeactivatedJobKeep
+			cfg.RequeuingBackoff.DeactivatedJobPolicy = &policy
+		}
+	}
+	if cfg.Resources != nil {
+		for i := range cfg.Resources.Transformations {
+			t := &cfg.Resources.Transformations[i]
+			if t.Strategy == nil {
+				strategy := Retain
+				t.Strategy = &strategy
+			}
+		}
+	}
+	if cfg.VisibilityServer != nil && cfg.VisibilityServer.Port == nil {
+		cfg.VisibilityServer.Port = pointer.Int32(DefaultVisibilityServerPort)
+	}
+	if cfg.QueueVisibility != nil {
+		if cfg.QueueVisibility.MaxCount == nil {
+			cfg.QueueVisibility.MaxCount = pointer.Int32(defaultQueueVisibilityCount)
+		}
+		if cfg.QueueVisibility.UpdateInterval == nil {
+			cfg.QueueVisibility.UpdateInterval = &metav1.Duration{Duration: defaultQueueVisibilityPeriod}
+		}
+	}
+	if cfg.Accounting != nil && cfg.Accounting.Interval == nil {
+		cfg.Accounting.Interval = &metav1.Duration{Duration: defaultAccountingInterval}
+	}
+	if cfg.CacheVerification != nil && cfg.CacheVerification.Interval == nil {
+		cfg.CacheVerification.Interval = &metav1.Duration{Duration: defaultCacheVerifyInterval}
+	}
+	if cfg.Pprof != nil && cfg.Pprof.BindAddress == nil {
+		cfg.Pprof.BindAddress = pointer.String(DefaultPprofBindAddress)
+	}
+}
+
+// defaultManagedJobsNamespaceSelector returns a selector that excludes the
+// kube-system namespace and the namespace in which Kueue is deployed.
+func defaultManagedJobsNamespaceSelector(namespace string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      corev1.LabelMetadataName,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{metav1.NamespaceSystem, namespace},
+			},
+		},
+	}
+}
+
+// setLeaderElectionDefaults sets the defaults of the leader election. The lock
+// is a Lease in the namespace of Kueue, whose transitions are the fencing
+// tokens of the scheduler.
+func setLeaderElectionDefaults(cfg *Configuration) {
+	le := cfg.LeaderElection
+	if len(le.ResourceName) == 0 {
+		le.ResourceName = DefaultLeaderElectionID
+	}
+	if len(le.ResourceNamespace) == 0 {
+		le.ResourceNamespace = *cfg.Namespace
+	}
+	if len(le.ResourceLock) == 0 {
+		le.ResourceLock = defaultLeaderElectionLock
+	}
+	if le.LeaseDuration.Duration == 0 {
+		le.LeaseDuration = metav1.Duration{Duration: defaultLeaseDuration}
+	}
+	if le.RenewDeadline.Duration == 0 {
+		le.RenewDeadline = metav1.Duration{Duration: defaultRenewDeadline}
+	}
+	if le.RetryPeriod.Duration == 0 {
+		le.RetryPeriod = metav1.Duration{Duration: defaultRetryPeriod}
+	}
 }
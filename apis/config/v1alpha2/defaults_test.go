@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	componentconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	"k8s.io/utils/pointer"
@@ -51,8 +53,18 @@ func TestSetDefaults_Configuration(t *testing.T) {
 		QPS:   pointer.Float32(DefaultClientConnectionQPS),
 		Burst: pointer.Int32(DefaultClientConnectionBurst),
 	}
+	defaultIntegrations := &Integrations{
+		Frameworks: []string{DefaultJobFrameworkName},
+	}
 	podsReadyTimeoutTimeout := metav1.Duration{Duration: defaultPodsReadyTimeout}
 	podsReadyTimeoutOverwrite := metav1.Duration{Duration: time.Minute}
+	requeuingBaseDelay := metav1.Duration{Duration: defaultRequeuingBaseDelay}
+	requeuingMaxDelay := metav1.Duration{Duration: defaultRequeuingMaxDelay}
+	requeuingMaxDelayOverwrite := metav1.Duration{Duration: time.Hour}
+	deactivatedJobKeep := DeactivatedJobKeep
+	deactivatedJobDelete := DeactivatedJobDelete
+	retain := Retain
+	replace := Replace
 
 	testCases := map[string]struct {
 		original *Configuration
@@ -66,11 +78,13 @@ func TestSetDefaults_Configuration(t *testing.T) {
 			},
 			want: &Configuration{
 				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
 				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"defaulting ControllerManagerConfigurationSpec": {
@@ -85,7 +99,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 				},
 			},
 			want: &Configuration{
-				Namespace: pointer.String(DefaultNamespace),
+				Namespace:                    pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(DefaultNamespace),
 				ControllerManagerConfigurationSpec: ctrlconfigv1alpha1.ControllerManagerConfigurationSpec{
 					Webhook: ctrlconfigv1alpha1.ControllerWebhook{
 						Port: pointer.Int(DefaultWebhookPort),
@@ -97,14 +112,20 @@ func TestSetDefaults_Configuration(t *testing.T) {
 						HealthProbeBindAddress: DefaultHealthProbeBindAddress,
 					},
 					LeaderElection: &componentconfigv1alpha1.LeaderElectionConfiguration{
-						LeaderElect:  pointer.Bool(true),
-						ResourceName: DefaultLeaderElectionID,
+						LeaderElect:       pointer.Bool(true),
+						LeaseDuration:     metav1.Duration{Duration: defaultLeaseDuration},
+						RenewDeadline:     metav1.Duration{Duration: defaultRenewDeadline},
+						RetryPeriod:       metav1.Duration{Duration: defaultRetryPeriod},
+						ResourceLock:      defaultLeaderElectionLock,
+						ResourceName:      DefaultLeaderElectionID,
+						ResourceNamespace: DefaultNamespace,
 					},
 				},
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"should not default ControllerManagerConfigurationSpec": {
@@ -120,8 +141,13 @@ func TestSetDefaults_Configuration(t *testing.T) {
 						HealthProbeBindAddress: overwriteHealthProbeBindAddress,
 					},
 					LeaderElection: &componentconfigv1alpha1.LeaderElectionConfiguration{
-						LeaderElect:  pointer.Bool(true),
-						ResourceName: overwriteLeaderElectionID,
+						LeaderElect:       pointer.Bool(true),
+						LeaseDuration:     metav1.Duration{Duration: time.Minute},
+						RenewDeadline:     metav1.Duration{Duration: 40 * time.Second},
+						RetryPeriod:       metav1.Duration{Duration: 5 * time.Second},
+						ResourceLock:      "configmapsleases",
+						ResourceName:      overwriteLeaderElectionID,
+						ResourceNamespace: overwriteNamespace,
 					},
 				},
 				InternalCertManagement: &InternalCertManagement{
@@ -129,7 +155,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 				},
 			},
 			want: &Configuration{
-				Namespace: pointer.String(DefaultNamespace),
+				Namespace:                    pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(DefaultNamespace),
 				ControllerManagerConfigurationSpec: ctrlconfigv1alpha1.ControllerManagerConfigurationSpec{
 					Webhook: ctrlconfigv1alpha1.ControllerWebhook{
 						Port: pointer.Int(overwriteWebhookPort),
@@ -141,14 +168,20 @@ func TestSetDefaults_Configuration(t *testing.T) {
 						HealthProbeBindAddress: overwriteHealthProbeBindAddress,
 					},
 					LeaderElection: &componentconfigv1alpha1.LeaderElectionConfiguration{
-						LeaderElect:  pointer.Bool(true),
-						ResourceName: overwriteLeaderElectionID,
+						LeaderElect:       pointer.Bool(true),
+						LeaseDuration:     metav1.Duration{Duration: time.Minute},
+						RenewDeadline:     metav1.Duration{Duration: 40 * time.Second},
+						RetryPeriod:       metav1.Duration{Duration: 5 * time.Second},
+						ResourceLock:      "configmapsleases",
+						ResourceName:      overwriteLeaderElectionID,
+						ResourceNamespace: overwriteNamespace,
 					},
 				},
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"should not set LeaderElectionID": {
@@ -163,7 +196,8 @@ func TestSetDefaults_Configuration(t *testing.T) {
 				},
 			},
 			want: &Configuration{
-				Namespace: pointer.String(DefaultNamespace),
+				Namespace:                    pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(DefaultNamespace),
 				ControllerManagerConfigurationSpec: ctrlconfigv1alpha1.ControllerManagerConfigurationSpec{
 					Webhook: ctrlconfigv1alpha1.ControllerWebhook{
 						Port: pointer.Int(DefaultWebhookPort),
@@ -182,6 +216,7 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"defaulting InternalCertManagement": {
@@ -190,6 +225,7 @@ func TestSetDefaults_Configuration(t *testing.T) {
 			},
 			want: &Configuration{
 				Namespace:                          pointer.String(overwriteNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(overwriteNamespace),
 				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
 				InternalCertManagement: &InternalCertManagement{
 					Enable:             pointer.Bool(true),
@@ -197,6 +233,25 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					WebhookSecretName:  pointer.String(DefaultWebhookSecretName),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"should not default ManagedJobsNamespaceSelector": {
+			original: &Configuration{
+				ManagedJobsNamespaceSelector: &metav1.LabelSelector{},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       &metav1.LabelSelector{},
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"should not default InternalCertManagement": {
@@ -208,11 +263,13 @@ func TestSetDefaults_Configuration(t *testing.T) {
 			},
 			want: &Configuration{
 				Namespace:                          pointer.String(overwriteNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(overwriteNamespace),
 				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"should not default values in custom ClientConnection": {
@@ -228,6 +285,7 @@ func TestSetDefaults_Configuration(t *testing.T) {
 			},
 			want: &Configuration{
 				Namespace:                          pointer.String(overwriteNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(overwriteNamespace),
 				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
@@ -236,6 +294,29 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					QPS:   pointer.Float32(123.0),
 					Burst: pointer.Int32(456),
 				},
+				Integrations: defaultIntegrations,
+			},
+		},
+		"should not default custom Integrations": {
+			original: &Configuration{
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				Integrations: &Integrations{
+					Frameworks: []string{"kubeflow.org/mpijob"},
+				},
+			},
+			want: &Configuration{
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations: &Integrations{
+					Frameworks: []string{"kubeflow.org/mpijob"},
+				},
 			},
 		},
 		"should default empty custom ClientConnection": {
@@ -248,11 +329,13 @@ func TestSetDefaults_Configuration(t *testing.T) {
 			},
 			want: &Configuration{
 				Namespace:                          pointer.String(overwriteNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(overwriteNamespace),
 				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"defaulting waitForPodsReady.timeout": {
@@ -270,11 +353,85 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Timeout: &podsReadyTimeoutTimeout,
 				},
 				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting unschedulableEviction.timeout": {
+			original: &Configuration{
+				UnschedulableEviction: &UnschedulableEviction{
+					Enable: true,
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				UnschedulableEviction: &UnschedulableEviction{
+					Enable:  true,
+					Timeout: &metav1.Duration{Duration: defaultUnschedulableTimeout},
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting accounting.interval": {
+			original: &Configuration{
+				Accounting: &Accounting{
+					Enable: true,
+					Sink:   AccountingSink{ConfigMap: pointer.String("kueue-usage")},
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				Accounting: &Accounting{
+					Enable:   true,
+					Interval: &metav1.Duration{Duration: defaultAccountingInterval},
+					Sink:     AccountingSink{ConfigMap: pointer.String("kueue-usage")},
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting cacheVerification.interval": {
+			original: &Configuration{
+				CacheVerification: &CacheVerification{Enable: true},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				CacheVerification: &CacheVerification{
+					Enable:   true,
+					Interval: &metav1.Duration{Duration: defaultCacheVerifyInterval},
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
 				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 		"respecting provided waitForPodsReady.timeout": {
@@ -293,11 +450,189 @@ func TestSetDefaults_Configuration(t *testing.T) {
 					Timeout: &podsReadyTimeoutOverwrite,
 				},
 				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting requeuingBackoff": {
+			original: &Configuration{
+				RequeuingBackoff: &RequeuingBackoff{
+					Enable: true,
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				RequeuingBackoff: &RequeuingBackoff{
+					Enable:               true,
+					BaseDelay:            &requeuingBaseDelay,
+					MaxDelay:             &requeuingMaxDelay,
+					Jitter:               pointer.Float64(defaultRequeuingJitter),
+					DeactivatedJobPolicy: &deactivatedJobKeep,
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"respecting provided requeuingBackoff": {
+			original: &Configuration{
+				RequeuingBackoff: &RequeuingBackoff{
+					Enable:               true,
+					MaxDelay:             &requeuingMaxDelayOverwrite,
+					Jitter:               pointer.Float64(0),
+					DeactivatedJobPolicy: &deactivatedJobDelete,
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				RequeuingBackoff: &RequeuingBackoff{
+					Enable:               true,
+					BaseDelay:            &requeuingBaseDelay,
+					MaxDelay:             &requeuingMaxDelayOverwrite,
+					Jitter:               pointer.Float64(0),
+					DeactivatedJobPolicy: &deactivatedJobDelete,
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting queueVisibility": {
+			original: &Configuration{
+				QueueVisibility: &QueueVisibility{
+					Enable: true,
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				QueueVisibility: &QueueVisibility{
+					Enable:         true,
+					MaxCount:       pointer.Int32(defaultQueueVisibilityCount),
+					UpdateInterval: &metav1.Duration{Duration: defaultQueueVisibilityPeriod},
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting visibilityServer": {
+			original: &Configuration{
+				VisibilityServer: &VisibilityServer{
+					Enable: true,
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				VisibilityServer: &VisibilityServer{
+					Enable: true,
+					Port:   pointer.Int32(DefaultVisibilityServerPort),
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting pprof": {
+			original: &Configuration{
+				Pprof: &Pprof{
+					Enable: true,
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				Pprof: &Pprof{
+					Enable:      true,
+					BindAddress: pointer.String(DefaultPprofBindAddress),
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
+				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
+			},
+		},
+		"defaulting resource transformations": {
+			original: &Configuration{
+				Resources: &Resources{
+					Transformations: []ResourceTransformation{
+						{
+							Input: "nvidia.com/mig-1g.5gb",
+							Outputs: corev1.ResourceList{
+								"nvidia.com/gpu": resource.MustParse("500m"),
+							},
+						},
+						{
+							Input:    corev1.ResourceEphemeralStorage,
+							Strategy: &replace,
+						},
+					},
+				},
+				InternalCertManagement: &InternalCertManagement{
+					Enable: pointer.Bool(false),
+				},
+			},
+			want: &Configuration{
+				Resources: &Resources{
+					Transformations: []ResourceTransformation{
+						{
+							Input:    "nvidia.com/mig-1g.5gb",
+							Strategy: &retain,
+							Outputs: corev1.ResourceList{
+								"nvidia.com/gpu": resource.MustParse("500m"),
+							},
+						},
+						{
+							Input:    corev1.ResourceEphemeralStorage,
+							Strategy: &replace,
+						},
+					},
+				},
+				Namespace:                          pointer.String(DefaultNamespace),
+				ManagedJobsNamespaceSelector:       defaultManagedJobsNamespaceSelector(DefaultNamespace),
 				ControllerManagerConfigurationSpec: defaultCtrlManagerConfigurationSpec,
 				InternalCertManagement: &InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 		},
 	}
@@ -22,10 +22,122 @@ limitations under the License.
 package v1alpha2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Accounting) DeepCopyInto(out *Accounting) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.Sink.DeepCopyInto(&out.Sink)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Accounting.
+func (in *Accounting) DeepCopy() *Accounting {
+	if in == nil {
+		return nil
+	}
+	out := new(Accounting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountingSink) DeepCopyInto(out *AccountingSink) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(string)
+		**out = **in
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.Directory != nil {
+		in, out := &in.Directory, &out.Directory
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingSink.
+func (in *AccountingSink) DeepCopy() *AccountingSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountingSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionAudit) DeepCopyInto(out *AdmissionAudit) {
+	*out = *in
+	if in.DecisionLogPath != nil {
+		in, out := &in.DecisionLogPath, &out.DecisionLogPath
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionAudit.
+func (in *AdmissionAudit) DeepCopy() *AdmissionAudit {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionAudit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionScope) DeepCopyInto(out *AdmissionScope) {
+	*out = *in
+	if in.ResourcePrefixes != nil {
+		in, out := &in.ResourcePrefixes, &out.ResourcePrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionScope.
+func (in *AdmissionScope) DeepCopy() *AdmissionScope {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheVerification) DeepCopyInto(out *CacheVerification) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheVerification.
+func (in *CacheVerification) DeepCopy() *CacheVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConnection) DeepCopyInto(out *ClientConnection) {
 	*out = *in
@@ -51,6 +163,21 @@ func (in *ClientConnection) DeepCopy() *ClientConnection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQueueValidation) DeepCopyInto(out *ClusterQueueValidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQueueValidation.
+func (in *ClusterQueueValidation) DeepCopy() *ClusterQueueValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQueueValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Configuration) DeepCopyInto(out *Configuration) {
 	*out = *in
@@ -61,6 +188,11 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		**out = **in
 	}
 	in.ControllerManagerConfigurationSpec.DeepCopyInto(&out.ControllerManagerConfigurationSpec)
+	if in.ManagedJobsNamespaceSelector != nil {
+		in, out := &in.ManagedJobsNamespaceSelector, &out.ManagedJobsNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InternalCertManagement != nil {
 		in, out := &in.InternalCertManagement, &out.InternalCertManagement
 		*out = new(InternalCertManagement)
@@ -71,11 +203,126 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(WaitForPodsReady)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RequeuingBackoff != nil {
+		in, out := &in.RequeuingBackoff, &out.RequeuingBackoff
+		*out = new(RequeuingBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueueVisibility != nil {
+		in, out := &in.QueueVisibility, &out.QueueVisibility
+		*out = new(QueueVisibility)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VisibilityServer != nil {
+		in, out := &in.VisibilityServer, &out.VisibilityServer
+		*out = new(VisibilityServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtendedResources != nil {
+		in, out := &in.ExtendedResources, &out.ExtendedResources
+		*out = new(ExtendedResources)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(Resources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalQueueValidation != nil {
+		in, out := &in.LocalQueueValidation, &out.LocalQueueValidation
+		*out = new(LocalQueueValidation)
+		**out = **in
+	}
+	if in.TopologyAwareScheduling != nil {
+		in, out := &in.TopologyAwareScheduling, &out.TopologyAwareScheduling
+		*out = new(TopologyAwareScheduling)
+		**out = **in
+	}
+	if in.FlavorCapacity != nil {
+		in, out := &in.FlavorCapacity, &out.FlavorCapacity
+		*out = new(FlavorCapacity)
+		**out = **in
+	}
+	if in.QuotaAutoSizing != nil {
+		in, out := &in.QuotaAutoSizing, &out.QuotaAutoSizing
+		*out = new(QuotaAutoSizing)
+		**out = **in
+	}
+	if in.UnreliableFlavors != nil {
+		in, out := &in.UnreliableFlavors, &out.UnreliableFlavors
+		*out = new(UnreliableFlavors)
+		**out = **in
+	}
+	if in.UnschedulableEviction != nil {
+		in, out := &in.UnschedulableEviction, &out.UnschedulableEviction
+		*out = new(UnschedulableEviction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningRequest != nil {
+		in, out := &in.ProvisioningRequest, &out.ProvisioningRequest
+		*out = new(ProvisioningRequest)
+		**out = **in
+	}
+	if in.PodIntegration != nil {
+		in, out := &in.PodIntegration, &out.PodIntegration
+		*out = new(PodIntegration)
+		**out = **in
+	}
+	if in.ObjectRetentionPolicies != nil {
+		in, out := &in.ObjectRetentionPolicies, &out.ObjectRetentionPolicies
+		*out = new(ObjectRetentionPolicies)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ClientConnection != nil {
 		in, out := &in.ClientConnection, &out.ClientConnection
 		*out = new(ClientConnection)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Integrations != nil {
+		in, out := &in.Integrations, &out.Integrations
+		*out = new(Integrations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdmissionScope != nil {
+		in, out := &in.AdmissionScope, &out.AdmissionScope
+		*out = new(AdmissionScope)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Accounting != nil {
+		in, out := &in.Accounting, &out.Accounting
+		*out = new(Accounting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdmissionAudit != nil {
+		in, out := &in.AdmissionAudit, &out.AdmissionAudit
+		*out = new(AdmissionAudit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduler != nil {
+		in, out := &in.Scheduler, &out.Scheduler
+		*out = new(Scheduler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CacheVerification != nil {
+		in, out := &in.CacheVerification, &out.CacheVerification
+		*out = new(CacheVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterQueueValidation != nil {
+		in, out := &in.ClusterQueueValidation, &out.ClusterQueueValidation
+		*out = new(ClusterQueueValidation)
+		**out = **in
+	}
+	if in.Pprof != nil {
+		in, out := &in.Pprof, &out.Pprof
+		*out = new(Pprof)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Configuration.
@@ -96,6 +343,61 @@ func (in *Configuration) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtendedResources) DeepCopyInto(out *ExtendedResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedResources.
+func (in *ExtendedResources) DeepCopy() *ExtendedResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtendedResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlavorCapacity) DeepCopyInto(out *FlavorCapacity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlavorCapacity.
+func (in *FlavorCapacity) DeepCopy() *FlavorCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(FlavorCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Integrations) DeepCopyInto(out *Integrations) {
+	*out = *in
+	if in.Frameworks != nil {
+		in, out := &in.Frameworks, &out.Frameworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalFrameworks != nil {
+		in, out := &in.ExternalFrameworks, &out.ExternalFrameworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Integrations.
+func (in *Integrations) DeepCopy() *Integrations {
+	if in == nil {
+		return nil
+	}
+	out := new(Integrations)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InternalCertManagement) DeepCopyInto(out *InternalCertManagement) {
 	*out = *in
@@ -126,6 +428,362 @@ func (in *InternalCertManagement) DeepCopy() *InternalCertManagement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalQueueValidation) DeepCopyInto(out *LocalQueueValidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueValidation.
+func (in *LocalQueueValidation) DeepCopy() *LocalQueueValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalQueueValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logging) DeepCopyInto(out *Logging) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(LoggingFormat)
+		**out = **in
+	}
+	if in.Verbosity != nil {
+		in, out := &in.Verbosity, &out.Verbosity
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LoggerVerbosity != nil {
+		in, out := &in.LoggerVerbosity, &out.LoggerVerbosity
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logging.
+func (in *Logging) DeepCopy() *Logging {
+	if in == nil {
+		return nil
+	}
+	out := new(Logging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectRetentionPolicies) DeepCopyInto(out *ObjectRetentionPolicies) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = new(WorkloadRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRetentionPolicies.
+func (in *ObjectRetentionPolicies) DeepCopy() *ObjectRetentionPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectRetentionPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodIntegration) DeepCopyInto(out *PodIntegration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodIntegration.
+func (in *PodIntegration) DeepCopy() *PodIntegration {
+	if in == nil {
+		return nil
+	}
+	out := new(PodIntegration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pprof) DeepCopyInto(out *Pprof) {
+	*out = *in
+	if in.BindAddress != nil {
+		in, out := &in.BindAddress, &out.BindAddress
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pprof.
+func (in *Pprof) DeepCopy() *Pprof {
+	if in == nil {
+		return nil
+	}
+	out := new(Pprof)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningRequest) DeepCopyInto(out *ProvisioningRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningRequest.
+func (in *ProvisioningRequest) DeepCopy() *ProvisioningRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueVisibility) DeepCopyInto(out *QueueVisibility) {
+	*out = *in
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UpdateInterval != nil {
+		in, out := &in.UpdateInterval, &out.UpdateInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueVisibility.
+func (in *QueueVisibility) DeepCopy() *QueueVisibility {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueVisibility)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaAutoSizing) DeepCopyInto(out *QuotaAutoSizing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaAutoSizing.
+func (in *QuotaAutoSizing) DeepCopy() *QuotaAutoSizing {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaAutoSizing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeuingBackoff) DeepCopyInto(out *RequeuingBackoff) {
+	*out = *in
+	if in.BaseDelay != nil {
+		in, out := &in.BaseDelay, &out.BaseDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxDelay != nil {
+		in, out := &in.MaxDelay, &out.MaxDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Jitter != nil {
+		in, out := &in.Jitter, &out.Jitter
+		*out = new(float64)
+		**out = **in
+	}
+	if in.BackoffLimitCount != nil {
+		in, out := &in.BackoffLimitCount, &out.BackoffLimitCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeactivatedJobPolicy != nil {
+		in, out := &in.DeactivatedJobPolicy, &out.DeactivatedJobPolicy
+		*out = new(DeactivatedJobPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeuingBackoff.
+func (in *RequeuingBackoff) DeepCopy() *RequeuingBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeuingBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTransformation) DeepCopyInto(out *ResourceTransformation) {
+	*out = *in
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(ResourceTransformationStrategy)
+		**out = **in
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTransformation.
+func (in *ResourceTransformation) DeepCopy() *ResourceTransformation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTransformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Resources) DeepCopyInto(out *Resources) {
+	*out = *in
+	if in.ExcludeResourcePrefixes != nil {
+		in, out := &in.ExcludeResourcePrefixes, &out.ExcludeResourcePrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Transformations != nil {
+		in, out := &in.Transformations, &out.Transformations
+		*out = make([]ResourceTransformation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resources.
+func (in *Resources) DeepCopy() *Resources {
+	if in == nil {
+		return nil
+	}
+	out := new(Resources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scheduler) DeepCopyInto(out *Scheduler) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CohortConcurrency != nil {
+		in, out := &in.CohortConcurrency, &out.CohortConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AdmissionPatchWorkers != nil {
+		in, out := &in.AdmissionPatchWorkers, &out.AdmissionPatchWorkers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FieldManager != nil {
+		in, out := &in.FieldManager, &out.FieldManager
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scheduler.
+func (in *Scheduler) DeepCopy() *Scheduler {
+	if in == nil {
+		return nil
+	}
+	out := new(Scheduler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyAwareScheduling) DeepCopyInto(out *TopologyAwareScheduling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyAwareScheduling.
+func (in *TopologyAwareScheduling) DeepCopy() *TopologyAwareScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyAwareScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnreliableFlavors) DeepCopyInto(out *UnreliableFlavors) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnreliableFlavors.
+func (in *UnreliableFlavors) DeepCopy() *UnreliableFlavors {
+	if in == nil {
+		return nil
+	}
+	out := new(UnreliableFlavors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnschedulableEviction) DeepCopyInto(out *UnschedulableEviction) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnschedulableEviction.
+func (in *UnschedulableEviction) DeepCopy() *UnschedulableEviction {
+	if in == nil {
+		return nil
+	}
+	out := new(UnschedulableEviction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VisibilityServer) DeepCopyInto(out *VisibilityServer) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VisibilityServer.
+func (in *VisibilityServer) DeepCopy() *VisibilityServer {
+	if in == nil {
+		return nil
+	}
+	out := new(VisibilityServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WaitForPodsReady) DeepCopyInto(out *WaitForPodsReady) {
 	*out = *in
@@ -145,3 +803,23 @@ func (in *WaitForPodsReady) DeepCopy() *WaitForPodsReady {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadRetentionPolicy) DeepCopyInto(out *WorkloadRetentionPolicy) {
+	*out = *in
+	if in.AfterFinished != nil {
+		in, out := &in.AfterFinished, &out.AfterFinished
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadRetentionPolicy.
+func (in *WorkloadRetentionPolicy) DeepCopy() *WorkloadRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AdmissionCheckSpec defines the desired state of AdmissionCheck
+type AdmissionCheckSpec struct {
+	// controllerName is the name of the controller that sets the state of the
+	// check in the workloads admitted by the ClusterQueues that use the
+	// AdmissionCheck. The controller can be external to Kueue.
+	// Kueue implements the controller kueue.x-k8s.io/provisioning-request.
+	ControllerName string `json:"controllerName"`
+
+	// provisioningRequest holds the parameters of the ProvisioningRequests
+	// created by the kueue.x-k8s.io/provisioning-request controller.
+	// +optional
+	ProvisioningRequest *ProvisioningRequestParameters `json:"provisioningRequest,omitempty"`
+}
+
+type ProvisioningRequestParameters struct {
+	// provisioningClassName is the class of the ProvisioningRequests, which
+	// determines how cluster-autoscaler provisions the capacity for them.
+	ProvisioningClassName string `json:"provisioningClassName"`
+
+	// parameters are the parameters of the ProvisioningRequests, which are
+	// interpreted based on the provisioningClassName.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+const (
+	// AdmissionCheckActive indicates that the controller of the AdmissionCheck
+	// is running and its parameters are valid. ClusterQueues that use an
+	// AdmissionCheck that is not active don't admit new workloads.
+	AdmissionCheckActive = "Active"
+)
+
+// AdmissionCheckStatus defines the observed state of AdmissionCheck
+type AdmissionCheckStatus struct {
+	// conditions hold the latest available observations of the AdmissionCheck
+	// current state. The controller of the AdmissionCheck sets the Active
+	// condition.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Controller",JSONPath=".spec.controllerName",type=string,description="Name of the controller of the AdmissionCheck"
+
+// AdmissionCheck is the Schema for the admissionchecks API. The workloads
+// admitted by a ClusterQueue that uses an AdmissionCheck reserve quota, but
+// they only start once the controller of the AdmissionCheck marks the check
+// as Ready.
+type AdmissionCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AdmissionCheckSpec   `json:"spec,omitempty"`
+	Status AdmissionCheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AdmissionCheckList contains a list of AdmissionCheck
+type AdmissionCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AdmissionCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AdmissionCheck{}, &AdmissionCheckList{})
+}
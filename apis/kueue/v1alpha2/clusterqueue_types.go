@@ -24,33 +24,46 @@ import (
 
 // ClusterQueueSpec defines the desired state of ClusterQueue
 type ClusterQueueSpec struct {
-	// resources represent the total pod requests of workloads dispatched
-	// via this clusterQueue. This doesn't guarantee the actual availability of
-	// resources, although an integration with a resource provisioner like Cluster
-	// Autoscaler is possible to achieve that. Example:
+	// resourceGroups describes groups of resources. Each resource group
+	// defines the list of resources that its flavors cover, and the quota of
+	// each flavor for each of those resources. This doesn't guarantee the
+	// actual availability of resources, although an integration with a
+	// resource provisioner like Cluster Autoscaler is possible to achieve
+	// that. Example:
 	//
-	// - name: cpu
+	// - coveredResources: ["cpu", "memory"]
 	//   flavors:
 	//   - name: default
-	//     quota:
-	//       min: 100
-	// - name: memory
-	//   flavors:
-	//   - name: default
-	//     quota:
-	//       min: 100Gi
+	//     resources:
+	//     - name: cpu
+	//       quota:
+	//         min: 100
+	//     - name: memory
+	//       quota:
+	//         min: 100Gi
 	//
-	// Two resources must either have all the flavors in the same order or not
-	// have any matching flavors. When two resources match their flavors, they
-	// are said to be codependent. When a workload is admitted by this
-	// ClusterQueue, all the codependent resources that the Workload requests get
-	// assigned the same flavor.
+	// When a workload is admitted by this ClusterQueue, all the resources of
+	// a group that a pod set requests get assigned the same flavor. A resource
+	// can only be covered by one group, and a flavor can only be in one
+	// group.
 	//
-	// resources can be up to 16 elements.
+	// resourceGroups can be up to 16 elements.
+	//
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=16
+	ResourceGroups []ResourceGroup `json:"resourceGroups,omitempty"`
+
+	// resources is the deprecated list of quotas per resource, from before
+	// they were grouped into resourceGroups. Resources that have the same
+	// flavors in the same order form a group.
+	//
+	// Deprecated: use resourceGroups. When set, the webhook converts it into
+	// resourceGroups, replacing them.
 	//
 	// +listType=map
 	// +listMapKey=name
 	// +kubebuilder:validation:MaxItems=16
+	// +optional
 	Resources []Resource `json:"resources,omitempty"`
 
 	// cohort that this ClusterQueue belongs to. CQs that belong to the
@@ -80,42 +93,54 @@ type ClusterQueueSpec struct {
 	//   name: tenantA
 	// spec:
 	//   cohort: borrowing-cohort
-	//   resources:
-	//   - name: cpu
+	//   resourceGroups:
+	//   - coveredResources: ["cpu"]
 	//     flavors:
 	//     - name: spot
-	//       quota:
-	//         min: 1000
+	//       resources:
+	//       - name: cpu
+	//         quota:
+	//           min: 1000
 	//     - name: on-demand
-	//       quota:
-	//         min: 100
-	//   - name: nvidia.com/gpu
+	//       resources:
+	//       - name: cpu
+	//         quota:
+	//           min: 100
+	//   - coveredResources: ["nvidia.com/gpu"]
 	//     flavors:
 	//     - name: k80
-	//       quota:
-	//         min: 10
-	//         max: 20
+	//       resources:
+	//       - name: nvidia.com/gpu
+	//         quota:
+	//           min: 10
+	//           max: 20
 	//     - name: p100
-	//       quota:
-	//         min: 10
-	//         max: 20
+	//       resources:
+	//       - name: nvidia.com/gpu
+	//         quota:
+	//           min: 10
+	//           max: 20
 	//
 	// metadata:
-	//  name: tenantB
+	//   name: tenantB
 	// spec:
-	//  cohort: borrowing-cohort
-	//  resources:
-	//  - name: cpu
-	//    flavors:
-	//    - name: on-demand
-	//      quota:
-	//        min: 100
-	//  - name: nvidia.com/gpu
-	//    flavors:
-	//    - name: k80
-	//      quota:
-	//        min: 10
-	//        max: 20
+	//   cohort: borrowing-cohort
+	//   resourceGroups:
+	//   - coveredResources: ["cpu"]
+	//     flavors:
+	//     - name: on-demand
+	//       resources:
+	//       - name: cpu
+	//         quota:
+	//           min: 100
+	//   - coveredResources: ["nvidia.com/gpu"]
+	//     flavors:
+	//     - name: k80
+	//       resources:
+	//       - name: nvidia.com/gpu
+	//         quota:
+	//           min: 10
+	//           max: 20
 	//
 	// If empty, this ClusterQueue cannot borrow from any other ClusterQueue and vice versa.
 	//
@@ -133,11 +158,36 @@ type ClusterQueueSpec struct {
 	// - BestEffortFIFO：workloads are ordered by creation time,
 	// however older workloads that can't be admitted will not block
 	// admitting newer workloads that fit existing quota.
+	// - Priority: workloads are ordered like BestEffortFIFO, and the priority
+	// of the pending workloads can age, as set in priorityAging.
+	// - EarliestDeadlineFirst: workloads are ordered by their admission
+	// deadline, earliest first, followed by the workloads without a deadline.
+	// Ties are ordered by priority and creation time. Workloads that can't be
+	// admitted will not block admitting other workloads that fit existing
+	// quota.
 	//
 	// +kubebuilder:default=BestEffortFIFO
-	// +kubebuilder:validation:Enum=StrictFIFO;BestEffortFIFO
+	// +kubebuilder:validation:Enum=StrictFIFO;BestEffortFIFO;Priority;EarliestDeadlineFirst
 	QueueingStrategy QueueingStrategy `json:"queueingStrategy,omitempty"`
 
+	// localQueueSharing determines how the pending workloads of the
+	// localQueues pointing to this ClusterQueue share the admission attempts.
+	// Possible values are:
+	//
+	// - `None` (default): the workloads of all the localQueues are ordered
+	//   together, according to the queueingStrategy.
+	// - `Weighted`: the ClusterQueue takes turns between the localQueues with
+	//   pending workloads, according to their weights, so that a localQueue
+	//   with many pending workloads can't starve the others. The
+	//   queueingStrategy only orders the workloads within each localQueue, so
+	//   a StrictFIFO workload that can't be admitted only blocks the workloads
+	//   of its own localQueue.
+	//
+	// +optional
+	// +kubebuilder:default=None
+	// +kubebuilder:validation:Enum=None;Weighted
+	LocalQueueSharing *LocalQueueSharing `json:"localQueueSharing,omitempty"`
+
 	// namespaceSelector defines which namespaces are allowed to submit workloads to
 	// this clusterQueue. Beyond this basic support for policy, an policy agent like
 	// Gatekeeper should be used to enforce more advanced policies.
@@ -161,6 +211,173 @@ type ClusterQueueSpec struct {
 	// preempt to accomodate the pending Workload, preempting Workloads with
 	// lower priority first.
 	Preemption *ClusterQueuePreemption `json:"preemption,omitempty"`
+
+	// flavorFungibility defines whether a workload should try the next flavor
+	// before borrowing or preempting in the flavor being evaluated.
+	FlavorFungibility *FlavorFungibility `json:"flavorFungibility,omitempty"`
+
+	// admissionChecks are the names of the AdmissionChecks that the workloads
+	// admitted by this ClusterQueue have to pass, after reserving quota, to
+	// start. The ClusterQueue is inactive while any of the AdmissionChecks
+	// doesn't exist.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=8
+	AdmissionChecks []string `json:"admissionChecks,omitempty"`
+
+	// stopPolicy allows to stop the ClusterQueue for maintenance. While set
+	// to a value other than None, the ClusterQueue is inactive and doesn't
+	// reserve quota for new workloads. Possible values are:
+	//
+	// - `None` (default): the ClusterQueue operates normally.
+	// - `Hold`: the workloads that reserved quota but are still waiting for
+	//   their admission checks release the reservation. Admitted workloads
+	//   run to completion.
+	// - `HoldAndDrain`: additionally, the admitted workloads are evicted.
+	//
+	// +optional
+	// +kubebuilder:default=None
+	// +kubebuilder:validation:Enum=None;Hold;HoldAndDrain
+	StopPolicy *StopPolicy `json:"stopPolicy,omitempty"`
+
+	// overQuotaPolicy determines what happens to the admitted workloads when
+	// the usage of the ClusterQueue exceeds its quota, for example after the
+	// quota decreases. The quota is the min quota when the ClusterQueue
+	// doesn't belong to a cohort, and the max quota, if any, otherwise.
+	// Possible values are:
+	//
+	// - `Keep` (default): the admitted workloads keep running.
+	// - `Evict`: evict admitted workloads, lowest priority first, until the
+	//   usage fits in the quota.
+	//
+	// +optional
+	// +kubebuilder:default=Keep
+	// +kubebuilder:validation:Enum=Keep;Evict
+	OverQuotaPolicy *OverQuotaPolicy `json:"overQuotaPolicy,omitempty"`
+
+	// schedulingProfile determines the order in which the flavors of a
+	// resource group are tried for a pod set. Possible values are:
+	//
+	// - `Ordered` (default): the flavors are tried in the order in which they
+	//   are listed.
+	// - `BestFit`: the flavors with the lowest fraction of unused min quota
+	//   are tried first, to pack the workloads in as few flavors as possible.
+	// - `Cheapest`: the flavors with the lowest cost, as declared in the
+	//   kueue.x-k8s.io/cost annotation of the ResourceFlavors, are tried first.
+	//   Flavors without a valid cost are tried last.
+	// - `Spread`: the flavors with the highest fraction of unused min quota
+	//   are tried first, to spread the workloads across the flavors.
+	//
+	// Flavors that are equally preferred are tried in the order in which they
+	// are listed.
+	//
+	// +optional
+	// +kubebuilder:default=Ordered
+	// +kubebuilder:validation:Enum=Ordered;BestFit;Cheapest;Spread
+	SchedulingProfile *SchedulingProfile `json:"schedulingProfile,omitempty"`
+
+	// priorityAging increases the effective priority of the pending
+	// workloads of this ClusterQueue with the time they wait for admission,
+	// so that low priority workloads are not starved by a constant load of
+	// high priority workloads. The effective priority is used to order the
+	// workloads of a ClusterQueue with the Priority queueing strategy, and to
+	// find the workloads that a pending workload can preempt.
+	// If null, the effective priority is the priority of the workload.
+	// +optional
+	PriorityAging *PriorityAging `json:"priorityAging,omitempty"`
+
+	// quotaSchedules are time windows during which some quotas of the
+	// resourceGroups are replaced, for example, to offer more quota at nights
+	// or during weekends. When the windows of multiple schedules overlap, the
+	// first schedule in the list applies.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	QuotaSchedules []QuotaSchedule `json:"quotaSchedules,omitempty"`
+
+	// maximumExecutionTimeSeconds is the default maximum execution time of
+	// the workloads admitted by this ClusterQueue that don't set one, and
+	// whose LocalQueue doesn't set one either.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaximumExecutionTimeSeconds *int32 `json:"maximumExecutionTimeSeconds,omitempty"`
+}
+
+// QuotaSchedule is a recurrent time window during which some quotas of a
+// ClusterQueue are replaced.
+type QuotaSchedule struct {
+	// name of the schedule.
+	Name string `json:"name"`
+
+	// days of the week in which the window starts. If empty, the window
+	// starts every day.
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=7
+	// +optional
+	Days []ScheduleDay `json:"days,omitempty"`
+
+	// start is the time of the day, in the HH:MM format, at which the window
+	// starts.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// end is the time of the day, in the HH:MM format, at which the window
+	// ends. If end is not after start, the window ends on the next day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// timeZone is the name of the IANA time zone of start and end, for
+	// example, Europe/Madrid. Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// quotas that replace the quotas of the resourceGroups for the same
+	// flavor and resource while the window lasts.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=64
+	Quotas []ScheduledQuota `json:"quotas"`
+}
+
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type ScheduleDay string
+
+type ScheduledQuota struct {
+	// flavor is the name of the ResourceFlavor, which has to be listed in
+	// the resourceGroups.
+	Flavor ResourceFlavorReference `json:"flavor"`
+
+	// resource is the name of the resource, which has to be covered by the
+	// flavor in the resourceGroups.
+	Resource corev1.ResourceName `json:"resource"`
+
+	// quota is the quota of the flavor and resource while the window lasts.
+	Quota Quota `json:"quota"`
+}
+
+// PriorityAging describes how the effective priority of a pending workload
+// increases with the time that it waits for admission. The waiting time is
+// measured since the workload was created or, if it was evicted, since it was
+// requeued.
+type PriorityAging struct {
+	// intervalSeconds is the waiting time, in seconds, after which the
+	// effective priority increases by step.
+	// +kubebuilder:validation:Minimum=1
+	IntervalSeconds int32 `json:"intervalSeconds"`
+
+	// step is the increase of the effective priority for every interval that
+	// the workload waits. Defaults to 1.
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	Step int32 `json:"step,omitempty"`
+
+	// maxBoost is the maximum increase of the effective priority over the
+	// priority of the workload.
+	// If null, the effective priority increases without limit.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxBoost *int32 `json:"maxBoost,omitempty"`
 }
 
 type QueueingStrategy string
@@ -175,13 +392,30 @@ const (
 	// however older workloads that can't be admitted will not block
 	// admitting newer workloads that fit existing quota.
 	BestEffortFIFO QueueingStrategy = "BestEffortFIFO"
+
+	// Priority means that workloads are ordered like BestEffortFIFO, and the
+	// priority of the pending workloads can age, as set in priorityAging.
+	Priority QueueingStrategy = "Priority"
+
+	// EarliestDeadlineFirst means that workloads are ordered by their
+	// admission deadline, earliest first, followed by the workloads without
+	// a deadline. Ties are ordered by priority and creation time. Workloads
+	// that can't be admitted will not block admitting other workloads that
+	// fit existing quota.
+	EarliestDeadlineFirst QueueingStrategy = "EarliestDeadlineFirst"
 )
 
-type Resource struct {
-	// name of the resource. For example, cpu, memory or nvidia.com/gpu.
-	Name corev1.ResourceName `json:"name"`
+type ResourceGroup struct {
+	// coveredResources is the list of resources covered by the flavors in this
+	// group. For example, cpu, memory or nvidia.com/gpu.
+	// The list can't be empty and it can contain up to 16 resources.
+	//
+	// +listType=set
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	CoveredResources []corev1.ResourceName `json:"coveredResources"`
 
-	// flavors is the list of different flavors of this resource and their limits.
+	// flavors is the list of flavors that provide the resources of this group.
 	// Typically two different “flavors” of the same resource represent
 	// different hardware models (e.g., gpu models, cpu architectures) or
 	// pricing (on-demand vs spot cpus). The flavors are distinguished via labels and
@@ -191,16 +425,18 @@ type Resource struct {
 	// different limits for different gpu models, then each model is mapped to a
 	// flavor and must set different values of a shared key. For example:
 	//
-	// spec:
-	//  resources:
-	//  - name: nvidia.com/gpu
-	//    flavors:
-	//    - name: k80
-	//      quota:
-	//        min: 10
-	//    - name: p100
-	//      quota:
-	//        min: 10
+	// - coveredResources: ["nvidia.com/gpu"]
+	//   flavors:
+	//   - name: k80
+	//     resources:
+	//     - name: nvidia.com/gpu
+	//       quota:
+	//         min: 10
+	//   - name: p100
+	//     resources:
+	//     - name: nvidia.com/gpu
+	//       quota:
+	//         min: 10
 	//
 	// The flavors are evaluated in order, selecting the first to satisfy a
 	// workload’s requirements. Also the quantities are additive, in the example
@@ -215,9 +451,46 @@ type Resource struct {
 	// +listMapKey=name
 	// +kubebuilder:validation:MaxItems=16
 	// +kubebuilder:validation:MinItems=1
+	Flavors []FlavorQuotas `json:"flavors"`
+}
+
+type FlavorQuotas struct {
+	// name is a reference to the resourceFlavor that defines this flavor.
+	// The name "*" declares a wildcard flavor, which must be the only flavor
+	// of its group. The covered resources of a group with the wildcard flavor
+	// are assigned whatever flavor is assigned to the other resources of a pod
+	// set, and their usage is accounted in the quota of the wildcard flavor.
+	// A pod set can't be admitted if it only requests resources of groups
+	// with the wildcard flavor.
+	// +kubebuilder:default=default
+	Name ResourceFlavorReference `json:"name"`
+
+	// resources is the list of quotas for this flavor per resource.
+	// There must be exactly one element for each covered resource of the
+	// group, in the same order as the coveredResources.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:MinItems=1
+	Resources []ResourceQuota `json:"resources"`
+}
+
+// Resource is the deprecated format of the quotas of a resource.
+type Resource struct {
+	// name of the resource. For example, cpu, memory or nvidia.com/gpu.
+	Name corev1.ResourceName `json:"name"`
+
+	// flavors is the list of different flavors of this resource and their limits.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:MinItems=1
 	Flavors []Flavor `json:"flavors"`
 }
 
+// Flavor is the deprecated format of the quota of a resource in a flavor.
 type Flavor struct {
 	// name is a reference to the resourceFlavor that defines this flavor.
 	// +kubebuilder:default=default
@@ -227,17 +500,45 @@ type Flavor struct {
 	Quota Quota `json:"quota"`
 }
 
+type ResourceQuota struct {
+	// name of the resource. For example, cpu, memory or nvidia.com/gpu.
+	Name corev1.ResourceName `json:"name"`
+
+	// quota is the limit of resource usage at a point in time.
+	Quota Quota `json:"quota"`
+}
+
 // ResourceFlavorReference is the name of the ResourceFlavor.
 type ResourceFlavorReference string
 
+// AnyFlavor is the name of the wildcard flavor, which covers resources with
+// whatever flavor is assigned to the other resources of a pod set.
+const AnyFlavor ResourceFlavorReference = "*"
+
 type Quota struct {
 	// min quantity of resource requests that are available to be used by workloads
 	// admitted by this ClusterQueue at a point in time.
-	// The quantity must be positive.
+	// The quantity must be non-negative. A flavor with 0 min quota can only be
+	// used with unused quota borrowed from the cohort, up to max or
+	// borrowingLimit, so that a ClusterQueue can define the shapes of the
+	// workloads that it runs without owning any quota.
 	// The sum of min quotas for a flavor in a cohort defines the maximum amount
 	// of resources that can be allocated by a ClusterQueue in the cohort.
 	Min resource.Quantity `json:"min,omitempty"`
 
+	// minPercentage declares the min quota as a percentage of the total
+	// allocatable quantity of the resource in the nodes selected by the
+	// nodeSelector of the flavor. The effective min quota is recomputed as
+	// nodes join or leave the cluster, so that it tracks cluster autoscaling.
+	// It requires quota auto-sizing to be enabled in the Kueue configuration;
+	// otherwise, the effective min quota is 0.
+	// If not null, min must be 0. If max is not null, the effective min quota
+	// doesn't exceed max, and lendingLimit doesn't exceed the effective min.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MinPercentage *int32 `json:"minPercentage,omitempty"`
+
 	// max is the upper limit on the quantity of resource requests that
 	// can be used by workloads admitted by this ClusterQueue at a point in time.
 	// Resources can be borrowed from unused min quota of other
@@ -245,6 +546,34 @@ type Quota struct {
 	// If not null, it must be greater than or equal to min.
 	// If null, there is no upper limit for borrowing.
 	Max *resource.Quantity `json:"max,omitempty"`
+
+	// borrowingLimit is the maximum quantity of resource requests that this
+	// ClusterQueue can borrow from the unused min quota of other ClusterQueues
+	// in the same cohort, beyond its own min quota.
+	// If both max and borrowingLimit are set, the lowest of max and
+	// min+borrowingLimit is enforced.
+	// If not null, it must be non-negative.
+	// If null, the borrowing is only limited by max.
+	// +optional
+	BorrowingLimit *resource.Quantity `json:"borrowingLimit,omitempty"`
+
+	// lendingLimit is the maximum quantity of the min quota that other
+	// ClusterQueues in the same cohort can borrow when it is unused.
+	// The rest of the min quota is kept for the workloads of this ClusterQueue.
+	// If not null, it must be non-negative and less than or equal to min.
+	// If null, all the min quota can be lent.
+	// +optional
+	LendingLimit *resource.Quantity `json:"lendingLimit,omitempty"`
+
+	// maxPerNamespace is the upper limit on the quantity of resource requests
+	// that can be used by the workloads of a single namespace admitted by this
+	// ClusterQueue at a point in time, so that a namespace can't use all the
+	// quota of the ClusterQueue, even if it is unused.
+	// If not null, it must be positive and, if max is not null, less than or
+	// equal to max.
+	// If null, there is no limit per namespace.
+	// +optional
+	MaxPerNamespace *resource.Quantity `json:"maxPerNamespace,omitempty"`
 }
 
 // ClusterQueueStatus defines the observed state of ClusterQueue
@@ -264,6 +593,13 @@ type ClusterQueueStatus struct {
 	// +optional
 	AdmittedWorkloads int32 `json:"admittedWorkloads"`
 
+	// PendingWorkloadsStatus contains the pending workloads at the head of
+	// this clusterQueue, in the order in which they are considered for
+	// admission. It's only populated when queueVisibility is enabled in the
+	// Kueue configuration.
+	// +optional
+	PendingWorkloadsStatus *ClusterQueuePendingWorkloadsStatus `json:"pendingWorkloadsStatus,omitempty"`
+
 	// conditions hold the latest available observations of the ClusterQueue
 	// current state.
 	// +optional
@@ -274,10 +610,43 @@ type ClusterQueueStatus struct {
 
 type UsedResources map[corev1.ResourceName]map[string]Usage
 
+type ClusterQueuePendingWorkloadsStatus struct {
+	// Head contains the top pending workloads, up to the maxCount configured
+	// in queueVisibility.
+	// +listType=atomic
+	// +optional
+	Head []ClusterQueuePendingWorkload `json:"head,omitempty"`
+
+	// LastChangeTime is the last time that the head changed.
+	LastChangeTime metav1.Time `json:"lastChangeTime"`
+}
+
+// ClusterQueuePendingWorkload identifies a pending workload and its place in
+// the clusterQueue.
+type ClusterQueuePendingWorkload struct {
+	// Name is the name of the workload.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the workload.
+	Namespace string `json:"namespace"`
+
+	// Position is the position of the workload in the clusterQueue, starting
+	// from 0 for the next workload to be considered for admission.
+	Position int32 `json:"position"`
+
+	// Priority is the priority of the workload.
+	Priority int32 `json:"priority"`
+}
+
 const (
 	// ClusterQueueActive indicates that the ClusterQueue can admit new workloads and its quota
 	// can be borrowed by other ClusterQueues in the same cohort.
 	ClusterQueueActive string = "Active"
+
+	// ClusterQueueDeletionBlocked indicates that the deletion of the
+	// ClusterQueue waits for the workloads that it admitted, which are listed
+	// in the message, to finish or be evicted.
+	ClusterQueueDeletionBlocked string = "DeletionBlocked"
 )
 
 type Usage struct {
@@ -289,12 +658,60 @@ type Usage struct {
 	Borrowed *resource.Quantity `json:"borrowing,omitempty"`
 }
 
+type StopPolicy string
+
+const (
+	None         StopPolicy = "None"
+	Hold         StopPolicy = "Hold"
+	HoldAndDrain StopPolicy = "HoldAndDrain"
+)
+
+type OverQuotaPolicy string
+
+const (
+	OverQuotaPolicyKeep  OverQuotaPolicy = "Keep"
+	OverQuotaPolicyEvict OverQuotaPolicy = "Evict"
+)
+
+type LocalQueueSharing string
+
+const (
+	LocalQueueSharingNone     LocalQueueSharing = "None"
+	LocalQueueSharingWeighted LocalQueueSharing = "Weighted"
+)
+
+type SchedulingProfile string
+
+const (
+	SchedulingProfileOrdered  SchedulingProfile = "Ordered"
+	SchedulingProfileBestFit  SchedulingProfile = "BestFit"
+	SchedulingProfileCheapest SchedulingProfile = "Cheapest"
+	SchedulingProfileSpread   SchedulingProfile = "Spread"
+)
+
 type PreemptionPolicy string
 
 const (
 	PreemptionPolicyNever         PreemptionPolicy = "Never"
 	PreemptionPolicyAny           PreemptionPolicy = "Any"
 	PreemptionPolicyLowerPriority PreemptionPolicy = "LowerPriority"
+	// PreemptionPolicyLowerOrNewerEqualPriority allows to preempt the
+	// workloads with lower priority, and the workloads with the same priority
+	// that are newer than the preemptor.
+	PreemptionPolicyLowerOrNewerEqualPriority PreemptionPolicy = "LowerOrNewerEqualPriority"
+)
+
+// PreemptionVictimOrder is the order in which the candidates for preemption
+// are preempted.
+type PreemptionVictimOrder string
+
+const (
+	// PreemptionVictimOrderPriority preempts the workloads in other
+	// ClusterQueues first and, then, the ones with lower priority first.
+	PreemptionVictimOrderPriority PreemptionVictimOrder = "Priority"
+	// PreemptionVictimOrderBorrowingFirst preempts the workloads that make
+	// the ClusterQueue borrow quota before any other workload.
+	PreemptionVictimOrderBorrowingFirst PreemptionVictimOrder = "BorrowingFirst"
 )
 
 // ClusterQueuePreemption contains policies to preempt Workloads from this
@@ -323,10 +740,84 @@ type ClusterQueuePreemption struct {
 	// - `Never` (default): do not preempt workloads in the ClusterQueue.
 	// - `LowerPriority`: only preempt workloads in the ClusterQueue that have
 	//   lower priority than the pending Workload.
+	// - `LowerOrNewerEqualPriority`: preempt workloads in the ClusterQueue that
+	//   have lower priority than the pending Workload, or the same priority and
+	//   were created after the pending Workload.
 	//
 	// +kubebuilder:default=Never
-	// +kubebuilder:validation:Enum=Never;LowerPriority
+	// +kubebuilder:validation:Enum=Never;LowerPriority;LowerOrNewerEqualPriority
 	WithinClusterQueue PreemptionPolicy `json:"withinClusterQueue,omitempty"`
+
+	// victimOrder determines which of the Workloads that a pending Workload
+	// can preempt are preempted first. Possible values are:
+	//
+	// - `Priority` (default): preempt the Workloads in other ClusterQueues of
+	//   the cohort first and, then, the Workloads with lower priority first.
+	// - `BorrowingFirst`: preempt the Workloads of the ClusterQueue that make it
+	//   borrow quota from the cohort before any other Workload, so that the
+	//   ClusterQueue returns the borrowed quota before disrupting the rest of
+	//   the cohort. The borrowing is attributed to the Workloads that were
+	//   admitted last.
+	//
+	// +kubebuilder:default=Priority
+	// +kubebuilder:validation:Enum=Priority;BorrowingFirst
+	VictimOrder PreemptionVictimOrder `json:"victimOrder,omitempty"`
+}
+
+type FlavorFungibilityPolicy string
+
+const (
+	FlavorFungibilityPolicyBorrow        FlavorFungibilityPolicy = "Borrow"
+	FlavorFungibilityPolicyPreempt       FlavorFungibilityPolicy = "Preempt"
+	FlavorFungibilityPolicyTryNextFlavor FlavorFungibilityPolicy = "TryNextFlavor"
+)
+
+type PodSetPlacementPolicy string
+
+const (
+	PodSetPlacementPolicyMixed             PodSetPlacementPolicy = "Mixed"
+	PodSetPlacementPolicyPreferHomogeneous PodSetPlacementPolicy = "PreferHomogeneous"
+)
+
+// FlavorFungibility determines whether a workload should try the next flavor
+// before borrowing or preempting in the current flavor.
+type FlavorFungibility struct {
+	// whenCanBorrow determines whether a workload should try the next flavor
+	// before borrowing in the current flavor. Possible values are:
+	//
+	// - `Borrow` (default): allocate in the current flavor if borrowing
+	//   is possible.
+	// - `TryNextFlavor`: try the next flavor even if the current
+	//   flavor has enough resources to borrow.
+	//
+	// +kubebuilder:default=Borrow
+	// +kubebuilder:validation:Enum=Borrow;TryNextFlavor
+	WhenCanBorrow FlavorFungibilityPolicy `json:"whenCanBorrow,omitempty"`
+
+	// whenCanPreempt determines whether a workload should try the next flavor
+	// before preempting in the current flavor. Possible values are:
+	//
+	// - `Preempt`: allocate in the current flavor if it's possible to preempt
+	//   some workloads.
+	// - `TryNextFlavor` (default): try the next flavor even if there are
+	//   enough candidates for preemption in the current flavor.
+	//
+	// +kubebuilder:default=TryNextFlavor
+	// +kubebuilder:validation:Enum=Preempt;TryNextFlavor
+	WhenCanPreempt FlavorFungibilityPolicy `json:"whenCanPreempt,omitempty"`
+
+	// podSetPlacement determines whether the pod sets of a workload can get
+	// different flavors for the same resource. Possible values are:
+	//
+	// - `Mixed` (default): each pod set gets the flavors that fit it best,
+	//   independently of the flavors of the other pod sets.
+	// - `PreferHomogeneous`: all the pod sets get the same flavor for each
+	//   resource, if there is such a flavor where the workload fits as well
+	//   as with mixed flavors. Otherwise, the pod sets get mixed flavors.
+	//
+	// +kubebuilder:default=Mixed
+	// +kubebuilder:validation:Enum=Mixed;PreferHomogeneous
+	PodSetPlacement PodSetPlacementPolicy `json:"podSetPlacement,omitempty"`
 }
 
 //+kubebuilder:object:root=true
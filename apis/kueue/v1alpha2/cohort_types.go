@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CohortSpec defines the desired state of Cohort
+type CohortSpec struct {
+	// parent is the name of the cohort that this cohort belongs to. The
+	// ClusterQueues under a cohort can borrow unused quota from all the
+	// ClusterQueues under the root of the hierarchy of cohorts, as long as
+	// the max quotas of the cohorts in between are not exceeded.
+	// If empty, this cohort is the root of a hierarchy.
+	// +optional
+	Parent string `json:"parent,omitempty"`
+
+	// resourceGroups are the quotas of the cohort, with the same structure as
+	// the resourceGroups of a ClusterQueue.
+	// For each flavor and resource:
+	// - min is quota that the cohort adds to the quota of the ClusterQueues
+	//   and cohorts under it, which they can borrow.
+	// - max is the upper limit on the quantity of resource requests that can be
+	//   used by the workloads admitted by all the ClusterQueues under the
+	//   cohort at a point in time.
+	// - maxPerNamespace and minPercentage are not supported for cohorts.
+	//
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	ResourceGroups []ResourceGroup `json:"resourceGroups,omitempty"`
+
+	// resources is the deprecated list of quotas per resource, with the
+	// same structure as the resources of a ClusterQueue.
+	//
+	// Deprecated: use resourceGroups. When set, the webhook converts it into
+	// resourceGroups, replacing them.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Parent",JSONPath=".spec.parent",type=string,description="Parent of the cohort"
+
+// Cohort is the Schema for the cohorts API. A Cohort object configures the
+// cohort with the same name that ClusterQueues reference in their
+// .spec.cohort field.
+type Cohort struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CohortSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CohortList contains a list of Cohort
+type CohortList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Cohort `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Cohort{}, &CohortList{})
+}
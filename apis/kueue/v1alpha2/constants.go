@@ -20,4 +20,18 @@ const (
 	ResourceInUseFinalizerName = "kueue.k8s.io/resource-in-use"
 
 	DefaultPodSetName = "main"
+
+	// WorkloadPriorityClassSource is the priorityClassSource of the
+	// Workloads whose priority comes from a WorkloadPriorityClass.
+	WorkloadPriorityClassSource = "kueue.x-k8s.io/workloadpriorityclass"
+
+	// PodPriorityClassSource is the priorityClassSource of the Workloads whose
+	// priority comes from the PriorityClass of their pods.
+	PodPriorityClassSource = "scheduling.k8s.io/priorityclass"
+
+	// ResourceFlavorCostAnnotation is the annotation in a ResourceFlavor that
+	// holds its relative cost, as a non-negative decimal number. The
+	// ClusterQueues with the Cheapest scheduling profile try the cheapest
+	// flavors that fit first.
+	ResourceFlavorCostAnnotation = "kueue.x-k8s.io/cost"
 )
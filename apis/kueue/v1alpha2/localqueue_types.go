@@ -24,6 +24,25 @@ import (
 type LocalQueueSpec struct {
 	// clusterQueue is a reference to a clusterQueue that backs this localQueue.
 	ClusterQueue ClusterQueueReference `json:"clusterQueue,omitempty"`
+
+	// weight is the relative share of admission attempts that this
+	// localQueue gets, compared to other localQueues backed by the same
+	// clusterQueue, when the clusterQueue has the Weighted localQueueSharing.
+	// Pending workloads are then popped from the localQueues in weighted
+	// round-robin order, so a localQueue with many pending workloads can't
+	// starve the others. It's ignored otherwise.
+	// Defaults to 1.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+
+	// maximumExecutionTimeSeconds is the default maximum execution time of
+	// the workloads of this localQueue that don't set one. It takes precedence
+	// over the default of the clusterQueue.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaximumExecutionTimeSeconds *int32 `json:"maximumExecutionTimeSeconds,omitempty"`
 }
 
 // ClusterQueueReference is the name of the ClusterQueue.
@@ -23,6 +23,7 @@ import (
 
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:scope=Cluster,shortName={rf}
+//+kubebuilder:subresource:status
 
 // ResourceFlavor is the Schema for the resourceflavors API.
 type ResourceFlavor struct {
@@ -46,8 +47,85 @@ type ResourceFlavor struct {
 	// +listType=atomic
 	// +kubebuilder:validation:MaxItems=8
 	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// tolerations are added to the pods of the pod sets admitted with this
+	// flavor, and removed when the job is suspended, so that the pods can run
+	// in nodes with taints, such as the nodes of a dedicated node pool. Unlike
+	// the taints of the flavor, the workloads don't need to tolerate them.
+	//
+	// tolerations can be up to 8 elements.
+	//
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// topologyName is the name of the Topology of the nodes of this flavor.
+	// When set, the pod sets that request a topology are placed in a single
+	// domain of the topology, if topology aware scheduling is enabled in the
+	// Kueue configuration.
+	// +optional
+	TopologyName *string `json:"topologyName,omitempty"`
+
+	// unreliable marks the nodes of this flavor as unreliable, such as spot or
+	// preemptible VMs that the cloud provider can reclaim at any time. When
+	// the pods of a workload admitted with this flavor are disrupted because
+	// their node is reclaimed, Kueue evicts and requeues the workload, if
+	// unreliable flavors are enabled in the Kueue configuration.
+	// +optional
+	Unreliable *UnreliableNodes `json:"unreliable,omitempty"`
+
+	// +optional
+	Status ResourceFlavorStatus `json:"status,omitempty"`
 }
 
+type UnreliableNodes struct {
+	// excludeForRetries is the number of times that a workload evicted
+	// because the nodes of this flavor were reclaimed is admitted without
+	// this flavor, falling back to the next flavors of its ClusterQueue,
+	// before it can be assigned this flavor again. If 0, the workload can be
+	// assigned this flavor again right away.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ExcludeForRetries int32 `json:"excludeForRetries,omitempty"`
+}
+
+// ResourceFlavorStatus defines the observed state of the ResourceFlavor. It's
+// only set when the flavor capacity controller is enabled in the Kueue
+// configuration.
+type ResourceFlavorStatus struct {
+	// nodes is the number of ready and schedulable nodes that match the
+	// nodeSelector of the flavor and whose NoSchedule and NoExecute taints are
+	// tolerated by the taints and tolerations of the flavor.
+	// +optional
+	Nodes int32 `json:"nodes"`
+
+	// allocatable is the sum of the allocatable resources of the matching
+	// nodes.
+	// +optional
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+
+	// conditions hold the latest available observations of the
+	// ResourceFlavor current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ResourceFlavorNodesAvailable indicates whether there are ready nodes
+	// that match the flavor. It's False when the flavor is backed by zero
+	// capacity.
+	ResourceFlavorNodesAvailable string = "NodesAvailable"
+
+	// ResourceFlavorDeletionBlocked indicates that the deletion of the
+	// ResourceFlavor waits for the ClusterQueues that use it, or for the
+	// workloads admitted in it, which are listed in the message.
+	ResourceFlavorDeletionBlocked string = "DeletionBlocked"
+)
+
 //+kubebuilder:object:root=true
 
 // ResourceFlavorList contains a list of ResourceFlavor
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+// ResourceGroupsFromResources converts the deprecated resources into resource
+// groups. The resources that have the same flavors in the same order, which
+// were codependent, form a group; any other resource forms a group of its
+// own.
+func ResourceGroupsFromResources(resources []Resource) []ResourceGroup {
+	if len(resources) == 0 {
+		return nil
+	}
+	var groups []ResourceGroup
+	for i := range resources {
+		groups = AppendResource(groups, &resources[i])
+	}
+	return groups
+}
+
+// AppendResource adds the quotas of the resource to the group that has the
+// same flavors, in the same order, or to a new group if there is none.
+func AppendResource(groups []ResourceGroup, r *Resource) []ResourceGroup {
+	i := 0
+	for ; i < len(groups); i++ {
+		if sameFlavors(groups[i].Flavors, r.Flavors) {
+			break
+		}
+	}
+	if i == len(groups) {
+		rg := ResourceGroup{Flavors: make([]FlavorQuotas, len(r.Flavors))}
+		for j, f := range r.Flavors {
+			rg.Flavors[j].Name = f.Name
+		}
+		groups = append(groups, rg)
+	}
+	rg := &groups[i]
+	rg.CoveredResources = append(rg.CoveredResources, r.Name)
+	for j, f := range r.Flavors {
+		rg.Flavors[j].Resources = append(rg.Flavors[j].Resources, ResourceQuota{
+			Name:  r.Name,
+			Quota: *f.Quota.DeepCopy(),
+		})
+	}
+	return groups
+}
+
+func sameFlavors(fqs []FlavorQuotas, flavors []Flavor) bool {
+	if len(fqs) != len(flavors) {
+		return false
+	}
+	for i := range fqs {
+		if fqs[i].Name != flavors[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// EffectiveResourceGroups returns the resource groups of the ClusterQueue,
+// converted from the deprecated resources when they are set.
+func (s *ClusterQueueSpec) EffectiveResourceGroups() []ResourceGroup {
+	if len(s.Resources) > 0 {
+		return ResourceGroupsFromResources(s.Resources)
+	}
+	return s.ResourceGroups
+}
+
+// EffectiveResourceGroups returns the resource groups of the Cohort,
+// converted from the deprecated resources when they are set.
+func (s *CohortSpec) EffectiveResourceGroups() []ResourceGroup {
+	if len(s.Resources) > 0 {
+		return ResourceGroupsFromResources(s.Resources)
+	}
+	return s.ResourceGroups
+}
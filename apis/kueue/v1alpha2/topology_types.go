@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TopologySpec defines the desired state of Topology
+type TopologySpec struct {
+	// levels define the levels of the topology, from the highest, such as a
+	// zone, to the lowest, such as a rack. The nodes in the same domain of a
+	// level have the same value for the node label of the level and of all
+	// the levels above it.
+	//
+	// levels can be up to 8 elements.
+	//
+	// +listType=atomic
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=8
+	Levels []TopologyLevel `json:"levels"`
+}
+
+type TopologyLevel struct {
+	// nodeLabel is the label of the nodes that identifies the domain of the
+	// level that a node belongs to.
+	NodeLabel string `json:"nodeLabel"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// Topology is the Schema for the topologies API. ResourceFlavors reference
+// a Topology to place the pods of a pod set in the same topology domain.
+type Topology struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TopologySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TopologyList contains a list of Topology
+type TopologyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Topology `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Topology{}, &TopologyList{})
+}
@@ -18,6 +18,7 @@ package v1alpha2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -39,23 +40,104 @@ type WorkloadSpec struct {
 	QueueName string `json:"queueName,omitempty"`
 
 	// admission holds the parameters of the admission of the workload by a ClusterQueue.
-	// admission cannot be changed once set.
+	// admission cannot be changed once set, except for the counts of the
+	// podSetFlavors, which can be changed to the counts in podSetResizes.
 	Admission *Admission `json:"admission,omitempty"`
 
+	// podSetResizes holds requests to change the number of pods of the podSets
+	// of an admitted workload. An increase is admitted in place, with the same
+	// flavors, once there is enough quota for the additional pods. A decrease
+	// releases the quota of the removed pods.
+	// The requests are ignored while the workload is not admitted.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	PodSetResizes []PodSetResize `json:"podSetResizes,omitempty"`
+
 	// If specified, indicates the workload's priority.
 	// "system-node-critical" and "system-cluster-critical" are two special
 	// keywords which indicate the highest priorities with the former being
 	// the highest priority. Any other name must be defined by creating a
-	// PriorityClass object with that name. If not specified, the workload
-	// priority will be default or zero if there is no default.
+	// PriorityClass object with that name, or a WorkloadPriorityClass object
+	// when priorityClassSource is kueue.x-k8s.io/workloadpriorityclass. If not
+	// specified, the workload priority will be default or zero if there is no
+	// default.
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// priorityClassSource is the kind of the class in priorityClassName:
+	// kueue.x-k8s.io/workloadpriorityclass for a WorkloadPriorityClass, or
+	// scheduling.k8s.io/priorityclass for the PriorityClass of the pods.
+	// +kubebuilder:default=""
+	// +kubebuilder:validation:Enum=kueue.x-k8s.io/workloadpriorityclass;scheduling.k8s.io/priorityclass;""
+	PriorityClassSource string `json:"priorityClassSource,omitempty"`
+
 	// Priority determines the order of access to the resources managed by the
 	// ClusterQueue where the workload is queued.
 	// The priority value is populated from PriorityClassName.
 	// The higher the value, the higher the priority.
 	// If priorityClassName is specified, priority must not be null.
+	// The priority, priorityClassName and priorityClassSource can be changed
+	// at any time. A pending workload is requeued with the new priority, and
+	// an admitted workload keeps its admission and is preempted according to
+	// the new priority.
 	Priority *int32 `json:"priority,omitempty"`
+
+	// active determines whether the workload can be admitted. Setting it to
+	// false evicts the workload, if it's admitted, and removes it from the
+	// queues until it's set back to true. The job of the workload is
+	// suspended, but not deleted.
+	// Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Active *bool `json:"active,omitempty"`
+
+	// reservation, when set, makes the workload only reserve quota for its
+	// podSets. It's queued and admitted like any other workload, but no pods
+	// are created for it and the job that owns it, if any, is never started.
+	// The quota is held until the workload is deleted, deactivated or finished,
+	// or until the reservation expires.
+	// reservation cannot be changed.
+	// +optional
+	Reservation *WorkloadReservation `json:"reservation,omitempty"`
+
+	// admissionDeadline is the time by which the workload has to be admitted.
+	// ClusterQueues with the EarliestDeadlineFirst queueing strategy consider
+	// the workloads with the earliest deadlines first. A workload that is
+	// still pending when its deadline passes is finished with the reason
+	// AdmissionDeadlineExceeded, and it is not admitted anymore.
+	// +optional
+	AdmissionDeadline *metav1.Time `json:"admissionDeadline,omitempty"`
+
+	// maximumExecutionTimeSeconds is the maximum time, in seconds, that the
+	// workload can stay admitted, accumulated over all its admissions. Once
+	// exceeded, the workload is deactivated, which evicts it.
+	// If not set, the maximumExecutionTimeSeconds of the LocalQueue or, if not
+	// set either, of the ClusterQueue in which the workload is admitted
+	// applies.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaximumExecutionTimeSeconds *int32 `json:"maximumExecutionTimeSeconds,omitempty"`
+}
+
+type WorkloadReservation struct {
+	// ttlSecondsAfterAdmitted is the number of seconds after the admission of
+	// the workload when the reservation expires. An expired reservation is
+	// finished, which releases its quota.
+	// If not set, the reservation doesn't expire.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTLSecondsAfterAdmitted *int32 `json:"ttlSecondsAfterAdmitted,omitempty"`
+}
+
+type PodSetResize struct {
+	// name is the PodSet name.
+	Name string `json:"name"`
+
+	// count is the requested number of pods for the podSet.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
 }
 
 type Admission struct {
@@ -66,6 +148,15 @@ type Admission struct {
 	// +listType=map
 	// +listMapKey=name
 	PodSetFlavors []PodSetFlavors `json:"podSetFlavors"`
+
+	// admissionChecks are the names of the AdmissionChecks of the ClusterQueue
+	// at the time of admission. The workload reserves quota when the admission
+	// is set, but it's only admitted once all the checks are Ready in
+	// .status.admissionChecks.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=8
+	AdmissionChecks []string `json:"admissionChecks,omitempty"`
 }
 
 type PodSetFlavors struct {
@@ -75,6 +166,19 @@ type PodSetFlavors struct {
 
 	// Flavors are the flavors assigned to the workload for each resource.
 	Flavors map[corev1.ResourceName]string `json:"flavors,omitempty"`
+
+	// count is the number of pods of the podSet taken into account at
+	// admission time. It can be lower than .spec.podSets[*].count when the
+	// podSet sets a minCount and the workload was partially admitted.
+	// If not set, all the pods of the podSet were admitted.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// topologyDomain holds the node labels, and their values, that identify
+	// the topology domain where the pods of the podSet are placed, when the
+	// podSet requests a topology.
+	// +optional
+	TopologyDomain map[string]string `json:"topologyDomain,omitempty"`
 }
 
 type PodSet struct {
@@ -90,6 +194,60 @@ type PodSet struct {
 	// count is the number of pods for the spec.
 	// +kubebuilder:validation:Minimum=1
 	Count int32 `json:"count"`
+
+	// minCount is the minimum number of pods for the spec acceptable
+	// if the workload supports partial admission.
+	//
+	// If not provided, partial admission for the current PodSet is not
+	// enabled.
+	//
+	// Only one podSet within the workload can use this.
+	// +optional
+	MinCount *int32 `json:"minCount,omitempty"`
+
+	// topologyRequest requests the pods of the podSet to be placed in a
+	// single domain of the topology of the assigned flavor.
+	// +optional
+	TopologyRequest *PodSetTopologyRequest `json:"topologyRequest,omitempty"`
+
+	// flavorAffinity restricts or orders the ResourceFlavors that the
+	// resources of the podSet can be assigned.
+	// +optional
+	FlavorAffinity *PodSetFlavorAffinity `json:"flavorAffinity,omitempty"`
+}
+
+type PodSetFlavorAffinity struct {
+	// required are the only ResourceFlavors that the podSet can be assigned,
+	// for the resources whose flavors in the ClusterQueue include any of
+	// them. The other resources can be assigned any flavor.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=16
+	Required []string `json:"required,omitempty"`
+
+	// preferred are the ResourceFlavors that are tried first for the
+	// resources of the podSet, in the order of the ClusterQueue. The other
+	// flavors are tried next, if the podSet doesn't fit in the preferred
+	// ones.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=16
+	Preferred []string `json:"preferred,omitempty"`
+}
+
+type PodSetTopologyRequest struct {
+	// required is the node label of the topology level whose domain must
+	// hold all the pods of the podSet. The workload is not admitted with a
+	// flavor if no domain of the level fits the podSet.
+	// +optional
+	Required *string `json:"required,omitempty"`
+
+	// preferred is the node label of the topology level whose domain should
+	// hold all the pods of the podSet. If no domain of the level fits the
+	// podSet, the domains of the levels above are tried, and otherwise the
+	// podSet is admitted without a topology domain.
+	// +optional
+	Preferred *string `json:"preferred,omitempty"`
 }
 
 // WorkloadStatus defines the observed state of Workload
@@ -99,16 +257,290 @@ type WorkloadStatus struct {
 	//
 	// The type of the condition could be:
 	//
-	// - Admitted: the Workload was admitted through a ClusterQueue.
+	// - QuotaReserved: the Workload reserved quota in a ClusterQueue.
+	// - Admitted: the Workload was admitted through a ClusterQueue, after
+	//   reserving quota and passing the admission checks.
 	// - Finished: the associated workload finished running (failed or succeeded).
 	//
 	// +optional
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// requeueState holds the state of the requeuing backoff of a workload
+	// that couldn't be admitted. It's only set when the requeuing backoff is
+	// enabled in the Kueue configuration.
+	// +optional
+	RequeueState *RequeueState `json:"requeueState,omitempty"`
+
+	// reclaimablePods keeps track of the number of pods of each podSet that
+	// finished and won't be replaced, so that the quota that they used is
+	// released while the rest of the workload is running.
+	// The counts can only increase while the workload is admitted.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ReclaimablePods []ReclaimablePod `json:"reclaimablePods,omitempty"`
+
+	// admissionChecks hold the states of the admission checks listed in
+	// .spec.admission.admissionChecks.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	AdmissionChecks []AdmissionCheckState `json:"admissionChecks,omitempty"`
+
+	// inadmissibleReasons are the reasons why the last admission attempt
+	// couldn't assign flavors to the podSets of the workload, per resource and
+	// flavor. They are cleared once the workload reserves quota.
+	// +optional
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=32
+	InadmissibleReasons []InadmissibleReason `json:"inadmissibleReasons,omitempty"`
+
+	// excludedFlavors are the unreliable flavors that the workload can't be
+	// assigned, because their nodes were reclaimed while the workload was
+	// running on them. Each admission of the workload decrements the
+	// remaining retries of the flavors, which are removed once they reach 0.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	ExcludedFlavors []ExcludedFlavor `json:"excludedFlavors,omitempty"`
+
+	// accumulatedPastExecutionTimeSeconds is the time, in seconds, that the
+	// workload stayed admitted in its previous admissions. It's reset when
+	// the workload is deactivated for exceeding its maximum execution time.
+	// +optional
+	AccumulatedPastExecutionTimeSeconds *int32 `json:"accumulatedPastExecutionTimeSeconds,omitempty"`
+
+	// resourceUsage is the quota that the workload uses in the ClusterQueue
+	// of its admission, per podSet, resource and flavor. It doesn't include
+	// the reclaimable pods. It's cleared when the workload releases its quota,
+	// and kept once the workload finishes.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ResourceUsage []PodSetResourceUsage `json:"resourceUsage,omitempty"`
+}
+
+type PodSetResourceUsage struct {
+	// name is the name of the podSet.
+	Name string `json:"name"`
+
+	// count is the number of pods of the podSet that use quota.
+	Count int32 `json:"count"`
+
+	// resources are the quantities of the resources of the podSet, for all
+	// its pods, and the flavors that provide them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Resources []ResourceUsage `json:"resources,omitempty"`
+}
+
+type ResourceUsage struct {
+	// name is the name of the resource.
+	Name corev1.ResourceName `json:"name"`
+
+	// flavor is the name of the ResourceFlavor assigned to the resource.
+	Flavor string `json:"flavor"`
+
+	// total is the quantity of the resource used by all the pods of the
+	// podSet.
+	Total resource.Quantity `json:"total"`
+}
+
+type ExcludedFlavor struct {
+	// name is the name of the ResourceFlavor.
+	Name string `json:"name"`
+
+	// remainingRetries is the number of admissions of the workload, from
+	// now on, that exclude the flavor.
+	// +kubebuilder:validation:Minimum=1
+	RemainingRetries int32 `json:"remainingRetries"`
 }
 
+type InadmissibleReasonType string
+
 const (
+	// InadmissibleReasonResourceUnavailable means that the ClusterQueue
+	// doesn't have quota for the resource.
+	InadmissibleReasonResourceUnavailable InadmissibleReasonType = "ResourceUnavailable"
+
+	// InadmissibleReasonFlavorNotFound means that the ResourceFlavor doesn't
+	// exist.
+	InadmissibleReasonFlavorNotFound InadmissibleReasonType = "FlavorNotFound"
+
+	// InadmissibleReasonUntoleratedTaint means that the podSet doesn't
+	// tolerate a taint of the flavor.
+	InadmissibleReasonUntoleratedTaint InadmissibleReasonType = "UntoleratedTaint"
+
+	// InadmissibleReasonNodeAffinityMismatch means that the node selector or
+	// affinity of the podSet doesn't match the labels of the flavor.
+	InadmissibleReasonNodeAffinityMismatch InadmissibleReasonType = "NodeAffinityMismatch"
+
+	// InadmissibleReasonMissingNodeResources means that the nodes selected by
+	// the flavor don't expose some of the extended resources of the podSet.
+	InadmissibleReasonMissingNodeResources InadmissibleReasonType = "MissingNodeResources"
+
+	// InadmissibleReasonNamespaceLimitExceeded means that the podSet would
+	// exceed the maxPerNamespace quota of the flavor.
+	InadmissibleReasonNamespaceLimitExceeded InadmissibleReasonType = "NamespaceLimitExceeded"
+
+	// InadmissibleReasonBorrowingLimitExceeded means that the podSet would
+	// exceed the max quota or the borrowing limit of the flavor.
+	InadmissibleReasonBorrowingLimitExceeded InadmissibleReasonType = "BorrowingLimitExceeded"
+
+	// InadmissibleReasonCohortLimitExceeded means that the podSet would
+	// exceed the max quota of a cohort.
+	InadmissibleReasonCohortLimitExceeded InadmissibleReasonType = "CohortLimitExceeded"
+
+	// InadmissibleReasonInsufficientQuota means that the podSet requests more
+	// than the quota of the flavor, so it can't fit even if the ClusterQueue
+	// is empty.
+	InadmissibleReasonInsufficientQuota InadmissibleReasonType = "InsufficientQuota"
+
+	// InadmissibleReasonInsufficientUnusedQuota means that the podSet doesn't
+	// fit in the unused quota of the ClusterQueue or its cohort. It could fit
+	// once other workloads finish or are preempted.
+	InadmissibleReasonInsufficientUnusedQuota InadmissibleReasonType = "InsufficientUnusedQuota"
+
+	// InadmissibleReasonTopologyMismatch means that the podSet doesn't fit in
+	// the topology of the flavor.
+	InadmissibleReasonTopologyMismatch InadmissibleReasonType = "TopologyMismatch"
+
+	// InadmissibleReasonFlavorExcluded means that the flavor is excluded for
+	// the workload, because the nodes of the flavor were reclaimed while the
+	// workload was running on them.
+	InadmissibleReasonFlavorExcluded InadmissibleReasonType = "FlavorExcluded"
+
+	// InadmissibleReasonFlavorAffinityMismatch means that the flavor is not
+	// one of the flavors required by the podSet.
+	InadmissibleReasonFlavorAffinityMismatch InadmissibleReasonType = "FlavorAffinityMismatch"
+)
+
+// InadmissibleReason is a reason why a flavor couldn't be assigned to the
+// resources of a podSet.
+type InadmissibleReason struct {
+	// podSet is the name of the podSet.
+	PodSet string `json:"podSet"`
+
+	// type is the machine-readable reason.
+	Type InadmissibleReasonType `json:"type"`
+
+	// resource is the name of the resource, if the reason is specific to one.
+	// +optional
+	Resource corev1.ResourceName `json:"resource,omitempty"`
+
+	// flavor is the name of the ResourceFlavor, if the reason is specific to
+	// one.
+	// +optional
+	Flavor string `json:"flavor,omitempty"`
+
+	// missing is the quantity of the resource that doesn't fit in the
+	// unused quota.
+	// +optional
+	Missing *resource.Quantity `json:"missing,omitempty"`
+
+	// message is a human readable message explaining the reason.
+	// +kubebuilder:validation:MaxLength=1024
+	Message string `json:"message"`
+}
+
+type CheckState string
+
+const (
+	// CheckStateRetry means that the check failed, and the workload should
+	// release its quota and be admitted again later.
+	CheckStateRetry CheckState = "Retry"
+
+	// CheckStateRejected means that the check failed, and the workload can't
+	// be admitted.
+	CheckStateRejected CheckState = "Rejected"
+
+	// CheckStatePending means that the check is still being evaluated.
+	CheckStatePending CheckState = "Pending"
+
+	// CheckStateReady means that the check passed.
+	CheckStateReady CheckState = "Ready"
+)
+
+type AdmissionCheckState struct {
+	// name is the name of the AdmissionCheck.
+	Name string `json:"name"`
+
+	// state of the check.
+	// +kubebuilder:validation:Enum=Retry;Rejected;Pending;Ready
+	State CheckState `json:"state"`
+
+	// lastTransitionTime is the last time the state changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// message is a human readable message indicating details about the state.
+	// +optional
+	// +kubebuilder:validation:MaxLength=32768
+	Message string `json:"message,omitempty"`
+
+	// podSetUpdates are changes that the check requires in the pods of the
+	// podSets, which are applied when the workload starts.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	PodSetUpdates []PodSetUpdate `json:"podSetUpdates,omitempty"`
+}
+
+type PodSetUpdate struct {
+	// name is the PodSet name.
+	Name string `json:"name"`
+
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+type ReclaimablePod struct {
+	// name is the PodSet name.
+	Name string `json:"name"`
+
+	// count is the number of pods for which the requested resources are no
+	// longer needed.
+	// +kubebuilder:validation:Minimum=0
+	Count int32 `json:"count"`
+}
+
+type RequeueState struct {
+	// count records the number of consecutive times the workload couldn't
+	// be admitted. It's reset once the workload is admitted.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// requeueAt records the time when the workload will be considered again
+	// for admission. It's cleared once that time passes.
+	// +optional
+	RequeueAt *metav1.Time `json:"requeueAt,omitempty"`
+
+	// evictions records the number of times the workload was evicted and
+	// requeued because its pods didn't become ready in time or because an
+	// admission check requested a retry. Unlike count, it's not reset when
+	// the workload is admitted. Once it exceeds the backoffLimitCount of the
+	// requeuing configuration, the workload is deactivated. It's reset when
+	// the workload is activated again.
+	// +optional
+	Evictions *int32 `json:"evictions,omitempty"`
+}
+
+const (
+	// WorkloadQuotaReserved means that the Workload reserved quota in a
+	// ClusterQueue.
+	WorkloadQuotaReserved = "QuotaReserved"
+
 	// WorkloadAdmitted means that the Workload was admitted by a ClusterQueue.
 	WorkloadAdmitted = "Admitted"
 
@@ -119,8 +551,76 @@ const (
 	// WorkloadPodsReady means that at least `.spec.podSets[*].count` Pods are
 	// ready or have succeeded.
 	WorkloadPodsReady = "PodsReady"
+
+	// WorkloadEvicted means that the Workload was evicted by Kueue. While the
+	// condition is True, the job of the Workload is suspended and, once it
+	// is, the admission of the Workload is cleared and the Workload is
+	// requeued. The reason of the condition is one of the WorkloadEvictedBy
+	// reasons.
+	WorkloadEvicted = "Evicted"
+)
+
+const (
+	// WorkloadEvictedByPreemption is the reason of the Evicted condition of a
+	// Workload preempted to admit another Workload.
+	WorkloadEvictedByPreemption = "Preemption"
+
+	// WorkloadEvictedByPodsReadyTimeout is the reason of the Evicted condition
+	// of a Workload whose pods didn't become ready within the timeout of
+	// waitForPodsReady.
+	WorkloadEvictedByPodsReadyTimeout = "PodsReadyTimeout"
+
+	// WorkloadEvictedByAdmissionCheck is the reason of the Evicted condition
+	// of a Workload with an admission check in the Retry state.
+	WorkloadEvictedByAdmissionCheck = "AdmissionCheck"
+
+	// WorkloadEvictedByClusterQueueStopped is the reason of the Evicted
+	// condition of a Workload whose ClusterQueue was stopped.
+	WorkloadEvictedByClusterQueueStopped = "ClusterQueueStopped"
+
+	// WorkloadEvictedByDeactivation is the reason of the Evicted condition of
+	// a Workload that was deactivated.
+	WorkloadEvictedByDeactivation = "Deactivated"
+
+	// WorkloadEvictedByClusterQueueOverQuota is the reason of the Evicted
+	// condition of a Workload evicted to bring the usage of its ClusterQueue
+	// back under its quota.
+	WorkloadEvictedByClusterQueueOverQuota = "ClusterQueueOverQuota"
+
+	// WorkloadEvictedByResourceFlavorDeleted is the reason of the Evicted
+	// condition of a Workload that was assigned a deleted ResourceFlavor.
+	WorkloadEvictedByResourceFlavorDeleted = "ResourceFlavorDeleted"
+
+	// WorkloadEvictedByWorkloadGroup is the reason of the Evicted condition of
+	// a Workload whose admission was cancelled because another Workload of its
+	// group was evicted.
+	WorkloadEvictedByWorkloadGroup = "WorkloadGroup"
+
+	// WorkloadEvictedByNodeReclaimed is the reason of the Evicted condition of
+	// a Workload whose pods were disrupted because the nodes of an unreliable
+	// flavor were reclaimed.
+	WorkloadEvictedByNodeReclaimed = "NodeReclaimed"
+
+	// WorkloadEvictedByUnschedulablePods is the reason of the Evicted
+	// condition of a Workload whose pods were unschedulable for too long,
+	// because no node matches a flavor assigned to the Workload anymore.
+	WorkloadEvictedByUnschedulablePods = "UnschedulablePods"
+
+	// WorkloadEvictedByMaximumExecutionTimeExceeded is the reason of the
+	// Evicted condition of a Workload that was deactivated because it stayed
+	// admitted for longer than its maximum execution time.
+	WorkloadEvictedByMaximumExecutionTimeExceeded = "MaximumExecutionTimeExceeded"
 )
 
+// WorkloadRequeuingLimitExceeded is the reason of the Admitted condition of a
+// Workload that was deactivated because it was evicted more times than the
+// backoffLimitCount of the requeuing backoff.
+const WorkloadRequeuingLimitExceeded = "RequeuingLimitExceeded"
+
+// WorkloadAdmissionDeadlineExceeded is the reason of the Finished condition
+// of a Workload that wasn't admitted before its admission deadline.
+const WorkloadAdmissionDeadlineExceeded = "AdmissionDeadlineExceeded"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Queue",JSONPath=".spec.queueName",type=string,description="Name of the queue this workload was submitted to"
@@ -37,6 +37,11 @@ func (in *Admission) DeepCopyInto(out *Admission) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdmissionChecks != nil {
+		in, out := &in.AdmissionChecks, &out.AdmissionChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Admission.
@@ -49,6 +54,130 @@ func (in *Admission) DeepCopy() *Admission {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheck) DeepCopyInto(out *AdmissionCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheck.
+func (in *AdmissionCheck) DeepCopy() *AdmissionCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AdmissionCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheckList) DeepCopyInto(out *AdmissionCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AdmissionCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheckList.
+func (in *AdmissionCheckList) DeepCopy() *AdmissionCheckList {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AdmissionCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheckSpec) DeepCopyInto(out *AdmissionCheckSpec) {
+	*out = *in
+	if in.ProvisioningRequest != nil {
+		in, out := &in.ProvisioningRequest, &out.ProvisioningRequest
+		*out = new(ProvisioningRequestParameters)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheckSpec.
+func (in *AdmissionCheckSpec) DeepCopy() *AdmissionCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheckState) DeepCopyInto(out *AdmissionCheckState) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.PodSetUpdates != nil {
+		in, out := &in.PodSetUpdates, &out.PodSetUpdates
+		*out = make([]PodSetUpdate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheckState.
+func (in *AdmissionCheckState) DeepCopy() *AdmissionCheckState {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheckState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheckStatus) DeepCopyInto(out *AdmissionCheckStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheckStatus.
+func (in *AdmissionCheckStatus) DeepCopy() *AdmissionCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterQueue) DeepCopyInto(out *ClusterQueue) {
 	*out = *in
@@ -108,6 +237,42 @@ func (in *ClusterQueueList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQueuePendingWorkload) DeepCopyInto(out *ClusterQueuePendingWorkload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQueuePendingWorkload.
+func (in *ClusterQueuePendingWorkload) DeepCopy() *ClusterQueuePendingWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQueuePendingWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQueuePendingWorkloadsStatus) DeepCopyInto(out *ClusterQueuePendingWorkloadsStatus) {
+	*out = *in
+	if in.Head != nil {
+		in, out := &in.Head, &out.Head
+		*out = make([]ClusterQueuePendingWorkload, len(*in))
+		copy(*out, *in)
+	}
+	in.LastChangeTime.DeepCopyInto(&out.LastChangeTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQueuePendingWorkloadsStatus.
+func (in *ClusterQueuePendingWorkloadsStatus) DeepCopy() *ClusterQueuePendingWorkloadsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQueuePendingWorkloadsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterQueuePreemption) DeepCopyInto(out *ClusterQueuePreemption) {
 	*out = *in
@@ -126,6 +291,13 @@ func (in *ClusterQueuePreemption) DeepCopy() *ClusterQueuePreemption {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterQueueSpec) DeepCopyInto(out *ClusterQueueSpec) {
 	*out = *in
+	if in.ResourceGroups != nil {
+		in, out := &in.ResourceGroups, &out.ResourceGroups
+		*out = make([]ResourceGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = make([]Resource, len(*in))
@@ -133,6 +305,11 @@ func (in *ClusterQueueSpec) DeepCopyInto(out *ClusterQueueSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LocalQueueSharing != nil {
+		in, out := &in.LocalQueueSharing, &out.LocalQueueSharing
+		*out = new(LocalQueueSharing)
+		**out = **in
+	}
 	if in.NamespaceSelector != nil {
 		in, out := &in.NamespaceSelector, &out.NamespaceSelector
 		*out = new(v1.LabelSelector)
@@ -143,6 +320,48 @@ func (in *ClusterQueueSpec) DeepCopyInto(out *ClusterQueueSpec) {
 		*out = new(ClusterQueuePreemption)
 		**out = **in
 	}
+	if in.FlavorFungibility != nil {
+		in, out := &in.FlavorFungibility, &out.FlavorFungibility
+		*out = new(FlavorFungibility)
+		**out = **in
+	}
+	if in.AdmissionChecks != nil {
+		in, out := &in.AdmissionChecks, &out.AdmissionChecks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StopPolicy != nil {
+		in, out := &in.StopPolicy, &out.StopPolicy
+		*out = new(StopPolicy)
+		**out = **in
+	}
+	if in.OverQuotaPolicy != nil {
+		in, out := &in.OverQuotaPolicy, &out.OverQuotaPolicy
+		*out = new(OverQuotaPolicy)
+		**out = **in
+	}
+	if in.SchedulingProfile != nil {
+		in, out := &in.SchedulingProfile, &out.SchedulingProfile
+		*out = new(SchedulingProfile)
+		**out = **in
+	}
+	if in.PriorityAging != nil {
+		in, out := &in.PriorityAging, &out.PriorityAging
+		*out = new(PriorityAging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QuotaSchedules != nil {
+		in, out := &in.QuotaSchedules, &out.QuotaSchedules
+		*out = make([]QuotaSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaximumExecutionTimeSeconds != nil {
+		in, out := &in.MaximumExecutionTimeSeconds, &out.MaximumExecutionTimeSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQueueSpec.
@@ -175,6 +394,11 @@ func (in *ClusterQueueStatus) DeepCopyInto(out *ClusterQueueStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.PendingWorkloadsStatus != nil {
+		in, out := &in.PendingWorkloadsStatus, &out.PendingWorkloadsStatus
+		*out = new(ClusterQueuePendingWorkloadsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -194,6 +418,108 @@ func (in *ClusterQueueStatus) DeepCopy() *ClusterQueueStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cohort) DeepCopyInto(out *Cohort) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cohort.
+func (in *Cohort) DeepCopy() *Cohort {
+	if in == nil {
+		return nil
+	}
+	out := new(Cohort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Cohort) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CohortList) DeepCopyInto(out *CohortList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Cohort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CohortList.
+func (in *CohortList) DeepCopy() *CohortList {
+	if in == nil {
+		return nil
+	}
+	out := new(CohortList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CohortList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CohortSpec) DeepCopyInto(out *CohortSpec) {
+	*out = *in
+	if in.ResourceGroups != nil {
+		in, out := &in.ResourceGroups, &out.ResourceGroups
+		*out = make([]ResourceGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]Resource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CohortSpec.
+func (in *CohortSpec) DeepCopy() *CohortSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CohortSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExcludedFlavor) DeepCopyInto(out *ExcludedFlavor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExcludedFlavor.
+func (in *ExcludedFlavor) DeepCopy() *ExcludedFlavor {
+	if in == nil {
+		return nil
+	}
+	out := new(ExcludedFlavor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Flavor) DeepCopyInto(out *Flavor) {
 	*out = *in
@@ -205,32 +531,582 @@ func (in *Flavor) DeepCopy() *Flavor {
 	if in == nil {
 		return nil
 	}
-	out := new(Flavor)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalQueue) DeepCopyInto(out *LocalQueue) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	out := new(Flavor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlavorFungibility) DeepCopyInto(out *FlavorFungibility) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlavorFungibility.
+func (in *FlavorFungibility) DeepCopy() *FlavorFungibility {
+	if in == nil {
+		return nil
+	}
+	out := new(FlavorFungibility)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlavorQuotas) DeepCopyInto(out *FlavorQuotas) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlavorQuotas.
+func (in *FlavorQuotas) DeepCopy() *FlavorQuotas {
+	if in == nil {
+		return nil
+	}
+	out := new(FlavorQuotas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InadmissibleReason) DeepCopyInto(out *InadmissibleReason) {
+	*out = *in
+	if in.Missing != nil {
+		in, out := &in.Missing, &out.Missing
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InadmissibleReason.
+func (in *InadmissibleReason) DeepCopy() *InadmissibleReason {
+	if in == nil {
+		return nil
+	}
+	out := new(InadmissibleReason)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalQueue) DeepCopyInto(out *LocalQueue) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueue.
+func (in *LocalQueue) DeepCopy() *LocalQueue {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalQueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LocalQueue) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalQueueList) DeepCopyInto(out *LocalQueueList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LocalQueue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueList.
+func (in *LocalQueueList) DeepCopy() *LocalQueueList {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalQueueList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LocalQueueList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalQueueSpec) DeepCopyInto(out *LocalQueueSpec) {
+	*out = *in
+	if in.MaximumExecutionTimeSeconds != nil {
+		in, out := &in.MaximumExecutionTimeSeconds, &out.MaximumExecutionTimeSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueSpec.
+func (in *LocalQueueSpec) DeepCopy() *LocalQueueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalQueueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalQueueStatus) DeepCopyInto(out *LocalQueueStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueStatus.
+func (in *LocalQueueStatus) DeepCopy() *LocalQueueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalQueueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSet) DeepCopyInto(out *PodSet) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.MinCount != nil {
+		in, out := &in.MinCount, &out.MinCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TopologyRequest != nil {
+		in, out := &in.TopologyRequest, &out.TopologyRequest
+		*out = new(PodSetTopologyRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FlavorAffinity != nil {
+		in, out := &in.FlavorAffinity, &out.FlavorAffinity
+		*out = new(PodSetFlavorAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSet.
+func (in *PodSet) DeepCopy() *PodSet {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetFlavorAffinity) DeepCopyInto(out *PodSetFlavorAffinity) {
+	*out = *in
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Preferred != nil {
+		in, out := &in.Preferred, &out.Preferred
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetFlavorAffinity.
+func (in *PodSetFlavorAffinity) DeepCopy() *PodSetFlavorAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetFlavorAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetFlavors) DeepCopyInto(out *PodSetFlavors) {
+	*out = *in
+	if in.Flavors != nil {
+		in, out := &in.Flavors, &out.Flavors
+		*out = make(map[corev1.ResourceName]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TopologyDomain != nil {
+		in, out := &in.TopologyDomain, &out.TopologyDomain
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetFlavors.
+func (in *PodSetFlavors) DeepCopy() *PodSetFlavors {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetFlavors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetResize) DeepCopyInto(out *PodSetResize) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetResize.
+func (in *PodSetResize) DeepCopy() *PodSetResize {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetResize)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetResourceUsage) DeepCopyInto(out *PodSetResourceUsage) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetResourceUsage.
+func (in *PodSetResourceUsage) DeepCopy() *PodSetResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetTopologyRequest) DeepCopyInto(out *PodSetTopologyRequest) {
+	*out = *in
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(string)
+		**out = **in
+	}
+	if in.Preferred != nil {
+		in, out := &in.Preferred, &out.Preferred
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetTopologyRequest.
+func (in *PodSetTopologyRequest) DeepCopy() *PodSetTopologyRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetTopologyRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSetUpdate) DeepCopyInto(out *PodSetUpdate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetUpdate.
+func (in *PodSetUpdate) DeepCopy() *PodSetUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSetUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityAging) DeepCopyInto(out *PriorityAging) {
+	*out = *in
+	if in.MaxBoost != nil {
+		in, out := &in.MaxBoost, &out.MaxBoost
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityAging.
+func (in *PriorityAging) DeepCopy() *PriorityAging {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityAging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningRequestParameters) DeepCopyInto(out *ProvisioningRequestParameters) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningRequestParameters.
+func (in *ProvisioningRequestParameters) DeepCopy() *ProvisioningRequestParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningRequestParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Quota) DeepCopyInto(out *Quota) {
+	*out = *in
+	out.Min = in.Min.DeepCopy()
+	if in.MinPercentage != nil {
+		in, out := &in.MinPercentage, &out.MinPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.BorrowingLimit != nil {
+		in, out := &in.BorrowingLimit, &out.BorrowingLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LendingLimit != nil {
+		in, out := &in.LendingLimit, &out.LendingLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxPerNamespace != nil {
+		in, out := &in.MaxPerNamespace, &out.MaxPerNamespace
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quota.
+func (in *Quota) DeepCopy() *Quota {
+	if in == nil {
+		return nil
+	}
+	out := new(Quota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaSchedule) DeepCopyInto(out *QuotaSchedule) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]ScheduleDay, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]ScheduledQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaSchedule.
+func (in *QuotaSchedule) DeepCopy() *QuotaSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReclaimablePod) DeepCopyInto(out *ReclaimablePod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReclaimablePod.
+func (in *ReclaimablePod) DeepCopy() *ReclaimablePod {
+	if in == nil {
+		return nil
+	}
+	out := new(ReclaimablePod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeueState) DeepCopyInto(out *RequeueState) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequeueAt != nil {
+		in, out := &in.RequeueAt, &out.RequeueAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Evictions != nil {
+		in, out := &in.Evictions, &out.Evictions
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeueState.
+func (in *RequeueState) DeepCopy() *RequeueState {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeueState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Resource) DeepCopyInto(out *Resource) {
+	*out = *in
+	if in.Flavors != nil {
+		in, out := &in.Flavors, &out.Flavors
+		*out = make([]Flavor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resource.
+func (in *Resource) DeepCopy() *Resource {
+	if in == nil {
+		return nil
+	}
+	out := new(Resource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFlavor) DeepCopyInto(out *ResourceFlavor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologyName != nil {
+		in, out := &in.TopologyName, &out.TopologyName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Unreliable != nil {
+		in, out := &in.Unreliable, &out.Unreliable
+		*out = new(UnreliableNodes)
+		**out = **in
+	}
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueue.
-func (in *LocalQueue) DeepCopy() *LocalQueue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFlavor.
+func (in *ResourceFlavor) DeepCopy() *ResourceFlavor {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalQueue)
+	out := new(ResourceFlavor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LocalQueue) DeepCopyObject() runtime.Object {
+func (in *ResourceFlavor) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -238,31 +1114,31 @@ func (in *LocalQueue) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalQueueList) DeepCopyInto(out *LocalQueueList) {
+func (in *ResourceFlavorList) DeepCopyInto(out *ResourceFlavorList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]LocalQueue, len(*in))
+		*out = make([]ResourceFlavor, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueList.
-func (in *LocalQueueList) DeepCopy() *LocalQueueList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFlavorList.
+func (in *ResourceFlavorList) DeepCopy() *ResourceFlavorList {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalQueueList)
+	out := new(ResourceFlavorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LocalQueueList) DeepCopyObject() runtime.Object {
+func (in *ResourceFlavorList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -270,149 +1146,176 @@ func (in *LocalQueueList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalQueueSpec) DeepCopyInto(out *LocalQueueSpec) {
+func (in *ResourceFlavorStatus) DeepCopyInto(out *ResourceFlavorStatus) {
 	*out = *in
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueSpec.
-func (in *LocalQueueSpec) DeepCopy() *LocalQueueSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFlavorStatus.
+func (in *ResourceFlavorStatus) DeepCopy() *ResourceFlavorStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalQueueSpec)
+	out := new(ResourceFlavorStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalQueueStatus) DeepCopyInto(out *LocalQueueStatus) {
+func (in *ResourceGroup) DeepCopyInto(out *ResourceGroup) {
 	*out = *in
+	if in.CoveredResources != nil {
+		in, out := &in.CoveredResources, &out.CoveredResources
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	if in.Flavors != nil {
+		in, out := &in.Flavors, &out.Flavors
+		*out = make([]FlavorQuotas, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueStatus.
-func (in *LocalQueueStatus) DeepCopy() *LocalQueueStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGroup.
+func (in *ResourceGroup) DeepCopy() *ResourceGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalQueueStatus)
+	out := new(ResourceGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodSet) DeepCopyInto(out *PodSet) {
+func (in *ResourceQuota) DeepCopyInto(out *ResourceQuota) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.Quota.DeepCopyInto(&out.Quota)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSet.
-func (in *PodSet) DeepCopy() *PodSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuota.
+func (in *ResourceQuota) DeepCopy() *ResourceQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(PodSet)
+	out := new(ResourceQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodSetFlavors) DeepCopyInto(out *PodSetFlavors) {
+func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
 	*out = *in
-	if in.Flavors != nil {
-		in, out := &in.Flavors, &out.Flavors
-		*out = make(map[corev1.ResourceName]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.Total = in.Total.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSetFlavors.
-func (in *PodSetFlavors) DeepCopy() *PodSetFlavors {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
+func (in *ResourceUsage) DeepCopy() *ResourceUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(PodSetFlavors)
+	out := new(ResourceUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Quota) DeepCopyInto(out *Quota) {
+func (in *ScheduledQuota) DeepCopyInto(out *ScheduledQuota) {
 	*out = *in
-	out.Min = in.Min.DeepCopy()
-	if in.Max != nil {
-		in, out := &in.Max, &out.Max
-		x := (*in).DeepCopy()
-		*out = &x
-	}
+	in.Quota.DeepCopyInto(&out.Quota)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quota.
-func (in *Quota) DeepCopy() *Quota {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledQuota.
+func (in *ScheduledQuota) DeepCopy() *ScheduledQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(Quota)
+	out := new(ScheduledQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Resource) DeepCopyInto(out *Resource) {
+func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
-	if in.Flavors != nil {
-		in, out := &in.Flavors, &out.Flavors
-		*out = make([]Flavor, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Topology.
+func (in *Topology) DeepCopy() *Topology {
+	if in == nil {
+		return nil
 	}
+	out := new(Topology)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resource.
-func (in *Resource) DeepCopy() *Resource {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Topology) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyLevel) DeepCopyInto(out *TopologyLevel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyLevel.
+func (in *TopologyLevel) DeepCopy() *TopologyLevel {
 	if in == nil {
 		return nil
 	}
-	out := new(Resource)
+	out := new(TopologyLevel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceFlavor) DeepCopyInto(out *ResourceFlavor) {
+func (in *TopologyList) DeepCopyInto(out *TopologyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Taints != nil {
-		in, out := &in.Taints, &out.Taints
-		*out = make([]corev1.Taint, len(*in))
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Topology, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFlavor.
-func (in *ResourceFlavor) DeepCopy() *ResourceFlavor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyList.
+func (in *TopologyList) DeepCopy() *TopologyList {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceFlavor)
+	out := new(TopologyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ResourceFlavor) DeepCopyObject() runtime.Object {
+func (in *TopologyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -420,35 +1323,38 @@ func (in *ResourceFlavor) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceFlavorList) DeepCopyInto(out *ResourceFlavorList) {
+func (in *TopologySpec) DeepCopyInto(out *TopologySpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ResourceFlavor, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Levels != nil {
+		in, out := &in.Levels, &out.Levels
+		*out = make([]TopologyLevel, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFlavorList.
-func (in *ResourceFlavorList) DeepCopy() *ResourceFlavorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpec.
+func (in *TopologySpec) DeepCopy() *TopologySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceFlavorList)
+	out := new(TopologySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ResourceFlavorList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnreliableNodes) DeepCopyInto(out *UnreliableNodes) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnreliableNodes.
+func (in *UnreliableNodes) DeepCopy() *UnreliableNodes {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(UnreliableNodes)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -566,6 +1472,83 @@ func (in *WorkloadList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPriorityClass) DeepCopyInto(out *WorkloadPriorityClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPriorityClass.
+func (in *WorkloadPriorityClass) DeepCopy() *WorkloadPriorityClass {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPriorityClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadPriorityClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPriorityClassList) DeepCopyInto(out *WorkloadPriorityClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkloadPriorityClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPriorityClassList.
+func (in *WorkloadPriorityClassList) DeepCopy() *WorkloadPriorityClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPriorityClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadPriorityClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReservation) DeepCopyInto(out *WorkloadReservation) {
+	*out = *in
+	if in.TTLSecondsAfterAdmitted != nil {
+		in, out := &in.TTLSecondsAfterAdmitted, &out.TTLSecondsAfterAdmitted
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReservation.
+func (in *WorkloadReservation) DeepCopy() *WorkloadReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
 	*out = *in
@@ -581,11 +1564,35 @@ func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
 		*out = new(Admission)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodSetResizes != nil {
+		in, out := &in.PodSetResizes, &out.PodSetResizes
+		*out = make([]PodSetResize, len(*in))
+		copy(*out, *in)
+	}
 	if in.Priority != nil {
 		in, out := &in.Priority, &out.Priority
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Reservation != nil {
+		in, out := &in.Reservation, &out.Reservation
+		*out = new(WorkloadReservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdmissionDeadline != nil {
+		in, out := &in.AdmissionDeadline, &out.AdmissionDeadline
+		*out = (*in).DeepCopy()
+	}
+	if in.MaximumExecutionTimeSeconds != nil {
+		in, out := &in.MaximumExecutionTimeSeconds, &out.MaximumExecutionTimeSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpec.
@@ -608,6 +1615,47 @@ func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RequeueState != nil {
+		in, out := &in.RequeueState, &out.RequeueState
+		*out = new(RequeueState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReclaimablePods != nil {
+		in, out := &in.ReclaimablePods, &out.ReclaimablePods
+		*out = make([]ReclaimablePod, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdmissionChecks != nil {
+		in, out := &in.AdmissionChecks, &out.AdmissionChecks
+		*out = make([]AdmissionCheckState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InadmissibleReasons != nil {
+		in, out := &in.InadmissibleReasons, &out.InadmissibleReasons
+		*out = make([]InadmissibleReason, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludedFlavors != nil {
+		in, out := &in.ExcludedFlavors, &out.ExcludedFlavors
+		*out = make([]ExcludedFlavor, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccumulatedPastExecutionTimeSeconds != nil {
+		in, out := &in.AccumulatedPastExecutionTimeSeconds, &out.AccumulatedPastExecutionTimeSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = make([]PodSetResourceUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestValidateAdmissionCheck(t *testing.T) {
+	controllerNamePath := field.NewPath("spec", "controllerName")
+
+	testcases := map[string]struct {
+		admissionCheck *kueue.AdmissionCheck
+		wantErr        field.ErrorList
+	}{
+		"valid controller name": {
+			admissionCheck: testingutil.MakeAdmissionCheck("check", "kueue.x-k8s.io/provisioning-request").Obj(),
+		},
+		"empty controller name": {
+			admissionCheck: testingutil.MakeAdmissionCheck("check", "").Obj(),
+			wantErr: field.ErrorList{
+				field.Required(controllerNamePath, ""),
+			},
+		},
+		"invalid controller name": {
+			admissionCheck: testingutil.MakeAdmissionCheck("check", "@controller").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(controllerNamePath, "@controller", ""),
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			gotErr := ValidateAdmissionCheck(tc.admissionCheck)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("ValidateAdmissionCheck() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateAdmissionCheckUpdate(t *testing.T) {
+	testcases := map[string]struct {
+		newObj  *kueue.AdmissionCheck
+		oldObj  *kueue.AdmissionCheck
+		wantErr field.ErrorList
+	}{
+		"parameters can change": {
+			newObj: testingutil.MakeAdmissionCheck("check", "kueue.x-k8s.io/provisioning-request").
+				ProvisioningRequest("queued-provisioning", nil).Obj(),
+			oldObj: testingutil.MakeAdmissionCheck("check", "kueue.x-k8s.io/provisioning-request").Obj(),
+		},
+		"controller name is immutable": {
+			newObj: testingutil.MakeAdmissionCheck("check", "example.com/other").Obj(),
+			oldObj: testingutil.MakeAdmissionCheck("check", "kueue.x-k8s.io/provisioning-request").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "controllerName"), nil, ""),
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			gotErr := ValidateAdmissionCheckUpdate(tc.newObj, tc.oldObj)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("ValidateAdmissionCheckUpdate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,8 +18,14 @@ package webhooks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
@@ -29,26 +35,54 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 )
 
 const (
 	isNegativeErrorMsg string = `must be greater than or equal to 0`
+
+	// maxWarnedWorkloads is the maximum number of workloads listed in the
+	// warning of an update of the quotas of a ClusterQueue.
+	maxWarnedWorkloads = 10
+
+	notLeaderWarning = "The admitted workloads that wouldn't fit in the new quotas of the ClusterQueue couldn't be checked, because the update was served by a replica of Kueue that isn't the leader"
 )
 
+// QuotaSimulator simulates the updates of the quotas of the ClusterQueues.
+type QuotaSimulator interface {
+	// WorkloadsOverQuotaAfterUpdate returns the keys of the workloads
+	// admitted by the ClusterQueue that wouldn't fit in the quotas of the
+	// given ClusterQueue.
+	WorkloadsOverQuotaAfterUpdate(cq *kueue.ClusterQueue) ([]string, error)
+}
+
 type ClusterQueueWebhook struct{}
 
-func setupWebhookForClusterQueue(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
+func setupWebhookForClusterQueue(mgr ctrl.Manager, simulator QuotaSimulator) error {
+	blder := ctrl.NewWebhookManagedBy(mgr).
 		For(&kueue.ClusterQueue{}).
-		WithDefaulter(&ClusterQueueWebhook{}).
-		WithValidator(&ClusterQueueWebhook{}).
-		Complete()
+		WithDefaulter(&ClusterQueueWebhook{})
+	if simulator == nil {
+		return blder.WithValidator(&ClusterQueueWebhook{}).Complete()
+	}
+	if err := blder.Complete(); err != nil {
+		return err
+	}
+	mgr.GetWebhookServer().Register("/validate-kueue-x-k8s-io-v1alpha2-clusterqueue", &webhook.Admission{
+		Handler: &clusterQueueValidator{
+			validator: admission.WithCustomValidator(&kueue.ClusterQueue{}, &ClusterQueueWebhook{}).Handler,
+			simulator: simulator,
+			elected:   mgr.Elected(),
+		},
+	})
+	return nil
 }
 
-// +kubebuilder:webhook:path=/mutate-kueue-x-k8s-io-v1alpha2-clusterqueue,mutating=true,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=clusterqueues,verbs=create,versions=v1alpha2,name=mclusterqueue.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-kueue-x-k8s-io-v1alpha2-clusterqueue,mutating=true,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=clusterqueues,verbs=create;update,versions=v1alpha2,name=mclusterqueue.kb.io,admissionReviewVersions=v1
 
 var _ webhook.CustomDefaulter = &ClusterQueueWebhook{}
 
@@ -57,13 +91,25 @@ func (w *ClusterQueueWebhook) Default(ctx context.Context, obj runtime.Object) e
 	cq := obj.(*kueue.ClusterQueue)
 	log := ctrl.LoggerFrom(ctx).WithName("clusterqueue-webhook")
 	log.V(5).Info("Applying defaults", "clusterQueue", klog.KObj(cq))
-	if !controllerutil.ContainsFinalizer(cq, kueue.ResourceInUseFinalizerName) {
+	if cq.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(cq, kueue.ResourceInUseFinalizerName) {
 		controllerutil.AddFinalizer(cq, kueue.ResourceInUseFinalizerName)
 	}
+	if len(cq.Spec.Resources) > 0 {
+		cq.Spec.ResourceGroups = kueue.ResourceGroupsFromResources(cq.Spec.Resources)
+		cq.Spec.Resources = nil
+	}
 	if cq.Spec.Preemption == nil {
 		cq.Spec.Preemption = &kueue.ClusterQueuePreemption{
 			WithinClusterQueue:  kueue.PreemptionPolicyNever,
 			ReclaimWithinCohort: kueue.PreemptionPolicyNever,
+			VictimOrder:         kueue.PreemptionVictimOrderPriority,
+		}
+	}
+	if cq.Spec.FlavorFungibility == nil {
+		cq.Spec.FlavorFungibility = &kueue.FlavorFungibility{
+			WhenCanBorrow:   kueue.FlavorFungibilityPolicyBorrow,
+			WhenCanPreempt:  kueue.FlavorFungibilityPolicyTryNextFlavor,
+			PodSetPlacement: kueue.PodSetPlacementPolicyMixed,
 		}
 	}
 	return nil
@@ -98,6 +144,71 @@ func (w *ClusterQueueWebhook) ValidateDelete(ctx context.Context, obj runtime.Ob
 	return nil
 }
 
+// clusterQueueValidator validates the ClusterQueues with the
+// ClusterQueueWebhook and, when the quotas of a ClusterQueue are updated,
+// warns about the admitted workloads that wouldn't fit in the new quotas.
+// Custom validators can't return warnings in this version of
+// controller-runtime, so the validator is wrapped.
+// The cache that simulates the updates is only filled in the leader, so the
+// other replicas warn that the workloads couldn't be checked instead.
+type clusterQueueValidator struct {
+	validator admission.Handler
+	simulator QuotaSimulator
+	// elected is closed once the replica is elected as the leader.
+	elected <-chan struct{}
+}
+
+var _ admission.Handler = &clusterQueueValidator{}
+var _ inject.Injector = &clusterQueueValidator{}
+
+// InjectFunc implements inject.Injector, so that the decoder is injected into
+// the wrapped validator.
+func (v *clusterQueueValidator) InjectFunc(f inject.Func) error {
+	return f(v.validator)
+}
+
+func (v *clusterQueueValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := v.validator.Handle(ctx, req)
+	if !resp.Allowed || req.Operation != admissionv1.Update {
+		return resp
+	}
+	// The validator already decoded the objects successfully.
+	var newCQ, oldCQ kueue.ClusterQueue
+	if err := json.Unmarshal(req.Object.Raw, &newCQ); err != nil {
+		return resp
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, &oldCQ); err != nil {
+		return resp
+	}
+	if equality.Semantic.DeepEqual(newCQ.Spec.EffectiveResourceGroups(), oldCQ.Spec.EffectiveResourceGroups()) &&
+		equality.Semantic.DeepEqual(newCQ.Spec.QuotaSchedules, oldCQ.Spec.QuotaSchedules) {
+		return resp
+	}
+	select {
+	case <-v.elected:
+	default:
+		return resp.WithWarnings(notLeaderWarning)
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("clusterqueue-webhook")
+	workloads, err := v.simulator.WorkloadsOverQuotaAfterUpdate(&newCQ)
+	if err != nil {
+		log.Error(err, "Simulating the update of the quotas", "clusterQueue", klog.KObj(&newCQ))
+		return resp
+	}
+	if len(workloads) == 0 {
+		return resp
+	}
+	listed := workloads
+	if len(listed) > maxWarnedWorkloads {
+		listed = listed[:maxWarnedWorkloads]
+	}
+	warning := fmt.Sprintf("%d admitted workloads wouldn't fit in the new quotas of the ClusterQueue: %s", len(workloads), strings.Join(listed, ", "))
+	if len(workloads) > len(listed) {
+		warning += fmt.Sprintf(" and %d more", len(workloads)-len(listed))
+	}
+	return resp.WithWarnings(warning)
+}
+
 func ValidateClusterQueue(cq *kueue.ClusterQueue) field.ErrorList {
 	path := field.NewPath("spec")
 
@@ -105,9 +216,13 @@ func ValidateClusterQueue(cq *kueue.ClusterQueue) field.ErrorList {
 	if len(cq.Spec.Cohort) != 0 {
 		allErrs = append(allErrs, validateNameReference(cq.Spec.Cohort, path.Child("cohort"))...)
 	}
-	allErrs = append(allErrs, validateResources(cq.Spec.Resources, path.Child("resources"))...)
+	allErrs = append(allErrs, validateResourceGroups(cq.Spec.ResourceGroups, path.Child("resourceGroups"))...)
+	allErrs = append(allErrs, validateQuotaSchedules(cq.Spec.QuotaSchedules, cq.Spec.ResourceGroups, path.Child("quotaSchedules"))...)
 	allErrs = append(allErrs,
 		validation.ValidateLabelSelector(cq.Spec.NamespaceSelector, validation.LabelSelectorValidationOptions{}, path.Child("namespaceSelector"))...)
+	for i, name := range cq.Spec.AdmissionChecks {
+		allErrs = append(allErrs, validateNameReference(name, path.Child("admissionChecks").Index(i))...)
+	}
 
 	return allErrs
 }
@@ -123,55 +238,139 @@ func ValidateClusterQueueUpdate(newObj, oldObj *kueue.ClusterQueue) field.ErrorL
 	return allErrs
 }
 
-func validateResources(resources []kueue.Resource, path *field.Path) field.ErrorList {
+func validateResourceGroups(groups []kueue.ResourceGroup, path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
-	flavorsPerRes := make([]sets.Set[string], len(resources))
+	seenResources := sets.New[corev1.ResourceName]()
+	seenFlavors := sets.New[kueue.ResourceFlavorReference]()
 
-	for i, resource := range resources {
+	for i, rg := range groups {
 		path := path.Index(i)
-		allErrs = append(allErrs, validateResourceName(resource.Name, path.Child("name"))...)
-
-		flavorsPerRes[i] = make(sets.Set[string], len(resource.Flavors))
-		for j, flavor := range resource.Flavors {
-			path := path.Child("flavors").Index(j)
-			allErrs = append(allErrs, validateNameReference(string(flavor.Name), path.Child("name"))...)
-			allErrs = append(allErrs, validateFlavorQuota(flavor, path.Child("quota"))...)
-			flavorsPerRes[i].Insert(string(flavor.Name))
+		for j, name := range rg.CoveredResources {
+			path := path.Child("coveredResources").Index(j)
+			allErrs = append(allErrs, validateResourceName(name, path)...)
+			if seenResources.Has(name) {
+				allErrs = append(allErrs, field.Duplicate(path, name))
+			} else {
+				seenResources.Insert(name)
+			}
 		}
-		for j := 0; j < i; j++ {
-			if !flavorsPerRes[i].HasAny(flavorsPerRes[j].UnsortedList()...) || matchesFlavorsInOrder(resource.Flavors, resources[j].Flavors) {
-				continue
+		for j, fq := range rg.Flavors {
+			path := path.Child("flavors").Index(j)
+			if fq.Name == kueue.AnyFlavor {
+				if len(rg.Flavors) > 1 {
+					allErrs = append(allErrs, field.Invalid(path.Child("name"), fq.Name, "the wildcard flavor must be the only flavor of the resource group"))
+				}
+			} else {
+				allErrs = append(allErrs, validateNameReference(string(fq.Name), path.Child("name"))...)
 			}
-			err := field.Invalid(path.Child("flavors"), resource.Flavors, fmt.Sprintf("has flavors present in resource %s; all flavors must be different or they all must be present in the same order", resources[j].Name))
-			allErrs = append(allErrs, err)
+			if seenFlavors.Has(fq.Name) {
+				allErrs = append(allErrs, field.Duplicate(path.Child("name"), fq.Name))
+			} else {
+				seenFlavors.Insert(fq.Name)
+			}
+			allErrs = append(allErrs, validateFlavorQuotas(fq, rg.CoveredResources, path)...)
 		}
 	}
 	return allErrs
 }
 
-func validateFlavorQuota(flavor kueue.Flavor, path *field.Path) field.ErrorList {
+// validateFlavorQuotas checks that the flavor has a quota for each of the
+// covered resources, in the same order, and validates the quotas.
+func validateFlavorQuotas(fq kueue.FlavorQuotas, coveredResources []corev1.ResourceName, path *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
-	allErrs = append(allErrs, validateResourceQuantity(flavor.Quota.Min, path.Child("min"))...)
+	if len(fq.Resources) != len(coveredResources) {
+		allErrs = append(allErrs, field.Invalid(path.Child("resources"), field.OmitValueType{}, "must have the same number of resources as the coveredResources"))
+	}
+	for i, rq := range fq.Resources {
+		path := path.Child("resources").Index(i)
+		if i < len(coveredResources) && rq.Name != coveredResources[i] {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), rq.Name, fmt.Sprintf("must match the name of the coveredResources[%d], %s", i, coveredResources[i])))
+		}
+		allErrs = append(allErrs, validateQuota(fq.Name, rq.Quota, path.Child("quota"))...)
+	}
+	return allErrs
+}
 
-	if flavor.Quota.Max != nil {
-		allErrs = append(allErrs, validateResourceQuantity(*flavor.Quota.Max, path.Child("max"))...)
-		if flavor.Quota.Min.Cmp(*flavor.Quota.Max) > 0 {
-			allErrs = append(allErrs, field.Invalid(path.Child("min"), flavor.Quota.Min.String(), fmt.Sprintf("must be less than or equal to %s max", flavor.Name)))
+// validateQuotaSchedules checks that the time zones of the schedules exist,
+// and that their quotas replace quotas of the resource groups.
+func validateQuotaSchedules(schedules []kueue.QuotaSchedule, groups []kueue.ResourceGroup, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, qs := range schedules {
+		path := path.Index(i)
+		if qs.TimeZone != nil {
+			if _, err := time.LoadLocation(*qs.TimeZone); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), *qs.TimeZone, err.Error()))
+			}
+		}
+		seen := sets.New[string]()
+		for j, sq := range qs.Quotas {
+			path := path.Child("quotas").Index(j)
+			key := fmt.Sprintf("%s/%s", sq.Flavor, sq.Resource)
+			if seen.Has(key) {
+				allErrs = append(allErrs, field.Duplicate(path, key))
+			} else {
+				seen.Insert(key)
+			}
+			if !hasFlavorResource(groups, sq.Flavor, sq.Resource) {
+				allErrs = append(allErrs, field.Invalid(path, key, "the flavor must have a quota for the resource in the resourceGroups"))
+			}
+			allErrs = append(allErrs, validateQuota(sq.Flavor, sq.Quota, path.Child("quota"))...)
 		}
 	}
 	return allErrs
 }
 
-func matchesFlavorsInOrder(f1, f2 []kueue.Flavor) bool {
-	if len(f1) != len(f2) {
-		return false
+func hasFlavorResource(groups []kueue.ResourceGroup, flavor kueue.ResourceFlavorReference, resource corev1.ResourceName) bool {
+	for _, rg := range groups {
+		for _, fq := range rg.Flavors {
+			if fq.Name != flavor {
+				continue
+			}
+			for _, rq := range fq.Resources {
+				if rq.Name == resource {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func validateQuota(flavor kueue.ResourceFlavorReference, quota kueue.Quota, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateResourceQuantity(quota.Min, path.Child("min"))...)
+	// The effective min quota of a percentage depends on the nodes, so it's
+	// not compared with max and lendingLimit, which cap it instead.
+	autoSized := quota.MinPercentage != nil
+	if autoSized && !quota.Min.IsZero() {
+		allErrs = append(allErrs, field.Invalid(path.Child("min"), quota.Min.String(), "must be 0 when minPercentage is set"))
+	}
+	if autoSized && flavor == kueue.AnyFlavor {
+		allErrs = append(allErrs, field.Forbidden(path.Child("minPercentage"), "not supported for the wildcard flavor"))
 	}
-	for i := range f1 {
-		if f1[i].Name != f2[i].Name {
-			return false
+
+	if quota.Max != nil {
+		allErrs = append(allErrs, validateResourceQuantity(*quota.Max, path.Child("max"))...)
+		if !autoSized && quota.Min.Cmp(*quota.Max) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("min"), quota.Min.String(), fmt.Sprintf("must be less than or equal to %s max", flavor)))
+		}
+	}
+	if quota.BorrowingLimit != nil {
+		allErrs = append(allErrs, validateResourceQuantity(*quota.BorrowingLimit, path.Child("borrowingLimit"))...)
+	}
+	if quota.LendingLimit != nil {
+		allErrs = append(allErrs, validateResourceQuantity(*quota.LendingLimit, path.Child("lendingLimit"))...)
+		if !autoSized && quota.LendingLimit.Cmp(quota.Min) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("lendingLimit"), quota.LendingLimit.String(), fmt.Sprintf("must be less than or equal to %s min", flavor)))
 		}
 	}
-	return true
+	if quota.MaxPerNamespace != nil {
+		allErrs = append(allErrs, validateResourceQuantity(*quota.MaxPerNamespace, path.Child("maxPerNamespace"))...)
+		if quota.Max != nil && quota.MaxPerNamespace.Cmp(*quota.Max) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxPerNamespace"), quota.MaxPerNamespace.String(), fmt.Sprintf("must be less than or equal to %s max", flavor)))
+		}
+	}
+	return allErrs
 }
 
 // validateResourceQuantity enforces that specified quantity is valid for specified resource
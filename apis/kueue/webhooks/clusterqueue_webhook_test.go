@@ -17,12 +17,22 @@ limitations under the License.
 package webhooks
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
@@ -30,7 +40,9 @@ import (
 
 func TestValidateClusterQueue(t *testing.T) {
 	specField := field.NewPath("spec")
-	resourceField := specField.Child("resources")
+	resourceGroupsField := specField.Child("resourceGroups")
+	flavorField := resourceGroupsField.Index(0).Child("flavors").Index(0)
+	quotaField := flavorField.Child("resources").Index(0).Child("quota")
 
 	testcases := []struct {
 		name         string
@@ -49,7 +61,7 @@ func TestValidateClusterQueue(t *testing.T) {
 				testingutil.MakeResource("@cpu").Obj(),
 			).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(resourceField.Index(0).Child("name"), "@cpu", ""),
+				field.Invalid(resourceGroupsField.Index(0).Child("coveredResources").Index(0), "@cpu", ""),
 			},
 		},
 		{
@@ -63,6 +75,17 @@ func TestValidateClusterQueue(t *testing.T) {
 				field.Invalid(specField.Child("cohort"), "@prod", ""),
 			},
 		},
+		{
+			name:         "admission checks",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").AdmissionChecks("provisioning", "budget").Obj(),
+		},
+		{
+			name:         "invalid admission check name",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").AdmissionChecks("provisioning", "@budget").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specField.Child("admissionChecks").Index(1), "@budget", ""),
+			},
+		},
 		{
 			name: "extended resources with qualified names",
 			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
@@ -75,7 +98,7 @@ func TestValidateClusterQueue(t *testing.T) {
 				testingutil.MakeResource("example.com/@gpu").Obj(),
 			).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(resourceField.Index(0).Child("name"), "example.com/@gpu", ""),
+				field.Invalid(resourceGroupsField.Index(0).Child("coveredResources").Index(0), "example.com/@gpu", ""),
 			},
 		},
 		{
@@ -90,7 +113,34 @@ func TestValidateClusterQueue(t *testing.T) {
 				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("invalid_name", "10").Obj()).Obj(),
 			).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(resourceField.Index(0).Child("flavors").Index(0).Child("name"), "invalid_name", ""),
+				field.Invalid(flavorField.Child("name"), "invalid_name", ""),
+			},
+		},
+		{
+			name: "wildcard flavor",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Resource(testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "10").Obj()).Obj()).
+				Resource(testingutil.MakeResource("ephemeral-storage").Flavor(testingutil.MakeFlavor("*", "10Gi").Obj()).Obj()).
+				Obj(),
+		},
+		{
+			name: "wildcard flavor with other flavors",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").
+					Flavor(testingutil.MakeFlavor("*", "10").Obj()).
+					Flavor(testingutil.MakeFlavor("x86", "10").Obj()).Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(flavorField.Child("name"), "*", ""),
+			},
+		},
+		{
+			name: "wildcard flavor with minPercentage",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("*", "0").MinPercentage(50).Obj()).Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(quotaField.Child("minPercentage"), ""),
 			},
 		},
 		{
@@ -99,7 +149,7 @@ func TestValidateClusterQueue(t *testing.T) {
 				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "-1").Obj()).Obj(),
 			).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(resourceField.Index(0).Child("flavors").Index(0).Child("quota", "min"), "-1", ""),
+				field.Invalid(quotaField.Child("min"), "-1", ""),
 			},
 		},
 		{
@@ -108,6 +158,12 @@ func TestValidateClusterQueue(t *testing.T) {
 				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "0").Obj()).Obj(),
 			).Obj(),
 		},
+		{
+			name: "flavor quota with zero value and borrowingLimit",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Cohort("prod").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "0").BorrowingLimit("10").Obj()).Obj(),
+			).Obj(),
+		},
 		{
 			name: "flavor quota with min is equal to max",
 			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
@@ -120,7 +176,67 @@ func TestValidateClusterQueue(t *testing.T) {
 				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").Max("1").Obj()).Obj(),
 			).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(resourceField.Index(0).Child("flavors").Index(0).Child("quota", "min"), "2", ""),
+				field.Invalid(quotaField.Child("min"), "2", ""),
+			},
+		},
+		{
+			name: "flavor quota with maxPerNamespace less than max",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").Max("4").MaxPerNamespace("3").Obj()).Obj(),
+			).Obj(),
+		},
+		{
+			name: "flavor quota with maxPerNamespace greater than max",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").Max("4").MaxPerNamespace("5").Obj()).Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(quotaField.Child("maxPerNamespace"), "5", ""),
+			},
+		},
+		{
+			name: "flavor quota with borrowingLimit",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").BorrowingLimit("0").Obj()).Obj(),
+			).Obj(),
+		},
+		{
+			name: "flavor quota with negative borrowingLimit",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").BorrowingLimit("-1").Obj()).Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(quotaField.Child("borrowingLimit"), "-1", ""),
+			},
+		},
+		{
+			name: "flavor quota with lendingLimit less than min",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").LendingLimit("1").Obj()).Obj(),
+			).Obj(),
+		},
+		{
+			name: "flavor quota with lendingLimit greater than min",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").LendingLimit("3").Obj()).Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(quotaField.Child("lendingLimit"), "3", ""),
+			},
+		},
+		{
+			name: "flavor quota with minPercentage",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "0").MinPercentage(50).Max("10").LendingLimit("3").Obj()).Obj(),
+			).Obj(),
+		},
+		{
+			name: "flavor quota with minPercentage and min",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").Resource(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "2").MinPercentage(50).Obj()).Obj(),
+			).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(quotaField.Child("min"), "2", ""),
 			},
 		},
 		{
@@ -175,7 +291,8 @@ func TestValidateClusterQueue(t *testing.T) {
 					Flavor(testingutil.MakeFlavor("alpha", "0").Obj()).Obj()).
 				Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(specField.Child("resources").Index(1).Child("flavors"), nil, ""),
+				field.Duplicate(resourceGroupsField.Index(1).Child("flavors").Index(0).Child("name"), nil),
+				field.Duplicate(resourceGroupsField.Index(1).Child("flavors").Index(1).Child("name"), nil),
 			},
 		},
 		{
@@ -190,7 +307,116 @@ func TestValidateClusterQueue(t *testing.T) {
 					Flavor(testingutil.MakeFlavor("omega", "0").Obj()).Obj()).
 				Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(specField.Child("resources").Index(1).Child("flavors"), nil, ""),
+				field.Duplicate(resourceGroupsField.Index(1).Child("flavors").Index(0).Child("name"), nil),
+				field.Duplicate(resourceGroupsField.Index(1).Child("flavors").Index(1).Child("name"), nil),
+			},
+		},
+		{
+			name: "resource covered by more than one resource group",
+			clusterQueue: &kueue.ClusterQueue{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-queue"},
+				Spec: kueue.ClusterQueueSpec{
+					ResourceGroups: []kueue.ResourceGroup{
+						{
+							CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+							Flavors: []kueue.FlavorQuotas{{
+								Name:      "alpha",
+								Resources: []kueue.ResourceQuota{{Name: corev1.ResourceCPU}},
+							}},
+						},
+						{
+							CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+							Flavors: []kueue.FlavorQuotas{{
+								Name:      "beta",
+								Resources: []kueue.ResourceQuota{{Name: corev1.ResourceCPU}},
+							}},
+						},
+					},
+				},
+			},
+			wantErr: field.ErrorList{
+				field.Duplicate(resourceGroupsField.Index(1).Child("coveredResources").Index(0), nil),
+			},
+		},
+		{
+			name: "flavor resources don't match the covered resources",
+			clusterQueue: &kueue.ClusterQueue{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-queue"},
+				Spec: kueue.ClusterQueueSpec{
+					ResourceGroups: []kueue.ResourceGroup{{
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+						Flavors: []kueue.FlavorQuotas{
+							{
+								Name: "alpha",
+								Resources: []kueue.ResourceQuota{
+									{Name: corev1.ResourceMemory},
+									{Name: corev1.ResourceCPU},
+								},
+							},
+							{
+								Name:      "beta",
+								Resources: []kueue.ResourceQuota{{Name: corev1.ResourceCPU}},
+							},
+						},
+					}},
+				},
+			},
+			wantErr: field.ErrorList{
+				field.Invalid(flavorField.Child("resources").Index(0).Child("name"), nil, ""),
+				field.Invalid(flavorField.Child("resources").Index(1).Child("name"), nil, ""),
+				field.Invalid(resourceGroupsField.Index(0).Child("flavors").Index(1).Child("resources"), nil, ""),
+			},
+		},
+		{
+			name: "valid quota schedule",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Resource(testingutil.MakeResource(corev1.ResourceCPU).Flavor(testingutil.MakeFlavor("default", "10").Obj()).Obj()).
+				QuotaSchedule(kueue.QuotaSchedule{
+					Name:     "nights",
+					Start:    "20:00",
+					End:      "08:00",
+					TimeZone: pointer.String("UTC"),
+					Quotas: []kueue.ScheduledQuota{{
+						Flavor:   "default",
+						Resource: corev1.ResourceCPU,
+						Quota:    kueue.Quota{Min: resource.MustParse("20")},
+					}},
+				}).
+				Obj(),
+		},
+		{
+			name: "invalid quota schedule",
+			clusterQueue: testingutil.MakeClusterQueue("cluster-queue").
+				Resource(testingutil.MakeResource(corev1.ResourceCPU).Flavor(testingutil.MakeFlavor("default", "10").Obj()).Obj()).
+				QuotaSchedule(kueue.QuotaSchedule{
+					Name:     "nights",
+					Start:    "20:00",
+					End:      "08:00",
+					TimeZone: pointer.String("Mars/Olympus_Mons"),
+					Quotas: []kueue.ScheduledQuota{
+						{
+							Flavor:   "default",
+							Resource: corev1.ResourceMemory,
+							Quota:    kueue.Quota{Min: resource.MustParse("1Gi")},
+						},
+						{
+							Flavor:   "default",
+							Resource: corev1.ResourceCPU,
+							Quota:    kueue.Quota{Min: resource.MustParse("-1")},
+						},
+						{
+							Flavor:   "default",
+							Resource: corev1.ResourceCPU,
+							Quota:    kueue.Quota{Min: resource.MustParse("20")},
+						},
+					},
+				}).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specField.Child("quotaSchedules").Index(0).Child("timeZone"), nil, ""),
+				field.Invalid(specField.Child("quotaSchedules").Index(0).Child("quotas").Index(0), nil, ""),
+				field.Invalid(specField.Child("quotaSchedules").Index(0).Child("quotas").Index(1).Child("quota", "min"), nil, ""),
+				field.Duplicate(specField.Child("quotaSchedules").Index(0).Child("quotas").Index(2), nil),
 			},
 		},
 	}
@@ -237,3 +463,255 @@ func TestValidateClusterQueueUpdate(t *testing.T) {
 		})
 	}
 }
+
+type fakeQuotaSimulator struct {
+	workloads []string
+	err       error
+	simulated bool
+}
+
+func (s *fakeQuotaSimulator) WorkloadsOverQuotaAfterUpdate(*kueue.ClusterQueue) ([]string, error) {
+	s.simulated = true
+	return s.workloads, s.err
+}
+
+func TestClusterQueueQuotaUpdateWarnings(t *testing.T) {
+	clusterQueue := func(nominalQuota string) *kueue.ClusterQueue {
+		return testingutil.MakeClusterQueue("cluster-queue").
+			QueueingStrategy(kueue.BestEffortFIFO).
+			Resource(testingutil.MakeResource(corev1.ResourceCPU).
+				Flavor(testingutil.MakeFlavor("default", nominalQuota).Obj()).
+				Obj()).
+			Obj()
+	}
+	manyWorkloads := make([]string, 12)
+	for i := range manyWorkloads {
+		manyWorkloads[i] = fmt.Sprintf("ns/wl-%02d", i)
+	}
+	testcases := map[string]struct {
+		operation     admissionv1.Operation
+		newCQ         *kueue.ClusterQueue
+		oldCQ         *kueue.ClusterQueue
+		simulator     fakeQuotaSimulator
+		notLeader     bool
+		wantAllowed   bool
+		wantSimulated bool
+		wantWarnings  []string
+	}{
+		"quota reduced": {
+			operation:     admissionv1.Update,
+			newCQ:         clusterQueue("2"),
+			oldCQ:         clusterQueue("10"),
+			simulator:     fakeQuotaSimulator{workloads: []string{"ns/a", "ns/b"}},
+			wantAllowed:   true,
+			wantSimulated: true,
+			wantWarnings: []string{
+				"2 admitted workloads wouldn't fit in the new quotas of the ClusterQueue: ns/a, ns/b",
+			},
+		},
+		"too many workloads to list": {
+			operation:     admissionv1.Update,
+			newCQ:         clusterQueue("2"),
+			oldCQ:         clusterQueue("10"),
+			simulator:     fakeQuotaSimulator{workloads: manyWorkloads},
+			wantAllowed:   true,
+			wantSimulated: true,
+			wantWarnings: []string{
+				"12 admitted workloads wouldn't fit in the new quotas of the ClusterQueue: " +
+					"ns/wl-00, ns/wl-01, ns/wl-02, ns/wl-03, ns/wl-04, ns/wl-05, ns/wl-06, ns/wl-07, ns/wl-08, ns/wl-09 and 2 more",
+			},
+		},
+		"served by a replica that isn't the leader": {
+			operation:   admissionv1.Update,
+			newCQ:       clusterQueue("2"),
+			oldCQ:       clusterQueue("10"),
+			simulator:   fakeQuotaSimulator{workloads: []string{"ns/a"}},
+			notLeader:   true,
+			wantAllowed: true,
+			wantWarnings: []string{
+				"The admitted workloads that wouldn't fit in the new quotas of the ClusterQueue couldn't be checked, because the update was served by a replica of Kueue that isn't the leader",
+			},
+		},
+		"all workloads fit": {
+			operation:     admissionv1.Update,
+			newCQ:         clusterQueue("2"),
+			oldCQ:         clusterQueue("10"),
+			wantAllowed:   true,
+			wantSimulated: true,
+		},
+		"simulation failed": {
+			operation:     admissionv1.Update,
+			newCQ:         clusterQueue("2"),
+			oldCQ:         clusterQueue("10"),
+			simulator:     fakeQuotaSimulator{err: fmt.Errorf("unknown flavor")},
+			wantAllowed:   true,
+			wantSimulated: true,
+		},
+		"quotas not updated": {
+			operation:   admissionv1.Update,
+			newCQ:       testingutil.MakeClusterQueue("cluster-queue").QueueingStrategy(kueue.BestEffortFIFO).Cohort("cohort").Obj(),
+			oldCQ:       testingutil.MakeClusterQueue("cluster-queue").QueueingStrategy(kueue.BestEffortFIFO).Obj(),
+			simulator:   fakeQuotaSimulator{workloads: []string{"ns/a"}},
+			wantAllowed: true,
+		},
+		"creation": {
+			operation:   admissionv1.Create,
+			newCQ:       clusterQueue("2"),
+			simulator:   fakeQuotaSimulator{workloads: []string{"ns/a"}},
+			wantAllowed: true,
+		},
+		"invalid update": {
+			operation: admissionv1.Update,
+			newCQ:     clusterQueue("2"),
+			oldCQ:     testingutil.MakeClusterQueue("cluster-queue").QueueingStrategy(kueue.StrictFIFO).Obj(),
+			simulator: fakeQuotaSimulator{workloads: []string{"ns/a"}},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			elected := make(chan struct{})
+			if !tc.notLeader {
+				close(elected)
+			}
+			wh := &webhook.Admission{
+				Handler: &clusterQueueValidator{
+					validator: admission.WithCustomValidator(&kueue.ClusterQueue{}, &ClusterQueueWebhook{}).Handler,
+					simulator: &tc.simulator,
+					elected:   elected,
+				},
+			}
+			if err := wh.InjectScheme(testingutil.MustGetScheme(t)); err != nil {
+				t.Fatalf("Failed injecting the scheme: %v", err)
+			}
+			if err := wh.InjectFunc(func(interface{}) error { return nil }); err != nil {
+				t.Fatalf("Failed injecting the decoder: %v", err)
+			}
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Object:    rawExtension(t, tc.newCQ),
+			}}
+			if tc.oldCQ != nil {
+				req.OldObject = rawExtension(t, tc.oldCQ)
+			}
+
+			resp := wh.Handle(context.Background(), req)
+			if resp.Allowed != tc.wantAllowed {
+				t.Errorf("Got allowed %t, want %t: %v", resp.Allowed, tc.wantAllowed, resp.Result)
+			}
+			if tc.simulator.simulated != tc.wantSimulated {
+				t.Errorf("Got simulated %t, want %t", tc.simulator.simulated, tc.wantSimulated)
+			}
+			if diff := cmp.Diff(tc.wantWarnings, resp.Warnings); diff != "" {
+				t.Errorf("Unexpected warnings (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func rawExtension(t *testing.T, obj runtime.Object) runtime.RawExtension {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("Failed marshalling the object: %v", err)
+	}
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestClusterQueueDefaultResources(t *testing.T) {
+	cases := map[string]struct {
+		resources          []kueue.Resource
+		resourceGroups     []kueue.ResourceGroup
+		wantResourceGroups []kueue.ResourceGroup
+	}{
+		"resource groups are kept": {
+			resourceGroups: testingutil.ResourceGroups(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "10").Obj()).Obj(),
+			),
+			wantResourceGroups: testingutil.ResourceGroups(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "10").Obj()).Obj(),
+			),
+		},
+		"codependent resources are grouped": {
+			resources: []kueue.Resource{
+				{
+					Name: corev1.ResourceCPU,
+					Flavors: []kueue.Flavor{
+						{Name: "on-demand", Quota: kueue.Quota{Min: resource.MustParse("10")}},
+						{Name: "spot", Quota: kueue.Quota{Min: resource.MustParse("5")}},
+					},
+				},
+				{
+					Name: corev1.ResourceMemory,
+					Flavors: []kueue.Flavor{
+						{Name: "on-demand", Quota: kueue.Quota{Min: resource.MustParse("10Gi")}},
+						{Name: "spot", Quota: kueue.Quota{Min: resource.MustParse("5Gi")}},
+					},
+				},
+			},
+			wantResourceGroups: testingutil.ResourceGroups(
+				testingutil.MakeResource(corev1.ResourceCPU).
+					Flavor(testingutil.MakeFlavor("on-demand", "10").Obj()).
+					Flavor(testingutil.MakeFlavor("spot", "5").Obj()).Obj(),
+				testingutil.MakeResource(corev1.ResourceMemory).
+					Flavor(testingutil.MakeFlavor("on-demand", "10Gi").Obj()).
+					Flavor(testingutil.MakeFlavor("spot", "5Gi").Obj()).Obj(),
+			),
+		},
+		"resources with different flavors are separate groups": {
+			resources: []kueue.Resource{
+				{
+					Name: corev1.ResourceCPU,
+					Flavors: []kueue.Flavor{
+						{Name: "x86", Quota: kueue.Quota{Min: resource.MustParse("10")}},
+					},
+				},
+				{
+					Name: "example.com/gpu",
+					Flavors: []kueue.Flavor{
+						{Name: "a100", Quota: kueue.Quota{Min: resource.MustParse("4")}},
+						{Name: "t4", Quota: kueue.Quota{Min: resource.MustParse("8")}},
+					},
+				},
+			},
+			wantResourceGroups: testingutil.ResourceGroups(
+				testingutil.MakeResource(corev1.ResourceCPU).Flavor(testingutil.MakeFlavor("x86", "10").Obj()).Obj(),
+				testingutil.MakeResource("example.com/gpu").
+					Flavor(testingutil.MakeFlavor("a100", "4").Obj()).
+					Flavor(testingutil.MakeFlavor("t4", "8").Obj()).Obj(),
+			),
+		},
+		"resources replace the resource groups": {
+			resources: []kueue.Resource{
+				{
+					Name: corev1.ResourceCPU,
+					Flavors: []kueue.Flavor{
+						{Name: "arm", Quota: kueue.Quota{Min: resource.MustParse("4")}},
+					},
+				},
+			},
+			resourceGroups: testingutil.ResourceGroups(
+				testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "10").Obj()).Obj(),
+			),
+			wantResourceGroups: testingutil.ResourceGroups(
+				testingutil.MakeResource(corev1.ResourceCPU).Flavor(testingutil.MakeFlavor("arm", "4").Obj()).Obj(),
+			),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq := testingutil.MakeClusterQueue("cq").Obj()
+			cq.Spec.Resources = tc.resources
+			cq.Spec.ResourceGroups = tc.resourceGroups
+			wh := &ClusterQueueWebhook{}
+			if err := wh.Default(context.Background(), cq); err != nil {
+				t.Fatalf("Could not apply defaults: %v", err)
+			}
+			if cq.Spec.Resources != nil {
+				t.Errorf("Deprecated resources weren't cleared: %v", cq.Spec.Resources)
+			}
+			if diff := cmp.Diff(tc.wantResourceGroups, cq.Spec.ResourceGroups); diff != "" {
+				t.Errorf("Obtained wrong resource groups (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
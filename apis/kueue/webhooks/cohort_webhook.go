@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+type CohortWebhook struct{}
+
+func setupWebhookForCohort(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kueue.Cohort{}).
+		WithDefaulter(&CohortWebhook{}).
+		WithValidator(&CohortWebhook{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-kueue-x-k8s-io-v1alpha2-cohort,mutating=true,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=cohorts,verbs=create;update,versions=v1alpha2,name=mcohort.kb.io,admissionReviewVersions=v1
+
+var _ webhook.CustomDefaulter = &CohortWebhook{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (w *CohortWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	cohort := obj.(*kueue.Cohort)
+	log := ctrl.LoggerFrom(ctx).WithName("cohort-webhook")
+	log.V(5).Info("Applying defaults", "cohort", klog.KObj(cohort))
+	if len(cohort.Spec.Resources) > 0 {
+		cohort.Spec.ResourceGroups = kueue.ResourceGroupsFromResources(cohort.Spec.Resources)
+		cohort.Spec.Resources = nil
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-kueue-x-k8s-io-v1alpha2-cohort,mutating=false,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=cohorts,verbs=create;update,versions=v1alpha2,name=vcohort.kb.io,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &CohortWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *CohortWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	cohort := obj.(*kueue.Cohort)
+	log := ctrl.LoggerFrom(ctx).WithName("cohort-webhook")
+	log.V(5).Info("Validating create", "cohort", klog.KObj(cohort))
+	return ValidateCohort(cohort).ToAggregate()
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *CohortWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	newCohort := newObj.(*kueue.Cohort)
+	log := ctrl.LoggerFrom(ctx).WithName("cohort-webhook")
+	log.V(5).Info("Validating update", "cohort", klog.KObj(newCohort))
+	return ValidateCohort(newCohort).ToAggregate()
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *CohortWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func ValidateCohort(cohort *kueue.Cohort) field.ErrorList {
+	path := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	if len(cohort.Spec.Parent) != 0 {
+		allErrs = append(allErrs, validateNameReference(cohort.Spec.Parent, path.Child("parent"))...)
+		if cohort.Spec.Parent == cohort.Name {
+			allErrs = append(allErrs, field.Invalid(path.Child("parent"), cohort.Spec.Parent, "must be different from the cohort name"))
+		}
+	}
+	groupsPath := path.Child("resourceGroups")
+	allErrs = append(allErrs, validateResourceGroups(cohort.Spec.ResourceGroups, groupsPath)...)
+	for i, rg := range cohort.Spec.ResourceGroups {
+		for j, fq := range rg.Flavors {
+			for k, rq := range fq.Resources {
+				quotaPath := groupsPath.Index(i).Child("flavors").Index(j).Child("resources").Index(k).Child("quota")
+				if rq.Quota.MaxPerNamespace != nil {
+					allErrs = append(allErrs, field.Forbidden(quotaPath.Child("maxPerNamespace"), "not supported for cohorts"))
+				}
+				if rq.Quota.MinPercentage != nil {
+					allErrs = append(allErrs, field.Forbidden(quotaPath.Child("minPercentage"), "not supported for cohorts"))
+				}
+			}
+		}
+	}
+	return allErrs
+}
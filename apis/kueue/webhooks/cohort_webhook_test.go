@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestValidateCohort(t *testing.T) {
+	specPath := field.NewPath("spec")
+	quotaPath := specPath.Child("resourceGroups").Index(0).Child("flavors").Index(0).Child("resources").Index(0).Child("quota")
+
+	testcases := map[string]struct {
+		cohort  *kueue.Cohort
+		wantErr field.ErrorList
+	}{
+		"valid parent and resources": {
+			cohort: &kueue.Cohort{
+				ObjectMeta: metav1.ObjectMeta{Name: "child"},
+				Spec: kueue.CohortSpec{
+					Parent: "parent",
+					ResourceGroups: testingutil.ResourceGroups(
+						testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "1").Max("10").Obj()).Obj(),
+					),
+				},
+			},
+		},
+		"invalid parent": {
+			cohort: &kueue.Cohort{
+				ObjectMeta: metav1.ObjectMeta{Name: "child"},
+				Spec: kueue.CohortSpec{
+					Parent: "@parent",
+				},
+			},
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("parent"), "@parent", ""),
+			},
+		},
+		"parent is the cohort itself": {
+			cohort: &kueue.Cohort{
+				ObjectMeta: metav1.ObjectMeta{Name: "child"},
+				Spec: kueue.CohortSpec{
+					Parent: "child",
+				},
+			},
+			wantErr: field.ErrorList{
+				field.Invalid(specPath.Child("parent"), "child", ""),
+			},
+		},
+		"maxPerNamespace is not supported": {
+			cohort: &kueue.Cohort{
+				ObjectMeta: metav1.ObjectMeta{Name: "child"},
+				Spec: kueue.CohortSpec{
+					ResourceGroups: testingutil.ResourceGroups(
+						testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "1").MaxPerNamespace("1").Obj()).Obj(),
+					),
+				},
+			},
+			wantErr: field.ErrorList{
+				field.Forbidden(quotaPath.Child("maxPerNamespace"), ""),
+			},
+		},
+		"minPercentage is not supported": {
+			cohort: &kueue.Cohort{
+				ObjectMeta: metav1.ObjectMeta{Name: "child"},
+				Spec: kueue.CohortSpec{
+					ResourceGroups: testingutil.ResourceGroups(
+						testingutil.MakeResource("cpu").Flavor(testingutil.MakeFlavor("x86", "0").MinPercentage(50).Obj()).Obj(),
+					),
+				},
+			},
+			wantErr: field.ErrorList{
+				field.Forbidden(quotaPath.Child("minPercentage"), ""),
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			gotErr := ValidateCohort(tc.cohort)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("ValidateCohort() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCohortDefaultResources(t *testing.T) {
+	cohort := &kueue.Cohort{
+		ObjectMeta: metav1.ObjectMeta{Name: "cohort"},
+		Spec: kueue.CohortSpec{
+			Resources: []kueue.Resource{
+				{
+					Name: corev1.ResourceCPU,
+					Flavors: []kueue.Flavor{
+						{Name: "x86", Quota: kueue.Quota{Min: resource.MustParse("10")}},
+					},
+				},
+				{
+					Name: corev1.ResourceMemory,
+					Flavors: []kueue.Flavor{
+						{Name: "x86", Quota: kueue.Quota{Min: resource.MustParse("10Gi")}},
+					},
+				},
+			},
+		},
+	}
+	wantResourceGroups := testingutil.ResourceGroups(
+		testingutil.MakeResource(corev1.ResourceCPU).Flavor(testingutil.MakeFlavor("x86", "10").Obj()).Obj(),
+		testingutil.MakeResource(corev1.ResourceMemory).Flavor(testingutil.MakeFlavor("x86", "10Gi").Obj()).Obj(),
+	)
+	wh := &CohortWebhook{}
+	if err := wh.Default(context.Background(), cohort); err != nil {
+		t.Fatalf("Could not apply defaults: %v", err)
+	}
+	if cohort.Spec.Resources != nil {
+		t.Errorf("Deprecated resources weren't cleared: %v", cohort.Spec.Resources)
+	}
+	if diff := cmp.Diff(wantResourceGroups, cohort.Spec.ResourceGroups); diff != "" {
+		t.Errorf("Obtained wrong resource groups (-want,+got):\n%s", diff)
+	}
+}
@@ -18,23 +18,38 @@ package webhooks
 
 import (
 	"context"
+	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 )
 
-type LocalQueueWebhook struct{}
+type LocalQueueWebhook struct {
+	client client.Client
+	// validateNamespaceSelector indicates if the LocalQueues whose
+	// ClusterQueue doesn't select their namespace are rejected.
+	validateNamespaceSelector bool
+}
 
-func setupWebhookForLocalQueue(mgr ctrl.Manager) error {
+func setupWebhookForLocalQueue(mgr ctrl.Manager, validateNamespaceSelector bool) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&kueue.LocalQueue{}).
-		WithValidator(&LocalQueueWebhook{}).
+		WithValidator(&LocalQueueWebhook{
+			client:                    mgr.GetClient(),
+			validateNamespaceSelector: validateNamespaceSelector,
+		}).
 		Complete()
 }
 
@@ -47,7 +62,48 @@ func (w *LocalQueueWebhook) ValidateCreate(ctx context.Context, obj runtime.Obje
 	q := obj.(*kueue.LocalQueue)
 	log := ctrl.LoggerFrom(ctx).WithName("localqueue-webhook")
 	log.V(5).Info("Validating create", "localQueue", klog.KObj(q))
-	return ValidateLocalQueue(q).ToAggregate()
+	allErrs := ValidateLocalQueue(q)
+	if w.validateNamespaceSelector && len(allErrs) == 0 {
+		errs, err := w.validateClusterQueueNamespaceSelector(ctx, q)
+		if err != nil {
+			return err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	return allErrs.ToAggregate()
+}
+
+// validateClusterQueueNamespaceSelector rejects the LocalQueue if its
+// ClusterQueue exists and the namespaceSelector of the ClusterQueue doesn't
+// match the namespace of the LocalQueue. The ClusterQueue can be created after
+// the LocalQueue.
+func (w *LocalQueueWebhook) validateClusterQueueNamespaceSelector(ctx context.Context, q *kueue.LocalQueue) (field.ErrorList, error) {
+	var cq kueue.ClusterQueue
+	if err := w.client.Get(ctx, types.NamespacedName{Name: string(q.Spec.ClusterQueue)}, &cq); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting the ClusterQueue: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(cq.Spec.NamespaceSelector)
+	if err != nil {
+		// The ClusterQueue webhook validates the selector.
+		return nil, fmt.Errorf("parsing the namespaceSelector of the ClusterQueue: %w", err)
+	}
+	var ns corev1.Namespace
+	if err := w.client.Get(ctx, types.NamespacedName{Name: q.Namespace}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting the namespace: %w", err)
+	}
+	if !selector.Matches(labels.Set(ns.Labels)) {
+		return field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "clusterQueue"),
+				fmt.Sprintf("the namespaceSelector of the ClusterQueue %s doesn't match the namespace %s", cq.Name, q.Namespace)),
+		}, nil
+	}
+	return nil, nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
@@ -17,11 +17,16 @@ limitations under the License.
 package webhooks
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	. "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
@@ -81,3 +86,56 @@ func TestValidateLocalQueueUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateLocalQueueNamespaceSelector(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   testLocalQueueNamespace,
+			Labels: map[string]string{"team": "a"},
+		},
+	}
+	testCases := map[string]struct {
+		clusterQueue *ClusterQueue
+		wantErr      field.ErrorList
+	}{
+		"selector matches the namespace": {
+			clusterQueue: testingutil.MakeClusterQueue("cq").NamespaceSelector(&metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "a"},
+			}).Obj(),
+		},
+		"selector doesn't match the namespace": {
+			clusterQueue: testingutil.MakeClusterQueue("cq").NamespaceSelector(&metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "b"},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "clusterQueue"), ""),
+			},
+		},
+		"nil selector doesn't match any namespace": {
+			clusterQueue: testingutil.MakeClusterQueue("cq").NamespaceSelector(nil).Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "clusterQueue"), ""),
+			},
+		},
+		"ClusterQueue doesn't exist": {
+			clusterQueue: testingutil.MakeClusterQueue("other").NamespaceSelector(nil).Obj(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().
+				WithScheme(testingutil.MustGetScheme(t)).
+				WithObjects([]client.Object{ns, tc.clusterQueue}...).
+				Build()
+			w := &LocalQueueWebhook{client: cl, validateNamespaceSelector: true}
+			q := testingutil.MakeLocalQueue(testLocalQueueName, testLocalQueueNamespace).ClusterQueue("cq").Obj()
+			errList, err := w.validateClusterQueueNamespaceSelector(context.Background(), q)
+			if err != nil {
+				t.Fatalf("Failed validating the namespaceSelector: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantErr, errList, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("validateClusterQueueNamespaceSelector() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package webhooks
 
 import (
 	"context"
+	"math"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -93,9 +95,27 @@ func ValidateResourceFlavor(rf *kueue.ResourceFlavor) field.ErrorList {
 
 	taintsPath := field.NewPath("taints")
 	allErrs = append(allErrs, validateNodeTaints(rf.Taints, taintsPath)...)
+	allErrs = append(allErrs, validateTolerations(rf.Tolerations, field.NewPath("tolerations"))...)
+
+	if rf.TopologyName != nil {
+		allErrs = append(allErrs, validateNameReference(*rf.TopologyName, field.NewPath("topologyName"))...)
+	}
+	allErrs = append(allErrs, validateCost(rf.Annotations, field.NewPath("metadata", "annotations"))...)
 	return allErrs
 }
 
+func validateCost(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	v, found := annotations[kueue.ResourceFlavorCostAnnotation]
+	if !found {
+		return nil
+	}
+	cost, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsInf(cost, 0) || math.IsNaN(cost) || cost < 0 {
+		return field.ErrorList{field.Invalid(fldPath.Key(kueue.ResourceFlavorCostAnnotation), v, "must be a non-negative decimal number")}
+	}
+	return nil
+}
+
 // validateNodeTaints is extracted from git.k8s.io/kubernetes/pkg/apis/core/validation/validation.go
 func validateNodeTaints(taints []corev1.Taint, fldPath *field.Path) field.ErrorList {
 	allErrors := field.ErrorList{}
@@ -130,6 +150,51 @@ func validateNodeTaints(taints []corev1.Taint, fldPath *field.Path) field.ErrorL
 	return allErrors
 }
 
+// validateTolerations is extracted from git.k8s.io/kubernetes/pkg/apis/core/validation/validation.go
+func validateTolerations(tolerations []corev1.Toleration, fldPath *field.Path) field.ErrorList {
+	allErrors := field.ErrorList{}
+	for i, toleration := range tolerations {
+		idxPath := fldPath.Index(i)
+		// validate the toleration key
+		if len(toleration.Key) > 0 {
+			allErrors = append(allErrors, metavalidation.ValidateLabelName(toleration.Key, idxPath.Child("key"))...)
+		}
+
+		// empty toleration key with Exists operator and empty value means match all taints
+		if len(toleration.Key) == 0 && toleration.Operator != corev1.TolerationOpExists {
+			allErrors = append(allErrors, field.Invalid(idxPath.Child("operator"), toleration.Operator,
+				"operator must be Exists when `key` is empty, which means \"match all values and all keys\""))
+		}
+
+		if toleration.TolerationSeconds != nil && toleration.Effect != corev1.TaintEffectNoExecute {
+			allErrors = append(allErrors, field.Invalid(idxPath.Child("effect"), toleration.Effect,
+				"effect must be 'NoExecute' when `tolerationSeconds` is set"))
+		}
+
+		// validate toleration operator and value
+		switch toleration.Operator {
+		// empty operator means Equal
+		case corev1.TolerationOpEqual, "":
+			if errs := validation.IsValidLabelValue(toleration.Value); len(errs) != 0 {
+				allErrors = append(allErrors, field.Invalid(idxPath.Child("operator"), toleration.Value, strings.Join(errs, ";")))
+			}
+		case corev1.TolerationOpExists:
+			if len(toleration.Value) > 0 {
+				allErrors = append(allErrors, field.Invalid(idxPath.Child("operator"), toleration.Value, "value must be empty when `operator` is 'Exists'"))
+			}
+		default:
+			validValues := []string{string(corev1.TolerationOpEqual), string(corev1.TolerationOpExists)}
+			allErrors = append(allErrors, field.NotSupported(idxPath.Child("operator"), toleration.Operator, validValues))
+		}
+
+		// validate toleration effect, empty toleration effect means match all taint effects
+		if len(toleration.Effect) > 0 {
+			allErrors = append(allErrors, validateTaintEffect(&toleration.Effect, true, idxPath.Child("effect"))...)
+		}
+	}
+	return allErrors
+}
+
 // validateTaintEffect is extracted from git.k8s.io/kubernetes/pkg/apis/core/validation/validation.go
 func validateTaintEffect(effect *corev1.TaintEffect, allowEmpty bool, fldPath *field.Path) field.ErrorList {
 	if !allowEmpty && len(*effect) == 0 {
@@ -47,8 +47,33 @@ func TestValidateResourceFlavor(t *testing.T) {
 					Key:    "spot",
 					Value:  "true",
 					Effect: corev1.TaintEffectNoSchedule,
+				}).
+				Toleration(corev1.Toleration{
+					Key:      "node-pool",
+					Operator: corev1.TolerationOpEqual,
+					Value:    "gpu",
+					Effect:   corev1.TaintEffectNoSchedule,
 				}).Obj(),
 		},
+		{
+			// Toleration validation is not exhaustively tested, because the code was copied from upstream k8s.
+			name: "invalid toleration",
+			rf: utiltesting.MakeResourceFlavor("resource-flavor").
+				Toleration(corev1.Toleration{
+					Key:      "node-pool",
+					Operator: corev1.TolerationOpExists,
+					Value:    "gpu",
+				}).
+				Toleration(corev1.Toleration{
+					Operator: corev1.TolerationOpEqual,
+					Effect:   "Never",
+				}).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("tolerations").Index(0).Child("operator"), "gpu", ""),
+				field.Invalid(field.NewPath("tolerations").Index(1).Child("operator"), corev1.TolerationOpEqual, ""),
+				field.NotSupported(field.NewPath("tolerations").Index(1).Child("effect"), corev1.TaintEffect("Never"), nil),
+			},
+		},
 		{
 			// Taint validation is not exhaustively tested, because the code was copied from upstream k8s.
 			name: "invalid taint",
@@ -73,6 +98,31 @@ func TestValidateResourceFlavor(t *testing.T) {
 				field.Invalid(field.NewPath("nodeSelector"), "@abc", ""),
 			},
 		},
+		{
+			name: "valid cost",
+			rf:   utiltesting.MakeResourceFlavor("resource-flavor").Cost("0.25").Obj(),
+		},
+		{
+			name: "invalid cost",
+			rf:   utiltesting.MakeResourceFlavor("resource-flavor").Cost("cheap").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "annotations").Key(kueue.ResourceFlavorCostAnnotation), "cheap", ""),
+			},
+		},
+		{
+			name: "negative cost",
+			rf:   utiltesting.MakeResourceFlavor("resource-flavor").Cost("-1").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "annotations").Key(kueue.ResourceFlavorCostAnnotation), "-1", ""),
+			},
+		},
+		{
+			name: "invalid topology name",
+			rf:   utiltesting.MakeResourceFlavor("resource-flavor").TopologyName("@default").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("topologyName"), "@default", ""),
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+
+	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+type TopologyWebhook struct{}
+
+func setupWebhookForTopology(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&kueue.Topology{}).
+		WithValidator(&TopologyWebhook{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-kueue-x-k8s-io-v1alpha2-topology,mutating=false,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=topologies,verbs=create;update,versions=v1alpha2,name=vtopology.kb.io,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &TopologyWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *TopologyWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	topology := obj.(*kueue.Topology)
+	log := ctrl.LoggerFrom(ctx).WithName("topology-webhook")
+	log.V(5).Info("Validating create", "topology", klog.KObj(topology))
+	return ValidateTopology(topology).ToAggregate()
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *TopologyWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	newTopology := newObj.(*kueue.Topology)
+	log := ctrl.LoggerFrom(ctx).WithName("topology-webhook")
+	log.V(5).Info("Validating update", "topology", klog.KObj(newTopology))
+	return ValidateTopology(newTopology).ToAggregate()
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *TopologyWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func ValidateTopology(topology *kueue.Topology) field.ErrorList {
+	levelsPath := field.NewPath("spec", "levels")
+
+	var allErrs field.ErrorList
+	nodeLabels := sets.New[string]()
+	for i, level := range topology.Spec.Levels {
+		path := levelsPath.Index(i).Child("nodeLabel")
+		allErrs = append(allErrs, metavalidation.ValidateLabelName(level.NodeLabel, path)...)
+		if nodeLabels.Has(level.NodeLabel) {
+			allErrs = append(allErrs, field.Duplicate(path, level.NodeLabel))
+		}
+		nodeLabels.Insert(level.NodeLabel)
+	}
+	return allErrs
+}
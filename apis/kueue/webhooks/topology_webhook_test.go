@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestValidateTopology(t *testing.T) {
+	levelsPath := field.NewPath("spec", "levels")
+
+	testcases := map[string]struct {
+		topology *kueue.Topology
+		wantErr  field.ErrorList
+	}{
+		"valid levels": {
+			topology: testingutil.MakeTopology("default", "cloud.provider.com/block", "kubernetes.io/hostname").Obj(),
+		},
+		"invalid node label": {
+			topology: testingutil.MakeTopology("default", "@block", "kubernetes.io/hostname").Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(levelsPath.Index(0).Child("nodeLabel"), "@block", ""),
+			},
+		},
+		"duplicated node label": {
+			topology: testingutil.MakeTopology("default", "kubernetes.io/hostname", "kubernetes.io/hostname").Obj(),
+			wantErr: field.ErrorList{
+				field.Duplicate(levelsPath.Index(1).Child("nodeLabel"), "kubernetes.io/hostname"),
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			gotErr := ValidateTopology(tc.topology)
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("ValidateTopology() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
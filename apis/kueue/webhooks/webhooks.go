@@ -18,9 +18,41 @@ package webhooks
 
 import ctrl "sigs.k8s.io/controller-runtime"
 
+type options struct {
+	validateNamespaceSelector bool
+	quotaSimulator            QuotaSimulator
+}
+
+// Option configures the webhooks.
+type Option func(*options)
+
+// WithNamespaceSelectorValidation indicates if the LocalQueue webhook should
+// reject the LocalQueues whose ClusterQueue doesn't select their namespace.
+func WithNamespaceSelectorValidation(f bool) Option {
+	return func(o *options) {
+		o.validateNamespaceSelector = f
+	}
+}
+
+// WithQuotaUpdateWarnings indicates that the ClusterQueue webhook should warn
+// about the admitted workloads that wouldn't fit in the updated quotas of a
+// ClusterQueue, as computed by the simulator.
+func WithQuotaUpdateWarnings(simulator QuotaSimulator) Option {
+	return func(o *options) {
+		o.quotaSimulator = simulator
+	}
+}
+
+var defaultOptions = options{}
+
 // Setup sets up the webhooks for core controllers. It returns the name of the
 // webhook that failed to create and an error, if any.
-func Setup(mgr ctrl.Manager) (string, error) {
+func Setup(mgr ctrl.Manager, opts ...Option) (string, error) {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if err := setupWebhookForWorkload(mgr); err != nil {
 		return "Workload", err
 	}
@@ -29,12 +61,24 @@ func Setup(mgr ctrl.Manager) (string, error) {
 		return "ResourceFlavor", err
 	}
 
-	if err := setupWebhookForClusterQueue(mgr); err != nil {
+	if err := setupWebhookForClusterQueue(mgr, options.quotaSimulator); err != nil {
 		return "ClusterQueue", err
 	}
 
-	if err := setupWebhookForLocalQueue(mgr); err != nil {
+	if err := setupWebhookForLocalQueue(mgr, options.validateNamespaceSelector); err != nil {
 		return "Queue", err
 	}
+
+	if err := setupWebhookForCohort(mgr); err != nil {
+		return "Cohort", err
+	}
+
+	if err := setupWebhookForTopology(mgr); err != nil {
+		return "Topology", err
+	}
+
+	if err := setupWebhookForAdmissionCheck(mgr); err != nil {
+		return "AdmissionCheck", err
+	}
 	return "", nil
 }
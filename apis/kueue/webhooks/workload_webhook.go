@@ -18,8 +18,10 @@ package webhooks
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -85,7 +87,7 @@ func setContainersDefaults(containers []corev1.Container) {
 	}
 }
 
-// +kubebuilder:webhook:path=/validate-kueue-x-k8s-io-v1alpha2-workload,mutating=false,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=workloads,verbs=create;update,versions=v1alpha2,name=vworkload.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-kueue-x-k8s-io-v1alpha2-workload,mutating=false,failurePolicy=fail,sideEffects=None,groups=kueue.x-k8s.io,resources=workloads;workloads/status,verbs=create;update,versions=v1alpha2,name=vworkload.kb.io,admissionReviewVersions=v1
 
 var _ webhook.CustomValidator = &WorkloadWebhook{}
 
@@ -116,10 +118,29 @@ func ValidateWorkload(obj *kueue.Workload) field.ErrorList {
 	specPath := field.NewPath("spec")
 	podSetsPath := specPath.Child("podSets")
 
+	variableCountPodSets := 0
 	for i, podSet := range obj.Spec.PodSets {
 		path := podSetsPath.Index(i)
 		allErrs = append(allErrs, validatePodSetName(podSet.Name, path.Child("name"))...)
+		if podSet.MinCount != nil {
+			variableCountPodSets++
+			if *podSet.MinCount <= 0 || *podSet.MinCount > podSet.Count {
+				allErrs = append(allErrs, field.Invalid(path.Child("minCount"), *podSet.MinCount, "should be positive and less or equal to count"))
+			}
+		}
+		if podSet.TopologyRequest != nil {
+			allErrs = append(allErrs, validateTopologyRequest(podSet.TopologyRequest, path.Child("topologyRequest"))...)
+		}
+		if podSet.FlavorAffinity != nil {
+			allErrs = append(allErrs, validateFlavorAffinity(podSet.FlavorAffinity, path.Child("flavorAffinity"))...)
+		}
+		allErrs = append(allErrs, validateContainersResources(podSet.Spec.InitContainers, path.Child("spec", "initContainers"))...)
+		allErrs = append(allErrs, validateContainersResources(podSet.Spec.Containers, path.Child("spec", "containers"))...)
+	}
+	if variableCountPodSets > 1 {
+		allErrs = append(allErrs, field.Invalid(podSetsPath, variableCountPodSets, "at most one podSet can use minCount"))
 	}
+	allErrs = append(allErrs, validatePodSetResizes(obj, specPath.Child("podSetResizes"))...)
 
 	if len(obj.Spec.PriorityClassName) > 0 {
 		msgs := validation.IsDNS1123Subdomain(obj.Spec.PriorityClassName)
@@ -131,6 +152,8 @@ func ValidateWorkload(obj *kueue.Workload) field.ErrorList {
 		if obj.Spec.Priority == nil {
 			allErrs = append(allErrs, field.Invalid(specPath.Child("priority"), obj.Spec.Priority, "priority should not be nil when priorityClassName is set"))
 		}
+	} else if len(obj.Spec.PriorityClassSource) > 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("priorityClassSource"), obj.Spec.PriorityClassSource, "priorityClassSource should be empty when priorityClassName is not set"))
 	}
 
 	if len(obj.Spec.QueueName) > 0 {
@@ -142,10 +165,73 @@ func ValidateWorkload(obj *kueue.Workload) field.ErrorList {
 	}
 
 	allErrs = append(allErrs, metav1validation.ValidateConditions(obj.Status.Conditions, field.NewPath("status", "conditions"))...)
+	allErrs = append(allErrs, validateReclaimablePods(obj, field.NewPath("status", "reclaimablePods"))...)
 
 	return allErrs
 }
 
+// validateContainersResources checks that the containers don't request more of
+// a resource than their limit, as the pods would be rejected.
+func validateContainersResources(containers []corev1.Container, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, c := range containers {
+		for name, request := range c.Resources.Requests {
+			if limit, found := c.Resources.Limits[name]; found && request.Cmp(limit) > 0 {
+				allErrs = append(allErrs, field.Invalid(path.Index(i).Child("resources", "requests").Key(string(name)), request.String(), fmt.Sprintf("must be less than or equal to %s limit of %s", name, limit.String())))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validatePodSetResizes(obj *kueue.Workload, path *field.Path) field.ErrorList {
+	podSetNames := sets.New[string]()
+	for _, ps := range obj.Spec.PodSets {
+		podSetNames.Insert(ps.Name)
+	}
+	var allErrs field.ErrorList
+	for i, r := range obj.Spec.PodSetResizes {
+		if !podSetNames.Has(r.Name) {
+			allErrs = append(allErrs, field.NotFound(path.Index(i).Child("name"), r.Name))
+		}
+		if r.Count <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("count"), r.Count, "should be positive"))
+		}
+	}
+	return allErrs
+}
+
+// resizeCounts returns the counts requested in the podSetResizes of the
+// workload, keyed by podSet name.
+func resizeCounts(obj *kueue.Workload) map[string]int32 {
+	counts := make(map[string]int32, len(obj.Spec.PodSetResizes))
+	for _, r := range obj.Spec.PodSetResizes {
+		counts[r.Name] = r.Count
+	}
+	return counts
+}
+
+func validateReclaimablePods(obj *kueue.Workload, path *field.Path) field.ErrorList {
+	resizes := resizeCounts(obj)
+	podSetCounts := make(map[string]int32, len(obj.Spec.PodSets))
+	for _, ps := range obj.Spec.PodSets {
+		podSetCounts[ps.Name] = ps.Count
+		if resizes[ps.Name] > ps.Count {
+			podSetCounts[ps.Name] = resizes[ps.Name]
+		}
+	}
+	var allErrs field.ErrorList
+	for i, rp := range obj.Status.ReclaimablePods {
+		count, found := podSetCounts[rp.Name]
+		if !found {
+			allErrs = append(allErrs, field.NotFound(path.Index(i).Child("name"), rp.Name))
+		} else if rp.Count < 0 || rp.Count > count {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("count"), rp.Count, fmt.Sprintf("should be between 0 and the podSet count, %d", count)))
+		}
+	}
+	return allErrs
+}
+
 func validatePodSetName(name string, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	// Apply the same validation as container names.
@@ -155,43 +241,146 @@ func validatePodSetName(name string, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateTopologyRequest(req *kueue.PodSetTopologyRequest, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if (req.Required == nil) == (req.Preferred == nil) {
+		allErrs = append(allErrs, field.Invalid(path, req, "exactly one of required or preferred must be set"))
+	}
+	if req.Required != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(*req.Required, path.Child("required"))...)
+	}
+	if req.Preferred != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(*req.Preferred, path.Child("preferred"))...)
+	}
+	return allErrs
+}
+
+func validateFlavorAffinity(affinity *kueue.PodSetFlavorAffinity, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, name := range affinity.Required {
+		allErrs = append(allErrs, validateNameReference(name, path.Child("required").Index(i))...)
+	}
+	for i, name := range affinity.Preferred {
+		allErrs = append(allErrs, validateNameReference(name, path.Child("preferred").Index(i))...)
+	}
+	return allErrs
+}
+
 func validateAdmission(obj *kueue.Workload, path *field.Path) field.ErrorList {
 	admission := obj.Spec.Admission
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, validateNameReference(string(admission.ClusterQueue), path.Child("clusterQueue"))...)
 
-	names := sets.NewString()
-	for _, ps := range obj.Spec.PodSets {
-		names.Insert(ps.Name)
+	podSets := make(map[string]*kueue.PodSet, len(obj.Spec.PodSets))
+	for i := range obj.Spec.PodSets {
+		podSets[obj.Spec.PodSets[i].Name] = &obj.Spec.PodSets[i]
 	}
 
+	resizes := resizeCounts(obj)
 	for i, ps := range obj.Spec.Admission.PodSetFlavors {
-		if !names.Has(ps.Name) {
+		podSet, found := podSets[ps.Name]
+		if !found {
 			allErrs = append(allErrs, field.NotFound(path.Child("podSetFlavors").Index(i).Child("name"), ps.Name))
+			continue
+		}
+		if ps.Count != nil {
+			minCount := podSet.Count
+			if podSet.MinCount != nil {
+				minCount = *podSet.MinCount
+			}
+			resizeCount, resized := resizes[ps.Name]
+			if (*ps.Count < minCount || *ps.Count > podSet.Count) && (!resized || *ps.Count != resizeCount) {
+				allErrs = append(allErrs, field.Invalid(path.Child("podSetFlavors").Index(i).Child("count"), *ps.Count, "should be between the podSet minCount and count, or equal to the podSetResizes count"))
+			}
 		}
 	}
 
 	return allErrs
 }
 
+// workloadMutableFields is appended to the errors for the changes of the
+// immutable fields of a workload, to tell which changes are allowed instead.
+const workloadMutableFields = "only priority, priorityClassName, priorityClassSource, active, podSetResizes and, while the workload is not admitted, queueName can be changed"
+
+// ValidateWorkloadUpdate validates the changes of a workload. The priority and
+// active fields can change at any time, as well as the reclaimablePods in the
+// status, as long as they don't decrease while the workload is admitted.
+// The podSets and reservation can't change, and the queueName can't change
+// while the workload is admitted.
 func ValidateWorkloadUpdate(newObj, oldObj *kueue.Workload) field.ErrorList {
 	var allErrs field.ErrorList
 	specPath := field.NewPath("spec")
 	allErrs = append(allErrs, ValidateWorkload(newObj)...)
-	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newObj.Spec.PodSets, oldObj.Spec.PodSets, specPath.Child("podSets"))...)
+	allErrs = append(allErrs, validatePodSetsUpdate(newObj.Spec.PodSets, oldObj.Spec.PodSets, specPath.Child("podSets"))...)
+	if !equality.Semantic.DeepEqual(newObj.Spec.Reservation, oldObj.Spec.Reservation) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("reservation"), "reservation cannot be changed; "+workloadMutableFields))
+	}
+	if newObj.Spec.Admission != nil && oldObj.Spec.Admission != nil && newObj.Spec.QueueName != oldObj.Spec.QueueName {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("queueName"), "queueName cannot be changed while the workload is admitted"))
+	}
+	allErrs = append(allErrs, validateAdmissionUpdate(newObj, oldObj, specPath.Child("admission"))...)
 	if newObj.Spec.Admission != nil && oldObj.Spec.Admission != nil {
-		allErrs = append(allErrs, apivalidation.ValidateImmutableField(newObj.Spec.QueueName, oldObj.Spec.QueueName, specPath.Child("queueName"))...)
+		allErrs = append(allErrs, validateReclaimablePodsUpdate(newObj.Status.ReclaimablePods, oldObj.Status.ReclaimablePods, field.NewPath("status", "reclaimablePods"))...)
 	}
-	allErrs = append(allErrs, validateAdmissionUpdate(newObj.Spec.Admission, oldObj.Spec.Admission, specPath.Child("admission"))...)
 
 	return allErrs
 }
 
+// validatePodSetsUpdate validates that the podSets don't change, reporting the
+// podSets that changed rather than the whole list.
+func validatePodSetsUpdate(new, old []kueue.PodSet, path *field.Path) field.ErrorList {
+	if len(new) != len(old) {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("the number of podSets cannot change from %d to %d; %s", len(old), len(new), workloadMutableFields))}
+	}
+	var allErrs field.ErrorList
+	for i := range new {
+		psPath := path.Index(i)
+		switch {
+		case new[i].Name != old[i].Name:
+			allErrs = append(allErrs, field.Forbidden(psPath.Child("name"), "podSet names cannot be changed; "+workloadMutableFields))
+		case new[i].Count != old[i].Count:
+			allErrs = append(allErrs, field.Forbidden(psPath.Child("count"), "podSet counts cannot be changed, use podSetResizes to resize an admitted workload; "+workloadMutableFields))
+		case !equality.Semantic.DeepEqual(new[i], old[i]):
+			allErrs = append(allErrs, field.Forbidden(psPath, "podSets cannot be changed; "+workloadMutableFields))
+		}
+	}
+	return allErrs
+}
+
+// validateReclaimablePodsUpdate validates that the reclaimable pods of each
+// podSet don't decrease while the workload is admitted.
+func validateReclaimablePodsUpdate(new, old []kueue.ReclaimablePod, path *field.Path) field.ErrorList {
+	newCounts := make(map[string]int32, len(new))
+	for _, rp := range new {
+		newCounts[rp.Name] = rp.Count
+	}
+	var allErrs field.ErrorList
+	for _, rp := range old {
+		if newCount := newCounts[rp.Name]; newCount < rp.Count {
+			allErrs = append(allErrs, field.Invalid(path.Key(rp.Name).Child("count"), newCount, fmt.Sprintf("cannot be less than %d while the workload is admitted", rp.Count)))
+		}
+	}
+	return allErrs
+}
+
 // validateAdmissionUpdate validates that admission can be set or unset, but the
-// fields within can't change.
-func validateAdmissionUpdate(new, old *kueue.Admission, path *field.Path) field.ErrorList {
+// fields within can't change, except for the counts of the podSetFlavors, which
+// can change to the counts requested in podSetResizes.
+func validateAdmissionUpdate(newObj, oldObj *kueue.Workload, path *field.Path) field.ErrorList {
+	new, old := newObj.Spec.Admission, oldObj.Spec.Admission
 	if old == nil || new == nil {
 		return nil
 	}
+	resizes := resizeCounts(newObj)
+	old = old.DeepCopy()
+	for i := range old.PodSetFlavors {
+		ps := &old.PodSetFlavors[i]
+		if i >= len(new.PodSetFlavors) || new.PodSetFlavors[i].Name != ps.Name || new.PodSetFlavors[i].Count == nil {
+			continue
+		}
+		if count, found := resizes[ps.Name]; found && *new.PodSetFlavors[i].Count == count {
+			ps.Count = new.PodSetFlavors[i].Count
+		}
+	}
 	return apivalidation.ValidateImmutableField(new, old, path)
 }
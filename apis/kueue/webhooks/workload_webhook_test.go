@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/util/pointer"
 	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
 )
 
@@ -191,6 +192,7 @@ func TestWorkloadWebhookDefault(t *testing.T) {
 func TestValidateWorkload(t *testing.T) {
 	specField := field.NewPath("spec")
 	podSetsField := specField.Child("podSets")
+	statusField := field.NewPath("status")
 	testCases := map[string]struct {
 		workload *kueue.Workload
 		wantErr  field.ErrorList
@@ -237,6 +239,150 @@ func TestValidateWorkload(t *testing.T) {
 				field.Invalid(specField.Child("priority"), nil, ""),
 			},
 		},
+		"should have priorityClassName once priorityClassSource is set": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PriorityClassSource(kueue.WorkloadPriorityClassSource).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specField.Child("priorityClassSource"), nil, ""),
+			},
+		},
+		"should pass validation with a WorkloadPriorityClass": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PriorityClass("low").
+				PriorityClassSource(kueue.WorkloadPriorityClassSource).
+				Priority(10).
+				Obj(),
+		},
+		"should have minCount less or equal to count": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:     "workers",
+					Count:    2,
+					MinCount: pointer.Int32(3),
+				},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsField.Index(0).Child("minCount"), nil, ""),
+			},
+		},
+		"should have at most one podSet with minCount": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:     "driver",
+					Count:    2,
+					MinCount: pointer.Int32(1),
+				},
+				{
+					Name:     "workers",
+					Count:    4,
+					MinCount: pointer.Int32(2),
+				},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsField, nil, ""),
+			},
+		},
+		"should have admission count between minCount and count": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:     "main",
+					Count:    4,
+					MinCount: pointer.Int32(2),
+				},
+			}).
+				Admit(testingutil.MakeAdmission("cluster-queue").Count(1).Obj()).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(specField.Child("admission", "podSetFlavors").Index(0).Child("count"), nil, ""),
+			},
+		},
+		"admission count can be the podSetResizes count": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Admit(testingutil.MakeAdmission("cluster-queue").Count(3).Obj()).
+				PodSetResizes(kueue.PodSetResize{Name: "main", Count: 3}).
+				Obj(),
+		},
+		"should have podSetResizes for existing podSets": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				PodSetResizes(kueue.PodSetResize{Name: "workers", Count: 0}).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.NotFound(specField.Child("podSetResizes").Index(0).Child("name"), nil),
+				field.Invalid(specField.Child("podSetResizes").Index(0).Child("count"), nil, ""),
+			},
+		},
+		"should have requests not greater than limits": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Request(corev1.ResourceCPU, "2").
+				Limit(corev1.ResourceCPU, "1").
+				Request(corev1.ResourceMemory, "1Gi").
+				Limit(corev1.ResourceMemory, "2Gi").
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsField.Index(0).Child("spec", "containers").Index(0).Child("resources", "requests").Key("cpu"), nil, ""),
+			},
+		},
+		"should have exactly one of required or preferred topology": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:  "main",
+					Count: 1,
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Required:  pointer.String("cloud.provider.com/block"),
+						Preferred: pointer.String("cloud.provider.com/rack"),
+					},
+				},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsField.Index(0).Child("topologyRequest"), nil, ""),
+			},
+		},
+		"should have a valid topology level": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:  "main",
+					Count: 1,
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Preferred: pointer.String("@rack"),
+					},
+				},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsField.Index(0).Child("topologyRequest", "preferred"), nil, ""),
+			},
+		},
+		"should have valid flavor names in the flavor affinity": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:  "main",
+					Count: 1,
+					FlavorAffinity: &kueue.PodSetFlavorAffinity{
+						Required:  []string{"a100"},
+						Preferred: []string{"on-demand", "Spot"},
+					},
+				},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(podSetsField.Index(0).Child("flavorAffinity", "preferred").Index(1), nil, ""),
+			},
+		},
+		"should have reclaimable pods for existing podSets": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				ReclaimablePods(kueue.ReclaimablePod{Name: "workers", Count: 1}).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.NotFound(statusField.Child("reclaimablePods").Index(0).Child("name"), nil),
+			},
+		},
+		"should have reclaimable pods not greater than count": {
+			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				ReclaimablePods(kueue.ReclaimablePod{Name: "main", Count: 2}).
+				Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(statusField.Child("reclaimablePods").Index(0).Child("count"), nil, ""),
+			},
+		},
 		"should have a valid queueName": {
 			workload: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
 				Queue("@invalid").
@@ -314,7 +460,7 @@ func TestValidateWorkloadUpdate(t *testing.T) {
 				},
 			}).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(field.NewPath("spec").Child("podSets"), nil, ""),
+				field.Forbidden(field.NewPath("spec", "podSets").Index(0).Child("count"), ""),
 			},
 		},
 		"podSets should not be updated: podSpec": {
@@ -336,7 +482,44 @@ func TestValidateWorkloadUpdate(t *testing.T) {
 				},
 			}).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(field.NewPath("spec").Child("podSets"), nil, ""),
+				field.Forbidden(field.NewPath("spec", "podSets").Index(0), ""),
+			},
+		},
+		"podSets should not be updated: name": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:  "driver",
+					Count: 1,
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "c", Resources: corev1.ResourceRequirements{Requests: make(corev1.ResourceList)}}},
+					},
+				},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "podSets").Index(0).Child("name"), ""),
+			},
+		},
+		"podSets should not be updated: added podSet": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).PodSets([]kueue.PodSet{
+				{
+					Name:  "main",
+					Count: 1,
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "c", Resources: corev1.ResourceRequirements{Requests: make(corev1.ResourceList)}}},
+					},
+				},
+				{
+					Name:  "workers",
+					Count: 1,
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "c", Resources: corev1.ResourceRequirements{Requests: make(corev1.ResourceList)}}},
+					},
+				},
+			}).Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "podSets"), ""),
 			},
 		},
 		"queueName can be updated when not admitted": {
@@ -355,7 +538,7 @@ func TestValidateWorkloadUpdate(t *testing.T) {
 			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q2").
 				Admit(testingutil.MakeAdmission("cq").Obj()).Obj(),
 			wantErr: field.ErrorList{
-				field.Invalid(field.NewPath("spec").Child("queueName"), nil, ""),
+				field.Forbidden(field.NewPath("spec").Child("queueName"), ""),
 			},
 		},
 		"queueName can be updated when admission is reset": {
@@ -363,6 +546,33 @@ func TestValidateWorkloadUpdate(t *testing.T) {
 				Admit(testingutil.MakeAdmission("cq").Obj()).Obj(),
 			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q2").Obj(),
 		},
+		"priority can be updated when not admitted": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").
+				PriorityClass("low").PriorityClassSource(kueue.WorkloadPriorityClassSource).Priority(10).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").
+				PriorityClass("high").PriorityClassSource(kueue.WorkloadPriorityClassSource).Priority(100).Obj(),
+		},
+		"priority can be updated once admitted": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").
+				PriorityClass("low").PriorityClassSource(kueue.WorkloadPriorityClassSource).Priority(10).
+				Admit(testingutil.MakeAdmission("cq").Obj()).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").
+				PriorityClass("high").PriorityClassSource(kueue.WorkloadPriorityClassSource).Priority(100).
+				Admit(testingutil.MakeAdmission("cq").Obj()).Obj(),
+		},
+		"active can be updated once admitted": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").
+				Admit(testingutil.MakeAdmission("cq").Obj()).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").
+				Active(false).Admit(testingutil.MakeAdmission("cq").Obj()).Obj(),
+		},
+		"reservation should not be updated": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").Reservation(pointer.Int32(60)).Obj(),
+			after:  testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Queue("q").Reservation(pointer.Int32(120)).Obj(),
+			wantErr: field.ErrorList{
+				field.Forbidden(field.NewPath("spec").Child("reservation"), ""),
+			},
+		},
 		"admission can be set": {
 			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Obj(),
 			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Admit(
@@ -377,6 +587,43 @@ func TestValidateWorkloadUpdate(t *testing.T) {
 			after:   testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Obj(),
 			wantErr: nil,
 		},
+		"reclaimable pods can increase while admitted": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Request(corev1.ResourceCPU, "1").
+				Admit(testingutil.MakeAdmission("cluster-queue").Obj()).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Request(corev1.ResourceCPU, "1").
+				Admit(testingutil.MakeAdmission("cluster-queue").Obj()).
+				ReclaimablePods(kueue.ReclaimablePod{Name: "main", Count: 1}).Obj(),
+		},
+		"reclaimable pods should not decrease while admitted": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Admit(testingutil.MakeAdmission("cluster-queue").Obj()).
+				ReclaimablePods(kueue.ReclaimablePod{Name: "main", Count: 1}).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Admit(testingutil.MakeAdmission("cluster-queue").Obj()).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("status", "reclaimablePods").Key("main").Child("count"), nil, ""),
+			},
+		},
+		"admission count can change to the podSetResizes count": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Admit(testingutil.MakeAdmission("cluster-queue").Count(1).Obj()).
+				PodSetResizes(kueue.PodSetResize{Name: "main", Count: 3}).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Admit(testingutil.MakeAdmission("cluster-queue").Count(3).Obj()).
+				PodSetResizes(kueue.PodSetResize{Name: "main", Count: 3}).Obj(),
+		},
+		"admission count should not change to other than the podSetResizes count": {
+			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Admit(testingutil.MakeAdmission("cluster-queue").Count(1).Obj()).
+				PodSetResizes(kueue.PodSetResize{Name: "main", Count: 3}).Obj(),
+			after: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).
+				Admit(testingutil.MakeAdmission("cluster-queue").Count(2).Obj()).
+				PodSetResizes(kueue.PodSetResize{Name: "main", Count: 3}).Obj(),
+			wantErr: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "admission", "podSetFlavors").Index(0).Child("count"), nil, ""),
+				field.Invalid(field.NewPath("spec").Child("admission"), nil, ""),
+			},
+		},
 		"admission should not be updated once set": {
 			before: testingutil.MakeWorkload(testWorkloadName, testWorkloadNamespace).Admit(
 				testingutil.MakeAdmission("cluster-queue").Obj(),
@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const DefaultPendingWorkloadsLimit = 1000
+
+func addConversionFuncs(scheme *runtime.Scheme) error {
+	return scheme.AddConversionFunc((*url.Values)(nil), (*PendingWorkloadOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_url_Values_To_v1alpha1_PendingWorkloadOptions(a.(*url.Values), b.(*PendingWorkloadOptions), scope)
+	})
+}
+
+// Convert_url_Values_To_v1alpha1_PendingWorkloadOptions decodes the offset
+// and limit query parameters, defaulting the limit when it's not set.
+func Convert_url_Values_To_v1alpha1_PendingWorkloadOptions(in *url.Values, out *PendingWorkloadOptions, _ conversion.Scope) error {
+	out.Offset = 0
+	out.Limit = DefaultPendingWorkloadsLimit
+	if v := in.Get("offset"); v != "" {
+		offset, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		out.Offset = offset
+	}
+	if v := in.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		out.Limit = limit
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertURLValuesToPendingWorkloadOptions(t *testing.T) {
+	testCases := map[string]struct {
+		values  url.Values
+		want    PendingWorkloadOptions
+		wantErr bool
+	}{
+		"defaults": {
+			values: url.Values{},
+			want:   PendingWorkloadOptions{Limit: DefaultPendingWorkloadsLimit},
+		},
+		"offset and limit": {
+			values: url.Values{"offset": {"5"}, "limit": {"10"}},
+			want:   PendingWorkloadOptions{Offset: 5, Limit: 10},
+		},
+		"invalid limit": {
+			values:  url.Values{"limit": {"ten"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var got PendingWorkloadOptions
+			err := Convert_url_Values_To_v1alpha1_PendingWorkloadOptions(&tc.values, &got, nil)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected options (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
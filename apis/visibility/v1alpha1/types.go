@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PendingWorkload is a pending workload and its positions in the queues.
+type PendingWorkload struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Priority is the priority of the workload.
+	Priority int32 `json:"priority"`
+
+	// LocalQueueName is the name of the LocalQueue of the workload.
+	LocalQueueName string `json:"localQueueName"`
+
+	// PositionInClusterQueue is the position of the workload in its
+	// ClusterQueue, starting from 0 for the next workload to be considered
+	// for admission.
+	PositionInClusterQueue int32 `json:"positionInClusterQueue"`
+
+	// PositionInLocalQueue is the position of the workload among the
+	// workloads of its LocalQueue, starting from 0.
+	PositionInLocalQueue int32 `json:"positionInLocalQueue"`
+}
+
+// +kubebuilder:object:root=true
+
+// PendingWorkloadsSummary contains a page of the pending workloads of a
+// queue, in the order in which they are considered for admission.
+type PendingWorkloadsSummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Items []PendingWorkload `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// PendingWorkloadOptions are the query parameters of the pendingworkloads
+// subresources.
+type PendingWorkloadOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Offset is the position of the first pending workload to return.
+	// Defaults to 0.
+	Offset int64 `json:"offset"`
+
+	// Limit is the maximum number of pending workloads to return.
+	// Defaults to 1000.
+	Limit int64 `json:"limit,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterQueue exposes the pending workloads of a ClusterQueue through its
+// pendingworkloads subresource.
+type ClusterQueue struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Summary PendingWorkloadsSummary `json:"pendingWorkloadsSummary"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterQueueList contains a list of ClusterQueue.
+type ClusterQueueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterQueue `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// LocalQueue exposes the pending workloads of a LocalQueue through its
+// pendingworkloads subresource.
+type LocalQueue struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Summary PendingWorkloadsSummary `json:"pendingWorkloadsSummary"`
+}
+
+// +kubebuilder:object:root=true
+
+// LocalQueueList contains a list of LocalQueue.
+type LocalQueueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []LocalQueue `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// Workload exposes why a pending workload is not admitted through its
+// explanation subresource.
+type Workload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Explanation WorkloadExplanation `json:"explanation"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkloadList contains a list of Workload.
+type WorkloadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Workload `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkloadExplanation explains whether a pending workload fits in the quota
+// of its ClusterQueue. It is computed on request against the current usage of
+// the quota, the same way as in a scheduling cycle, but without admitting the
+// workload or preempting other workloads.
+type WorkloadExplanation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// ClusterQueue is the ClusterQueue in which the workload is pending.
+	// Empty if the LocalQueue of the workload doesn't exist.
+	ClusterQueue string `json:"clusterQueue,omitempty"`
+
+	// Mode is Fit if the workload fits in the unused quota, Preempt if it
+	// needs quota used by other workloads, or NoFit.
+	Mode string `json:"mode"`
+
+	// Message explains why the workload can't be admitted. Empty if the
+	// workload fits.
+	Message string `json:"message,omitempty"`
+
+	// PodSets are the flavors that the pod sets would be assigned and the
+	// reasons why the other flavors don't fit.
+	PodSets []PodSetExplanation `json:"podSets,omitempty"`
+
+	// PreemptionTargets are the workloads that would be preempted to admit
+	// the workload, when its mode is Preempt.
+	PreemptionTargets []PreemptionTarget `json:"preemptionTargets,omitempty"`
+}
+
+// PodSetExplanation holds the flavors that a pod set would be assigned and
+// the reasons why the other flavors don't fit.
+type PodSetExplanation struct {
+	// Name is the name of the pod set.
+	Name string `json:"name"`
+
+	// Count is the number of pods taken into account, lower than the count of
+	// the pod set if the workload would be partially admitted.
+	Count int32 `json:"count"`
+
+	// Flavors are the flavors that the resources of the pod set would be
+	// assigned.
+	Flavors []FlavorAssignment `json:"flavors,omitempty"`
+
+	// Reasons are the reasons why the other flavors don't fit, per flavor and
+	// resource.
+	Reasons []FlavorReason `json:"reasons,omitempty"`
+}
+
+// FlavorAssignment is the flavor that a resource would be assigned.
+type FlavorAssignment struct {
+	// Resource is the name of the resource.
+	Resource corev1.ResourceName `json:"resource"`
+
+	// Flavor is the name of the ResourceFlavor.
+	Flavor string `json:"flavor"`
+
+	// Mode is Fit if the flavor has enough unused quota, or Preempt if it
+	// needs quota used by other workloads.
+	Mode string `json:"mode"`
+}
+
+// FlavorReason is a reason why a flavor doesn't fit a resource.
+type FlavorReason struct {
+	// Type is the machine-readable reason.
+	Type string `json:"type"`
+
+	// Resource is the name of the resource, if the reason is specific to one.
+	Resource corev1.ResourceName `json:"resource,omitempty"`
+
+	// Flavor is the name of the ResourceFlavor, if the reason is specific to
+	// one.
+	Flavor string `json:"flavor,omitempty"`
+
+	// Missing is the quantity of the resource that doesn't fit in the unused
+	// quota.
+	Missing *resource.Quantity `json:"missing,omitempty"`
+
+	// Message is a human readable message explaining the reason.
+	Message string `json:"message"`
+}
+
+// PreemptionTarget is a workload that would be preempted.
+type PreemptionTarget struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// ClusterQueue is the ClusterQueue that admitted the workload.
+	ClusterQueue string `json:"clusterQueue"`
+
+	// Priority is the priority of the workload.
+	Priority int32 `json:"priority"`
+}
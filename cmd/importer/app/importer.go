@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package app implements the importer, which adopts the Jobs and Pods that are
+// already running in a cluster, without Kueue, by creating admitted Workloads
+// for them in a ClusterQueue, so that Kueue can be installed in a live cluster
+// without evicting them.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/workload/job"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+	"sigs.k8s.io/kueue/pkg/controller/workload/pod"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// Options configure the import.
+type Options struct {
+	// Namespaces are the namespaces to import the Jobs and Pods from.
+	Namespaces []string
+	// QueueName is the name of the LocalQueue, which must exist in each of the
+	// namespaces, to add the imported Jobs and Pods to. Their Workloads are
+	// admitted by its ClusterQueue.
+	QueueName string
+	// Flavor is the ResourceFlavor that the Workloads are admitted with, for
+	// all their resources. If empty, the first flavor of each resource in the
+	// ClusterQueue is used.
+	Flavor string
+	// ImportPods enables importing the running Pods that are not owned by a
+	// Job. Only enable it if the pod integration is enabled in Kueue.
+	ImportPods bool
+	// DryRun only logs the Jobs and Pods that would be imported.
+	DryRun bool
+}
+
+// Result holds the number of imported Jobs and Pods.
+type Result struct {
+	Jobs int
+	Pods int
+}
+
+// Importer imports the running Jobs and Pods into Kueue.
+type Importer struct {
+	client client.Client
+	scheme *runtime.Scheme
+	opts   Options
+}
+
+func NewImporter(c client.Client, scheme *runtime.Scheme, opts Options) *Importer {
+	return &Importer{
+		client: c,
+		scheme: scheme,
+		opts:   opts,
+	}
+}
+
+// Run imports the running Jobs, and Pods if enabled, of the namespaces. The
+// objects that can't be imported are skipped, and the errors are returned
+// once all the namespaces are processed.
+func (i *Importer) Run(ctx context.Context) (Result, error) {
+	var result Result
+	var errs []error
+	for _, ns := range i.opts.Namespaces {
+		nsResult, err := i.importNamespace(ctx, ns)
+		result.Jobs += nsResult.Jobs
+		result.Pods += nsResult.Pods
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return result, utilerrors.NewAggregate(errs)
+}
+
+func (i *Importer) importNamespace(ctx context.Context, ns string) (Result, error) {
+	var result Result
+	log := ctrl.LoggerFrom(ctx).WithValues("namespace", ns)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	var lq kueue.LocalQueue
+	if err := i.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: i.opts.QueueName}, &lq); err != nil {
+		return result, fmt.Errorf("getting LocalQueue %s/%s: %w", ns, i.opts.QueueName, err)
+	}
+	var cq kueue.ClusterQueue
+	if err := i.client.Get(ctx, types.NamespacedName{Name: string(lq.Spec.ClusterQueue)}, &cq); err != nil {
+		return result, fmt.Errorf("getting ClusterQueue %s: %w", lq.Spec.ClusterQueue, err)
+	}
+
+	var errs []error
+	var jobs batchv1.JobList
+	if err := i.client.List(ctx, &jobs, client.InNamespace(ns)); err != nil {
+		return result, fmt.Errorf("listing the Jobs in namespace %s: %w", ns, err)
+	}
+	for idx := range jobs.Items {
+		j := (*job.Job)(&jobs.Items[idx])
+		if !runningJobWithoutQueue(j) {
+			continue
+		}
+		if err := i.importJob(ctx, &cq, j); err != nil {
+			errs = append(errs, fmt.Errorf("importing Job %s/%s: %w", ns, j.Name, err))
+			continue
+		}
+		result.Jobs++
+	}
+
+	if i.opts.ImportPods {
+		var pods corev1.PodList
+		if err := i.client.List(ctx, &pods, client.InNamespace(ns)); err != nil {
+			return result, fmt.Errorf("listing the Pods in namespace %s: %w", ns, err)
+		}
+		for idx := range pods.Items {
+			p := &pods.Items[idx]
+			if !runningPodWithoutQueue(p) {
+				continue
+			}
+			if err := i.importPod(ctx, &cq, p); err != nil {
+				errs = append(errs, fmt.Errorf("importing Pod %s/%s: %w", ns, p.Name, err))
+				continue
+			}
+			result.Pods++
+		}
+	}
+	return result, utilerrors.NewAggregate(errs)
+}
+
+// runningJobWithoutQueue returns whether the job is running and not managed
+// by Kueue, neither through its own Workload nor through a parent Workload.
+func runningJobWithoutQueue(j *job.Job) bool {
+	if jobframework.QueueName(j) != "" || j.ParentWorkload() != "" || j.IsSuspended() {
+		return false
+	}
+	_, finished := j.Finished()
+	return !finished
+}
+
+// runningPodWithoutQueue returns whether the pod is running and not managed by
+// Kueue. The pods owned by a Job are imported through the Job, and the pods of
+// groups aren't supported.
+func runningPodWithoutQueue(p *corev1.Pod) bool {
+	if p.Annotations[constants.QueueAnnotation] != "" || p.Labels[pod.ManagedLabel] == "true" || p.Labels[pod.GroupNameLabel] != "" {
+		return false
+	}
+	if owner := metav1.GetControllerOf(p); owner != nil && owner.APIVersion == "batch/v1" && owner.Kind == "Job" {
+		return false
+	}
+	return p.Spec.NodeName != "" && p.Status.Phase != corev1.PodSucceeded && p.Status.Phase != corev1.PodFailed
+}
+
+// importJob creates the admitted Workload of the job and then adds the queue
+// name to the job, so that the job reconciler finds the job running with an
+// admitted Workload and leaves it running.
+func (i *Importer) importJob(ctx context.Context, cq *kueue.ClusterQueue, j *job.Job) error {
+	log := ctrl.LoggerFrom(ctx)
+	wl, err := jobframework.ConstructWorkload(ctx, i.client, j, i.scheme)
+	if err != nil {
+		return err
+	}
+	if err := i.admit(cq, wl); err != nil {
+		return err
+	}
+	if i.opts.DryRun {
+		log.Info("Would import Job", "job", klog.KObj(j.Object()), "workload", klog.KObj(wl))
+		return nil
+	}
+	if err := i.client.Create(ctx, wl); err != nil {
+		return fmt.Errorf("creating Workload: %w", err)
+	}
+	obj := (*batchv1.Job)(j)
+	patch := client.MergeFrom(obj.DeepCopy())
+	setImportedAnnotations(obj, i.opts.QueueName)
+	if err := i.client.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("adding the queue name: %w", err)
+	}
+	log.Info("Imported Job", "job", klog.KObj(obj), "workload", klog.KObj(wl))
+	return nil
+}
+
+// importPod creates the admitted Workload of the pod and then labels the pod
+// as managed by Kueue, with the queue name.
+func (i *Importer) importPod(ctx context.Context, cq *kueue.ClusterQueue, p *corev1.Pod) error {
+	log := ctrl.LoggerFrom(ctx)
+	wl, err := pod.ConstructWorkloadFor(ctx, i.client, p, []corev1.Pod{*p}, i.scheme)
+	if err != nil {
+		return err
+	}
+	if err := i.admit(cq, wl); err != nil {
+		return err
+	}
+	if i.opts.DryRun {
+		log.Info("Would import Pod", "pod", klog.KObj(p), "workload", klog.KObj(wl))
+		return nil
+	}
+	if err := i.client.Create(ctx, wl); err != nil {
+		return fmt.Errorf("creating Workload: %w", err)
+	}
+	patch := client.MergeFrom(p.DeepCopy())
+	setImportedAnnotations(p, i.opts.QueueName)
+	if p.Labels == nil {
+		p.Labels = make(map[string]string, 1)
+	}
+	p.Labels[pod.ManagedLabel] = "true"
+	if err := i.client.Patch(ctx, p, patch); err != nil {
+		return fmt.Errorf("adding the queue name: %w", err)
+	}
+	log.Info("Imported Pod", "pod", klog.KObj(p), "workload", klog.KObj(wl))
+	return nil
+}
+
+// admit sets the queue name of the workload and its admission by the
+// ClusterQueue, assigning the flavor of the options, or the first flavor of
+// each resource in the ClusterQueue, to all the requested resources. The
+// workload doesn't go through the admission checks of the ClusterQueue, since
+// it's already running.
+func (i *Importer) admit(cq *kueue.ClusterQueue, wl *kueue.Workload) error {
+	wl.Spec.QueueName = i.opts.QueueName
+	admission := &kueue.Admission{ClusterQueue: kueue.ClusterQueueReference(cq.Name)}
+	for _, ps := range workload.NewInfo(wl).TotalRequests {
+		flavors := make(map[corev1.ResourceName]string, len(ps.Requests))
+		for r := range ps.Requests {
+			f, err := flavorFor(cq, r, i.opts.Flavor)
+			if err != nil {
+				return err
+			}
+			flavors[r] = f
+		}
+		admission.PodSetFlavors = append(admission.PodSetFlavors, kueue.PodSetFlavors{
+			Name:    ps.Name,
+			Flavors: flavors,
+		})
+	}
+	wl.Spec.Admission = admission
+	return nil
+}
+
+// flavorFor returns the flavor of the resource in the ClusterQueue: the given
+// flavor, if not empty, or the first flavor of the resource.
+func flavorFor(cq *kueue.ClusterQueue, name corev1.ResourceName, flavor string) (string, error) {
+	for _, rg := range cq.Spec.EffectiveResourceGroups() {
+		if !sets.New(rg.CoveredResources...).Has(name) {
+			continue
+		}
+		if flavor == "" {
+			return string(rg.Flavors[0].Name), nil
+		}
+		for _, f := range rg.Flavors {
+			if string(f.Name) == flavor {
+				return flavor, nil
+			}
+		}
+		return "", fmt.Errorf("ClusterQueue %s doesn't have flavor %s for resource %s", cq.Name, flavor, name)
+	}
+	return "", fmt.Errorf("ClusterQueue %s doesn't have quota for resource %s", cq.Name, name)
+}
+
+func setImportedAnnotations(obj client.Object, queueName string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 2)
+	}
+	annotations[constants.QueueAnnotation] = queueName
+	annotations[constants.ImportedAnnotation] = "true"
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/workload/pod"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestImport(t *testing.T) {
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("on-demand", "10").Obj()).
+			Flavor(utiltesting.MakeFlavor("spot", "10").Obj()).
+			Obj()).
+		Obj()
+	finishedJob := utiltesting.MakeJob("finished", "ns").Suspend(false).Request(corev1.ResourceCPU, "1").Obj()
+	finishedJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	ownedPod := utiltesting.MakePod("owned", "ns").NodeName("node").Request(corev1.ResourceCPU, "1").Obj()
+	ownedPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "Job", Name: "running", Controller: pointer.Bool(true)}}
+
+	objs := []client.Object{
+		cq,
+		utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").Obj(),
+		utiltesting.MakeJob("running", "ns").Suspend(false).Request(corev1.ResourceCPU, "1").Obj(),
+		utiltesting.MakeJob("suspended", "ns").Request(corev1.ResourceCPU, "1").Obj(),
+		utiltesting.MakeJob("queued", "ns").Queue("lq").Suspend(false).Request(corev1.ResourceCPU, "1").Obj(),
+		finishedJob,
+		utiltesting.MakePod("running", "ns").NodeName("node").Phase(corev1.PodRunning).Request(corev1.ResourceCPU, "1").Obj(),
+		utiltesting.MakePod("pending", "ns").Request(corev1.ResourceCPU, "1").Obj(),
+		utiltesting.MakePod("succeeded", "ns").NodeName("node").Phase(corev1.PodSucceeded).Request(corev1.ResourceCPU, "1").Obj(),
+		ownedPod,
+	}
+	cases := map[string]struct {
+		opts          Options
+		wantResult    Result
+		wantErr       bool
+		wantWorkloads map[string]string
+	}{
+		"jobs": {
+			opts:          Options{Namespaces: []string{"ns"}, QueueName: "lq"},
+			wantResult:    Result{Jobs: 1},
+			wantWorkloads: map[string]string{"running": "on-demand"},
+		},
+		"jobs and pods in flavor": {
+			opts:          Options{Namespaces: []string{"ns"}, QueueName: "lq", Flavor: "spot", ImportPods: true},
+			wantResult:    Result{Jobs: 1, Pods: 1},
+			wantWorkloads: map[string]string{"running": "spot", "pod-running": "spot"},
+		},
+		"dry run": {
+			opts:       Options{Namespaces: []string{"ns"}, QueueName: "lq", ImportPods: true, DryRun: true},
+			wantResult: Result{Jobs: 1, Pods: 1},
+		},
+		"missing flavor": {
+			opts:    Options{Namespaces: []string{"ns"}, QueueName: "lq", Flavor: "reserved"},
+			wantErr: true,
+		},
+		"missing queue": {
+			opts:    Options{Namespaces: []string{"ns"}, QueueName: "other"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := utiltesting.MustGetScheme(t)
+			if err := batchv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("Adding batch to scheme: %v", err)
+			}
+			if err := schedulingv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("Adding scheduling to scheme: %v", err)
+			}
+			var initObjs []client.Object
+			for _, obj := range objs {
+				initObjs = append(initObjs, obj.DeepCopyObject().(client.Object))
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+
+			result, err := NewImporter(c, scheme, tc.opts).Run(ctx)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.wantResult, result); diff != "" {
+				t.Errorf("Unexpected result (-want,+got):\n%s", diff)
+			}
+
+			var wls kueue.WorkloadList
+			if err := c.List(ctx, &wls); err != nil {
+				t.Fatalf("Listing workloads: %v", err)
+			}
+			gotWorkloads := make(map[string]string, len(wls.Items))
+			for _, wl := range wls.Items {
+				if wl.Spec.QueueName != "lq" || wl.Spec.Admission == nil || wl.Spec.Admission.ClusterQueue != "cq" {
+					t.Errorf("Workload %s is not admitted by cq from lq: %+v", wl.Name, wl.Spec)
+					continue
+				}
+				gotWorkloads[wl.Name] = wl.Spec.Admission.PodSetFlavors[0].Flavors[corev1.ResourceCPU]
+			}
+			if diff := cmp.Diff(tc.wantWorkloads, gotWorkloads, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected workloads and flavors (-want,+got):\n%s", diff)
+			}
+
+			var job batchv1.Job
+			if err := c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "running"}, &job); err != nil {
+				t.Fatalf("Getting job: %v", err)
+			}
+			_, imported := gotWorkloads["running"]
+			if got := job.Annotations[constants.QueueAnnotation] == "lq" && job.Annotations[constants.ImportedAnnotation] == "true"; got != imported {
+				t.Errorf("Job has the queue name and imported annotations: %t, want %t", got, imported)
+			}
+			var p corev1.Pod
+			if err := c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "running"}, &p); err != nil {
+				t.Fatalf("Getting pod: %v", err)
+			}
+			_, imported = gotWorkloads["pod-running"]
+			if got := p.Annotations[constants.QueueAnnotation] == "lq" && p.Labels[pod.ManagedLabel] == "true"; got != imported {
+				t.Errorf("Pod has the queue name and managed label: %t, want %t", got, imported)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strings"
+
+	zaplog "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/cmd/importer/app"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	log    = ctrl.Log.WithName("importer")
+)
+
+func init() {
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(batchv1.AddToScheme(scheme))
+	utilruntime.Must(schedulingv1.AddToScheme(scheme))
+	utilruntime.Must(kueue.AddToScheme(scheme))
+}
+
+func main() {
+	var opts app.Options
+	var namespaces string
+	flag.StringVar(&namespaces, "namespaces", "", "Comma-separated list of the namespaces to import the running Jobs and Pods from.")
+	flag.StringVar(&opts.QueueName, "queue", "", "Name of the LocalQueue, which must exist in each of the namespaces, to add the imported Jobs and Pods to. Their Workloads are admitted by its ClusterQueue.")
+	flag.StringVar(&opts.Flavor, "flavor", "", "ResourceFlavor to admit the Workloads with. Defaults to the first flavor of each resource in the ClusterQueue.")
+	flag.BoolVar(&opts.ImportPods, "import-pods", false, "Also import the running Pods that aren't owned by a Job. Only enable it if the pod integration is enabled in Kueue.")
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "Only log the Jobs and Pods that would be imported.")
+	zapOpts := zap.Options{
+		TimeEncoder: zapcore.RFC3339NanoTimeEncoder,
+		ZapOpts:     []zaplog.Option{zaplog.AddCaller()},
+	}
+	zapOpts.BindFlags(flag.CommandLine)
+	flag.Parse()
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&zapOpts)))
+
+	if namespaces == "" || opts.QueueName == "" {
+		log.Error(nil, "The --namespaces and --queue flags are required")
+		os.Exit(1)
+	}
+	opts.Namespaces = strings.Split(namespaces, ",")
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		log.Error(err, "Unable to create the client")
+		os.Exit(1)
+	}
+	ctx := ctrl.LoggerInto(context.Background(), log)
+	result, err := app.NewImporter(c, scheme, opts).Run(ctx)
+	log.Info("Finished importing", "jobs", result.Jobs, "pods", result.Pods)
+	if err != nil {
+		log.Error(err, "Some Jobs or Pods couldn't be imported")
+		os.Exit(1)
+	}
+}
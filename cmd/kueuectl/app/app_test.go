@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/rest/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	visibility "sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+)
+
+var testNow = time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+type fakeClientGetter struct {
+	namespace  string
+	client     client.Client
+	visibility rest.Interface
+}
+
+func (g *fakeClientGetter) Namespace() (string, error) {
+	return g.namespace, nil
+}
+
+func (g *fakeClientGetter) KueueClient() (client.Client, error) {
+	return g.client, nil
+}
+
+func (g *fakeClientGetter) VisibilityClient() (rest.Interface, error) {
+	return g.visibility, nil
+}
+
+// runCmd runs kueuectl with the given args and returns its output.
+func runCmd(t *testing.T, getter ClientGetter, args ...string) (string, string, error) {
+	t.Helper()
+	now = func() time.Time { return testNow }
+	t.Cleanup(func() { now = time.Now })
+	var out, errOut bytes.Buffer
+	streams := genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: &out, ErrOut: &errOut}
+	cmd := newKueuectlCmd(getter, streams)
+	cmd.SetArgs(args)
+	cmd.SetOut(&errOut)
+	cmd.SetErr(&errOut)
+	err := cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	return clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+// newFakeVisibilityClient returns a client for the visibility API that
+// responds with the pending workloads for the given request paths.
+func newFakeVisibilityClient(t *testing.T, responses map[string]*visibility.PendingWorkloadsSummary) *fake.RESTClient {
+	t.Helper()
+	return &fake.RESTClient{
+		NegotiatedSerializer: serializer.NewCodecFactory(scheme).WithoutConversion(),
+		GroupVersion:         visibility.GroupVersion,
+		VersionedAPIPath:     "/apis/visibility.kueue.x-k8s.io/v1alpha1",
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			summary, found := responses[req.URL.Path+"?"+req.URL.RawQuery]
+			if !found {
+				return &http.Response{StatusCode: http.StatusNotFound, Header: jsonHeader(), Body: io.NopCloser(&bytes.Buffer{})}, nil
+			}
+			summary.APIVersion = visibility.GroupVersion.String()
+			summary.Kind = "PendingWorkloadsSummary"
+			body, err := json.Marshal(summary)
+			if err != nil {
+				t.Fatalf("Marshalling summary: %v", err)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: jsonHeader(), Body: io.NopCloser(bytes.NewReader(body))}, nil
+		}),
+	}
+}
+
+func jsonHeader() http.Header {
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	return h
+}
+
+func creation(obj client.Object, ago time.Duration) client.Object {
+	obj.SetCreationTimestamp(metav1.NewTime(testNow.Add(-ago)))
+	return obj
+}
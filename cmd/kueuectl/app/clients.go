@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	visibility "sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(batchv1.AddToScheme(scheme))
+	utilruntime.Must(kueue.AddToScheme(scheme))
+	utilruntime.Must(visibility.AddToScheme(scheme))
+}
+
+// ClientGetter provides the clients used by the commands.
+type ClientGetter interface {
+	// Namespace returns the namespace from the flags or the kubeconfig.
+	Namespace() (string, error)
+	// KueueClient returns a client for the Kueue API objects and the Jobs.
+	KueueClient() (client.Client, error)
+	// VisibilityClient returns a REST client for the visibility API.
+	VisibilityClient() (rest.Interface, error)
+}
+
+type clientGetter struct {
+	flags *genericclioptions.ConfigFlags
+}
+
+// NewClientGetter returns a ClientGetter that builds the clients from the
+// kubeconfig flags.
+func NewClientGetter(flags *genericclioptions.ConfigFlags) ClientGetter {
+	return &clientGetter{flags: flags}
+}
+
+func (g *clientGetter) Namespace() (string, error) {
+	ns, _, err := g.flags.ToRawKubeConfigLoader().Namespace()
+	return ns, err
+}
+
+func (g *clientGetter) KueueClient() (client.Client, error) {
+	cfg, err := g.flags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
+func (g *clientGetter) VisibilityClient() (rest.Interface, error) {
+	cfg, err := g.flags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg = rest.CopyConfig(cfg)
+	cfg.GroupVersion = &visibility.GroupVersion
+	cfg.APIPath = "/apis"
+	cfg.NegotiatedSerializer = serializer.NewCodecFactory(scheme).WithoutConversion()
+	return rest.RESTClientFor(cfg)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+type createOptions struct {
+	getter  ClientGetter
+	streams genericclioptions.IOStreams
+
+	cohort           string
+	queueingStrategy string
+	quotas           []string
+	clusterQueue     string
+	weight           int32
+}
+
+func newCreateCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Creates a ClusterQueue, a LocalQueue or a Job in a LocalQueue",
+	}
+	cmd.AddCommand(
+		newCreateClusterQueueCmd(getter, streams),
+		newCreateLocalQueueCmd(getter, streams),
+		newCreateJobCmd(getter, streams),
+		newCreateSlurmCmd(getter, streams),
+	)
+	return cmd
+}
+
+func newCreateClusterQueueCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &createOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:     "clusterqueue NAME",
+		Aliases: []string{"cq"},
+		Short:   "Creates a ClusterQueue",
+		Example: `  # Create a ClusterQueue with quota for cpu and memory in the default flavor
+  kueuectl create clusterqueue team-a --cohort all --quota cpu=default:9 --quota memory=default:36Gi
+
+  # Create a ClusterQueue with two flavors of GPUs, borrowing up to 20 k80 GPUs
+  kueuectl create clusterqueue team-b --quota nvidia.com/gpu=k80:10:20 --quota nvidia.com/gpu=p100:10`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.createClusterQueue(cmd.Context(), args[0])
+		},
+	}
+	cmd.Flags().StringVar(&o.cohort, "cohort", "", "Cohort that the ClusterQueue belongs to.")
+	cmd.Flags().StringVar(&o.queueingStrategy, "queueing-strategy", "", "Queueing strategy of the ClusterQueue. One of: StrictFIFO, BestEffortFIFO, Priority.")
+	cmd.Flags().StringArrayVar(&o.quotas, "quota", nil, "Quota of a resource in a flavor, as RESOURCE=FLAVOR:MIN[:MAX]. Can be repeated; the flavors of each resource keep the order of the flags.")
+	return cmd
+}
+
+func newCreateLocalQueueCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &createOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:     "localqueue NAME",
+		Aliases: []string{"lq"},
+		Short:   "Creates a LocalQueue",
+		Example: `  # Create a LocalQueue in the current namespace, pointing to the ClusterQueue team-a
+  kueuectl create localqueue user-queue --clusterqueue team-a`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.createLocalQueue(cmd.Context(), args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&o.clusterQueue, "clusterqueue", "c", "", "ClusterQueue that backs the LocalQueue.")
+	cmd.Flags().Int32Var(&o.weight, "weight", 0, "Weight of the LocalQueue in its ClusterQueue. Defaults to 1.")
+	_ = cmd.MarkFlagRequired("clusterqueue")
+	return cmd
+}
+
+func (o *createOptions) createClusterQueue(ctx context.Context, name string) error {
+	resourceGroups, err := parseQuotas(o.quotas)
+	if err != nil {
+		return err
+	}
+	cq := &kueue.ClusterQueue{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: kueue.ClusterQueueSpec{
+			Cohort:           o.cohort,
+			QueueingStrategy: kueue.QueueingStrategy(o.queueingStrategy),
+			ResourceGroups:   resourceGroups,
+		},
+	}
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	if err := c.Create(ctx, cq); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.streams.Out, "clusterqueue.kueue.x-k8s.io/%s created\n", name)
+	return nil
+}
+
+func (o *createOptions) createLocalQueue(ctx context.Context, name string) error {
+	ns, err := o.getter.Namespace()
+	if err != nil {
+		return err
+	}
+	lq := &kueue.LocalQueue{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec: kueue.LocalQueueSpec{
+			ClusterQueue: kueue.ClusterQueueReference(o.clusterQueue),
+			Weight:       o.weight,
+		},
+	}
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	if err := c.Create(ctx, lq); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.streams.Out, "localqueue.kueue.x-k8s.io/%s created\n", name)
+	return nil
+}
+
+// parseQuotas builds the resource groups of a ClusterQueue from quotas in the
+// form RESOURCE=FLAVOR:MIN[:MAX], keeping the order in which the resources and
+// their flavors appear. The resources that have quotas in the same flavors,
+// in the same order, are covered by the same resource group.
+func parseQuotas(quotas []string) ([]kueue.ResourceGroup, error) {
+	var resources []kueue.Resource
+	index := make(map[corev1.ResourceName]int)
+	for _, q := range quotas {
+		name, fq, err := parseQuota(q)
+		if err != nil {
+			return nil, err
+		}
+		i, found := index[name]
+		if !found {
+			i = len(resources)
+			index[name] = i
+			resources = append(resources, kueue.Resource{Name: name})
+		}
+		for _, f := range resources[i].Flavors {
+			if f.Name == fq.Name {
+				return nil, fmt.Errorf("duplicate quota for resource %q in flavor %q", name, fq.Name)
+			}
+		}
+		resources[i].Flavors = append(resources[i].Flavors, *fq)
+	}
+	return kueue.ResourceGroupsFromResources(resources), nil
+}
+
+func parseQuota(q string) (corev1.ResourceName, *kueue.Flavor, error) {
+	name, value, found := strings.Cut(q, "=")
+	if !found || name == "" {
+		return "", nil, fmt.Errorf("invalid quota %q, must be RESOURCE=FLAVOR:MIN[:MAX]", q)
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return "", nil, fmt.Errorf("invalid quota %q, must be RESOURCE=FLAVOR:MIN[:MAX]", q)
+	}
+	flavor := &kueue.Flavor{Name: kueue.ResourceFlavorReference(parts[0])}
+	min, err := resource.ParseQuantity(parts[1])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid min quota in %q: %w", q, err)
+	}
+	flavor.Quota.Min = min
+	if len(parts) == 3 {
+		max, err := resource.ParseQuantity(parts[2])
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid max quota in %q: %w", q, err)
+		}
+		flavor.Quota.Max = &max
+	}
+	return corev1.ResourceName(name), flavor, nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kueue/pkg/constants"
+)
+
+type createJobOptions struct {
+	getter  ClientGetter
+	streams genericclioptions.IOStreams
+
+	queue         string
+	image         string
+	parallelism   int32
+	completions   int32
+	requests      map[string]string
+	priorityClass string
+	dryRun        bool
+
+	// sbatch-style flags.
+	array       string
+	cpusPerTask string
+	mem         string
+}
+
+func newCreateJobCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &createJobOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:   "job NAME --queue LOCALQUEUE --image IMAGE [flags] -- [COMMAND] [ARGS...]",
+		Short: "Creates a Job in a LocalQueue",
+		Example: `  # Create a Job with 4 pods, each requesting 1 cpu and 2Gi of memory
+  kueuectl create job sample --queue user-queue --image busybox --parallelism 4 --completions 4 \
+    --request cpu=1,memory=2Gi -- sleep 60`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := o.newJob(args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			return o.create(cmd.Context(), job)
+		},
+	}
+	o.addFlags(cmd)
+	cmd.Flags().Int32Var(&o.parallelism, "parallelism", 1, "Number of pods running in parallel.")
+	cmd.Flags().Int32Var(&o.completions, "completions", 1, "Number of pods that need to succeed.")
+	cmd.Flags().StringToStringVar(&o.requests, "request", nil, "Resource requests of each pod, for example cpu=1,memory=2Gi.")
+	return cmd
+}
+
+func newCreateSlurmCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &createJobOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:   "slurm NAME --queue LOCALQUEUE --image IMAGE [sbatch flags] -- [COMMAND] [ARGS...]",
+		Short: "Creates a Job in a LocalQueue from sbatch-style flags",
+		Long: `Creates a Job in a LocalQueue from a subset of the flags of sbatch, to ease the
+migration from HPC schedulers.
+
+--array creates an Indexed Job with a pod per task. The completion index of a
+pod, in the JOB_COMPLETION_INDEX environment variable, starts from 0. The
+pods also get the SLURM_ARRAY_TASK_MIN, SLURM_ARRAY_TASK_MAX and
+SLURM_ARRAY_TASK_COUNT environment variables, so that the task ID is
+SLURM_ARRAY_TASK_MIN + JOB_COMPLETION_INDEX.`,
+		Example: `  # Run 10 tasks, 2 at a time, each with 4 cpus and 8Gi of memory
+  kueuectl create slurm sample --queue user-queue --image busybox \
+    --array 1-10%2 --cpus-per-task 4 --mem 8G -- ./process.sh`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := o.newSlurmJob(args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			return o.create(cmd.Context(), job)
+		},
+	}
+	o.addFlags(cmd)
+	cmd.Flags().StringVarP(&o.array, "array", "a", "", "Task indexes, as MIN-MAX, optionally followed by %LIMIT to limit the tasks running in parallel.")
+	cmd.Flags().StringVarP(&o.cpusPerTask, "cpus-per-task", "c", "", "Number of cpus of each task.")
+	cmd.Flags().StringVar(&o.mem, "mem", "", "Memory of each task, with the suffix K, M, G or T. Defaults to M.")
+	return cmd
+}
+
+func (o *createJobOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.queue, "queue", "q", "", "LocalQueue to submit the Job to.")
+	cmd.Flags().StringVar(&o.image, "image", "", "Image of the container of the pods.")
+	cmd.Flags().StringVar(&o.priorityClass, "priority-class", "", "PriorityClass of the pods.")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only print the Job that would be created.")
+	_ = cmd.MarkFlagRequired("queue")
+	_ = cmd.MarkFlagRequired("image")
+}
+
+// newJob returns a suspended Job in the queue, with a pod template of a single
+// container running the command.
+func (o *createJobOptions) newJob(name string, command []string) (*batchv1.Job, error) {
+	ns, err := o.getter.Namespace()
+	if err != nil {
+		return nil, err
+	}
+	requests, err := parseRequests(o.requests)
+	if err != nil {
+		return nil, err
+	}
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: batchv1.SchemeGroupVersion.String(), Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   ns,
+			Annotations: map[string]string{constants.QueueAnnotation: o.queue},
+		},
+		Spec: batchv1.JobSpec{
+			Parallelism: pointer.Int32(o.parallelism),
+			Completions: pointer.Int32(o.completions),
+			Suspend:     pointer.Bool(true),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:     corev1.RestartPolicyNever,
+					PriorityClassName: o.priorityClass,
+					Containers: []corev1.Container{{
+						Name:    "main",
+						Image:   o.image,
+						Command: command,
+						Resources: corev1.ResourceRequirements{
+							Requests: requests,
+						},
+					}},
+				},
+			},
+		},
+	}, nil
+}
+
+// newSlurmJob translates the sbatch-style flags into a Job.
+func (o *createJobOptions) newSlurmJob(name string, command []string) (*batchv1.Job, error) {
+	o.parallelism = 1
+	o.completions = 1
+	job, err := o.newJob(name, command)
+	if err != nil {
+		return nil, err
+	}
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.Resources.Requests = corev1.ResourceList{}
+	if o.cpusPerTask != "" {
+		cpus, err := strconv.ParseInt(o.cpusPerTask, 10, 32)
+		if err != nil || cpus <= 0 {
+			return nil, fmt.Errorf("invalid --cpus-per-task %q, must be a positive integer", o.cpusPerTask)
+		}
+		container.Resources.Requests[corev1.ResourceCPU] = *resource.NewQuantity(cpus, resource.DecimalSI)
+	}
+	if o.mem != "" {
+		mem, err := parseSlurmMemory(o.mem)
+		if err != nil {
+			return nil, err
+		}
+		container.Resources.Requests[corev1.ResourceMemory] = mem
+	}
+	if o.array != "" {
+		min, max, limit, err := parseSlurmArray(o.array)
+		if err != nil {
+			return nil, err
+		}
+		count := max - min + 1
+		if limit == 0 || limit > count {
+			limit = count
+		}
+		job.Spec.CompletionMode = completionModePtr(batchv1.IndexedCompletion)
+		job.Spec.Completions = pointer.Int32(count)
+		job.Spec.Parallelism = pointer.Int32(limit)
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "SLURM_ARRAY_TASK_MIN", Value: strconv.Itoa(int(min))},
+			corev1.EnvVar{Name: "SLURM_ARRAY_TASK_MAX", Value: strconv.Itoa(int(max))},
+			corev1.EnvVar{Name: "SLURM_ARRAY_TASK_COUNT", Value: strconv.Itoa(int(count))},
+		)
+	}
+	return job, nil
+}
+
+func (o *createJobOptions) create(ctx context.Context, job *batchv1.Job) error {
+	if o.dryRun {
+		out, err := yaml.Marshal(job)
+		if err != nil {
+			return err
+		}
+		_, err = o.streams.Out.Write(out)
+		return err
+	}
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	if err := c.Create(ctx, job); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.streams.Out, "job.batch/%s created\n", job.Name)
+	return nil
+}
+
+func parseRequests(requests map[string]string) (corev1.ResourceList, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	list := make(corev1.ResourceList, len(requests))
+	for name, value := range requests {
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request %s=%s: %w", name, value, err)
+		}
+		list[corev1.ResourceName(name)] = q
+	}
+	return list, nil
+}
+
+// parseSlurmMemory parses the memory in the format of the --mem flag of
+// sbatch: an integer with an optional K, M, G or T suffix, M by default.
+func parseSlurmMemory(mem string) (resource.Quantity, error) {
+	value, suffix := mem, "M"
+	if last := strings.ToUpper(mem[len(mem)-1:]); strings.Contains("KMGT", last) {
+		value, suffix = mem[:len(mem)-1], last
+	}
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || v <= 0 {
+		return resource.Quantity{}, fmt.Errorf("invalid --mem %q, must be a positive integer with an optional K, M, G or T suffix", mem)
+	}
+	return resource.ParseQuantity(fmt.Sprintf("%d%si", v, suffix))
+}
+
+// parseSlurmArray parses the task indexes in the format of the --array flag of
+// sbatch, restricted to a single range: MIN-MAX[%LIMIT] or INDEX[%LIMIT].
+func parseSlurmArray(array string) (min, max, limit int32, err error) {
+	invalid := fmt.Errorf("invalid --array %q, must be MIN-MAX, optionally followed by %%LIMIT", array)
+	indexes, limitStr, hasLimit := strings.Cut(array, "%")
+	minStr, maxStr, isRange := strings.Cut(indexes, "-")
+	if !isRange {
+		maxStr = minStr
+	}
+	parse := func(s string) (int32, error) {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || v < 0 {
+			return 0, invalid
+		}
+		return int32(v), nil
+	}
+	if min, err = parse(minStr); err != nil {
+		return 0, 0, 0, err
+	}
+	if max, err = parse(maxStr); err != nil {
+		return 0, 0, 0, err
+	}
+	if max < min {
+		return 0, 0, 0, invalid
+	}
+	if hasLimit {
+		if limit, err = parse(limitStr); err != nil || limit == 0 {
+			return 0, 0, 0, invalid
+		}
+	}
+	return min, max, limit, nil
+}
+
+func completionModePtr(m batchv1.CompletionMode) *batchv1.CompletionMode {
+	return &m
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/kueue/pkg/constants"
+)
+
+func TestCreateJob(t *testing.T) {
+	baseJob := func(parallelism, completions int32, requests corev1.ResourceList) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "job",
+				Namespace:   "ns",
+				Annotations: map[string]string{constants.QueueAnnotation: "lq"},
+			},
+			Spec: batchv1.JobSpec{
+				Parallelism: pointer.Int32(parallelism),
+				Completions: pointer.Int32(completions),
+				Suspend:     pointer.Bool(true),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers: []corev1.Container{{
+							Name:      "main",
+							Image:     "busybox",
+							Command:   []string{"sleep", "60"},
+							Resources: corev1.ResourceRequirements{Requests: requests},
+						}},
+					},
+				},
+			},
+		}
+	}
+	cases := map[string]struct {
+		args    []string
+		wantJob *batchv1.Job
+		wantErr bool
+	}{
+		"job": {
+			args: []string{"create", "job", "job", "--queue", "lq", "--image", "busybox",
+				"--parallelism", "4", "--completions", "8", "--request", "cpu=1,memory=2Gi", "--", "sleep", "60"},
+			wantJob: baseJob(4, 8, corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			}),
+		},
+		"job without queue": {
+			args:    []string{"create", "job", "job", "--image", "busybox", "--", "sleep", "60"},
+			wantErr: true,
+		},
+		"job with invalid request": {
+			args:    []string{"create", "job", "job", "--queue", "lq", "--image", "busybox", "--request", "cpu=one", "--", "sleep", "60"},
+			wantErr: true,
+		},
+		"slurm": {
+			args: []string{"create", "slurm", "job", "--queue", "lq", "--image", "busybox",
+				"--cpus-per-task", "4", "--mem", "512", "--", "sleep", "60"},
+			wantJob: baseJob(1, 1, corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			}),
+		},
+		"slurm array": {
+			args: []string{"create", "slurm", "job", "-q", "lq", "--image", "busybox",
+				"--array", "1-10%2", "-c", "2", "--mem", "8G", "--", "sleep", "60"},
+			wantJob: func() *batchv1.Job {
+				job := baseJob(2, 10, corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				})
+				job.Spec.CompletionMode = completionModePtr(batchv1.IndexedCompletion)
+				job.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+					{Name: "SLURM_ARRAY_TASK_MIN", Value: "1"},
+					{Name: "SLURM_ARRAY_TASK_MAX", Value: "10"},
+					{Name: "SLURM_ARRAY_TASK_COUNT", Value: "10"},
+				}
+				return job
+			}(),
+		},
+		"slurm array without limit": {
+			args: []string{"create", "slurm", "job", "-q", "lq", "--image", "busybox", "--array", "0-3", "--", "sleep", "60"},
+			wantJob: func() *batchv1.Job {
+				job := baseJob(4, 4, nil)
+				job.Spec.CompletionMode = completionModePtr(batchv1.IndexedCompletion)
+				job.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+					{Name: "SLURM_ARRAY_TASK_MIN", Value: "0"},
+					{Name: "SLURM_ARRAY_TASK_MAX", Value: "3"},
+					{Name: "SLURM_ARRAY_TASK_COUNT", Value: "4"},
+				}
+				return job
+			}(),
+		},
+		"slurm with invalid array": {
+			args:    []string{"create", "slurm", "job", "-q", "lq", "--image", "busybox", "--array", "1,3,5", "--", "sleep", "60"},
+			wantErr: true,
+		},
+		"slurm with invalid mem": {
+			args:    []string{"create", "slurm", "job", "-q", "lq", "--image", "busybox", "--mem", "8GB", "--", "sleep", "60"},
+			wantErr: true,
+		},
+		"slurm with invalid cpus": {
+			args:    []string{"create", "slurm", "job", "-q", "lq", "--image", "busybox", "-c", "0.5", "--", "sleep", "60"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newFakeClient()
+			out, _, err := runCmd(t, &fakeClientGetter{namespace: "ns", client: c}, tc.args...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff("job.batch/job created\n", out); diff != "" {
+				t.Errorf("Unexpected output (-want,+got):\n%s", diff)
+			}
+			var got batchv1.Job
+			if err := c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "job"}, &got); err != nil {
+				t.Fatalf("Getting created Job: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantJob, &got, cmpopts.EquateEmpty(), cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion"), cmpopts.IgnoreTypes(metav1.TypeMeta{})); diff != "" {
+				t.Errorf("Unexpected Job (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateJobDryRun(t *testing.T) {
+	c := newFakeClient()
+	out, _, err := runCmd(t, &fakeClientGetter{namespace: "ns", client: c},
+		"create", "slurm", "job", "-q", "lq", "--image", "busybox", "-c", "1", "--dry-run", "--", "sleep", "60")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	want := `apiVersion: batch/v1
+kind: Job
+metadata:
+  annotations:
+    kueue.x-k8s.io/queue-name: lq
+  creationTimestamp: null
+  name: job
+  namespace: ns
+spec:
+  completions: 1
+  parallelism: 1
+  suspend: true
+  template:
+    metadata:
+      creationTimestamp: null
+    spec:
+      containers:
+      - command:
+        - sleep
+        - "60"
+        image: busybox
+        name: main
+        resources:
+          requests:
+            cpu: "1"
+      restartPolicy: Never
+status: {}
+`
+	if diff := cmp.Diff(want, out); diff != "" {
+		t.Errorf("Unexpected output (-want,+got):\n%s", diff)
+	}
+	var jobs batchv1.JobList
+	if err := c.List(context.Background(), &jobs); err != nil {
+		t.Fatalf("Listing Jobs: %v", err)
+	}
+	if len(jobs.Items) != 0 {
+		t.Errorf("Dry run created %d Jobs", len(jobs.Items))
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		key     types.NamespacedName
+		newObj  func() client.Object
+		wantObj client.Object
+		wantOut string
+		wantErr bool
+	}{
+		"clusterqueue": {
+			args: []string{"create", "clusterqueue", "cq", "--cohort", "all", "--queueing-strategy", "StrictFIFO",
+				"--quota", "cpu=spot:10:20", "--quota", "memory=spot:36Gi", "--quota", "cpu=on-demand:5"},
+			key:    types.NamespacedName{Name: "cq"},
+			newObj: func() client.Object { return &kueue.ClusterQueue{} },
+			wantObj: &kueue.ClusterQueue{
+				ObjectMeta: metav1.ObjectMeta{Name: "cq"},
+				Spec: kueue.ClusterQueueSpec{
+					Cohort:           "all",
+					QueueingStrategy: kueue.StrictFIFO,
+					ResourceGroups: utiltesting.ResourceGroups(
+						utiltesting.MakeResource("cpu").
+							Flavor(utiltesting.MakeFlavor("spot", "10").Max("20").Obj()).
+							Flavor(utiltesting.MakeFlavor("on-demand", "5").Obj()).
+							Obj(),
+						utiltesting.MakeResource("memory").
+							Flavor(utiltesting.MakeFlavor("spot", "36Gi").Obj()).
+							Obj(),
+					),
+				},
+			},
+			wantOut: "clusterqueue.kueue.x-k8s.io/cq created\n",
+		},
+		"clusterqueue with invalid quota": {
+			args:    []string{"create", "clusterqueue", "cq", "--quota", "cpu=10"},
+			wantErr: true,
+		},
+		"clusterqueue with duplicate quota": {
+			args:    []string{"create", "clusterqueue", "cq", "--quota", "cpu=default:10", "--quota", "cpu=default:5"},
+			wantErr: true,
+		},
+		"localqueue": {
+			args:    []string{"create", "localqueue", "lq", "--clusterqueue", "cq", "--weight", "2"},
+			key:     types.NamespacedName{Namespace: "ns", Name: "lq"},
+			newObj:  func() client.Object { return &kueue.LocalQueue{} },
+			wantObj: utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").Weight(2).Obj(),
+			wantOut: "localqueue.kueue.x-k8s.io/lq created\n",
+		},
+		"localqueue without clusterqueue": {
+			args:    []string{"create", "localqueue", "lq"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newFakeClient()
+			out, _, err := runCmd(t, &fakeClientGetter{namespace: "ns", client: c}, tc.args...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.wantOut, out); diff != "" {
+				t.Errorf("Unexpected output (-want,+got):\n%s", diff)
+			}
+			got := tc.newObj()
+			if err := c.Get(context.Background(), tc.key, got); err != nil {
+				t.Fatalf("Getting created object: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantObj, got, cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion"), cmpopts.IgnoreTypes(metav1.TypeMeta{})); diff != "" {
+				t.Errorf("Unexpected object (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	visibility "sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+type describeOptions struct {
+	getter  ClientGetter
+	streams genericclioptions.IOStreams
+}
+
+func newDescribeCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &describeOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Shows the details of a ClusterQueue, LocalQueue or Workload",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:     "clusterqueue NAME",
+			Aliases: []string{"cq"},
+			Short:   "Shows the details of a ClusterQueue",
+			Args:    cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.describeClusterQueue(cmd.Context(), args[0])
+			},
+		},
+		&cobra.Command{
+			Use:     "localqueue NAME",
+			Aliases: []string{"lq"},
+			Short:   "Shows the details of a LocalQueue",
+			Args:    cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.describeLocalQueue(cmd.Context(), args[0])
+			},
+		},
+		&cobra.Command{
+			Use:     "workload NAME",
+			Aliases: []string{"wl"},
+			Short:   "Shows the details of a Workload, including its position if it's pending",
+			Args:    cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return o.describeWorkload(cmd.Context(), args[0])
+			},
+		},
+	)
+	return cmd
+}
+
+func (o *describeOptions) describeClusterQueue(ctx context.Context, name string) error {
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	var cq kueue.ClusterQueue
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, &cq); err != nil {
+		return err
+	}
+	stopPolicy := kueue.None
+	if cq.Spec.StopPolicy != nil {
+		stopPolicy = *cq.Spec.StopPolicy
+	}
+	w := printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintf(w, "Name:\t%s\n", cq.Name)
+	fmt.Fprintf(w, "Cohort:\t%s\n", orNone(cq.Spec.Cohort))
+	fmt.Fprintf(w, "Queueing Strategy:\t%s\n", cq.Spec.QueueingStrategy)
+	fmt.Fprintf(w, "Stop Policy:\t%s\n", stopPolicy)
+	fmt.Fprintf(w, "Pending Workloads:\t%d\n", cq.Status.PendingWorkloads)
+	fmt.Fprintf(w, "Admitted Workloads:\t%d\n", cq.Status.AdmittedWorkloads)
+	fmt.Fprintf(w, "Age:\t%s\n", age(cq.CreationTimestamp))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(o.streams.Out, "Resources:")
+	w = printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintln(w, "  RESOURCE\tFLAVOR\tMIN\tMAX\tUSED\tBORROWED")
+	for _, rg := range cq.Spec.EffectiveResourceGroups() {
+		for _, f := range rg.Flavors {
+			for _, r := range f.Resources {
+				used := cq.Status.UsedResources[r.Name][string(f.Name)]
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n", r.Name, f.Name, r.Quota.Min.String(),
+					quantityOrNone(r.Quota.Max), quantityOrNone(used.Total), quantityOrNone(used.Borrowed))
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return printConditions(o.streams.Out, cq.Status.Conditions)
+}
+
+func (o *describeOptions) describeLocalQueue(ctx context.Context, name string) error {
+	ns, err := o.getter.Namespace()
+	if err != nil {
+		return err
+	}
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	var lq kueue.LocalQueue
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &lq); err != nil {
+		return err
+	}
+	w := printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintf(w, "Name:\t%s\n", lq.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", lq.Namespace)
+	fmt.Fprintf(w, "ClusterQueue:\t%s\n", lq.Spec.ClusterQueue)
+	fmt.Fprintf(w, "Weight:\t%d\n", lq.Spec.Weight)
+	fmt.Fprintf(w, "Pending Workloads:\t%d\n", lq.Status.PendingWorkloads)
+	fmt.Fprintf(w, "Admitted Workloads:\t%d\n", lq.Status.AdmittedWorkloads)
+	fmt.Fprintf(w, "Age:\t%s\n", age(lq.CreationTimestamp))
+	return w.Flush()
+}
+
+func (o *describeOptions) describeWorkload(ctx context.Context, name string) error {
+	ns, err := o.getter.Namespace()
+	if err != nil {
+		return err
+	}
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	var wl kueue.Workload
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &wl); err != nil {
+		return err
+	}
+	status := workloadStatus(&wl)
+	var priority int32
+	if wl.Spec.Priority != nil {
+		priority = *wl.Spec.Priority
+	}
+	w := printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintf(w, "Name:\t%s\n", wl.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", wl.Namespace)
+	fmt.Fprintf(w, "LocalQueue:\t%s\n", orNone(wl.Spec.QueueName))
+	fmt.Fprintf(w, "Priority Class:\t%s\n", orNone(wl.Spec.PriorityClassName))
+	fmt.Fprintf(w, "Priority:\t%d\n", priority)
+	fmt.Fprintf(w, "Active:\t%t\n", workload.IsActive(&wl))
+	fmt.Fprintf(w, "Status:\t%s\n", status)
+	if wl.Spec.Admission != nil {
+		fmt.Fprintf(w, "Admitted By:\t%s\n", wl.Spec.Admission.ClusterQueue)
+	}
+	if status == statusPending && wl.Spec.QueueName != "" {
+		// The positions are only available through the visibility API, which
+		// might not be enabled.
+		pw, err := o.pendingWorkload(ctx, &wl)
+		switch {
+		case err != nil:
+			fmt.Fprintf(o.streams.ErrOut, "Couldn't get the position of the Workload: %v\n", err)
+		case pw != nil:
+			fmt.Fprintf(w, "Position In ClusterQueue:\t%d\n", pw.PositionInClusterQueue)
+			fmt.Fprintf(w, "Position In LocalQueue:\t%d\n", pw.PositionInLocalQueue)
+		}
+	}
+	fmt.Fprintf(w, "Age:\t%s\n", age(wl.CreationTimestamp))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(o.streams.Out, "Pod Sets:")
+	w = printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintln(w, "  NAME\tCOUNT\tFLAVORS")
+	for i := range wl.Spec.PodSets {
+		ps := &wl.Spec.PodSets[i]
+		count := ps.Count
+		flavors := "<none>"
+		if wl.Spec.Admission != nil {
+			if psFlavors := workload.FindPodSetFlavors(wl.Spec.Admission, ps.Name); psFlavors != nil {
+				count = workload.AdmittedCount(ps, psFlavors)
+				flavors = formatFlavors(psFlavors.Flavors)
+			}
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%s\n", ps.Name, count, flavors)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(wl.Status.AdmissionChecks) > 0 {
+		fmt.Fprintln(o.streams.Out, "Admission Checks:")
+		w = printers.GetNewTabWriter(o.streams.Out)
+		fmt.Fprintln(w, "  NAME\tSTATE\tMESSAGE")
+		for _, check := range wl.Status.AdmissionChecks {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", check.Name, check.State, orNone(check.Message))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return printConditions(o.streams.Out, wl.Status.Conditions)
+}
+
+func (o *describeOptions) pendingWorkload(ctx context.Context, wl *kueue.Workload) (*visibility.PendingWorkload, error) {
+	vc, err := o.getter.VisibilityClient()
+	if err != nil {
+		return nil, err
+	}
+	return findPendingWorkload(ctx, vc, wl.Namespace, wl.Spec.QueueName, wl.Name)
+}
+
+func printConditions(out io.Writer, conditions []metav1.Condition) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out, "Conditions:")
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE\tAGE")
+	for _, c := range conditions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message, age(c.LastTransitionTime))
+	}
+	return w.Flush()
+}
+
+func formatFlavors(flavors map[corev1.ResourceName]string) string {
+	if len(flavors) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(flavors))
+	for r, f := range flavors {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", r, f))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func quantityOrNone(q *resource.Quantity) string {
+	if q == nil {
+		return "<none>"
+	}
+	return q.String()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestDescribe(t *testing.T) {
+	cq := utiltesting.MakeClusterQueue("cq").
+		Cohort("all").
+		StopPolicy(kueue.Hold).
+		Resource(utiltesting.MakeResource("cpu").
+			Flavor(utiltesting.MakeFlavor("default", "10").Max("20").Obj()).
+			Obj()).
+		Obj()
+	cq.Status.PendingWorkloads = 3
+	cq.Status.UsedResources = kueue.UsedResources{
+		"cpu": {"default": kueue.Usage{Total: resourcePtr("12"), Borrowed: resourcePtr("2")}},
+	}
+	cq.Status.Conditions = []metav1.Condition{{
+		Type:               kueue.ClusterQueueActive,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Stopped",
+		Message:            "The ClusterQueue is stopped",
+		LastTransitionTime: metav1.NewTime(testNow.Add(-time.Minute)),
+	}}
+	objs := []client.Object{
+		creation(cq, time.Hour),
+		creation(utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").Weight(2).PendingWorkloads(3).Obj(), time.Hour),
+		creation(utiltesting.MakeWorkload("wl", "ns").
+			Queue("lq").
+			Priority(100).
+			Admit(utiltesting.MakeAdmission("cq").Flavor("cpu", "default").Obj()).
+			AdmissionCheck("check", kueue.CheckStateReady).
+			Obj(), 5*time.Minute),
+	}
+	cases := map[string]struct {
+		args    []string
+		wantOut string
+	}{
+		"clusterqueue": {
+			args: []string{"describe", "clusterqueue", "cq"},
+			wantOut: `Name:                 cq
+Cohort:               all
+Queueing Strategy:    BestEffortFIFO
+Stop Policy:          Hold
+Pending Workloads:    3
+Admitted Workloads:   0
+Age:                  60m
+Resources:
+  RESOURCE   FLAVOR    MIN   MAX   USED   BORROWED
+  cpu        default   10    20    12     2
+Conditions:
+  TYPE     STATUS   REASON    MESSAGE                       AGE
+  Active   False    Stopped   The ClusterQueue is stopped   60s
+`,
+		},
+		"localqueue": {
+			args: []string{"describe", "localqueue", "lq"},
+			wantOut: `Name:                 lq
+Namespace:            ns
+ClusterQueue:         cq
+Weight:               2
+Pending Workloads:    3
+Admitted Workloads:   0
+Age:                  60m
+`,
+		},
+		"admitted workload": {
+			args: []string{"describe", "workload", "wl"},
+			wantOut: `Name:             wl
+Namespace:        ns
+LocalQueue:       lq
+Priority Class:   <none>
+Priority:         100
+Active:           true
+Status:           Admitted
+Admitted By:      cq
+Age:              5m
+Pod Sets:
+  NAME   COUNT   FLAVORS
+  main   1       cpu=default
+Admission Checks:
+  NAME    STATE   MESSAGE
+  check   Ready   <none>
+`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			getter := &fakeClientGetter{namespace: "ns", client: newFakeClient(objs...)}
+			out, _, err := runCmd(t, getter, tc.args...)
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantOut, out); diff != "" {
+				t.Errorf("Unexpected output (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func resourcePtr(q string) *resource.Quantity {
+	v := resource.MustParse(q)
+	return &v
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// NewKueuectlCmd returns the root command of kueuectl. When installed in the
+// PATH as kubectl-kueue, it can be invoked as the kueue plugin of kubectl.
+func NewKueuectlCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	configFlags := genericclioptions.NewConfigFlags(true)
+	cmd := newKueuectlCmd(NewClientGetter(configFlags), streams)
+	configFlags.AddFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+func newKueuectlCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "kueuectl",
+		Short:        "Controls the queues and workloads of Kueue",
+		SilenceUsage: true,
+	}
+	cmd.AddCommand(
+		newListCmd(getter, streams),
+		newDescribeCmd(getter, streams),
+		newPendingCmd(getter, streams),
+		newStopCmd(getter, streams),
+		newResumeCmd(getter, streams),
+		newCreateCmd(getter, streams),
+	)
+	return cmd
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+var workloadStatuses = []string{statusPending, statusQuotaReserved, statusAdmitted, statusFinished, statusInactive}
+
+type listOptions struct {
+	getter  ClientGetter
+	streams genericclioptions.IOStreams
+
+	allNamespaces bool
+	clusterQueue  string
+	localQueue    string
+	status        string
+}
+
+func newListCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists ClusterQueues, LocalQueues or Workloads",
+	}
+	cmd.AddCommand(
+		newListClusterQueueCmd(getter, streams),
+		newListLocalQueueCmd(getter, streams),
+		newListWorkloadCmd(getter, streams),
+	)
+	return cmd
+}
+
+func newListClusterQueueCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &listOptions{getter: getter, streams: streams}
+	return &cobra.Command{
+		Use:     "clusterqueue",
+		Aliases: []string{"clusterqueues", "cq"},
+		Short:   "Lists the ClusterQueues",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.listClusterQueues(cmd.Context())
+		},
+	}
+}
+
+func newListLocalQueueCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &listOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:     "localqueue",
+		Aliases: []string{"localqueues", "lq"},
+		Short:   "Lists the LocalQueues",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.listLocalQueues(cmd.Context())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "List the LocalQueues across all namespaces.")
+	cmd.Flags().StringVarP(&o.clusterQueue, "clusterqueue", "c", "", "Only list the LocalQueues pointing to this ClusterQueue.")
+	return cmd
+}
+
+func newListWorkloadCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &listOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:     "workload",
+		Aliases: []string{"workloads", "wl"},
+		Short:   "Lists the Workloads",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.listWorkloads(cmd.Context())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "List the Workloads across all namespaces.")
+	cmd.Flags().StringVarP(&o.clusterQueue, "clusterqueue", "c", "", "Only list the Workloads queued in or admitted by this ClusterQueue.")
+	cmd.Flags().StringVarP(&o.localQueue, "localqueue", "q", "", "Only list the Workloads submitted to this LocalQueue.")
+	cmd.Flags().StringVar(&o.status, "status", "", fmt.Sprintf("Only list the Workloads with this status. One of: %s.", strings.Join(workloadStatuses, ", ")))
+	return cmd
+}
+
+func (o *listOptions) listOpts() ([]client.ListOption, error) {
+	if o.allNamespaces {
+		return nil, nil
+	}
+	ns, err := o.getter.Namespace()
+	if err != nil {
+		return nil, err
+	}
+	return []client.ListOption{client.InNamespace(ns)}, nil
+}
+
+func (o *listOptions) listClusterQueues(ctx context.Context) error {
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	var cqs kueue.ClusterQueueList
+	if err := c.List(ctx, &cqs); err != nil {
+		return err
+	}
+	if len(cqs.Items) == 0 {
+		fmt.Fprintln(o.streams.ErrOut, "No ClusterQueues found")
+		return nil
+	}
+	sort.Slice(cqs.Items, func(i, j int) bool {
+		return cqs.Items[i].Name < cqs.Items[j].Name
+	})
+	w := printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintln(w, "NAME\tCOHORT\tPENDING WORKLOADS\tADMITTED WORKLOADS\tACTIVE\tAGE")
+	for i := range cqs.Items {
+		cq := &cqs.Items[i]
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%t\t%s\n", cq.Name, orNone(cq.Spec.Cohort),
+			cq.Status.PendingWorkloads, cq.Status.AdmittedWorkloads, clusterQueueActive(cq), age(cq.CreationTimestamp))
+	}
+	return w.Flush()
+}
+
+func (o *listOptions) listLocalQueues(ctx context.Context) error {
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	opts, err := o.listOpts()
+	if err != nil {
+		return err
+	}
+	var lqs kueue.LocalQueueList
+	if err := c.List(ctx, &lqs, opts...); err != nil {
+		return err
+	}
+	items := make([]*kueue.LocalQueue, 0, len(lqs.Items))
+	for i := range lqs.Items {
+		lq := &lqs.Items[i]
+		if o.clusterQueue == "" || string(lq.Spec.ClusterQueue) == o.clusterQueue {
+			items = append(items, lq)
+		}
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(o.streams.ErrOut, "No LocalQueues found")
+		return nil
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+	w := printers.GetNewTabWriter(o.streams.Out)
+	o.printNamespaceHeader(w)
+	fmt.Fprintln(w, "NAME\tCLUSTERQUEUE\tPENDING WORKLOADS\tADMITTED WORKLOADS\tAGE")
+	for _, lq := range items {
+		o.printNamespace(w, lq.Namespace)
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", lq.Name, lq.Spec.ClusterQueue,
+			lq.Status.PendingWorkloads, lq.Status.AdmittedWorkloads, age(lq.CreationTimestamp))
+	}
+	return w.Flush()
+}
+
+func (o *listOptions) listWorkloads(ctx context.Context) error {
+	if o.status != "" && !isWorkloadStatus(o.status) {
+		return fmt.Errorf("invalid status %q, must be one of: %s", o.status, strings.Join(workloadStatuses, ", "))
+	}
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	opts, err := o.listOpts()
+	if err != nil {
+		return err
+	}
+	var wls kueue.WorkloadList
+	if err := c.List(ctx, &wls, opts...); err != nil {
+		return err
+	}
+	// The ClusterQueue of the pending workloads is the one of their LocalQueue.
+	lqToCQ := make(map[string]string)
+	if o.clusterQueue != "" {
+		var lqs kueue.LocalQueueList
+		if err := c.List(ctx, &lqs, opts...); err != nil {
+			return err
+		}
+		for _, lq := range lqs.Items {
+			lqToCQ[lq.Namespace+"/"+lq.Name] = string(lq.Spec.ClusterQueue)
+		}
+	}
+	items := make([]*kueue.Workload, 0, len(wls.Items))
+	for i := range wls.Items {
+		wl := &wls.Items[i]
+		if o.localQueue != "" && wl.Spec.QueueName != o.localQueue {
+			continue
+		}
+		if o.clusterQueue != "" && workloadClusterQueue(wl, lqToCQ) != o.clusterQueue {
+			continue
+		}
+		if o.status != "" && !strings.EqualFold(workloadStatus(wl), o.status) {
+			continue
+		}
+		items = append(items, wl)
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(o.streams.ErrOut, "No Workloads found")
+		return nil
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+	w := printers.GetNewTabWriter(o.streams.Out)
+	o.printNamespaceHeader(w)
+	fmt.Fprintln(w, "NAME\tLOCALQUEUE\tCLUSTERQUEUE\tSTATUS\tAGE")
+	for _, wl := range items {
+		o.printNamespace(w, wl.Namespace)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", wl.Name, orNone(wl.Spec.QueueName),
+			orNone(workloadClusterQueue(wl, lqToCQ)), workloadStatus(wl), age(wl.CreationTimestamp))
+	}
+	return w.Flush()
+}
+
+func (o *listOptions) printNamespaceHeader(w io.Writer) {
+	if o.allNamespaces {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+}
+
+func (o *listOptions) printNamespace(w io.Writer, ns string) {
+	if o.allNamespaces {
+		fmt.Fprintf(w, "%s\t", ns)
+	}
+}
+
+// workloadClusterQueue returns the ClusterQueue that admitted the workload or,
+// if it's not admitted, the ClusterQueue of its LocalQueue, if known.
+func workloadClusterQueue(wl *kueue.Workload, lqToCQ map[string]string) string {
+	if wl.Spec.Admission != nil {
+		return string(wl.Spec.Admission.ClusterQueue)
+	}
+	return lqToCQ[wl.Namespace+"/"+wl.Spec.QueueName]
+}
+
+func isWorkloadStatus(s string) bool {
+	for _, st := range workloadStatuses {
+		if strings.EqualFold(st, s) {
+			return true
+		}
+	}
+	return false
+}
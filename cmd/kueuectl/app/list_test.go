@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestList(t *testing.T) {
+	cqActive := utiltesting.MakeClusterQueue("cq-a").Cohort("all").Obj()
+	cqActive.Status.PendingWorkloads = 2
+	cqActive.Status.AdmittedWorkloads = 1
+	cqActive.Status.Conditions = []metav1.Condition{{Type: kueue.ClusterQueueActive, Status: metav1.ConditionTrue}}
+	objs := []client.Object{
+		creation(cqActive, time.Hour),
+		creation(utiltesting.MakeClusterQueue("cq-b").Obj(), 2*time.Hour),
+		creation(utiltesting.MakeLocalQueue("lq-a", "ns1").ClusterQueue("cq-a").PendingWorkloads(1).Obj(), time.Hour),
+		creation(utiltesting.MakeLocalQueue("lq-b", "ns1").ClusterQueue("cq-b").Obj(), time.Hour),
+		creation(utiltesting.MakeLocalQueue("lq-a", "ns2").ClusterQueue("cq-a").PendingWorkloads(1).Obj(), time.Minute),
+		creation(utiltesting.MakeWorkload("pending", "ns1").Queue("lq-a").Obj(), time.Minute),
+		creation(utiltesting.MakeWorkload("admitted", "ns1").Queue("lq-b").Admit(utiltesting.MakeAdmission("cq-b").Obj()).Obj(), 5*time.Minute),
+		creation(utiltesting.MakeWorkload("inactive", "ns1").Queue("lq-a").Active(false).Obj(), 2*time.Minute),
+		creation(utiltesting.MakeWorkload("pending", "ns2").Queue("lq-a").Obj(), time.Minute),
+	}
+	cases := map[string]struct {
+		args       []string
+		wantOut    string
+		wantErrOut string
+		wantErr    bool
+	}{
+		"clusterqueues": {
+			args: []string{"list", "clusterqueue"},
+			wantOut: `NAME   COHORT   PENDING WORKLOADS   ADMITTED WORKLOADS   ACTIVE   AGE
+cq-a   all      2                   1                    true     60m
+cq-b   <none>   0                   0                    false    120m
+`,
+		},
+		"localqueues in namespace": {
+			args: []string{"list", "localqueue"},
+			wantOut: `NAME   CLUSTERQUEUE   PENDING WORKLOADS   ADMITTED WORKLOADS   AGE
+lq-a   cq-a           1                   0                    60m
+lq-b   cq-b           0                   0                    60m
+`,
+		},
+		"localqueues of a clusterqueue in all namespaces": {
+			args: []string{"list", "lq", "-A", "--clusterqueue", "cq-a"},
+			wantOut: `NAMESPACE   NAME   CLUSTERQUEUE   PENDING WORKLOADS   ADMITTED WORKLOADS   AGE
+ns1         lq-a   cq-a           1                   0                    60m
+ns2         lq-a   cq-a           1                   0                    60s
+`,
+		},
+		"workloads in namespace": {
+			args: []string{"list", "workload"},
+			wantOut: `NAME       LOCALQUEUE   CLUSTERQUEUE   STATUS     AGE
+admitted   lq-b         cq-b           Admitted   5m
+inactive   lq-a         <none>         Inactive   2m
+pending    lq-a         <none>         Pending    60s
+`,
+		},
+		"workloads of a clusterqueue": {
+			args: []string{"list", "wl", "-A", "-c", "cq-a"},
+			wantOut: `NAMESPACE   NAME       LOCALQUEUE   CLUSTERQUEUE   STATUS     AGE
+ns1         inactive   lq-a         cq-a           Inactive   2m
+ns1         pending    lq-a         cq-a           Pending    60s
+ns2         pending    lq-a         cq-a           Pending    60s
+`,
+		},
+		"workloads by status": {
+			args: []string{"list", "workload", "--status", "pending"},
+			wantOut: `NAME      LOCALQUEUE   CLUSTERQUEUE   STATUS    AGE
+pending   lq-a         <none>         Pending   60s
+`,
+		},
+		"no workloads found": {
+			args:       []string{"list", "workload", "--localqueue", "lq-c"},
+			wantErrOut: "No Workloads found\n",
+		},
+		"invalid status": {
+			args:    []string{"list", "workload", "--status", "running"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			getter := &fakeClientGetter{namespace: "ns1", client: newFakeClient(objs...)}
+			out, errOut, err := runCmd(t, getter, tc.args...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.wantOut, out); diff != "" {
+				t.Errorf("Unexpected output (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantErrOut, errOut); diff != "" {
+				t.Errorf("Unexpected error output (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/rest"
+
+	visibility "sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+)
+
+type pendingOptions struct {
+	getter  ClientGetter
+	streams genericclioptions.IOStreams
+
+	offset int64
+	limit  int64
+}
+
+func newPendingCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pending",
+		Short: "Shows the pending Workloads of a queue, in the order in which they are considered for admission",
+		Long: `Shows the pending Workloads of a queue, in the order in which they are considered for admission.
+
+Requires the visibility API server of Kueue to be enabled.`,
+	}
+	cmd.AddCommand(
+		newPendingClusterQueueCmd(getter, streams),
+		newPendingLocalQueueCmd(getter, streams),
+	)
+	return cmd
+}
+
+func newPendingClusterQueueCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &pendingOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:     "clusterqueue NAME",
+		Aliases: []string{"cq"},
+		Short:   "Shows the pending Workloads of a ClusterQueue",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runClusterQueue(cmd.Context(), args[0])
+		},
+	}
+	o.addFlags(cmd)
+	return cmd
+}
+
+func newPendingLocalQueueCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &pendingOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:     "localqueue NAME",
+		Aliases: []string{"lq"},
+		Short:   "Shows the pending Workloads of a LocalQueue",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runLocalQueue(cmd.Context(), args[0])
+		},
+	}
+	o.addFlags(cmd)
+	return cmd
+}
+
+func (o *pendingOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64Var(&o.offset, "offset", 0, "Position of the first pending Workload to show.")
+	cmd.Flags().Int64Var(&o.limit, "limit", visibility.DefaultPendingWorkloadsLimit, "Maximum number of pending Workloads to show.")
+}
+
+func (o *pendingOptions) runClusterQueue(ctx context.Context, name string) error {
+	vc, err := o.getter.VisibilityClient()
+	if err != nil {
+		return err
+	}
+	summary, err := pendingWorkloadsInClusterQueue(ctx, vc, name, o.offset, o.limit)
+	if err != nil {
+		return err
+	}
+	if len(summary.Items) == 0 {
+		fmt.Fprintln(o.streams.ErrOut, "No pending Workloads found")
+		return nil
+	}
+	w := printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintln(w, "POSITION\tNAMESPACE\tNAME\tLOCALQUEUE\tPOSITION IN LOCALQUEUE\tPRIORITY\tAGE")
+	for _, pw := range summary.Items {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%s\n", pw.PositionInClusterQueue, pw.Namespace, pw.Name,
+			pw.LocalQueueName, pw.PositionInLocalQueue, pw.Priority, age(pw.CreationTimestamp))
+	}
+	return w.Flush()
+}
+
+func (o *pendingOptions) runLocalQueue(ctx context.Context, name string) error {
+	ns, err := o.getter.Namespace()
+	if err != nil {
+		return err
+	}
+	vc, err := o.getter.VisibilityClient()
+	if err != nil {
+		return err
+	}
+	summary, err := pendingWorkloadsInLocalQueue(ctx, vc, ns, name, o.offset, o.limit)
+	if err != nil {
+		return err
+	}
+	if len(summary.Items) == 0 {
+		fmt.Fprintln(o.streams.ErrOut, "No pending Workloads found")
+		return nil
+	}
+	w := printers.GetNewTabWriter(o.streams.Out)
+	fmt.Fprintln(w, "POSITION\tNAME\tPOSITION IN CLUSTERQUEUE\tPRIORITY\tAGE")
+	for _, pw := range summary.Items {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\n", pw.PositionInLocalQueue, pw.Name,
+			pw.PositionInClusterQueue, pw.Priority, age(pw.CreationTimestamp))
+	}
+	return w.Flush()
+}
+
+func pendingWorkloadsInClusterQueue(ctx context.Context, vc rest.Interface, name string, offset, limit int64) (*visibility.PendingWorkloadsSummary, error) {
+	summary := &visibility.PendingWorkloadsSummary{}
+	err := vc.Get().
+		Resource("clusterqueues").
+		Name(name).
+		SubResource("pendingworkloads").
+		Param("offset", strconv.FormatInt(offset, 10)).
+		Param("limit", strconv.FormatInt(limit, 10)).
+		Do(ctx).
+		Into(summary)
+	return summary, err
+}
+
+func pendingWorkloadsInLocalQueue(ctx context.Context, vc rest.Interface, ns, name string, offset, limit int64) (*visibility.PendingWorkloadsSummary, error) {
+	summary := &visibility.PendingWorkloadsSummary{}
+	err := vc.Get().
+		Namespace(ns).
+		Resource("localqueues").
+		Name(name).
+		SubResource("pendingworkloads").
+		Param("offset", strconv.FormatInt(offset, 10)).
+		Param("limit", strconv.FormatInt(limit, 10)).
+		Do(ctx).
+		Into(summary)
+	return summary, err
+}
+
+// findPendingWorkload looks for the workload among the pending workloads of
+// its LocalQueue. It returns nil if the workload is not pending.
+func findPendingWorkload(ctx context.Context, vc rest.Interface, ns, lqName, name string) (*visibility.PendingWorkload, error) {
+	var offset int64
+	for {
+		summary, err := pendingWorkloadsInLocalQueue(ctx, vc, ns, lqName, offset, visibility.DefaultPendingWorkloadsLimit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range summary.Items {
+			if summary.Items[i].Name == name {
+				return &summary.Items[i], nil
+			}
+		}
+		if len(summary.Items) < visibility.DefaultPendingWorkloadsLimit {
+			return nil, nil
+		}
+		offset += int64(len(summary.Items))
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	visibility "sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestPending(t *testing.T) {
+	pendingWorkload := func(ns, name, lq string, cqPosition, lqPosition, priority int32) visibility.PendingWorkload {
+		return visibility.PendingWorkload{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         ns,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(testNow.Add(-time.Minute)),
+			},
+			LocalQueueName:         lq,
+			PositionInClusterQueue: cqPosition,
+			PositionInLocalQueue:   lqPosition,
+			Priority:               priority,
+		}
+	}
+	responses := map[string]*visibility.PendingWorkloadsSummary{
+		"/apis/visibility.kueue.x-k8s.io/v1alpha1/clusterqueues/cq/pendingworkloads?limit=1000&offset=0": {
+			Items: []visibility.PendingWorkload{
+				pendingWorkload("ns1", "a", "lq", 0, 0, 10),
+				pendingWorkload("ns2", "b", "lq", 1, 0, 5),
+				pendingWorkload("ns1", "c", "lq", 2, 1, 0),
+			},
+		},
+		"/apis/visibility.kueue.x-k8s.io/v1alpha1/clusterqueues/cq/pendingworkloads?limit=1&offset=1": {
+			Items: []visibility.PendingWorkload{
+				pendingWorkload("ns2", "b", "lq", 1, 0, 5),
+			},
+		},
+		"/apis/visibility.kueue.x-k8s.io/v1alpha1/namespaces/ns1/localqueues/lq/pendingworkloads?limit=1000&offset=0": {
+			Items: []visibility.PendingWorkload{
+				pendingWorkload("ns1", "a", "lq", 0, 0, 10),
+				pendingWorkload("ns1", "c", "lq", 2, 1, 0),
+			},
+		},
+		"/apis/visibility.kueue.x-k8s.io/v1alpha1/clusterqueues/empty/pendingworkloads?limit=1000&offset=0": {},
+	}
+	cases := map[string]struct {
+		args       []string
+		wantOut    string
+		wantErrOut string
+		wantErr    bool
+	}{
+		"clusterqueue": {
+			args: []string{"pending", "clusterqueue", "cq"},
+			wantOut: `POSITION   NAMESPACE   NAME   LOCALQUEUE   POSITION IN LOCALQUEUE   PRIORITY   AGE
+0          ns1         a      lq           0                        10         60s
+1          ns2         b      lq           0                        5          60s
+2          ns1         c      lq           1                        0          60s
+`,
+		},
+		"clusterqueue page": {
+			args: []string{"pending", "cq", "cq", "--offset", "1", "--limit", "1"},
+			wantOut: `POSITION   NAMESPACE   NAME   LOCALQUEUE   POSITION IN LOCALQUEUE   PRIORITY   AGE
+1          ns2         b      lq           0                        5          60s
+`,
+		},
+		"empty clusterqueue": {
+			args:       []string{"pending", "clusterqueue", "empty"},
+			wantErrOut: "No pending Workloads found\n",
+		},
+		"localqueue": {
+			args: []string{"pending", "localqueue", "lq"},
+			wantOut: `POSITION   NAME   POSITION IN CLUSTERQUEUE   PRIORITY   AGE
+0          a      0                          10         60s
+1          c      2                          0          60s
+`,
+		},
+		"missing clusterqueue": {
+			args:    []string{"pending", "clusterqueue", "missing"},
+			wantErr: true,
+		},
+		"describe pending workload": {
+			args: []string{"describe", "workload", "c"},
+			wantOut: `Name:                       c
+Namespace:                  ns1
+LocalQueue:                 lq
+Priority Class:             <none>
+Priority:                   0
+Active:                     true
+Status:                     Pending
+Position In ClusterQueue:   2
+Position In LocalQueue:     1
+Age:                        60s
+Pod Sets:
+  NAME   COUNT   FLAVORS
+  main   1       <none>
+`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			getter := &fakeClientGetter{
+				namespace:  "ns1",
+				client:     newFakeClient(creation(utiltesting.MakeWorkload("c", "ns1").Queue("lq").Obj(), time.Minute)),
+				visibility: newFakeVisibilityClient(t, responses),
+			}
+			out, errOut, err := runCmd(t, getter, tc.args...)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Run returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.wantOut, out); diff != "" {
+				t.Errorf("Unexpected output (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantErrOut, errOut); diff != "" {
+				t.Errorf("Unexpected error output (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+const (
+	statusPending       = "Pending"
+	statusQuotaReserved = "QuotaReserved"
+	statusAdmitted      = "Admitted"
+	statusFinished      = "Finished"
+	statusInactive      = "Inactive"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+func age(t metav1.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now().Sub(t.Time))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+func clusterQueueActive(cq *kueue.ClusterQueue) bool {
+	return apimeta.IsStatusConditionTrue(cq.Status.Conditions, kueue.ClusterQueueActive)
+}
+
+// workloadStatus summarizes the state of the workload in its lifecycle.
+func workloadStatus(wl *kueue.Workload) string {
+	switch {
+	case apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished):
+		return statusFinished
+	case workload.IsAdmitted(wl):
+		return statusAdmitted
+	case wl.Spec.Admission != nil:
+		return statusQuotaReserved
+	case !workload.IsActive(wl):
+		return statusInactive
+	default:
+		return statusPending
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+type stopOptions struct {
+	getter  ClientGetter
+	streams genericclioptions.IOStreams
+
+	keepAlreadyRunning bool
+}
+
+func newStopCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &stopOptions{getter: getter, streams: streams}
+	clusterQueueCmd := &cobra.Command{
+		Use:     "clusterqueue NAME",
+		Aliases: []string{"cq"},
+		Short:   "Stops a ClusterQueue",
+		Long: `Stops a ClusterQueue, so that it doesn't admit new Workloads.
+
+By default, the admitted Workloads are evicted too. Use --keep-already-running
+to let them run to completion.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy := kueue.HoldAndDrain
+			if o.keepAlreadyRunning {
+				policy = kueue.Hold
+			}
+			return o.setStopPolicy(cmd.Context(), args[0], policy, "stopped")
+		},
+	}
+	clusterQueueCmd.Flags().BoolVar(&o.keepAlreadyRunning, "keep-already-running", false, "Don't evict the admitted Workloads.")
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stops a queue",
+	}
+	cmd.AddCommand(clusterQueueCmd)
+	return cmd
+}
+
+func newResumeCmd(getter ClientGetter, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &stopOptions{getter: getter, streams: streams}
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resumes a stopped queue",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:     "clusterqueue NAME",
+		Aliases: []string{"cq"},
+		Short:   "Resumes a stopped ClusterQueue",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.setStopPolicy(cmd.Context(), args[0], kueue.None, "resumed")
+		},
+	})
+	return cmd
+}
+
+func (o *stopOptions) setStopPolicy(ctx context.Context, name string, policy kueue.StopPolicy, verb string) error {
+	c, err := o.getter.KueueClient()
+	if err != nil {
+		return err
+	}
+	var cq kueue.ClusterQueue
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, &cq); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(cq.DeepCopy())
+	cq.Spec.StopPolicy = &policy
+	if err := c.Patch(ctx, &cq, patch); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.streams.Out, "clusterqueue.kueue.x-k8s.io/%s %s\n", name, verb)
+	return nil
+}
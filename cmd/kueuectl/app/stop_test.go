@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestStopResume(t *testing.T) {
+	cases := map[string]struct {
+		initial    *kueue.StopPolicy
+		args       []string
+		wantPolicy kueue.StopPolicy
+		wantOut    string
+	}{
+		"stop": {
+			args:       []string{"stop", "clusterqueue", "cq"},
+			wantPolicy: kueue.HoldAndDrain,
+			wantOut:    "clusterqueue.kueue.x-k8s.io/cq stopped\n",
+		},
+		"stop keeping the running workloads": {
+			args:       []string{"stop", "cq", "cq", "--keep-already-running"},
+			wantPolicy: kueue.Hold,
+			wantOut:    "clusterqueue.kueue.x-k8s.io/cq stopped\n",
+		},
+		"resume": {
+			initial:    policyPtr(kueue.HoldAndDrain),
+			args:       []string{"resume", "clusterqueue", "cq"},
+			wantPolicy: kueue.None,
+			wantOut:    "clusterqueue.kueue.x-k8s.io/cq resumed\n",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq := utiltesting.MakeClusterQueue("cq").Obj()
+			cq.Spec.StopPolicy = tc.initial
+			c := newFakeClient(cq)
+			out, _, err := runCmd(t, &fakeClientGetter{client: c}, tc.args...)
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantOut, out); diff != "" {
+				t.Errorf("Unexpected output (-want,+got):\n%s", diff)
+			}
+			var got kueue.ClusterQueue
+			if err := c.Get(context.Background(), types.NamespacedName{Name: "cq"}, &got); err != nil {
+				t.Fatalf("Getting ClusterQueue: %v", err)
+			}
+			if diff := cmp.Diff(&tc.wantPolicy, got.Spec.StopPolicy); diff != "" {
+				t.Errorf("Unexpected stopPolicy (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStopMissingClusterQueue(t *testing.T) {
+	if _, _, err := runCmd(t, &fakeClientGetter{client: newFakeClient()}, "stop", "clusterqueue", "cq"); err == nil {
+		t.Error("Run succeeded, want error")
+	}
+}
+
+func policyPtr(p kueue.StopPolicy) *kueue.StopPolicy {
+	return &p
+}
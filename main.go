@@ -19,8 +19,11 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -29,10 +32,15 @@ import (
 
 	zaplog "go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -41,16 +49,27 @@ import (
 	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/apis/kueue/webhooks"
+	"sigs.k8s.io/kueue/pkg/accounting"
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/admissionchecks/provisioning"
 	"sigs.k8s.io/kueue/pkg/controller/core"
-	"sigs.k8s.io/kueue/pkg/controller/workload/job"
+	"sigs.k8s.io/kueue/pkg/controller/workload/deployment"
+	"sigs.k8s.io/kueue/pkg/controller/workload/externaljob"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+	_ "sigs.k8s.io/kueue/pkg/controller/workload/jobs"
+	"sigs.k8s.io/kueue/pkg/controller/workload/pod"
+	"sigs.k8s.io/kueue/pkg/controller/workload/statefulset"
 	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
 	"sigs.k8s.io/kueue/pkg/scheduler"
 	"sigs.k8s.io/kueue/pkg/util/cert"
+	"sigs.k8s.io/kueue/pkg/util/logging"
+	"sigs.k8s.io/kueue/pkg/util/pprof"
 	"sigs.k8s.io/kueue/pkg/util/useragent"
 	"sigs.k8s.io/kueue/pkg/version"
+	"sigs.k8s.io/kueue/pkg/visibility"
+	"sigs.k8s.io/kueue/pkg/workload"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -81,10 +100,43 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	// The logger can only be set once, so the logging configuration is read
+	// before the rest of the configuration, which is logged once it's loaded.
+	loggingOpts, err := loggerOptions(configFile)
+	if err != nil {
+		ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+		setupLog.Error(err, "Invalid logging configuration")
+		os.Exit(1)
+	}
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts), loggingOpts))
 	setupLog.Info("Initializing", "gitVersion", version.GitVersion, "gitCommit", version.GitCommit)
 
 	options, cfg := apply(configFile)
+	externalGVKs, err := externalFrameworks(&cfg)
+	if err != nil {
+		setupLog.Error(err, "Invalid external frameworks")
+		os.Exit(1)
+	}
+	infoOpts, err := workloadInfoOptions(&cfg)
+	if err != nil {
+		setupLog.Error(err, "Invalid resources configuration")
+		os.Exit(1)
+	}
+	shards, err := schedulerShards(&cfg)
+	if err != nil {
+		setupLog.Error(err, "Invalid scheduler configuration")
+		os.Exit(1)
+	}
+	cohortConcurrency, err := schedulerCohortConcurrency(&cfg)
+	if err != nil {
+		setupLog.Error(err, "Invalid scheduler configuration")
+		os.Exit(1)
+	}
+	admissionPatchWorkers, err := schedulerAdmissionPatchWorkers(&cfg)
+	if err != nil {
+		setupLog.Error(err, "Invalid scheduler configuration")
+		os.Exit(1)
+	}
 
 	metrics.Register()
 
@@ -112,17 +164,18 @@ func main() {
 		close(certsReady)
 	}
 
-	cCache := cache.New(mgr.GetClient(), cache.WithPodsReadyTracking(waitForPodsReady(&cfg)))
-	queues := queue.NewManager(mgr.GetClient(), cCache)
+	cCache := cache.New(mgr.GetClient(), cache.WithPodsReadyTracking(waitForPodsReady(&cfg)), cache.WithNodeTracking(validateNodes(&cfg)), cache.WithTopologyTracking(topologyAwareScheduling(&cfg)), cache.WithQuotaAutoSizing(quotaAutoSizing(&cfg)), cache.WithWorkloadInfoOptions(infoOpts...))
+	queues := queue.NewManager(mgr.GetClient(), cCache, queue.WithWorkloadInfoOptions(infoOpts...), queue.WithShards(shards))
 
 	ctx := ctrl.SetupSignalHandler()
-	setupIndexes(ctx, mgr)
+	setupIndexes(ctx, mgr, &cfg, externalGVKs)
 
-	setupProbeEndpoints(mgr)
+	setupProbeEndpoints(mgr, queues)
+	setupPprof(mgr, &cfg)
 	// Cert won't be ready until manager starts, so start a goroutine here which
 	// will block until the cert is ready before setting up the controllers.
 	// Controllers who register after manager starts will start directly.
-	go setupControllers(mgr, cCache, queues, certsReady, &cfg)
+	go setupControllers(mgr, cCache, queues, certsReady, &cfg, externalGVKs)
 
 	go func() {
 		queues.CleanUpOnContext(ctx)
@@ -131,7 +184,9 @@ func main() {
 		cCache.CleanUpOnContext(ctx)
 	}()
 
-	setupScheduler(ctx, mgr, cCache, queues, &cfg)
+	sched := setupScheduler(mgr, cCache, queues, &cfg, infoOpts, cohortConcurrency, admissionPatchWorkers)
+	setupVisibilityServer(mgr, queues, sched, &cfg)
+	setupAccounting(mgr, &cfg)
 
 	setupLog.Info("Starting manager")
 	if err := mgr.Start(ctx); err != nil {
@@ -140,19 +195,33 @@ func main() {
 	}
 }
 
-func setupIndexes(ctx context.Context, mgr ctrl.Manager) {
+func setupIndexes(ctx context.Context, mgr ctrl.Manager, cfg *config.Configuration, externalGVKs []schema.GroupVersionKind) {
 	if err := queue.SetupIndexes(ctx, mgr.GetFieldIndexer()); err != nil {
 		setupLog.Error(err, "Unable to setup queue indexes")
 	}
 	if err := cache.SetupIndexes(ctx, mgr.GetFieldIndexer()); err != nil {
 		setupLog.Error(err, "Unable to setup cache indexes")
 	}
-	if err := job.SetupIndexes(ctx, mgr.GetFieldIndexer()); err != nil {
+	enabled := sets.NewString(enabledFrameworks(cfg)...)
+	if err := jobframework.ForEachIntegration(func(name string, cb jobframework.IntegrationCallbacks) error {
+		if !enabled.Has(name) {
+			return nil
+		}
+		if err := cb.SetupIndexes(ctx, mgr.GetFieldIndexer()); err != nil {
+			return fmt.Errorf("integration %s: %w", name, err)
+		}
+		return nil
+	}); err != nil {
 		setupLog.Error(err, "Unable to setup job indexes")
 	}
+	for _, gvk := range externalGVKs {
+		if err := jobframework.SetupWorkloadOwnerIndex(ctx, mgr.GetFieldIndexer(), gvk); err != nil {
+			setupLog.Error(err, "Unable to setup external job indexes", "kind", gvk)
+		}
+	}
 }
 
-func setupControllers(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, certsReady chan struct{}, cfg *config.Configuration) {
+func setupControllers(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, certsReady chan struct{}, cfg *config.Configuration, externalGVKs []schema.GroupVersionKind) {
 	// The controllers won't work until the webhooks are operating, and the webhook won't work until the
 	// certs are all in place.
 	setupLog.Info("Waiting for certificate generation to complete")
@@ -164,28 +233,91 @@ func setupControllers(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manag
 		os.Exit(1)
 	}
 	manageJobsWithoutQueueName := cfg.ManageJobsWithoutQueueName
-	if err := job.NewReconciler(mgr.GetScheme(),
-		mgr.GetClient(),
-		mgr.GetEventRecorderFor(constants.JobControllerName),
-		job.WithManageJobsWithoutQueueName(manageJobsWithoutQueueName),
-		job.WithWaitForPodsReady(waitForPodsReady(cfg)),
-	).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Job")
+	managedJobsNamespaceSelector, err := metav1.LabelSelectorAsSelector(cfg.ManagedJobsNamespaceSelector)
+	if err != nil {
+		setupLog.Error(err, "Invalid managedJobsNamespaceSelector")
 		os.Exit(1)
 	}
-	if failedWebhook, err := webhooks.Setup(mgr); err != nil {
+	if podIntegration(cfg) {
+		if err := pod.NewReconciler(mgr.GetScheme(),
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor(constants.PodControllerName),
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Pod")
+			os.Exit(1)
+		}
+	}
+	for _, name := range enabledFrameworks(cfg) {
+		cb, found := jobframework.GetIntegration(name)
+		if !found {
+			setupLog.Error(nil, "Unknown job framework", "framework", name)
+			os.Exit(1)
+		}
+		if err := cb.SetupController(mgr,
+			jobframework.WithManageJobsWithoutQueueName(manageJobsWithoutQueueName),
+			jobframework.WithManagedJobsNamespaceSelector(managedJobsNamespaceSelector),
+			jobframework.WithWaitForPodsReady(waitForPodsReady(cfg)),
+			jobframework.WithDeactivatedJobPolicy(deactivatedJobPolicy(cfg)),
+			jobframework.WithAdmissionScope(admissionScope(cfg)),
+		); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", name)
+			os.Exit(1)
+		}
+	}
+	for _, gvk := range externalGVKs {
+		if err := jobframework.SetupController(mgr, externaljob.NewJobFunc(gvk),
+			jobframework.WithWaitForPodsReady(waitForPodsReady(cfg)),
+			jobframework.WithDeactivatedJobPolicy(deactivatedJobPolicy(cfg)),
+		); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", gvk.String())
+			os.Exit(1)
+		}
+	}
+	if provisioningRequest(cfg) {
+		if err := provisioning.NewController(mgr.GetClient(), mgr.GetScheme()).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ProvisioningRequest")
+			os.Exit(1)
+		}
+	}
+	if failedWebhook, err := webhooks.Setup(mgr,
+		webhookOptions(cfg, cCache)...,
+	); err != nil {
 		setupLog.Error(err, "Unable to create webhook", "webhook", failedWebhook)
 		os.Exit(1)
 	}
-	if err := job.SetupWebhook(mgr, job.WithManageJobsWithoutQueueName(manageJobsWithoutQueueName)); err != nil {
-		setupLog.Error(err, "Unable to create webhook", "webhook", "Job")
+	if err := pod.SetupWebhook(mgr, pod.WithEnabled(podIntegration(cfg))); err != nil {
+		setupLog.Error(err, "Unable to create webhook", "webhook", "Pod")
+		os.Exit(1)
+	}
+	if err := deployment.SetupWebhook(mgr, deployment.WithEnabled(podIntegration(cfg))); err != nil {
+		setupLog.Error(err, "Unable to create webhook", "webhook", "Deployment")
+		os.Exit(1)
+	}
+	if err := statefulset.SetupWebhook(mgr, statefulset.WithEnabled(podIntegration(cfg))); err != nil {
+		setupLog.Error(err, "Unable to create webhook", "webhook", "StatefulSet")
+		os.Exit(1)
+	}
+	enabled := sets.NewString(enabledFrameworks(cfg)...)
+	if err := jobframework.ForEachIntegration(func(name string, cb jobframework.IntegrationCallbacks) error {
+		if err := cb.SetupWebhook(mgr,
+			jobframework.WithEnabled(enabled.Has(name)),
+			jobframework.WithManageJobsWithoutQueueName(manageJobsWithoutQueueName),
+			jobframework.WithManagedJobsNamespaceSelector(managedJobsNamespaceSelector),
+			jobframework.WithDefaultQueueName(cfg.DefaultLocalQueue),
+			jobframework.WithAdmissionScope(admissionScope(cfg)),
+		); err != nil {
+			return fmt.Errorf("integration %s: %w", name, err)
+		}
+		return nil
+	}); err != nil {
+		setupLog.Error(err, "Unable to create webhook")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 }
 
 // setupProbeEndpoints registers the health endpoints
-func setupProbeEndpoints(mgr ctrl.Manager) {
+func setupProbeEndpoints(mgr ctrl.Manager, queues *queue.Manager) {
 	defer setupLog.Info("Probe endpoints are configured on healthz and readyz")
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -196,23 +328,268 @@ func setupProbeEndpoints(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("queues", leaderOnlyCheck(mgr.Elected(), queues.ReadyzCheck())); err != nil {
+		setupLog.Error(err, "unable to set up queues ready check")
+		os.Exit(1)
+	}
 }
 
-func setupScheduler(ctx context.Context, mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, cfg *config.Configuration) {
+// leaderOnlyCheck returns a checker that only runs the given check once the
+// replica is elected as the leader. The controllers that fill the queues
+// only run in the leader, so the other replicas would never be ready.
+func leaderOnlyCheck(elected <-chan struct{}, check healthz.Checker) healthz.Checker {
+	return func(req *http.Request) error {
+		select {
+		case <-elected:
+			return check(req)
+		default:
+			return nil
+		}
+	}
+}
+
+func setupPprof(mgr ctrl.Manager, cfg *config.Configuration) {
+	if cfg.Pprof == nil || !cfg.Pprof.Enable {
+		return
+	}
+	if err := mgr.Add(pprof.NewServer(*cfg.Pprof.BindAddress)); err != nil {
+		setupLog.Error(err, "Unable to set up the pprof server")
+		os.Exit(1)
+	}
+}
+
+func setupScheduler(mgr ctrl.Manager, cCache *cache.Cache, queues *queue.Manager, cfg *config.Configuration, infoOpts []workload.InfoOption, cohortConcurrency, admissionPatchWorkers int) *scheduler.Scheduler {
+	opts := []scheduler.Option{
+		scheduler.WithWaitForPodsReady(waitForPodsReady(cfg)),
+		scheduler.WithWorkloadInfoOptions(infoOpts...),
+		scheduler.WithCohortConcurrency(cohortConcurrency),
+		scheduler.WithAdmissionPatchWorkers(admissionPatchWorkers),
+	}
+	if cfg.Scheduler != nil && cfg.Scheduler.FieldManager != nil {
+		opts = append(opts, scheduler.WithFieldManager(*cfg.Scheduler.FieldManager))
+	}
+	if b := cfg.RequeuingBackoff; b != nil && b.Enable {
+		opts = append(opts, scheduler.WithRequeuingBackoff(b.BaseDelay.Duration, b.MaxDelay.Duration, *b.Jitter))
+	}
+	if a := cfg.AdmissionAudit; a != nil && a.Enable {
+		var decisionLog io.Writer
+		if a.DecisionLogPath != nil {
+			f, err := os.OpenFile(*a.DecisionLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				setupLog.Error(err, "Unable to open the decision log")
+				os.Exit(1)
+			}
+			decisionLog = f
+		}
+		opts = append(opts, scheduler.WithAdmissionAudit(decisionLog))
+	}
+	if le := cfg.LeaderElection; le != nil && le.LeaderElect != nil && *le.LeaderElect {
+		lease := types.NamespacedName{Namespace: le.ResourceNamespace, Name: le.ResourceName}
+		opts = append(opts, scheduler.WithLeaderElectionFencing(mgr.GetAPIReader(), lease))
+	}
 	sched := scheduler.New(
 		queues,
 		cCache,
 		mgr.GetClient(),
 		mgr.GetEventRecorderFor(constants.AdmissionName),
-		scheduler.WithWaitForPodsReady(waitForPodsReady(cfg)),
+		opts...,
 	)
-	go sched.Start(ctx)
+	if err := mgr.Add(sched); err != nil {
+		setupLog.Error(err, "Unable to set up the scheduler")
+		os.Exit(1)
+	}
+	return sched
+}
+
+func setupVisibilityServer(mgr ctrl.Manager, queues *queue.Manager, sched *scheduler.Scheduler, cfg *config.Configuration) {
+	if cfg.VisibilityServer == nil || !cfg.VisibilityServer.Enable {
+		return
+	}
+	if err := mgr.Add(visibility.NewServer(queues, sched, int(*cfg.VisibilityServer.Port))); err != nil {
+		setupLog.Error(err, "Unable to set up the visibility server")
+		os.Exit(1)
+	}
+}
+
+func setupAccounting(mgr ctrl.Manager, cfg *config.Configuration) {
+	if cfg.Accounting == nil || !cfg.Accounting.Enable {
+		return
+	}
+	sink, err := accounting.NewSink(mgr.GetClient(), *cfg.Namespace, &cfg.Accounting.Sink)
+	if err != nil {
+		setupLog.Error(err, "Invalid accounting sink")
+		os.Exit(1)
+	}
+	if err := mgr.Add(accounting.NewExporter(mgr.GetClient(), sink, cfg.Accounting.Interval.Duration)); err != nil {
+		setupLog.Error(err, "Unable to set up the accounting exporter")
+		os.Exit(1)
+	}
 }
 
 func waitForPodsReady(cfg *config.Configuration) bool {
 	return cfg.WaitForPodsReady != nil && cfg.WaitForPodsReady.Enable
 }
 
+func deactivatedJobPolicy(cfg *config.Configuration) config.DeactivatedJobPolicy {
+	if cfg.RequeuingBackoff != nil && cfg.RequeuingBackoff.Enable && cfg.RequeuingBackoff.DeactivatedJobPolicy != nil {
+		return *cfg.RequeuingBackoff.DeactivatedJobPolicy
+	}
+	return config.DeactivatedJobKeep
+}
+
+func admissionScope(cfg *config.Configuration) []string {
+	if cfg.AdmissionScope == nil {
+		return nil
+	}
+	return cfg.AdmissionScope.ResourcePrefixes
+}
+
+func validateNodes(cfg *config.Configuration) bool {
+	return cfg.ExtendedResources != nil && cfg.ExtendedResources.ValidateNodes
+}
+
+func validateNamespaceSelector(cfg *config.Configuration) bool {
+	return cfg.LocalQueueValidation != nil && cfg.LocalQueueValidation.NamespaceSelector
+}
+
+func webhookOptions(cfg *config.Configuration, cCache *cache.Cache) []webhooks.Option {
+	opts := []webhooks.Option{
+		webhooks.WithNamespaceSelectorValidation(validateNamespaceSelector(cfg)),
+	}
+	if cfg.ClusterQueueValidation != nil && cfg.ClusterQueueValidation.QuotaUpdateWarnings {
+		opts = append(opts, webhooks.WithQuotaUpdateWarnings(cCache))
+	}
+	return opts
+}
+
+// schedulerShards returns the number of shards of the ClusterQueues.
+func schedulerShards(cfg *config.Configuration) (int, error) {
+	if cfg.Scheduler == nil {
+		return 1, nil
+	}
+	return schedulerConcurrency(cfg, "shards", cfg.Scheduler.Shards)
+}
+
+// schedulerCohortConcurrency returns the number of hierarchies of cohorts
+// that are scheduled concurrently in each cycle.
+func schedulerCohortConcurrency(cfg *config.Configuration) (int, error) {
+	if cfg.Scheduler == nil {
+		return 1, nil
+	}
+	return schedulerConcurrency(cfg, "cohortConcurrency", cfg.Scheduler.CohortConcurrency)
+}
+
+// schedulerAdmissionPatchWorkers returns the maximum number of admission
+// patches that the scheduler applies concurrently.
+func schedulerAdmissionPatchWorkers(cfg *config.Configuration) (int, error) {
+	if cfg.Scheduler == nil || cfg.Scheduler.AdmissionPatchWorkers == nil {
+		return scheduler.DefaultAdmissionPatchWorkers, nil
+	}
+	n := int(*cfg.Scheduler.AdmissionPatchWorkers)
+	if n < 1 {
+		return 0, fmt.Errorf("admissionPatchWorkers must be at least 1, got %d", n)
+	}
+	return n, nil
+}
+
+// schedulerConcurrency validates a setting of the concurrency of the
+// scheduler, which defaults to 1 and can only be greater than 1 if the
+// admissions in different cohorts don't depend on each other.
+func schedulerConcurrency(cfg *config.Configuration, name string, value *int32) (int, error) {
+	if value == nil {
+		return 1, nil
+	}
+	n := int(*value)
+	if n < 1 {
+		return 0, fmt.Errorf("%s must be at least 1, got %d", name, n)
+	}
+	if n > 1 && (waitForPodsReady(cfg) || topologyAwareScheduling(cfg)) {
+		return 0, fmt.Errorf("%s can't be greater than 1 with waitForPodsReady or topologyAwareScheduling enabled", name)
+	}
+	return n, nil
+}
+
+func topologyAwareScheduling(cfg *config.Configuration) bool {
+	return cfg.TopologyAwareScheduling != nil && cfg.TopologyAwareScheduling.Enable
+}
+
+func quotaAutoSizing(cfg *config.Configuration) bool {
+	return cfg.QuotaAutoSizing != nil && cfg.QuotaAutoSizing.Enable
+}
+
+func podIntegration(cfg *config.Configuration) bool {
+	return cfg.PodIntegration != nil && cfg.PodIntegration.Enable
+}
+
+func provisioningRequest(cfg *config.Configuration) bool {
+	return cfg.ProvisioningRequest != nil && cfg.ProvisioningRequest.Enable
+}
+
+func enabledFrameworks(cfg *config.Configuration) []string {
+	if cfg.Integrations == nil {
+		return nil
+	}
+	return cfg.Integrations.Frameworks
+}
+
+// externalFrameworks returns the kinds of the external frameworks in the
+// configuration.
+func externalFrameworks(cfg *config.Configuration) ([]schema.GroupVersionKind, error) {
+	if cfg.Integrations == nil {
+		return nil, nil
+	}
+	gvks := make([]schema.GroupVersionKind, 0, len(cfg.Integrations.ExternalFrameworks))
+	for _, s := range cfg.Integrations.ExternalFrameworks {
+		gvk, err := externaljob.ParseGVK(s)
+		if err != nil {
+			return nil, err
+		}
+		gvks = append(gvks, gvk)
+	}
+	return gvks, nil
+}
+
+// workloadInfoOptions returns the options to compute the requests of the
+// workloads from the resources configuration.
+func workloadInfoOptions(cfg *config.Configuration) ([]workload.InfoOption, error) {
+	if cfg.Resources == nil {
+		return nil, nil
+	}
+	for _, p := range cfg.Resources.ExcludeResourcePrefixes {
+		if p == "" {
+			return nil, errors.New("empty prefix in excludeResourcePrefixes")
+		}
+	}
+	transformations, err := resourceTransformations(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return []workload.InfoOption{
+		workload.WithExcludedResourcePrefixes(cfg.Resources.ExcludeResourcePrefixes),
+		workload.WithResourceTransformations(transformations),
+	}, nil
+}
+
+// resourceTransformations returns the resource transformations in the
+// configuration, after checking that each input resource has at most one
+// transformation, with a known strategy.
+func resourceTransformations(cfg *config.Configuration) ([]config.ResourceTransformation, error) {
+	if cfg.Resources == nil {
+		return nil, nil
+	}
+	inputs := sets.New[corev1.ResourceName]()
+	for _, t := range cfg.Resources.Transformations {
+		if inputs.Has(t.Input) {
+			return nil, fmt.Errorf("duplicate transformation for resource %s", t.Input)
+		}
+		inputs.Insert(t.Input)
+		if t.Strategy != nil && *t.Strategy != config.Retain && *t.Strategy != config.Replace {
+			return nil, fmt.Errorf("unknown strategy %q for the transformation of resource %s", *t.Strategy, t.Input)
+		}
+	}
+	return cfg.Resources.Transformations, nil
+}
+
 func encodeConfig(cfg *config.Configuration) (string, error) {
 	codecs := serializer.NewCodecFactory(scheme)
 	const mediaType = runtime.ContentTypeYAML
@@ -229,6 +606,106 @@ func encodeConfig(cfg *config.Configuration) (string, error) {
 	return buf.String(), nil
 }
 
+// decodeConfigFile decodes the configuration file in strict mode, so that the
+// unknown and duplicated fields, which the manager would otherwise ignore, are
+// reported instead of silently falling back to the defaults.
+func decodeConfigFile(configFile string) (*config.Configuration, error) {
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	codecs := serializer.NewCodecFactory(scheme, serializer.EnableStrict)
+	cfg := &config.Configuration{}
+	if err := runtime.DecodeInto(codecs.UniversalDecoder(), content, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loggerOptions returns the option that overrides the zap flags with the
+// logging configuration from the configuration file, if any.
+func loggerOptions(configFile string) (zap.Opts, error) {
+	if configFile == "" {
+		return func(*zap.Options) {}, nil
+	}
+	cfg, err := decodeConfigFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return loggingOptions(cfg.Logging)
+}
+
+func loggingOptions(logCfg *config.Logging) (zap.Opts, error) {
+	if logCfg == nil {
+		return func(*zap.Options) {}, nil
+	}
+	var newEncoder zap.NewEncoderFunc
+	if logCfg.Format != nil {
+		switch *logCfg.Format {
+		case config.LoggingFormatJSON:
+			newEncoder = func(opts ...zap.EncoderConfigOption) zapcore.Encoder {
+				return zapcore.NewJSONEncoder(encoderConfig(zaplog.NewProductionEncoderConfig(), opts))
+			}
+		case config.LoggingFormatConsole:
+			newEncoder = func(opts ...zap.EncoderConfigOption) zapcore.Encoder {
+				return zapcore.NewConsoleEncoder(encoderConfig(zaplog.NewDevelopmentEncoderConfig(), opts))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported logging format %q, must be %q or %q", *logCfg.Format, config.LoggingFormatJSON, config.LoggingFormatConsole)
+		}
+	}
+	if logCfg.Verbosity != nil && *logCfg.Verbosity < 0 {
+		return nil, fmt.Errorf("logging verbosity must be at least 0, got %d", *logCfg.Verbosity)
+	}
+	levels := make(map[string]zapcore.Level, len(logCfg.LoggerVerbosity))
+	for name, v := range logCfg.LoggerVerbosity {
+		if name == "" || v < 0 {
+			return nil, fmt.Errorf("logger verbosity must have a name and be at least 0, got %q: %d", name, v)
+		}
+		levels[name] = zapcore.Level(-v)
+	}
+	return func(o *zap.Options) {
+		if newEncoder != nil {
+			o.NewEncoder = newEncoder
+		}
+		if logCfg.Verbosity == nil && len(levels) == 0 {
+			return
+		}
+		level := zapcore.InfoLevel
+		switch {
+		case logCfg.Verbosity != nil:
+			level = zapcore.Level(-*logCfg.Verbosity)
+		case o.Level != nil:
+			level = zapcore.LevelOf(o.Level)
+		case o.Development:
+			level = zapcore.DebugLevel
+		}
+		if len(levels) == 0 {
+			o.Level = zaplog.NewAtomicLevelAt(level)
+			return
+		}
+		// The core of the logger needs to be enabled for the lowest of the
+		// levels, the wrapping core drops the entries of the other loggers.
+		min := level
+		for _, l := range levels {
+			if l < min {
+				min = l
+			}
+		}
+		o.Level = zaplog.NewAtomicLevelAt(min)
+		o.ZapOpts = append(o.ZapOpts, zaplog.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return logging.WithNamedLevels(c, level, levels)
+		}))
+	}, nil
+}
+
+func encoderConfig(ecfg zapcore.EncoderConfig, opts []zap.EncoderConfigOption) zapcore.EncoderConfig {
+	for _, opt := range opts {
+		opt(&ecfg)
+	}
+	return ecfg
+}
+
 func apply(configFile string) (ctrl.Options, config.Configuration) {
 	var err error
 	options := ctrl.Options{
@@ -239,7 +716,7 @@ func apply(configFile string) (ctrl.Options, config.Configuration) {
 	if configFile == "" {
 		scheme.Default(&cfg)
 		options, err = options.AndFrom(&cfg)
-	} else {
+	} else if _, err = decodeConfigFile(configFile); err == nil {
 		options, err = options.AndFrom(ctrl.ConfigFile().AtPath(configFile).OfKind(&cfg))
 	}
 	if err != nil {
@@ -17,18 +17,28 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/scheduler"
 )
 
 func TestApply(t *testing.T) {
@@ -69,6 +79,9 @@ metrics:
 leaderElection:
   leaderElect: true
   resourceName: test-id
+  leaseDuration: 1m
+  renewDeadline: 40s
+  retryPeriod: 5s
 webhook:
   port: 9444
 `), os.FileMode(0600)); err != nil {
@@ -165,14 +178,71 @@ clientConnection:
 		t.Fatal(err)
 	}
 
+	controllerConcurrencyConfig := filepath.Join(tmpDir, "controller-concurrency.yaml")
+	if err := os.WriteFile(controllerConcurrencyConfig, []byte(`
+apiVersion: config.kueue.x-k8s.io/v1alpha2
+kind: Configuration
+namespace: kueue-system
+health:
+  healthProbeBindAddress: :8081
+metrics:
+  bindAddress: :8080
+leaderElection:
+  leaderElect: true
+  resourceName: c1f6bfd2.kueue.x-k8s.io
+webhook:
+  port: 9443
+controller:
+  groupKindConcurrency:
+    Job.batch: 10
+    Workload.kueue.x-k8s.io: 5
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	resourcesConfig := filepath.Join(tmpDir, "resources.yaml")
+	if err := os.WriteFile(resourcesConfig, []byte(`
+apiVersion: config.kueue.x-k8s.io/v1alpha2
+kind: Configuration
+namespace: kueue-system
+health:
+  healthProbeBindAddress: :8081
+metrics:
+  bindAddress: :8080
+leaderElection:
+  leaderElect: true
+  resourceName: c1f6bfd2.kueue.x-k8s.io
+webhook:
+  port: 9443
+resources:
+  excludeResourcePrefixes:
+  - hugepages-
+  transformations:
+  - input: nvidia.com/mig-1g.5gb
+    strategy: Replace
+    outputs:
+      nvidia.com/gpu: 500m
+  - input: ephemeral-storage
+`), os.FileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
 	defaultControlOptions := ctrl.Options{
-		Port:                   config.DefaultWebhookPort,
-		HealthProbeBindAddress: config.DefaultHealthProbeBindAddress,
-		MetricsBindAddress:     config.DefaultMetricsBindAddress,
-		LeaderElectionID:       config.DefaultLeaderElectionID,
-		LeaderElection:         true,
+		Port:                       config.DefaultWebhookPort,
+		HealthProbeBindAddress:     config.DefaultHealthProbeBindAddress,
+		MetricsBindAddress:         config.DefaultMetricsBindAddress,
+		LeaderElectionID:           config.DefaultLeaderElectionID,
+		LeaderElection:             true,
+		LeaderElectionResourceLock: "leases",
+		LeaderElectionNamespace:    config.DefaultNamespace,
+		LeaseDuration:              durationPtr(15 * time.Second),
+		RenewDeadline:              durationPtr(10 * time.Second),
+		RetryPeriod:                durationPtr(2 * time.Second),
 	}
 
+	tenantControlOptions := defaultControlOptions
+	tenantControlOptions.LeaderElectionNamespace = "kueue-tenant-a"
+
 	enableDefaultInternalCertManagement := &config.InternalCertManagement{
 		Enable:             pointer.Bool(true),
 		WebhookServiceName: pointer.String(config.DefaultWebhookServiceName),
@@ -193,6 +263,10 @@ clientConnection:
 		Burst: pointer.Int32(config.DefaultClientConnectionBurst),
 	}
 
+	defaultIntegrations := &config.Integrations{
+		Frameworks: []string{config.DefaultJobFrameworkName},
+	}
+
 	testcases := []struct {
 		name              string
 		configFile        string
@@ -203,9 +277,11 @@ clientConnection:
 			name:       "default config",
 			configFile: "",
 			wantConfiguration: config.Configuration{
-				Namespace:              pointer.String(config.DefaultNamespace),
-				InternalCertManagement: enableDefaultInternalCertManagement,
-				ClientConnection:       defaultClientConnection,
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				InternalCertManagement:       enableDefaultInternalCertManagement,
+				ClientConnection:             defaultClientConnection,
+				Integrations:                 defaultIntegrations,
 			},
 			wantOptions: ctrl.Options{
 				Port:                   config.DefaultWebhookPort,
@@ -223,12 +299,14 @@ clientConnection:
 					APIVersion: config.GroupVersion.String(),
 					Kind:       "Configuration",
 				},
-				Namespace:                  pointer.String("kueue-tenant-a"),
-				ManageJobsWithoutQueueName: false,
-				InternalCertManagement:     enableDefaultInternalCertManagement,
-				ClientConnection:           defaultClientConnection,
+				Namespace:                    pointer.String("kueue-tenant-a"),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector("kueue-tenant-a"),
+				ManageJobsWithoutQueueName:   false,
+				InternalCertManagement:       enableDefaultInternalCertManagement,
+				ClientConnection:             defaultClientConnection,
+				Integrations:                 defaultIntegrations,
 			},
-			wantOptions: defaultControlOptions,
+			wantOptions: tenantControlOptions,
 		},
 		{
 			name:       "ControllerManagerConfigurationSpec overwrite config",
@@ -238,17 +316,24 @@ clientConnection:
 					APIVersion: config.GroupVersion.String(),
 					Kind:       "Configuration",
 				},
-				Namespace:                  pointer.String(config.DefaultNamespace),
-				ManageJobsWithoutQueueName: false,
-				InternalCertManagement:     enableDefaultInternalCertManagement,
-				ClientConnection:           defaultClientConnection,
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				ManageJobsWithoutQueueName:   false,
+				InternalCertManagement:       enableDefaultInternalCertManagement,
+				ClientConnection:             defaultClientConnection,
+				Integrations:                 defaultIntegrations,
 			},
 			wantOptions: ctrl.Options{
-				HealthProbeBindAddress: ":38081",
-				MetricsBindAddress:     ":38080",
-				Port:                   9444,
-				LeaderElection:         true,
-				LeaderElectionID:       "test-id",
+				HealthProbeBindAddress:     ":38081",
+				MetricsBindAddress:         ":38080",
+				Port:                       9444,
+				LeaderElection:             true,
+				LeaderElectionID:           "test-id",
+				LeaderElectionResourceLock: "leases",
+				LeaderElectionNamespace:    config.DefaultNamespace,
+				LeaseDuration:              durationPtr(time.Minute),
+				RenewDeadline:              durationPtr(40 * time.Second),
+				RetryPeriod:                durationPtr(5 * time.Second),
 			},
 		},
 		{
@@ -259,14 +344,16 @@ clientConnection:
 					APIVersion: config.GroupVersion.String(),
 					Kind:       "Configuration",
 				},
-				Namespace:                  pointer.String(config.DefaultNamespace),
-				ManageJobsWithoutQueueName: false,
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				ManageJobsWithoutQueueName:   false,
 				InternalCertManagement: &config.InternalCertManagement{
 					Enable:             pointer.Bool(true),
 					WebhookServiceName: pointer.String("kueue-tenant-a-webhook-service"),
 					WebhookSecretName:  pointer.String("kueue-tenant-a-webhook-server-cert"),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 			wantOptions: defaultControlOptions,
 		},
@@ -278,12 +365,14 @@ clientConnection:
 					APIVersion: config.GroupVersion.String(),
 					Kind:       "Configuration",
 				},
-				Namespace:                  pointer.String(config.DefaultNamespace),
-				ManageJobsWithoutQueueName: false,
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				ManageJobsWithoutQueueName:   false,
 				InternalCertManagement: &config.InternalCertManagement{
 					Enable: pointer.Bool(false),
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 			wantOptions: defaultControlOptions,
 		},
@@ -295,10 +384,12 @@ clientConnection:
 					APIVersion: config.GroupVersion.String(),
 					Kind:       "Configuration",
 				},
-				Namespace:                  pointer.String("kueue-system"),
-				ManageJobsWithoutQueueName: false,
-				InternalCertManagement:     enableDefaultInternalCertManagement,
-				ClientConnection:           defaultClientConnection,
+				Namespace:                    pointer.String("kueue-system"),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector("kueue-system"),
+				ManageJobsWithoutQueueName:   false,
+				InternalCertManagement:       enableDefaultInternalCertManagement,
+				ClientConnection:             defaultClientConnection,
+				Integrations:                 defaultIntegrations,
 			},
 			wantOptions: ctrl.Options{
 				Port:                   config.DefaultWebhookPort,
@@ -316,14 +407,16 @@ clientConnection:
 					APIVersion: config.GroupVersion.String(),
 					Kind:       "Configuration",
 				},
-				Namespace:                  pointer.String(config.DefaultNamespace),
-				ManageJobsWithoutQueueName: false,
-				InternalCertManagement:     enableDefaultInternalCertManagement,
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				ManageJobsWithoutQueueName:   false,
+				InternalCertManagement:       enableDefaultInternalCertManagement,
 				WaitForPodsReady: &config.WaitForPodsReady{
 					Enable:  true,
 					Timeout: &metav1.Duration{Duration: 5 * time.Minute},
 				},
 				ClientConnection: defaultClientConnection,
+				Integrations:     defaultIntegrations,
 			},
 			wantOptions: ctrl.Options{
 				Port:                   config.DefaultWebhookPort,
@@ -339,13 +432,82 @@ clientConnection:
 					APIVersion: config.GroupVersion.String(),
 					Kind:       "Configuration",
 				},
-				Namespace:                  pointer.String(config.DefaultNamespace),
-				ManageJobsWithoutQueueName: false,
-				InternalCertManagement:     enableDefaultInternalCertManagement,
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				ManageJobsWithoutQueueName:   false,
+				InternalCertManagement:       enableDefaultInternalCertManagement,
 				ClientConnection: &config.ClientConnection{
 					QPS:   pointer.Float32(50),
 					Burst: pointer.Int32(100),
 				},
+				Integrations: defaultIntegrations,
+			},
+			wantOptions: defaultControlOptions,
+		},
+		{
+			name:       "controller concurrency config",
+			configFile: controllerConcurrencyConfig,
+			wantConfiguration: config.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: config.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				ManageJobsWithoutQueueName:   false,
+				InternalCertManagement:       enableDefaultInternalCertManagement,
+				ClientConnection:             defaultClientConnection,
+				Integrations:                 defaultIntegrations,
+			},
+			wantOptions: ctrl.Options{
+				Port:                       config.DefaultWebhookPort,
+				HealthProbeBindAddress:     config.DefaultHealthProbeBindAddress,
+				MetricsBindAddress:         config.DefaultMetricsBindAddress,
+				LeaderElectionID:           config.DefaultLeaderElectionID,
+				LeaderElection:             true,
+				LeaderElectionResourceLock: "leases",
+				LeaderElectionNamespace:    config.DefaultNamespace,
+				LeaseDuration:              durationPtr(15 * time.Second),
+				RenewDeadline:              durationPtr(10 * time.Second),
+				RetryPeriod:                durationPtr(2 * time.Second),
+				Controller: ctrlconfig.ControllerConfigurationSpec{
+					GroupKindConcurrency: map[string]int{
+						"Job.batch":               10,
+						"Workload.kueue.x-k8s.io": 5,
+					},
+				},
+			},
+		},
+		{
+			name:       "resources config",
+			configFile: resourcesConfig,
+			wantConfiguration: config.Configuration{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: config.GroupVersion.String(),
+					Kind:       "Configuration",
+				},
+				Namespace:                    pointer.String(config.DefaultNamespace),
+				ManagedJobsNamespaceSelector: defaultManagedJobsNamespaceSelector(config.DefaultNamespace),
+				ManageJobsWithoutQueueName:   false,
+				InternalCertManagement:       enableDefaultInternalCertManagement,
+				ClientConnection:             defaultClientConnection,
+				Integrations:                 defaultIntegrations,
+				Resources: &config.Resources{
+					ExcludeResourcePrefixes: []string{"hugepages-"},
+					Transformations: []config.ResourceTransformation{
+						{
+							Input:    "nvidia.com/mig-1g.5gb",
+							Strategy: strategyPtr(config.Replace),
+							Outputs: corev1.ResourceList{
+								"nvidia.com/gpu": resource.MustParse("500m"),
+							},
+						},
+						{
+							Input:    corev1.ResourceEphemeralStorage,
+							Strategy: strategyPtr(config.Retain),
+						},
+					},
+				},
 			},
 			wantOptions: defaultControlOptions,
 		},
@@ -363,3 +525,413 @@ clientConnection:
 		})
 	}
 }
+
+func TestResourceTransformations(t *testing.T) {
+	testcases := map[string]struct {
+		cfg     config.Configuration
+		want    []config.ResourceTransformation
+		wantErr bool
+	}{
+		"no resources": {},
+		"valid": {
+			cfg: config.Configuration{
+				Resources: &config.Resources{
+					Transformations: []config.ResourceTransformation{
+						{Input: "nvidia.com/mig-1g.5gb", Strategy: strategyPtr(config.Replace)},
+						{Input: corev1.ResourceEphemeralStorage, Strategy: strategyPtr(config.Retain)},
+					},
+				},
+			},
+			want: []config.ResourceTransformation{
+				{Input: "nvidia.com/mig-1g.5gb", Strategy: strategyPtr(config.Replace)},
+				{Input: corev1.ResourceEphemeralStorage, Strategy: strategyPtr(config.Retain)},
+			},
+		},
+		"duplicate input": {
+			cfg: config.Configuration{
+				Resources: &config.Resources{
+					Transformations: []config.ResourceTransformation{
+						{Input: "nvidia.com/mig-1g.5gb", Strategy: strategyPtr(config.Replace)},
+						{Input: "nvidia.com/mig-1g.5gb", Strategy: strategyPtr(config.Retain)},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		"unknown strategy": {
+			cfg: config.Configuration{
+				Resources: &config.Resources{
+					Transformations: []config.ResourceTransformation{
+						{Input: "nvidia.com/mig-1g.5gb", Strategy: strategyPtr("Merge")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resourceTransformations(&tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("resourceTransformations returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected transformations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchedulerShards(t *testing.T) {
+	testcases := map[string]struct {
+		cfg     config.Configuration
+		want    int
+		wantErr bool
+	}{
+		"default": {
+			want: 1,
+		},
+		"shards": {
+			cfg:  config.Configuration{Scheduler: &config.Scheduler{Shards: pointer.Int32(4)}},
+			want: 4,
+		},
+		"no shards": {
+			cfg:     config.Configuration{Scheduler: &config.Scheduler{Shards: pointer.Int32(0)}},
+			wantErr: true,
+		},
+		"shards with waitForPodsReady": {
+			cfg: config.Configuration{
+				Scheduler:        &config.Scheduler{Shards: pointer.Int32(4)},
+				WaitForPodsReady: &config.WaitForPodsReady{Enable: true},
+			},
+			wantErr: true,
+		},
+		"single shard with waitForPodsReady": {
+			cfg: config.Configuration{
+				Scheduler:        &config.Scheduler{Shards: pointer.Int32(1)},
+				WaitForPodsReady: &config.WaitForPodsReady{Enable: true},
+			},
+			want: 1,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got, err := schedulerShards(&tc.cfg)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("schedulerShards() returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("schedulerShards() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerCohortConcurrency(t *testing.T) {
+	testcases := map[string]struct {
+		cfg     config.Configuration
+		want    int
+		wantErr bool
+	}{
+		"default": {
+			cfg:  config.Configuration{Scheduler: &config.Scheduler{Shards: pointer.Int32(4)}},
+			want: 1,
+		},
+		"concurrent cohorts": {
+			cfg:  config.Configuration{Scheduler: &config.Scheduler{CohortConcurrency: pointer.Int32(8)}},
+			want: 8,
+		},
+		"negative": {
+			cfg:     config.Configuration{Scheduler: &config.Scheduler{CohortConcurrency: pointer.Int32(-1)}},
+			wantErr: true,
+		},
+		"concurrent cohorts with topologyAwareScheduling": {
+			cfg: config.Configuration{
+				Scheduler:               &config.Scheduler{CohortConcurrency: pointer.Int32(8)},
+				TopologyAwareScheduling: &config.TopologyAwareScheduling{Enable: true},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got, err := schedulerCohortConcurrency(&tc.cfg)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("schedulerCohortConcurrency() returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("schedulerCohortConcurrency() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerAdmissionPatchWorkers(t *testing.T) {
+	testcases := map[string]struct {
+		cfg     config.Configuration
+		want    int
+		wantErr bool
+	}{
+		"default": {
+			want: scheduler.DefaultAdmissionPatchWorkers,
+		},
+		"configured": {
+			cfg:  config.Configuration{Scheduler: &config.Scheduler{AdmissionPatchWorkers: pointer.Int32(32)}},
+			want: 32,
+		},
+		"with waitForPodsReady": {
+			cfg: config.Configuration{
+				Scheduler:        &config.Scheduler{AdmissionPatchWorkers: pointer.Int32(2)},
+				WaitForPodsReady: &config.WaitForPodsReady{Enable: true},
+			},
+			want: 2,
+		},
+		"zero": {
+			cfg:     config.Configuration{Scheduler: &config.Scheduler{AdmissionPatchWorkers: pointer.Int32(0)}},
+			wantErr: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got, err := schedulerAdmissionPatchWorkers(&tc.cfg)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("schedulerAdmissionPatchWorkers() returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("schedulerAdmissionPatchWorkers() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeConfigFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	testcases := map[string]struct {
+		content string
+		wantErr bool
+	}{
+		"valid": {
+			content: `
+apiVersion: config.kueue.x-k8s.io/v1alpha2
+kind: Configuration
+namespace: kueue-system
+webhook:
+  port: 9443
+scheduler:
+  shards: 2
+`,
+		},
+		"unknown field": {
+			content: `
+apiVersion: config.kueue.x-k8s.io/v1alpha2
+kind: Configuration
+waitForPodReady:
+  enable: true
+`,
+			wantErr: true,
+		},
+		"unknown nested field": {
+			content: `
+apiVersion: config.kueue.x-k8s.io/v1alpha2
+kind: Configuration
+clientConnection:
+  qps: 50
+  bursts: 100
+`,
+			wantErr: true,
+		},
+		"duplicated field": {
+			content: `
+apiVersion: config.kueue.x-k8s.io/v1alpha2
+kind: Configuration
+namespace: kueue-system
+namespace: kueue-tenant-a
+`,
+			wantErr: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			configFile := filepath.Join(tmpDir, strings.ReplaceAll(name, " ", "-")+".yaml")
+			if err := os.WriteFile(configFile, []byte(tc.content), os.FileMode(0600)); err != nil {
+				t.Fatal(err)
+			}
+			_, err := decodeConfigFile(configFile)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("decodeConfigFile() returned error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoggingOptions(t *testing.T) {
+	format := func(f config.LoggingFormat) *config.LoggingFormat { return &f }
+	testcases := map[string]struct {
+		logging  *config.Logging
+		flags    zap.Options
+		want     []string
+		wantJSON bool
+		wantErr  bool
+	}{
+		"no logging configuration": {
+			flags: zap.Options{Development: true},
+			want:  []string{"setup info", "scheduler v1"},
+		},
+		"json format": {
+			logging:  &config.Logging{Format: format(config.LoggingFormatJSON)},
+			flags:    zap.Options{Development: true},
+			want:     []string{"setup info", "scheduler v1"},
+			wantJSON: true,
+		},
+		"console format": {
+			logging: &config.Logging{Format: format(config.LoggingFormatConsole)},
+			want:    []string{"setup info"},
+		},
+		"verbosity": {
+			logging:  &config.Logging{Verbosity: pointer.Int32(3)},
+			want:     []string{"setup info", "scheduler v1", "scheduler v3", "preemption v3"},
+			wantJSON: true,
+		},
+		"logger verbosity": {
+			logging: &config.Logging{
+				LoggerVerbosity: map[string]int32{
+					"scheduler":            1,
+					"scheduler.preemption": 5,
+				},
+			},
+			want:     []string{"setup info", "scheduler v1", "preemption v3", "preemption v5"},
+			wantJSON: true,
+		},
+		"logger verbosity over the flags": {
+			logging: &config.Logging{
+				LoggerVerbosity: map[string]int32{"setup": 0},
+			},
+			flags: zap.Options{Development: true},
+			want:  []string{"setup info", "scheduler v1"},
+		},
+		"invalid format": {
+			logging: &config.Logging{Format: format("text")},
+			wantErr: true,
+		},
+		"invalid logger verbosity": {
+			logging: &config.Logging{LoggerVerbosity: map[string]int32{"scheduler": -1}},
+			wantErr: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			opts, err := loggingOptions(tc.logging)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("loggingOptions() returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var buf bytes.Buffer
+			log := zap.New(zap.UseFlagOptions(&tc.flags), zap.WriteTo(&buf), opts)
+			log.WithName("setup").Info("setup info")
+			scheduler := log.WithName("scheduler")
+			scheduler.V(1).Info("scheduler v1")
+			scheduler.V(3).Info("scheduler v3")
+			scheduler.WithName("preemption").V(3).Info("preemption v3")
+			scheduler.WithName("preemption").V(5).Info("preemption v5")
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if isJSON := strings.HasPrefix(line, "{"); isJSON != tc.wantJSON {
+					t.Errorf("Unexpected encoding of %q, want JSON: %t", line, tc.wantJSON)
+				}
+				for _, msg := range []string{"setup info", "scheduler v1", "scheduler v3", "preemption v3", "preemption v5"} {
+					if strings.Contains(line, msg) {
+						got = append(got, msg)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected logged messages (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLeaderOnlyCheck(t *testing.T) {
+	elected := make(chan struct{})
+	check := leaderOnlyCheck(elected, func(*http.Request) error {
+		return errors.New("queues are not synced")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	if err := check(req); err != nil {
+		t.Errorf("The check failed before the replica was elected: %v", err)
+	}
+	close(elected)
+	if err := check(req); err == nil {
+		t.Error("The check passed after the replica was elected")
+	}
+}
+
+func TestWorkloadInfoOptions(t *testing.T) {
+	testcases := map[string]struct {
+		cfg      config.Configuration
+		wantOpts int
+		wantErr  bool
+	}{
+		"no resources": {},
+		"valid": {
+			cfg: config.Configuration{
+				Resources: &config.Resources{
+					ExcludeResourcePrefixes: []string{"hugepages-"},
+				},
+			},
+			wantOpts: 2,
+		},
+		"empty prefix": {
+			cfg: config.Configuration{
+				Resources: &config.Resources{
+					ExcludeResourcePrefixes: []string{"hugepages-", ""},
+				},
+			},
+			wantErr: true,
+		},
+		"invalid transformations": {
+			cfg: config.Configuration{
+				Resources: &config.Resources{
+					Transformations: []config.ResourceTransformation{
+						{Input: "nvidia.com/mig-1g.5gb", Strategy: strategyPtr("Merge")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got, err := workloadInfoOptions(&tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("workloadInfoOptions returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if len(got) != tc.wantOpts {
+				t.Errorf("workloadInfoOptions returned %d options, want %d", len(got), tc.wantOpts)
+			}
+		})
+	}
+}
+
+func strategyPtr(s config.ResourceTransformationStrategy) *config.ResourceTransformationStrategy {
+	return &s
+}
+
+func defaultManagedJobsNamespaceSelector(namespace string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      corev1.LabelMetadataName,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{metav1.NamespaceSystem, namespace},
+			},
+		},
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
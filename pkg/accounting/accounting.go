@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+// Record is the quota that the pods of a podSet of a workload used, for a
+// resource and flavor, during the period of a report.
+type Record struct {
+	Namespace    string              `json:"namespace"`
+	LocalQueue   string              `json:"localQueue"`
+	ClusterQueue string              `json:"clusterQueue"`
+	Workload     string              `json:"workload"`
+	PodSet       string              `json:"podSet"`
+	Resource     corev1.ResourceName `json:"resource"`
+	Flavor       string              `json:"flavor"`
+	Quantity     resource.Quantity   `json:"quantity"`
+	// Seconds is the time that the workload held the quota during the
+	// period.
+	Seconds int64 `json:"seconds"`
+}
+
+// Report holds the records of the workloads that held quota during a period.
+type Report struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Records []Record  `json:"records"`
+}
+
+var csvHeader = []string{"start", "end", "namespace", "localQueue", "clusterQueue", "workload", "podSet", "resource", "flavor", "quantity", "seconds"}
+
+// CSV returns the records of the report in CSV format, with a header.
+func (r *Report) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	start, end := r.Start.UTC().Format(time.RFC3339), r.End.UTC().Format(time.RFC3339)
+	for _, rec := range r.Records {
+		row := []string{start, end, rec.Namespace, rec.LocalQueue, rec.ClusterQueue, rec.Workload, rec.PodSet,
+			string(rec.Resource), rec.Flavor, rec.Quantity.String(), strconv.FormatInt(rec.Seconds, 10)}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// Sink writes the reports.
+type Sink interface {
+	Write(ctx context.Context, report *Report) error
+}
+
+// Exporter writes, at every interval, a report with the quota that the
+// workloads held during the interval to a sink. A report that can't be
+// written is merged into the next one.
+type Exporter struct {
+	log      logr.Logger
+	client   client.Client
+	sink     Sink
+	interval time.Duration
+	clock    clock.WithTicker
+}
+
+var _ manager.Runnable = &Exporter{}
+
+func NewExporter(client client.Client, sink Sink, interval time.Duration) *Exporter {
+	return &Exporter{
+		log:      ctrl.Log.WithName("accounting-exporter"),
+		client:   client,
+		sink:     sink,
+		interval: interval,
+		clock:    clock.RealClock{},
+	}
+}
+
+// Start exports the reports until the context is done. The first report
+// starts when the exporter starts.
+func (e *Exporter) Start(ctx context.Context) error {
+	start := e.clock.Now()
+	ticker := e.clock.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case end := <-ticker.C():
+			if err := e.export(ctx, start, end); err != nil {
+				e.log.Error(err, "Exporting the usage report, retrying in the next interval", "start", start)
+				continue
+			}
+			start = end
+		}
+	}
+}
+
+func (e *Exporter) export(ctx context.Context, start, end time.Time) error {
+	var workloads kueue.WorkloadList
+	if err := e.client.List(ctx, &workloads); err != nil {
+		return err
+	}
+	report := NewReport(workloads.Items, start, end)
+	if err := e.sink.Write(ctx, report); err != nil {
+		return err
+	}
+	e.log.V(2).Info("Exported the usage report", "start", start, "end", end, "records", len(report.Records))
+	return nil
+}
+
+// NewReport returns the report of the quota that the workloads held between
+// start and end, sorted by namespace, workload, podSet and resource.
+func NewReport(workloads []kueue.Workload, start, end time.Time) *Report {
+	report := &Report{Start: start, End: end}
+	for i := range workloads {
+		report.Records = append(report.Records, workloadRecords(&workloads[i], start, end)...)
+	}
+	sort.Slice(report.Records, func(i, j int) bool {
+		a, b := &report.Records[i], &report.Records[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Workload != b.Workload {
+			return a.Workload < b.Workload
+		}
+		if a.PodSet != b.PodSet {
+			return a.PodSet < b.PodSet
+		}
+		return a.Resource < b.Resource
+	})
+	return report
+}
+
+// workloadRecords returns the records of the resource usage of the workload,
+// for the time between start and end in which it held quota.
+func workloadRecords(wl *kueue.Workload, start, end time.Time) []Record {
+	if wl.Spec.Admission == nil || len(wl.Status.ResourceUsage) == 0 {
+		return nil
+	}
+	reserved := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadQuotaReserved)
+	if reserved == nil || reserved.Status != metav1.ConditionTrue {
+		return nil
+	}
+	from := reserved.LastTransitionTime.Time
+	if from.Before(start) {
+		from = start
+	}
+	to := end
+	if finished := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadFinished); finished != nil &&
+		finished.Status == metav1.ConditionTrue && finished.LastTransitionTime.Time.Before(to) {
+		to = finished.LastTransitionTime.Time
+	}
+	seconds := int64(to.Sub(from) / time.Second)
+	if seconds <= 0 {
+		return nil
+	}
+	var records []Record
+	for _, ps := range wl.Status.ResourceUsage {
+		for _, r := range ps.Resources {
+			records = append(records, Record{
+				Namespace:    wl.Namespace,
+				LocalQueue:   wl.Spec.QueueName,
+				ClusterQueue: string(wl.Spec.Admission.ClusterQueue),
+				Workload:     wl.Name,
+				PodSet:       ps.Name,
+				Resource:     r.Name,
+				Flavor:       r.Flavor,
+				Quantity:     r.Total.DeepCopy(),
+				Seconds:      seconds,
+			})
+		}
+	}
+	return records
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+var (
+	reportStart = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	reportEnd   = reportStart.Add(time.Hour)
+)
+
+func usingWorkload(name string, reservedAt time.Time) *utiltesting.WorkloadWrapper {
+	return utiltesting.MakeWorkload(name, "ns").
+		Queue("lq").
+		Admit(utiltesting.MakeAdmission("cq").Obj()).
+		Condition(metav1.Condition{
+			Type:               kueue.WorkloadQuotaReserved,
+			Status:             metav1.ConditionTrue,
+			Reason:             "QuotaReserved",
+			LastTransitionTime: metav1.NewTime(reservedAt),
+		}).
+		ResourceUsage(kueue.PodSetResourceUsage{
+			Name:  "main",
+			Count: 2,
+			Resources: []kueue.ResourceUsage{
+				{Name: corev1.ResourceCPU, Flavor: "on-demand", Total: resource.MustParse("4")},
+				{Name: "nvidia.com/gpu", Flavor: "a100", Total: resource.MustParse("2")},
+			},
+		})
+}
+
+func TestNewReport(t *testing.T) {
+	cases := map[string]struct {
+		workload    *kueue.Workload
+		wantSeconds int64
+	}{
+		"admitted before the period": {
+			workload:    usingWorkload("wl", reportStart.Add(-time.Hour)).Obj(),
+			wantSeconds: 3600,
+		},
+		"admitted during the period": {
+			workload:    usingWorkload("wl", reportStart.Add(40*time.Minute)).Obj(),
+			wantSeconds: 1200,
+		},
+		"finished during the period": {
+			workload: usingWorkload("wl", reportStart.Add(-time.Hour)).
+				Condition(metav1.Condition{
+					Type:               kueue.WorkloadFinished,
+					Status:             metav1.ConditionTrue,
+					Reason:             "JobFinished",
+					LastTransitionTime: metav1.NewTime(reportStart.Add(10 * time.Minute)),
+				}).
+				Obj(),
+			wantSeconds: 600,
+		},
+		"finished before the period": {
+			workload: usingWorkload("wl", reportStart.Add(-time.Hour)).
+				Condition(metav1.Condition{
+					Type:               kueue.WorkloadFinished,
+					Status:             metav1.ConditionTrue,
+					Reason:             "JobFinished",
+					LastTransitionTime: metav1.NewTime(reportStart.Add(-time.Minute)),
+				}).
+				Obj(),
+		},
+		"pending": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Queue("lq").Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var want []Record
+			if tc.wantSeconds > 0 {
+				want = []Record{
+					{Namespace: "ns", LocalQueue: "lq", ClusterQueue: "cq", Workload: "wl", PodSet: "main",
+						Resource: corev1.ResourceCPU, Flavor: "on-demand", Quantity: resource.MustParse("4"), Seconds: tc.wantSeconds},
+					{Namespace: "ns", LocalQueue: "lq", ClusterQueue: "cq", Workload: "wl", PodSet: "main",
+						Resource: "nvidia.com/gpu", Flavor: "a100", Quantity: resource.MustParse("2"), Seconds: tc.wantSeconds},
+				}
+			}
+			got := NewReport([]kueue.Workload{*tc.workload}, reportStart, reportEnd)
+			if diff := cmp.Diff(want, got.Records); diff != "" {
+				t.Errorf("Unexpected records (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReportCSV(t *testing.T) {
+	report := NewReport([]kueue.Workload{
+		*usingWorkload("b", reportStart).Obj(),
+		*usingWorkload("a", reportStart.Add(30*time.Minute)).Obj(),
+	}, reportStart, reportEnd)
+	got, err := report.CSV()
+	if err != nil {
+		t.Fatalf("CSV() failed: %v", err)
+	}
+	want := `start,end,namespace,localQueue,clusterQueue,workload,podSet,resource,flavor,quantity,seconds
+2024-01-01T10:00:00Z,2024-01-01T11:00:00Z,ns,lq,cq,a,main,cpu,on-demand,4,1800
+2024-01-01T10:00:00Z,2024-01-01T11:00:00Z,ns,lq,cq,a,main,nvidia.com/gpu,a100,2,1800
+2024-01-01T10:00:00Z,2024-01-01T11:00:00Z,ns,lq,cq,b,main,cpu,on-demand,4,3600
+2024-01-01T10:00:00Z,2024-01-01T11:00:00Z,ns,lq,cq,b,main,nvidia.com/gpu,a100,2,3600
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Unexpected CSV (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
+)
+
+const (
+	// ReportKey is the key of the ConfigMap sink that holds the last report.
+	ReportKey = "report.csv"
+
+	httpSinkTimeout  = 30 * time.Second
+	reportFileLayout = "20060102T150405Z"
+)
+
+var errInvalidSink = errors.New("exactly one of configMap, url and directory must be set")
+
+// NewSink returns the sink of the configuration. The ConfigMap sink writes in
+// the given namespace.
+func NewSink(c client.Client, namespace string, cfg *config.AccountingSink) (Sink, error) {
+	set := 0
+	for _, s := range []*string{cfg.ConfigMap, cfg.URL, cfg.Directory} {
+		if s != nil {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errInvalidSink
+	}
+	switch {
+	case cfg.ConfigMap != nil:
+		return &configMapSink{client: c, namespace: namespace, name: *cfg.ConfigMap}, nil
+	case cfg.URL != nil:
+		u, err := url.Parse(*cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported scheme %q in url", u.Scheme)
+		}
+		return &httpSink{client: &http.Client{Timeout: httpSinkTimeout}, url: u.String()}, nil
+	default:
+		return &directorySink{path: *cfg.Directory}, nil
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=create;update
+
+// configMapSink writes the last report in a ConfigMap, replacing the previous
+// one.
+type configMapSink struct {
+	client    client.Client
+	namespace string
+	name      string
+}
+
+func (s *configMapSink) Write(ctx context.Context, report *Report) error {
+	data, err := report.CSV()
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.name},
+		Data:       map[string]string{ReportKey: string(data)},
+	}
+	err = s.client.Update(ctx, cm)
+	if apierrors.IsNotFound(err) {
+		err = s.client.Create(ctx, cm)
+	}
+	return err
+}
+
+// httpSink posts each report as JSON to an endpoint.
+type httpSink struct {
+	client *http.Client
+	url    string
+}
+
+func (s *httpSink) Write(ctx context.Context, report *Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("posting the report to %s: %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// directorySink writes each report in a new CSV file of a directory, named
+// after the period of the report.
+type directorySink struct {
+	path string
+}
+
+func (s *directorySink) Write(_ context.Context, report *Report) error {
+	data, err := report.CSV()
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("usage-%s-%s.csv", report.Start.UTC().Format(reportFileLayout), report.End.UTC().Format(reportFileLayout))
+	// The file is renamed once complete, so that readers never see a
+	// partial report.
+	tmp, err := os.CreateTemp(s.path, ".usage-*.csv")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.path, name))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func testReport() *Report {
+	return NewReport([]kueue.Workload{*usingWorkload("wl", reportStart).Obj()}, reportStart, reportEnd)
+}
+
+func TestNewSink(t *testing.T) {
+	cases := map[string]struct {
+		sink    config.AccountingSink
+		wantErr bool
+	}{
+		"no sink": {
+			wantErr: true,
+		},
+		"two sinks": {
+			sink: config.AccountingSink{
+				ConfigMap: pointer.String("usage"),
+				Directory: pointer.String("/reports"),
+			},
+			wantErr: true,
+		},
+		"url": {
+			sink: config.AccountingSink{URL: pointer.String("https://billing.example.com/usage")},
+		},
+		"url with an unsupported scheme": {
+			sink:    config.AccountingSink{URL: pointer.String("ftp://billing.example.com/usage")},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSink(nil, "kueue-system", &tc.sink)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("NewSink() returned error %v, want error: %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigMapSink(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	sink, err := NewSink(cl, "kueue-system", &config.AccountingSink{ConfigMap: pointer.String("usage")})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	ctx := context.Background()
+	report := testReport()
+	want, err := report.CSV()
+	if err != nil {
+		t.Fatalf("CSV() failed: %v", err)
+	}
+	// The second write replaces the report.
+	for i := 0; i < 2; i++ {
+		if err := sink.Write(ctx, report); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	var cm corev1.ConfigMap
+	if err := cl.Get(ctx, types.NamespacedName{Namespace: "kueue-system", Name: "usage"}, &cm); err != nil {
+		t.Fatalf("Failed getting the ConfigMap: %v", err)
+	}
+	if diff := cmp.Diff(string(want), cm.Data[ReportKey]); diff != "" {
+		t.Errorf("Unexpected report (-want,+got):\n%s", diff)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var got Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	sink, err := NewSink(nil, "kueue-system", &config.AccountingSink{URL: pointer.String(server.URL)})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	report := testReport()
+	if err := sink.Write(context.Background(), report); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if diff := cmp.Diff(report, &got); diff != "" {
+		t.Errorf("Unexpected report (-want,+got):\n%s", diff)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	sink, err = NewSink(nil, "kueue-system", &config.AccountingSink{URL: pointer.String(failing.URL)})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	if err := sink.Write(context.Background(), report); err == nil {
+		t.Error("Write succeeded with an unavailable endpoint")
+	}
+}
+
+func TestDirectorySink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewSink(nil, "kueue-system", &config.AccountingSink{Directory: pointer.String(dir)})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	report := testReport()
+	if err := sink.Write(context.Background(), report); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed reading the directory: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	wantName := "usage-" + reportStart.Format(reportFileLayout) + "-" + reportStart.Add(time.Hour).Format(reportFileLayout) + ".csv"
+	if diff := cmp.Diff([]string{wantName}, names); diff != "" {
+		t.Fatalf("Unexpected files (-want,+got):\n%s", diff)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, wantName))
+	if err != nil {
+		t.Fatalf("Failed reading the report: %v", err)
+	}
+	want, _ := report.CSV()
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("Unexpected report (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// BorrowingWorkloads returns the keys of the workloads that make the
+// ClusterQueue borrow quota. The borrowing is attributed to the workloads that
+// were admitted last: the usage of the workloads is added up in the order in
+// which they were admitted, and a workload is borrowing if the usage exceeds
+// the min quota of any of its flavors once it's added. The workloads that are
+// not admitted yet, such as the ones assumed by the scheduler, come last.
+func (c *ClusterQueue) BorrowingWorkloads() sets.Set[string] {
+	workloads := make([]*workload.Info, 0, len(c.Workloads))
+	for _, wi := range c.Workloads {
+		workloads = append(workloads, wi)
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		ti, tj := admissionTime(workloads[i].Obj), admissionTime(workloads[j].Obj)
+		if ti.IsZero() != tj.IsZero() {
+			return tj.IsZero()
+		}
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return workload.Key(workloads[i].Obj) < workload.Key(workloads[j].Obj)
+	})
+
+	borrowing := sets.New[string]()
+	usage := make(ResourceQuantities, len(c.UsedResources))
+	for rName, flavors := range c.UsedResources {
+		usage[rName] = make(map[string]int64, len(flavors))
+		for flavor := range flavors {
+			usage[rName][flavor] = 0
+		}
+	}
+	for _, wi := range workloads {
+		updateUsage(wi, usage, 1)
+		if c.exceedsMinQuota(wi, usage) {
+			borrowing.Insert(workload.Key(wi.Obj))
+		}
+	}
+	return borrowing
+}
+
+// exceedsMinQuota returns whether the usage of any of the flavors assigned to
+// the workload exceeds its min quota.
+func (c *ClusterQueue) exceedsMinQuota(wi *workload.Info, usage ResourceQuantities) bool {
+	for _, ps := range wi.TotalRequests {
+		for rName, flavor := range ps.Flavors {
+			res := c.RequestableResources[rName]
+			if res == nil {
+				continue
+			}
+			flavor = usageFlavor(usage[rName], flavor)
+			for _, fl := range res.Flavors {
+				if fl.Name == flavor && usage[rName][flavor] > fl.Min {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
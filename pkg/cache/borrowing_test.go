@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestBorrowingWorkloads(t *testing.T) {
+	now := time.Now()
+	admitted := func(name string, cpu string, admittedAt time.Time) *kueue.Workload {
+		w := utiltesting.MakeWorkload(name, "ns").
+			Request(corev1.ResourceCPU, cpu).
+			Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "default").Obj())
+		if !admittedAt.IsZero() {
+			w.Condition(metav1.Condition{
+				Type:               kueue.WorkloadAdmitted,
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(admittedAt),
+			})
+		}
+		return w.Obj()
+	}
+	workloads := []*kueue.Workload{
+		admitted("old", "2", now.Add(-time.Hour)),
+		admitted("mid", "2", now.Add(-time.Minute)),
+		admitted("new", "1", now),
+		admitted("assumed", "1", time.Time{}),
+	}
+	testCases := map[string]struct {
+		min  string
+		want sets.Set[string]
+	}{
+		"not borrowing": {
+			min:  "6",
+			want: sets.New[string](),
+		},
+		"borrowing by the most recently admitted": {
+			min:  "4",
+			want: sets.New("ns/new", "ns/assumed"),
+		},
+		"borrowing by all but the oldest": {
+			min:  "2",
+			want: sets.New("ns/mid", "ns/new", "ns/assumed"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+			cq := utiltesting.MakeClusterQueue("cq").
+				Cohort("cohort").
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", tc.min).Obj()).
+					Obj()).
+				Obj()
+			if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+				t.Fatalf("Failed adding clusterQueue: %v", err)
+			}
+			for _, w := range workloads {
+				cache.AddOrUpdateWorkload(w)
+			}
+			got := cache.clusterQueues["cq"].BorrowingWorkloads()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected borrowing workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -20,9 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -50,6 +54,10 @@ var (
 
 type options struct {
 	podsReadyTracking bool
+	nodeTracking      bool
+	topologyTracking  bool
+	quotaAutoSizing   bool
+	workloadInfoOpts  []workload.InfoOption
 }
 
 // Option configures the reconciler.
@@ -64,6 +72,42 @@ func WithPodsReadyTracking(f bool) Option {
 	}
 }
 
+// WithNodeTracking indicates the cache tracks the extended resources exposed
+// by the nodes that each ResourceFlavor selects, so that the flavors are only
+// assigned to the extended resources that their nodes expose.
+func WithNodeTracking(f bool) Option {
+	return func(o *options) {
+		o.nodeTracking = f
+	}
+}
+
+// WithTopologyTracking indicates the cache tracks the Topologies and the
+// capacity of the nodes in their domains, so that the pod sets requesting a
+// topology are admitted into a single domain.
+func WithTopologyTracking(f bool) Option {
+	return func(o *options) {
+		o.topologyTracking = f
+	}
+}
+
+// WithQuotaAutoSizing indicates the cache tracks the allocatable capacity of
+// the nodes that each ResourceFlavor selects, so that the min quotas declared
+// as a percentage of that capacity follow the nodes that join or leave.
+func WithQuotaAutoSizing(f bool) Option {
+	return func(o *options) {
+		o.quotaAutoSizing = f
+	}
+}
+
+// WithWorkloadInfoOptions indicates how the requests of the admitted
+// workloads are computed before they are accounted in the usage of the
+// ClusterQueues, such as the resource transformations.
+func WithWorkloadInfoOptions(opts ...workload.InfoOption) Option {
+	return func(o *options) {
+		o.workloadInfoOpts = append(o.workloadInfoOpts, opts...)
+	}
+}
+
 var defaultOptions = options{}
 
 // Cache keeps track of the Workloads that got admitted through ClusterQueues.
@@ -74,9 +118,41 @@ type Cache struct {
 	client            client.Client
 	clusterQueues     map[string]*ClusterQueue
 	cohorts           map[string]*Cohort
+	cohortConfigs     map[string]*cohortConfig
 	assumedWorkloads  map[string]string
 	resourceFlavors   map[string]*kueue.ResourceFlavor
+	admissionChecks   map[string]*kueue.AdmissionCheck
 	podsReadyTracking bool
+	workloadInfoOpts  []workload.InfoOption
+
+	nodeTracking bool
+	nodes        map[string]*nodeInfo
+	// flavorNodeResources holds the extended resources exposed by the nodes
+	// selected by each flavor. It is nil when it needs to be recalculated.
+	flavorNodeResources map[string]sets.Set[corev1.ResourceName]
+
+	topologyTracking bool
+	// topologies holds the node labels of the levels of each Topology.
+	topologies map[string][]string
+	// flavorTopologies holds the domains of the nodes selected by each flavor
+	// with a topology, without usage. It is nil when it needs to be
+	// recalculated.
+	flavorTopologies map[string]*FlavorTopology
+
+	quotaAutoSizing bool
+	// flavorCapacity holds the allocatable capacity of the nodes selected by
+	// each flavor. It is nil when it needs to be recalculated.
+	flavorCapacity map[string]workload.Requests
+
+	// reservations holds the quota reserved for pending workloads that
+	// preempted other workloads, so that the quota freed by the preemptions
+	// is not taken by other workloads.
+	reservations map[string]*reservation
+}
+
+type reservation struct {
+	info      *workload.Info
+	expiresAt time.Time
 }
 
 func New(client client.Client, opts ...Option) *Cache {
@@ -88,9 +164,18 @@ func New(client client.Client, opts ...Option) *Cache {
 		client:            client,
 		clusterQueues:     make(map[string]*ClusterQueue),
 		cohorts:           make(map[string]*Cohort),
+		cohortConfigs:     make(map[string]*cohortConfig),
 		assumedWorkloads:  make(map[string]string),
 		resourceFlavors:   make(map[string]*kueue.ResourceFlavor),
+		admissionChecks:   make(map[string]*kueue.AdmissionCheck),
 		podsReadyTracking: options.podsReadyTracking,
+		workloadInfoOpts:  options.workloadInfoOpts,
+		nodeTracking:      options.nodeTracking,
+		nodes:             make(map[string]*nodeInfo),
+		topologyTracking:  options.topologyTracking,
+		quotaAutoSizing:   options.quotaAutoSizing,
+		topologies:        make(map[string][]string),
+		reservations:      make(map[string]*reservation),
 	}
 	c.podsReadyCond.L = &c.RWMutex
 	return c
@@ -104,8 +189,60 @@ type Cohort struct {
 	Members sets.Set[*ClusterQueue]
 
 	// These fields are only populated for a snapshot.
+
+	// Parent is the cohort that this cohort belongs to, if any.
+	Parent *Cohort
+	// ChildCohorts are the cohorts that belong to this cohort.
+	ChildCohorts sets.Set[*Cohort]
+	// RequestableResources and UsedResources account for all the
+	// ClusterQueues and cohorts under the cohort. Only the quota that the
+	// ClusterQueues can lend, and its usage, is accounted.
 	RequestableResources ResourceQuantities
 	UsedResources        ResourceQuantities
+	// Limits are the upper limits on the usage of all the ClusterQueues under
+	// the cohort.
+	Limits ResourceQuantities
+}
+
+// cohortConfig holds a processed Cohort object.
+type cohortConfig struct {
+	parent    string
+	resources map[corev1.ResourceName]*Resource
+}
+
+// Root returns the root of the hierarchy of cohorts that the cohort belongs to.
+func (c *Cohort) Root() *Cohort {
+	root := c
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	return root
+}
+
+// HasDescendant returns whether the given cohort is the cohort itself or
+// belongs to it, directly or through other cohorts.
+func (c *Cohort) HasDescendant(other *Cohort) bool {
+	for ; other != nil; other = other.Parent {
+		if other == c {
+			return true
+		}
+	}
+	return false
+}
+
+// AllMembers returns the ClusterQueues under the cohort, including the ones
+// under its child cohorts.
+func (c *Cohort) AllMembers() sets.Set[*ClusterQueue] {
+	members := sets.New[*ClusterQueue]()
+	c.collectMembers(members)
+	return members
+}
+
+func (c *Cohort) collectMembers(members sets.Set[*ClusterQueue]) {
+	members.Insert(c.Members.UnsortedList()...)
+	for child := range c.ChildCohorts {
+		child.collectMembers(members)
+	}
 }
 
 func newCohort(name string, size int) *Cohort {
@@ -132,16 +269,64 @@ type ClusterQueue struct {
 	WorkloadsNotReady    sets.Set[string]
 	NamespaceSelector    labels.Selector
 	Preemption           kueue.ClusterQueuePreemption
+	FlavorFungibility    kueue.FlavorFungibility
+	SchedulingProfile    kueue.SchedulingProfile
+	// PriorityAging is the policy to increase the effective priority of the
+	// pending workloads as they wait.
+	PriorityAging *kueue.PriorityAging
 	// The set of key labels from all flavors of a resource.
 	// Those keys define the affinity terms of a workload
 	// that can be matched against the flavors.
 	LabelKeys map[corev1.ResourceName]sets.Set[string]
-	Status    metrics.ClusterQueueStatus
+	// AdmissionChecks are the names of the AdmissionChecks that the admitted
+	// workloads have to pass.
+	AdmissionChecks []string
+	Status          metrics.ClusterQueueStatus
+	// FlavorNodeResources are the extended resources exposed by the nodes
+	// selected by each flavor. It's nil if the cache doesn't track nodes.
+	// Only populated in a snapshot.
+	FlavorNodeResources map[string]sets.Set[corev1.ResourceName]
+	// FlavorTopologies are the topology domains of the nodes selected by the
+	// flavors that have a topology, shared by all the ClusterQueues. It's nil
+	// if the cache doesn't track topologies.
+	// Only populated in a snapshot.
+	FlavorTopologies map[string]*FlavorTopology
+
+	// workloadsShared indicates that Workloads is shared between the cache and
+	// a snapshot, so it has to be copied before being modified.
+	workloadsShared bool
 
 	// The following fields are not populated in a snapshot.
 
 	admittedWorkloadsPerQueue map[string]int
-	podsReadyTracking         bool
+	// admittedWorkloads is the number of Workloads that, besides reserving
+	// quota, are admitted.
+	admittedWorkloads int
+	podsReadyTracking bool
+	workloadInfoOpts  []workload.InfoOption
+	// resourceGroups are the quotas of the ClusterQueue spec, kept to
+	// recompute the min quotas declared as a percentage of the capacity of
+	// the nodes.
+	resourceGroups []kueue.ResourceGroup
+	// autoSized indicates that any min quota is declared as a percentage.
+	autoSized bool
+	// quotaSchedules are the time windows during which some quotas of the
+	// resourceGroups are replaced.
+	quotaSchedules []kueue.QuotaSchedule
+	// activeQuotaSchedule is the name of the schedule whose quotas apply, if
+	// any.
+	activeQuotaSchedule string
+	// missingFlavors are the names of the ResourceFlavors that the
+	// ClusterQueue references, but don't exist.
+	missingFlavors []string
+	// inactiveAdmissionChecks are the names of the AdmissionChecks of the
+	// ClusterQueue that don't exist or aren't active.
+	inactiveAdmissionChecks []string
+	// cohortCycle indicates that the hierarchy of cohorts above the
+	// ClusterQueue has a cycle of parents.
+	cohortCycle     bool
+	stopPolicy      kueue.StopPolicy
+	overQuotaPolicy kueue.OverQuotaPolicy
 }
 
 type Resource struct {
@@ -163,9 +348,29 @@ func (r *Resource) matchesFlavors(other *Resource) bool {
 
 // FlavorLimits holds a processed ClusterQueue flavor quota.
 type FlavorLimits struct {
-	Name string
-	Min  int64
-	Max  *int64
+	Name            string
+	Min             int64
+	Max             *int64
+	MaxPerNamespace *int64
+	LendingLimit    *int64
+}
+
+// Guaranteed returns the part of the min quota that can't be lent to other
+// ClusterQueues in the cohort.
+func (f *FlavorLimits) Guaranteed() int64 {
+	if f.LendingLimit == nil {
+		return 0
+	}
+	return f.Min - *f.LendingLimit
+}
+
+// LentUsage returns the part of the given usage that doesn't fit in the
+// guaranteed quota, and thus is accounted in the cohort.
+func (f *FlavorLimits) LentUsage(used int64) int64 {
+	if lent := used - f.Guaranteed(); lent > 0 {
+		return lent
+	}
+	return 0
 }
 
 func (c *Cache) newClusterQueue(cq *kueue.ClusterQueue) (*ClusterQueue, error) {
@@ -175,8 +380,9 @@ func (c *Cache) newClusterQueue(cq *kueue.ClusterQueue) (*ClusterQueue, error) {
 		WorkloadsNotReady:         sets.New[string](),
 		admittedWorkloadsPerQueue: make(map[string]int),
 		podsReadyTracking:         c.podsReadyTracking,
+		workloadInfoOpts:          c.workloadInfoOpts,
 	}
-	if err := cqImpl.update(cq, c.resourceFlavors); err != nil {
+	if err := cqImpl.update(cq, c.resourceFlavors, c.admissionChecks, c.capacityPerFlavor()); err != nil {
 		return nil, err
 	}
 
@@ -254,32 +460,67 @@ func (c *ClusterQueue) Active() bool {
 var defaultPreemption = kueue.ClusterQueuePreemption{
 	ReclaimWithinCohort: kueue.PreemptionPolicyNever,
 	WithinClusterQueue:  kueue.PreemptionPolicyNever,
+	VictimOrder:         kueue.PreemptionVictimOrderPriority,
+}
+
+var defaultFlavorFungibility = kueue.FlavorFungibility{
+	WhenCanBorrow:   kueue.FlavorFungibilityPolicyBorrow,
+	WhenCanPreempt:  kueue.FlavorFungibilityPolicyTryNextFlavor,
+	PodSetPlacement: kueue.PodSetPlacementPolicyMixed,
 }
 
-func (c *ClusterQueue) update(in *kueue.ClusterQueue, resourceFlavors map[string]*kueue.ResourceFlavor) error {
-	c.RequestableResources = resourcesByName(in.Spec.Resources)
-	c.UpdateCodependentResources()
+func (c *ClusterQueue) update(in *kueue.ClusterQueue, resourceFlavors map[string]*kueue.ResourceFlavor, admissionChecks map[string]*kueue.AdmissionCheck, flavorCapacity map[string]workload.Requests) error {
+	c.resourceGroups = in.Spec.EffectiveResourceGroups()
+	c.quotaSchedules = in.Spec.QuotaSchedules
+	c.autoSized = false
+	for _, rg := range c.resourceGroups {
+		for _, fq := range rg.Flavors {
+			for _, rq := range fq.Resources {
+				c.autoSized = c.autoSized || rq.Quota.MinPercentage != nil
+			}
+		}
+	}
+	for _, qs := range in.Spec.QuotaSchedules {
+		for _, sq := range qs.Quotas {
+			c.autoSized = c.autoSized || sq.Quota.MinPercentage != nil
+		}
+	}
+	c.activeQuotaSchedule = ""
+	if s := activeQuotaSchedule(c.quotaSchedules, time.Now()); s != nil {
+		c.activeQuotaSchedule = s.Name
+	}
+	c.RequestableResources = resourcesByName(c.effectiveResourceGroups(), flavorCapacity)
 	nsSelector, err := metav1.LabelSelectorAsSelector(in.Spec.NamespaceSelector)
 	if err != nil {
 		return err
 	}
 	c.NamespaceSelector = nsSelector
 
-	usedResources := make(ResourceQuantities, len(in.Spec.Resources))
-	for _, r := range in.Spec.Resources {
+	usedResources := make(ResourceQuantities, len(c.RequestableResources))
+	for rName, r := range c.RequestableResources {
 		if len(r.Flavors) == 0 {
 			continue
 		}
 
-		existingUsedFlavors := c.UsedResources[r.Name]
+		existingUsedFlavors := c.UsedResources[rName]
 		usedFlavors := make(map[string]int64, len(r.Flavors))
 		for _, f := range r.Flavors {
-			usedFlavors[string(f.Name)] = existingUsedFlavors[string(f.Name)]
+			usedFlavors[f.Name] = existingUsedFlavors[f.Name]
 		}
-		usedResources[r.Name] = usedFlavors
+		usedResources[rName] = usedFlavors
 	}
 	c.UsedResources = usedResources
+	c.AdmissionChecks = append([]string(nil), in.Spec.AdmissionChecks...)
+	c.stopPolicy = kueue.None
+	if in.Spec.StopPolicy != nil {
+		c.stopPolicy = *in.Spec.StopPolicy
+	}
+	c.overQuotaPolicy = kueue.OverQuotaPolicyKeep
+	if in.Spec.OverQuotaPolicy != nil {
+		c.overQuotaPolicy = *in.Spec.OverQuotaPolicy
+	}
 	c.UpdateWithFlavors(resourceFlavors)
+	c.updateWithAdmissionChecks(admissionChecks)
 
 	if in.Spec.Preemption != nil {
 		c.Preemption = *in.Spec.Preemption
@@ -287,9 +528,71 @@ func (c *ClusterQueue) update(in *kueue.ClusterQueue, resourceFlavors map[string
 		c.Preemption = defaultPreemption
 	}
 
+	if in.Spec.FlavorFungibility != nil {
+		c.FlavorFungibility = *in.Spec.FlavorFungibility
+	} else {
+		c.FlavorFungibility = defaultFlavorFungibility
+	}
+
+	c.SchedulingProfile = kueue.SchedulingProfileOrdered
+	if in.Spec.SchedulingProfile != nil {
+		c.SchedulingProfile = *in.Spec.SchedulingProfile
+	}
+	c.PriorityAging = in.Spec.PriorityAging.DeepCopy()
+
 	return nil
 }
 
+// updateAutoSizedQuotas recomputes the min quotas declared as a percentage of
+// the capacity of the nodes. It returns whether any quota changed.
+func (c *ClusterQueue) updateAutoSizedQuotas(flavorCapacity map[string]workload.Requests) bool {
+	if !c.autoSized {
+		return false
+	}
+	resources := resourcesByName(c.effectiveResourceGroups(), flavorCapacity)
+	changed := false
+	for name, r := range resources {
+		changed = changed || !equality.Semantic.DeepEqual(r.Flavors, c.RequestableResources[name].Flavors)
+	}
+	if !changed {
+		return false
+	}
+	c.RequestableResources = resources
+	c.reportResourceMetrics(true)
+	return true
+}
+
+// effectiveResourceGroups returns the resource groups with the quotas of the
+// active quota schedule, if any.
+func (c *ClusterQueue) effectiveResourceGroups() []kueue.ResourceGroup {
+	for i := range c.quotaSchedules {
+		if c.quotaSchedules[i].Name == c.activeQuotaSchedule {
+			return scheduledResourceGroups(c.resourceGroups, &c.quotaSchedules[i])
+		}
+	}
+	return c.resourceGroups
+}
+
+// updateQuotaSchedule recomputes the quotas if the active quota schedule at
+// the given time changed. It returns whether it changed.
+func (c *ClusterQueue) updateQuotaSchedule(now time.Time, flavorCapacity map[string]workload.Requests) bool {
+	name := ""
+	if s := activeQuotaSchedule(c.quotaSchedules, now); s != nil {
+		name = s.Name
+	}
+	if name == c.activeQuotaSchedule {
+		return false
+	}
+	c.activeQuotaSchedule = name
+	c.RequestableResources = resourcesByName(c.effectiveResourceGroups(), flavorCapacity)
+	c.reportResourceMetrics(true)
+	return true
+}
+
+// UpdateCodependentResources marks as codependent the resources that have
+// the same flavors, which are the resources covered by the same resource
+// group, for a ClusterQueue built without resource groups.
+// Exported only for testing.
 func (c *ClusterQueue) UpdateCodependentResources() {
 	for iName, iRes := range c.RequestableResources {
 		if len(iRes.CodependentResources) > 0 {
@@ -313,8 +616,27 @@ func (c *ClusterQueue) UpdateCodependentResources() {
 // UpdateWithFlavors updates a ClusterQueue based on the passed ResourceFlavors set.
 // Exported only for testing.
 func (c *ClusterQueue) UpdateWithFlavors(flavors map[string]*kueue.ResourceFlavor) {
+	c.missingFlavors = c.updateLabelKeys(flavors)
+	c.updateStatus()
+}
+
+// updateWithAdmissionChecks updates a ClusterQueue based on the passed
+// AdmissionChecks set. The ClusterQueue is pending while any of its
+// AdmissionChecks doesn't exist or isn't active.
+func (c *ClusterQueue) updateWithAdmissionChecks(checks map[string]*kueue.AdmissionCheck) {
+	c.inactiveAdmissionChecks = nil
+	for _, name := range c.AdmissionChecks {
+		ac, found := checks[name]
+		if !found || !apimeta.IsStatusConditionTrue(ac.Status.Conditions, kueue.AdmissionCheckActive) {
+			c.inactiveAdmissionChecks = append(c.inactiveAdmissionChecks, name)
+		}
+	}
+	c.updateStatus()
+}
+
+func (c *ClusterQueue) updateStatus() {
 	status := active
-	if flavorNotFound := c.updateLabelKeys(flavors); flavorNotFound {
+	if len(c.missingFlavors) > 0 || len(c.inactiveAdmissionChecks) > 0 || c.cohortCycle || c.stopPolicy != kueue.None {
 		status = pending
 	}
 
@@ -324,8 +646,27 @@ func (c *ClusterQueue) UpdateWithFlavors(flavors map[string]*kueue.ResourceFlavo
 	metrics.ReportClusterQueueStatus(c.Name, c.Status)
 }
 
-func (c *ClusterQueue) updateLabelKeys(flavors map[string]*kueue.ResourceFlavor) bool {
-	var flavorNotFound bool
+// inactiveReason returns the reason and message of the Active condition of a
+// ClusterQueue that isn't active.
+func (c *ClusterQueue) inactiveReason() (string, string) {
+	switch {
+	case c.Status == terminating:
+		return "Terminating", "Can't admit new workloads; clusterQueue is terminating"
+	case c.stopPolicy != kueue.None:
+		return "Stopped", fmt.Sprintf("Can't admit new workloads; clusterQueue is stopped with the %s policy", c.stopPolicy)
+	case c.cohortCycle:
+		return "InvalidCohort", fmt.Sprintf("Can't admit new workloads; the parents of cohort %s form a cycle", c.Cohort.Name)
+	case len(c.inactiveAdmissionChecks) > 0:
+		return "AdmissionCheckInactive", fmt.Sprintf("Can't admit new workloads; the admission checks %s are not found or inactive", strings.Join(c.inactiveAdmissionChecks, ", "))
+	default:
+		return "FlavorNotFound", fmt.Sprintf("Can't admit new workloads; the flavors %s are not found", strings.Join(c.missingFlavors, ", "))
+	}
+}
+
+// updateLabelKeys updates the label keys of the flavors of each resource. It
+// returns the sorted names of the flavors that don't exist.
+func (c *ClusterQueue) updateLabelKeys(flavors map[string]*kueue.ResourceFlavor) []string {
+	missingFlavors := sets.New[string]()
 	labelKeys := make(map[corev1.ResourceName]sets.Set[string])
 	for rName, res := range c.RequestableResources {
 		if len(res.Flavors) == 0 {
@@ -333,12 +674,15 @@ func (c *ClusterQueue) updateLabelKeys(flavors map[string]*kueue.ResourceFlavor)
 		}
 		resKeys := sets.New[string]()
 		for _, rf := range res.Flavors {
+			if rf.Name == string(kueue.AnyFlavor) {
+				continue
+			}
 			if flv, exist := flavors[rf.Name]; exist {
 				for k := range flv.NodeSelector {
 					resKeys.Insert(k)
 				}
 			} else {
-				flavorNotFound = true
+				missingFlavors.Insert(rf.Name)
 			}
 		}
 
@@ -352,7 +696,7 @@ func (c *ClusterQueue) updateLabelKeys(flavors map[string]*kueue.ResourceFlavor)
 		c.LabelKeys = labelKeys
 	}
 
-	return flavorNotFound
+	return sets.List(missingFlavors)
 }
 
 func (c *ClusterQueue) addWorkload(w *kueue.Workload) error {
@@ -360,13 +704,17 @@ func (c *ClusterQueue) addWorkload(w *kueue.Workload) error {
 	if _, exist := c.Workloads[k]; exist {
 		return fmt.Errorf("workload already exists in ClusterQueue")
 	}
-	wi := workload.NewInfo(w)
+	wi := workload.NewInfo(w, c.workloadInfoOpts...)
+	c.ownWorkloads()
 	c.Workloads[k] = wi
 	c.updateWorkloadUsage(wi, 1)
-	if c.podsReadyTracking && !apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadPodsReady) {
+	if c.podsReadyTracking && !workload.IsReservation(w) && !apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadPodsReady) {
 		c.WorkloadsNotReady.Insert(k)
 	}
-	reportAdmittedActiveWorkloads(wi.ClusterQueue, len(c.Workloads))
+	if apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadAdmitted) {
+		c.admittedWorkloads++
+	}
+	c.reportActiveWorkloads()
 	return nil
 }
 
@@ -377,15 +725,34 @@ func (c *ClusterQueue) deleteWorkload(w *kueue.Workload) {
 		return
 	}
 	c.updateWorkloadUsage(wi, -1)
-	if c.podsReadyTracking && !apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadPodsReady) {
+	if c.podsReadyTracking && !workload.IsReservation(w) && !apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadPodsReady) {
 		c.WorkloadsNotReady.Delete(k)
 	}
+	if apimeta.IsStatusConditionTrue(wi.Obj.Status.Conditions, kueue.WorkloadAdmitted) {
+		c.admittedWorkloads--
+	}
+	c.ownWorkloads()
 	delete(c.Workloads, k)
-	reportAdmittedActiveWorkloads(wi.ClusterQueue, len(c.Workloads))
+	c.reportActiveWorkloads()
+}
+
+// ownWorkloads copies Workloads if it is shared between the cache and a
+// snapshot, so that it can be modified.
+func (c *ClusterQueue) ownWorkloads() {
+	if !c.workloadsShared {
+		return
+	}
+	workloads := make(map[string]*workload.Info, len(c.Workloads)+1)
+	for k, v := range c.Workloads {
+		workloads[k] = v
+	}
+	c.Workloads = workloads
+	c.workloadsShared = false
 }
 
 func (c *ClusterQueue) updateWorkloadUsage(wi *workload.Info, m int64) {
 	updateUsage(wi, c.UsedResources, m)
+	c.reportResourceMetrics(false)
 	qKey := workload.QueueKey(wi.Obj)
 	if _, ok := c.admittedWorkloadsPerQueue[qKey]; ok {
 		c.admittedWorkloadsPerQueue[qKey] += int(m)
@@ -398,6 +765,7 @@ func updateUsage(wi *workload.Info, usedResources ResourceQuantities, m int64) {
 			v, wlResExist := ps.Requests[wlRes]
 			cqResFlv, cqResExist := usedResources[wlRes]
 			if cqResExist && wlResExist {
+				wlResFlv = usageFlavor(cqResFlv, wlResFlv)
 				if _, cqFlvExist := cqResFlv[wlResFlv]; cqFlvExist {
 					cqResFlv[wlResFlv] += v * m
 				}
@@ -406,6 +774,101 @@ func updateUsage(wi *workload.Info, usedResources ResourceQuantities, m int64) {
 	}
 }
 
+// usageFlavor returns the flavor in which the usage of a resource assigned the
+// given flavor is accounted, which is the wildcard flavor if it covers the
+// resource.
+func usageFlavor(usedFlavors map[string]int64, flavor string) string {
+	if _, found := usedFlavors[string(kueue.AnyFlavor)]; found {
+		return string(kueue.AnyFlavor)
+	}
+	return flavor
+}
+
+// reportResourceMetrics reports the usage of the resource flavors of the
+// ClusterQueue and, if withQuotas, replaces the reported quotas.
+func (c *ClusterQueue) reportResourceMetrics(withQuotas bool) {
+	if withQuotas {
+		metrics.ClearClusterQueueResourceMetrics(c.Name)
+	}
+	for rName, res := range c.RequestableResources {
+		for _, f := range res.Flavors {
+			if withQuotas {
+				var borrowingLimit *float64
+				if f.Max != nil {
+					borrowingLimit = pointer.Float64(resourceFloat(rName, *f.Max-f.Min))
+				}
+				metrics.ReportClusterQueueQuotas(c.Name, f.Name, string(rName), resourceFloat(rName, f.Min), borrowingLimit)
+			}
+			used := c.UsedResources[rName][f.Name]
+			var borrowing int64
+			if used > f.Min {
+				borrowing = used - f.Min
+			}
+			metrics.ReportClusterQueueResourceUsage(c.Name, f.Name, string(rName), resourceFloat(rName, used), resourceFloat(rName, borrowing))
+		}
+	}
+}
+
+// resourceFloat converts a resource value of the cache to the units of the
+// resource, for example, cores for CPU.
+func resourceFloat(rName corev1.ResourceName, v int64) float64 {
+	q := workload.ResourceQuantity(rName, v)
+	return q.AsApproximateFloat64()
+}
+
+// flavorLimits returns the limits of the flavor for the resource, or nil if
+// the ClusterQueue doesn't define them.
+func (c *ClusterQueue) flavorLimits(rName corev1.ResourceName, flavor string) *FlavorLimits {
+	res := c.RequestableResources[rName]
+	if res == nil {
+		return nil
+	}
+	for i := range res.Flavors {
+		if res.Flavors[i].Name == flavor {
+			return &res.Flavors[i]
+		}
+	}
+	return nil
+}
+
+// CoveredByAnyFlavor returns whether the resource is covered by the wildcard
+// flavor.
+func (c *ClusterQueue) CoveredByAnyFlavor(rName corev1.ResourceName) bool {
+	res := c.RequestableResources[rName]
+	return res != nil && len(res.Flavors) == 1 && res.Flavors[0].Name == string(kueue.AnyFlavor)
+}
+
+// HasNamespaceLimits returns whether any flavor of the ClusterQueue limits
+// the usage of a single namespace.
+func (c *ClusterQueue) HasNamespaceLimits() bool {
+	for _, r := range c.RequestableResources {
+		for _, f := range r.Flavors {
+			if f.MaxPerNamespace != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NamespaceUsage returns the resources used by the workloads of the given
+// namespace admitted by the ClusterQueue.
+func (c *ClusterQueue) NamespaceUsage(namespace string) ResourceQuantities {
+	usage := make(ResourceQuantities, len(c.UsedResources))
+	for res, flavors := range c.UsedResources {
+		usage[res] = make(map[string]int64, len(flavors))
+		for flv := range flavors {
+			usage[res][flv] = 0
+		}
+	}
+	for _, wl := range c.Workloads {
+		if wl.Obj.Namespace == namespace {
+			updateUsage(wl, usage, 1)
+		}
+	}
+	return usage
+}
+
 func (c *ClusterQueue) addLocalQueue(q *kueue.LocalQueue) error {
 	qKey := queueKey(q)
 	if _, ok := c.admittedWorkloadsPerQueue[qKey]; ok {
@@ -448,6 +911,7 @@ func (c *Cache) updateClusterQueues() sets.Set[string] {
 		// because it is not expensive to do so, and is not worth tracking which ClusterQueues use
 		// which flavors.
 		cq.UpdateWithFlavors(c.resourceFlavors)
+		cq.updateWithAdmissionChecks(c.admissionChecks)
 		curStatus := cq.Status
 		if prevStatus == pending && curStatus == active {
 			cqs.Insert(cq.Name)
@@ -460,16 +924,271 @@ func (c *Cache) AddOrUpdateResourceFlavor(rf *kueue.ResourceFlavor) sets.Set[str
 	c.Lock()
 	defer c.Unlock()
 	c.resourceFlavors[rf.Name] = rf
-	return c.updateClusterQueues()
+	c.flavorNodeResources = nil
+	c.flavorTopologies = nil
+	c.flavorCapacity = nil
+	return c.updateClusterQueues().Union(c.updateAutoSizedQuotas())
 }
 
 func (c *Cache) DeleteResourceFlavor(rf *kueue.ResourceFlavor) sets.Set[string] {
 	c.Lock()
 	defer c.Unlock()
 	delete(c.resourceFlavors, rf.Name)
+	c.flavorNodeResources = nil
+	c.flavorTopologies = nil
+	c.flavorCapacity = nil
+	return c.updateClusterQueues().Union(c.updateAutoSizedQuotas())
+}
+
+// AddOrUpdateAdmissionCheck adds or updates the AdmissionCheck. It returns the
+// names of the ClusterQueues that changed status.
+func (c *Cache) AddOrUpdateAdmissionCheck(ac *kueue.AdmissionCheck) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	c.admissionChecks[ac.Name] = ac
+	return c.updateClusterQueues()
+}
+
+// DeleteAdmissionCheck removes the AdmissionCheck. It returns the names of the
+// ClusterQueues that changed status.
+func (c *Cache) DeleteAdmissionCheck(ac *kueue.AdmissionCheck) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.admissionChecks, ac.Name)
 	return c.updateClusterQueues()
 }
 
+// ClusterQueuesUsingAdmissionCheck returns the names of the ClusterQueues that
+// use the AdmissionCheck.
+func (c *Cache) ClusterQueuesUsingAdmissionCheck(name string) []string {
+	c.RLock()
+	defer c.RUnlock()
+	var cqs []string
+	for _, cq := range c.clusterQueues {
+		for _, check := range cq.AdmissionChecks {
+			if check == name {
+				cqs = append(cqs, cq.Name)
+				break
+			}
+		}
+	}
+	return cqs
+}
+
+// ClusterQueueInactiveAdmissionChecks returns whether any of the
+// AdmissionChecks of the ClusterQueue doesn't exist or isn't active.
+func (c *Cache) ClusterQueueInactiveAdmissionChecks(name string) bool {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueues[name]
+	return cq != nil && len(cq.inactiveAdmissionChecks) > 0
+}
+
+// ClusterQueueInactiveReason returns the reason and message explaining why the
+// ClusterQueue isn't active, or empty strings if it's active or doesn't exist.
+func (c *Cache) ClusterQueueInactiveReason(name string) (string, string) {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueues[name]
+	if cq == nil || cq.Active() {
+		return "", ""
+	}
+	return cq.inactiveReason()
+}
+
+// ClusterQueueStopPolicy returns the stop policy of the ClusterQueue, or None
+// if the ClusterQueue doesn't exist.
+func (c *Cache) ClusterQueueStopPolicy(name string) kueue.StopPolicy {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueues[name]
+	if cq == nil {
+		return kueue.None
+	}
+	return cq.stopPolicy
+}
+
+// ClusterQueueWorkloads returns the workloads that reserved quota in the
+// ClusterQueue.
+func (c *Cache) ClusterQueueWorkloads(name string) []*kueue.Workload {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueues[name]
+	if cq == nil {
+		return nil
+	}
+	workloads := make([]*kueue.Workload, 0, len(cq.Workloads))
+	for _, info := range cq.Workloads {
+		workloads = append(workloads, info.Obj)
+	}
+	return workloads
+}
+
+// nodeInfo holds the labels of a node and the extended resources that it
+// exposes. The allocatable capacity is only held when tracking topologies or
+// auto-sizing quotas.
+type nodeInfo struct {
+	labels    labels.Set
+	resources sets.Set[corev1.ResourceName]
+	capacity  workload.Requests
+}
+
+func newNodeInfo(node *corev1.Node, withCapacity bool) *nodeInfo {
+	info := &nodeInfo{
+		labels:    labels.Merge(nil, node.Labels),
+		resources: sets.New[corev1.ResourceName](),
+	}
+	if withCapacity {
+		info.capacity = make(workload.Requests, len(node.Status.Allocatable))
+	}
+	for name, q := range node.Status.Allocatable {
+		if workload.IsExtendedResource(name) && !q.IsZero() {
+			info.resources.Insert(name)
+		}
+		if withCapacity {
+			info.capacity[name] = workload.ResourceValue(name, q)
+		}
+	}
+	return info
+}
+
+func (n *nodeInfo) equal(other *nodeInfo) bool {
+	return labels.Equals(n.labels, other.labels) && n.resources.Equal(other.resources) && equality.Semantic.DeepEqual(n.capacity, other.capacity)
+}
+
+// AddOrUpdateNode updates the labels and extended resources of the node. It
+// returns the names of the ClusterQueues using flavors that select the node,
+// before or after the update, if the node changed.
+func (c *Cache) AddOrUpdateNode(node *corev1.Node) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	info := newNodeInfo(node, c.topologyTracking || c.quotaAutoSizing)
+	oldInfo := c.nodes[node.Name]
+	if oldInfo != nil && oldInfo.equal(info) {
+		return nil
+	}
+	c.nodes[node.Name] = info
+	c.flavorNodeResources = nil
+	c.flavorTopologies = nil
+	c.flavorCapacity = nil
+	return c.clusterQueuesSelectingNodes(oldInfo, info).Union(c.updateAutoSizedQuotas())
+}
+
+// DeleteNode removes the node. It returns the names of the ClusterQueues using
+// flavors that selected the node.
+func (c *Cache) DeleteNode(node *corev1.Node) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	info := c.nodes[node.Name]
+	if info == nil {
+		return nil
+	}
+	delete(c.nodes, node.Name)
+	c.flavorNodeResources = nil
+	c.flavorTopologies = nil
+	c.flavorCapacity = nil
+	return c.clusterQueuesSelectingNodes(info).Union(c.updateAutoSizedQuotas())
+}
+
+func (c *Cache) clusterQueuesSelectingNodes(nodes ...*nodeInfo) sets.Set[string] {
+	cqs := sets.New[string]()
+	for _, rf := range c.resourceFlavors {
+		selector := labels.SelectorFromSet(rf.NodeSelector)
+		for _, n := range nodes {
+			if n == nil || !selector.Matches(n.labels) {
+				continue
+			}
+			for _, cq := range c.clusterQueues {
+				if cq.flavorInUse(rf.Name) {
+					cqs.Insert(cq.Name)
+				}
+			}
+			break
+		}
+	}
+	return cqs
+}
+
+// nodeResourcesPerFlavor returns the extended resources exposed by the nodes
+// selected by each flavor. It must be called with the write lock held.
+func (c *Cache) nodeResourcesPerFlavor() map[string]sets.Set[corev1.ResourceName] {
+	if c.flavorNodeResources != nil {
+		return c.flavorNodeResources
+	}
+	c.flavorNodeResources = make(map[string]sets.Set[corev1.ResourceName], len(c.resourceFlavors))
+	for _, rf := range c.resourceFlavors {
+		selector := labels.SelectorFromSet(rf.NodeSelector)
+		resources := sets.New[corev1.ResourceName]()
+		for _, n := range c.nodes {
+			if selector.Matches(n.labels) {
+				resources.Insert(n.resources.UnsortedList()...)
+			}
+		}
+		c.flavorNodeResources[rf.Name] = resources
+	}
+	return c.flavorNodeResources
+}
+
+// capacityPerFlavor returns the allocatable capacity of the nodes selected by
+// each flavor, or nil if the cache doesn't auto-size quotas. It must be
+// called with the write lock held.
+func (c *Cache) capacityPerFlavor() map[string]workload.Requests {
+	if !c.quotaAutoSizing {
+		return nil
+	}
+	if c.flavorCapacity != nil {
+		return c.flavorCapacity
+	}
+	c.flavorCapacity = make(map[string]workload.Requests, len(c.resourceFlavors))
+	for _, rf := range c.resourceFlavors {
+		selector := labels.SelectorFromSet(rf.NodeSelector)
+		capacity := make(workload.Requests)
+		for _, n := range c.nodes {
+			if !selector.Matches(n.labels) {
+				continue
+			}
+			for r, v := range n.capacity {
+				capacity[r] += v
+			}
+		}
+		c.flavorCapacity[rf.Name] = capacity
+	}
+	return c.flavorCapacity
+}
+
+// updateAutoSizedQuotas recomputes the min quotas declared as a percentage of
+// the capacity of the nodes. It returns the names of the ClusterQueues whose
+// quotas changed. It must be called with the write lock held.
+func (c *Cache) updateAutoSizedQuotas() sets.Set[string] {
+	cqs := sets.New[string]()
+	if !c.quotaAutoSizing {
+		return cqs
+	}
+	capacity := c.capacityPerFlavor()
+	for _, cq := range c.clusterQueues {
+		if cq.updateAutoSizedQuotas(capacity) {
+			cqs.Insert(cq.Name)
+		}
+	}
+	return cqs
+}
+
+// UpdateQuotaSchedule recomputes the quotas of the ClusterQueue if its active
+// quota schedule changed at the given time. It returns whether it changed, and
+// the next time at which a window of its quota schedules starts or ends, which
+// is zero if it has no quota schedules.
+func (c *Cache) UpdateQuotaSchedule(name string, now time.Time) (bool, time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	cq, ok := c.clusterQueues[name]
+	if !ok {
+		return false, time.Time{}
+	}
+	changed := cq.updateQuotaSchedule(now, c.capacityPerFlavor())
+	next, _ := nextQuotaScheduleBoundary(cq.quotaSchedules, now)
+	return changed, next
+}
+
 func (c *Cache) ClusterQueueActive(name string) bool {
 	return c.clusterQueueInStatus(name, active)
 }
@@ -522,6 +1241,7 @@ func (c *Cache) AddClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) err
 	if err != nil {
 		return err
 	}
+	cqImpl.reportResourceMetrics(true)
 	c.addClusterQueueToCohort(cqImpl, cq.Spec.Cohort)
 	c.clusterQueues[cq.Name] = cqImpl
 
@@ -563,9 +1283,10 @@ func (c *Cache) UpdateClusterQueue(cq *kueue.ClusterQueue) error {
 	if !ok {
 		return errCqNotFound
 	}
-	if err := cqImpl.update(cq, c.resourceFlavors); err != nil {
+	if err := cqImpl.update(cq, c.resourceFlavors, c.admissionChecks, c.capacityPerFlavor()); err != nil {
 		return err
 	}
+	cqImpl.reportResourceMetrics(true)
 
 	if cqImpl.Cohort == nil {
 		c.addClusterQueueToCohort(cqImpl, cq.Spec.Cohort)
@@ -645,6 +1366,7 @@ func (c *Cache) addOrUpdateWorkload(w *kueue.Workload) bool {
 	}
 
 	c.cleanupAssumedState(w)
+	delete(c.reservations, workload.Key(w))
 
 	if _, exist := clusterQueue.Workloads[workload.Key(w)]; exist {
 		clusterQueue.deleteWorkload(w)
@@ -722,9 +1444,45 @@ func (c *Cache) AssumeWorkload(w *kueue.Workload) error {
 		return err
 	}
 	c.assumedWorkloads[k] = string(w.Spec.Admission.ClusterQueue)
+	delete(c.reservations, k)
 	return nil
 }
 
+// ReserveQuota reserves, until expiresAt, the quota described by the admission
+// of a pending workload in its ClusterQueue. The reserved quota counts as used
+// in the snapshots, except for the workload itself. The reservation is
+// released when the workload is admitted or when calling ReleaseQuota.
+func (c *Cache) ReserveQuota(w *kueue.Workload, expiresAt time.Time) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if w.Spec.Admission == nil {
+		return errWorkloadNotAdmitted
+	}
+	if _, ok := c.clusterQueues[string(w.Spec.Admission.ClusterQueue)]; !ok {
+		return errCqNotFound
+	}
+
+	now := time.Now()
+	for k, r := range c.reservations {
+		if !r.expiresAt.After(now) {
+			delete(c.reservations, k)
+		}
+	}
+	c.reservations[workload.Key(w)] = &reservation{
+		info:      workload.NewInfo(w, c.workloadInfoOpts...),
+		expiresAt: expiresAt,
+	}
+	return nil
+}
+
+// ReleaseQuota releases the quota reserved for a workload, if any.
+func (c *Cache) ReleaseQuota(w *kueue.Workload) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.reservations, workload.Key(w))
+}
+
 func (c *Cache) ForgetWorkload(w *kueue.Workload) error {
 	c.Lock()
 	defer c.Unlock()
@@ -794,6 +1552,27 @@ func (c *Cache) cleanupAssumedState(w *kueue.Workload) {
 	}
 }
 
+// AdmittedGroupMembers returns the admitted workloads that belong to the same
+// group of workloads as the given workload, excluding the given workload.
+func (c *Cache) AdmittedGroupMembers(w *kueue.Workload) []*kueue.Workload {
+	c.RLock()
+	defer c.RUnlock()
+	group := workload.GroupName(w)
+	key := workload.Key(w)
+	var members []*kueue.Workload
+	for _, cq := range c.clusterQueues {
+		for k, info := range cq.Workloads {
+			if k != key && info.Obj.Namespace == w.Namespace && workload.GroupName(info.Obj) == group {
+				members = append(members, info.Obj)
+			}
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+	return members
+}
+
 func (c *Cache) clusterQueueForWorkload(w *kueue.Workload) *ClusterQueue {
 	if w.Spec.Admission != nil {
 		return c.clusterQueues[string(w.Spec.Admission.ClusterQueue)]
@@ -807,6 +1586,81 @@ func (c *Cache) clusterQueueForWorkload(w *kueue.Workload) *ClusterQueue {
 	return nil
 }
 
+// AddOrUpdateCohort sets the configuration of the cohort with the name of the
+// given Cohort object.
+func (c *Cache) AddOrUpdateCohort(cohort *kueue.Cohort) {
+	c.Lock()
+	defer c.Unlock()
+	c.cohortConfigs[cohort.Name] = &cohortConfig{
+		parent:    cohort.Spec.Parent,
+		resources: resourcesByName(cohort.Spec.EffectiveResourceGroups(), nil),
+	}
+	c.updateCohortCycles()
+}
+
+// DeleteCohort removes the configuration of the cohort with the name of the
+// given Cohort object.
+func (c *Cache) DeleteCohort(cohort *kueue.Cohort) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.cohortConfigs, cohort.Name)
+	c.updateCohortCycles()
+}
+
+// ClusterQueuesUnderCohort returns the names of the ClusterQueues that belong
+// to the cohort, directly or through other cohorts.
+func (c *Cache) ClusterQueuesUnderCohort(name string) []string {
+	c.RLock()
+	defer c.RUnlock()
+	var cqs []string
+	for _, cq := range c.clusterQueues {
+		if cq.Cohort == nil {
+			continue
+		}
+		if ancestors, _ := c.cohortAncestors(cq.Cohort.Name); ancestors.Has(name) {
+			cqs = append(cqs, cq.Name)
+		}
+	}
+	return cqs
+}
+
+// cohortAncestors returns the names of the cohort and its ancestors, and
+// whether its ancestors form a cycle.
+func (c *Cache) cohortAncestors(name string) (sets.Set[string], bool) {
+	ancestors := sets.New[string]()
+	for name != "" {
+		if ancestors.Has(name) {
+			return ancestors, true
+		}
+		ancestors.Insert(name)
+		cfg := c.cohortConfigs[name]
+		if cfg == nil {
+			break
+		}
+		name = cfg.parent
+	}
+	return ancestors, false
+}
+
+// updateCohortCycles updates whether the cohorts of the ClusterQueues belong
+// to a cycle of parents, which makes the ClusterQueues inactive.
+func (c *Cache) updateCohortCycles() {
+	for _, cq := range c.clusterQueues {
+		c.updateCohortCycle(cq)
+	}
+}
+
+func (c *Cache) updateCohortCycle(cq *ClusterQueue) {
+	cycle := false
+	if cq.Cohort != nil {
+		_, cycle = c.cohortAncestors(cq.Cohort.Name)
+	}
+	if cycle != cq.cohortCycle {
+		cq.cohortCycle = cycle
+		cq.updateStatus()
+	}
+}
+
 func (c *Cache) addClusterQueueToCohort(cq *ClusterQueue, cohortName string) {
 	if cohortName == "" {
 		return
@@ -818,6 +1672,7 @@ func (c *Cache) addClusterQueueToCohort(cq *ClusterQueue, cohortName string) {
 	}
 	cohort.Members.Insert(cq)
 	cq.Cohort = cohort
+	c.updateCohortCycle(cq)
 }
 
 func (c *Cache) deleteClusterQueueFromCohort(cq *ClusterQueue) {
@@ -829,6 +1684,7 @@ func (c *Cache) deleteClusterQueueFromCohort(cq *ClusterQueue) {
 		delete(c.cohorts, cq.Cohort.Name)
 	}
 	cq.Cohort = nil
+	c.updateCohortCycle(cq)
 }
 
 func (c *Cache) ClusterQueuesUsingFlavor(flavor string) []string {
@@ -844,6 +1700,65 @@ func (c *Cache) ClusterQueuesUsingFlavor(flavor string) []string {
 	return cqs
 }
 
+// WorkloadsUsingFlavor returns the workloads that reserved quota in the
+// flavor.
+func (c *Cache) WorkloadsUsingFlavor(flavor string) []*kueue.Workload {
+	c.RLock()
+	defer c.RUnlock()
+	var workloads []*kueue.Workload
+	for _, cq := range c.clusterQueues {
+		for _, wi := range cq.Workloads {
+			if wi.UsesFlavor(flavor) {
+				workloads = append(workloads, wi.Obj)
+			}
+		}
+	}
+	return workloads
+}
+
+// NewWorkloadInfo returns the information of the workload, with the requests
+// computed like the ones of the workloads in the cache.
+func (c *Cache) NewWorkloadInfo(w *kueue.Workload) *workload.Info {
+	return workload.NewInfo(w, c.workloadInfoOpts...)
+}
+
+// WorkloadInDeletedFlavor returns whether the workload reserved quota in a
+// flavor that is deleted, or being deleted, and that its ClusterQueue no
+// longer has for the resource. The usage of such a workload isn't accounted
+// in the ClusterQueue.
+func (c *Cache) WorkloadInDeletedFlavor(w *kueue.Workload) bool {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueueForWorkload(w)
+	if cq == nil {
+		return false
+	}
+	wi := cq.Workloads[workload.Key(w)]
+	if wi == nil {
+		return false
+	}
+	for _, ps := range wi.TotalRequests {
+		for rName, flavor := range ps.Flavors {
+			if cq.flavorLimits(rName, flavor) != nil {
+				continue
+			}
+			if rf, found := c.resourceFlavors[flavor]; !found || rf.DeletionTimestamp != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResourceFlavorTerminating returns whether the ResourceFlavor is being
+// deleted.
+func (c *Cache) ResourceFlavorTerminating(name string) bool {
+	c.RLock()
+	defer c.RUnlock()
+	rf, found := c.resourceFlavors[name]
+	return found && rf.DeletionTimestamp != nil
+}
+
 func (c *Cache) MatchingClusterQueues(nsLabels map[string]string) sets.Set[string] {
 	c.RLock()
 	defer c.RUnlock()
@@ -858,28 +1773,69 @@ func (c *Cache) MatchingClusterQueues(nsLabels map[string]string) sets.Set[strin
 	return cqs
 }
 
-func resourcesByName(in []kueue.Resource) map[corev1.ResourceName]*Resource {
-	out := make(map[corev1.ResourceName]*Resource, len(in))
-	for _, r := range in {
-		flavors := make([]FlavorLimits, len(r.Flavors))
-		for i := range flavors {
-			f := &r.Flavors[i]
-			fLimits := FlavorLimits{
-				Name: string(f.Name),
-				Min:  workload.ResourceValue(r.Name, f.Quota.Min),
+// resourcesByName processes the quotas of the resource groups. The resources
+// covered by a group are codependent. The min quotas declared as a percentage
+// are computed from the capacity of the nodes of each flavor, and are 0 if
+// flavorCapacity is nil.
+func resourcesByName(groups []kueue.ResourceGroup, flavorCapacity map[string]workload.Requests) map[corev1.ResourceName]*Resource {
+	out := make(map[corev1.ResourceName]*Resource)
+	for _, rg := range groups {
+		var codep sets.Set[corev1.ResourceName]
+		if len(rg.CoveredResources) > 1 {
+			codep = sets.New(rg.CoveredResources...)
+		}
+		for _, rName := range rg.CoveredResources {
+			flavors := make([]FlavorLimits, 0, len(rg.Flavors))
+			for _, fq := range rg.Flavors {
+				for _, rq := range fq.Resources {
+					if rq.Name == rName {
+						flavors = append(flavors, flavorLimits(rName, string(fq.Name), &rq.Quota, flavorCapacity))
+						break
+					}
+				}
 			}
-			if f.Quota.Max != nil {
-				fLimits.Max = pointer.Int64(workload.ResourceValue(r.Name, *f.Quota.Max))
+			out[rName] = &Resource{
+				CodependentResources: codep,
+				Flavors:              flavors,
 			}
-			flavors[i] = fLimits
-		}
-		out[r.Name] = &Resource{
-			Flavors: flavors,
 		}
 	}
 	return out
 }
 
+func flavorLimits(rName corev1.ResourceName, flavor string, quota *kueue.Quota, flavorCapacity map[string]workload.Requests) FlavorLimits {
+	fLimits := FlavorLimits{
+		Name: flavor,
+		Min:  workload.ResourceValue(rName, quota.Min),
+	}
+	if quota.MinPercentage != nil {
+		fLimits.Min = flavorCapacity[flavor][rName] * int64(*quota.MinPercentage) / 100
+	}
+	if quota.Max != nil {
+		fLimits.Max = pointer.Int64(workload.ResourceValue(rName, *quota.Max))
+		if fLimits.Min > *fLimits.Max {
+			fLimits.Min = *fLimits.Max
+		}
+	}
+	if quota.BorrowingLimit != nil {
+		// The borrowing limit is enforced as a max quota.
+		ceiling := fLimits.Min + workload.ResourceValue(rName, *quota.BorrowingLimit)
+		if fLimits.Max == nil || ceiling < *fLimits.Max {
+			fLimits.Max = pointer.Int64(ceiling)
+		}
+	}
+	if quota.LendingLimit != nil {
+		fLimits.LendingLimit = pointer.Int64(workload.ResourceValue(rName, *quota.LendingLimit))
+		if *fLimits.LendingLimit > fLimits.Min {
+			fLimits.LendingLimit = pointer.Int64(fLimits.Min)
+		}
+	}
+	if quota.MaxPerNamespace != nil {
+		fLimits.MaxPerNamespace = pointer.Int64(workload.ResourceValue(rName, *quota.MaxPerNamespace))
+	}
+	return fLimits
+}
+
 func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
 	return indexer.IndexField(ctx, &kueue.Workload{}, workloadClusterQueueKey, func(o client.Object) []string {
 		wl := o.(*kueue.Workload)
@@ -899,6 +1855,8 @@ func queueKey(q *kueue.LocalQueue) string {
 	return fmt.Sprintf("%s/%s", q.Namespace, q.Name)
 }
 
-func reportAdmittedActiveWorkloads(cqName string, val int) {
-	metrics.AdmittedActiveWorkloads.WithLabelValues(cqName).Set(float64(val))
+// reportActiveWorkloads reports the number of workloads that reserve quota in
+// the ClusterQueue and the number of them that are admitted.
+func (c *ClusterQueue) reportActiveWorkloads() {
+	metrics.ReportActiveWorkloads(c.Name, len(c.Workloads), c.admittedWorkloads)
 }
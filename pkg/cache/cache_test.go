@@ -23,6 +23,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -45,16 +47,23 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "a"},
 			Spec: kueue.ClusterQueueSpec{
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{{
-							Name: "default",
-							Quota: kueue.Quota{
-								Min: resource.MustParse("10"),
-								Max: pointer.Quantity(resource.MustParse("20")),
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+						Flavors: []kueue.FlavorQuotas{
+							{
+								Name: "default",
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("10"),
+											Max: pointer.Quantity(resource.MustParse("20")),
+										},
+									},
+								},
 							},
-						}},
+						},
 					},
 				},
 				Cohort: "one",
@@ -63,15 +72,22 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "b"},
 			Spec: kueue.ClusterQueueSpec{
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{{
-							Name: "default",
-							Quota: kueue.Quota{
-								Min: resource.MustParse("15"),
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+						Flavors: []kueue.FlavorQuotas{
+							{
+								Name: "default",
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("15"),
+										},
+									},
+								},
 							},
-						}},
+						},
 					},
 				},
 				Cohort: "one",
@@ -87,15 +103,22 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "e"},
 			Spec: kueue.ClusterQueueSpec{
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{{
-							Name: "nonexistent-flavor",
-							Quota: kueue.Quota{
-								Min: resource.MustParse("15"),
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+						Flavors: []kueue.FlavorQuotas{
+							{
+								Name: "nonexistent-flavor",
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("15"),
+										},
+									},
+								},
 							},
-						}},
+						},
 					},
 				},
 				Cohort: "two",
@@ -143,6 +166,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:     ResourceQuantities{corev1.ResourceCPU: {"default": 0}},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"b": {
 					Name: "b",
@@ -154,6 +179,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         map[corev1.ResourceName]sets.Set[string]{corev1.ResourceCPU: sets.New("cpuType")},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"c": {
 					Name:                 "c",
@@ -162,6 +189,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"d": {
 					Name:                 "d",
@@ -170,6 +199,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"e": {
 					Name: "e",
@@ -181,6 +212,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         nil,
 					Status:            pending,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 			},
 			wantCohorts: map[string]sets.Set[string]{
@@ -208,6 +241,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 						ReclaimWithinCohort: kueue.PreemptionPolicyLowerPriority,
 						WithinClusterQueue:  kueue.PreemptionPolicyLowerPriority,
 					},
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 			},
 		},
@@ -237,6 +272,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:     ResourceQuantities{corev1.ResourceCPU: {"default": 0}},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"b": {
 					Name: "b",
@@ -248,6 +285,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         map[corev1.ResourceName]sets.Set[string]{corev1.ResourceCPU: sets.New("cpuType")},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"c": {
 					Name:                 "c",
@@ -256,6 +295,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"d": {
 					Name:                 "d",
@@ -264,6 +305,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"e": {
 					Name: "e",
@@ -275,6 +318,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         nil,
 					Status:            pending,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 			},
 			wantCohorts: map[string]sets.Set[string]{
@@ -290,19 +335,25 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					{
 						ObjectMeta: metav1.ObjectMeta{Name: "a"},
 						Spec: kueue.ClusterQueueSpec{
-							Resources: []kueue.Resource{
+							ResourceGroups: []kueue.ResourceGroup{
 								{
-									Name: corev1.ResourceCPU,
-									Flavors: []kueue.Flavor{
+									CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+									Flavors: []kueue.FlavorQuotas{
 										{
 											Name: "default",
-											Quota: kueue.Quota{
-												Min: resource.MustParse("5"),
-												Max: pointer.Quantity(resource.MustParse("10")),
+											Resources: []kueue.ResourceQuota{
+												{
+													Name: corev1.ResourceCPU,
+													Quota: kueue.Quota{
+														Min: resource.MustParse("5"),
+														Max: pointer.Quantity(resource.MustParse("10")),
+													},
+												},
 											},
 										},
 									},
-								}},
+								},
+							},
 							Cohort: "two",
 						},
 					},
@@ -317,19 +368,25 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					{
 						ObjectMeta: metav1.ObjectMeta{Name: "e"},
 						Spec: kueue.ClusterQueueSpec{
-							Resources: []kueue.Resource{
+							ResourceGroups: []kueue.ResourceGroup{
 								{
-									Name: corev1.ResourceCPU,
-									Flavors: []kueue.Flavor{
+									CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+									Flavors: []kueue.FlavorQuotas{
 										{
 											Name: "default",
-											Quota: kueue.Quota{
-												Min: resource.MustParse("5"),
-												Max: pointer.Quantity(resource.MustParse("10")),
+											Resources: []kueue.ResourceQuota{
+												{
+													Name: corev1.ResourceCPU,
+													Quota: kueue.Quota{
+														Min: resource.MustParse("5"),
+														Max: pointer.Quantity(resource.MustParse("10")),
+													},
+												},
 											},
 										},
 									},
-								}},
+								},
+							},
 							Cohort: "two",
 						},
 					},
@@ -357,6 +414,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:     ResourceQuantities{corev1.ResourceCPU: {"default": 0}},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"b": {
 					Name:                 "b",
@@ -365,6 +424,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"c": {
 					Name:                 "c",
@@ -373,6 +434,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"d": {
 					Name:                 "d",
@@ -381,6 +444,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"e": {
 					Name: "e",
@@ -392,6 +457,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         map[corev1.ResourceName]sets.Set[string]{corev1.ResourceCPU: sets.New("cpuType", "region")},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 			},
 			wantCohorts: map[string]sets.Set[string]{
@@ -422,6 +489,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         map[corev1.ResourceName]sets.Set[string]{corev1.ResourceCPU: sets.New("cpuType")},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"c": {
 					Name:                 "c",
@@ -430,6 +499,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"e": {
 					Name: "e",
@@ -441,6 +512,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         nil,
 					Status:            pending,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 			},
 			wantCohorts: map[string]sets.Set[string]{
@@ -467,6 +540,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:     ResourceQuantities{corev1.ResourceCPU: {"default": 0}},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"b": {
 					Name: "b",
@@ -478,6 +553,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         map[corev1.ResourceName]sets.Set[string]{corev1.ResourceCPU: sets.New("cpuType")},
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 				"c": {
 					Name:                 "c",
@@ -486,6 +563,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"d": {
 					Name:                 "d",
@@ -494,6 +573,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					UsedResources:        ResourceQuantities{},
 					Status:               active,
 					Preemption:           defaultPreemption,
+					FlavorFungibility:    defaultFlavorFungibility,
+					SchedulingProfile:    kueue.SchedulingProfileOrdered,
 				},
 				"e": {
 					Name: "e",
@@ -505,6 +586,8 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 					LabelKeys:         nil,
 					Status:            active,
 					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 			},
 			wantCohorts: map[string]sets.Set[string]{
@@ -521,26 +604,41 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 							Name: "foo",
 						},
 						Spec: kueue.ClusterQueueSpec{
-							Resources: []kueue.Resource{
-								{
-									Name: "cpu",
-									Flavors: []kueue.Flavor{
-										{Name: "foo"},
-										{Name: "bar"},
-									},
-								},
+							ResourceGroups: []kueue.ResourceGroup{
 								{
-									Name: "memory",
-									Flavors: []kueue.Flavor{
-										{Name: "foo"},
-										{Name: "bar"},
+									CoveredResources: []corev1.ResourceName{"cpu", "memory"},
+									Flavors: []kueue.FlavorQuotas{
+										{
+											Name: "foo",
+											Resources: []kueue.ResourceQuota{
+												{Name: "cpu"},
+												{Name: "memory"},
+											},
+										},
+										{
+											Name: "bar",
+											Resources: []kueue.ResourceQuota{
+												{Name: "cpu"},
+												{Name: "memory"},
+											},
+										},
 									},
 								},
 								{
-									Name: "example.com/gpu",
-									Flavors: []kueue.Flavor{
-										{Name: "theta"},
-										{Name: "gamma"},
+									CoveredResources: []corev1.ResourceName{"example.com/gpu"},
+									Flavors: []kueue.FlavorQuotas{
+										{
+											Name: "theta",
+											Resources: []kueue.ResourceQuota{
+												{Name: "example.com/gpu"},
+											},
+										},
+										{
+											Name: "gamma",
+											Resources: []kueue.ResourceQuota{
+												{Name: "example.com/gpu"},
+											},
+										},
 									},
 								},
 							},
@@ -590,8 +688,10 @@ func TestCacheClusterQueueOperations(t *testing.T) {
 							"gamma": 0,
 						},
 					},
-					Status:     pending,
-					Preemption: defaultPreemption,
+					Status:            pending,
+					Preemption:        defaultPreemption,
+					FlavorFungibility: defaultFlavorFungibility,
+					SchedulingProfile: kueue.SchedulingProfileOrdered,
 				},
 			},
 		},
@@ -627,12 +727,22 @@ func TestCacheWorkloadOperations(t *testing.T) {
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "one"},
 			Spec: kueue.ClusterQueueSpec{
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: "cpu",
-						Flavors: []kueue.Flavor{
-							{Name: "on-demand"},
-							{Name: "spot"},
+						CoveredResources: []corev1.ResourceName{"cpu"},
+						Flavors: []kueue.FlavorQuotas{
+							{
+								Name: "on-demand",
+								Resources: []kueue.ResourceQuota{
+									{Name: "cpu"},
+								},
+							},
+							{
+								Name: "spot",
+								Resources: []kueue.ResourceQuota{
+									{Name: "cpu"},
+								},
+							},
 						},
 					},
 				},
@@ -641,12 +751,22 @@ func TestCacheWorkloadOperations(t *testing.T) {
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "two"},
 			Spec: kueue.ClusterQueueSpec{
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: "cpu",
-						Flavors: []kueue.Flavor{
-							{Name: "on-demand"},
-							{Name: "spot"},
+						CoveredResources: []corev1.ResourceName{"cpu"},
+						Flavors: []kueue.FlavorQuotas{
+							{
+								Name: "on-demand",
+								Resources: []kueue.ResourceQuota{
+									{Name: "cpu"},
+								},
+							},
+							{
+								Name: "spot",
+								Resources: []kueue.ResourceQuota{
+									{Name: "cpu"},
+								},
+							},
 						},
 					},
 				},
@@ -1165,34 +1285,48 @@ func TestClusterQueueUsage(t *testing.T) {
 	cq := kueue.ClusterQueue{
 		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
 		Spec: kueue.ClusterQueueSpec{
-			Resources: []kueue.Resource{
+			ResourceGroups: []kueue.ResourceGroup{
 				{
-					Name: corev1.ResourceCPU,
-					Flavors: []kueue.Flavor{
+					CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+					Flavors: []kueue.FlavorQuotas{
 						{
 							Name: "default",
-							Quota: kueue.Quota{
-								Min: resource.MustParse("10"),
-								Max: pointer.Quantity(resource.MustParse("20")),
+							Resources: []kueue.ResourceQuota{
+								{
+									Name: corev1.ResourceCPU,
+									Quota: kueue.Quota{
+										Min: resource.MustParse("10"),
+										Max: pointer.Quantity(resource.MustParse("20")),
+									},
+								},
 							},
 						},
 					},
 				},
 				{
-					Name: "example.com/gpu",
-					Flavors: []kueue.Flavor{
+					CoveredResources: []corev1.ResourceName{"example.com/gpu"},
+					Flavors: []kueue.FlavorQuotas{
 						{
 							Name: "model_a",
-							Quota: kueue.Quota{
-								Min: resource.MustParse("5"),
-								Max: pointer.Quantity(resource.MustParse("10")),
+							Resources: []kueue.ResourceQuota{
+								{
+									Name: "example.com/gpu",
+									Quota: kueue.Quota{
+										Min: resource.MustParse("5"),
+										Max: pointer.Quantity(resource.MustParse("10")),
+									},
+								},
 							},
 						},
 						{
 							Name: "model_b",
-							Quota: kueue.Quota{
-								Min: resource.MustParse("5"),
-								// No max.
+							Resources: []kueue.ResourceQuota{
+								{
+									Name: "example.com/gpu",
+									Quota: kueue.Quota{
+										Min: resource.MustParse("5"),
+									},
+								},
 							},
 						},
 					},
@@ -1333,6 +1467,48 @@ func TestClusterQueueUsage(t *testing.T) {
 	}
 }
 
+func TestClusterQueueUsageAnyFlavor(t *testing.T) {
+	cq := utiltesting.MakeClusterQueue("foo").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("default", "10").Obj()).Obj()).
+		Resource(utiltesting.MakeResource(corev1.ResourceEphemeralStorage).
+			Flavor(utiltesting.MakeFlavor("*", "10Gi").Obj()).Obj()).
+		Obj()
+	cache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+		t.Fatalf("Adding ClusterQueue: %v", err)
+	}
+	if !cache.ClusterQueueActive("foo") {
+		t.Errorf("ClusterQueue with the wildcard flavor is not active")
+	}
+	wl := utiltesting.MakeWorkload("one", "").
+		Request(corev1.ResourceCPU, "2").
+		Request(corev1.ResourceEphemeralStorage, "3Gi").
+		Admit(utiltesting.MakeAdmission("foo").
+			Flavor(corev1.ResourceCPU, "default").
+			Flavor(corev1.ResourceEphemeralStorage, "default").Obj()).
+		Obj()
+	if added := cache.AddOrUpdateWorkload(wl); !added {
+		t.Fatalf("Workload %s was not added", workload.Key(wl))
+	}
+	resources, _, err := cache.Usage(cq)
+	if err != nil {
+		t.Fatalf("Couldn't get usage: %v", err)
+	}
+	wantResources := kueue.UsedResources{
+		corev1.ResourceCPU: {
+			"default": kueue.Usage{Total: pointer.Quantity(resource.MustParse("2"))},
+		},
+		corev1.ResourceEphemeralStorage: {
+			"*": kueue.Usage{Total: pointer.Quantity(resource.MustParse("3Gi"))},
+		},
+	}
+	if diff := cmp.Diff(wantResources, resources); diff != "" {
+		t.Errorf("Unexpected used resources (-want,+got):\n%s", diff)
+	}
+}
+
 func TestCacheQueueOperations(t *testing.T) {
 	cqs := []*kueue.ClusterQueue{
 		utiltesting.MakeClusterQueue("foo").Obj(),
@@ -1617,6 +1793,231 @@ func TestClusterQueuesUsingFlavor(t *testing.T) {
 	}
 }
 
+func TestWorkloadInDeletedFlavor(t *testing.T) {
+	ctx := context.Background()
+	cache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+	x86Rf := utiltesting.MakeResourceFlavor("x86").Obj()
+	cache.AddOrUpdateResourceFlavor(x86Rf)
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("aarch64").Obj())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("x86", "5").Obj()).
+			Flavor(utiltesting.MakeFlavor("aarch64", "5").Obj()).
+			Obj()).
+		Obj()
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "1").
+		Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "x86").Obj()).
+		Obj()
+	cache.AddOrUpdateWorkload(wl)
+	if diff := cmp.Diff([]*kueue.Workload{wl}, cache.WorkloadsUsingFlavor("x86")); diff != "" {
+		t.Errorf("Unexpected workloads using the flavor (-want,+got):\n%s", diff)
+	}
+
+	deletingRf := x86Rf.DeepCopy()
+	deletingRf.DeletionTimestamp = &metav1.Time{}
+	cache.AddOrUpdateResourceFlavor(deletingRf)
+	if !cache.ResourceFlavorTerminating("x86") {
+		t.Errorf("ResourceFlavor isn't terminating after being deleted")
+	}
+	if cache.WorkloadInDeletedFlavor(wl) {
+		t.Errorf("Workload is in a deleted flavor while the clusterQueue still uses it")
+	}
+
+	cq = utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("aarch64", "5").Obj()).
+			Obj()).
+		Obj()
+	if err := cache.UpdateClusterQueue(cq); err != nil {
+		t.Fatalf("Failed updating clusterQueue: %v", err)
+	}
+	if !cache.WorkloadInDeletedFlavor(wl) {
+		t.Errorf("Workload isn't in a deleted flavor after the clusterQueue stopped using it")
+	}
+}
+
+func TestClusterQueueAdmissionChecks(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	cache := New(fake.NewClientBuilder().WithScheme(scheme).Build())
+	provisioning := utiltesting.MakeAdmissionCheck("provisioning", "kueue.x-k8s.io/provisioning-request").Obj()
+	cq := utiltesting.MakeClusterQueue("cq").AdmissionChecks("provisioning").Obj()
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	if cache.ClusterQueueActive("cq") || !cache.ClusterQueueInactiveAdmissionChecks("cq") {
+		t.Errorf("ClusterQueue is active while its admission check doesn't exist")
+	}
+
+	cache.AddOrUpdateAdmissionCheck(provisioning)
+	if cache.ClusterQueueActive("cq") || !cache.ClusterQueueInactiveAdmissionChecks("cq") {
+		t.Errorf("ClusterQueue is active while its admission check isn't active")
+	}
+
+	cache.AddOrUpdateAdmissionCheck(utiltesting.MakeAdmissionCheck("provisioning", "kueue.x-k8s.io/provisioning-request").Active(metav1.ConditionTrue).Obj())
+	if !cache.ClusterQueueActive("cq") || cache.ClusterQueueInactiveAdmissionChecks("cq") {
+		t.Errorf("ClusterQueue is inactive after its admission check became active")
+	}
+	if diff := cmp.Diff([]string{"cq"}, cache.ClusterQueuesUsingAdmissionCheck("provisioning")); diff != "" {
+		t.Errorf("Unexpected clusterQueues using the admission check (-want,+got):\n%s", diff)
+	}
+
+	cache.DeleteAdmissionCheck(provisioning)
+	if cache.ClusterQueueActive("cq") || !cache.ClusterQueueInactiveAdmissionChecks("cq") {
+		t.Errorf("ClusterQueue is active after deleting its admission check")
+	}
+}
+
+func TestClusterQueueInactiveReason(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	cache := New(fake.NewClientBuilder().WithScheme(scheme).Build())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Cohort("child").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("spot", "5").Obj()).
+			Flavor(utiltesting.MakeFlavor("on-demand", "5").Obj()).Obj()).
+		AdmissionChecks("provisioning").
+		Obj()
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	checkReason := func(wantReason, wantMessage string) {
+		t.Helper()
+		reason, msg := cache.ClusterQueueInactiveReason("cq")
+		if reason != wantReason || msg != wantMessage {
+			t.Errorf("Got inactive reason %q with message %q, want %q with message %q", reason, msg, wantReason, wantMessage)
+		}
+	}
+
+	cache.AddOrUpdateCohort(&kueue.Cohort{
+		ObjectMeta: metav1.ObjectMeta{Name: "child"},
+		Spec:       kueue.CohortSpec{Parent: "root"},
+	})
+	cache.AddOrUpdateCohort(&kueue.Cohort{
+		ObjectMeta: metav1.ObjectMeta{Name: "root"},
+		Spec:       kueue.CohortSpec{Parent: "child"},
+	})
+	checkReason("InvalidCohort", "Can't admit new workloads; the parents of cohort child form a cycle")
+	if diff := cmp.Diff([]string{"cq"}, cache.ClusterQueuesUnderCohort("root")); diff != "" {
+		t.Errorf("Unexpected clusterQueues under the cohort (-want,+got):\n%s", diff)
+	}
+
+	cache.DeleteCohort(&kueue.Cohort{ObjectMeta: metav1.ObjectMeta{Name: "root"}})
+	checkReason("AdmissionCheckInactive", "Can't admit new workloads; the admission checks provisioning are not found or inactive")
+
+	cache.AddOrUpdateAdmissionCheck(utiltesting.MakeAdmissionCheck("provisioning", "kueue.x-k8s.io/provisioning-request").Active(metav1.ConditionTrue).Obj())
+	checkReason("FlavorNotFound", "Can't admit new workloads; the flavors on-demand, spot are not found")
+
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("spot").Obj())
+	cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("on-demand").Obj())
+	checkReason("", "")
+
+	cache.TerminateClusterQueue("cq")
+	checkReason("Terminating", "Can't admit new workloads; clusterQueue is terminating")
+}
+
+func TestClusterQueueStopPolicy(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	cache := New(fake.NewClientBuilder().WithScheme(scheme).Build())
+	cq := utiltesting.MakeClusterQueue("cq").StopPolicy(kueue.Hold).Obj()
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	if cache.ClusterQueueActive("cq") || cache.ClusterQueueStopPolicy("cq") != kueue.Hold {
+		t.Errorf("ClusterQueue is active while it's held")
+	}
+
+	if err := cache.UpdateClusterQueue(utiltesting.MakeClusterQueue("cq").StopPolicy(kueue.None).Obj()); err != nil {
+		t.Fatalf("Failed updating clusterQueue: %v", err)
+	}
+	if !cache.ClusterQueueActive("cq") || cache.ClusterQueueStopPolicy("cq") != kueue.None {
+		t.Errorf("ClusterQueue is inactive after being resumed")
+	}
+
+	if err := cache.UpdateClusterQueue(utiltesting.MakeClusterQueue("cq").StopPolicy(kueue.HoldAndDrain).Obj()); err != nil {
+		t.Fatalf("Failed updating clusterQueue: %v", err)
+	}
+	if cache.ClusterQueueActive("cq") || cache.ClusterQueueStopPolicy("cq") != kueue.HoldAndDrain {
+		t.Errorf("ClusterQueue is active while it's drained")
+	}
+}
+
+func TestClusterQueueResourceMetrics(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	cache := New(fake.NewClientBuilder().WithScheme(scheme).Build())
+	cq := utiltesting.MakeClusterQueue("metrics").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("on-demand", "4").Max("10").Obj()).
+			Flavor(utiltesting.MakeFlavor("spot", "2").Obj()).Obj()).
+		Obj()
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "5500m").
+		Admit(utiltesting.MakeAdmission("metrics").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+		Obj()
+	if !cache.AddOrUpdateWorkload(wl) {
+		t.Fatalf("Failed adding workload")
+	}
+
+	gauges := map[string]*prometheus.GaugeVec{
+		"usage":           metrics.ClusterQueueResourceUsage,
+		"borrowing":       metrics.ClusterQueueResourceBorrowing,
+		"nominal quota":   metrics.ClusterQueueNominalQuota,
+		"borrowing limit": metrics.ClusterQueueBorrowingLimit,
+	}
+	wantValues := map[string]map[string]float64{
+		"usage":           {"on-demand": 5.5, "spot": 0},
+		"borrowing":       {"on-demand": 1.5, "spot": 0},
+		"nominal quota":   {"on-demand": 4, "spot": 2},
+		"borrowing limit": {"on-demand": 6},
+	}
+	for name, gauge := range gauges {
+		gotValues := make(map[string]float64)
+		for _, f := range []string{"on-demand", "spot"} {
+			v := testutil.ToFloat64(gauge.WithLabelValues("metrics", f, "cpu"))
+			if _, reported := wantValues[name][f]; reported || v != 0 {
+				gotValues[f] = v
+			}
+		}
+		if diff := cmp.Diff(wantValues[name], gotValues); diff != "" {
+			t.Errorf("Unexpected %s (-want,+got):\n%s", name, diff)
+		}
+	}
+
+	if err := cache.DeleteWorkload(wl); err != nil {
+		t.Fatalf("Failed deleting workload: %v", err)
+	}
+	if v := testutil.ToFloat64(metrics.ClusterQueueResourceUsage.WithLabelValues("metrics", "on-demand", "cpu")); v != 0 {
+		t.Errorf("Got usage %v after deleting the workload, want 0", v)
+	}
+
+	cache.DeleteClusterQueue(cq)
+	if metrics.ClusterQueueNominalQuota.DeleteLabelValues("metrics", "spot", "cpu") {
+		t.Errorf("The nominal quota is still reported after deleting the ClusterQueue")
+	}
+}
+
 func TestClusterQueueUpdateWithFlavors(t *testing.T) {
 	rf := utiltesting.MakeResourceFlavor("x86").Obj()
 	flavor := utiltesting.MakeFlavor(rf.Name, "5").Obj()
@@ -1963,6 +2364,17 @@ func TestCachePodsReadyForAllAdmittedWorkloads(t *testing.T) {
 			},
 			wantReady: true,
 		},
+		{
+			name: "add reservation Workload without PodsReady condition",
+			operation: func(cache *Cache) error {
+				wl := utiltesting.MakeWorkload("a", "").Reservation(nil).Admit(&kueue.Admission{
+					ClusterQueue: "one",
+				}).Obj()
+				cache.AddOrUpdateWorkload(wl)
+				return nil
+			},
+			wantReady: true,
+		},
 		{
 			name: "assume Workload without PodsReady condition",
 			operation: func(cache *Cache) error {
@@ -2161,3 +2573,288 @@ func messageOrEmpty(err error) string {
 	}
 	return err.Error()
 }
+
+func TestResourcesByNameBorrowingLimit(t *testing.T) {
+	cases := map[string]struct {
+		flavor *utiltesting.FlavorQuota
+		want   FlavorLimits
+	}{
+		"no limits": {
+			flavor: utiltesting.MakeFlavor("default", "5").Obj(),
+			want:   FlavorLimits{Name: "default", Min: 5_000},
+		},
+		"only borrowingLimit": {
+			flavor: utiltesting.MakeFlavor("default", "5").BorrowingLimit("2").Obj(),
+			want:   FlavorLimits{Name: "default", Min: 5_000, Max: pointer.Int64(7_000)},
+		},
+		"borrowingLimit lower than max": {
+			flavor: utiltesting.MakeFlavor("default", "5").Max("10").BorrowingLimit("2").Obj(),
+			want:   FlavorLimits{Name: "default", Min: 5_000, Max: pointer.Int64(7_000)},
+		},
+		"max lower than borrowingLimit": {
+			flavor: utiltesting.MakeFlavor("default", "5").Max("6").BorrowingLimit("2").Obj(),
+			want:   FlavorLimits{Name: "default", Min: 5_000, Max: pointer.Int64(6_000)},
+		},
+		"zero borrowingLimit": {
+			flavor: utiltesting.MakeFlavor("default", "5").BorrowingLimit("0").Obj(),
+			want:   FlavorLimits{Name: "default", Min: 5_000, Max: pointer.Int64(5_000)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res := utiltesting.MakeResource(corev1.ResourceCPU).Flavor(tc.flavor).Obj()
+			got := resourcesByName(utiltesting.ResourceGroups(res), nil)
+			if diff := cmp.Diff([]FlavorLimits{tc.want}, got[corev1.ResourceCPU].Flavors); diff != "" {
+				t.Errorf("Unexpected flavor limits (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeprecatedResources(t *testing.T) {
+	resources := []kueue.Resource{
+		{
+			Name: corev1.ResourceCPU,
+			Flavors: []kueue.Flavor{
+				{Name: "on-demand", Quota: kueue.Quota{Min: resource.MustParse("10")}},
+				{Name: "spot", Quota: kueue.Quota{Min: resource.MustParse("5")}},
+			},
+		},
+		{
+			Name: corev1.ResourceMemory,
+			Flavors: []kueue.Flavor{
+				{Name: "on-demand", Quota: kueue.Quota{Min: resource.MustParse("10Gi")}},
+				{Name: "spot", Quota: kueue.Quota{Min: resource.MustParse("5Gi")}},
+			},
+		},
+		{
+			Name: "example.com/gpu",
+			Flavors: []kueue.Flavor{
+				{Name: "a100", Quota: kueue.Quota{Min: resource.MustParse("4")}},
+			},
+		},
+	}
+	codep := sets.New[corev1.ResourceName](corev1.ResourceCPU, corev1.ResourceMemory)
+	want := map[corev1.ResourceName]*Resource{
+		corev1.ResourceCPU: {
+			CodependentResources: codep,
+			Flavors: []FlavorLimits{
+				{Name: "on-demand", Min: 10_000},
+				{Name: "spot", Min: 5_000},
+			},
+		},
+		corev1.ResourceMemory: {
+			CodependentResources: codep,
+			Flavors: []FlavorLimits{
+				{Name: "on-demand", Min: 10 * utiltesting.Gi},
+				{Name: "spot", Min: 5 * utiltesting.Gi},
+			},
+		},
+		"example.com/gpu": {
+			Flavors: []FlavorLimits{
+				{Name: "a100", Min: 4},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	cache := New(fake.NewClientBuilder().WithScheme(scheme).Build())
+
+	cq := utiltesting.MakeClusterQueue("cq").Obj()
+	cq.Spec.Resources = resources
+	gotCQ, err := cache.newClusterQueue(cq)
+	if err != nil {
+		t.Fatalf("Failed to create the ClusterQueue: %v", err)
+	}
+	if diff := cmp.Diff(want, gotCQ.RequestableResources); diff != "" {
+		t.Errorf("Unexpected ClusterQueue resources (-want,+got):\n%s", diff)
+	}
+
+	cache.AddOrUpdateCohort(&kueue.Cohort{
+		ObjectMeta: metav1.ObjectMeta{Name: "cohort"},
+		Spec:       kueue.CohortSpec{Resources: resources},
+	})
+	if diff := cmp.Diff(want, cache.cohortConfigs["cohort"].resources); diff != "" {
+		t.Errorf("Unexpected Cohort resources (-want,+got):\n%s", diff)
+	}
+}
+
+func TestCacheNodeTracking(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl, WithNodeTracking(true))
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("a100").Label("gpu", "a100").Obj())
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("t4").Label("gpu", "t4").Obj())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource("nvidia.com/gpu").
+			Flavor(utiltesting.MakeFlavor("a100", "8").Obj()).
+			Flavor(utiltesting.MakeFlavor("t4", "8").Obj()).
+			Obj()).
+		Obj()
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node",
+			Labels: map[string]string{"gpu": "a100"},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("8"),
+				"nvidia.com/gpu":   resource.MustParse("4"),
+			},
+		},
+	}
+
+	if diff := cmp.Diff(sets.New("cq"), cqCache.AddOrUpdateNode(node)); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after adding the node (-want,+got):\n%s", diff)
+	}
+	if cqs := cqCache.AddOrUpdateNode(node.DeepCopy()); len(cqs) != 0 {
+		t.Errorf("Unexpected ClusterQueues after an update without changes: %v", sets.List(cqs))
+	}
+	snap := cqCache.Snapshot()
+	wantResources := map[string]sets.Set[corev1.ResourceName]{
+		"a100": sets.New[corev1.ResourceName]("nvidia.com/gpu"),
+		"t4":   sets.New[corev1.ResourceName](),
+	}
+	if diff := cmp.Diff(wantResources, snap.ClusterQueues["cq"].FlavorNodeResources); diff != "" {
+		t.Errorf("Unexpected node resources in snapshot (-want,+got):\n%s", diff)
+	}
+
+	node.Labels["gpu"] = "t4"
+	if diff := cmp.Diff(sets.New("cq"), cqCache.AddOrUpdateNode(node)); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after updating the node (-want,+got):\n%s", diff)
+	}
+	snap = cqCache.Snapshot()
+	wantResources = map[string]sets.Set[corev1.ResourceName]{
+		"a100": sets.New[corev1.ResourceName](),
+		"t4":   sets.New[corev1.ResourceName]("nvidia.com/gpu"),
+	}
+	if diff := cmp.Diff(wantResources, snap.ClusterQueues["cq"].FlavorNodeResources); diff != "" {
+		t.Errorf("Unexpected node resources in snapshot after updating the node (-want,+got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(sets.New("cq"), cqCache.DeleteNode(node)); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after deleting the node (-want,+got):\n%s", diff)
+	}
+	snap = cqCache.Snapshot()
+	wantResources = map[string]sets.Set[corev1.ResourceName]{
+		"a100": sets.New[corev1.ResourceName](),
+		"t4":   sets.New[corev1.ResourceName](),
+	}
+	if diff := cmp.Diff(wantResources, snap.ClusterQueues["cq"].FlavorNodeResources); diff != "" {
+		t.Errorf("Unexpected node resources in snapshot after deleting the node (-want,+got):\n%s", diff)
+	}
+}
+
+func TestCacheQuotaAutoSizing(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl, WithQuotaAutoSizing(true))
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("spot").Label("pool", "spot").Obj())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("spot", "0").MinPercentage(50).Max("10").LendingLimit("3").Obj()).
+			Obj()).
+		Obj()
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+	}
+	node := func(name, pool, cpu string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"pool": pool},
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			},
+		}
+	}
+	checkLimits := func(step string, want FlavorLimits) {
+		t.Helper()
+		snap := cqCache.Snapshot()
+		if diff := cmp.Diff([]FlavorLimits{want}, snap.ClusterQueues["cq"].RequestableResources[corev1.ResourceCPU].Flavors); diff != "" {
+			t.Errorf("Unexpected flavor limits after %s (-want,+got):\n%s", step, diff)
+		}
+	}
+	checkLimits("adding the ClusterQueue", FlavorLimits{Name: "spot", Min: 0, Max: pointer.Int64(10_000), LendingLimit: pointer.Int64(0)})
+
+	if diff := cmp.Diff(sets.New("cq"), cqCache.AddOrUpdateNode(node("n1", "spot", "4"))); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after adding a node (-want,+got):\n%s", diff)
+	}
+	checkLimits("adding a node", FlavorLimits{Name: "spot", Min: 2_000, Max: pointer.Int64(10_000), LendingLimit: pointer.Int64(2_000)})
+
+	if cqs := cqCache.AddOrUpdateNode(node("n2", "on-demand", "6")); len(cqs) != 0 {
+		t.Errorf("Unexpected ClusterQueues after adding a node of another flavor: %v", sets.List(cqs))
+	}
+	cqCache.AddOrUpdateNode(node("n3", "spot", "5"))
+	checkLimits("adding another node", FlavorLimits{Name: "spot", Min: 4_500, Max: pointer.Int64(10_000), LendingLimit: pointer.Int64(3_000)})
+
+	cqCache.AddOrUpdateNode(node("n4", "spot", "32"))
+	checkLimits("adding a large node", FlavorLimits{Name: "spot", Min: 10_000, Max: pointer.Int64(10_000), LendingLimit: pointer.Int64(3_000)})
+
+	cqCache.DeleteNode(node("n4", "spot", "32"))
+	if diff := cmp.Diff(sets.New("cq"), cqCache.DeleteNode(node("n3", "spot", "5"))); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after deleting a node (-want,+got):\n%s", diff)
+	}
+	checkLimits("deleting nodes", FlavorLimits{Name: "spot", Min: 2_000, Max: pointer.Int64(10_000), LendingLimit: pointer.Int64(2_000)})
+
+	if diff := cmp.Diff(sets.New("cq"), cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("spot").Label("pool", "on-demand").Obj())); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after updating the flavor (-want,+got):\n%s", diff)
+	}
+	checkLimits("updating the flavor", FlavorLimits{Name: "spot", Min: 3_000, Max: pointer.Int64(10_000), LendingLimit: pointer.Int64(3_000)})
+}
+
+func TestClusterQueueActiveWorkloadsMetrics(t *testing.T) {
+	ctx := context.Background()
+	cache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+	cq := utiltesting.MakeClusterQueue("active-metrics").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("default", "10").Obj()).Obj()).
+		Obj()
+	if err := cache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	admission := utiltesting.MakeAdmission("active-metrics").Flavor(corev1.ResourceCPU, "default").Obj()
+	admittedCond := metav1.Condition{Type: kueue.WorkloadAdmitted, Status: metav1.ConditionTrue}
+	reserving := utiltesting.MakeWorkload("reserving", "ns").Request(corev1.ResourceCPU, "1").Admit(admission).Obj()
+	admitted := utiltesting.MakeWorkload("admitted", "ns").Request(corev1.ResourceCPU, "1").Admit(admission).Condition(admittedCond).Obj()
+	for _, wl := range []*kueue.Workload{reserving, admitted} {
+		if !cache.AddOrUpdateWorkload(wl) {
+			t.Fatalf("Failed adding workload %s", wl.Name)
+		}
+	}
+	wantActive := func(wantReserving, wantAdmitted float64) {
+		t.Helper()
+		if v := testutil.ToFloat64(metrics.ReservingActiveWorkloads.WithLabelValues("active-metrics")); v != wantReserving {
+			t.Errorf("Got %v reserving active workloads, want %v", v, wantReserving)
+		}
+		if v := testutil.ToFloat64(metrics.AdmittedActiveWorkloads.WithLabelValues("active-metrics")); v != wantAdmitted {
+			t.Errorf("Got %v admitted active workloads, want %v", v, wantAdmitted)
+		}
+	}
+	wantActive(2, 1)
+
+	// The admission checks of the reserving workload passed.
+	newReserving := reserving.DeepCopy()
+	apimeta.SetStatusCondition(&newReserving.Status.Conditions, admittedCond)
+	if err := cache.UpdateWorkload(reserving, newReserving); err != nil {
+		t.Fatalf("Failed updating workload: %v", err)
+	}
+	wantActive(2, 2)
+
+	if err := cache.DeleteWorkload(admitted); err != nil {
+		t.Fatalf("Failed deleting workload: %v", err)
+	}
+	wantActive(1, 1)
+
+	cache.DeleteClusterQueue(cq)
+	if metrics.ReservingActiveWorkloads.DeleteLabelValues("active-metrics") {
+		t.Errorf("The reserving active workloads are still reported after deleting the ClusterQueue")
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// InconsistencyKind is the kind of a difference between the cache and the
+// workloads in the cluster.
+type InconsistencyKind string
+
+const (
+	// InconsistencyMissing means that an admitted workload is not held by
+	// its ClusterQueue.
+	InconsistencyMissing InconsistencyKind = "missing"
+	// InconsistencyUnexpected means that a ClusterQueue holds a workload that
+	// is not admitted by it.
+	InconsistencyUnexpected InconsistencyKind = "unexpected"
+	// InconsistencyStale means that a ClusterQueue holds an admitted
+	// workload with outdated requests.
+	InconsistencyStale InconsistencyKind = "stale"
+	// InconsistencyUsage means that the usage of a ClusterQueue doesn't add
+	// up to the requests of the workloads that it holds.
+	InconsistencyUsage InconsistencyKind = "usage"
+)
+
+// Inconsistency is a difference between the cache and the workloads in the
+// cluster.
+type Inconsistency struct {
+	Kind         InconsistencyKind
+	ClusterQueue string
+	// Workload is the key of the workload. It's empty for the inconsistencies
+	// of the usage.
+	Workload string
+	// ResourceVersion is the version of the workload in the cluster or, for
+	// unexpected workloads, in the cache.
+	ResourceVersion string
+}
+
+// Verify compares the workloads held by the ClusterQueues with the given
+// workloads, which are all the admitted workloads that are not finished, and
+// returns the differences. The differences for which repair returns true are
+// fixed by replacing the state of the cache with the given workloads.
+// The workloads assumed by the scheduler are not verified, as the given
+// workloads might not reflect their admission yet.
+func (c *Cache) Verify(workloads []*kueue.Workload, repair func(Inconsistency) bool) []Inconsistency {
+	c.Lock()
+	defer c.Unlock()
+
+	admitted := make(map[string]*kueue.Workload, len(workloads))
+	for _, w := range workloads {
+		if w.Spec.Admission == nil {
+			continue
+		}
+		if _, ok := c.clusterQueues[string(w.Spec.Admission.ClusterQueue)]; ok {
+			admitted[workload.Key(w)] = w
+		}
+	}
+	cqNames := make([]string, 0, len(c.clusterQueues))
+	for name := range c.clusterQueues {
+		cqNames = append(cqNames, name)
+	}
+	sort.Strings(cqNames)
+
+	var found []Inconsistency
+	repaired := false
+	for _, name := range cqNames {
+		cq := c.clusterQueues[name]
+		for _, k := range sortedKeys(cq.Workloads) {
+			if _, assumed := c.assumedWorkloads[k]; assumed {
+				continue
+			}
+			if w, ok := admitted[k]; ok && string(w.Spec.Admission.ClusterQueue) == name {
+				continue
+			}
+			wi := cq.Workloads[k]
+			inconsistency := Inconsistency{Kind: InconsistencyUnexpected, ClusterQueue: name, Workload: k, ResourceVersion: wi.Obj.ResourceVersion}
+			found = append(found, inconsistency)
+			if repair(inconsistency) {
+				cq.deleteWorkload(wi.Obj)
+				repaired = true
+			}
+		}
+	}
+	for _, k := range sortedKeys(admitted) {
+		if _, assumed := c.assumedWorkloads[k]; assumed {
+			continue
+		}
+		w := admitted[k]
+		cq := c.clusterQueues[string(w.Spec.Admission.ClusterQueue)]
+		inconsistency := Inconsistency{ClusterQueue: cq.Name, Workload: k, ResourceVersion: w.ResourceVersion}
+		wi, ok := cq.Workloads[k]
+		switch {
+		case !ok:
+			inconsistency.Kind = InconsistencyMissing
+		case !equality.Semantic.DeepEqual(wi.TotalRequests, workload.NewInfo(w, c.workloadInfoOpts...).TotalRequests):
+			inconsistency.Kind = InconsistencyStale
+		default:
+			continue
+		}
+		found = append(found, inconsistency)
+		if repair(inconsistency) {
+			if ok {
+				cq.deleteWorkload(wi.Obj)
+			}
+			if err := cq.addWorkload(w); err == nil {
+				repaired = true
+			}
+		}
+	}
+	for _, name := range cqNames {
+		cq := c.clusterQueues[name]
+		usage := cq.workloadsUsage()
+		if equality.Semantic.DeepEqual(usage, cq.UsedResources) {
+			continue
+		}
+		inconsistency := Inconsistency{Kind: InconsistencyUsage, ClusterQueue: name}
+		found = append(found, inconsistency)
+		if repair(inconsistency) {
+			cq.UsedResources = usage
+			cq.reportResourceMetrics(false)
+		}
+	}
+	if repaired && c.podsReadyTracking {
+		c.podsReadyCond.Broadcast()
+	}
+	return found
+}
+
+// workloadsUsage returns the usage of the resource flavors of the
+// ClusterQueue computed from the workloads that it holds.
+func (c *ClusterQueue) workloadsUsage() ResourceQuantities {
+	usage := make(ResourceQuantities, len(c.UsedResources))
+	for rName, flavors := range c.UsedResources {
+		usage[rName] = make(map[string]int64, len(flavors))
+		for flavor := range flavors {
+			usage[rName][flavor] = 0
+		}
+	}
+	for _, wi := range c.Workloads {
+		updateUsage(wi, usage, 1)
+	}
+	return usage
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestVerify(t *testing.T) {
+	admitted := func(name, cq, cpu, resourceVersion string) *kueue.Workload {
+		w := utiltesting.MakeWorkload(name, "ns").
+			Request(corev1.ResourceCPU, cpu).
+			Admit(utiltesting.MakeAdmission(cq).Flavor(corev1.ResourceCPU, "default").Obj()).
+			Obj()
+		w.ResourceVersion = resourceVersion
+		return w
+	}
+	testCases := map[string]struct {
+		cached       []*kueue.Workload
+		assumed      []*kueue.Workload
+		workloads    []*kueue.Workload
+		corruptUsage bool
+		repair       bool
+		want         []Inconsistency
+	}{
+		"consistent": {
+			cached:    []*kueue.Workload{admitted("a", "cq-a", "1", "1")},
+			assumed:   []*kueue.Workload{admitted("b", "cq-a", "1", "1")},
+			workloads: []*kueue.Workload{admitted("a", "cq-a", "1", "1")},
+			repair:    true,
+		},
+		"missed events": {
+			cached: []*kueue.Workload{
+				admitted("a", "cq-a", "1", "1"),
+				admitted("b", "cq-a", "1", "1"),
+				admitted("c", "cq-a", "1", "1"),
+			},
+			workloads: []*kueue.Workload{
+				admitted("a", "cq-a", "2", "2"),
+				admitted("b", "cq-b", "1", "2"),
+				admitted("d", "cq-b", "1", "1"),
+				admitted("e", "unknown", "1", "1"),
+			},
+			repair: true,
+			want: []Inconsistency{
+				{Kind: InconsistencyUnexpected, ClusterQueue: "cq-a", Workload: "ns/b", ResourceVersion: "1"},
+				{Kind: InconsistencyUnexpected, ClusterQueue: "cq-a", Workload: "ns/c", ResourceVersion: "1"},
+				{Kind: InconsistencyStale, ClusterQueue: "cq-a", Workload: "ns/a", ResourceVersion: "2"},
+				{Kind: InconsistencyMissing, ClusterQueue: "cq-b", Workload: "ns/b", ResourceVersion: "2"},
+				{Kind: InconsistencyMissing, ClusterQueue: "cq-b", Workload: "ns/d", ResourceVersion: "1"},
+			},
+		},
+		"not repaired": {
+			cached: []*kueue.Workload{admitted("a", "cq-a", "1", "1")},
+			want: []Inconsistency{
+				{Kind: InconsistencyUnexpected, ClusterQueue: "cq-a", Workload: "ns/a", ResourceVersion: "1"},
+			},
+		},
+		"usage drift": {
+			cached:       []*kueue.Workload{admitted("a", "cq-a", "1", "1")},
+			workloads:    []*kueue.Workload{admitted("a", "cq-a", "1", "1")},
+			corruptUsage: true,
+			repair:       true,
+			want: []Inconsistency{
+				{Kind: InconsistencyUsage, ClusterQueue: "cq-a"},
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+			for _, cqName := range []string{"cq-a", "cq-b"} {
+				cq := utiltesting.MakeClusterQueue(cqName).
+					Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+						Flavor(utiltesting.MakeFlavor("default", "10").Obj()).
+						Obj()).
+					Obj()
+				if err := cache.AddClusterQueue(context.Background(), cq); err != nil {
+					t.Fatalf("Failed adding clusterQueue: %v", err)
+				}
+			}
+			for _, w := range tc.cached {
+				cache.AddOrUpdateWorkload(w)
+			}
+			for _, w := range tc.assumed {
+				if err := cache.AssumeWorkload(w); err != nil {
+					t.Fatalf("Failed assuming workload: %v", err)
+				}
+			}
+			if tc.corruptUsage {
+				cache.clusterQueues["cq-a"].UsedResources[corev1.ResourceCPU]["default"] = 5000
+			}
+
+			got := cache.Verify(tc.workloads, func(Inconsistency) bool { return tc.repair })
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected inconsistencies (-want,+got):\n%s", diff)
+			}
+
+			wantAfter := tc.want
+			if tc.repair {
+				wantAfter = nil
+			}
+			got = cache.Verify(tc.workloads, func(Inconsistency) bool { return false })
+			if diff := cmp.Diff(wantAfter, got); diff != "" {
+				t.Errorf("Unexpected inconsistencies after the verification (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// WorkloadOverQuota returns whether the workload has to be evicted because
+// the usage of the ClusterQueue in which it reserved quota exceeds the quota
+// and the ClusterQueue evicts workloads when that happens.
+func (c *Cache) WorkloadOverQuota(w *kueue.Workload) bool {
+	c.RLock()
+	defer c.RUnlock()
+	cq := c.clusterQueueForWorkload(w)
+	if cq == nil || cq.overQuotaPolicy != kueue.OverQuotaPolicyEvict {
+		return false
+	}
+	key := workload.Key(w)
+	for _, wi := range cq.overQuotaWorkloads() {
+		if workload.Key(wi.Obj) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// overQuotaWorkloads returns the workloads to evict for the usage of the
+// ClusterQueue to fit in its quota. Similarly to preemption, the workloads are
+// removed, lowest priority first and, among them, most recently admitted
+// first, until the usage fits. Then, they are added back, in reverse order,
+// while the usage still fits.
+// The ordering doesn't depend on the time at which the function is called, so
+// the same workloads are selected while evictions are in progress.
+func (c *ClusterQueue) overQuotaWorkloads() []*workload.Info {
+	usage := make(ResourceQuantities, len(c.UsedResources))
+	for r, flavors := range c.UsedResources {
+		usage[r] = make(map[string]int64, len(flavors))
+		for f, v := range flavors {
+			usage[r][f] = v
+		}
+	}
+	over := c.resourcesOverQuota(usage)
+	if len(over) == 0 {
+		return nil
+	}
+	var candidates []*workload.Info
+	for _, wi := range c.Workloads {
+		if usesResources(wi, over) {
+			candidates = append(candidates, wi)
+		}
+	}
+	sortByEvictionOrder(candidates)
+
+	var targets []*workload.Info
+	for _, wi := range candidates {
+		if len(c.resourcesOverQuota(usage)) == 0 {
+			break
+		}
+		updateUsage(wi, usage, -1)
+		targets = append(targets, wi)
+	}
+	for i := len(targets) - 1; i >= 0; i-- {
+		updateUsage(targets[i], usage, 1)
+		if len(c.resourcesOverQuota(usage)) == 0 {
+			targets = append(targets[:i], targets[i+1:]...)
+		} else {
+			updateUsage(targets[i], usage, -1)
+		}
+	}
+	return targets
+}
+
+// sortByEvictionOrder sorts the workloads lowest priority first and, among
+// them, most recently admitted first.
+func sortByEvictionOrder(workloads []*workload.Info) {
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i], workloads[j]
+		if pa, pb := priority.Priority(a.Obj), priority.Priority(b.Obj); pa != pb {
+			return pa < pb
+		}
+		// Workloads without an admission time were just admitted.
+		if ta, tb := admissionTime(a.Obj), admissionTime(b.Obj); !ta.Equal(tb) {
+			return ta.IsZero() || (!tb.IsZero() && ta.After(tb))
+		}
+		return workload.Key(a.Obj) < workload.Key(b.Obj)
+	})
+}
+
+// WorkloadsOverQuotaAfterUpdate simulates, in a snapshot, the update of the
+// quotas of the ClusterQueue to the ones of the given ClusterQueue, and
+// returns the keys of the workloads admitted by the ClusterQueue that wouldn't
+// fit in the new quotas. Like for overQuotaWorkloads, the workloads that
+// don't fit are the lowest priority and most recently admitted ones. In a
+// cohort, the usage above the nominal quotas also has to fit in the quotas of
+// the cohort.
+// It returns no workloads if the ClusterQueue doesn't exist or is inactive.
+// The simulation doesn't report metrics, nor changes the cache.
+func (c *Cache) WorkloadsOverQuotaAfterUpdate(cq *kueue.ClusterQueue) ([]string, error) {
+	c.Lock()
+	defer c.Unlock()
+	if _, found := c.clusterQueues[cq.Name]; !found {
+		return nil, nil
+	}
+	updated, err := c.newClusterQueue(cq)
+	if err != nil {
+		return nil, err
+	}
+	snap := c.snapshot(func(cqCopy *ClusterQueue) {
+		if cqCopy.Name != cq.Name {
+			return
+		}
+		usage := make(ResourceQuantities, len(updated.UsedResources))
+		for rName, flavors := range updated.UsedResources {
+			usage[rName] = make(map[string]int64, len(flavors))
+			for flavor := range flavors {
+				usage[rName][flavor] = cqCopy.UsedResources[rName][flavor]
+			}
+		}
+		cqCopy.RequestableResources = updated.RequestableResources
+		cqCopy.UsedResources = usage
+	})
+	snapCQ := snap.ClusterQueues[cq.Name]
+	if snapCQ == nil {
+		return nil, nil
+	}
+	// Only the admitted workloads are considered.
+	for k, r := range snap.Reservations {
+		if r.ClusterQueue == cq.Name {
+			snap.RemoveReservation(k)
+		}
+	}
+	var keys []string
+	for _, wi := range snap.overQuotaWorkloads(snapCQ) {
+		keys = append(keys, workload.Key(wi.Obj))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// overQuotaWorkloads returns the workloads to remove from the ClusterQueue of
+// the snapshot for its usage to fit in its quotas and in the quotas of its
+// cohorts, selected like for the overQuotaWorkloads of a ClusterQueue.
+func (s *Snapshot) overQuotaWorkloads(cq *ClusterQueue) []*workload.Info {
+	fits := func() bool {
+		return len(cq.resourcesOverQuota(cq.UsedResources)) == 0 && len(cq.resourcesOverCohortQuota()) == 0
+	}
+	if fits() {
+		return nil
+	}
+	over := cq.resourcesOverQuota(cq.UsedResources)
+	for rName, flavors := range cq.resourcesOverCohortQuota() {
+		if over[rName] == nil {
+			over[rName] = sets.New[string]()
+		}
+		over[rName].Insert(flavors.UnsortedList()...)
+	}
+	var candidates []*workload.Info
+	for _, wi := range cq.Workloads {
+		if usesResources(wi, over) {
+			candidates = append(candidates, wi)
+		}
+	}
+	sortByEvictionOrder(candidates)
+
+	var targets []*workload.Info
+	for _, wi := range candidates {
+		if fits() {
+			break
+		}
+		s.RemoveWorkload(wi)
+		targets = append(targets, wi)
+	}
+	for i := len(targets) - 1; i >= 0; i-- {
+		s.AddWorkload(targets[i])
+		if fits() {
+			targets = append(targets[:i], targets[i+1:]...)
+		} else {
+			s.RemoveWorkload(targets[i])
+		}
+	}
+	return targets
+}
+
+// resourcesOverCohortQuota returns the flavors, per resource, in which the
+// ClusterQueue uses quota above its nominal quota while the usage of any of
+// its cohorts exceeds the quota of the cohort.
+func (c *ClusterQueue) resourcesOverCohortQuota() map[corev1.ResourceName]sets.Set[string] {
+	over := make(map[corev1.ResourceName]sets.Set[string])
+	if c.Cohort == nil {
+		return over
+	}
+	for rName, res := range c.RequestableResources {
+		for _, fl := range res.Flavors {
+			if c.UsedResources[rName][fl.Name] <= fl.Min {
+				continue
+			}
+			for cohort := c.Cohort; cohort != nil; cohort = cohort.Parent {
+				if cohort.UsedResources[rName][fl.Name] > cohort.RequestableResources[rName][fl.Name] {
+					if over[rName] == nil {
+						over[rName] = sets.New[string]()
+					}
+					over[rName].Insert(fl.Name)
+					break
+				}
+			}
+		}
+	}
+	return over
+}
+
+// resourcesOverQuota returns the flavors, per resource, in which the usage
+// exceeds the quota. The quota is the min quota when the ClusterQueue doesn't
+// belong to a cohort, and the max quota, if any, otherwise.
+func (c *ClusterQueue) resourcesOverQuota(usage ResourceQuantities) map[corev1.ResourceName]sets.Set[string] {
+	over := make(map[corev1.ResourceName]sets.Set[string])
+	for rName, res := range c.RequestableResources {
+		for _, fl := range res.Flavors {
+			limit := fl.Max
+			if c.Cohort == nil {
+				limit = &fl.Min
+			}
+			if limit == nil || usage[rName][fl.Name] <= *limit {
+				continue
+			}
+			if over[rName] == nil {
+				over[rName] = sets.New[string]()
+			}
+			over[rName].Insert(fl.Name)
+		}
+	}
+	return over
+}
+
+func usesResources(wi *workload.Info, resources map[corev1.ResourceName]sets.Set[string]) bool {
+	for _, ps := range wi.TotalRequests {
+		for rName, flavor := range ps.Flavors {
+			if resources[rName].Has(flavor) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// admissionTime returns the time at which the workload was admitted or, if
+// the workload isn't admitted yet, the time at which it reserved quota.
+func admissionTime(w *kueue.Workload) time.Time {
+	cond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadAdmitted)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		cond = apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadQuotaReserved)
+	}
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return time.Time{}
+	}
+	return cond.LastTransitionTime.Time
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/metrics"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestWorkloadOverQuota(t *testing.T) {
+	now := time.Now()
+	admitted := func(name string, cpu string, prio int32, admittedAt time.Time) *kueue.Workload {
+		return utiltesting.MakeWorkload(name, "ns").
+			Request(corev1.ResourceCPU, cpu).
+			Priority(prio).
+			Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "default").Obj()).
+			Condition(metav1.Condition{
+				Type:               kueue.WorkloadAdmitted,
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(admittedAt),
+			}).
+			Obj()
+	}
+	workloads := []*kueue.Workload{
+		admitted("high", "2", 100, now.Add(-time.Hour)),
+		admitted("low-old", "2", 0, now.Add(-time.Hour)),
+		admitted("low-new", "1", 0, now),
+	}
+	testCases := map[string]struct {
+		clusterQueue *kueue.ClusterQueue
+		want         []string
+	}{
+		"usage fits": {
+			clusterQueue: utiltesting.MakeClusterQueue("cq").
+				OverQuotaPolicy(kueue.OverQuotaPolicyEvict).
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", "5").Obj()).
+					Obj()).
+				Obj(),
+		},
+		"keep policy": {
+			clusterQueue: utiltesting.MakeClusterQueue("cq").
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", "2").Obj()).
+					Obj()).
+				Obj(),
+		},
+		"evict the most recent of the lowest priority": {
+			clusterQueue: utiltesting.MakeClusterQueue("cq").
+				OverQuotaPolicy(kueue.OverQuotaPolicyEvict).
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", "4").Obj()).
+					Obj()).
+				Obj(),
+			want: []string{"low-new"},
+		},
+		"keep workloads that fit after the evictions": {
+			clusterQueue: utiltesting.MakeClusterQueue("cq").
+				OverQuotaPolicy(kueue.OverQuotaPolicyEvict).
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", "3").Obj()).
+					Obj()).
+				Obj(),
+			want: []string{"low-old"},
+		},
+		"evict higher priority workloads if needed": {
+			clusterQueue: utiltesting.MakeClusterQueue("cq").
+				OverQuotaPolicy(kueue.OverQuotaPolicyEvict).
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", "1").Obj()).
+					Obj()).
+				Obj(),
+			want: []string{"high", "low-old"},
+		},
+		"max quota in a cohort": {
+			clusterQueue: utiltesting.MakeClusterQueue("cq").
+				Cohort("cohort").
+				OverQuotaPolicy(kueue.OverQuotaPolicyEvict).
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", "1").Max("4").Obj()).
+					Obj()).
+				Obj(),
+			want: []string{"low-new"},
+		},
+		"no max quota in a cohort": {
+			clusterQueue: utiltesting.MakeClusterQueue("cq").
+				Cohort("cohort").
+				OverQuotaPolicy(kueue.OverQuotaPolicyEvict).
+				Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+					Flavor(utiltesting.MakeFlavor("default", "1").Obj()).
+					Obj()).
+				Obj(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+			if err := cache.AddClusterQueue(context.Background(), tc.clusterQueue); err != nil {
+				t.Fatalf("Failed adding clusterQueue: %v", err)
+			}
+			for _, w := range workloads {
+				cache.AddOrUpdateWorkload(w)
+			}
+			var got []string
+			for _, w := range workloads {
+				if cache.WorkloadOverQuota(w) {
+					got = append(got, w.Name)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected workloads over quota (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkloadsOverQuotaAfterUpdate(t *testing.T) {
+	now := time.Now()
+	admitted := func(name, cq, cpu string, prio int32, admittedAt time.Time) *kueue.Workload {
+		return utiltesting.MakeWorkload(name, "ns").
+			Request(corev1.ResourceCPU, cpu).
+			Priority(prio).
+			Admit(utiltesting.MakeAdmission(cq).Flavor(corev1.ResourceCPU, "default").Obj()).
+			Condition(metav1.Condition{
+				Type:               kueue.WorkloadAdmitted,
+				Status:             metav1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(admittedAt),
+			}).
+			Obj()
+	}
+	clusterQueue := func(name, cohort, quota string) *kueue.ClusterQueue {
+		return utiltesting.MakeClusterQueue(name).
+			Cohort(cohort).
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", quota).Obj()).
+				Obj()).
+			Obj()
+	}
+	workloads := []*kueue.Workload{
+		admitted("high", "cq", "2", 100, now.Add(-time.Hour)),
+		admitted("low-old", "cq", "2", 0, now.Add(-time.Hour)),
+		admitted("low-new", "cq", "1", 0, now),
+	}
+	testCases := map[string]struct {
+		clusterQueues []*kueue.ClusterQueue
+		workloads     []*kueue.Workload
+		update        *kueue.ClusterQueue
+		want          []string
+	}{
+		"quota increase": {
+			clusterQueues: []*kueue.ClusterQueue{clusterQueue("cq", "", "5")},
+			update:        clusterQueue("cq", "", "10"),
+		},
+		"quota shrink": {
+			clusterQueues: []*kueue.ClusterQueue{clusterQueue("cq", "", "5")},
+			update:        clusterQueue("cq", "", "3"),
+			want:          []string{"ns/low-old"},
+		},
+		"quota shrink covered by the cohort": {
+			clusterQueues: []*kueue.ClusterQueue{
+				clusterQueue("cq", "team", "5"),
+				clusterQueue("other", "team", "5"),
+			},
+			update: clusterQueue("cq", "team", "1"),
+		},
+		"quota shrink with the cohort in use": {
+			clusterQueues: []*kueue.ClusterQueue{
+				clusterQueue("cq", "team", "5"),
+				clusterQueue("other", "team", "5"),
+			},
+			workloads: []*kueue.Workload{admitted("other", "other", "5", 0, now)},
+			update:    clusterQueue("cq", "team", "1"),
+			want:      []string{"ns/high", "ns/low-old"},
+		},
+		"unknown ClusterQueue": {
+			clusterQueues: []*kueue.ClusterQueue{clusterQueue("cq", "", "5")},
+			update:        clusterQueue("new", "", "1"),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+			cache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+			for _, cq := range tc.clusterQueues {
+				if err := cache.AddClusterQueue(ctx, cq); err != nil {
+					t.Fatalf("Failed adding clusterQueue: %v", err)
+				}
+			}
+			for _, w := range append(workloads, tc.workloads...) {
+				cache.AddOrUpdateWorkload(w)
+			}
+			got, err := cache.WorkloadsOverQuotaAfterUpdate(tc.update)
+			if err != nil {
+				t.Fatalf("Simulating the update: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected workloads over quota (-want,+got):\n%s", diff)
+			}
+			// The simulation doesn't change the cache.
+			snapshot := cache.Snapshot()
+			if got := len(snapshot.ClusterQueues["cq"].Workloads); got != len(workloads) {
+				t.Errorf("Unexpected number of workloads in the cache after the simulation: %d", got)
+			}
+			// Nor the metrics of the ClusterQueue.
+			if got := testutil.ToFloat64(metrics.ClusterQueueNominalQuota.WithLabelValues("cq", "default", "cpu")); got != 5 {
+				t.Errorf("Got nominal quota %v after the simulation, want 5", got)
+			}
+			if got := testutil.ToFloat64(metrics.ClusterQueueResourceUsage.WithLabelValues("cq", "default", "cpu")); got != 5 {
+				t.Errorf("Got usage %v after the simulation, want 5", got)
+			}
+		})
+	}
+}
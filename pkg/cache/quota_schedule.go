@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"time"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+const timeOfDayLayout = "15:04"
+
+// quotaScheduleHorizonDays is the number of days after the current one in
+// which the next boundary of the quota schedules is searched. A window starts
+// at least once a week.
+const quotaScheduleHorizonDays = 7
+
+var scheduleWeekdays = map[kueue.ScheduleDay]time.Weekday{
+	"Sunday":    time.Sunday,
+	"Monday":    time.Monday,
+	"Tuesday":   time.Tuesday,
+	"Wednesday": time.Wednesday,
+	"Thursday":  time.Thursday,
+	"Friday":    time.Friday,
+	"Saturday":  time.Saturday,
+}
+
+// quotaScheduleWindow returns the window of the schedule that starts the
+// given number of days after the day of now, in the time zone of the
+// schedule. It returns false if the window doesn't start that day, or the
+// schedule is invalid.
+func quotaScheduleWindow(s *kueue.QuotaSchedule, now time.Time, days int) (time.Time, time.Time, bool) {
+	loc := time.UTC
+	if s.TimeZone != nil {
+		var err error
+		if loc, err = time.LoadLocation(*s.TimeZone); err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	start, err := time.Parse(timeOfDayLayout, s.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.Parse(timeOfDayLayout, s.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	local := now.In(loc)
+	y, m, d := local.Date()
+	windowStart := time.Date(y, m, d+days, start.Hour(), start.Minute(), 0, 0, loc)
+	if len(s.Days) > 0 && !hasWeekday(s.Days, windowStart.Weekday()) {
+		return time.Time{}, time.Time{}, false
+	}
+	windowEnd := time.Date(y, m, d+days, end.Hour(), end.Minute(), 0, 0, loc)
+	if !windowEnd.After(windowStart) {
+		windowEnd = time.Date(y, m, d+days+1, end.Hour(), end.Minute(), 0, 0, loc)
+	}
+	return windowStart, windowEnd, true
+}
+
+func hasWeekday(days []kueue.ScheduleDay, weekday time.Weekday) bool {
+	for _, d := range days {
+		if wd, ok := scheduleWeekdays[d]; ok && wd == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// activeQuotaSchedule returns the first schedule whose window includes now,
+// or nil if there is none. A window that includes now started on the day of
+// now or on the previous day.
+func activeQuotaSchedule(schedules []kueue.QuotaSchedule, now time.Time) *kueue.QuotaSchedule {
+	for i := range schedules {
+		s := &schedules[i]
+		for days := -1; days <= 0; days++ {
+			start, end, ok := quotaScheduleWindow(s, now, days)
+			if ok && !now.Before(start) && now.Before(end) {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// nextQuotaScheduleBoundary returns the earliest time after now at which a
+// window of the schedules starts or ends. It returns false if there are no
+// windows.
+func nextQuotaScheduleBoundary(schedules []kueue.QuotaSchedule, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for i := range schedules {
+		for days := -1; days <= quotaScheduleHorizonDays; days++ {
+			start, end, ok := quotaScheduleWindow(&schedules[i], now, days)
+			if !ok {
+				continue
+			}
+			for _, t := range []time.Time{start, end} {
+				if t.After(now) && (next.IsZero() || t.Before(next)) {
+					next = t
+				}
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// scheduledResourceGroups returns the resource groups with the quotas of the
+// schedule, if any, replacing the ones for the same flavor and resource.
+func scheduledResourceGroups(groups []kueue.ResourceGroup, schedule *kueue.QuotaSchedule) []kueue.ResourceGroup {
+	if schedule == nil {
+		return groups
+	}
+	out := make([]kueue.ResourceGroup, len(groups))
+	for i := range groups {
+		groups[i].DeepCopyInto(&out[i])
+	}
+	for _, sq := range schedule.Quotas {
+		for i := range out {
+			for j := range out[i].Flavors {
+				fq := &out[i].Flavors[j]
+				if fq.Name != sq.Flavor {
+					continue
+				}
+				for k := range fq.Resources {
+					if fq.Resources[k].Name == sq.Resource {
+						fq.Resources[k].Quota = *sq.Quota.DeepCopy()
+					}
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/util/pointer"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+// 2024-01-01 is a Monday.
+func monday(hour, min int) time.Time {
+	return time.Date(2024, 1, 1, hour, min, 0, 0, time.UTC)
+}
+
+var testQuotaSchedules = []kueue.QuotaSchedule{
+	{
+		Name:  "weekends",
+		Days:  []kueue.ScheduleDay{"Saturday"},
+		Start: "00:00",
+		End:   "00:00",
+		Quotas: []kueue.ScheduledQuota{{
+			Flavor:   "default",
+			Resource: corev1.ResourceCPU,
+			Quota:    kueue.Quota{Min: resource.MustParse("30")},
+		}},
+	},
+	{
+		Name:  "nights",
+		Days:  []kueue.ScheduleDay{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+		Start: "20:00",
+		End:   "08:00",
+		Quotas: []kueue.ScheduledQuota{{
+			Flavor:   "default",
+			Resource: corev1.ResourceCPU,
+			Quota:    kueue.Quota{Min: resource.MustParse("20")},
+		}},
+	},
+}
+
+func TestActiveQuotaSchedule(t *testing.T) {
+	cases := map[string]struct {
+		now  time.Time
+		want string
+	}{
+		"working hours": {
+			now: monday(10, 0),
+		},
+		"start of the night": {
+			now:  monday(20, 0),
+			want: "nights",
+		},
+		"night, after midnight": {
+			now:  monday(24+7, 59),
+			want: "nights",
+		},
+		"end of the night": {
+			now: monday(24+8, 0),
+		},
+		"Monday early morning, the night started on Sunday": {
+			now: monday(6, 0),
+		},
+		"Friday night": {
+			now:  monday(4*24+23, 0),
+			want: "nights",
+		},
+		"Saturday early morning, both windows": {
+			now:  monday(5*24+6, 0),
+			want: "weekends",
+		},
+		"Saturday morning": {
+			now:  monday(5*24+10, 0),
+			want: "weekends",
+		},
+		"Sunday": {
+			now: monday(6*24+10, 0),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if s := activeQuotaSchedule(testQuotaSchedules, tc.now); s != nil {
+				got = s.Name
+			}
+			if got != tc.want {
+				t.Errorf("activeQuotaSchedule() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestActiveQuotaScheduleTimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("Time zone database not available: %v", err)
+	}
+	schedules := []kueue.QuotaSchedule{{
+		Name:     "mornings",
+		Start:    "09:00",
+		End:      "10:00",
+		TimeZone: pointer.String("Europe/Madrid"),
+	}}
+	now := time.Date(2024, 1, 1, 9, 30, 0, 0, loc)
+	if s := activeQuotaSchedule(schedules, now.UTC()); s == nil {
+		t.Errorf("Schedule not active at %s", now)
+	}
+	if s := activeQuotaSchedule(schedules, time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)); s != nil {
+		t.Errorf("Schedule active at 09:30 UTC")
+	}
+}
+
+func TestNextQuotaScheduleBoundary(t *testing.T) {
+	cases := map[string]struct {
+		schedules []kueue.QuotaSchedule
+		now       time.Time
+		want      time.Time
+		wantOk    bool
+	}{
+		"no schedules": {
+			now: monday(10, 0),
+		},
+		"start of the night": {
+			schedules: testQuotaSchedules,
+			now:       monday(10, 0),
+			want:      monday(20, 0),
+			wantOk:    true,
+		},
+		"at the start of the night": {
+			schedules: testQuotaSchedules,
+			now:       monday(20, 0),
+			want:      monday(24+8, 0),
+			wantOk:    true,
+		},
+		"during the weekend": {
+			schedules: testQuotaSchedules,
+			now:       monday(5*24+10, 0),
+			want:      monday(6*24, 0),
+			wantOk:    true,
+		},
+		"Sunday": {
+			schedules: testQuotaSchedules,
+			now:       monday(6*24+10, 0),
+			want:      monday(7*24+20, 0),
+			wantOk:    true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := nextQuotaScheduleBoundary(tc.schedules, tc.now)
+			if ok != tc.wantOk || !got.Equal(tc.want) {
+				t.Errorf("nextQuotaScheduleBoundary() = (%s, %t), want (%s, %t)", got, ok, tc.want, tc.wantOk)
+			}
+		})
+	}
+}
+
+func TestCacheUpdateQuotaSchedule(t *testing.T) {
+	cqCache := New(fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build())
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("default", "10").Obj()).
+			Obj()).
+		Obj()
+	cq.Spec.QuotaSchedules = testQuotaSchedules
+	if err := cqCache.AddClusterQueue(context.Background(), cq); err != nil {
+		t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+	}
+	checkMin := func(step string, want int64) {
+		t.Helper()
+		snap := cqCache.Snapshot()
+		if diff := cmp.Diff([]FlavorLimits{{Name: "default", Min: want}}, snap.ClusterQueues["cq"].RequestableResources[corev1.ResourceCPU].Flavors); diff != "" {
+			t.Errorf("Unexpected flavor limits %s (-want,+got):\n%s", step, diff)
+		}
+	}
+
+	_, next := cqCache.UpdateQuotaSchedule("cq", monday(10, 0))
+	checkMin("during working hours", 10_000)
+	if !next.Equal(monday(20, 0)) {
+		t.Errorf("Got next boundary %s during working hours, want %s", next, monday(20, 0))
+	}
+
+	changed, next := cqCache.UpdateQuotaSchedule("cq", monday(20, 0))
+	if !changed {
+		t.Error("The active schedule didn't change at the start of the night")
+	}
+	checkMin("at night", 20_000)
+	if !next.Equal(monday(24+8, 0)) {
+		t.Errorf("Got next boundary %s at night, want %s", next, monday(24+8, 0))
+	}
+
+	if changed, _ := cqCache.UpdateQuotaSchedule("cq", monday(23, 0)); changed {
+		t.Error("The active schedule changed during the night")
+	}
+
+	if changed, _ := cqCache.UpdateQuotaSchedule("cq", monday(24+8, 0)); !changed {
+		t.Error("The active schedule didn't change at the end of the night")
+	}
+	checkMin("after the night", 10_000)
+
+	if changed, next := cqCache.UpdateQuotaSchedule("missing", monday(10, 0)); changed || !next.IsZero() {
+		t.Errorf("UpdateQuotaSchedule() for a missing ClusterQueue = (%t, %s), want (false, zero)", changed, next)
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package cache
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
@@ -27,34 +30,115 @@ type Snapshot struct {
 	ClusterQueues            map[string]*ClusterQueue
 	ResourceFlavors          map[string]*kueue.ResourceFlavor
 	InactiveClusterQueueSets sets.Set[string]
+	// Reservations are the quota reservations of pending workloads, keyed by
+	// workload. Their usage is included in the usage of their ClusterQueues.
+	Reservations map[string]*workload.Info
+}
+
+// SliceKey returns the key of the slice of the snapshot that holds the
+// ClusterQueue: the name of the root of its hierarchy of cohorts or, if it
+// doesn't belong to a cohort or isn't in the snapshot, its own name.
+func (s *Snapshot) SliceKey(cqName string) string {
+	cq := s.ClusterQueues[cqName]
+	if cq == nil || cq.Cohort == nil {
+		return cqName
+	}
+	return cq.Cohort.Root().Name
+}
+
+// SlicesByCohort splits the snapshot in slices, keyed by SliceKey, with the
+// ClusterQueues of each hierarchy of cohorts and their reservations. As the
+// ClusterQueues of different hierarchies don't share quota, the slices can be
+// used concurrently. The resource flavors and the inactive ClusterQueues are
+// shared by all the slices, so they must not be modified.
+func (s *Snapshot) SlicesByCohort() map[string]*Snapshot {
+	slices := make(map[string]*Snapshot)
+	for name, cq := range s.ClusterQueues {
+		key := s.SliceKey(name)
+		slice := slices[key]
+		if slice == nil {
+			slice = s.EmptySlice()
+			slices[key] = slice
+		}
+		slice.ClusterQueues[name] = cq
+	}
+	for k, r := range s.Reservations {
+		slice := slices[s.SliceKey(r.ClusterQueue)]
+		if slice.Reservations == nil {
+			slice.Reservations = make(map[string]*workload.Info)
+		}
+		slice.Reservations[k] = r
+	}
+	return slices
+}
+
+// EmptySlice returns a slice of the snapshot without ClusterQueues.
+func (s *Snapshot) EmptySlice() *Snapshot {
+	return &Snapshot{
+		ClusterQueues:            make(map[string]*ClusterQueue),
+		ResourceFlavors:          s.ResourceFlavors,
+		InactiveClusterQueueSets: s.InactiveClusterQueueSets,
+	}
 }
 
 // RemoveWorkload removes a workload from its corresponding ClusterQueue and
 // updates resources usage.
 func (s *Snapshot) RemoveWorkload(wl *workload.Info) {
 	cq := s.ClusterQueues[wl.ClusterQueue]
+	cq.ownWorkloads()
 	delete(cq.Workloads, workload.Key(wl.Obj))
 	updateUsage(wl, cq.UsedResources, -1)
-	if cq.Cohort != nil {
-		updateUsage(wl, cq.Cohort.UsedResources, -1)
-	}
+	updateCohortUsage(cq, wl, -1)
 }
 
 // AddWorkload removes a workload from its corresponding ClusterQueue and
 // updates resources usage.
 func (s *Snapshot) AddWorkload(wl *workload.Info) {
 	cq := s.ClusterQueues[wl.ClusterQueue]
+	cq.ownWorkloads()
 	cq.Workloads[workload.Key(wl.Obj)] = wl
 	updateUsage(wl, cq.UsedResources, 1)
-	if cq.Cohort != nil {
-		updateUsage(wl, cq.Cohort.UsedResources, 1)
+	updateCohortUsage(cq, wl, 1)
+}
+
+// RemoveReservation releases the quota reserved for a workload from the usage
+// of its ClusterQueue. It returns the reservation, or nil if there was none.
+func (s *Snapshot) RemoveReservation(key string) *workload.Info {
+	r := s.Reservations[key]
+	if r == nil {
+		return nil
+	}
+	delete(s.Reservations, key)
+	cq := s.ClusterQueues[r.ClusterQueue]
+	updateUsage(r, cq.UsedResources, -1)
+	updateCohortUsage(cq, r, -1)
+	return r
+}
+
+// AddReservation adds a quota reservation to the usage of its ClusterQueue.
+func (s *Snapshot) AddReservation(r *workload.Info) {
+	cq := s.ClusterQueues[r.ClusterQueue]
+	if s.Reservations == nil {
+		s.Reservations = make(map[string]*workload.Info)
 	}
+	s.Reservations[workload.Key(r.Obj)] = r
+	updateUsage(r, cq.UsedResources, 1)
+	updateCohortUsage(cq, r, 1)
 }
 
 func (c *Cache) Snapshot() Snapshot {
-	c.RLock()
-	defer c.RUnlock()
+	// The write lock is needed because the ClusterQueues are marked as shared
+	// with the snapshot, and the resources and topologies of the nodes might
+	// be calculated.
+	c.Lock()
+	defer c.Unlock()
+	return c.snapshot(nil)
+}
 
+// snapshot takes a snapshot of the cache, calling adjust, if not nil, with
+// the copy of each ClusterQueue before accumulating the quotas and usage of
+// the cohorts. It must be called with the write lock held.
+func (c *Cache) snapshot(adjust func(*ClusterQueue)) Snapshot {
 	snap := Snapshot{
 		ClusterQueues:            make(map[string]*ClusterQueue, len(c.clusterQueues)),
 		ResourceFlavors:          make(map[string]*kueue.ResourceFlavor, len(c.resourceFlavors)),
@@ -65,12 +149,45 @@ func (c *Cache) Snapshot() Snapshot {
 			snap.InactiveClusterQueueSets.Insert(cq.Name)
 			continue
 		}
-		snap.ClusterQueues[cq.Name] = cq.snapshot()
+		cqCopy := cq.snapshot()
+		if adjust != nil {
+			adjust(cqCopy)
+		}
+		snap.ClusterQueues[cq.Name] = cqCopy
 	}
 	for _, rf := range c.resourceFlavors {
 		// Shallow copy is enough
 		snap.ResourceFlavors[rf.Name] = rf
 	}
+	if c.nodeTracking {
+		// The map is replaced, not modified, when the nodes or flavors change.
+		nodeResources := c.nodeResourcesPerFlavor()
+		for _, cq := range snap.ClusterQueues {
+			cq.FlavorNodeResources = nodeResources
+		}
+	}
+	if c.topologyTracking {
+		// The usage of the topology domains is shared by all the ClusterQueues,
+		// as they share the nodes.
+		topologies := c.snapshotTopologies()
+		for _, cq := range snap.ClusterQueues {
+			cq.FlavorTopologies = topologies
+		}
+	}
+	now := time.Now()
+	for k, r := range c.reservations {
+		cq := snap.ClusterQueues[r.info.ClusterQueue]
+		if cq == nil || !r.expiresAt.After(now) {
+			continue
+		}
+		if snap.Reservations == nil {
+			snap.Reservations = make(map[string]*workload.Info)
+		}
+		// Shallow copy is enough.
+		snap.Reservations[k] = r.info
+		updateUsage(r.info, cq.UsedResources, 1)
+	}
+	cohorts := make(map[string]*Cohort, len(c.cohorts))
 	for _, cohort := range c.cohorts {
 		cohortCopy := newCohort(cohort.Name, cohort.Members.Len())
 		for cq := range cohort.Members {
@@ -81,23 +198,153 @@ func (c *Cache) Snapshot() Snapshot {
 				cohortCopy.Members.Insert(cqCopy)
 			}
 		}
+		cohorts[cohort.Name] = cohortCopy
+	}
+	if len(c.cohortConfigs) > 0 {
+		c.snapshotCohortHierarchy(cohorts)
 	}
 	return snap
 }
 
+// snapshotCohortHierarchy links the cohorts of a snapshot to their parents,
+// according to the Cohort objects, and accumulates the quotas and usage of
+// each cohort into its ancestors.
+func (c *Cache) snapshotCohortHierarchy(cohorts map[string]*Cohort) {
+	getCohort := func(name string) *Cohort {
+		cohort := cohorts[name]
+		if cohort == nil {
+			cohort = newCohort(name, 0)
+			cohorts[name] = cohort
+		}
+		return cohort
+	}
+	for name, cfg := range c.cohortConfigs {
+		cohort := getCohort(name)
+		for rName, res := range cfg.resources {
+			for _, flavor := range res.Flavors {
+				addQuantity(&cohort.RequestableResources, rName, flavor.Name, flavor.Min)
+				if flavor.Max != nil {
+					addQuantity(&cohort.Limits, rName, flavor.Name, *flavor.Max)
+				}
+				// Make sure that the usage of the flavor is tracked.
+				addQuantity(&cohort.UsedResources, rName, flavor.Name, 0)
+			}
+		}
+	}
+	// Only link the cohorts once all the cohorts exist, so that the quotas
+	// accumulated by each cohort are only its own.
+	type own struct {
+		requestable, used ResourceQuantities
+	}
+	owned := make(map[*Cohort]own, len(cohorts))
+	for _, cohort := range cohorts {
+		var o own
+		accumulateQuantities(&o.requestable, cohort.RequestableResources)
+		accumulateQuantities(&o.used, cohort.UsedResources)
+		owned[cohort] = o
+	}
+	for name, cfg := range c.cohortConfigs {
+		if cfg.parent == "" {
+			continue
+		}
+		cohort := cohorts[name]
+		parent := getCohort(cfg.parent)
+		if cohort.HasDescendant(parent) {
+			// Ignore the parent if it would create a cycle.
+			continue
+		}
+		cohort.Parent = parent
+		if parent.ChildCohorts == nil {
+			parent.ChildCohorts = sets.New[*Cohort]()
+		}
+		parent.ChildCohorts.Insert(cohort)
+	}
+	for cohort, o := range owned {
+		for ancestor := cohort.Parent; ancestor != nil; ancestor = ancestor.Parent {
+			accumulateQuantities(&ancestor.RequestableResources, o.requestable)
+			accumulateQuantities(&ancestor.UsedResources, o.used)
+		}
+	}
+}
+
+// updateCohortUsage updates the usage of the cohort of the ClusterQueue, and
+// its ancestors, with the usage of the workload. The usage of the ClusterQueue
+// must already include the workload.
+func updateCohortUsage(cq *ClusterQueue, wi *workload.Info, m int64) {
+	if cq.Cohort == nil {
+		return
+	}
+	requests := make(ResourceQuantities)
+	for _, ps := range wi.TotalRequests {
+		for rName, flavor := range ps.Flavors {
+			if v, found := ps.Requests[rName]; found {
+				addQuantity(&requests, rName, usageFlavor(cq.UsedResources[rName], flavor), v)
+			}
+		}
+	}
+	for rName, flavors := range requests {
+		for flavor, v := range flavors {
+			used, found := cq.UsedResources[rName][flavor]
+			if !found {
+				continue
+			}
+			delta := v * m
+			if limits := cq.flavorLimits(rName, flavor); limits != nil {
+				// Only the usage beyond the guaranteed quota is accounted in the cohort.
+				delta = limits.LentUsage(used) - limits.LentUsage(used-v*m)
+			}
+			for cohort := cq.Cohort; cohort != nil; cohort = cohort.Parent {
+				if cohortFlavors, found := cohort.UsedResources[rName]; found {
+					if _, found := cohortFlavors[flavor]; found {
+						cohortFlavors[flavor] += delta
+					}
+				}
+			}
+		}
+	}
+}
+
+func addQuantity(q *ResourceQuantities, rName corev1.ResourceName, flavor string, val int64) {
+	if *q == nil {
+		*q = make(ResourceQuantities)
+	}
+	flavors := (*q)[rName]
+	if flavors == nil {
+		flavors = make(map[string]int64)
+		(*q)[rName] = flavors
+	}
+	flavors[flavor] += val
+}
+
+func accumulateQuantities(dst *ResourceQuantities, src ResourceQuantities) {
+	for rName, flavors := range src {
+		for flavor, val := range flavors {
+			addQuantity(dst, rName, flavor, val)
+		}
+	}
+}
+
 // Snapshot creates a copy of ClusterQueue that includes references to immutable
 // objects and deep copies of changing ones. A reference to the cohort is not included.
+// The Workloads map is shared with the snapshot, and copied by the cache or
+// the snapshot only when any of them modifies it.
 func (c *ClusterQueue) snapshot() *ClusterQueue {
 	cc := &ClusterQueue{
 		Name:                 c.Name,
 		RequestableResources: c.RequestableResources, // Shallow copy is enough.
 		UsedResources:        make(ResourceQuantities, len(c.UsedResources)),
-		Workloads:            make(map[string]*workload.Info, len(c.Workloads)),
+		Workloads:            c.Workloads,
 		Preemption:           c.Preemption,
+		FlavorFungibility:    c.FlavorFungibility,
+		SchedulingProfile:    c.SchedulingProfile,
+		PriorityAging:        c.PriorityAging,
 		LabelKeys:            c.LabelKeys, // Shallow copy is enough.
+		AdmissionChecks:      c.AdmissionChecks,
 		NamespaceSelector:    c.NamespaceSelector,
 		Status:               c.Status,
+		workloadsShared:      true,
 	}
+	c.workloadsShared = true
 	for res, flavors := range c.UsedResources {
 		flavorsCopy := make(map[string]int64, len(flavors))
 		for k, v := range flavors {
@@ -105,10 +352,6 @@ func (c *ClusterQueue) snapshot() *ClusterQueue {
 		}
 		cc.UsedResources[res] = flavorsCopy
 	}
-	for k, v := range c.Workloads {
-		// Shallow copy is enough.
-		cc.Workloads[k] = v
-	}
 	return cc
 }
 
@@ -123,7 +366,7 @@ func (c *ClusterQueue) accumulateResources(cohort *Cohort) {
 			cohort.RequestableResources[name] = req
 		}
 		for _, flavor := range res.Flavors {
-			req[flavor.Name] += flavor.Min
+			req[flavor.Name] += flavor.Min - flavor.Guaranteed()
 		}
 	}
 	if cohort.UsedResources == nil {
@@ -136,6 +379,9 @@ func (c *ClusterQueue) accumulateResources(cohort *Cohort) {
 			cohort.UsedResources[res] = used
 		}
 		for flavor, val := range flavors {
+			if limits := c.flavorLimits(res, flavor); limits != nil {
+				val = limits.LentUsage(val)
+			}
 			used[flavor] += val
 		}
 	}
@@ -18,11 +18,14 @@ package cache
 
 import (
 	"context"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -80,7 +83,9 @@ func TestSnapshot(t *testing.T) {
 								utiltesting.MakeWorkload("alpha", "").
 									Admit(&kueue.Admission{ClusterQueue: "a"}).Obj()),
 						},
-						Preemption: defaultPreemption,
+						Preemption:        defaultPreemption,
+						FlavorFungibility: defaultFlavorFungibility,
+						SchedulingProfile: kueue.SchedulingProfileOrdered,
 					},
 					"b": {
 						Name:                 "b",
@@ -93,7 +98,9 @@ func TestSnapshot(t *testing.T) {
 								utiltesting.MakeWorkload("beta", "").
 									Admit(&kueue.Admission{ClusterQueue: "b"}).Obj()),
 						},
-						Preemption: defaultPreemption,
+						Preemption:        defaultPreemption,
+						FlavorFungibility: defaultFlavorFungibility,
+						SchedulingProfile: kueue.SchedulingProfileOrdered,
 					},
 				},
 				ResourceFlavors: map[string]*kueue.ResourceFlavor{},
@@ -102,9 +109,9 @@ func TestSnapshot(t *testing.T) {
 		"inactive clusterQueues": {
 			cqs: []*kueue.ClusterQueue{
 				utiltesting.MakeClusterQueue("flavor-nonexistent-cq").
-					Resource(&kueue.Resource{
+					Resource(&utiltesting.ResourceQuotas{
 						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						Flavors: []utiltesting.FlavorQuota{
 							*utiltesting.MakeFlavor("nonexistent-flavor", "100").Obj(),
 						},
 					}).Obj(),
@@ -138,30 +145,30 @@ func TestSnapshot(t *testing.T) {
 			cqs: []*kueue.ClusterQueue{
 				utiltesting.MakeClusterQueue("a").
 					Cohort("borrowing").
-					Resource(&kueue.Resource{
+					Resource(&utiltesting.ResourceQuotas{
 						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						Flavors: []utiltesting.FlavorQuota{
 							*utiltesting.MakeFlavor("demand", "100").Obj(),
 							*utiltesting.MakeFlavor("spot", "200").Obj(),
 						},
 					}).Obj(),
 				utiltesting.MakeClusterQueue("b").
 					Cohort("borrowing").
-					Resource(&kueue.Resource{
+					Resource(&utiltesting.ResourceQuotas{
 						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						Flavors: []utiltesting.FlavorQuota{
 							*utiltesting.MakeFlavor("spot", "100").Obj(),
 						},
-					}).Resource(&kueue.Resource{
+					}).Resource(&utiltesting.ResourceQuotas{
 					Name: "example.com/gpu",
-					Flavors: []kueue.Flavor{
+					Flavors: []utiltesting.FlavorQuota{
 						*utiltesting.MakeFlavor("default", "50").Obj(),
 					},
 				}).Obj(),
 				utiltesting.MakeClusterQueue("c").
-					Resource(&kueue.Resource{
+					Resource(&utiltesting.ResourceQuotas{
 						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						Flavors: []utiltesting.FlavorQuota{
 							*utiltesting.MakeFlavor("default", "100").Obj(),
 						},
 					}).Obj(),
@@ -301,7 +308,9 @@ func TestSnapshot(t *testing.T) {
 										}},
 									}).Obj()),
 							},
-							Preemption: defaultPreemption,
+							Preemption:        defaultPreemption,
+							FlavorFungibility: defaultFlavorFungibility,
+							SchedulingProfile: kueue.SchedulingProfileOrdered,
 							LabelKeys: map[corev1.ResourceName]sets.Set[string]{
 								corev1.ResourceCPU: sets.New("one", "two", "instance"),
 							},
@@ -373,7 +382,9 @@ func TestSnapshot(t *testing.T) {
 										}},
 									}).Obj()),
 							},
-							Preemption: defaultPreemption,
+							Preemption:        defaultPreemption,
+							FlavorFungibility: defaultFlavorFungibility,
+							SchedulingProfile: kueue.SchedulingProfileOrdered,
 							LabelKeys: map[corev1.ResourceName]sets.Set[string]{
 								corev1.ResourceCPU: sets.New("two", "instance"),
 							},
@@ -397,6 +408,8 @@ func TestSnapshot(t *testing.T) {
 							},
 							Workloads:         map[string]*workload.Info{},
 							Preemption:        defaultPreemption,
+							FlavorFungibility: defaultFlavorFungibility,
+							SchedulingProfile: kueue.SchedulingProfileOrdered,
 							NamespaceSelector: labels.Everything(),
 							Status:            active,
 						},
@@ -432,6 +445,8 @@ func TestSnapshot(t *testing.T) {
 							ReclaimWithinCohort: kueue.PreemptionPolicyAny,
 							WithinClusterQueue:  kueue.PreemptionPolicyLowerPriority,
 						},
+						FlavorFungibility: defaultFlavorFungibility,
+						SchedulingProfile: kueue.SchedulingProfileOrdered,
 					},
 				},
 				ResourceFlavors: map[string]*kueue.ResourceFlavor{},
@@ -677,7 +692,7 @@ func TestSnapshotAddRemoveWorkload(t *testing.T) {
 		},
 	}
 	cmpOpts := append(snapCmpOpts,
-		cmpopts.IgnoreFields(ClusterQueue{}, "NamespaceSelector", "Preemption", "Status"),
+		cmpopts.IgnoreFields(ClusterQueue{}, "NamespaceSelector", "Preemption", "FlavorFungibility", "SchedulingProfile", "Status"),
 		cmpopts.IgnoreFields(Snapshot{}, "ResourceFlavors"),
 		cmpopts.IgnoreTypes(&workload.Info{}))
 	for name, tc := range cases {
@@ -695,3 +710,367 @@ func TestSnapshotAddRemoveWorkload(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotReservations(t *testing.T) {
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("c1").
+			Cohort("cohort").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "6").Obj()).
+				Obj()).
+			Obj(),
+		utiltesting.MakeClusterQueue("c2").
+			Cohort("cohort").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "6").Obj()).
+				Obj()).
+			Obj(),
+	}
+	reserved := utiltesting.MakeWorkload("reserved", "").
+		Request(corev1.ResourceCPU, "2").
+		Admit(utiltesting.MakeAdmission("c1").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj()
+	expired := utiltesting.MakeWorkload("expired", "").
+		Request(corev1.ResourceCPU, "1").
+		Admit(utiltesting.MakeAdmission("c2").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj()
+
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	for _, cq := range clusterQueues {
+		if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+		}
+	}
+	now := time.Now()
+	if err := cqCache.ReserveQuota(expired, now.Add(-time.Second)); err != nil {
+		t.Fatalf("Couldn't reserve quota: %v", err)
+	}
+	if err := cqCache.ReserveQuota(reserved, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Couldn't reserve quota: %v", err)
+	}
+	if err := cqCache.ReserveQuota(utiltesting.MakeWorkload("pending", "").Obj(), now.Add(time.Minute)); err == nil {
+		t.Error("Reserved quota for a workload without admission")
+	}
+
+	type usage struct {
+		c1, c2, cohort int64
+	}
+	usageOf := func(snap *Snapshot) usage {
+		return usage{
+			c1:     snap.ClusterQueues["c1"].UsedResources[corev1.ResourceCPU]["default"],
+			c2:     snap.ClusterQueues["c2"].UsedResources[corev1.ResourceCPU]["default"],
+			cohort: snap.ClusterQueues["c1"].Cohort.UsedResources[corev1.ResourceCPU]["default"],
+		}
+	}
+
+	snap := cqCache.Snapshot()
+	if diff := cmp.Diff([]string{"/reserved"}, sets.List(sets.KeySet(snap.Reservations))); diff != "" {
+		t.Errorf("Unexpected reservations (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(usage{c1: 2_000, cohort: 2_000}, usageOf(&snap), cmp.AllowUnexported(usage{})); diff != "" {
+		t.Errorf("Unexpected usage with reservations (-want,+got):\n%s", diff)
+	}
+	r := snap.RemoveReservation("/reserved")
+	if r == nil {
+		t.Fatal("RemoveReservation didn't return the reservation")
+	}
+	if diff := cmp.Diff(usage{}, usageOf(&snap), cmp.AllowUnexported(usage{})); diff != "" {
+		t.Errorf("Unexpected usage after removing the reservation (-want,+got):\n%s", diff)
+	}
+	snap.AddReservation(r)
+	if diff := cmp.Diff(usage{c1: 2_000, cohort: 2_000}, usageOf(&snap), cmp.AllowUnexported(usage{})); diff != "" {
+		t.Errorf("Unexpected usage after adding back the reservation (-want,+got):\n%s", diff)
+	}
+
+	cqCache.ReleaseQuota(reserved)
+	snap = cqCache.Snapshot()
+	if snap.Reservations != nil {
+		t.Errorf("Unexpected reservations after releasing quota: %v", sets.List(sets.KeySet(snap.Reservations)))
+	}
+	if diff := cmp.Diff(usage{}, usageOf(&snap), cmp.AllowUnexported(usage{})); diff != "" {
+		t.Errorf("Unexpected usage after releasing quota (-want,+got):\n%s", diff)
+	}
+}
+
+func TestSnapshotCohortHierarchy(t *testing.T) {
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("c1").
+			Cohort("child").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "6").Obj()).
+				Obj()).
+			Obj(),
+		utiltesting.MakeClusterQueue("c2").
+			Cohort("root").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "2").Obj()).
+				Obj()).
+			Obj(),
+	}
+	cohorts := []*kueue.Cohort{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "child"},
+			Spec:       kueue.CohortSpec{Parent: "root"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "root"},
+			Spec: kueue.CohortSpec{
+				ResourceGroups: utiltesting.ResourceGroups(
+					utiltesting.MakeResource(corev1.ResourceCPU).
+						Flavor(utiltesting.MakeFlavor("default", "4").Max("20").Obj()).
+						Obj(),
+				),
+			},
+		},
+		{
+			// Cycles are ignored.
+			ObjectMeta: metav1.ObjectMeta{Name: "cycle"},
+			Spec:       kueue.CohortSpec{Parent: "cycle"},
+		},
+	}
+	admitted := utiltesting.MakeWorkload("admitted", "").
+		Request(corev1.ResourceCPU, "3").
+		Admit(utiltesting.MakeAdmission("c1").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj()
+
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	for _, cq := range clusterQueues {
+		if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+		}
+	}
+	for _, c := range cohorts {
+		cqCache.AddOrUpdateCohort(c)
+	}
+	if !cqCache.AddOrUpdateWorkload(admitted) {
+		t.Fatal("Couldn't add Workload to cache")
+	}
+
+	snap := cqCache.Snapshot()
+	child := snap.ClusterQueues["c1"].Cohort
+	root := snap.ClusterQueues["c2"].Cohort
+	if child.Parent != root {
+		t.Fatalf("Cohort %q has parent %v, want %q", child.Name, child.Parent, root.Name)
+	}
+	if child.Root() != root {
+		t.Errorf("Cohort %q has root %q, want %q", child.Name, child.Root().Name, root.Name)
+	}
+	if diff := cmp.Diff([]string{"c1", "c2"}, memberNames(root.AllMembers())); diff != "" {
+		t.Errorf("Unexpected members of the root cohort (-want,+got):\n%s", diff)
+	}
+	wantChild := ResourceQuantities{corev1.ResourceCPU: {"default": 6_000}}
+	if diff := cmp.Diff(wantChild, child.RequestableResources); diff != "" {
+		t.Errorf("Unexpected requestable resources in child cohort (-want,+got):\n%s", diff)
+	}
+	wantRoot := ResourceQuantities{corev1.ResourceCPU: {"default": 12_000}}
+	if diff := cmp.Diff(wantRoot, root.RequestableResources); diff != "" {
+		t.Errorf("Unexpected requestable resources in root cohort (-want,+got):\n%s", diff)
+	}
+	wantUsed := ResourceQuantities{corev1.ResourceCPU: {"default": 3_000}}
+	if diff := cmp.Diff(wantUsed, root.UsedResources); diff != "" {
+		t.Errorf("Unexpected used resources in root cohort (-want,+got):\n%s", diff)
+	}
+	wantLimits := ResourceQuantities{corev1.ResourceCPU: {"default": 20_000}}
+	if diff := cmp.Diff(wantLimits, root.Limits); diff != "" {
+		t.Errorf("Unexpected limits in root cohort (-want,+got):\n%s", diff)
+	}
+
+	// Usage added to a ClusterQueue propagates to all the ancestors.
+	snap.AddWorkload(workload.NewInfo(utiltesting.MakeWorkload("new", "").
+		Request(corev1.ResourceCPU, "1").
+		Admit(utiltesting.MakeAdmission("c1").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj()))
+	if got := child.UsedResources[corev1.ResourceCPU]["default"]; got != 4_000 {
+		t.Errorf("Child cohort uses %d, want 4000", got)
+	}
+	if got := root.UsedResources[corev1.ResourceCPU]["default"]; got != 4_000 {
+		t.Errorf("Root cohort uses %d, want 4000", got)
+	}
+}
+
+func TestSnapshotSlicesByCohort(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	for _, cq := range []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("c1").Cohort("child").Obj(),
+		utiltesting.MakeClusterQueue("c2").Cohort("root").Obj(),
+		utiltesting.MakeClusterQueue("c3").Cohort("other").Obj(),
+		utiltesting.MakeClusterQueue("standalone").Obj(),
+	} {
+		if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+		}
+	}
+	cqCache.AddOrUpdateCohort(&kueue.Cohort{
+		ObjectMeta: metav1.ObjectMeta{Name: "child"},
+		Spec:       kueue.CohortSpec{Parent: "root"},
+	})
+	reserved := utiltesting.MakeWorkload("reserved", "").
+		Admit(utiltesting.MakeAdmission("c1").Obj()).
+		Obj()
+	if err := cqCache.ReserveQuota(reserved, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Couldn't reserve quota: %v", err)
+	}
+
+	snap := cqCache.Snapshot()
+	slices := snap.SlicesByCohort()
+	got := make(map[string][]string, len(slices))
+	for key, slice := range slices {
+		for name, cq := range slice.ClusterQueues {
+			if cq != snap.ClusterQueues[name] {
+				t.Errorf("The slice %q has a different ClusterQueue %q than the snapshot", key, name)
+			}
+			got[key] = append(got[key], name)
+		}
+		sort.Strings(got[key])
+	}
+	want := map[string][]string{
+		"root":       {"c1", "c2"},
+		"other":      {"c3"},
+		"standalone": {"standalone"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected slices (-want,+got):\n%s", diff)
+	}
+	if _, found := slices["root"].Reservations["/reserved"]; !found {
+		t.Error("The reservation isn't in the slice of its ClusterQueue")
+	}
+	if len(slices["other"].Reservations) != 0 {
+		t.Errorf("Unexpected reservations in another slice: %v", slices["other"].Reservations)
+	}
+}
+
+func memberNames(members sets.Set[*ClusterQueue]) []string {
+	names := make([]string, 0, len(members))
+	for cq := range members {
+		names = append(names, cq.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSnapshotLendingLimit(t *testing.T) {
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("a").
+			Cohort("cohort").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "6").LendingLimit("2").Obj()).
+				Obj()).
+			Obj(),
+		utiltesting.MakeClusterQueue("b").
+			Cohort("cohort").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "4").Obj()).
+				Obj()).
+			Obj(),
+	}
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	for _, cq := range clusterQueues {
+		if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+		}
+	}
+	admitted := utiltesting.MakeWorkload("admitted", "").
+		Request(corev1.ResourceCPU, "3").
+		Admit(utiltesting.MakeAdmission("a").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj()
+	if !cqCache.AddOrUpdateWorkload(admitted) {
+		t.Fatal("Couldn't add Workload to cache")
+	}
+
+	snap := cqCache.Snapshot()
+	cohort := snap.ClusterQueues["a"].Cohort
+	cohortUsage := func() int64 {
+		return cohort.UsedResources[corev1.ResourceCPU]["default"]
+	}
+	// Only the lendable quota of "a" is requestable in the cohort.
+	if got := cohort.RequestableResources[corev1.ResourceCPU]["default"]; got != 6_000 {
+		t.Errorf("Cohort has %d requestable, want 6000", got)
+	}
+	// The usage within the guaranteed quota of "a" isn't accounted in the cohort.
+	if got := cohortUsage(); got != 0 {
+		t.Errorf("Cohort uses %d, want 0", got)
+	}
+
+	wl := workload.NewInfo(utiltesting.MakeWorkload("new", "").
+		Request(corev1.ResourceCPU, "2").
+		Admit(utiltesting.MakeAdmission("a").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj())
+	snap.AddWorkload(wl)
+	if got := cohortUsage(); got != 1_000 {
+		t.Errorf("After adding a workload, cohort uses %d, want 1000", got)
+	}
+	snap.RemoveWorkload(wl)
+	if got := cohortUsage(); got != 0 {
+		t.Errorf("After removing the workload, cohort uses %d, want 0", got)
+	}
+	snap.AddWorkload(workload.NewInfo(utiltesting.MakeWorkload("other", "").
+		Request(corev1.ResourceCPU, "2").
+		Admit(utiltesting.MakeAdmission("b").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj()))
+	if got := cohortUsage(); got != 2_000 {
+		t.Errorf("After adding a workload to b, cohort uses %d, want 2000", got)
+	}
+}
+
+func TestSnapshotWorkloadsCopyOnWrite(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl)
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("default", "10").Obj()).
+			Obj()).
+		Obj()
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+	}
+	newWorkload := func(name string) *kueue.Workload {
+		return utiltesting.MakeWorkload(name, "").
+			Request(corev1.ResourceCPU, "1").
+			Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "default").Obj()).
+			Obj()
+	}
+	if !cqCache.AddOrUpdateWorkload(newWorkload("a")) {
+		t.Fatal("Couldn't add Workload to cache")
+	}
+
+	workloadKeys := func(cq *ClusterQueue) []string {
+		return sets.List(sets.KeySet(cq.Workloads))
+	}
+	snap1 := cqCache.Snapshot()
+	snap2 := cqCache.Snapshot()
+
+	// Modifying the cache doesn't affect the snapshots.
+	if !cqCache.AddOrUpdateWorkload(newWorkload("b")) {
+		t.Fatal("Couldn't add Workload to cache")
+	}
+	if diff := cmp.Diff([]string{"/a"}, workloadKeys(snap1.ClusterQueues["cq"])); diff != "" {
+		t.Errorf("Unexpected workloads in snapshot after modifying the cache (-want,+got):\n%s", diff)
+	}
+
+	// Modifying a snapshot doesn't affect the cache or other snapshots.
+	snap1.RemoveWorkload(snap1.ClusterQueues["cq"].Workloads["/a"])
+	snap1.AddWorkload(workload.NewInfo(newWorkload("c")))
+	if diff := cmp.Diff([]string{"/c"}, workloadKeys(snap1.ClusterQueues["cq"])); diff != "" {
+		t.Errorf("Unexpected workloads in modified snapshot (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"/a"}, workloadKeys(snap2.ClusterQueues["cq"])); diff != "" {
+		t.Errorf("Unexpected workloads in other snapshot (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"/a", "/b"}, workloadKeys(cqCache.clusterQueues["cq"])); diff != "" {
+		t.Errorf("Unexpected workloads in cache (-want,+got):\n%s", diff)
+	}
+}
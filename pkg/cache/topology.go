@@ -0,0 +1,261 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// FlavorTopology holds the domains of a Topology formed by the nodes that a
+// ResourceFlavor selects.
+type FlavorTopology struct {
+	// Levels are the node labels of the levels, from the highest to the
+	// lowest.
+	Levels []string
+	// Domains holds the domains of each level, keyed by the values of the node
+	// labels of the level and of the levels above it.
+	Domains []map[string]*TopologyDomain
+}
+
+// TopologyDomain is a group of nodes that have the same values for the node
+// labels of a topology level and of the levels above it.
+type TopologyDomain struct {
+	// Values are the values of the node labels, from the highest level to the
+	// level of the domain.
+	Values []string
+	Parent *TopologyDomain
+	// Capacity is the allocatable capacity of the nodes in the domain.
+	Capacity workload.Requests
+	// Usage is the usage of the pod sets admitted into the domain or into any
+	// of its descendants.
+	Usage workload.Requests
+}
+
+// Level returns the index of the level with the given node label, or -1 if
+// the topology doesn't have such level.
+func (t *FlavorTopology) Level(nodeLabel string) int {
+	for i, l := range t.Levels {
+		if l == nodeLabel {
+			return i
+		}
+	}
+	return -1
+}
+
+// Domain returns the domain identified by the given node labels, or nil if
+// there is no such domain.
+func (t *FlavorTopology) Domain(nodeLabels map[string]string) *TopologyDomain {
+	var values []string
+	for _, l := range t.Levels {
+		v, found := nodeLabels[l]
+		if !found {
+			break
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return t.Domains[len(values)-1][domainKey(values)]
+}
+
+// NodeLabels returns the node labels that identify the domain.
+func (d *TopologyDomain) NodeLabels(levels []string) map[string]string {
+	nodeLabels := make(map[string]string, len(d.Values))
+	for i, v := range d.Values {
+		nodeLabels[levels[i]] = v
+	}
+	return nodeLabels
+}
+
+// addUsage adds the requests to the usage of the domain and its ancestors.
+func (d *TopologyDomain) addUsage(requests workload.Requests) {
+	for ; d != nil; d = d.Parent {
+		for r, v := range requests {
+			d.Usage[r] += v
+		}
+	}
+}
+
+// domainKey returns the key of a domain. Label values can't contain commas.
+func domainKey(values []string) string {
+	return strings.Join(values, ",")
+}
+
+func newFlavorTopology(levels []string) *FlavorTopology {
+	t := &FlavorTopology{
+		Levels:  levels,
+		Domains: make([]map[string]*TopologyDomain, len(levels)),
+	}
+	for i := range t.Domains {
+		t.Domains[i] = make(map[string]*TopologyDomain)
+	}
+	return t
+}
+
+// addNode adds the capacity of the node to the domains that it belongs to.
+// Nodes that lack the label of any level are ignored.
+func (t *FlavorTopology) addNode(n *nodeInfo) {
+	values := make([]string, len(t.Levels))
+	for i, l := range t.Levels {
+		v, found := n.labels[l]
+		if !found {
+			return
+		}
+		values[i] = v
+	}
+	var parent *TopologyDomain
+	for i := range t.Levels {
+		key := domainKey(values[:i+1])
+		d := t.Domains[i][key]
+		if d == nil {
+			d = &TopologyDomain{
+				Values:   values[:i+1],
+				Parent:   parent,
+				Capacity: make(workload.Requests),
+				Usage:    make(workload.Requests),
+			}
+			t.Domains[i][key] = d
+		}
+		for r, v := range n.capacity {
+			d.Capacity[r] += v
+		}
+		parent = d
+	}
+}
+
+// clone returns a copy of the topology with empty usage. The capacity is
+// shared, as it's not modified.
+func (t *FlavorTopology) clone() *FlavorTopology {
+	c := newFlavorTopology(t.Levels)
+	for i, domains := range t.Domains {
+		for key, d := range domains {
+			dCopy := &TopologyDomain{
+				Values:   d.Values,
+				Capacity: d.Capacity,
+				Usage:    make(workload.Requests),
+			}
+			if d.Parent != nil {
+				dCopy.Parent = c.Domains[i-1][domainKey(d.Parent.Values)]
+			}
+			c.Domains[i][key] = dCopy
+		}
+	}
+	return c
+}
+
+// AddOrUpdateTopology updates the levels of the topology. It returns the names
+// of the ClusterQueues using flavors with the topology.
+func (c *Cache) AddOrUpdateTopology(t *kueue.Topology) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	levels := make([]string, len(t.Spec.Levels))
+	for i, l := range t.Spec.Levels {
+		levels[i] = l.NodeLabel
+	}
+	c.topologies[t.Name] = levels
+	c.flavorTopologies = nil
+	return c.clusterQueuesUsingTopology(t.Name)
+}
+
+// DeleteTopology removes the topology. It returns the names of the
+// ClusterQueues using flavors with the topology.
+func (c *Cache) DeleteTopology(t *kueue.Topology) sets.Set[string] {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.topologies, t.Name)
+	c.flavorTopologies = nil
+	return c.clusterQueuesUsingTopology(t.Name)
+}
+
+func (c *Cache) clusterQueuesUsingTopology(name string) sets.Set[string] {
+	cqs := sets.New[string]()
+	for _, rf := range c.resourceFlavors {
+		if rf.TopologyName == nil || *rf.TopologyName != name {
+			continue
+		}
+		for _, cq := range c.clusterQueues {
+			if cq.flavorInUse(rf.Name) {
+				cqs.Insert(cq.Name)
+			}
+		}
+	}
+	return cqs
+}
+
+// topologiesPerFlavor returns the domains of the nodes selected by each
+// flavor with an existing topology, without usage. It must be called with the
+// write lock held.
+func (c *Cache) topologiesPerFlavor() map[string]*FlavorTopology {
+	if c.flavorTopologies != nil {
+		return c.flavorTopologies
+	}
+	c.flavorTopologies = make(map[string]*FlavorTopology)
+	for _, rf := range c.resourceFlavors {
+		if rf.TopologyName == nil {
+			continue
+		}
+		levels, found := c.topologies[*rf.TopologyName]
+		if !found {
+			continue
+		}
+		t := newFlavorTopology(levels)
+		selector := labels.SelectorFromSet(rf.NodeSelector)
+		for _, n := range c.nodes {
+			if selector.Matches(n.labels) {
+				t.addNode(n)
+			}
+		}
+		c.flavorTopologies[rf.Name] = t
+	}
+	return c.flavorTopologies
+}
+
+// snapshotTopologies returns copies of the topologies of the flavors, with the
+// usage of the pod sets admitted into their domains by any ClusterQueue. It
+// must be called with the write lock held.
+func (c *Cache) snapshotTopologies() map[string]*FlavorTopology {
+	topologies := make(map[string]*FlavorTopology, len(c.topologiesPerFlavor()))
+	for name, t := range c.topologiesPerFlavor() {
+		topologies[name] = t.clone()
+	}
+	for _, cq := range c.clusterQueues {
+		for _, wl := range cq.Workloads {
+			for _, ps := range wl.TotalRequests {
+				if ps.TopologyDomain == nil {
+					continue
+				}
+				for r, v := range ps.Requests {
+					t := topologies[ps.Flavors[r]]
+					if t == nil {
+						continue
+					}
+					if d := t.Domain(ps.TopologyDomain); d != nil {
+						d.addUsage(workload.Requests{r: v})
+					}
+				}
+			}
+		}
+	}
+	return topologies
+}
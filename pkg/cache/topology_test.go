@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestSnapshotTopologies(t *testing.T) {
+	ctx := context.Background()
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := New(cl, WithTopologyTracking(true))
+	cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("tas").Label("pool", "tas").TopologyName("default").Obj())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("tas", "20").Obj()).
+			Obj()).
+		Obj()
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Couldn't add ClusterQueue to cache: %v", err)
+	}
+	node := func(name string, nodeLabels map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: nodeLabels,
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+		}
+	}
+	cqCache.AddOrUpdateNode(node("n1", map[string]string{"pool": "tas", "block": "b1", "host": "n1"}))
+	cqCache.AddOrUpdateNode(node("n2", map[string]string{"pool": "tas", "block": "b1", "host": "n2"}))
+	cqCache.AddOrUpdateNode(node("n3", map[string]string{"pool": "tas", "block": "b2", "host": "n3"}))
+	// Not in any domain.
+	cqCache.AddOrUpdateNode(node("n4", map[string]string{"pool": "tas", "host": "n4"}))
+	// Not selected by the flavor.
+	cqCache.AddOrUpdateNode(node("n5", map[string]string{"pool": "other", "block": "b1", "host": "n5"}))
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Request(corev1.ResourceCPU, "3").
+		Admit(utiltesting.MakeAdmission("cq").
+			Flavor(corev1.ResourceCPU, "tas").
+			TopologyDomain(map[string]string{"block": "b1", "host": "n1"}).
+			Obj()).
+		Obj()
+	cqCache.AddOrUpdateWorkload(wl)
+
+	if diff := cmp.Diff(sets.New[string]("cq"), cqCache.AddOrUpdateTopology(utiltesting.MakeTopology("default", "block", "host").Obj())); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after adding the topology (-want,+got):\n%s", diff)
+	}
+	snap := cqCache.Snapshot()
+	topology := snap.ClusterQueues["cq"].FlavorTopologies["tas"]
+	if topology == nil {
+		t.Fatalf("Topology of flavor tas not found in snapshot")
+	}
+	// Capacity and usage of CPU in each domain.
+	gotDomains := make(map[string][2]int64)
+	for _, domains := range topology.Domains {
+		for key, d := range domains {
+			gotDomains[key] = [2]int64{d.Capacity[corev1.ResourceCPU], d.Usage[corev1.ResourceCPU]}
+		}
+	}
+	wantDomains := map[string][2]int64{
+		"b1":    {8_000, 3_000},
+		"b1,n1": {4_000, 3_000},
+		"b1,n2": {4_000, 0},
+		"b2":    {4_000, 0},
+		"b2,n3": {4_000, 0},
+	}
+	if diff := cmp.Diff(wantDomains, gotDomains); diff != "" {
+		t.Errorf("Unexpected domains in snapshot (-want,+got):\n%s", diff)
+	}
+	if d := topology.Domain(map[string]string{"block": "b1", "host": "n2"}); d == nil || d.Parent != topology.Domains[0]["b1"] {
+		t.Errorf("Domain(b1, n2) = %v, want the domain of n2 under b1", d)
+	}
+
+	if diff := cmp.Diff(sets.New[string]("cq"), cqCache.DeleteTopology(utiltesting.MakeTopology("default").Obj())); diff != "" {
+		t.Errorf("Unexpected ClusterQueues after deleting the topology (-want,+got):\n%s", diff)
+	}
+	snap = cqCache.Snapshot()
+	if got := snap.ClusterQueues["cq"].FlavorTopologies; len(got) != 0 {
+		t.Errorf("Unexpected topologies in snapshot after deleting the topology: %v", got)
+	}
+}
@@ -32,9 +32,105 @@ const (
 	// status based on the admission status of the parent workload.
 	ParentWorkloadAnnotation = "kueue.x-k8s.io/parent-workload"
 
-	KueueName         = "kueue"
-	JobControllerName = KueueName + "-job-controller"
-	AdmissionName     = KueueName + "-admission"
+	// JobMinParallelismAnnotation is the annotation in a kubernetes Job that
+	// holds the minimum parallelism the Job can run with. When set, the Job
+	// can be partially admitted with a reduced parallelism if there is not
+	// enough quota to admit it with the full parallelism.
+	JobMinParallelismAnnotation = "kueue.x-k8s.io/job-min-parallelism"
+
+	// JobSliceSizeAnnotation is the annotation in a kubernetes Job that holds
+	// the maximum number of pods of each of the Workload slices that the Job is
+	// split into. The slices are admitted independently, and the Job runs with
+	// the pods of the admitted ones.
+	JobSliceSizeAnnotation = "kueue.x-k8s.io/job-slice-size"
+
+	// WorkloadSliceAnnotation is the annotation in a Workload that holds its
+	// index among the Workload slices of its job.
+	WorkloadSliceAnnotation = "kueue.x-k8s.io/workload-slice"
+
+	// PreemptorAnnotation is the annotation set on a preempted Workload that
+	// holds the namespace/name of the Workload that preempted it.
+	PreemptorAnnotation = "kueue.x-k8s.io/preempted-by"
+
+	// PreemptorUIDAnnotation is the annotation set on a preempted Workload that
+	// holds the UID of the Workload that preempted it.
+	PreemptorUIDAnnotation = "kueue.x-k8s.io/preempted-by-uid"
+
+	// WorkloadGroupAnnotation is the annotation in a Workload, or in the Job
+	// it is created for, that holds the name of the group of Workloads, in the
+	// same namespace, that it belongs to. The Workloads of a group are admitted
+	// together and, when the admission of one of them is cancelled, the
+	// admission of the others is cancelled too.
+	WorkloadGroupAnnotation = "kueue.x-k8s.io/workload-group"
+
+	// WorkloadGroupSizeAnnotation is the annotation in a Workload, or in the Job
+	// it is created for, that holds the number of Workloads in its group. A
+	// group is not admitted until all its Workloads are pending in the same
+	// ClusterQueue.
+	WorkloadGroupSizeAnnotation = "kueue.x-k8s.io/workload-group-size"
+
+	// PodSetRequiredTopologyAnnotation is the annotation in the pod template of
+	// a Job that holds the node label of the topology level whose domains the
+	// pods must be admitted into, all of them in the same domain.
+	PodSetRequiredTopologyAnnotation = "kueue.x-k8s.io/podset-required-topology"
+
+	// PodSetPreferredTopologyAnnotation is the annotation in the pod template of
+	// a Job that holds the node label of the topology level whose domains the
+	// pods should preferably be admitted into, all of them in the same domain.
+	// If no domain of the level fits the pods, the levels above are tried.
+	PodSetPreferredTopologyAnnotation = "kueue.x-k8s.io/podset-preferred-topology"
+
+	// PodSetRequiredFlavorsAnnotation is the annotation in the pod template of
+	// a Job that holds the comma-separated names of the only ResourceFlavors
+	// that the pods can be admitted into.
+	PodSetRequiredFlavorsAnnotation = "kueue.x-k8s.io/podset-required-flavors"
+
+	// PodSetPreferredFlavorsAnnotation is the annotation in the pod template of
+	// a Job that holds the comma-separated names of the ResourceFlavors that
+	// are tried first for the pods.
+	PodSetPreferredFlavorsAnnotation = "kueue.x-k8s.io/podset-preferred-flavors"
+
+	// WorkloadPriorityClassLabel is the label in a job that holds the name of
+	// the WorkloadPriorityClass of its Workload. It takes precedence over the
+	// PriorityClass of the pods.
+	WorkloadPriorityClassLabel = "kueue.x-k8s.io/priority-class"
+
+	// ExternalJobPodSetsAnnotation is the annotation in a job of an external
+	// framework that describes its pod sets, as a JSON list of objects with the
+	// name of the pod set, the dot-separated path of its pod template and,
+	// optionally, the path of its pod count. For example:
+	// [{"name": "main", "template": "spec.template", "count": "spec.replicas"}]
+	ExternalJobPodSetsAnnotation = "kueue.x-k8s.io/pod-sets"
+
+	// ExternalJobSuspendPathAnnotation is the annotation in a job of an
+	// external framework that holds the dot-separated path of the boolean field
+	// that suspends the job. Defaults to "spec.suspend".
+	ExternalJobSuspendPathAnnotation = "kueue.x-k8s.io/suspend-path"
+
+	// ImportedAnnotation is the annotation that the importer sets in the
+	// running jobs and pods that it adopts, once it created their admitted
+	// Workloads. It allows adding the queue name to a running job.
+	ImportedAnnotation = "kueue.x-k8s.io/imported"
+
+	// PodSetsHashAnnotation is the annotation in a Workload that holds the
+	// hash of the pod sets of its job when the Workload was created. The
+	// Workload of a suspended job is recreated if the pod templates of the job
+	// no longer have this hash.
+	PodSetsHashAnnotation = "kueue.x-k8s.io/pod-sets-hash"
+
+	// DefaultQueueAnnotation is the annotation in a namespace that holds the
+	// name of the LocalQueue that the job webhooks assign to the jobs created
+	// in the namespace without a queue name.
+	DefaultQueueAnnotation = "kueue.x-k8s.io/default-queue-name"
+
+	KueueName                    = "kueue"
+	JobControllerName            = KueueName + "-job-controller"
+	PodControllerName            = KueueName + "-pod-controller"
+	WorkloadControllerName       = KueueName + "-workload-controller"
+	FlavorCapacityControllerName = KueueName + "-flavor-capacity-controller"
+	ReclaimControllerName        = KueueName + "-reclaim-controller"
+	UnschedulableControllerName  = KueueName + "-unschedulable-controller"
+	AdmissionName                = KueueName + "-admission"
 
 	// UpdatesBatchPeriod is the batch period to hold workload updates
 	// before syncing a Queue and ClusterQueue objects.
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admissionchecks holds the building blocks of the controllers of
+// AdmissionChecks, which can be external to Kueue.
+//
+// A controller of AdmissionChecks:
+//   - sets the Active condition of the AdmissionChecks with its controllerName,
+//     for example with an ActiveReconciler. ClusterQueues don't admit new
+//     workloads while any of their AdmissionChecks is not active.
+//   - sets the state of its checks in the workloads that reserved quota, with
+//     workload.SetAdmissionCheckState. Kueue sets the Admitted condition of a
+//     workload once all its checks are Ready, releases the quota and requeues
+//     the workload if any check is Retry, and finishes the workload if any
+//     check is Rejected.
+package admissionchecks
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/util/api"
+)
+
+// ParametersValidator validates the parameters of an AdmissionCheck for its
+// controller.
+type ParametersValidator func(*kueue.AdmissionCheck) error
+
+// ActiveReconciler sets the Active condition of the AdmissionChecks of a
+// controller. An AdmissionCheck is active if its parameters are valid.
+type ActiveReconciler struct {
+	client         client.Client
+	controllerName string
+	validate       ParametersValidator
+}
+
+// NewActiveReconciler creates a reconciler for the AdmissionChecks with the
+// given controllerName. A nil validator accepts any parameters.
+func NewActiveReconciler(client client.Client, controllerName string, validate ParametersValidator) *ActiveReconciler {
+	return &ActiveReconciler{
+		client:         client,
+		controllerName: controllerName,
+		validate:       validate,
+	}
+}
+
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=admissionchecks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=admissionchecks/status,verbs=get;update;patch
+
+func (r *ActiveReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var ac kueue.AdmissionCheck
+	if err := r.client.Get(ctx, req.NamespacedName, &ac); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if ac.Spec.ControllerName != r.controllerName {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("admissionCheck", klog.KObj(&ac))
+	log.V(2).Info("Reconciling AdmissionCheck")
+
+	cond := ActiveCondition(&ac, r.validate)
+	if apimeta.IsStatusConditionPresentAndEqual(ac.Status.Conditions, cond.Type, cond.Status) {
+		return ctrl.Result{}, nil
+	}
+	log.V(2).Info("Updating the Active condition", "status", cond.Status)
+	apimeta.SetStatusCondition(&ac.Status.Conditions, cond)
+	return ctrl.Result{}, client.IgnoreNotFound(r.client.Status().Update(ctx, &ac))
+}
+
+// SetupWithManager sets up the reconciler with the Manager, with the given
+// name, which must be unique among the controllers of the Manager.
+func (r *ActiveReconciler) SetupWithManager(mgr ctrl.Manager, name string) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&kueue.AdmissionCheck{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			ac, ok := o.(*kueue.AdmissionCheck)
+			return ok && ac.Spec.ControllerName == r.controllerName
+		}))).
+		Complete(r)
+}
+
+// ActiveCondition returns the Active condition of an AdmissionCheck, based on
+// the validation of its parameters.
+func ActiveCondition(ac *kueue.AdmissionCheck, validate ParametersValidator) metav1.Condition {
+	if validate != nil {
+		if err := validate(ac); err != nil {
+			return metav1.Condition{
+				Type:    kueue.AdmissionCheckActive,
+				Status:  metav1.ConditionFalse,
+				Reason:  "InvalidParameters",
+				Message: api.TruncateConditionMessage(err.Error()),
+			}
+		}
+	}
+	return metav1.Condition{
+		Type:    kueue.AdmissionCheckActive,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Active",
+		Message: "The admission check is active",
+	}
+}
+
+// ChecksForController returns the AdmissionChecks of the admission of the
+// workload that have the given controllerName. The AdmissionChecks that don't
+// exist are skipped.
+func ChecksForController(ctx context.Context, c client.Client, wl *kueue.Workload, controllerName string) ([]*kueue.AdmissionCheck, error) {
+	if wl.Spec.Admission == nil {
+		return nil, nil
+	}
+	var checks []*kueue.AdmissionCheck
+	for _, name := range wl.Spec.Admission.AdmissionChecks {
+		var ac kueue.AdmissionCheck
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, &ac); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if ac.Spec.ControllerName == controllerName {
+			checks = append(checks, &ac)
+		}
+	}
+	return checks, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionchecks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+const testControllerName = "example.com/budget"
+
+func TestActiveReconciler(t *testing.T) {
+	requireParameters := func(ac *kueue.AdmissionCheck) error {
+		if ac.Spec.ProvisioningRequest == nil {
+			return errors.New("missing parameters")
+		}
+		return nil
+	}
+	cases := map[string]struct {
+		admissionCheck *kueue.AdmissionCheck
+		wantConditions []metav1.Condition
+	}{
+		"valid parameters": {
+			admissionCheck: utiltesting.MakeAdmissionCheck("check", testControllerName).
+				ProvisioningRequest("class", nil).Obj(),
+			wantConditions: []metav1.Condition{{
+				Type:    kueue.AdmissionCheckActive,
+				Status:  metav1.ConditionTrue,
+				Reason:  "Active",
+				Message: "The admission check is active",
+			}},
+		},
+		"invalid parameters": {
+			admissionCheck: utiltesting.MakeAdmissionCheck("check", testControllerName).Obj(),
+			wantConditions: []metav1.Condition{{
+				Type:    kueue.AdmissionCheckActive,
+				Status:  metav1.ConditionFalse,
+				Reason:  "InvalidParameters",
+				Message: "missing parameters",
+			}},
+		},
+		"another controller": {
+			admissionCheck: utiltesting.MakeAdmissionCheck("check", "example.com/other").Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.admissionCheck).Build()
+			r := NewActiveReconciler(cl, testControllerName, requireParameters)
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: tc.admissionCheck.Name}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			var got kueue.AdmissionCheck
+			if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Failed getting the AdmissionCheck: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantConditions, got.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChecksForController(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		utiltesting.MakeAdmissionCheck("budget", testControllerName).Obj(),
+		utiltesting.MakeAdmissionCheck("provisioning", "kueue.x-k8s.io/provisioning-request").Obj(),
+	).Build()
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("provisioning", "budget", "missing").Obj()).
+		Obj()
+
+	checks, err := ChecksForController(context.Background(), cl, wl, testControllerName)
+	if err != nil {
+		t.Fatalf("ChecksForController failed: %v", err)
+	}
+	var names []string
+	for _, ac := range checks {
+		names = append(names, ac.Name)
+	}
+	if diff := cmp.Diff([]string{"budget"}, names); diff != "" {
+		t.Errorf("Unexpected checks (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/controller/admissionchecks"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+const (
+	// ControllerName is the controllerName of the AdmissionChecks handled by
+	// this controller.
+	ControllerName = "kueue.x-k8s.io/provisioning-request"
+
+	// ConsumesAnnotation makes cluster-autoscaler place the pods in the
+	// capacity provisioned for the ProvisioningRequest named by its value.
+	ConsumesAnnotation = "cluster-autoscaler.kubernetes.io/consume-provisioning-request"
+
+	// ClassNameAnnotation holds the provisioningClassName of the
+	// ProvisioningRequest that the pods consume.
+	ClassNameAnnotation = "cluster-autoscaler.kubernetes.io/provisioning-class-name"
+
+	// WorkloadUIDLabel is the label of the ProvisioningRequests and
+	// PodTemplates that holds the UID of the workload they were created for.
+	WorkloadUIDLabel = "kueue.x-k8s.io/workload-uid"
+
+	conditionProvisioned = "Provisioned"
+	conditionFailed      = "Failed"
+)
+
+// GroupVersionKind is the kind of the cluster-autoscaler ProvisioningRequests.
+var GroupVersionKind = schema.GroupVersionKind{
+	Group:   "autoscaling.x-k8s.io",
+	Version: "v1beta1",
+	Kind:    "ProvisioningRequest",
+}
+
+// Controller sets the state of the AdmissionChecks of the
+// kueue.x-k8s.io/provisioning-request controller in the workloads that
+// reserved quota, based on the ProvisioningRequests that it creates for them.
+type Controller struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func NewController(client client.Client, scheme *runtime.Scheme) *Controller {
+	return &Controller{
+		client: client,
+		scheme: scheme,
+	}
+}
+
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=admissionchecks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=podtemplates,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=autoscaling.x-k8s.io,resources=provisioningrequests,verbs=get;list;watch;create;delete
+
+func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var wl kueue.Workload
+	if err := c.client.Get(ctx, req.NamespacedName, &wl); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("workload", klog.KObj(&wl))
+	ctx = ctrl.LoggerInto(ctx, log)
+	log.V(2).Info("Reconciling Workload")
+
+	if wl.Spec.Admission == nil || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+		// The provisioned capacity is no longer needed.
+		return ctrl.Result{}, c.deleteOwnedObjects(ctx, &wl)
+	}
+
+	checks, err := admissionchecks.ChecksForController(ctx, c.client, &wl, ControllerName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	changed := false
+	for _, check := range checks {
+		state := workload.FindAdmissionCheck(wl.Status.AdmissionChecks, check.Name)
+		if state == nil || state.State != kueue.CheckStatePending {
+			// The workload controller adds the missing states as Pending.
+			continue
+		}
+		pr, err := c.syncProvisioningRequest(ctx, &wl, check, state)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if pr == nil {
+			continue
+		}
+		newState := checkStateFor(pr, check, &wl)
+		if newState.State != state.State || newState.Message != state.Message {
+			log.V(2).Info("Updating the state of the admission check", "admissionCheck", check.Name, "state", newState.State)
+			workload.SetAdmissionCheckState(&wl.Status.AdmissionChecks, newState)
+			changed = true
+		}
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, client.IgnoreNotFound(c.client.Status().Update(ctx, &wl))
+}
+
+// syncProvisioningRequest creates the ProvisioningRequest for the check, and
+// the PodTemplates that it references, if they don't exist. A failed
+// ProvisioningRequest created before the check was last reset to Pending is
+// deleted, so that it's created again. It returns the existing
+// ProvisioningRequest, or nil if it was just created or deleted.
+func (c *Controller) syncProvisioningRequest(ctx context.Context, wl *kueue.Workload, check *kueue.AdmissionCheck, state *kueue.AdmissionCheckState) (*unstructured.Unstructured, error) {
+	log := ctrl.LoggerFrom(ctx)
+	name := ProvisioningRequestName(wl, check.Name)
+	pr := &unstructured.Unstructured{}
+	pr.SetGroupVersionKind(GroupVersionKind)
+	err := c.client.Get(ctx, types.NamespacedName{Namespace: wl.Namespace, Name: name}, pr)
+	if err == nil {
+		created := pr.GetCreationTimestamp()
+		if isConditionTrue(pr, conditionFailed) && created.Before(&state.LastTransitionTime) {
+			log.V(2).Info("Deleting the failed ProvisioningRequest of a previous admission", "provisioningRequest", name)
+			return nil, client.IgnoreNotFound(c.client.Delete(ctx, pr))
+		}
+		return pr, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	podSets, err := c.createPodTemplates(ctx, wl, name)
+	if err != nil {
+		return nil, err
+	}
+	pr = &unstructured.Unstructured{}
+	pr.SetGroupVersionKind(GroupVersionKind)
+	pr.SetNamespace(wl.Namespace)
+	pr.SetName(name)
+	pr.SetLabels(map[string]string{WorkloadUIDLabel: string(wl.UID)})
+	if err := ctrl.SetControllerReference(wl, pr, c.scheme); err != nil {
+		return nil, err
+	}
+	var className string
+	var parameters map[string]string
+	if p := check.Spec.ProvisioningRequest; p != nil {
+		className = p.ProvisioningClassName
+		parameters = p.Parameters
+	}
+	if err := unstructured.SetNestedField(pr.Object, className, "spec", "provisioningClassName"); err != nil {
+		return nil, err
+	}
+	if len(parameters) > 0 {
+		if err := unstructured.SetNestedStringMap(pr.Object, parameters, "spec", "parameters"); err != nil {
+			return nil, err
+		}
+	}
+	if err := unstructured.SetNestedSlice(pr.Object, podSets, "spec", "podSets"); err != nil {
+		return nil, err
+	}
+	log.V(2).Info("Creating ProvisioningRequest", "provisioningRequest", name)
+	return nil, client.IgnoreAlreadyExists(c.client.Create(ctx, pr))
+}
+
+// createPodTemplates creates a PodTemplate for each podSet of the workload,
+// with the nodeSelector of the assigned flavors, and returns the podSets of
+// the ProvisioningRequest referencing them.
+func (c *Controller) createPodTemplates(ctx context.Context, wl *kueue.Workload, prName string) ([]interface{}, error) {
+	podSets := make([]interface{}, 0, len(wl.Spec.PodSets))
+	for i := range wl.Spec.PodSets {
+		ps := &wl.Spec.PodSets[i]
+		psFlavors := workload.FindPodSetFlavors(wl.Spec.Admission, ps.Name)
+		template := &corev1.PodTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: wl.Namespace,
+				Name:      fmt.Sprintf("%s-%s", prName, ps.Name),
+				Labels:    map[string]string{WorkloadUIDLabel: string(wl.UID)},
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: *ps.Spec.DeepCopy(),
+			},
+		}
+		if psFlavors != nil {
+			nodeSelector, err := c.flavorsNodeSelector(ctx, psFlavors.Flavors)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range psFlavors.TopologyDomain {
+				nodeSelector[k] = v
+			}
+			if len(nodeSelector) > 0 && template.Template.Spec.NodeSelector == nil {
+				template.Template.Spec.NodeSelector = make(map[string]string, len(nodeSelector))
+			}
+			for k, v := range nodeSelector {
+				template.Template.Spec.NodeSelector[k] = v
+			}
+		}
+		if err := ctrl.SetControllerReference(wl, template, c.scheme); err != nil {
+			return nil, err
+		}
+		if err := c.client.Create(ctx, template); client.IgnoreAlreadyExists(err) != nil {
+			return nil, err
+		}
+		podSets = append(podSets, map[string]interface{}{
+			"podTemplateRef": map[string]interface{}{
+				"name": template.Name,
+			},
+			"count": int64(workload.AdmittedCount(ps, psFlavors)),
+		})
+	}
+	return podSets, nil
+}
+
+func (c *Controller) flavorsNodeSelector(ctx context.Context, flavors map[corev1.ResourceName]string) (map[string]string, error) {
+	nodeSelector := make(map[string]string)
+	for _, name := range flavors {
+		var rf kueue.ResourceFlavor
+		if err := c.client.Get(ctx, types.NamespacedName{Name: name}, &rf); err != nil {
+			return nil, err
+		}
+		for k, v := range rf.NodeSelector {
+			nodeSelector[k] = v
+		}
+	}
+	return nodeSelector, nil
+}
+
+// deleteOwnedObjects deletes the ProvisioningRequests and PodTemplates
+// created for the workload.
+func (c *Controller) deleteOwnedObjects(ctx context.Context, wl *kueue.Workload) error {
+	selector := client.MatchingLabels{WorkloadUIDLabel: string(wl.UID)}
+	prs := &unstructured.UnstructuredList{}
+	prs.SetGroupVersionKind(GroupVersionKind.GroupVersion().WithKind(GroupVersionKind.Kind + "List"))
+	if err := c.client.List(ctx, prs, client.InNamespace(wl.Namespace), selector); err != nil {
+		return err
+	}
+	for i := range prs.Items {
+		if err := c.client.Delete(ctx, &prs.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	var templates corev1.PodTemplateList
+	if err := c.client.List(ctx, &templates, client.InNamespace(wl.Namespace), selector); err != nil {
+		return err
+	}
+	for i := range templates.Items {
+		if err := c.client.Delete(ctx, &templates.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProvisioningRequestName returns the name of the ProvisioningRequest created
+// for the admission check of the workload.
+func ProvisioningRequestName(wl *kueue.Workload, checkName string) string {
+	return fmt.Sprintf("%s-%s", wl.Name, checkName)
+}
+
+// checkStateFor returns the state of the admission check based on the
+// conditions of its ProvisioningRequest.
+func checkStateFor(pr *unstructured.Unstructured, check *kueue.AdmissionCheck, wl *kueue.Workload) kueue.AdmissionCheckState {
+	state := kueue.AdmissionCheckState{
+		Name:    check.Name,
+		State:   kueue.CheckStatePending,
+		Message: fmt.Sprintf("Waiting for the ProvisioningRequest %s to be provisioned", pr.GetName()),
+	}
+	switch {
+	case isConditionTrue(pr, conditionFailed):
+		state.State = kueue.CheckStateRetry
+		state.Message = fmt.Sprintf("ProvisioningRequest %s failed: %s", pr.GetName(), conditionMessage(pr, conditionFailed))
+	case isConditionTrue(pr, conditionProvisioned):
+		state.State = kueue.CheckStateReady
+		state.Message = fmt.Sprintf("ProvisioningRequest %s is provisioned", pr.GetName())
+		annotations := map[string]string{ConsumesAnnotation: pr.GetName()}
+		if className, _, _ := unstructured.NestedString(pr.Object, "spec", "provisioningClassName"); className != "" {
+			annotations[ClassNameAnnotation] = className
+		}
+		for _, ps := range wl.Spec.PodSets {
+			state.PodSetUpdates = append(state.PodSetUpdates, kueue.PodSetUpdate{
+				Name:        ps.Name,
+				Annotations: annotations,
+			})
+		}
+	}
+	return state
+}
+
+func isConditionTrue(pr *unstructured.Unstructured, conditionType string) bool {
+	cond := findCondition(pr, conditionType)
+	return cond != nil && cond["status"] == string(metav1.ConditionTrue)
+}
+
+func conditionMessage(pr *unstructured.Unstructured, conditionType string) string {
+	if cond := findCondition(pr, conditionType); cond != nil {
+		msg, _ := cond["message"].(string)
+		return msg
+	}
+	return ""
+}
+
+func findCondition(pr *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(pr.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == conditionType {
+			return cond
+		}
+	}
+	return nil
+}
+
+// ValidateParameters checks that the AdmissionCheck has the parameters of the
+// ProvisioningRequests.
+func ValidateParameters(ac *kueue.AdmissionCheck) error {
+	if ac.Spec.ProvisioningRequest == nil || ac.Spec.ProvisioningRequest.ProvisioningClassName == "" {
+		return errors.New("spec.provisioningRequest.provisioningClassName is required")
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager, along with the
+// reconciler of the Active condition of its AdmissionChecks.
+func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	if err := admissionchecks.NewActiveReconciler(c.client, ControllerName, ValidateParameters).
+		SetupWithManager(mgr, "provisioning-request-admissioncheck"); err != nil {
+		return err
+	}
+	pr := &unstructured.Unstructured{}
+	pr.SetGroupVersionKind(GroupVersionKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("provisioning-request").
+		For(&kueue.Workload{}).
+		Owns(pr).
+		Complete(c)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestCheckStateFor(t *testing.T) {
+	check := utiltesting.MakeAdmissionCheck("provisioning", ControllerName).Obj()
+	wl := utiltesting.MakeWorkload("wl", "ns").Obj()
+
+	cases := map[string]struct {
+		conditions []interface{}
+		wantState  kueue.AdmissionCheckState
+	}{
+		"not provisioned yet": {
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Accepted", "status": "True"},
+			},
+			wantState: kueue.AdmissionCheckState{
+				Name:    "provisioning",
+				State:   kueue.CheckStatePending,
+				Message: "Waiting for the ProvisioningRequest wl-provisioning to be provisioned",
+			},
+		},
+		"provisioned": {
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Provisioned", "status": "True"},
+			},
+			wantState: kueue.AdmissionCheckState{
+				Name:    "provisioning",
+				State:   kueue.CheckStateReady,
+				Message: "ProvisioningRequest wl-provisioning is provisioned",
+				PodSetUpdates: []kueue.PodSetUpdate{{
+					Name: kueue.DefaultPodSetName,
+					Annotations: map[string]string{
+						ConsumesAnnotation:  "wl-provisioning",
+						ClassNameAnnotation: "queued-provisioning",
+					},
+				}},
+			},
+		},
+		"failed": {
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Failed", "status": "True", "message": "out of capacity"},
+			},
+			wantState: kueue.AdmissionCheckState{
+				Name:    "provisioning",
+				State:   kueue.CheckStateRetry,
+				Message: "ProvisioningRequest wl-provisioning failed: out of capacity",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pr := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec":   map[string]interface{}{"provisioningClassName": "queued-provisioning"},
+				"status": map[string]interface{}{"conditions": tc.conditions},
+			}}
+			pr.SetGroupVersionKind(GroupVersionKind)
+			pr.SetName(ProvisioningRequestName(wl, check.Name))
+			got := checkStateFor(pr, check, wl)
+			if diff := cmp.Diff(tc.wantState, got); diff != "" {
+				t.Errorf("Unexpected state (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
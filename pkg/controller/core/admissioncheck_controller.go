@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+)
+
+type AdmissionCheckUpdateWatcher interface {
+	NotifyAdmissionCheckUpdate(*kueue.AdmissionCheck)
+}
+
+// AdmissionCheckReconciler tracks the AdmissionCheck objects, so that the
+// ClusterQueues using missing AdmissionChecks are inactive.
+type AdmissionCheckReconciler struct {
+	log      logr.Logger
+	qManager *queue.Manager
+	cache    *cache.Cache
+	client   client.Client
+	watchers []AdmissionCheckUpdateWatcher
+}
+
+func NewAdmissionCheckReconciler(
+	client client.Client,
+	qMgr *queue.Manager,
+	cache *cache.Cache,
+) *AdmissionCheckReconciler {
+	return &AdmissionCheckReconciler{
+		log:      ctrl.Log.WithName("admissioncheck-reconciler"),
+		cache:    cache,
+		client:   client,
+		qManager: qMgr,
+	}
+}
+
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=admissionchecks,verbs=get;list;watch
+
+// Reconcile is a no-op, as the events for AdmissionCheck objects are fully
+// handled by the event filters, which update the cache and the queues.
+func (r *AdmissionCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ctrl.Result{}, nil
+}
+
+func (r *AdmissionCheckReconciler) AddUpdateWatcher(watchers ...AdmissionCheckUpdateWatcher) {
+	r.watchers = watchers
+}
+
+func (r *AdmissionCheckReconciler) notifyWatchers(ac *kueue.AdmissionCheck) {
+	for _, w := range r.watchers {
+		w.NotifyAdmissionCheckUpdate(ac)
+	}
+}
+
+func (r *AdmissionCheckReconciler) Create(e event.CreateEvent) bool {
+	ac, match := e.Object.(*kueue.AdmissionCheck)
+	if !match {
+		return false
+	}
+	defer r.notifyWatchers(ac)
+	log := r.log.WithValues("admissionCheck", klog.KObj(ac))
+	log.V(2).Info("AdmissionCheck create event")
+	if cqNames := r.cache.AddOrUpdateAdmissionCheck(ac.DeepCopy()); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+		// The ClusterQueues that became active should be evaluated by the
+		// scheduler, even if their workloads are not inadmissible.
+		r.qManager.Broadcast()
+	}
+	return false
+}
+
+func (r *AdmissionCheckReconciler) Delete(e event.DeleteEvent) bool {
+	ac, match := e.Object.(*kueue.AdmissionCheck)
+	if !match {
+		return false
+	}
+	defer r.notifyWatchers(ac)
+	log := r.log.WithValues("admissionCheck", klog.KObj(ac))
+	log.V(2).Info("AdmissionCheck delete event")
+	r.cache.DeleteAdmissionCheck(ac)
+	return false
+}
+
+func (r *AdmissionCheckReconciler) Update(e event.UpdateEvent) bool {
+	ac, match := e.ObjectNew.(*kueue.AdmissionCheck)
+	if !match {
+		return false
+	}
+	defer r.notifyWatchers(ac)
+	log := r.log.WithValues("admissionCheck", klog.KObj(ac))
+	log.V(2).Info("AdmissionCheck update event")
+	if cqNames := r.cache.AddOrUpdateAdmissionCheck(ac.DeepCopy()); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+		r.qManager.Broadcast()
+	}
+	return false
+}
+
+func (r *AdmissionCheckReconciler) Generic(e event.GenericEvent) bool {
+	r.log.V(3).Info("Ignore generic event", "obj", klog.KObj(e.Object), "kind", e.Object.GetObjectKind().GroupVersionKind())
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AdmissionCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kueue.AdmissionCheck{}).
+		WithEventFilter(r).
+		Complete(r)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/metrics"
+)
+
+// CacheVerifier periodically compares the workloads held by the cache with
+// the admitted workloads in the informer, to repair the drift caused by
+// missed events, for example, after the informer relists.
+// An inconsistency is only repaired when it's found again in the next
+// verification, as the events that fix it might not have been handled yet.
+type CacheVerifier struct {
+	log      logr.Logger
+	client   client.Client
+	cache    *cache.Cache
+	interval time.Duration
+	// suspected are the inconsistencies found in the last verification.
+	suspected sets.Set[cache.Inconsistency]
+}
+
+var _ manager.LeaderElectionRunnable = &CacheVerifier{}
+
+func NewCacheVerifier(client client.Client, cache *cache.Cache, interval time.Duration) *CacheVerifier {
+	return &CacheVerifier{
+		log:      ctrl.Log.WithName("cache-verifier"),
+		client:   client,
+		cache:    cache,
+		interval: interval,
+	}
+}
+
+func (v *CacheVerifier) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(v)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The
+// controllers that fill the cache only run in the leader, so only the leader
+// verifies it.
+func (v *CacheVerifier) NeedLeaderElection() bool {
+	return true
+}
+
+// Start verifies the cache at every interval until the context is done.
+func (v *CacheVerifier) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, v.verify, v.interval)
+	return nil
+}
+
+func (v *CacheVerifier) verify(ctx context.Context) {
+	var list kueue.WorkloadList
+	if err := v.client.List(ctx, &list); err != nil {
+		v.log.Error(err, "Listing the workloads to verify the cache")
+		return
+	}
+	workloads := make([]*kueue.Workload, 0, len(list.Items))
+	for i := range list.Items {
+		wl := &list.Items[i]
+		if wl.Spec.Admission == nil || workloadStatus(wl) == finished {
+			continue
+		}
+		// Adjust the requests as the workload controller does before adding
+		// the workloads to the cache.
+		handlePodOverhead(v.log, wl, v.client)
+		handlePodLimitRange(v.log, wl, v.client)
+		handleLimitsToRequests(wl)
+		workloads = append(workloads, wl)
+	}
+
+	suspected := sets.New[cache.Inconsistency]()
+	inconsistencies := v.cache.Verify(workloads, func(i cache.Inconsistency) bool {
+		if v.suspected.Has(i) {
+			return true
+		}
+		suspected.Insert(i)
+		return false
+	})
+	for _, i := range inconsistencies {
+		log := v.log.WithValues("kind", i.Kind, "clusterQueue", i.ClusterQueue)
+		if i.Workload != "" {
+			log = log.WithValues("workload", i.Workload, "resourceVersion", i.ResourceVersion)
+		}
+		if v.suspected.Has(i) {
+			log.Info("Repaired an inconsistency of the cache")
+			metrics.CacheInconsistencyRepaired(i.ClusterQueue, string(i.Kind))
+		} else {
+			log.V(2).Info("Found an inconsistency of the cache, repairing it if found in the next verification")
+		}
+	}
+	v.suspected = suspected
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestCacheVerifier(t *testing.T) {
+	ctx := context.Background()
+	scheme := utiltesting.MustGetScheme(t)
+	admission := utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "default").Obj()
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		utiltesting.MakeWorkload("admitted", "ns").Request(corev1.ResourceCPU, "1").Admit(admission).Obj(),
+		utiltesting.MakeWorkload("finished", "ns").Request(corev1.ResourceCPU, "1").Admit(admission).
+			Condition(metav1.Condition{Type: kueue.WorkloadFinished, Status: metav1.ConditionTrue}).Obj(),
+		utiltesting.MakeWorkload("pending", "ns").Request(corev1.ResourceCPU, "1").Obj(),
+	).Build()
+
+	// The cache missed the events of the workloads in the cluster.
+	cqCache := cache.New(fake.NewClientBuilder().WithScheme(scheme).Build())
+	cq := utiltesting.MakeClusterQueue("cq").
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("default", "10").Obj()).
+			Obj()).
+		Obj()
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	cqCache.AddOrUpdateWorkload(utiltesting.MakeWorkload("deleted", "ns").Request(corev1.ResourceCPU, "1").Admit(admission).Obj())
+
+	verifier := NewCacheVerifier(cl, cqCache, time.Minute)
+	workloadNames := func() []string {
+		var names []string
+		for _, wl := range cqCache.ClusterQueueWorkloads("cq") {
+			names = append(names, wl.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	verifier.verify(ctx)
+	if diff := cmp.Diff([]string{"deleted"}, workloadNames()); diff != "" {
+		t.Errorf("Unexpected workloads after the first verification (-want,+got):\n%s", diff)
+	}
+	verifier.verify(ctx)
+	if diff := cmp.Diff([]string{"admitted"}, workloadNames()); diff != "" {
+		t.Errorf("Unexpected workloads after the second verification (-want,+got):\n%s", diff)
+	}
+	if len(verifier.suspected) != 0 {
+		t.Errorf("Unexpected inconsistencies after the repair: %v", verifier.suspected)
+	}
+}
@@ -18,6 +18,10 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -39,12 +43,20 @@ import (
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/queue"
+	"sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
 )
 
 type ClusterQueueUpdateWatcher interface {
 	NotifyClusterQueueUpdate(*kueue.ClusterQueue, *kueue.ClusterQueue)
 }
 
+// QuotaScheduleWatcher is notified when the quotas of ClusterQueues change
+// because a window of their quota schedules starts or ends.
+type QuotaScheduleWatcher interface {
+	NotifyQuotaScheduleUpdate(clusterQueues sets.Set[string])
+}
+
 // ClusterQueueReconciler reconciles a ClusterQueue object
 type ClusterQueueReconciler struct {
 	client     client.Client
@@ -53,23 +65,67 @@ type ClusterQueueReconciler struct {
 	cache      *cache.Cache
 	wlUpdateCh chan event.GenericEvent
 	rfUpdateCh chan event.GenericEvent
+	acUpdateCh chan event.GenericEvent
+	cohortCh   chan event.GenericEvent
 	watchers   []ClusterQueueUpdateWatcher
+
+	quotaScheduleWatchers []QuotaScheduleWatcher
+
+	queueVisibilityMaxCount       int32
+	queueVisibilityUpdateInterval time.Duration
+}
+
+type clusterQueueReconcilerOptions struct {
+	watchers                      []ClusterQueueUpdateWatcher
+	queueVisibilityMaxCount       int32
+	queueVisibilityUpdateInterval time.Duration
 }
 
+// ClusterQueueReconcilerOption configures the reconciler.
+type ClusterQueueReconcilerOption func(*clusterQueueReconcilerOptions)
+
+// WithWatchers sets the watchers that are notified of the updates of the
+// ClusterQueues.
+func WithWatchers(watchers ...ClusterQueueUpdateWatcher) ClusterQueueReconcilerOption {
+	return func(o *clusterQueueReconcilerOptions) {
+		o.watchers = watchers
+	}
+}
+
+// WithQueueVisibility makes the reconciler expose up to maxCount pending
+// workloads in the status of the ClusterQueues, updating them at most once
+// per updateInterval. A maxCount of 0 disables it.
+func WithQueueVisibility(maxCount int32, updateInterval time.Duration) ClusterQueueReconcilerOption {
+	return func(o *clusterQueueReconcilerOptions) {
+		o.queueVisibilityMaxCount = maxCount
+		o.queueVisibilityUpdateInterval = updateInterval
+	}
+}
+
+var defaultCQOptions = clusterQueueReconcilerOptions{}
+
 func NewClusterQueueReconciler(
 	client client.Client,
 	qMgr *queue.Manager,
 	cache *cache.Cache,
-	watchers ...ClusterQueueUpdateWatcher,
+	opts ...ClusterQueueReconcilerOption,
 ) *ClusterQueueReconciler {
+	options := defaultCQOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	return &ClusterQueueReconciler{
-		client:     client,
-		log:        ctrl.Log.WithName("cluster-queue-reconciler"),
-		qManager:   qMgr,
-		cache:      cache,
-		wlUpdateCh: make(chan event.GenericEvent, updateChBuffer),
-		rfUpdateCh: make(chan event.GenericEvent, updateChBuffer),
-		watchers:   watchers,
+		client:                        client,
+		log:                           ctrl.Log.WithName("cluster-queue-reconciler"),
+		qManager:                      qMgr,
+		cache:                         cache,
+		wlUpdateCh:                    make(chan event.GenericEvent, updateChBuffer),
+		rfUpdateCh:                    make(chan event.GenericEvent, updateChBuffer),
+		acUpdateCh:                    make(chan event.GenericEvent, updateChBuffer),
+		cohortCh:                      make(chan event.GenericEvent, updateChBuffer),
+		watchers:                      options.watchers,
+		queueVisibilityMaxCount:       options.queueVisibilityMaxCount,
+		queueVisibilityUpdateInterval: options.queueVisibilityUpdateInterval,
 	}
 }
 
@@ -111,6 +167,14 @@ func (r *ClusterQueueReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				if err := r.client.Update(ctx, &cqObj); err != nil {
 					return ctrl.Result{}, client.IgnoreNotFound(err)
 				}
+				return ctrl.Result{}, nil
+			}
+			workloads := r.cache.ClusterQueueWorkloads(cqObj.Name)
+			log.V(3).Info("clusterQueue is still used by admitted workloads", "workloads", len(workloads))
+			if setDeletionBlockedCondition(&cqObj.Status.Conditions, kueue.ClusterQueueDeletionBlocked, "WorkloadsAdmitted", "admitted workloads", workloadKeys(workloads)) {
+				if err := r.client.Status().Update(ctx, &cqObj); err != nil {
+					return ctrl.Result{}, client.IgnoreNotFound(err)
+				}
 			}
 			return ctrl.Result{}, nil
 		}
@@ -122,19 +186,47 @@ func (r *ClusterQueueReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		if err := r.updateCqStatusIfChanged(ctx, newCQObj, metav1.ConditionTrue, "Ready", msg); err != nil {
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
-	} else if r.cache.ClusterQueueTerminating(newCQObj.Name) {
-		msg := "Can't admit new workloads; clusterQueue is terminating"
-		if err := r.updateCqStatusIfChanged(ctx, newCQObj, metav1.ConditionFalse, "Terminating", msg); err != nil {
-			return ctrl.Result{}, client.IgnoreNotFound(err)
-		}
 	} else {
-		msg := "Can't admit new workloads; some flavors are not found"
-		if err := r.updateCqStatusIfChanged(ctx, newCQObj, metav1.ConditionFalse, "FlavorNotFound", msg); err != nil {
+		reason, msg := r.cache.ClusterQueueInactiveReason(newCQObj.Name)
+		if err := r.updateCqStatusIfChanged(ctx, newCQObj, metav1.ConditionFalse, reason, msg); err != nil {
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	var result ctrl.Result
+	now := realClock.Now()
+	changed, next := r.cache.UpdateQuotaSchedule(cqObj.Name, now)
+	if changed {
+		log.V(2).Info("The active quota schedule changed, requeueing the inadmissible workloads")
+		cqNames := sets.New(cqObj.Name)
+		r.qManager.QueueInadmissibleWorkloads(ctx, cqNames)
+		for _, w := range r.quotaScheduleWatchers {
+			w.NotifyQuotaScheduleUpdate(cqNames)
+		}
+	}
+	if !next.IsZero() {
+		// Reconcile again when the next window starts or ends.
+		result.RequeueAfter = next.Sub(now)
+	}
+
+	if r.queueVisibilityMaxCount > 0 && (result.RequeueAfter == 0 || r.queueVisibilityUpdateInterval < result.RequeueAfter) {
+		// Refresh the pending workloads periodically, as their order can
+		// change without any event for the ClusterQueue.
+		result.RequeueAfter = r.queueVisibilityUpdateInterval
+	}
+	return result, nil
+}
+
+// AddUpdateWatcher adds watchers that are notified of the updates of the
+// ClusterQueues.
+func (r *ClusterQueueReconciler) AddUpdateWatcher(watchers ...ClusterQueueUpdateWatcher) {
+	r.watchers = append(r.watchers, watchers...)
+}
+
+// AddQuotaScheduleWatcher adds watchers that are notified when the quotas of
+// the ClusterQueues change because of their quota schedules.
+func (r *ClusterQueueReconciler) AddQuotaScheduleWatcher(watchers ...QuotaScheduleWatcher) {
+	r.quotaScheduleWatchers = append(r.quotaScheduleWatchers, watchers...)
 }
 
 func (r *ClusterQueueReconciler) NotifyWorkloadUpdate(w *kueue.Workload) {
@@ -151,6 +243,14 @@ func (r *ClusterQueueReconciler) NotifyResourceFlavorUpdate(rf *kueue.ResourceFl
 	r.rfUpdateCh <- event.GenericEvent{Object: rf}
 }
 
+func (r *ClusterQueueReconciler) NotifyAdmissionCheckUpdate(ac *kueue.AdmissionCheck) {
+	r.acUpdateCh <- event.GenericEvent{Object: ac}
+}
+
+func (r *ClusterQueueReconciler) NotifyCohortUpdate(cohort *kueue.Cohort) {
+	r.cohortCh <- event.GenericEvent{Object: cohort}
+}
+
 // Event handlers return true to signal the controller to reconcile the
 // ClusterQueue associated with the event.
 
@@ -210,6 +310,11 @@ func (r *ClusterQueueReconciler) Update(e event.UpdateEvent) bool {
 	if err := r.cache.UpdateClusterQueue(newCq); err != nil {
 		log.Error(err, "Failed to update clusterQueue in cache")
 	}
+	// Status updates can't make inadmissible workloads admissible, so they
+	// don't need to move them back to the queue.
+	if equality.Semantic.DeepEqual(oldCq.Spec, newCq.Spec) {
+		return true
+	}
 	if err := r.qManager.UpdateClusterQueue(context.Background(), newCq); err != nil {
 		log.Error(err, "Failed to update clusterQueue in queue manager")
 	}
@@ -323,6 +428,64 @@ func (h *cqResourceFlavorHandler) Generic(e event.GenericEvent, q workqueue.Rate
 	}
 }
 
+type cqAdmissionCheckHandler struct {
+	cache *cache.Cache
+}
+
+func (h *cqAdmissionCheckHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *cqAdmissionCheckHandler) Update(event.UpdateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *cqAdmissionCheckHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *cqAdmissionCheckHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	ac, ok := e.Object.(*kueue.AdmissionCheck)
+	if !ok {
+		return
+	}
+
+	for _, cq := range h.cache.ClusterQueuesUsingAdmissionCheck(ac.Name) {
+		req := &reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: cq,
+			}}
+		q.Add(req)
+	}
+}
+
+type cqCohortHandler struct {
+	cache *cache.Cache
+}
+
+func (h *cqCohortHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *cqCohortHandler) Update(event.UpdateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *cqCohortHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+// Generic reconciles the ClusterQueues under the cohort, as a change in its
+// parent can make their hierarchy of cohorts valid or invalid.
+func (h *cqCohortHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	cohort, ok := e.Object.(*kueue.Cohort)
+	if !ok {
+		return
+	}
+
+	for _, cq := range h.cache.ClusterQueuesUnderCohort(cohort.Name) {
+		req := &reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name: cq,
+			}}
+		q.Add(req)
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterQueueReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	wHandler := cqWorkloadHandler{
@@ -335,11 +498,19 @@ func (r *ClusterQueueReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	rfHandler := cqResourceFlavorHandler{
 		cache: r.cache,
 	}
+	acHandler := cqAdmissionCheckHandler{
+		cache: r.cache,
+	}
+	cohortHandler := cqCohortHandler{
+		cache: r.cache,
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kueue.ClusterQueue{}).
 		Watches(&source.Kind{Type: &corev1.Namespace{}}, &nsHandler).
 		Watches(&source.Channel{Source: r.wlUpdateCh}, &wHandler).
 		Watches(&source.Channel{Source: r.rfUpdateCh}, &rfHandler).
+		Watches(&source.Channel{Source: r.acUpdateCh}, &acHandler).
+		Watches(&source.Channel{Source: r.cohortCh}, &cohortHandler).
 		WithEventFilter(r).
 		Complete(r)
 }
@@ -362,6 +533,7 @@ func (r *ClusterQueueReconciler) updateCqStatusIfChanged(
 	cq.Status.UsedResources = usage
 	cq.Status.AdmittedWorkloads = int32(workloads)
 	cq.Status.PendingWorkloads = int32(pendingWorkloads)
+	r.updatePendingWorkloadsStatus(cq)
 	meta.SetStatusCondition(&cq.Status.Conditions, metav1.Condition{
 		Type:    kueue.ClusterQueueActive,
 		Status:  conditionStatus,
@@ -373,3 +545,70 @@ func (r *ClusterQueueReconciler) updateCqStatusIfChanged(
 	}
 	return nil
 }
+
+// updatePendingWorkloadsStatus sets the head of the pending workloads in the
+// status of the ClusterQueue. A new head is only set once the
+// queueVisibilityUpdateInterval has passed since the last change, to limit
+// the number of status updates.
+func (r *ClusterQueueReconciler) updatePendingWorkloadsStatus(cq *kueue.ClusterQueue) {
+	if r.queueVisibilityMaxCount == 0 {
+		cq.Status.PendingWorkloadsStatus = nil
+		return
+	}
+	var head []kueue.ClusterQueuePendingWorkload
+	for i, info := range r.qManager.PendingWorkloadsHead(cq.Name, int(r.queueVisibilityMaxCount)) {
+		head = append(head, kueue.ClusterQueuePendingWorkload{
+			Name:      info.Obj.Name,
+			Namespace: info.Obj.Namespace,
+			Position:  int32(i),
+			Priority:  priority.Priority(info.Obj),
+		})
+	}
+	if status := cq.Status.PendingWorkloadsStatus; status != nil {
+		if equality.Semantic.DeepEqual(status.Head, head) ||
+			time.Since(status.LastChangeTime.Time) < r.queueVisibilityUpdateInterval {
+			return
+		}
+	}
+	cq.Status.PendingWorkloadsStatus = &kueue.ClusterQueuePendingWorkloadsStatus{
+		Head:           head,
+		LastChangeTime: metav1.Now(),
+	}
+}
+
+// maxDeletionBlockers is the maximum number of the objects that block the
+// deletion of a ClusterQueue or ResourceFlavor listed in the message of the
+// DeletionBlocked condition.
+const maxDeletionBlockers = 10
+
+// setDeletionBlockedCondition sets the condition of the given type to list
+// the objects of the given kind that block the deletion. It returns whether
+// the condition changed.
+func setDeletionBlockedCondition(conditions *[]metav1.Condition, conditionType, reason, kind string, names []string) bool {
+	sort.Strings(names)
+	msg := fmt.Sprintf("Deletion is blocked by %d %s: ", len(names), kind)
+	if len(names) > maxDeletionBlockers {
+		msg += fmt.Sprintf("%s and %d more", strings.Join(names[:maxDeletionBlockers], ", "), len(names)-maxDeletionBlockers)
+	} else {
+		msg += strings.Join(names, ", ")
+	}
+	if c := meta.FindStatusCondition(*conditions, conditionType); c != nil &&
+		c.Status == metav1.ConditionTrue && c.Reason == reason && c.Message == msg {
+		return false
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: msg,
+	})
+	return true
+}
+
+func workloadKeys(workloads []*kueue.Workload) []string {
+	keys := make([]string, len(workloads))
+	for i, wl := range workloads {
+		keys[i] = workload.Key(wl)
+	}
+	return keys
+}
@@ -18,14 +18,18 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr/testr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
@@ -37,26 +41,29 @@ import (
 func TestUpdateCqStatusIfChanged(t *testing.T) {
 	cqName := "test-cq"
 	lqName := "test-lq"
+	now := time.Now()
 	defaultWls := &kueue.WorkloadList{
 		Items: []kueue.Workload{
-			*testingutil.MakeWorkload("alpha", "").Queue(lqName).Obj(),
-			*testingutil.MakeWorkload("beta", "").Queue(lqName).Obj(),
+			*testingutil.MakeWorkload("alpha", "").Queue(lqName).Creation(now).Obj(),
+			*testingutil.MakeWorkload("beta", "").Queue(lqName).Creation(now.Add(time.Second)).Obj(),
 		},
 	}
 
 	testCases := map[string]struct {
-		cqStatus           kueue.ClusterQueueStatus
-		newConditionStatus metav1.ConditionStatus
-		newReason          string
-		newMessage         string
-		newWl              *kueue.Workload
-		wantCqStatus       kueue.ClusterQueueStatus
+		cqStatus                      kueue.ClusterQueueStatus
+		newConditionStatus            metav1.ConditionStatus
+		newReason                     string
+		newMessage                    string
+		newWl                         *kueue.Workload
+		queueVisibilityMaxCount       int32
+		queueVisibilityUpdateInterval time.Duration
+		wantCqStatus                  kueue.ClusterQueueStatus
 	}{
 		"empty ClusterQueueStatus": {
 			cqStatus:           kueue.ClusterQueueStatus{},
 			newConditionStatus: metav1.ConditionFalse,
 			newReason:          "FlavorNotFound",
-			newMessage:         "Can't admit new workloads; some flavors are not found",
+			newMessage:         "Can't admit new workloads; the flavors on-demand are not found",
 			wantCqStatus: kueue.ClusterQueueStatus{
 				UsedResources:    kueue.UsedResources{},
 				PendingWorkloads: int32(len(defaultWls.Items)),
@@ -64,7 +71,7 @@ func TestUpdateCqStatusIfChanged(t *testing.T) {
 					Type:    kueue.ClusterQueueActive,
 					Status:  metav1.ConditionFalse,
 					Reason:  "FlavorNotFound",
-					Message: "Can't admit new workloads; some flavors are not found",
+					Message: "Can't admit new workloads; the flavors on-demand are not found",
 				}},
 			},
 		},
@@ -101,7 +108,7 @@ func TestUpdateCqStatusIfChanged(t *testing.T) {
 					Type:    kueue.ClusterQueueActive,
 					Status:  metav1.ConditionFalse,
 					Reason:  "FlavorNotFound",
-					Message: "Can't admit new workloads; Can't admit new workloads; some flavors are not found",
+					Message: "Can't admit new workloads; Can't admit new workloads; the flavors on-demand are not found",
 				}},
 			},
 			newConditionStatus: metav1.ConditionFalse,
@@ -126,7 +133,7 @@ func TestUpdateCqStatusIfChanged(t *testing.T) {
 					Type:    kueue.ClusterQueueActive,
 					Status:  metav1.ConditionFalse,
 					Reason:  "FlavorNotFound",
-					Message: "Can't admit new workloads; some flavors are not found",
+					Message: "Can't admit new workloads; the flavors on-demand are not found",
 				}},
 			},
 			newConditionStatus: metav1.ConditionTrue,
@@ -169,6 +176,92 @@ func TestUpdateCqStatusIfChanged(t *testing.T) {
 				}},
 			},
 		},
+		"pending workloads status with queue visibility": {
+			cqStatus: kueue.ClusterQueueStatus{
+				UsedResources:    kueue.UsedResources{},
+				PendingWorkloads: int32(len(defaultWls.Items)),
+			},
+			newConditionStatus:            metav1.ConditionTrue,
+			newReason:                     "Ready",
+			newMessage:                    "Can admit new workloads",
+			queueVisibilityMaxCount:       1,
+			queueVisibilityUpdateInterval: time.Second,
+			wantCqStatus: kueue.ClusterQueueStatus{
+				UsedResources:    kueue.UsedResources{},
+				PendingWorkloads: int32(len(defaultWls.Items)),
+				PendingWorkloadsStatus: &kueue.ClusterQueuePendingWorkloadsStatus{
+					Head: []kueue.ClusterQueuePendingWorkload{{
+						Name:     "alpha",
+						Position: 0,
+					}},
+				},
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ClusterQueueActive,
+					Status:  metav1.ConditionTrue,
+					Reason:  "Ready",
+					Message: "Can admit new workloads",
+				}},
+			},
+		},
+		"pending workloads status kept within the update interval": {
+			cqStatus: kueue.ClusterQueueStatus{
+				UsedResources:    kueue.UsedResources{},
+				PendingWorkloads: int32(len(defaultWls.Items)),
+				PendingWorkloadsStatus: &kueue.ClusterQueuePendingWorkloadsStatus{
+					Head: []kueue.ClusterQueuePendingWorkload{{
+						Name:     "beta",
+						Position: 0,
+					}},
+					LastChangeTime: metav1.Now(),
+				},
+			},
+			newConditionStatus:            metav1.ConditionTrue,
+			newReason:                     "Ready",
+			newMessage:                    "Can admit new workloads",
+			queueVisibilityMaxCount:       2,
+			queueVisibilityUpdateInterval: time.Hour,
+			wantCqStatus: kueue.ClusterQueueStatus{
+				UsedResources:    kueue.UsedResources{},
+				PendingWorkloads: int32(len(defaultWls.Items)),
+				PendingWorkloadsStatus: &kueue.ClusterQueuePendingWorkloadsStatus{
+					Head: []kueue.ClusterQueuePendingWorkload{{
+						Name:     "beta",
+						Position: 0,
+					}},
+				},
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ClusterQueueActive,
+					Status:  metav1.ConditionTrue,
+					Reason:  "Ready",
+					Message: "Can admit new workloads",
+				}},
+			},
+		},
+		"pending workloads status cleared without queue visibility": {
+			cqStatus: kueue.ClusterQueueStatus{
+				UsedResources:    kueue.UsedResources{},
+				PendingWorkloads: int32(len(defaultWls.Items)),
+				PendingWorkloadsStatus: &kueue.ClusterQueuePendingWorkloadsStatus{
+					Head: []kueue.ClusterQueuePendingWorkload{{
+						Name:     "alpha",
+						Position: 0,
+					}},
+				},
+			},
+			newConditionStatus: metav1.ConditionTrue,
+			newReason:          "Ready",
+			newMessage:         "Can admit new workloads",
+			wantCqStatus: kueue.ClusterQueueStatus{
+				UsedResources:    kueue.UsedResources{},
+				PendingWorkloads: int32(len(defaultWls.Items)),
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ClusterQueueActive,
+					Status:  metav1.ConditionTrue,
+					Reason:  "Ready",
+					Message: "Can admit new workloads",
+				}},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -205,10 +298,12 @@ func TestUpdateCqStatusIfChanged(t *testing.T) {
 				cqCache.AddOrUpdateWorkload(&wl)
 			}
 			r := &ClusterQueueReconciler{
-				client:   cl,
-				log:      log,
-				cache:    cqCache,
-				qManager: qManager,
+				client:                        cl,
+				log:                           log,
+				cache:                         cqCache,
+				qManager:                      qManager,
+				queueVisibilityMaxCount:       tc.queueVisibilityMaxCount,
+				queueVisibilityUpdateInterval: tc.queueVisibilityUpdateInterval,
 			}
 			if tc.newWl != nil {
 				r.qManager.AddOrUpdateWorkload(tc.newWl)
@@ -218,9 +313,81 @@ func TestUpdateCqStatusIfChanged(t *testing.T) {
 				t.Errorf("Updating ClusterQueueStatus: %v", err)
 			}
 			if diff := cmp.Diff(tc.wantCqStatus, cq.Status,
-				cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); len(diff) != 0 {
+				cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"),
+				cmpopts.IgnoreFields(kueue.ClusterQueuePendingWorkloadsStatus{}, "LastChangeTime")); len(diff) != 0 {
 				t.Errorf("unexpected ClusterQueueStatus (-want,+got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestReconcileDeletionBlocked(t *testing.T) {
+	ctx := context.Background()
+	cq := testingutil.MakeClusterQueue("cq").Obj()
+	now := metav1.Now()
+	cq.DeletionTimestamp = &now
+	cq.Finalizers = []string{kueue.ResourceInUseFinalizerName}
+	var workloads []*kueue.Workload
+	for _, name := range []string{"c", "a", "b"} {
+		workloads = append(workloads, testingutil.MakeWorkload(name, "ns").
+			Admit(testingutil.MakeAdmission("cq").Obj()).Obj())
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(testingutil.MustGetScheme(t)).WithObjects(cq).Build()
+	cqCache := cache.New(cl)
+	if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Inserting clusterQueue in cache: %v", err)
+	}
+	for _, wl := range workloads {
+		cqCache.AddOrUpdateWorkload(wl)
+	}
+	r := NewClusterQueueReconciler(cl, queue.NewManager(cl, cqCache), cqCache)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cq)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	var got kueue.ClusterQueue
+	if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Getting the clusterQueue: %v", err)
+	}
+	wantConditions := []metav1.Condition{{
+		Type:    kueue.ClusterQueueDeletionBlocked,
+		Status:  metav1.ConditionTrue,
+		Reason:  "WorkloadsAdmitted",
+		Message: "Deletion is blocked by 3 admitted workloads: ns/a, ns/b, ns/c",
+	}}
+	if diff := cmp.Diff(wantConditions, got.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+	}
+
+	for _, wl := range workloads {
+		if err := cqCache.DeleteWorkload(wl); err != nil {
+			t.Fatalf("Deleting workload from cache: %v", err)
+		}
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	// The clusterQueue is gone once the finalizer is removed.
+	if err := cl.Get(ctx, req.NamespacedName, &got); !apierrors.IsNotFound(err) {
+		t.Errorf("The clusterQueue wasn't deleted after the workloads finished, got error %v", err)
+	}
+}
+
+func TestSetDeletionBlockedCondition(t *testing.T) {
+	var names []string
+	for i := 0; i < 12; i++ {
+		names = append(names, fmt.Sprintf("ns/wl-%02d", i))
+	}
+	var conditions []metav1.Condition
+	if !setDeletionBlockedCondition(&conditions, kueue.ResourceFlavorDeletionBlocked, "WorkloadsAdmitted", "admitted workloads", names) {
+		t.Error("The condition didn't change")
+	}
+	wantMsg := "Deletion is blocked by 12 admitted workloads: ns/wl-00, ns/wl-01, ns/wl-02, ns/wl-03, ns/wl-04, ns/wl-05, ns/wl-06, ns/wl-07, ns/wl-08, ns/wl-09 and 2 more"
+	if diff := cmp.Diff(wantMsg, conditions[0].Message); diff != "" {
+		t.Errorf("Unexpected message (-want,+got):\n%s", diff)
+	}
+	if setDeletionBlockedCondition(&conditions, kueue.ResourceFlavorDeletionBlocked, "WorkloadsAdmitted", "admitted workloads", names) {
+		t.Error("The condition changed with the same objects")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+)
+
+type CohortUpdateWatcher interface {
+	NotifyCohortUpdate(*kueue.Cohort)
+}
+
+// CohortReconciler reconciles a Cohort object
+type CohortReconciler struct {
+	log      logr.Logger
+	qManager *queue.Manager
+	cache    *cache.Cache
+	client   client.Client
+	watchers []CohortUpdateWatcher
+}
+
+func NewCohortReconciler(
+	client client.Client,
+	qMgr *queue.Manager,
+	cache *cache.Cache,
+) *CohortReconciler {
+	return &CohortReconciler{
+		log:      ctrl.Log.WithName("cohort-reconciler"),
+		cache:    cache,
+		client:   client,
+		qManager: qMgr,
+	}
+}
+
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=cohorts,verbs=get;list;watch
+
+// Reconcile is a no-op, as the events for Cohort objects are fully handled
+// by the event filters, which update the cache and the queues.
+func (r *CohortReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ctrl.Result{}, nil
+}
+
+func (r *CohortReconciler) AddUpdateWatcher(watchers ...CohortUpdateWatcher) {
+	r.watchers = watchers
+}
+
+func (r *CohortReconciler) notifyWatchers(cohort *kueue.Cohort) {
+	for _, w := range r.watchers {
+		w.NotifyCohortUpdate(cohort)
+	}
+}
+
+func (r *CohortReconciler) Create(e event.CreateEvent) bool {
+	cohort, match := e.Object.(*kueue.Cohort)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("cohort", klog.KObj(cohort))
+	defer r.notifyWatchers(cohort)
+	log.V(2).Info("Cohort create event")
+	r.cache.AddOrUpdateCohort(cohort.DeepCopy())
+	r.qManager.AddOrUpdateCohort(context.Background(), cohort)
+	return false
+}
+
+func (r *CohortReconciler) Delete(e event.DeleteEvent) bool {
+	cohort, match := e.Object.(*kueue.Cohort)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("cohort", klog.KObj(cohort))
+	defer r.notifyWatchers(cohort)
+	log.V(2).Info("Cohort delete event")
+	r.cache.DeleteCohort(cohort)
+	r.qManager.DeleteCohort(context.Background(), cohort)
+	return false
+}
+
+func (r *CohortReconciler) Update(e event.UpdateEvent) bool {
+	cohort, match := e.ObjectNew.(*kueue.Cohort)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("cohort", klog.KObj(cohort))
+	defer r.notifyWatchers(cohort)
+	log.V(2).Info("Cohort update event")
+	r.cache.AddOrUpdateCohort(cohort.DeepCopy())
+	r.qManager.AddOrUpdateCohort(context.Background(), cohort)
+	return false
+}
+
+func (r *CohortReconciler) Generic(e event.GenericEvent) bool {
+	r.log.V(3).Info("Ignore generic event", "obj", klog.KObj(e.Object), "kind", e.Object.GetObjectKind().GroupVersionKind())
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CohortReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kueue.Cohort{}).
+		WithEventFilter(r).
+		Complete(r)
+}
@@ -17,12 +17,14 @@ limitations under the License.
 package core
 
 import (
+	"context"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/queue"
 )
 
@@ -35,21 +37,116 @@ func SetupControllers(mgr ctrl.Manager, qManager *queue.Manager, cc *cache.Cache
 	if err := rfRec.SetupWithManager(mgr); err != nil {
 		return "ResourceFlavor", err
 	}
+	cohortRec := NewCohortReconciler(mgr.GetClient(), qManager, cc)
+	if err := cohortRec.SetupWithManager(mgr); err != nil {
+		return "Cohort", err
+	}
+	validateNodes := cfg.ExtendedResources != nil && cfg.ExtendedResources.ValidateNodes
+	topologyAware := cfg.TopologyAwareScheduling != nil && cfg.TopologyAwareScheduling.Enable
+	quotaAutoSizing := cfg.QuotaAutoSizing != nil && cfg.QuotaAutoSizing.Enable
+	var nodeRec *NodeReconciler
+	if validateNodes || topologyAware || quotaAutoSizing {
+		nodeRec = NewNodeReconciler(mgr.GetClient(), qManager, cc)
+		if err := nodeRec.SetupWithManager(mgr); err != nil {
+			return "Node", err
+		}
+	}
+	if topologyAware {
+		if err := NewTopologyReconciler(mgr.GetClient(), qManager, cc).SetupWithManager(mgr); err != nil {
+			return "Topology", err
+		}
+	}
+	if cfg.FlavorCapacity != nil && cfg.FlavorCapacity.Enable {
+		fcRec := NewFlavorCapacityReconciler(mgr.GetClient(), mgr.GetEventRecorderFor(constants.FlavorCapacityControllerName))
+		if err := fcRec.SetupWithManager(mgr); err != nil {
+			return "FlavorCapacity", err
+		}
+	}
+	unreliableFlavors := cfg.UnreliableFlavors != nil && cfg.UnreliableFlavors.Enable
+	unschedulableEviction := cfg.UnschedulableEviction != nil && cfg.UnschedulableEviction.Enable
+	if unreliableFlavors || unschedulableEviction {
+		if err := setupWorkloadOwnerUIDIndex(context.Background(), mgr.GetFieldIndexer()); err != nil {
+			return "WorkloadOwnerIndex", err
+		}
+	}
+	if unreliableFlavors {
+		reclaimRec := NewReclaimReconciler(mgr.GetClient(), mgr.GetEventRecorderFor(constants.ReclaimControllerName))
+		if err := reclaimRec.SetupWithManager(mgr); err != nil {
+			return "Reclaim", err
+		}
+	}
+	if unschedulableEviction {
+		unschedulableRec := NewUnschedulableReconciler(mgr.GetClient(), mgr.GetEventRecorderFor(constants.UnschedulableControllerName),
+			unschedulableTimeout(cfg))
+		if err := unschedulableRec.SetupWithManager(mgr); err != nil {
+			return "Unschedulable", err
+		}
+	}
 	qRec := NewLocalQueueReconciler(mgr.GetClient(), qManager, cc)
 	if err := qRec.SetupWithManager(mgr); err != nil {
 		return "LocalQueue", err
 	}
-	cqRec := NewClusterQueueReconciler(mgr.GetClient(), qManager, cc, rfRec)
-	rfRec.AddUpdateWatcher(cqRec)
+	acRec := NewAdmissionCheckReconciler(mgr.GetClient(), qManager, cc)
+	if err := acRec.SetupWithManager(mgr); err != nil {
+		return "AdmissionCheck", err
+	}
+	cqRec := NewClusterQueueReconciler(mgr.GetClient(), qManager, cc, WithWatchers(rfRec),
+		WithQueueVisibility(queueVisibility(cfg)))
+	acRec.AddUpdateWatcher(cqRec)
+	cohortRec.AddUpdateWatcher(cqRec)
 	if err := cqRec.SetupWithManager(mgr); err != nil {
 		return "ClusterQueue", err
 	}
-	if err := NewWorkloadReconciler(mgr.GetClient(), qManager, cc, WithWorkloadUpdateWatchers(qRec, cqRec), WithPodsReadyTimeout(podsReadyTimeout(cfg))).SetupWithManager(mgr); err != nil {
+	wlRec := NewWorkloadReconciler(mgr.GetClient(), qManager, cc, mgr.GetEventRecorderFor(constants.WorkloadControllerName),
+		WithWorkloadUpdateWatchers(qRec, cqRec, rfRec), WithPodsReadyTimeout(podsReadyTimeout(cfg)),
+		WithRequeuingLimitCount(requeuingLimitCount(cfg)), WithFinishedRetention(finishedRetention(cfg)))
+	cqRec.AddUpdateWatcher(wlRec)
+	cqRec.AddQuotaScheduleWatcher(wlRec)
+	rfRec.AddUpdateWatcher(cqRec, wlRec)
+	if nodeRec != nil && quotaAutoSizing {
+		nodeRec.AddUpdateWatcher(wlRec)
+	}
+	if err := wlRec.SetupWithManager(mgr); err != nil {
 		return "Workload", err
 	}
+	if cfg.CacheVerification != nil && cfg.CacheVerification.Enable {
+		if err := NewCacheVerifier(mgr.GetClient(), cc, cfg.CacheVerification.Interval.Duration).SetupWithManager(mgr); err != nil {
+			return "CacheVerifier", err
+		}
+	}
 	return "", nil
 }
 
+func queueVisibility(cfg *config.Configuration) (int32, time.Duration) {
+	if cfg.QueueVisibility != nil && cfg.QueueVisibility.Enable &&
+		cfg.QueueVisibility.MaxCount != nil && cfg.QueueVisibility.UpdateInterval != nil {
+		return *cfg.QueueVisibility.MaxCount, cfg.QueueVisibility.UpdateInterval.Duration
+	}
+	return 0, 0
+}
+
+func requeuingLimitCount(cfg *config.Configuration) *int32 {
+	if cfg.RequeuingBackoff != nil && cfg.RequeuingBackoff.Enable {
+		return cfg.RequeuingBackoff.BackoffLimitCount
+	}
+	return nil
+}
+
+func finishedRetention(cfg *config.Configuration) *time.Duration {
+	if cfg.ObjectRetentionPolicies != nil && cfg.ObjectRetentionPolicies.Workloads != nil &&
+		cfg.ObjectRetentionPolicies.Workloads.AfterFinished != nil {
+		return &cfg.ObjectRetentionPolicies.Workloads.AfterFinished.Duration
+	}
+	return nil
+}
+
+func unschedulableTimeout(cfg *config.Configuration) time.Duration {
+	if cfg.UnschedulableEviction.Timeout != nil {
+		return cfg.UnschedulableEviction.Timeout.Duration
+	}
+	return 0
+}
+
 func podsReadyTimeout(cfg *config.Configuration) *time.Duration {
 	if cfg.WaitForPodsReady != nil && cfg.WaitForPodsReady.Enable && cfg.WaitForPodsReady.Timeout != nil {
 		return &cfg.WaitForPodsReady.Timeout.Duration
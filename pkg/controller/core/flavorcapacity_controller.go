@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+// FlavorCapacityReconciler reports in the status of the ResourceFlavors the
+// ready nodes that match them and their allocatable capacity, so that
+// administrators notice the flavors that are backed by no nodes.
+type FlavorCapacityReconciler struct {
+	log      logr.Logger
+	client   client.Client
+	recorder record.EventRecorder
+}
+
+func NewFlavorCapacityReconciler(client client.Client, recorder record.EventRecorder) *FlavorCapacityReconciler {
+	return &FlavorCapacityReconciler{
+		log:      ctrl.Log.WithName("flavor-capacity-reconciler"),
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors/status,verbs=get;update;patch
+
+func (r *FlavorCapacityReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var rf kueue.ResourceFlavor
+	if err := r.client.Get(ctx, req.NamespacedName, &rf); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("resourceFlavor", klog.KObj(&rf))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	var nodes corev1.NodeList
+	if err := r.client.List(ctx, &nodes, client.MatchingLabels(rf.NodeSelector)); err != nil {
+		return ctrl.Result{}, err
+	}
+	oldStatus := rf.Status.DeepCopy()
+	rf.Status.Nodes = 0
+	rf.Status.Allocatable = nil
+	for i := range nodes.Items {
+		n := &nodes.Items[i]
+		if !flavorMatchesNode(&rf, n) {
+			continue
+		}
+		rf.Status.Nodes++
+		if rf.Status.Allocatable == nil {
+			rf.Status.Allocatable = make(corev1.ResourceList, len(n.Status.Allocatable))
+		}
+		for name, q := range n.Status.Allocatable {
+			total := rf.Status.Allocatable[name]
+			total.Add(q)
+			rf.Status.Allocatable[name] = total
+		}
+	}
+	cond := metav1.Condition{
+		Type:    kueue.ResourceFlavorNodesAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  "NodesAvailable",
+		Message: "There are ready nodes that match the flavor",
+	}
+	if rf.Status.Nodes == 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "NoMatchingNodes"
+		cond.Message = "No ready node matches the nodeSelector and taints of the flavor"
+	}
+	wasAvailable := !meta.IsStatusConditionFalse(oldStatus.Conditions, kueue.ResourceFlavorNodesAvailable)
+	meta.SetStatusCondition(&rf.Status.Conditions, cond)
+	if equality.Semantic.DeepEqual(&rf.Status, oldStatus) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.client.Status().Update(ctx, &rf); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log.V(2).Info("Updated flavor capacity", "nodes", rf.Status.Nodes)
+	if rf.Status.Nodes == 0 && wasAvailable {
+		r.recorder.Event(&rf, corev1.EventTypeWarning, cond.Reason, cond.Message)
+	}
+	return ctrl.Result{}, nil
+}
+
+// flavorMatchesNode returns whether the node is ready and schedulable, its
+// labels match the nodeSelector of the flavor and its NoSchedule and
+// NoExecute taints are either taints of the flavor, which the workloads
+// tolerate, or tolerated by the tolerations of the flavor.
+func flavorMatchesNode(rf *kueue.ResourceFlavor, n *corev1.Node) bool {
+	if n.Spec.Unschedulable || !nodeIsReady(n) {
+		return false
+	}
+	if !labels.SelectorFromSet(rf.NodeSelector).Matches(labels.Set(n.Labels)) {
+		return false
+	}
+	for i := range n.Spec.Taints {
+		taint := &n.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || flavorToleratesTaint(rf, taint) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func flavorToleratesTaint(rf *kueue.ResourceFlavor, taint *corev1.Taint) bool {
+	for i := range rf.Taints {
+		if rf.Taints[i].MatchTaint(taint) {
+			return true
+		}
+	}
+	for i := range rf.Tolerations {
+		if rf.Tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeIsReady(n *corev1.Node) bool {
+	for _, c := range n.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// flavorsForNode returns the requests for all the ResourceFlavors, as any of
+// them could start or stop matching the node.
+func (r *FlavorCapacityReconciler) flavorsForNode(o client.Object) []reconcile.Request {
+	var flavors kueue.ResourceFlavorList
+	if err := r.client.List(context.Background(), &flavors); err != nil {
+		r.log.Error(err, "Listing ResourceFlavors", "node", klog.KObj(o))
+		return nil
+	}
+	requests := make([]reconcile.Request, len(flavors.Items))
+	for i := range flavors.Items {
+		requests[i] = reconcile.Request{NamespacedName: types.NamespacedName{Name: flavors.Items[i].Name}}
+	}
+	return requests
+}
+
+// nodeCapacityChanged filters out the Node updates that don't change the
+// labels, taints, schedulability, readiness or allocatable resources, such as
+// the status heartbeats.
+func nodeCapacityChanged(e event.UpdateEvent) bool {
+	oldNode, okOld := e.ObjectOld.(*corev1.Node)
+	newNode, okNew := e.ObjectNew.(*corev1.Node)
+	if !okOld || !okNew {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+		!equality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints) ||
+		oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+		nodeIsReady(oldNode) != nodeIsReady(newNode) ||
+		!equality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *FlavorCapacityReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("flavorcapacity").
+		For(&kueue.ResourceFlavor{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(r.flavorsForNode),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: nodeCapacityChanged})).
+		Complete(r)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestReconcileFlavorCapacity(t *testing.T) {
+	node := func(name string, ready bool, nodeLabels map[string]string, taints ...corev1.Taint) *corev1.Node {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+			Spec:       corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			},
+		}
+	}
+	spotTaint := corev1.Taint{Key: "spot", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	cases := map[string]struct {
+		flavor     *kueue.ResourceFlavor
+		nodes      []client.Object
+		wantStatus kueue.ResourceFlavorStatus
+		wantEvent  bool
+	}{
+		"ready nodes match the labels": {
+			flavor: utiltesting.MakeResourceFlavor("on-demand").Label("pool", "a").Obj(),
+			nodes: []client.Object{
+				node("a1", true, map[string]string{"pool": "a"}),
+				node("a2", true, map[string]string{"pool": "a"}),
+				node("a3", false, map[string]string{"pool": "a"}),
+				node("b1", true, map[string]string{"pool": "b"}),
+			},
+			wantStatus: kueue.ResourceFlavorStatus{
+				Nodes: 2,
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ResourceFlavorNodesAvailable,
+					Status:  metav1.ConditionTrue,
+					Reason:  "NodesAvailable",
+					Message: "There are ready nodes that match the flavor",
+				}},
+			},
+		},
+		"tainted nodes match the taints and tolerations of the flavor": {
+			flavor: utiltesting.MakeResourceFlavor("spot").
+				Label("pool", "spot").
+				Taint(spotTaint).
+				Toleration(corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}).
+				Obj(),
+			nodes: []client.Object{
+				node("spot1", true, map[string]string{"pool": "spot"}, spotTaint),
+				node("spot2", true, map[string]string{"pool": "spot"},
+					corev1.Taint{Key: "dedicated", Value: "ml", Effect: corev1.TaintEffectNoExecute}),
+				node("spot3", true, map[string]string{"pool": "spot"},
+					corev1.Taint{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}),
+			},
+			wantStatus: kueue.ResourceFlavorStatus{
+				Nodes: 2,
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ResourceFlavorNodesAvailable,
+					Status:  metav1.ConditionTrue,
+					Reason:  "NodesAvailable",
+					Message: "There are ready nodes that match the flavor",
+				}},
+			},
+		},
+		"no node matches": {
+			flavor: utiltesting.MakeResourceFlavor("on-demand").Label("pool", "a").Obj(),
+			nodes: []client.Object{
+				node("a1", false, map[string]string{"pool": "a"}),
+				node("b1", true, map[string]string{"pool": "b"}),
+			},
+			wantStatus: kueue.ResourceFlavorStatus{
+				Conditions: []metav1.Condition{{
+					Type:    kueue.ResourceFlavorNodesAvailable,
+					Status:  metav1.ConditionFalse,
+					Reason:  "NoMatchingNodes",
+					Message: "No ready node matches the nodeSelector and taints of the flavor",
+				}},
+			},
+			wantEvent: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).
+				WithObjects(tc.flavor).WithObjects(tc.nodes...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := NewFlavorCapacityReconciler(cl, recorder)
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: tc.flavor.Name}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			var got kueue.ResourceFlavor
+			if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Failed getting the flavor: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantStatus, got.Status, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected status (-want,+got):\n%s", diff)
+			}
+			if gotEvent := len(recorder.Events) > 0; gotEvent != tc.wantEvent {
+				t.Errorf("Emitted warning event: %t, want %t", gotEvent, tc.wantEvent)
+			}
+		})
+	}
+}
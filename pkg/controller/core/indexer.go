@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+const workloadOwnerUIDKey = "metadata.ownerReferences.uid"
+
+// setupWorkloadOwnerUIDIndex indexes the Workloads by the UIDs of their
+// owners, which the controllers that watch the Pods use to find the Workloads
+// of a Pod.
+func setupWorkloadOwnerUIDIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &kueue.Workload{}, workloadOwnerUIDKey, func(o client.Object) []string {
+		var uids []string
+		for _, owner := range o.GetOwnerReferences() {
+			uids = append(uids, string(owner.UID))
+		}
+		return uids
+	})
+}
+
+// podWorkloads returns the Workloads of the pod. The Workloads of jobs are
+// owned by the job that controls the pod, while the Workloads of plain pods
+// are owned by the pods.
+func podWorkloads(ctx context.Context, c client.Client, pod *corev1.Pod) ([]kueue.Workload, error) {
+	owners := []types.UID{pod.UID}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		owners = append(owners, owner.UID)
+	}
+	var wls []kueue.Workload
+	for _, uid := range owners {
+		var list kueue.WorkloadList
+		if err := c.List(ctx, &list, client.InNamespace(pod.Namespace), client.MatchingFields{workloadOwnerUIDKey: string(uid)}); err != nil {
+			return nil, err
+		}
+		wls = append(wls, list.Items...)
+	}
+	return wls, nil
+}
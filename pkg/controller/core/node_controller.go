@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+)
+
+type NodeUpdateWatcher interface {
+	NotifyNodeUpdate(clusterQueues sets.Set[string])
+}
+
+// NodeReconciler tracks the labels, extended resources and capacity of the
+// Nodes, so that ResourceFlavors are only assigned to the extended resources
+// exposed by the nodes that they select, and the quotas declared as a
+// percentage of the capacity of the nodes follow the nodes that join or leave.
+type NodeReconciler struct {
+	log      logr.Logger
+	qManager *queue.Manager
+	cache    *cache.Cache
+	client   client.Client
+	watchers []NodeUpdateWatcher
+}
+
+func NewNodeReconciler(
+	client client.Client,
+	qMgr *queue.Manager,
+	cache *cache.Cache,
+) *NodeReconciler {
+	return &NodeReconciler{
+		log:      ctrl.Log.WithName("node-reconciler"),
+		cache:    cache,
+		client:   client,
+		qManager: qMgr,
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// Reconcile is a no-op, as the events for Node objects are fully handled
+// by the event filters, which update the cache and the queues.
+func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ctrl.Result{}, nil
+}
+
+func (r *NodeReconciler) AddUpdateWatcher(watchers ...NodeUpdateWatcher) {
+	r.watchers = watchers
+}
+
+// notifyWatchers signals the watchers that the nodes of the ClusterQueues
+// changed, which can change the quotas declared as a percentage of them.
+func (r *NodeReconciler) notifyWatchers(cqNames sets.Set[string]) {
+	for _, w := range r.watchers {
+		w.NotifyNodeUpdate(cqNames)
+	}
+}
+
+func (r *NodeReconciler) Create(e event.CreateEvent) bool {
+	node, match := e.Object.(*corev1.Node)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("node", klog.KObj(node))
+	log.V(2).Info("Node create event")
+	if cqNames := r.cache.AddOrUpdateNode(node); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+		r.notifyWatchers(cqNames)
+	}
+	return false
+}
+
+func (r *NodeReconciler) Delete(e event.DeleteEvent) bool {
+	node, match := e.Object.(*corev1.Node)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("node", klog.KObj(node))
+	log.V(2).Info("Node delete event")
+	if cqNames := r.cache.DeleteNode(node); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+		r.notifyWatchers(cqNames)
+	}
+	return false
+}
+
+func (r *NodeReconciler) Update(e event.UpdateEvent) bool {
+	node, match := e.ObjectNew.(*corev1.Node)
+	if !match {
+		return false
+	}
+	// Most updates are status heartbeats that don't change the labels or the
+	// extended resources, which the cache ignores.
+	if cqNames := r.cache.AddOrUpdateNode(node); len(cqNames) > 0 {
+		r.log.V(2).Info("Node update event", "node", klog.KObj(node))
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+		r.notifyWatchers(cqNames)
+	}
+	return false
+}
+
+func (r *NodeReconciler) Generic(e event.GenericEvent) bool {
+	r.log.V(3).Info("Ignore generic event", "obj", klog.KObj(e.Object), "kind", e.Object.GetObjectKind().GroupVersionKind())
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Node{}).
+		WithEventFilter(r).
+		Complete(r)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// reclaimDisruptionReasons are the reasons of the DisruptionTarget condition
+// of the pods that are terminated because their node is going away.
+var reclaimDisruptionReasons = []string{"DeletionByTaintManager", "TerminationByKubelet", "DeletionByPodGC"}
+
+// reclaimFailureReasons are the reasons of the pods that failed because their
+// node was shut down or lost.
+var reclaimFailureReasons = []string{"Shutdown", "Terminated", "NodeLost"}
+
+// ReclaimReconciler evicts the workloads whose pods are disrupted because the
+// nodes of an unreliable ResourceFlavor are reclaimed, and excludes the flavor
+// from the next admissions of the workloads, so that they fall back to the
+// next flavors of their ClusterQueue.
+type ReclaimReconciler struct {
+	log      logr.Logger
+	client   client.Client
+	recorder record.EventRecorder
+}
+
+func NewReclaimReconciler(client client.Client, recorder record.EventRecorder) *ReclaimReconciler {
+	return &ReclaimReconciler{
+		log:      ctrl.Log.WithName("reclaim-reconciler"),
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+
+func (r *ReclaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pod corev1.Pod
+	if err := r.client.Get(ctx, req.NamespacedName, &pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !podReclaimed(&pod) {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("pod", klog.KObj(&pod), "node", pod.Spec.NodeName)
+	ctx = ctrl.LoggerInto(ctx, log)
+	log.V(2).Info("Reconciling reclaimed Pod")
+
+	wls, err := podWorkloads(ctx, r.client, &pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range wls {
+		if err := r.evictReclaimed(ctx, &wls[i], &pod); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// evictReclaimed evicts the workload, if the pod was running on an unreliable
+// flavor of its current admission, and excludes the flavor for the number of
+// retries that the flavor configures.
+func (r *ReclaimReconciler) evictReclaimed(ctx context.Context, wl *kueue.Workload, pod *corev1.Pod) error {
+	if wl.Spec.Admission == nil || workload.IsEvicted(wl) || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+		return nil
+	}
+	// The pods of a previous admission don't evict the workload again.
+	if reserved := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadQuotaReserved); reserved == nil ||
+		reserved.Status != metav1.ConditionTrue || pod.CreationTimestamp.Before(&reserved.LastTransitionTime) {
+		return nil
+	}
+	retries, err := r.reclaimedFlavors(ctx, wl, pod)
+	if err != nil || len(retries) == 0 {
+		return err
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("workload", klog.KObj(wl))
+	if workload.ExcludeFlavors(wl, retries) {
+		log.V(2).Info("Excluding the reclaimed flavors of the workload", "excludedFlavors", wl.Status.ExcludedFlavors)
+		if err := r.client.Status().Update(ctx, wl); err != nil {
+			return err
+		}
+	}
+	names := make([]string, 0, len(retries))
+	for name := range retries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msg := fmt.Sprintf("The pod %s was disrupted because its node %s, of the unreliable flavor %s, was reclaimed", pod.Name, pod.Spec.NodeName, strings.Join(names, ", "))
+	log.V(2).Info("Evicting the workload due to a reclaimed node")
+	if err := workload.Evict(ctx, r.client, wl, kueue.WorkloadEvictedByNodeReclaimed, msg); err != nil {
+		return err
+	}
+	r.recorder.Event(wl, corev1.EventTypeNormal, kueue.WorkloadEvictedByNodeReclaimed, msg)
+	return nil
+}
+
+// reclaimedFlavors returns the unreliable flavors of the admission of the
+// workload whose node selector the pod has, with the number of retries for
+// which they are excluded.
+func (r *ReclaimReconciler) reclaimedFlavors(ctx context.Context, wl *kueue.Workload, pod *corev1.Pod) (map[string]int32, error) {
+	retries := make(map[string]int32)
+	checked := make(map[string]bool)
+	for _, psFlavors := range wl.Spec.Admission.PodSetFlavors {
+		for _, name := range psFlavors.Flavors {
+			if checked[name] {
+				continue
+			}
+			checked[name] = true
+			var rf kueue.ResourceFlavor
+			if err := r.client.Get(ctx, types.NamespacedName{Name: name}, &rf); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if rf.Unreliable != nil && selectsPod(&rf, pod) {
+				retries[name] = rf.Unreliable.ExcludeForRetries
+			}
+		}
+	}
+	return retries, nil
+}
+
+// selectsPod returns whether the node selector of the flavor was injected in
+// the pod.
+func selectsPod(rf *kueue.ResourceFlavor, pod *corev1.Pod) bool {
+	for k, v := range rf.NodeSelector {
+		if pod.Spec.NodeSelector[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// podReclaimed returns whether the pod was disrupted because its node is going
+// away, as reported by the DisruptionTarget condition or by the reason of the
+// failure of the pod.
+func podReclaimed(pod *corev1.Pod) bool {
+	if pod.Spec.NodeName == "" {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.AlphaNoCompatGuaranteeDisruptionTarget && c.Status == corev1.ConditionTrue {
+			return hasReason(reclaimDisruptionReasons, c.Reason)
+		}
+	}
+	return pod.Status.Phase == corev1.PodFailed && hasReason(reclaimFailureReasons, pod.Status.Reason)
+}
+
+func hasReason(reasons []string, reason string) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager. Only the
+// reclaimed pods are reconciled.
+func (r *ReclaimReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("reclaim").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			pod, ok := o.(*corev1.Pod)
+			return ok && podReclaimed(pod)
+		}))).
+		Complete(r)
+}
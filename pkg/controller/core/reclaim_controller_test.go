@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestPodReclaimed(t *testing.T) {
+	cases := map[string]struct {
+		pod  corev1.Pod
+		want bool
+	}{
+		"running pod": {
+			pod: corev1.Pod{
+				Spec:   corev1.PodSpec{NodeName: "spot-1"},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+		"deleted by the taint manager": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "spot-1"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.AlphaNoCompatGuaranteeDisruptionTarget,
+						Status: corev1.ConditionTrue,
+						Reason: "DeletionByTaintManager",
+					}},
+				},
+			},
+			want: true,
+		},
+		"preempted by the scheduler": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "spot-1"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.AlphaNoCompatGuaranteeDisruptionTarget,
+						Status: corev1.ConditionTrue,
+						Reason: "PreemptionByScheduler",
+					}},
+				},
+			},
+		},
+		"failed due to a node shutdown": {
+			pod: corev1.Pod{
+				Spec:   corev1.PodSpec{NodeName: "spot-1"},
+				Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Terminated"},
+			},
+			want: true,
+		},
+		"failed with an error": {
+			pod: corev1.Pod{
+				Spec:   corev1.PodSpec{NodeName: "spot-1"},
+				Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Error"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := podReclaimed(&tc.pod); got != tc.want {
+				t.Errorf("podReclaimed() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReclaimedFlavors(t *testing.T) {
+	flavors := []*kueue.ResourceFlavor{
+		utiltesting.MakeResourceFlavor("spot").Label("instance-type", "spot").Unreliable(2).Obj(),
+		utiltesting.MakeResourceFlavor("spot-gpu").Label("instance-type", "spot-gpu").Unreliable(0).Obj(),
+		utiltesting.MakeResourceFlavor("on-demand").Label("instance-type", "on-demand").Obj(),
+	}
+	cases := map[string]struct {
+		admission    *kueue.Admission
+		nodeSelector map[string]string
+		want         map[string]int32
+	}{
+		"pod on an unreliable flavor": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "spot").
+				Flavor("example.com/gpu", "spot-gpu").
+				Obj(),
+			nodeSelector: map[string]string{"instance-type": "spot"},
+			want:         map[string]int32{"spot": 2},
+		},
+		"pod on a reliable flavor": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "on-demand").
+				Obj(),
+			nodeSelector: map[string]string{"instance-type": "on-demand"},
+			want:         map[string]int32{},
+		},
+		"deleted flavor": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "spot-deleted").
+				Obj(),
+			want: map[string]int32{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t))
+			for _, rf := range flavors {
+				builder = builder.WithObjects(rf)
+			}
+			r := NewReclaimReconciler(builder.Build(), record.NewFakeRecorder(10))
+			wl := utiltesting.MakeWorkload("wl", "ns").Admit(tc.admission).Obj()
+			pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node", NodeSelector: tc.nodeSelector}}
+			got, err := r.reclaimedFlavors(context.Background(), wl, pod)
+			if err != nil {
+				t.Fatalf("reclaimedFlavors failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected flavors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
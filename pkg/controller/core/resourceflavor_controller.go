@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/workqueue"
@@ -47,6 +48,7 @@ type ResourceFlavorReconciler struct {
 	cache      *cache.Cache
 	client     client.Client
 	cqUpdateCh chan event.GenericEvent
+	wlUpdateCh chan event.GenericEvent
 	watchers   []ResourceFlavorUpdateWatcher
 }
 
@@ -61,10 +63,12 @@ func NewResourceFlavorReconciler(
 		client:     client,
 		qManager:   qMgr,
 		cqUpdateCh: make(chan event.GenericEvent, updateChBuffer),
+		wlUpdateCh: make(chan event.GenericEvent, updateChBuffer),
 	}
 }
 
 //+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch;update;delete
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors/finalizers,verbs=update
 
 func (r *ResourceFlavorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -93,7 +97,14 @@ func (r *ResourceFlavorReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				// We avoid to return error here to prevent backoff requeue, which is passive and wasteful.
 				// Instead, we drive the removal of finalizer by ClusterQueue Update/Delete events
 				// when resourceFlavor is no longer in use.
-				return ctrl.Result{}, nil
+				return ctrl.Result{}, r.updateDeletionBlockedCondition(ctx, &flavor, "ClusterQueuesUsingFlavor", "ClusterQueues", cqs)
+			}
+			// The usage of the workloads admitted in the flavor is only
+			// accounted while the flavor exists. The workload controller
+			// evicts them once no clusterQueue uses the flavor.
+			if wls := r.cache.WorkloadsUsingFlavor(flavor.Name); len(wls) != 0 {
+				log.V(3).Info("resourceFlavor is still used by admitted workloads", "workloads", len(wls))
+				return ctrl.Result{}, r.updateDeletionBlockedCondition(ctx, &flavor, "WorkloadsAdmitted", "admitted workloads", workloadKeys(wls))
 			}
 
 			controllerutil.RemoveFinalizer(&flavor, kueue.ResourceInUseFinalizerName)
@@ -106,6 +117,15 @@ func (r *ResourceFlavorReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ctrl.Result{}, nil
 }
 
+// updateDeletionBlockedCondition reports, in the status of the
+// resourceFlavor, the objects that block its deletion.
+func (r *ResourceFlavorReconciler) updateDeletionBlockedCondition(ctx context.Context, flavor *kueue.ResourceFlavor, reason, kind string, names []string) error {
+	if !setDeletionBlockedCondition(&flavor.Status.Conditions, kueue.ResourceFlavorDeletionBlocked, reason, kind, names) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.client.Status().Update(ctx, flavor))
+}
+
 func (r *ResourceFlavorReconciler) AddUpdateWatcher(watchers ...ResourceFlavorUpdateWatcher) {
 	r.watchers = watchers
 }
@@ -164,14 +184,10 @@ func (r *ResourceFlavorReconciler) Update(e event.UpdateEvent) bool {
 	log := r.log.WithValues("resourceFlavor", klog.KObj(flv))
 	log.V(2).Info("ResourceFlavor update event")
 
-	if flv.DeletionTimestamp != nil {
-		return true
-	}
-
 	if cqNames := r.cache.AddOrUpdateResourceFlavor(flv.DeepCopy()); len(cqNames) > 0 {
 		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
 	}
-	return false
+	return flv.DeletionTimestamp != nil
 }
 
 func (r *ResourceFlavorReconciler) Generic(e event.GenericEvent) bool {
@@ -202,6 +218,26 @@ func (r *ResourceFlavorReconciler) NotifyClusterQueueUpdate(oldCQ, newCQ *kueue.
 	}
 }
 
+// NotifyWorkloadUpdate signals the controller to reconcile the resourceFlavors
+// being deleted in which the workload reserved quota, as the workload may no
+// longer use them.
+func (r *ResourceFlavorReconciler) NotifyWorkloadUpdate(wl *kueue.Workload) {
+	if wl.Spec.Admission == nil {
+		return
+	}
+	flavors := sets.New[string]()
+	for _, psFlavors := range wl.Spec.Admission.PodSetFlavors {
+		for _, f := range psFlavors.Flavors {
+			flavors.Insert(f)
+		}
+	}
+	for f := range flavors {
+		if r.cache.ResourceFlavorTerminating(f) {
+			r.wlUpdateCh <- event.GenericEvent{Object: &kueue.ResourceFlavor{ObjectMeta: metav1.ObjectMeta{Name: f}}}
+		}
+	}
+}
+
 // cqHandler signals the controller to reconcile the resourceFlavor
 // associated to the clusterQueue in the event.
 // Since the events come from a channel Source, only the Generic handler will
@@ -223,15 +259,17 @@ func (h *cqHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
 // For update events, we only check the old obj to see whether old resourceFlavors
 // are still in use since new resourceFlavors are always in use.
 // For delete events, we check the original obj since new obj is nil.
+// The resourceFlavors being deleted are reconciled in any case, to update the
+// ClusterQueues that block their deletion.
 func (h *cqHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
 	cq := e.Object.(*kueue.ClusterQueue)
 	if cq.Name == "" {
 		return
 	}
 
-	for _, resource := range cq.Spec.Resources {
-		for _, flavor := range resource.Flavors {
-			if cqs := h.cache.ClusterQueuesUsingFlavor(string(flavor.Name)); len(cqs) == 0 {
+	for _, rg := range cq.Spec.EffectiveResourceGroups() {
+		for _, flavor := range rg.Flavors {
+			if cqs := h.cache.ClusterQueuesUsingFlavor(string(flavor.Name)); len(cqs) == 0 || h.cache.ResourceFlavorTerminating(string(flavor.Name)) {
 				req := reconcile.Request{
 					NamespacedName: types.NamespacedName{
 						Name: string(flavor.Name),
@@ -243,6 +281,25 @@ func (h *cqHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterf
 	}
 }
 
+// rfWorkloadHandler signals the controller to reconcile the resourceFlavor in
+// the event, sent when a workload that reserved quota in it is updated.
+// Since the events come from a channel Source, only the Generic handler will
+// receive events.
+type rfWorkloadHandler struct{}
+
+func (h *rfWorkloadHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *rfWorkloadHandler) Update(event.UpdateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *rfWorkloadHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *rfWorkloadHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: e.Object.GetName()}})
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ResourceFlavorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	handler := cqHandler{
@@ -251,14 +308,15 @@ func (r *ResourceFlavorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kueue.ResourceFlavor{}).
 		Watches(&source.Channel{Source: r.cqUpdateCh}, &handler).
+		Watches(&source.Channel{Source: r.wlUpdateCh}, &rfWorkloadHandler{}).
 		WithEventFilter(r).
 		Complete(r)
 }
 
 func resourceFlavors(cq *kueue.ClusterQueue) sets.Set[kueue.ResourceFlavorReference] {
 	flavors := sets.New[kueue.ResourceFlavorReference]()
-	for _, resource := range cq.Spec.Resources {
-		for _, flavor := range resource.Flavors {
+	for _, rg := range cq.Spec.EffectiveResourceGroups() {
+		for _, flavor := range rg.Flavors {
 			flavors.Insert(flavor.Name)
 		}
 	}
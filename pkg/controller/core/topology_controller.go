@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+)
+
+// TopologyReconciler tracks the levels of the Topology objects, so that the
+// pod sets can be admitted into the domains of the flavors' topologies.
+type TopologyReconciler struct {
+	log      logr.Logger
+	qManager *queue.Manager
+	cache    *cache.Cache
+	client   client.Client
+}
+
+func NewTopologyReconciler(
+	client client.Client,
+	qMgr *queue.Manager,
+	cache *cache.Cache,
+) *TopologyReconciler {
+	return &TopologyReconciler{
+		log:      ctrl.Log.WithName("topology-reconciler"),
+		cache:    cache,
+		client:   client,
+		qManager: qMgr,
+	}
+}
+
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=topologies,verbs=get;list;watch
+
+// Reconcile is a no-op, as the events for Topology objects are fully handled
+// by the event filters, which update the cache and the queues.
+func (r *TopologyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return ctrl.Result{}, nil
+}
+
+func (r *TopologyReconciler) Create(e event.CreateEvent) bool {
+	topology, match := e.Object.(*kueue.Topology)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("topology", klog.KObj(topology))
+	log.V(2).Info("Topology create event")
+	if cqNames := r.cache.AddOrUpdateTopology(topology); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+	}
+	return false
+}
+
+func (r *TopologyReconciler) Delete(e event.DeleteEvent) bool {
+	topology, match := e.Object.(*kueue.Topology)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("topology", klog.KObj(topology))
+	log.V(2).Info("Topology delete event")
+	if cqNames := r.cache.DeleteTopology(topology); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+	}
+	return false
+}
+
+func (r *TopologyReconciler) Update(e event.UpdateEvent) bool {
+	topology, match := e.ObjectNew.(*kueue.Topology)
+	if !match {
+		return false
+	}
+	log := r.log.WithValues("topology", klog.KObj(topology))
+	log.V(2).Info("Topology update event")
+	if cqNames := r.cache.AddOrUpdateTopology(topology); len(cqNames) > 0 {
+		r.qManager.QueueInadmissibleWorkloads(context.Background(), cqNames)
+	}
+	return false
+}
+
+func (r *TopologyReconciler) Generic(e event.GenericEvent) bool {
+	r.log.V(3).Info("Ignore generic event", "obj", klog.KObj(e.Object), "kind", e.Object.GetObjectKind().GroupVersionKind())
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TopologyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kueue.Topology{}).
+		WithEventFilter(r).
+		Complete(r)
+}
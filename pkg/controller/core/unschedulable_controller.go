@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// UnschedulableReconciler evicts the admitted workloads whose pods are
+// unschedulable for longer than a timeout because no node matches a flavor
+// assigned to them anymore, and excludes the flavor from their next
+// admission, so that they get a fresh flavor assignment.
+type UnschedulableReconciler struct {
+	log      logr.Logger
+	client   client.Client
+	recorder record.EventRecorder
+	timeout  time.Duration
+}
+
+func NewUnschedulableReconciler(client client.Client, recorder record.EventRecorder, timeout time.Duration) *UnschedulableReconciler {
+	return &UnschedulableReconciler{
+		log:      ctrl.Log.WithName("unschedulable-reconciler"),
+		client:   client,
+		recorder: recorder,
+		timeout:  timeout,
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+
+func (r *UnschedulableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pod corev1.Pod
+	if err := r.client.Get(ctx, req.NamespacedName, &pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	cond := unschedulableCondition(&pod)
+	if cond == nil {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("pod", klog.KObj(&pod))
+	ctx = ctrl.LoggerInto(ctx, log)
+	if remaining := cond.LastTransitionTime.Add(r.timeout).Sub(realClock.Now()); remaining > 0 {
+		log.V(4).Info("Pod is unschedulable and did not exceed the timeout", "recheckAfter", remaining)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.V(2).Info("Reconciling unschedulable Pod")
+
+	wls, err := podWorkloads(ctx, r.client, &pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range wls {
+		if err := r.evictUnschedulable(ctx, &wls[i], &pod); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// evictUnschedulable evicts the workload, if no node matches a flavor of its
+// current admission that the pod uses, and excludes those flavors for its next
+// admission.
+func (r *UnschedulableReconciler) evictUnschedulable(ctx context.Context, wl *kueue.Workload, pod *corev1.Pod) error {
+	if wl.Spec.Admission == nil || workload.IsEvicted(wl) || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+		return nil
+	}
+	// The pods of a previous admission don't evict the workload again.
+	if reserved := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadQuotaReserved); reserved == nil ||
+		reserved.Status != metav1.ConditionTrue || pod.CreationTimestamp.Before(&reserved.LastTransitionTime) {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("workload", klog.KObj(wl))
+	names, err := r.flavorsWithoutNodes(ctx, wl, pod)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		log.V(3).Info("The flavors of the unschedulable pod have nodes, keeping the workload")
+		return nil
+	}
+	retries := make(map[string]int32, len(names))
+	for _, name := range names {
+		retries[name] = 1
+	}
+	if workload.ExcludeFlavors(wl, retries) {
+		log.V(2).Info("Excluding the flavors without nodes of the workload", "excludedFlavors", wl.Status.ExcludedFlavors)
+		if err := r.client.Status().Update(ctx, wl); err != nil {
+			return err
+		}
+	}
+	msg := fmt.Sprintf("The pod %s was unschedulable for more than %s, and no node matches the flavor %s", pod.Name, r.timeout, strings.Join(names, ", "))
+	log.V(2).Info("Evicting the workload due to an unschedulable pod")
+	if err := workload.Evict(ctx, r.client, wl, kueue.WorkloadEvictedByUnschedulablePods, msg); err != nil {
+		return err
+	}
+	r.recorder.Event(wl, corev1.EventTypeNormal, kueue.WorkloadEvictedByUnschedulablePods, msg)
+	return nil
+}
+
+// flavorsWithoutNodes returns the names of the flavors of the admission of
+// the workload, whose node selector the pod has, that no node matches.
+func (r *UnschedulableReconciler) flavorsWithoutNodes(ctx context.Context, wl *kueue.Workload, pod *corev1.Pod) ([]string, error) {
+	var names []string
+	checked := make(map[string]bool)
+	for _, psFlavors := range wl.Spec.Admission.PodSetFlavors {
+		for _, name := range psFlavors.Flavors {
+			if checked[name] {
+				continue
+			}
+			checked[name] = true
+			var rf kueue.ResourceFlavor
+			if err := r.client.Get(ctx, types.NamespacedName{Name: name}, &rf); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			// A flavor without a node selector matches every node.
+			if len(rf.NodeSelector) == 0 || !selectsPod(&rf, pod) {
+				continue
+			}
+			var nodes corev1.NodeList
+			if err := r.client.List(ctx, &nodes, client.MatchingLabels(rf.NodeSelector)); err != nil {
+				return nil, err
+			}
+			if len(nodes.Items) == 0 {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// unschedulableCondition returns the PodScheduled condition of the pod, if
+// the scheduler couldn't find a node for it.
+func unschedulableCondition(pod *corev1.Pod) *corev1.PodCondition {
+	if pod.Spec.NodeName != "" || pod.Status.Phase != corev1.PodPending {
+		return nil
+	}
+	for i := range pod.Status.Conditions {
+		c := &pod.Status.Conditions[i]
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+			return c
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager. Only the
+// unschedulable pods are reconciled.
+func (r *UnschedulableReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("unschedulable").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			pod, ok := o.(*corev1.Pod)
+			return ok && unschedulableCondition(pod) != nil
+		}))).
+		Complete(r)
+}
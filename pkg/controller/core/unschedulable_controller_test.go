@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestUnschedulableCondition(t *testing.T) {
+	cases := map[string]struct {
+		pod  corev1.Pod
+		want bool
+	}{
+		"unschedulable pod": {
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodScheduled,
+						Status: corev1.ConditionFalse,
+						Reason: corev1.PodReasonUnschedulable,
+					}},
+				},
+			},
+			want: true,
+		},
+		"pending pod without the condition": {
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{Phase: corev1.PodPending},
+			},
+		},
+		"scheduling gated pod": {
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodScheduled,
+						Status: corev1.ConditionFalse,
+						Reason: "SchedulingGated",
+					}},
+				},
+			},
+		},
+		"scheduled pod": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "node"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodScheduled,
+						Status: corev1.ConditionTrue,
+					}},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := unschedulableCondition(&tc.pod) != nil; got != tc.want {
+				t.Errorf("unschedulableCondition() != nil is %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFlavorsWithoutNodes(t *testing.T) {
+	flavors := []*kueue.ResourceFlavor{
+		utiltesting.MakeResourceFlavor("on-demand").Label("instance-type", "on-demand").Obj(),
+		utiltesting.MakeResourceFlavor("spot").Label("instance-type", "spot").Obj(),
+		utiltesting.MakeResourceFlavor("default").Obj(),
+	}
+	nodes := []*corev1.Node{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "on-demand-1",
+			Labels: map[string]string{"instance-type": "on-demand"},
+		},
+	}}
+	cases := map[string]struct {
+		admission    *kueue.Admission
+		nodeSelector map[string]string
+		want         []string
+	}{
+		"flavor with nodes": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "on-demand").
+				Obj(),
+			nodeSelector: map[string]string{"instance-type": "on-demand"},
+		},
+		"flavor without nodes": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "spot").
+				Flavor(corev1.ResourceMemory, "spot").
+				Obj(),
+			nodeSelector: map[string]string{"instance-type": "spot"},
+			want:         []string{"spot"},
+		},
+		"flavor without nodes not used by the pod": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "spot").
+				Obj(),
+			nodeSelector: map[string]string{"instance-type": "on-demand"},
+		},
+		"flavor without node selector": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "default").
+				Obj(),
+		},
+		"deleted flavor": {
+			admission: utiltesting.MakeAdmission("cq").
+				Flavor(corev1.ResourceCPU, "spot-deleted").
+				Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t))
+			for _, rf := range flavors {
+				builder = builder.WithObjects(rf)
+			}
+			for _, n := range nodes {
+				builder = builder.WithObjects(n)
+			}
+			r := NewUnschedulableReconciler(builder.Build(), record.NewFakeRecorder(10), 5*time.Minute)
+			wl := utiltesting.MakeWorkload("wl", "ns").Admit(tc.admission).Obj()
+			pod := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: tc.nodeSelector}}
+			got, err := r.flavorsWithoutNodes(context.Background(), wl, pod)
+			if err != nil {
+				t.Fatalf("flavorsWithoutNodes failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected flavors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
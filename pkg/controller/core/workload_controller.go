@@ -19,23 +19,34 @@ package core
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/cache"
-	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
+	"sigs.k8s.io/kueue/pkg/util/api"
+	"sigs.k8s.io/kueue/pkg/util/priority"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -63,9 +74,15 @@ var (
 	realClock = clock.RealClock{}
 )
 
+// finishedByKueueReasons are the reasons of the Finished condition of the
+// workloads that Kueue finishes without their jobs finishing.
+var finishedByKueueReasons = sets.New("AdmissionCheckRejected", "ReservationExpired", kueue.WorkloadAdmissionDeadlineExceeded)
+
 type options struct {
-	watchers         []WorkloadUpdateWatcher
-	podsReadyTimeout *time.Duration
+	watchers            []WorkloadUpdateWatcher
+	podsReadyTimeout    *time.Duration
+	requeuingLimitCount *int32
+	finishedRetention   *time.Duration
 }
 
 // Option configures the reconciler.
@@ -79,6 +96,23 @@ func WithPodsReadyTimeout(value *time.Duration) Option {
 	}
 }
 
+// WithRequeuingLimitCount indicates the number of times that a workload can be
+// evicted due to the PodsReady timeout or to an admission check to retry
+// before it's deactivated.
+func WithRequeuingLimitCount(value *int32) Option {
+	return func(o *options) {
+		o.requeuingLimitCount = value
+	}
+}
+
+// WithFinishedRetention indicates the time after which the finished workloads
+// are deleted.
+func WithFinishedRetention(value *time.Duration) Option {
+	return func(o *options) {
+		o.finishedRetention = value
+	}
+}
+
 // WithWorkloadUpdateWatchers allows to specify the workload update watchers
 func WithWorkloadUpdateWatchers(value ...WorkloadUpdateWatcher) Option {
 	return func(o *options) {
@@ -94,27 +128,37 @@ type WorkloadUpdateWatcher interface {
 
 // WorkloadReconciler reconciles a Workload object
 type WorkloadReconciler struct {
-	log              logr.Logger
-	queues           *queue.Manager
-	cache            *cache.Cache
-	client           client.Client
-	watchers         []WorkloadUpdateWatcher
-	podsReadyTimeout *time.Duration
+	log                 logr.Logger
+	queues              *queue.Manager
+	cache               *cache.Cache
+	client              client.Client
+	recorder            record.EventRecorder
+	watchers            []WorkloadUpdateWatcher
+	podsReadyTimeout    *time.Duration
+	requeuingLimitCount *int32
+	finishedRetention   *time.Duration
+	cqUpdateCh          chan event.GenericEvent
+	rfUpdateCh          chan event.GenericEvent
 }
 
-func NewWorkloadReconciler(client client.Client, queues *queue.Manager, cache *cache.Cache, opts ...Option) *WorkloadReconciler {
+func NewWorkloadReconciler(client client.Client, queues *queue.Manager, cache *cache.Cache, recorder record.EventRecorder, opts ...Option) *WorkloadReconciler {
 	options := defaultOptions
 	for _, opt := range opts {
 		opt(&options)
 	}
 
 	return &WorkloadReconciler{
-		log:              ctrl.Log.WithName("workload-reconciler"),
-		client:           client,
-		queues:           queues,
-		cache:            cache,
-		watchers:         options.watchers,
-		podsReadyTimeout: options.podsReadyTimeout,
+		log:                 ctrl.Log.WithName("workload-reconciler"),
+		client:              client,
+		queues:              queues,
+		cache:               cache,
+		recorder:            recorder,
+		watchers:            options.watchers,
+		podsReadyTimeout:    options.podsReadyTimeout,
+		requeuingLimitCount: options.requeuingLimitCount,
+		finishedRetention:   options.finishedRetention,
+		cqUpdateCh:          make(chan event.GenericEvent, updateChBuffer),
+		rfUpdateCh:          make(chan event.GenericEvent, updateChBuffer),
 	}
 }
 
@@ -123,6 +167,7 @@ func NewWorkloadReconciler(client client.Client, queues *queue.Manager, cache *c
 //+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/finalizers,verbs=update
 //+kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list;watch
 
 func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	var wl kueue.Workload
@@ -137,41 +182,310 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	status := workloadStatus(&wl)
 	switch status {
 	case pending:
-		if !r.queues.QueueForWorkloadExists(&wl) {
-			err := workload.UpdateStatusIfChanged(ctx, r.client, &wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
-				"Inadmissible", fmt.Sprintf("LocalQueue %s doesn't exist", wl.Spec.QueueName))
-			return ctrl.Result{}, client.IgnoreNotFound(err)
+		remaining, hasDeadline := admissionDeadlineRemaining(&wl, realClock)
+		if hasDeadline && remaining <= 0 {
+			return r.reconcileDeadlineExceeded(ctx, &wl)
+		}
+		result, err := r.reconcilePending(ctx, &wl)
+		if err == nil && hasDeadline && (result.RequeueAfter == 0 || remaining < result.RequeueAfter) {
+			log.V(4).Info("The workload is pending with an admission deadline", "recheckAfter", remaining)
+			result.RequeueAfter = remaining
+		}
+		return result, err
+	case cancellingAdmission:
+		if err := r.cancelGroupAdmission(ctx, &wl); err != nil {
+			return ctrl.Result{}, err
 		}
+		return r.reconcileAdmissionCancelled(ctx, &wl)
+	case admitted:
+		return r.reconcileAdmitted(ctx, req, &wl)
+	case finished:
+		return r.reconcileFinished(ctx, &wl)
+	}
 
-		cqName, cqOk := r.queues.ClusterQueueForWorkload(&wl)
-		if !cqOk {
-			err := workload.UpdateStatusIfChanged(ctx, r.client, &wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
-				"Inadmissible", fmt.Sprintf("ClusterQueue %s doesn't exist", cqName))
-			return ctrl.Result{}, client.IgnoreNotFound(err)
+	return ctrl.Result{}, nil
+}
+
+// reconcilePending updates the conditions of a pending workload that explain
+// why it can't be admitted.
+func (r *WorkloadReconciler) reconcilePending(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadQuotaReserved) || len(wl.Status.AdmissionChecks) > 0 || len(wl.Status.ResourceUsage) > 0 {
+		return r.reconcileQuotaReleased(ctx, wl)
+	}
+	if !workload.IsActive(wl) {
+		if r.requeuingLimitExceeded(wl) || maximumExecutionTimeExceeded(wl) {
+			// The Admitted condition already explains why the workload
+			// was deactivated.
+			return ctrl.Result{}, nil
 		}
+		err := workload.UpdateStatusIfChanged(ctx, r.client, wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
+			"Inactive", "The workload is deactivated")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if r.requeuingLimitExceeded(wl) {
+		log.V(2).Info("Resetting the evictions of the reactivated workload")
+		wl.Status.RequeueState.Evictions = nil
+		if wl.Status.RequeueState.Count == nil && wl.Status.RequeueState.RequeueAt == nil {
+			wl.Status.RequeueState = nil
+		}
+		err := r.client.Status().Update(ctx, wl)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !r.queues.QueueForWorkloadExists(wl) {
+		err := workload.UpdateStatusIfChanged(ctx, r.client, wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
+			"Inadmissible", fmt.Sprintf("LocalQueue %s doesn't exist", wl.Spec.QueueName))
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
 
-		if !r.cache.ClusterQueueActive(cqName) {
-			err := workload.UpdateStatusIfChanged(ctx, r.client, &wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
-				"Inadmissible", fmt.Sprintf("ClusterQueue %s is inactive", cqName))
-			return ctrl.Result{}, client.IgnoreNotFound(err)
+	cqName, cqOk := r.queues.ClusterQueueForWorkload(wl)
+	if !cqOk {
+		err := workload.UpdateStatusIfChanged(ctx, r.client, wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
+			"Inadmissible", fmt.Sprintf("ClusterQueue %s doesn't exist", cqName))
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !r.cache.ClusterQueueActive(cqName) {
+		msg := fmt.Sprintf("ClusterQueue %s is inactive", cqName)
+		if r.cache.ClusterQueueStopPolicy(cqName) != kueue.None {
+			msg = fmt.Sprintf("ClusterQueue %s is stopped", cqName)
 		}
-	case cancellingAdmission:
-		err := workload.UpdateStatusIfChanged(ctx, r.client, &wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
-			"AdmissionCancelled", "Admission cancelled")
+		err := workload.UpdateStatusIfChanged(ctx, r.client, wl, kueue.WorkloadAdmitted, metav1.ConditionFalse,
+			"Inadmissible", msg)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
-	case admitted:
-		if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadAdmitted) {
-			return r.reconcileNotReadyTimeout(ctx, req, &wl)
-		} else {
-			msg := fmt.Sprintf("Admitted by ClusterQueue %s", wl.Spec.Admission.ClusterQueue)
-			err := workload.UpdateStatusIfChanged(ctx, r.client, &wl, kueue.WorkloadAdmitted, metav1.ConditionTrue, "AdmissionByKueue", msg)
+	}
+
+	if wl.Status.RequeueState != nil && wl.Status.RequeueState.RequeueAt != nil {
+		return r.reconcileRequeuingBackoff(ctx, wl)
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileAdmitted sets the QuotaReserved condition and the states of the
+// admission checks of a workload that has an admission, and sets the Admitted
+// condition once all the checks are Ready.
+// If any of the checks is Rejected, the workload is finished. Otherwise, if
+// any of the checks is Retry, the workload is evicted and, once its admission
+// is cleared, requeued with all its checks reset to Pending.
+func (r *WorkloadReconciler) reconcileAdmitted(ctx context.Context, req ctrl.Request, wl *kueue.Workload) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if workload.IsEvicted(wl) {
+		return r.reconcileEvicted(ctx, wl)
+	}
+	if !workload.IsActive(wl) {
+		log.V(2).Info("Evicting the deactivated workload")
+		return r.evict(ctx, wl, kueue.WorkloadEvictedByDeactivation, "The workload is deactivated")
+	}
+	if r.stoppedClusterQueueEvicts(wl) {
+		log.V(2).Info("Evicting the workload due to the stopped ClusterQueue")
+		return r.evict(ctx, wl, kueue.WorkloadEvictedByClusterQueueStopped, fmt.Sprintf("Evicted because the ClusterQueue %s is stopped", wl.Spec.Admission.ClusterQueue))
+	}
+	if r.cache.WorkloadInDeletedFlavor(wl) {
+		log.V(2).Info("Evicting the workload due to a deleted ResourceFlavor")
+		return r.evict(ctx, wl, kueue.WorkloadEvictedByResourceFlavorDeleted, "Evicted because a flavor assigned to the workload was deleted")
+	}
+	if r.cache.WorkloadOverQuota(wl) {
+		log.V(2).Info("Evicting the workload due to the usage of the ClusterQueue exceeding its quota")
+		return r.evict(ctx, wl, kueue.WorkloadEvictedByClusterQueueOverQuota, fmt.Sprintf("Evicted to bring the usage of the ClusterQueue %s back under its quota", wl.Spec.Admission.ClusterQueue))
+	}
+	if check := workload.FirstCheckInState(wl, kueue.CheckStateRejected); check != nil {
+		log.V(2).Info("Finishing the workload due to a rejected admission check", "admissionCheck", check.Name)
+		apimeta.SetStatusCondition(&wl.Status.Conditions, metav1.Condition{
+			Type:    kueue.WorkloadFinished,
+			Status:  metav1.ConditionTrue,
+			Reason:  "AdmissionCheckRejected",
+			Message: api.TruncateConditionMessage(fmt.Sprintf("The admission check %s rejected the workload: %s", check.Name, check.Message)),
+		})
+		err := r.client.Status().Update(ctx, wl)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if check := workload.FirstCheckInState(wl, kueue.CheckStateRetry); check != nil {
+		log.V(2).Info("Evicting the workload due to an admission check to retry", "admissionCheck", check.Name)
+		return r.evict(ctx, wl, kueue.WorkloadEvictedByAdmissionCheck, fmt.Sprintf("The admission check %s requested a retry: %s", check.Name, check.Message))
+	}
+
+	changed := workload.SyncAdmissionChecks(wl)
+	if usage := r.cache.NewWorkloadInfo(wl).ResourceUsage(); !equality.Semantic.DeepEqual(usage, wl.Status.ResourceUsage) {
+		wl.Status.ResourceUsage = usage
+		changed = true
+	}
+	if !apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadQuotaReserved) {
+		apimeta.SetStatusCondition(&wl.Status.Conditions, metav1.Condition{
+			Type:    kueue.WorkloadQuotaReserved,
+			Status:  metav1.ConditionTrue,
+			Reason:  "QuotaReserved",
+			Message: fmt.Sprintf("Quota reserved in ClusterQueue %s", wl.Spec.Admission.ClusterQueue),
+		})
+		wl.Status.InadmissibleReasons = nil
+		// The admission was a retry without the excluded flavors.
+		workload.ConsumeExclusionRetry(wl)
+		changed = true
+	}
+	if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadAdmitted) {
+		if changed {
+			err := r.client.Status().Update(ctx, wl)
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
+		remaining, limited, err := r.executionTimeRemaining(ctx, wl)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if limited && remaining <= 0 {
+			return r.reconcileMaximumExecutionTimeExceeded(ctx, wl)
+		}
+		var result ctrl.Result
+		if workload.IsReservation(wl) {
+			result, err = r.reconcileReservation(ctx, wl)
+		} else {
+			result, err = r.reconcileNotReadyTimeout(ctx, req, wl)
+		}
+		if err == nil && limited && (result.RequeueAfter == 0 || remaining < result.RequeueAfter) {
+			log.V(4).Info("The workload did not exceed its maximum execution time", "recheckAfter", remaining)
+			result.RequeueAfter = remaining
+		}
+		return result, err
 	}
 
+	var admittedCond metav1.Condition
+	if workload.HasAllChecksReady(wl) {
+		admittedCond = metav1.Condition{
+			Type:    kueue.WorkloadAdmitted,
+			Status:  metav1.ConditionTrue,
+			Reason:  "AdmissionByKueue",
+			Message: fmt.Sprintf("Admitted by ClusterQueue %s", wl.Spec.Admission.ClusterQueue),
+		}
+		// The backoff only accounts for consecutive failed admission attempts.
+		if s := wl.Status.RequeueState; s != nil {
+			s.Count = nil
+			s.RequeueAt = nil
+			if s.Evictions == nil {
+				wl.Status.RequeueState = nil
+			}
+		}
+	} else {
+		admittedCond = metav1.Condition{
+			Type:    kueue.WorkloadAdmitted,
+			Status:  metav1.ConditionFalse,
+			Reason:  "AdmissionChecksPending",
+			Message: fmt.Sprintf("Waiting for the admission checks %s", strings.Join(wl.Spec.Admission.AdmissionChecks, ", ")),
+		}
+	}
+	if !apimeta.IsStatusConditionPresentAndEqual(wl.Status.Conditions, admittedCond.Type, admittedCond.Status) {
+		apimeta.SetStatusCondition(&wl.Status.Conditions, admittedCond)
+		changed = true
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+	err := r.client.Status().Update(ctx, wl)
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
+// stoppedClusterQueueEvicts returns whether the stop policy of the
+// ClusterQueue in which the workload reserved quota requires releasing it.
+// The Hold policy only releases the quota of the workloads that are still
+// waiting for their admission checks, while HoldAndDrain also evicts the
+// admitted workloads.
+func (r *WorkloadReconciler) stoppedClusterQueueEvicts(wl *kueue.Workload) bool {
+	switch r.cache.ClusterQueueStopPolicy(string(wl.Spec.Admission.ClusterQueue)) {
+	case kueue.HoldAndDrain:
+		return true
+	case kueue.Hold:
+		return !workload.IsAdmitted(wl)
+	}
+	return false
+}
+
+// evict sets the Evicted condition of a workload and records an event with
+// the given reason and message.
+func (r *WorkloadReconciler) evict(ctx context.Context, wl *kueue.Workload, reason, msg string) (ctrl.Result, error) {
+	if err := workload.Evict(ctx, r.client, wl, reason, msg); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	r.recorder.Event(wl, corev1.EventTypeNormal, reason, msg)
 	return ctrl.Result{}, nil
 }
 
+// reconcileEvicted clears the admission of an evicted workload that isn't
+// controlled by a job. The admission of the workload of a job is cleared by
+// the job controller, once the job is suspended.
+func (r *WorkloadReconciler) reconcileEvicted(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if metav1.GetControllerOf(wl) != nil {
+		log.V(3).Info("Waiting for the job of the evicted workload to be suspended")
+		return ctrl.Result{}, nil
+	}
+	log.V(2).Info("Clearing the admission of the evicted workload")
+	err := workload.ClearAdmission(ctx, r.client, wl)
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
+// reconcileAdmissionCancelled sets the Admitted condition of a workload whose
+// admission was cleared to False. If the workload was evicted, the reason of
+// the eviction is kept in the Admitted condition and the Evicted condition is
+// set to False, so that the workload is requeued.
+// The evictions of workloads that failed to start are counted and, once they
+// exceed the requeuing limit, the workload is deactivated.
+func (r *WorkloadReconciler) reconcileAdmissionCancelled(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	reason, msg := "AdmissionCancelled", "Admission cancelled"
+	if evicted := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadEvicted); evicted != nil && evicted.Status == metav1.ConditionTrue {
+		reason, msg = evicted.Reason, evicted.Message
+		accumulateExecutionTime(wl, evicted)
+		if countsTowardsRequeuingLimit(reason) {
+			evictions := int32(1)
+			if wl.Status.RequeueState != nil && wl.Status.RequeueState.Evictions != nil {
+				evictions += *wl.Status.RequeueState.Evictions
+			}
+			if r.requeuingLimitCount != nil && evictions > *r.requeuingLimitCount {
+				if workload.IsActive(wl) {
+					ctrl.LoggerFrom(ctx).V(2).Info("Deactivating the workload due to exceeding the requeuing limit", "evictions", evictions)
+					wl.Spec.Active = pointer.Bool(false)
+					if err := r.client.Update(ctx, wl); err != nil {
+						return ctrl.Result{}, client.IgnoreNotFound(err)
+					}
+				}
+				reason = kueue.WorkloadRequeuingLimitExceeded
+				msg = fmt.Sprintf("Deactivated after being evicted %d times, the last one with the reason %s: %s", evictions, evicted.Reason, evicted.Message)
+				r.recorder.Event(wl, corev1.EventTypeWarning, reason, msg)
+			}
+			if wl.Status.RequeueState == nil {
+				wl.Status.RequeueState = &kueue.RequeueState{}
+			}
+			wl.Status.RequeueState.Evictions = &evictions
+		}
+		apimeta.SetStatusCondition(&wl.Status.Conditions, metav1.Condition{
+			Type:    kueue.WorkloadEvicted,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Requeued",
+			Message: "The workload was requeued after its eviction",
+		})
+	}
+	apimeta.SetStatusCondition(&wl.Status.Conditions, metav1.Condition{
+		Type:    kueue.WorkloadAdmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: msg,
+	})
+	err := r.client.Status().Update(ctx, wl)
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
+// reconcileQuotaReleased clears the QuotaReserved condition, the states of the
+// admission checks and the resource usage of a workload that no longer has an
+// admission.
+func (r *WorkloadReconciler) reconcileQuotaReleased(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	workload.SyncAdmissionChecks(wl)
+	wl.Status.ResourceUsage = nil
+	apimeta.SetStatusCondition(&wl.Status.Conditions, metav1.Condition{
+		Type:    kueue.WorkloadQuotaReserved,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Pending",
+		Message: "The workload has no reservation",
+	})
+	err := r.client.Status().Update(ctx, wl)
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
 func (r *WorkloadReconciler) reconcileNotReadyTimeout(ctx context.Context, req ctrl.Request, wl *kueue.Workload) (ctrl.Result, error) {
 	countingTowardsTimeout, recheckAfter := r.admittedNotReadyWorkload(wl, realClock)
 	if !countingTowardsTimeout {
@@ -181,10 +495,164 @@ func (r *WorkloadReconciler) reconcileNotReadyTimeout(ctx context.Context, req c
 		klog.V(4).InfoS("Workload not yet ready and did not exceed its timeout", "workload", req.NamespacedName.String(), "recheckAfter", recheckAfter)
 		return ctrl.Result{RequeueAfter: recheckAfter}, nil
 	} else {
-		klog.V(2).InfoS("Cancelling admission of the workload due to exceeding the PodsReady timeout", "workload", req.NamespacedName.String())
-		err := r.client.Patch(ctx, workload.ClearAdmissionPatch(wl), client.Apply, client.FieldOwner(constants.AdmissionName))
+		klog.V(2).InfoS("Evicting the workload due to exceeding the PodsReady timeout", "workload", req.NamespacedName.String())
+		return r.evict(ctx, wl, kueue.WorkloadEvictedByPodsReadyTimeout, fmt.Sprintf("Exceeded the PodsReady timeout %s", r.podsReadyTimeout))
+	}
+}
+
+// reconcileReservation finishes an admitted workload that only reserves quota
+// once its reservation expires, which releases the quota.
+func (r *WorkloadReconciler) reconcileReservation(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	remaining, expires := reservationRemaining(wl, realClock)
+	if !expires {
+		return ctrl.Result{}, nil
+	}
+	if remaining > 0 {
+		log.V(4).Info("The reservation of the workload did not expire yet", "recheckAfter", remaining)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.V(2).Info("Finishing the workload due to its expired reservation")
+	msg := fmt.Sprintf("The reservation expired %ds after the admission", *wl.Spec.Reservation.TTLSecondsAfterAdmitted)
+	apimeta.SetStatusCondition(&wl.Status.Conditions, metav1.Condition{
+		Type:    kueue.WorkloadFinished,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ReservationExpired",
+		Message: msg,
+	})
+	if err := r.client.Status().Update(ctx, wl); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	r.recorder.Event(wl, corev1.EventTypeNormal, "ReservationExpired", msg)
+	return ctrl.Result{}, nil
+}
+
+// reconcileMaximumExecutionTimeExceeded deactivates and evicts an admitted
+// workload that exceeded its maximum execution time.
+func (r *WorkloadReconciler) reconcileMaximumExecutionTimeExceeded(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	ctrl.LoggerFrom(ctx).V(2).Info("Deactivating the workload due to exceeding its maximum execution time")
+	wl.Spec.Active = pointer.Bool(false)
+	if err := r.client.Update(ctx, wl); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	return r.evict(ctx, wl, kueue.WorkloadEvictedByMaximumExecutionTimeExceeded, "Deactivated after exceeding its maximum execution time")
+}
+
+// executionTimeRemaining returns the time until an admitted workload exceeds
+// its maximum execution time, and whether it has a maximum execution time at
+// all. The maximum execution time of the workload defaults to the one of its
+// LocalQueue and then to the one of its ClusterQueue.
+func (r *WorkloadReconciler) executionTimeRemaining(ctx context.Context, wl *kueue.Workload) (time.Duration, bool, error) {
+	limit := wl.Spec.MaximumExecutionTimeSeconds
+	if limit == nil {
+		var lq kueue.LocalQueue
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: wl.Namespace, Name: wl.Spec.QueueName}, &lq); client.IgnoreNotFound(err) != nil {
+			return 0, false, err
+		}
+		limit = lq.Spec.MaximumExecutionTimeSeconds
+	}
+	if limit == nil {
+		var cq kueue.ClusterQueue
+		if err := r.client.Get(ctx, types.NamespacedName{Name: string(wl.Spec.Admission.ClusterQueue)}, &cq); client.IgnoreNotFound(err) != nil {
+			return 0, false, err
+		}
+		limit = cq.Spec.MaximumExecutionTimeSeconds
+	}
+	if limit == nil {
+		return 0, false, nil
+	}
+	remaining, _ := executionTimeRemaining(wl, *limit, realClock)
+	return remaining, true, nil
+}
+
+// reconcileDeadlineExceeded finishes a pending workload whose admission
+// deadline passed, so that it's not admitted anymore.
+func (r *WorkloadReconciler) reconcileDeadlineExceeded(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	ctrl.LoggerFrom(ctx).V(2).Info("Finishing the workload due to its admission deadline")
+	msg := fmt.Sprintf("The workload wasn't admitted before its admission deadline %s", wl.Spec.AdmissionDeadline.UTC().Format(time.RFC3339))
+	apimeta.SetStatusCondition(&wl.Status.Conditions, metav1.Condition{
+		Type:    kueue.WorkloadFinished,
+		Status:  metav1.ConditionTrue,
+		Reason:  kueue.WorkloadAdmissionDeadlineExceeded,
+		Message: msg,
+	})
+	if err := r.client.Status().Update(ctx, wl); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	r.recorder.Event(wl, corev1.EventTypeWarning, kueue.WorkloadAdmissionDeadlineExceeded, msg)
+	return ctrl.Result{}, nil
+}
+
+// reconcileFinished deletes a finished workload once the retention period
+// after it finished passes. The workloads that Kueue finished while their
+// jobs didn't are kept, as the jobs would get new workloads otherwise, and
+// they are deleted with their jobs.
+func (r *WorkloadReconciler) reconcileFinished(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	if r.finishedRetention == nil {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	finishedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadFinished)
+	if metav1.GetControllerOf(wl) != nil && finishedByKueueReasons.Has(finishedCond.Reason) {
+		log.V(3).Info("Keeping the finished workload of a job that didn't finish")
+		return ctrl.Result{}, nil
+	}
+	if remaining := finishedCond.LastTransitionTime.Add(*r.finishedRetention).Sub(realClock.Now()); remaining > 0 {
+		log.V(4).Info("The finished workload did not exceed its retention period", "recheckAfter", remaining)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.V(2).Info("Deleting the finished workload after its retention period")
+	err := r.client.Delete(ctx, wl)
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
+// cancelGroupAdmission evicts the rest of the workloads of the group of a
+// workload whose admission was cancelled.
+func (r *WorkloadReconciler) cancelGroupAdmission(ctx context.Context, wl *kueue.Workload) error {
+	if workload.GroupName(wl) == "" {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	for _, member := range r.cache.AdmittedGroupMembers(wl) {
+		if workload.IsEvicted(member) {
+			continue
+		}
+		log.V(2).Info("Evicting the workload group", "groupMember", klog.KObj(member))
+		msg := fmt.Sprintf("The admission of the workload %s of the group was cancelled", workload.Key(wl))
+		if err := workload.Evict(ctx, r.client, member, kueue.WorkloadEvictedByWorkloadGroup, msg); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		r.recorder.Event(member, corev1.EventTypeNormal, kueue.WorkloadEvictedByWorkloadGroup, msg)
+	}
+	return nil
+}
+
+// requeuingLimitExceeded returns whether the workload was evicted more times
+// than the requeuing limit allows.
+func (r *WorkloadReconciler) requeuingLimitExceeded(wl *kueue.Workload) bool {
+	s := wl.Status.RequeueState
+	return r.requeuingLimitCount != nil && s != nil && s.Evictions != nil && *s.Evictions > *r.requeuingLimitCount
+}
+
+// countsTowardsRequeuingLimit returns whether an eviction with the given
+// reason means that the workload failed to start.
+func countsTowardsRequeuingLimit(reason string) bool {
+	return reason == kueue.WorkloadEvictedByPodsReadyTimeout || reason == kueue.WorkloadEvictedByAdmissionCheck
+}
+
+// reconcileRequeuingBackoff clears the requeueAt time of a workload once its
+// requeuing backoff expires. The resulting update event moves the workload
+// back to the ClusterQueue heap.
+func (r *WorkloadReconciler) reconcileRequeuingBackoff(ctx context.Context, wl *kueue.Workload) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	if remaining := workload.BackoffRemaining(wl, realClock.Now()); remaining > 0 {
+		log.V(4).Info("Workload is waiting for its requeuing backoff to expire", "recheckAfter", remaining)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.V(2).Info("Requeuing backoff of the workload expired")
+	wl.Status.RequeueState.RequeueAt = nil
+	err := r.client.Status().Update(ctx, wl)
+	return ctrl.Result{}, client.IgnoreNotFound(err)
 }
 
 func (r *WorkloadReconciler) Create(e event.CreateEvent) bool {
@@ -200,6 +668,8 @@ func (r *WorkloadReconciler) Create(e event.CreateEvent) bool {
 
 	wlCopy := wl.DeepCopy()
 	handlePodOverhead(r.log, wlCopy, r.client)
+	handlePodLimitRange(r.log, wlCopy, r.client)
+	handleLimitsToRequests(wlCopy)
 
 	if wl.Spec.Admission == nil {
 		if !r.queues.AddOrUpdateWorkload(wlCopy) {
@@ -210,6 +680,9 @@ func (r *WorkloadReconciler) Create(e event.CreateEvent) bool {
 	if !r.cache.AddOrUpdateWorkload(wlCopy) {
 		log.V(2).Info("ClusterQueue for workload didn't exist; ignored for now")
 	}
+	if workload.HasPendingResize(wl) && !r.queues.AddOrUpdateWorkload(wlCopy) {
+		log.V(2).Info("Queue for workload didn't exist; ignored for now")
+	}
 
 	return true
 }
@@ -246,6 +719,9 @@ func (r *WorkloadReconciler) Delete(e event.DeleteEvent) bool {
 	// workload was in the queues and should be cleared from them.
 	if wl.Spec.Admission == nil {
 		r.queues.DeleteWorkload(wl)
+		r.cache.ReleaseQuota(wl)
+	} else if workload.HasPendingResize(wl) {
+		r.queues.DeleteWorkload(wl)
 	}
 	return true
 }
@@ -275,10 +751,13 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 		log = log.WithValues("prevClusterQueue", oldWl.Spec.Admission.ClusterQueue)
 	}
 	log.V(2).Info("Workload update event")
+	reportWaitTimeMetrics(oldWl, wl)
 
 	wlCopy := wl.DeepCopy()
 	// We do not handle old workload here as it will be deleted or replaced by new one anyway.
 	handlePodOverhead(r.log, wlCopy, r.client)
+	handlePodLimitRange(r.log, wlCopy, r.client)
+	handleLimitsToRequests(wlCopy)
 
 	switch {
 	case status == finished:
@@ -300,6 +779,11 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 			log.V(2).Info("Queue for updated workload didn't exist; ignoring for now")
 		}
 
+	case prevStatus == pending && status == cancellingAdmission:
+		// The workload was evicted after its admission was cleared. It is
+		// requeued once the workload controller resets the Evicted condition.
+		r.queues.DeleteWorkload(wl)
+
 	case prevStatus == pending && status == admitted:
 		r.queues.DeleteWorkload(oldWl)
 		if !r.cache.AddOrUpdateWorkload(wlCopy) {
@@ -312,9 +796,10 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 		// transition possible, triggered by the workload controller, is to the
 		// pending status. Scheduler is only able to re-admit the workload once
 		// requeued after reaching the pending status.
-	case prevStatus == cancellingAdmission && status == pending:
+	case (prevStatus == cancellingAdmission || prevStatus == admitted) && status == pending:
 		// trigger the move of associated inadmissibleWorkloads, if there are any.
-		r.queues.QueueAssociatedInadmissibleWorkloadsAfter(ctx, wl, func() {
+		// The old workload holds the admission that released the quota.
+		r.queues.QueueAssociatedInadmissibleWorkloadsAfter(ctx, oldWl, func() {
 			// Delete the workload from cache while holding the queues lock
 			// to guarantee that requeueued workloads are taken into account before
 			// the next scheduling cycle.
@@ -326,6 +811,34 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 			log.V(2).Info("Queue for workload didn't exist; ignored for now")
 		}
 
+	case prevStatus == admitted && status == admitted:
+		// Admitted workloads with a pending resize wait in the queues for the
+		// scheduler to admit the additional pods.
+		if workload.HasPendingResize(wl) {
+			if !r.queues.AddOrUpdateWorkload(wlCopy) {
+				log.V(2).Info("Queue for workload didn't exist; ignored for now")
+			}
+		} else {
+			r.queues.DeleteWorkload(wl)
+		}
+		if equality.Semantic.DeepEqual(oldWl.Status.ReclaimablePods, wl.Status.ReclaimablePods) &&
+			equality.Semantic.DeepEqual(oldWl.Spec.Admission, wl.Spec.Admission) {
+			if err := r.cache.UpdateWorkload(oldWl, wlCopy); err != nil {
+				log.Error(err, "Updating workload in cache")
+			}
+			break
+		}
+		// The workload released quota, by reclaiming pods or by shrinking, so
+		// trigger the move of associated inadmissibleWorkloads, if there are any.
+		r.queues.QueueAssociatedInadmissibleWorkloadsAfter(ctx, wl, func() {
+			// Update the workload in the cache while holding the queues lock
+			// to guarantee that requeued workloads are taken into account before
+			// the next scheduling cycle.
+			if err := r.cache.UpdateWorkload(oldWl, wlCopy); err != nil {
+				log.Error(err, "Updating workload in cache")
+			}
+		})
+
 	default:
 		// Workload update in the cache is handled here; however, some fields are immutable
 		// and are not supposed to actually change anything.
@@ -338,6 +851,10 @@ func (r *WorkloadReconciler) Update(e event.UpdateEvent) bool {
 }
 
 func (r *WorkloadReconciler) Generic(e event.GenericEvent) bool {
+	switch e.Object.(type) {
+	case *kueue.ClusterQueue, *kueue.ResourceFlavor:
+		return true
+	}
 	r.log.V(3).Info("Ignore generic event", "obj", klog.KObj(e.Object), "kind", e.Object.GetObjectKind().GroupVersionKind())
 	return false
 }
@@ -348,10 +865,122 @@ func (r *WorkloadReconciler) notifyWatchers(wl *kueue.Workload) {
 	}
 }
 
+// NotifyClusterQueueUpdate signals the controller to reconcile the workloads
+// that reserved quota in a ClusterQueue when it's stopped with a policy that
+// releases quota, when its quota or flavors change, or when it starts
+// evicting the workloads over quota.
+func (r *WorkloadReconciler) NotifyClusterQueueUpdate(oldCQ, newCQ *kueue.ClusterQueue) {
+	if newCQ == nil {
+		return
+	}
+	if oldCQ == nil {
+		oldCQ = &kueue.ClusterQueue{}
+	}
+	stopped := stopPolicy(newCQ) != kueue.None && stopPolicy(oldCQ) != stopPolicy(newCQ)
+	resourcesChanged := !equality.Semantic.DeepEqual(oldCQ.Spec.EffectiveResourceGroups(), newCQ.Spec.EffectiveResourceGroups()) ||
+		!equality.Semantic.DeepEqual(oldCQ.Spec.QuotaSchedules, newCQ.Spec.QuotaSchedules)
+	evictsOverQuota := overQuotaPolicy(newCQ) == kueue.OverQuotaPolicyEvict &&
+		(overQuotaPolicy(oldCQ) != kueue.OverQuotaPolicyEvict || oldCQ.Spec.Cohort != newCQ.Spec.Cohort)
+	if stopped || resourcesChanged || evictsOverQuota {
+		r.cqUpdateCh <- event.GenericEvent{Object: newCQ}
+	}
+}
+
+// NotifyNodeUpdate signals the controller to reconcile the workloads that
+// reserved quota in the ClusterQueues whose nodes changed, as their quotas
+// declared as a percentage of the nodes might have shrunk below their usage.
+func (r *WorkloadReconciler) NotifyNodeUpdate(clusterQueues sets.Set[string]) {
+	r.notifyClusterQueues(clusterQueues)
+}
+
+// NotifyQuotaScheduleUpdate signals the controller to reconcile the workloads
+// that reserved quota in ClusterQueues whose quotas changed because of their
+// quota schedules.
+func (r *WorkloadReconciler) NotifyQuotaScheduleUpdate(clusterQueues sets.Set[string]) {
+	r.notifyClusterQueues(clusterQueues)
+}
+
+func (r *WorkloadReconciler) notifyClusterQueues(clusterQueues sets.Set[string]) {
+	for name := range clusterQueues {
+		r.cqUpdateCh <- event.GenericEvent{Object: &kueue.ClusterQueue{ObjectMeta: metav1.ObjectMeta{Name: name}}}
+	}
+}
+
+// NotifyResourceFlavorUpdate signals the controller to reconcile the workloads
+// that reserved quota in a ResourceFlavor that is being deleted.
+func (r *WorkloadReconciler) NotifyResourceFlavorUpdate(rf *kueue.ResourceFlavor) {
+	if rf.DeletionTimestamp != nil {
+		r.rfUpdateCh <- event.GenericEvent{Object: rf}
+	}
+}
+
+func stopPolicy(cq *kueue.ClusterQueue) kueue.StopPolicy {
+	if cq.Spec.StopPolicy == nil {
+		return kueue.None
+	}
+	return *cq.Spec.StopPolicy
+}
+
+func overQuotaPolicy(cq *kueue.ClusterQueue) kueue.OverQuotaPolicy {
+	if cq.Spec.OverQuotaPolicy == nil {
+		return kueue.OverQuotaPolicyKeep
+	}
+	return *cq.Spec.OverQuotaPolicy
+}
+
+// wlCqHandler signals the controller to reconcile the workloads that reserved
+// quota in the ClusterQueue in the event.
+// Since the events come from a channel Source, only the Generic handler will
+// receive events.
+type wlCqHandler struct {
+	cache *cache.Cache
+}
+
+func (h *wlCqHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *wlCqHandler) Update(event.UpdateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *wlCqHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *wlCqHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	cq := e.Object.(*kueue.ClusterQueue)
+	for _, wl := range h.cache.ClusterQueueWorkloads(cq.Name) {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: wl.Name, Namespace: wl.Namespace}})
+	}
+}
+
+// wlRfHandler signals the controller to reconcile the workloads that reserved
+// quota in the ResourceFlavor in the event.
+// Since the events come from a channel Source, only the Generic handler will
+// receive events.
+type wlRfHandler struct {
+	cache *cache.Cache
+}
+
+func (h *wlRfHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *wlRfHandler) Update(event.UpdateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *wlRfHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h *wlRfHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	for _, wl := range h.cache.WorkloadsUsingFlavor(e.Object.GetName()) {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: wl.Name, Namespace: wl.Namespace}})
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *WorkloadReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kueue.Workload{}).
+		Watches(&source.Channel{Source: r.cqUpdateCh}, &wlCqHandler{cache: r.cache}).
+		Watches(&source.Channel{Source: r.rfUpdateCh}, &wlRfHandler{cache: r.cache}).
 		WithEventFilter(r).
 		Complete(r)
 }
@@ -391,6 +1020,129 @@ func (r *WorkloadReconciler) admittedNotReadyWorkload(workload *kueue.Workload,
 	return true, waitFor
 }
 
+// reservationRemaining returns the time until the reservation of an admitted
+// workload expires, counted since the Admitted condition became true, and
+// whether the reservation expires at all.
+func reservationRemaining(wl *kueue.Workload, clock clock.Clock) (time.Duration, bool) {
+	if wl.Spec.Reservation == nil || wl.Spec.Reservation.TTLSecondsAfterAdmitted == nil {
+		return 0, false
+	}
+	admittedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted)
+	if admittedCond == nil || admittedCond.Status != metav1.ConditionTrue {
+		return 0, false
+	}
+	ttl := time.Duration(*wl.Spec.Reservation.TTLSecondsAfterAdmitted) * time.Second
+	remaining := ttl - clock.Since(admittedCond.LastTransitionTime.Time)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// executionTimeRemaining returns the time until the admitted workload exceeds
+// the given maximum execution time, in seconds, accounting for the time that
+// it stayed admitted in its previous admissions, and whether it's admitted.
+func executionTimeRemaining(wl *kueue.Workload, limitSeconds int32, clock clock.Clock) (time.Duration, bool) {
+	admittedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted)
+	if admittedCond == nil || admittedCond.Status != metav1.ConditionTrue {
+		return 0, false
+	}
+	remaining := time.Duration(limitSeconds)*time.Second - clock.Since(admittedCond.LastTransitionTime.Time)
+	if past := wl.Status.AccumulatedPastExecutionTimeSeconds; past != nil {
+		remaining -= time.Duration(*past) * time.Second
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// accumulateExecutionTime adds the time that the workload stayed admitted
+// until its eviction to the time of its past admissions. The time is reset
+// when the workload is evicted for exceeding its maximum execution time, so
+// that it starts over if it's reactivated.
+func accumulateExecutionTime(wl *kueue.Workload, evicted *metav1.Condition) {
+	if evicted.Reason == kueue.WorkloadEvictedByMaximumExecutionTimeExceeded {
+		wl.Status.AccumulatedPastExecutionTimeSeconds = nil
+		return
+	}
+	admittedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted)
+	if admittedCond == nil || admittedCond.Status != metav1.ConditionTrue || evicted.LastTransitionTime.Before(&admittedCond.LastTransitionTime) {
+		return
+	}
+	seconds := int32(evicted.LastTransitionTime.Sub(admittedCond.LastTransitionTime.Time) / time.Second)
+	if past := wl.Status.AccumulatedPastExecutionTimeSeconds; past != nil {
+		seconds += *past
+	}
+	wl.Status.AccumulatedPastExecutionTimeSeconds = &seconds
+}
+
+// maximumExecutionTimeExceeded returns whether the workload was deactivated
+// for exceeding its maximum execution time, as explained by its Admitted
+// condition.
+func maximumExecutionTimeExceeded(wl *kueue.Workload) bool {
+	admittedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted)
+	return admittedCond != nil && admittedCond.Reason == kueue.WorkloadEvictedByMaximumExecutionTimeExceeded
+}
+
+// admissionDeadlineRemaining returns the time until the admission deadline of
+// the workload passes, and whether the workload has a deadline at all.
+func admissionDeadlineRemaining(wl *kueue.Workload, clock clock.Clock) (time.Duration, bool) {
+	if wl.Spec.AdmissionDeadline == nil {
+		return 0, false
+	}
+	remaining := wl.Spec.AdmissionDeadline.Sub(clock.Now())
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// reportWaitTimeMetrics observes the time that the workload waited for each
+// of the QuotaReserved, Admitted and PodsReady conditions that became true in
+// the update, and counts the admission when the Admitted condition became
+// true.
+func reportWaitTimeMetrics(oldWl, wl *kueue.Workload) {
+	if wl.Spec.Admission == nil {
+		return
+	}
+	cqName := string(wl.Spec.Admission.ClusterQueue)
+	bucket := metrics.PriorityBucketFor(priority.Priority(wl))
+	if cond := conditionBecameTrue(oldWl, wl, kueue.WorkloadQuotaReserved); cond != nil {
+		metrics.QuotaReservedWorkloadWaitTime(cqName, bucket, cond.LastTransitionTime.Sub(wl.CreationTimestamp.Time))
+	}
+	if cond := conditionBecameTrue(oldWl, wl, kueue.WorkloadAdmitted); cond != nil {
+		waitTime := cond.LastTransitionTime.Sub(wl.CreationTimestamp.Time)
+		metrics.AdmittedWorkload(wl.Spec.Admission.ClusterQueue, waitTime)
+		metrics.AdmittedWorkloadWaitTime(cqName, bucket, waitTime)
+	}
+	// Only the first time that the pods are ready after the admission is
+	// observed, and not when they recover from a failure.
+	admittedCond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted)
+	if admittedCond == nil || admittedCond.Status != metav1.ConditionTrue {
+		return
+	}
+	if oldCond := apimeta.FindStatusCondition(oldWl.Status.Conditions, kueue.WorkloadPodsReady); oldCond != nil && !oldCond.LastTransitionTime.Before(&admittedCond.LastTransitionTime) {
+		return
+	}
+	if cond := conditionBecameTrue(oldWl, wl, kueue.WorkloadPodsReady); cond != nil {
+		metrics.ReadyWorkloadWaitTime(cqName, bucket, cond.LastTransitionTime.Sub(admittedCond.LastTransitionTime.Time))
+	}
+}
+
+// conditionBecameTrue returns the condition of the new workload if it is true
+// and it wasn't in the old workload.
+func conditionBecameTrue(oldWl, wl *kueue.Workload, condType string) *metav1.Condition {
+	if apimeta.IsStatusConditionTrue(oldWl.Status.Conditions, condType) {
+		return nil
+	}
+	cond := apimeta.FindStatusCondition(wl.Status.Conditions, condType)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return nil
+	}
+	return cond
+}
+
 func workloadStatus(w *kueue.Workload) string {
 	if apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadFinished) {
 		return finished
@@ -398,7 +1150,7 @@ func workloadStatus(w *kueue.Workload) string {
 	if w.Spec.Admission != nil {
 		return admitted
 	}
-	if apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadAdmitted) {
+	if apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadAdmitted) || workload.IsEvicted(w) {
 		return cancellingAdmission
 	}
 	return pending
@@ -424,3 +1176,64 @@ func handlePodOverhead(log logr.Logger, wl *kueue.Workload, c client.Client) {
 		}
 	}
 }
+
+// handlePodLimitRange applies the default limits and requests of the
+// containers, from the LimitRanges in the namespace of the workload, to the
+// containers that don't set them, like the LimitRanger admission plugin does
+// when the pods are created.
+func handlePodLimitRange(log logr.Logger, wl *kueue.Workload, c client.Client) {
+	ctx := context.Background()
+
+	var limitRanges corev1.LimitRangeList
+	if err := c.List(ctx, &limitRanges, client.InNamespace(wl.Namespace)); err != nil {
+		log.Error(err, "Could not list LimitRanges")
+		return
+	}
+	for _, lr := range limitRanges.Items {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for i := range wl.Spec.PodSets {
+				spec := &wl.Spec.PodSets[i].Spec
+				for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+					for j := range containers {
+						res := &containers[j].Resources
+						res.Limits = withDefaults(res.Limits, item.Default)
+						res.Requests = withDefaults(res.Requests, item.DefaultRequest)
+					}
+				}
+			}
+		}
+	}
+}
+
+// handleLimitsToRequests uses the limits of the containers as their requests
+// for the resources that they don't request, like the API server does when the
+// pods are created.
+func handleLimitsToRequests(wl *kueue.Workload) {
+	for i := range wl.Spec.PodSets {
+		spec := &wl.Spec.PodSets[i].Spec
+		for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+			for j := range containers {
+				res := &containers[j].Resources
+				res.Requests = withDefaults(res.Requests, res.Limits)
+			}
+		}
+	}
+}
+
+// withDefaults returns the resource list with the defaults of the resources
+// that it doesn't have.
+func withDefaults(rl, defaults corev1.ResourceList) corev1.ResourceList {
+	for name, q := range defaults {
+		if _, found := rl[name]; found {
+			continue
+		}
+		if rl == nil {
+			rl = make(corev1.ResourceList, len(defaults))
+		}
+		rl[name] = q.DeepCopy()
+	}
+	return rl
+}
@@ -17,14 +17,32 @@ limitations under the License.
 package core
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
 )
 
 func TestAdmittedNotReadyWorkload(t *testing.T) {
@@ -205,3 +223,921 @@ func TestAdmittedNotReadyWorkload(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileAdmissionChecks(t *testing.T) {
+	admission := utiltesting.MakeAdmission("cq").AdmissionChecks("a", "b").Obj()
+	testCases := map[string]struct {
+		workload       *kueue.Workload
+		wantConditions []metav1.Condition
+	}{
+		"checks pending": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Admit(admission).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				Obj(),
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadQuotaReserved,
+					Status:  metav1.ConditionTrue,
+					Reason:  "QuotaReserved",
+					Message: "Quota reserved in ClusterQueue cq",
+				},
+				{
+					Type:    kueue.WorkloadAdmitted,
+					Status:  metav1.ConditionFalse,
+					Reason:  "AdmissionChecksPending",
+					Message: "Waiting for the admission checks a, b",
+				},
+			},
+		},
+		"all checks ready": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Admit(admission).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				AdmissionCheck("b", kueue.CheckStateReady).
+				Obj(),
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadQuotaReserved,
+					Status:  metav1.ConditionTrue,
+					Reason:  "QuotaReserved",
+					Message: "Quota reserved in ClusterQueue cq",
+				},
+				{
+					Type:    kueue.WorkloadAdmitted,
+					Status:  metav1.ConditionTrue,
+					Reason:  "AdmissionByKueue",
+					Message: "Admitted by ClusterQueue cq",
+				},
+			},
+		},
+		"check rejected": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").
+					Admit(admission).
+					AdmissionCheck("a", kueue.CheckStateReady).
+					Obj()
+				wl.Status.AdmissionChecks = append(wl.Status.AdmissionChecks, kueue.AdmissionCheckState{
+					Name:    "b",
+					State:   kueue.CheckStateRejected,
+					Message: "over budget",
+				})
+				return wl
+			}(),
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadFinished,
+					Status:  metav1.ConditionTrue,
+					Reason:  "AdmissionCheckRejected",
+					Message: "The admission check b rejected the workload: over budget",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.workload).Build()
+			cqCache := cache.New(cl)
+			r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10))
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "wl", Namespace: "ns"}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			var got kueue.Workload
+			if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Failed getting the workload: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantConditions, got.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileResourceUsage(t *testing.T) {
+	testCases := map[string]struct {
+		workload  *kueue.Workload
+		wantUsage []kueue.PodSetResourceUsage
+	}{
+		"admitted workload": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "2").
+				Request("example.com/gpu", "1").
+				Admit(utiltesting.MakeAdmission("cq").
+					Flavor(corev1.ResourceCPU, "on-demand").
+					Flavor("example.com/gpu", "a100").
+					Obj()).
+				Obj(),
+			wantUsage: []kueue.PodSetResourceUsage{{
+				Name:  "main",
+				Count: 1,
+				Resources: []kueue.ResourceUsage{
+					{Name: corev1.ResourceCPU, Flavor: "on-demand", Total: resource.MustParse("2")},
+					{Name: "example.com/gpu", Flavor: "a100", Total: resource.MustParse("1")},
+				},
+			}},
+		},
+		"released quota": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "2").
+				Condition(metav1.Condition{
+					Type:   kueue.WorkloadQuotaReserved,
+					Status: metav1.ConditionTrue,
+					Reason: "QuotaReserved",
+				}).
+				ResourceUsage(kueue.PodSetResourceUsage{
+					Name:  "main",
+					Count: 1,
+					Resources: []kueue.ResourceUsage{
+						{Name: corev1.ResourceCPU, Flavor: "on-demand", Total: resource.MustParse("2")},
+					},
+				}).
+				Obj(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.workload).Build()
+			cqCache := cache.New(cl)
+			r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10))
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "wl", Namespace: "ns"}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			var got kueue.Workload
+			if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Failed getting the workload: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantUsage, got.Status.ResourceUsage); diff != "" {
+				t.Errorf("Unexpected resource usage (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReservationRemaining(t *testing.T) {
+	now := time.Now()
+	fakeClock := testingclock.NewFakeClock(now)
+	admitted := func(since time.Duration) metav1.Condition {
+		return metav1.Condition{
+			Type:               kueue.WorkloadAdmitted,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(now.Add(-since)),
+		}
+	}
+
+	testCases := map[string]struct {
+		workload      *kueue.Workload
+		wantRemaining time.Duration
+		wantExpires   bool
+	}{
+		"workload that is not a reservation": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Condition(admitted(time.Minute)).Obj(),
+		},
+		"reservation without TTL": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Reservation(nil).Condition(admitted(time.Minute)).Obj(),
+		},
+		"reservation not admitted": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Reservation(pointer.Int32(300)).Obj(),
+		},
+		"reservation admitted within its TTL": {
+			workload:      utiltesting.MakeWorkload("wl", "ns").Reservation(pointer.Int32(300)).Condition(admitted(time.Minute)).Obj(),
+			wantRemaining: 4 * time.Minute,
+			wantExpires:   true,
+		},
+		"expired reservation": {
+			workload:    utiltesting.MakeWorkload("wl", "ns").Reservation(pointer.Int32(300)).Condition(admitted(10 * time.Minute)).Obj(),
+			wantExpires: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			remaining, expires := reservationRemaining(tc.workload, fakeClock)
+			if remaining != tc.wantRemaining || expires != tc.wantExpires {
+				t.Errorf("reservationRemaining() = (%v, %t), want (%v, %t)", remaining, expires, tc.wantRemaining, tc.wantExpires)
+			}
+		})
+	}
+}
+
+func TestReconcileExpiredReservation(t *testing.T) {
+	wl := utiltesting.MakeWorkload("wl", "ns").
+		Reservation(pointer.Int32(60)).
+		Admit(utiltesting.MakeAdmission("cq").Obj()).
+		ResourceUsage(kueue.PodSetResourceUsage{Name: "main", Count: 1}).
+		Condition(metav1.Condition{
+			Type:               kueue.WorkloadQuotaReserved,
+			Status:             metav1.ConditionTrue,
+			Reason:             "QuotaReserved",
+			Message:            "Quota reserved in ClusterQueue cq",
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+		}).
+		Condition(metav1.Condition{
+			Type:               kueue.WorkloadAdmitted,
+			Status:             metav1.ConditionTrue,
+			Reason:             "AdmissionByKueue",
+			Message:            "Admitted by ClusterQueue cq",
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+		}).
+		Obj()
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(wl).Build()
+	cqCache := cache.New(cl)
+	r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10))
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "wl", Namespace: "ns"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	var got kueue.Workload
+	if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Failed getting the workload: %v", err)
+	}
+	wantFinished := &metav1.Condition{
+		Type:    kueue.WorkloadFinished,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ReservationExpired",
+		Message: "The reservation expired 60s after the admission",
+	}
+	gotFinished := apimeta.FindStatusCondition(got.Status.Conditions, kueue.WorkloadFinished)
+	if diff := cmp.Diff(wantFinished, gotFinished, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("Unexpected Finished condition (-want,+got):\n%s", diff)
+	}
+}
+
+func TestReconcileAdmissionDeadline(t *testing.T) {
+	deadline := time.Now().Add(-time.Minute).Truncate(time.Second)
+	testCases := map[string]struct {
+		workload     *kueue.Workload
+		wantFinished *metav1.Condition
+		wantRequeue  bool
+	}{
+		"pending workload before its deadline": {
+			workload:    utiltesting.MakeWorkload("wl", "ns").AdmissionDeadline(time.Now().Add(time.Hour)).Obj(),
+			wantRequeue: true,
+		},
+		"pending workload after its deadline": {
+			workload: utiltesting.MakeWorkload("wl", "ns").AdmissionDeadline(deadline).Obj(),
+			wantFinished: &metav1.Condition{
+				Type:    kueue.WorkloadFinished,
+				Status:  metav1.ConditionTrue,
+				Reason:  kueue.WorkloadAdmissionDeadlineExceeded,
+				Message: fmt.Sprintf("The workload wasn't admitted before its admission deadline %s", deadline.UTC().Format(time.RFC3339)),
+			},
+		},
+		"admitted workload after its deadline": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				AdmissionDeadline(deadline).
+				Admit(utiltesting.MakeAdmission("cq").Obj()).
+				Obj(),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.workload).Build()
+			cqCache := cache.New(cl)
+			r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10))
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "wl", Namespace: "ns"}}
+			result, err := r.Reconcile(ctx, req)
+			if err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			if gotRequeue := result.RequeueAfter > 0; gotRequeue != tc.wantRequeue {
+				t.Errorf("Reconcile requeued after %v, want requeue %t", result.RequeueAfter, tc.wantRequeue)
+			}
+			var got kueue.Workload
+			if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Failed getting the workload: %v", err)
+			}
+			gotFinished := apimeta.FindStatusCondition(got.Status.Conditions, kueue.WorkloadFinished)
+			if diff := cmp.Diff(tc.wantFinished, gotFinished, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected Finished condition (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecutionTimeRemaining(t *testing.T) {
+	now := time.Now()
+	fakeClock := testingclock.NewFakeClock(now)
+	admitted := metav1.Condition{
+		Type:               kueue.WorkloadAdmitted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(now.Add(-time.Minute)),
+	}
+
+	testCases := map[string]struct {
+		workload      *kueue.Workload
+		wantRemaining time.Duration
+		wantAdmitted  bool
+	}{
+		"pending workload": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Obj(),
+		},
+		"first admission": {
+			workload:      utiltesting.MakeWorkload("wl", "ns").Condition(admitted).Obj(),
+			wantRemaining: 4 * time.Minute,
+			wantAdmitted:  true,
+		},
+		"with past admissions": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").Condition(admitted).Obj()
+				wl.Status.AccumulatedPastExecutionTimeSeconds = pointer.Int32(120)
+				return wl
+			}(),
+			wantRemaining: 2 * time.Minute,
+			wantAdmitted:  true,
+		},
+		"exceeded": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").Condition(admitted).Obj()
+				wl.Status.AccumulatedPastExecutionTimeSeconds = pointer.Int32(600)
+				return wl
+			}(),
+			wantAdmitted: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			remaining, admitted := executionTimeRemaining(tc.workload, 300, fakeClock)
+			if remaining != tc.wantRemaining || admitted != tc.wantAdmitted {
+				t.Errorf("executionTimeRemaining() = (%v, %t), want (%v, %t)", remaining, admitted, tc.wantRemaining, tc.wantAdmitted)
+			}
+		})
+	}
+}
+
+func TestAccumulateExecutionTime(t *testing.T) {
+	now := time.Now()
+	admitted := metav1.Condition{
+		Type:               kueue.WorkloadAdmitted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(now.Add(-90 * time.Second)),
+	}
+	evicted := func(reason string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:               kueue.WorkloadEvicted,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			LastTransitionTime: metav1.NewTime(now),
+		}
+	}
+
+	testCases := map[string]struct {
+		workload *kueue.Workload
+		evicted  *metav1.Condition
+		want     *int32
+	}{
+		"evicted before being admitted": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Obj(),
+			evicted:  evicted(kueue.WorkloadEvictedByPreemption),
+		},
+		"first eviction": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Condition(admitted).Obj(),
+			evicted:  evicted(kueue.WorkloadEvictedByPreemption),
+			want:     pointer.Int32(90),
+		},
+		"with past admissions": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").Condition(admitted).Obj()
+				wl.Status.AccumulatedPastExecutionTimeSeconds = pointer.Int32(100)
+				return wl
+			}(),
+			evicted: evicted(kueue.WorkloadEvictedByPreemption),
+			want:    pointer.Int32(190),
+		},
+		"evicted for exceeding the maximum execution time": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").Condition(admitted).Obj()
+				wl.Status.AccumulatedPastExecutionTimeSeconds = pointer.Int32(100)
+				return wl
+			}(),
+			evicted: evicted(kueue.WorkloadEvictedByMaximumExecutionTimeExceeded),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			accumulateExecutionTime(tc.workload, tc.evicted)
+			if diff := cmp.Diff(tc.want, tc.workload.Status.AccumulatedPastExecutionTimeSeconds); diff != "" {
+				t.Errorf("Unexpected accumulated execution time (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileMaximumExecutionTime(t *testing.T) {
+	admitted := func(wl *utiltesting.WorkloadWrapper) *kueue.Workload {
+		return wl.
+			Queue("lq").
+			Admit(utiltesting.MakeAdmission("cq").Obj()).
+			ResourceUsage(kueue.PodSetResourceUsage{Name: "main", Count: 1}).
+			Condition(metav1.Condition{
+				Type:               kueue.WorkloadQuotaReserved,
+				Status:             metav1.ConditionTrue,
+				Reason:             "QuotaReserved",
+				Message:            "Quota reserved in ClusterQueue cq",
+				LastTransitionTime: metav1.NewTime(time.Now()),
+			}).
+			Condition(metav1.Condition{
+				Type:               kueue.WorkloadAdmitted,
+				Status:             metav1.ConditionTrue,
+				Reason:             "AdmissionByKueue",
+				Message:            "Admitted by ClusterQueue cq",
+				LastTransitionTime: metav1.NewTime(time.Now()),
+			}).
+			Obj()
+	}
+	testCases := map[string]struct {
+		workload    *kueue.Workload
+		lqLimit     *int32
+		cqLimit     *int32
+		wantRequeue time.Duration
+	}{
+		"without maximum execution time": {
+			workload: admitted(utiltesting.MakeWorkload("wl", "ns")),
+		},
+		"maximum execution time of the workload": {
+			workload: func() *kueue.Workload {
+				wl := admitted(utiltesting.MakeWorkload("wl", "ns"))
+				wl.Spec.MaximumExecutionTimeSeconds = pointer.Int32(600)
+				return wl
+			}(),
+			lqLimit:     pointer.Int32(3600),
+			cqLimit:     pointer.Int32(7200),
+			wantRequeue: 10 * time.Minute,
+		},
+		"defaulted from the LocalQueue": {
+			workload:    admitted(utiltesting.MakeWorkload("wl", "ns")),
+			lqLimit:     pointer.Int32(3600),
+			cqLimit:     pointer.Int32(7200),
+			wantRequeue: time.Hour,
+		},
+		"defaulted from the ClusterQueue": {
+			workload:    admitted(utiltesting.MakeWorkload("wl", "ns")),
+			cqLimit:     pointer.Int32(7200),
+			wantRequeue: 2 * time.Hour,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			lq := utiltesting.MakeLocalQueue("lq", "ns").ClusterQueue("cq").Obj()
+			lq.Spec.MaximumExecutionTimeSeconds = tc.lqLimit
+			cq := utiltesting.MakeClusterQueue("cq").Obj()
+			cq.Spec.MaximumExecutionTimeSeconds = tc.cqLimit
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.workload, lq, cq).Build()
+			cqCache := cache.New(cl)
+			r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10))
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "wl", Namespace: "ns"}}
+			result, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			if diff := tc.wantRequeue - result.RequeueAfter; diff < 0 || diff > time.Minute {
+				t.Errorf("Reconcile requeued after %v, want %v", result.RequeueAfter, tc.wantRequeue)
+			}
+		})
+	}
+}
+
+func TestReconcileFinishedWorkload(t *testing.T) {
+	finished := func(reason string, since time.Duration) metav1.Condition {
+		return metav1.Condition{
+			Type:               kueue.WorkloadFinished,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+		}
+	}
+	testCases := map[string]struct {
+		workload          *kueue.Workload
+		finishedRetention *time.Duration
+		wantDeleted       bool
+		wantRequeue       bool
+	}{
+		"finished workload is kept without a retention policy": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Condition(finished("JobFinished", time.Hour)).Obj(),
+		},
+		"finished workload within the retention period is kept": {
+			workload:          utiltesting.MakeWorkload("wl", "ns").Condition(finished("JobFinished", time.Minute)).Obj(),
+			finishedRetention: pointer.Duration(time.Hour),
+			wantRequeue:       true,
+		},
+		"finished workload after the retention period is deleted": {
+			workload:          utiltesting.MakeWorkload("wl", "ns").Condition(finished("JobFinished", 2*time.Hour)).Obj(),
+			finishedRetention: pointer.Duration(time.Hour),
+			wantDeleted:       true,
+		},
+		"rejected workload of a job is kept": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				ControllerReference(batchv1.SchemeGroupVersion.WithKind("Job"), "job", "job-uid").
+				Condition(finished("AdmissionCheckRejected", 2*time.Hour)).
+				Obj(),
+			finishedRetention: pointer.Duration(time.Hour),
+		},
+		"rejected workload without a job is deleted": {
+			workload:          utiltesting.MakeWorkload("wl", "ns").Condition(finished("AdmissionCheckRejected", 2*time.Hour)).Obj(),
+			finishedRetention: pointer.Duration(time.Hour),
+			wantDeleted:       true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.workload).Build()
+			cqCache := cache.New(cl)
+			r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10),
+				WithFinishedRetention(tc.finishedRetention))
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "wl", Namespace: "ns"}}
+			result, err := r.Reconcile(ctx, req)
+			if err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			if gotRequeue := result.RequeueAfter > 0; gotRequeue != tc.wantRequeue {
+				t.Errorf("Reconcile() requeued after %v, want requeue %t", result.RequeueAfter, tc.wantRequeue)
+			}
+			var got kueue.Workload
+			err = cl.Get(ctx, req.NamespacedName, &got)
+			if gotDeleted := apierrors.IsNotFound(err); gotDeleted != tc.wantDeleted {
+				t.Errorf("Workload deleted: %t, want %t (error: %v)", gotDeleted, tc.wantDeleted, err)
+			}
+		})
+	}
+}
+
+func TestReconcileEvictedWorkload(t *testing.T) {
+	evictedCond := metav1.Condition{
+		Type:    kueue.WorkloadEvicted,
+		Status:  metav1.ConditionTrue,
+		Reason:  kueue.WorkloadEvictedByPreemption,
+		Message: "Preempted by workload ns/other",
+	}
+	admittedCond := metav1.Condition{
+		Type:    kueue.WorkloadAdmitted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AdmissionByKueue",
+		Message: "Admitted by ClusterQueue cq",
+	}
+	testCases := map[string]struct {
+		workload            *kueue.Workload
+		requeuingLimitCount *int32
+		wantAdmission       *kueue.Admission
+		wantActive          *bool
+		wantRequeueState    *kueue.RequeueState
+		wantConditions      []metav1.Condition
+	}{
+		"evicted workload of a job waits for the job to be suspended": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").
+					Admit(utiltesting.MakeAdmission("cq").Obj()).
+					Condition(admittedCond).
+					Condition(evictedCond).
+					Obj()
+				wl.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: "batch/v1",
+					Kind:       "Job",
+					Name:       "job",
+					UID:        "job-uid",
+					Controller: pointer.Bool(true),
+				}}
+				return wl
+			}(),
+			wantAdmission:  utiltesting.MakeAdmission("cq").Obj(),
+			wantConditions: []metav1.Condition{admittedCond, evictedCond},
+		},
+		"evicted workload with the admission cleared is requeued": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Condition(admittedCond).
+				Condition(evictedCond).
+				Obj(),
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadAdmitted,
+					Status:  metav1.ConditionFalse,
+					Reason:  kueue.WorkloadEvictedByPreemption,
+					Message: "Preempted by workload ns/other",
+				},
+				{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionFalse,
+					Reason:  "Requeued",
+					Message: "The workload was requeued after its eviction",
+				},
+			},
+		},
+		"eviction due to the PodsReady timeout is counted": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Condition(admittedCond).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionTrue,
+					Reason:  kueue.WorkloadEvictedByPodsReadyTimeout,
+					Message: "Exceeded the PodsReady timeout 5m0s",
+				}).
+				Obj(),
+			requeuingLimitCount: pointer.Int32(2),
+			wantRequeueState:    &kueue.RequeueState{Evictions: pointer.Int32(1)},
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadAdmitted,
+					Status:  metav1.ConditionFalse,
+					Reason:  kueue.WorkloadEvictedByPodsReadyTimeout,
+					Message: "Exceeded the PodsReady timeout 5m0s",
+				},
+				{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionFalse,
+					Reason:  "Requeued",
+					Message: "The workload was requeued after its eviction",
+				},
+			},
+		},
+		"exceeding the requeuing limit deactivates the workload": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").
+					Condition(admittedCond).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadEvicted,
+						Status:  metav1.ConditionTrue,
+						Reason:  kueue.WorkloadEvictedByAdmissionCheck,
+						Message: "The admission check a requested a retry: no capacity",
+					}).
+					Obj()
+				wl.Status.RequeueState = &kueue.RequeueState{Evictions: pointer.Int32(2)}
+				return wl
+			}(),
+			requeuingLimitCount: pointer.Int32(2),
+			wantActive:          pointer.Bool(false),
+			wantRequeueState:    &kueue.RequeueState{Evictions: pointer.Int32(3)},
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadAdmitted,
+					Status:  metav1.ConditionFalse,
+					Reason:  "RequeuingLimitExceeded",
+					Message: "Deactivated after being evicted 3 times, the last one with the reason AdmissionCheck: The admission check a requested a retry: no capacity",
+				},
+				{
+					Type:    kueue.WorkloadEvicted,
+					Status:  metav1.ConditionFalse,
+					Reason:  "Requeued",
+					Message: "The workload was requeued after its eviction",
+				},
+			},
+		},
+		"evictions of a reactivated workload are reset": {
+			workload: func() *kueue.Workload {
+				wl := utiltesting.MakeWorkload("wl", "ns").
+					Active(true).
+					Condition(metav1.Condition{
+						Type:    kueue.WorkloadAdmitted,
+						Status:  metav1.ConditionFalse,
+						Reason:  "RequeuingLimitExceeded",
+						Message: "Deactivated after being evicted 3 times",
+					}).
+					Obj()
+				wl.Status.RequeueState = &kueue.RequeueState{Evictions: pointer.Int32(3)}
+				return wl
+			}(),
+			requeuingLimitCount: pointer.Int32(2),
+			wantActive:          pointer.Bool(true),
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadAdmitted,
+					Status:  metav1.ConditionFalse,
+					Reason:  "RequeuingLimitExceeded",
+					Message: "Deactivated after being evicted 3 times",
+				},
+			},
+		},
+		"workload with the admission cancelled": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Condition(admittedCond).
+				Obj(),
+			wantConditions: []metav1.Condition{
+				{
+					Type:    kueue.WorkloadAdmitted,
+					Status:  metav1.ConditionFalse,
+					Reason:  "AdmissionCancelled",
+					Message: "Admission cancelled",
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.workload).Build()
+			cqCache := cache.New(cl)
+			r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10),
+				WithRequeuingLimitCount(tc.requeuingLimitCount))
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "wl", Namespace: "ns"}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			var got kueue.Workload
+			if err := cl.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Failed getting the workload: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantAdmission, got.Spec.Admission, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected admission (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantActive, got.Spec.Active); diff != "" {
+				t.Errorf("Unexpected active (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantRequeueState, got.Status.RequeueState); diff != "" {
+				t.Errorf("Unexpected requeue state (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantConditions, got.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStoppedClusterQueueEvicts(t *testing.T) {
+	admission := utiltesting.MakeAdmission("cq").AdmissionChecks("a").Obj()
+	admittedWl := utiltesting.MakeWorkload("wl", "ns").
+		Admit(admission).
+		AdmissionCheck("a", kueue.CheckStateReady).
+		Obj()
+	checksPendingWl := utiltesting.MakeWorkload("wl", "ns").
+		Admit(admission).
+		Obj()
+	testCases := map[string]struct {
+		stopPolicy *kueue.StopPolicy
+		workload   *kueue.Workload
+		want       bool
+	}{
+		"not stopped": {
+			workload: admittedWl,
+		},
+		"none": {
+			stopPolicy: stopPolicyPtr(kueue.None),
+			workload:   checksPendingWl,
+		},
+		"hold; admitted": {
+			stopPolicy: stopPolicyPtr(kueue.Hold),
+			workload:   admittedWl,
+		},
+		"hold; checks pending": {
+			stopPolicy: stopPolicyPtr(kueue.Hold),
+			workload:   checksPendingWl,
+			want:       true,
+		},
+		"hold and drain; admitted": {
+			stopPolicy: stopPolicyPtr(kueue.HoldAndDrain),
+			workload:   admittedWl,
+			want:       true,
+		},
+		"hold and drain; checks pending": {
+			stopPolicy: stopPolicyPtr(kueue.HoldAndDrain),
+			workload:   checksPendingWl,
+			want:       true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+			cqCache := cache.New(cl)
+			cq := utiltesting.MakeClusterQueue("cq").Obj()
+			cq.Spec.StopPolicy = tc.stopPolicy
+			if err := cqCache.AddClusterQueue(context.Background(), cq); err != nil {
+				t.Fatalf("Failed adding clusterQueue: %v", err)
+			}
+			r := NewWorkloadReconciler(cl, queue.NewManager(cl, cqCache), cqCache, record.NewFakeRecorder(10))
+			if got := r.stoppedClusterQueueEvicts(tc.workload); got != tc.want {
+				t.Errorf("stoppedClusterQueueEvicts() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func stopPolicyPtr(p kueue.StopPolicy) *kueue.StopPolicy {
+	return &p
+}
+
+func TestHandlePodLimitRange(t *testing.T) {
+	limitRanges := []client.Object{
+		&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "ns"},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type: corev1.LimitTypePod,
+						Max: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("10"),
+						},
+					},
+					{
+						Type: corev1.LimitTypeContainer,
+						Default: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("2"),
+							corev1.ResourceMemory: resource.MustParse("2Gi"),
+						},
+						DefaultRequest: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+		&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other-ns"},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type: corev1.LimitTypeContainer,
+						DefaultRequest: corev1.ResourceList{
+							"example.com/gpu": resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+	}
+	cases := map[string]struct {
+		workload *kueue.Workload
+		want     *kueue.Workload
+	}{
+		"defaults applied": {
+			workload: utiltesting.MakeWorkload("wl", "ns").Obj(),
+			want: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "1").
+				Request(corev1.ResourceMemory, "2Gi").
+				Limit(corev1.ResourceCPU, "2").
+				Limit(corev1.ResourceMemory, "2Gi").
+				Obj(),
+		},
+		"requests and limits kept": {
+			workload: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "500m").
+				Limit(corev1.ResourceMemory, "1Gi").
+				Obj(),
+			want: utiltesting.MakeWorkload("wl", "ns").
+				Request(corev1.ResourceCPU, "500m").
+				Request(corev1.ResourceMemory, "1Gi").
+				Limit(corev1.ResourceCPU, "2").
+				Limit(corev1.ResourceMemory, "1Gi").
+				Obj(),
+		},
+		"namespace without LimitRanges": {
+			workload: utiltesting.MakeWorkload("wl", "default").
+				Limit(corev1.ResourceCPU, "3").
+				Obj(),
+			want: utiltesting.MakeWorkload("wl", "default").
+				Request(corev1.ResourceCPU, "3").
+				Limit(corev1.ResourceCPU, "3").
+				Obj(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).WithObjects(limitRanges...).Build()
+			handlePodLimitRange(ctrl.Log, tc.workload, cl)
+			handleLimitsToRequests(tc.workload)
+			if diff := cmp.Diff(tc.want.Spec, tc.workload.Spec); diff != "" {
+				t.Errorf("Unexpected workload spec (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
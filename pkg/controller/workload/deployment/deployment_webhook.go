@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/kueue/pkg/constants"
+)
+
+// DeploymentWebhook copies the queue name of the Deployments to their pod
+// template, so that each of their Pods is admitted with its own Workload by
+// the Pod integration. The Deployments are handled as raw JSON, so that the
+// fields that are unknown to this version of the Kubernetes API are
+// preserved.
+type DeploymentWebhook struct {
+	enabled bool
+}
+
+type options struct {
+	enabled bool
+}
+
+// Option configures the webhook.
+type Option func(*options)
+
+// WithEnabled indicates if the webhook should manage the Deployments that set
+// the queue name annotation. It requires the Pod integration.
+func WithEnabled(f bool) Option {
+	return func(o *options) {
+		o.enabled = f
+	}
+}
+
+var defaultOptions = options{}
+
+// SetupWebhook configures the webhook for Deployments.
+func SetupWebhook(mgr ctrl.Manager, opts ...Option) error {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	mgr.GetWebhookServer().Register("/mutate-apps-v1-deployment", &webhook.Admission{
+		Handler: &DeploymentWebhook{enabled: options.enabled},
+	})
+	return nil
+}
+
+// +kubebuilder:webhook:path=/mutate-apps-v1-deployment,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps,resources=deployments,verbs=create;update,versions=v1,name=mdeployment.kb.io,admissionReviewVersions=v1
+
+var _ admission.Handler = &DeploymentWebhook{}
+
+// Handle sets the queue name annotation and the WorkloadPriorityClass label of
+// the pod template to the ones of the Deployment.
+func (w *DeploymentWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !w.enabled {
+		return admission.Allowed("")
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	queueName := u.GetAnnotations()[constants.QueueAnnotation]
+	if queueName == "" {
+		return admission.Allowed("")
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("deployment-webhook")
+	log.V(5).Info("Applying defaults", "deployment", klog.KObj(u))
+
+	if err := unstructured.SetNestedField(u.Object, queueName, "spec", "template", "metadata", "annotations", constants.QueueAnnotation); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if priorityClass := u.GetLabels()[constants.WorkloadPriorityClassLabel]; priorityClass != "" {
+		if err := unstructured.SetNestedField(u.Object, priorityClass, "spec", "template", "metadata", "labels", constants.WorkloadPriorityClassLabel); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	marshaled, err := json.Marshal(u.Object)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestHandle(t *testing.T) {
+	testcases := map[string]struct {
+		enabled     bool
+		deployment  string
+		wantPatches []jsonpatch.JsonPatchOperation
+	}{
+		"integration disabled": {
+			deployment: `{"metadata":{"name":"deploy","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"template":{"spec":{}}}}`,
+		},
+		"deployment without queue name": {
+			enabled:    true,
+			deployment: `{"metadata":{"name":"deploy"},"spec":{"template":{"spec":{}}}}`,
+		},
+		"deployment with queue name": {
+			enabled:    true,
+			deployment: `{"metadata":{"name":"deploy","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"template":{"spec":{}}}}`,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/spec/template/metadata", map[string]interface{}{
+					"annotations": map[string]interface{}{"kueue.x-k8s.io/queue-name": "queue"},
+				}),
+			},
+		},
+		"deployment with queue name and priority class": {
+			enabled:    true,
+			deployment: `{"metadata":{"name":"deploy","labels":{"kueue.x-k8s.io/priority-class":"high"},"annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"template":{"spec":{}}}}`,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/spec/template/metadata", map[string]interface{}{
+					"annotations": map[string]interface{}{"kueue.x-k8s.io/queue-name": "queue"},
+					"labels":      map[string]interface{}{"kueue.x-k8s.io/priority-class": "high"},
+				}),
+			},
+		},
+		"deployment with a different queue name in the template": {
+			enabled:    true,
+			deployment: `{"metadata":{"name":"deploy","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"template":{"metadata":{"annotations":{"kueue.x-k8s.io/queue-name":"other"}},"spec":{}}}}`,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("replace", "/spec/template/metadata/annotations/kueue.x-k8s.io~1queue-name", "queue"),
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			w := &DeploymentWebhook{enabled: tc.enabled}
+			resp := w.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: []byte(tc.deployment)},
+			}})
+			if !resp.Allowed {
+				t.Errorf("Handle() denied the deployment: %v", resp.Result)
+			}
+			if diff := cmp.Diff(tc.wantPatches, resp.Patches, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected patches (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
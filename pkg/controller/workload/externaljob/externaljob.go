@@ -0,0 +1,262 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externaljob integrates with Kueue the jobs of custom kinds that Kueue
+// doesn't know. The jobs opt in with the queue-name label, and describe their
+// pod sets and the field that suspends them with annotations.
+package externaljob
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+// DefaultSuspendPath is the path of the field that suspends the jobs that
+// don't set the suspend-path annotation.
+const DefaultSuspendPath = "spec.suspend"
+
+// Job is an unstructured job of an external framework. Kueue only manages the
+// jobs that have both the queue-name label and the pod-sets annotation.
+//
+// Kueue can't observe the pods of the jobs, so it considers that a job has no
+// active pods, and that its pods are ready once it's not suspended. A job
+// finishes when it has the Complete, Succeeded or Failed condition.
+type Job struct {
+	u   unstructured.Unstructured
+	gvk schema.GroupVersionKind
+}
+
+var _ jobframework.GenericJob = &Job{}
+var _ jobframework.JobWithSkip = &Job{}
+var _ jobframework.JobWithCustomQueueName = &Job{}
+
+// podSetPaths describes a pod set of a job in the pod-sets annotation.
+type podSetPaths struct {
+	Name     string `json:"name"`
+	Template string `json:"template"`
+	Count    string `json:"count,omitempty"`
+}
+
+// NewJob returns an empty job of the given kind.
+func NewJob(gvk schema.GroupVersionKind) *Job {
+	j := &Job{gvk: gvk}
+	j.u.SetGroupVersionKind(gvk)
+	return j
+}
+
+// NewJobFunc returns the constructor of the empty jobs of the given kind.
+func NewJobFunc(gvk schema.GroupVersionKind) func() jobframework.GenericJob {
+	return func() jobframework.GenericJob {
+		return NewJob(gvk)
+	}
+}
+
+// ParseGVK parses the kind of an external framework, in the format
+// "Kind.version.group".
+func ParseGVK(s string) (schema.GroupVersionKind, error) {
+	gvk, _ := schema.ParseKindArg(s)
+	if gvk == nil || gvk.Kind == "" || gvk.Version == "" || gvk.Group == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid external framework %q, expecting Kind.version.group", s)
+	}
+	return *gvk, nil
+}
+
+func (j *Job) Object() client.Object {
+	return &j.u
+}
+
+func (j *Job) GVK() schema.GroupVersionKind {
+	return j.gvk
+}
+
+// Skip returns whether the job lacks the queue-name label or the pod-sets
+// annotation.
+func (j *Job) Skip() bool {
+	_, hasPodSets := j.u.GetAnnotations()[constants.ExternalJobPodSetsAnnotation]
+	return j.QueueName() == "" || !hasPodSets
+}
+
+// QueueName returns the queue name of the job, from the queue-name label.
+func (j *Job) QueueName() string {
+	return j.u.GetLabels()[constants.QueueAnnotation]
+}
+
+func (j *Job) IsSuspended() bool {
+	suspend, _, _ := unstructured.NestedBool(j.u.Object, j.suspendPath()...)
+	return suspend
+}
+
+func (j *Job) Suspend() error {
+	return unstructured.SetNestedField(j.u.Object, true, j.suspendPath()...)
+}
+
+func (j *Job) RunWithPodSetsInfo(infos []jobframework.PodSetInfo) error {
+	templates, _, _, err := j.templates()
+	if err != nil {
+		return err
+	}
+	if len(templates) != len(infos) {
+		return fmt.Errorf("expecting %d pod sets, got %d", len(templates), len(infos))
+	}
+	for i := range templates {
+		if err := jobframework.ApplyPodSetInfo(templates[i], &infos[i]); err != nil {
+			return err
+		}
+	}
+	return unstructured.SetNestedField(j.u.Object, false, j.suspendPath()...)
+}
+
+func (j *Job) RestorePodSetsInfo(infos []jobframework.PodSetInfo) (bool, error) {
+	templates, _, _, err := j.templates()
+	if err != nil {
+		return false, err
+	}
+	if len(templates) != len(infos) {
+		return false, fmt.Errorf("expecting %d pod sets, got %d", len(templates), len(infos))
+	}
+	changed := false
+	for i := range templates {
+		c, err := jobframework.RestorePodSetInfo(templates[i], &infos[i])
+		if err != nil {
+			return false, err
+		}
+		changed = changed || c
+	}
+	return changed, nil
+}
+
+// Finished returns whether the job has the Complete, Succeeded or Failed
+// condition.
+func (j *Job) Finished() (metav1.Condition, bool) {
+	conditions, _, _ := unstructured.NestedSlice(j.u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		switch condition["type"] {
+		case "Complete", "Succeeded":
+			if message == "" {
+				message = "Job finished successfully"
+			}
+		case "Failed":
+			if message == "" {
+				message = "Job failed"
+			}
+		default:
+			continue
+		}
+		return metav1.Condition{
+			Type:    kueue.WorkloadFinished,
+			Status:  metav1.ConditionTrue,
+			Reason:  "JobFinished",
+			Message: message,
+		}, true
+	}
+	return metav1.Condition{}, false
+}
+
+func (j *Job) PodSets() ([]kueue.PodSet, error) {
+	templates, names, counts, err := j.templates()
+	if err != nil {
+		return nil, err
+	}
+	podSets := make([]kueue.PodSet, len(templates))
+	for i := range templates {
+		if podSets[i], err = jobframework.PodSetFromTemplate(templates[i], names[i], counts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return podSets, nil
+}
+
+// IsActive returns false, as the pods of the job are unknown.
+func (j *Job) IsActive() bool {
+	return false
+}
+
+// PodsReady returns whether the job is not suspended, as the pods of the job
+// are unknown.
+func (j *Job) PodsReady() bool {
+	return !j.IsSuspended()
+}
+
+func (j *Job) EquivalentToWorkload(wl *kueue.Workload) bool {
+	podSets, err := j.PodSets()
+	return err == nil && jobframework.PodSetsEquivalent(wl.Spec.PodSets, podSets)
+}
+
+func (j *Job) suspendPath() []string {
+	path := j.u.GetAnnotations()[constants.ExternalJobSuspendPathAnnotation]
+	if path == "" {
+		path = DefaultSuspendPath
+	}
+	return strings.Split(path, ".")
+}
+
+// templates returns the pod templates of the pod sets described in the
+// pod-sets annotation, without copying them, and their names and counts. The
+// count of a pod set without a count path is 1.
+func (j *Job) templates() ([]map[string]interface{}, []string, []int32, error) {
+	var paths []podSetPaths
+	if err := json.Unmarshal([]byte(j.u.GetAnnotations()[constants.ExternalJobPodSetsAnnotation]), &paths); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid %s annotation: %w", constants.ExternalJobPodSetsAnnotation, err)
+	}
+	if len(paths) == 0 {
+		return nil, nil, nil, fmt.Errorf("the %s annotation has no pod sets", constants.ExternalJobPodSetsAnnotation)
+	}
+	templates := make([]map[string]interface{}, len(paths))
+	names := make([]string, len(paths))
+	counts := make([]int32, len(paths))
+	for i, p := range paths {
+		if p.Name == "" || p.Template == "" {
+			return nil, nil, nil, fmt.Errorf("pod set %d lacks a name or a template in the %s annotation", i, constants.ExternalJobPodSetsAnnotation)
+		}
+		template, found, err := unstructured.NestedFieldNoCopy(j.u.Object, strings.Split(p.Template, ".")...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !found {
+			return nil, nil, nil, fmt.Errorf("missing %s", p.Template)
+		}
+		if templates[i], _ = template.(map[string]interface{}); templates[i] == nil {
+			return nil, nil, nil, fmt.Errorf("invalid %s", p.Template)
+		}
+		names[i] = p.Name
+		counts[i] = 1
+		if p.Count != "" {
+			count, found, err := unstructured.NestedInt64(j.u.Object, strings.Split(p.Count, ".")...)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if found {
+				counts[i] = int32(count)
+			}
+		}
+	}
+	return templates, names, counts, nil
+}
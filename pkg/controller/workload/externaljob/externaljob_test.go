@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaljob
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+var testGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "TrainingRun"}
+
+func TestParseGVK(t *testing.T) {
+	cases := map[string]struct {
+		in      string
+		want    schema.GroupVersionKind
+		wantErr bool
+	}{
+		"kind, version and group": {
+			in:   "TrainingRun.v1.example.com",
+			want: testGVK,
+		},
+		"missing group": {
+			in:      "TrainingRun.v1",
+			wantErr: true,
+		},
+		"only kind": {
+			in:      "TrainingRun",
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseGVK(tc.in)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ParseGVK returned error %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ParseGVK returned %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func newTestJob(t *testing.T, metadata, spec, status string) *Job {
+	t.Helper()
+	j := NewJob(testGVK)
+	raw := `{"apiVersion": "example.com/v1", "kind": "TrainingRun", "metadata": ` + metadata + `, "spec": ` + spec
+	if status != "" {
+		raw += `, "status": ` + status
+	}
+	if err := json.Unmarshal([]byte(raw+"}"), j.Object()); err != nil {
+		t.Fatalf("Invalid job: %v", err)
+	}
+	return j
+}
+
+func TestSkip(t *testing.T) {
+	cases := map[string]struct {
+		metadata string
+		want     bool
+	}{
+		"queue-name label and pod-sets annotation": {
+			metadata: `{"name": "run", "labels": {"kueue.x-k8s.io/queue-name": "queue"}, "annotations": {"kueue.x-k8s.io/pod-sets": "[]"}}`,
+		},
+		"queue-name annotation": {
+			metadata: `{"name": "run", "annotations": {"kueue.x-k8s.io/queue-name": "queue", "kueue.x-k8s.io/pod-sets": "[]"}}`,
+			want:     true,
+		},
+		"no pod-sets annotation": {
+			metadata: `{"name": "run", "labels": {"kueue.x-k8s.io/queue-name": "queue"}}`,
+			want:     true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			j := newTestJob(t, tc.metadata, `{}`, "")
+			if got := j.Skip(); got != tc.want {
+				t.Errorf("Skip() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPodSets(t *testing.T) {
+	j := newTestJob(t, `{
+		"name": "run",
+		"labels": {"kueue.x-k8s.io/queue-name": "queue"},
+		"annotations": {"kueue.x-k8s.io/pod-sets": "[{\"name\": \"driver\", \"template\": \"spec.driver\"}, {\"name\": \"workers\", \"template\": \"spec.workers.template\", \"count\": \"spec.workers.size\"}]"}
+	}`, `{
+		"suspend": true,
+		"driver": {"spec": {"containers": [{"name": "driver"}]}},
+		"workers": {"size": 3, "template": {"spec": {"containers": [{"name": "worker"}]}}}
+	}`, "")
+	if !j.IsSuspended() {
+		t.Errorf("The job isn't suspended")
+	}
+	if got := jobframework.QueueName(j); got != "queue" {
+		t.Errorf("Unexpected queue name %q", got)
+	}
+	got, err := j.PodSets()
+	if err != nil {
+		t.Fatalf("PodSets() returned error: %v", err)
+	}
+	want := []kueue.PodSet{
+		{
+			Name:  "driver",
+			Count: 1,
+			Spec:  corev1.PodSpec{Containers: []corev1.Container{{Name: "driver"}}},
+		},
+		{
+			Name:  "workers",
+			Count: 3,
+			Spec:  corev1.PodSpec{Containers: []corev1.Container{{Name: "worker"}}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected pod sets (-want,+got):\n%s", diff)
+	}
+}
+
+func TestRunAndSuspend(t *testing.T) {
+	j := newTestJob(t, `{
+		"name": "run",
+		"annotations": {
+			"kueue.x-k8s.io/pod-sets": "[{\"name\": \"main\", \"template\": \"spec.template\"}]",
+			"kueue.x-k8s.io/suspend-path": "spec.control.paused"
+		}
+	}`, `{
+		"control": {"paused": true},
+		"template": {"spec": {"containers": [{"name": "main"}]}}
+	}`, "")
+	if !j.IsSuspended() {
+		t.Fatalf("The job isn't suspended")
+	}
+	if err := j.RunWithPodSetsInfo([]jobframework.PodSetInfo{{
+		Name:         "main",
+		NodeSelector: map[string]string{"instance-type": "spot"},
+	}}); err != nil {
+		t.Fatalf("RunWithPodSetsInfo() returned error: %v", err)
+	}
+	if j.IsSuspended() {
+		t.Errorf("The job is still suspended")
+	}
+	nodeSelector, _, _ := unstructured.NestedStringMap(j.u.Object, "spec", "template", "spec", "nodeSelector")
+	if diff := cmp.Diff(map[string]string{"instance-type": "spot"}, nodeSelector); diff != "" {
+		t.Errorf("Unexpected node selector after running (-want,+got):\n%s", diff)
+	}
+
+	if err := j.Suspend(); err != nil {
+		t.Fatalf("Suspend() returned error: %v", err)
+	}
+	changed, err := j.RestorePodSetsInfo([]jobframework.PodSetInfo{{Name: "main"}})
+	if err != nil {
+		t.Fatalf("RestorePodSetsInfo() returned error: %v", err)
+	}
+	if !changed {
+		t.Errorf("RestorePodSetsInfo() didn't change the job")
+	}
+	if !j.IsSuspended() {
+		t.Errorf("The job isn't suspended")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(j.u.Object, "spec", "template", "spec", "nodeSelector"); found {
+		t.Errorf("The node selector wasn't removed")
+	}
+}
+
+func TestFinished(t *testing.T) {
+	cases := map[string]struct {
+		status       string
+		wantFinished bool
+		wantMessage  string
+	}{
+		"no conditions": {
+			status: `{}`,
+		},
+		"running": {
+			status: `{"conditions": [{"type": "Running", "status": "True"}]}`,
+		},
+		"complete": {
+			status:       `{"conditions": [{"type": "Complete", "status": "True"}]}`,
+			wantFinished: true,
+			wantMessage:  "Job finished successfully",
+		},
+		"failed": {
+			status:       `{"conditions": [{"type": "Failed", "status": "True", "message": "Out of memory"}]}`,
+			wantFinished: true,
+			wantMessage:  "Out of memory",
+		},
+		"succeeded condition is false": {
+			status: `{"conditions": [{"type": "Succeeded", "status": "False"}]}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			j := newTestJob(t, `{"name": "run"}`, `{}`, tc.status)
+			cond, finished := j.Finished()
+			if finished != tc.wantFinished {
+				t.Errorf("Finished() = %t, want %t", finished, tc.wantFinished)
+			}
+			if cond.Message != tc.wantMessage {
+				t.Errorf("Unexpected message %q, want %q", cond.Message, tc.wantMessage)
+			}
+		})
+	}
+}
@@ -19,16 +19,15 @@ package job
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -41,70 +40,55 @@ import (
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/constants"
-	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
-	"sigs.k8s.io/kueue/pkg/workload"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
 )
 
+// FrameworkName is the name of the integration in the configuration.
+const FrameworkName = "batch/job"
+
 var (
-	ownerKey          = ".metadata.controller"
+	gvk               = batchv1.SchemeGroupVersion.WithKind("Job")
 	parentWorkloadKey = ".metadata.parentWorkload"
 )
 
-// JobReconciler reconciles a Job object
-type JobReconciler struct {
-	client                     client.Client
-	scheme                     *runtime.Scheme
-	record                     record.EventRecorder
-	manageJobsWithoutQueueName bool
-	waitForPodsReady           bool
-}
-
-type options struct {
-	manageJobsWithoutQueueName bool
-	waitForPodsReady           bool
-}
-
-// Option configures the reconciler.
-type Option func(*options)
-
-// WithManageJobsWithoutQueueName indicates if the controller should reconcile
-// jobs that don't set the queue name annotation.
-func WithManageJobsWithoutQueueName(f bool) Option {
-	return func(o *options) {
-		o.manageJobsWithoutQueueName = f
+func init() {
+	if err := jobframework.RegisterIntegration(FrameworkName, jobframework.IntegrationCallbacks{
+		NewJob:          NewJob,
+		SetupIndexes:    SetupIndexes,
+		SetupController: SetupController,
+		SetupWebhook:    SetupWebhook,
+	}); err != nil {
+		panic(err)
 	}
 }
 
-// WithWaitForPodsReady indicates if the controller should add the PodsReady
-// condition to the workload when the corresponding job has all pods ready
-// or succeeded.
-func WithWaitForPodsReady(f bool) Option {
-	return func(o *options) {
-		o.waitForPodsReady = f
-	}
+// JobReconciler reconciles a Job object. On top of the reconciler of the job
+// framework, it reconciles the child jobs when their parent workload changes.
+type JobReconciler struct {
+	*jobframework.JobReconciler
+	client client.Client
 }
 
-var defaultOptions = options{}
-
 func NewReconciler(
 	scheme *runtime.Scheme,
 	client client.Client,
 	record record.EventRecorder,
-	opts ...Option) *JobReconciler {
-	options := defaultOptions
-	for _, opt := range opts {
-		opt(&options)
-	}
-
+	opts ...jobframework.Option) *JobReconciler {
 	return &JobReconciler{
-		scheme:                     scheme,
-		client:                     client,
-		record:                     record,
-		manageJobsWithoutQueueName: options.manageJobsWithoutQueueName,
-		waitForPodsReady:           options.waitForPodsReady,
+		JobReconciler: jobframework.NewReconciler(scheme, client, record, NewJob, opts...),
+		client:        client,
 	}
 }
 
+// SetupController sets up the reconciler of the Jobs.
+func SetupController(mgr ctrl.Manager, opts ...jobframework.Option) error {
+	return NewReconciler(mgr.GetScheme(),
+		mgr.GetClient(),
+		mgr.GetEventRecorderFor(constants.JobControllerName),
+		opts...,
+	).SetupWithManager(mgr)
+}
+
 type parentWorkloadHandler struct {
 	client client.Client
 }
@@ -153,8 +137,7 @@ func (h *parentWorkloadHandler) queueReconcileForChildJob(object client.Object,
 	}
 }
 
-// SetupWithManager sets up the controller with the Manager. It indexes workloads
-// based on the owning jobs.
+// SetupWithManager sets up the controller with the Manager.
 func (r *JobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	wlHandler := parentWorkloadHandler{client: r.client}
 	return ctrl.NewControllerManagedBy(mgr).
@@ -164,6 +147,8 @@ func (r *JobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// SetupIndexes indexes the Jobs by their parent workload, and the Workloads
+// by their owner Job.
 func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
 	if err := indexer.IndexField(ctx, &batchv1.Job{}, parentWorkloadKey, func(o client.Object) []string {
 		job := o.(*batchv1.Job)
@@ -174,23 +159,11 @@ func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
 	}); err != nil {
 		return err
 	}
-	return indexer.IndexField(ctx, &kueue.Workload{}, ownerKey, func(o client.Object) []string {
-		// grab the Workload object, extract the owner...
-		wl := o.(*kueue.Workload)
-		owner := metav1.GetControllerOf(wl)
-		if owner == nil {
-			return nil
-		}
-		// ...make sure it's a Job...
-		if owner.APIVersion != "batch/v1" || owner.Kind != "Job" {
-			return nil
-		}
-		// ...and if so, return it
-		return []string{owner.Name}
-	})
+	return jobframework.SetupWorkloadOwnerIndex(ctx, indexer, gvk)
 }
 
 //+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloadpriorityclasses,verbs=list;get;watch
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get
@@ -200,388 +173,233 @@ func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
 //+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/finalizers,verbs=update
 //+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
 
-func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	var job batchv1.Job
-	if err := r.client.Get(ctx, req.NamespacedName, &job); err != nil {
-		// we'll ignore not-found errors, since there is nothing to do.
-		return ctrl.Result{}, client.IgnoreNotFound(err)
-	}
-
-	log := ctrl.LoggerFrom(ctx).WithValues("job", klog.KObj(&job))
-	ctx = ctrl.LoggerInto(ctx, log)
-
-	pwName := parentWorkload(&job)
-
-	// when manageJobsWithoutQueueName is disabled we only reconcile jobs that have either
-	// queue-name or the parent-workload annotation set.
-	if queueName(&job) == "" && pwName == "" && !r.manageJobsWithoutQueueName {
-		log.V(3).Info(fmt.Sprintf("Neither %s, nor %s annotation is set, ignoring the job", constants.QueueAnnotation, constants.ParentWorkloadAnnotation))
-		return ctrl.Result{}, nil
-	}
-
-	log.V(2).Info("Reconciling Job")
-
-	var childWorkloads kueue.WorkloadList
-	if err := r.client.List(ctx, &childWorkloads, client.InNamespace(req.Namespace),
-		client.MatchingFields{ownerKey: req.Name}); err != nil {
-		log.Error(err, "Unable to list child workloads")
-		return ctrl.Result{}, err
-	}
-
-	// 1. make sure there is only a single existing instance of the workload
-	wl, err := r.ensureAtMostOneWorkload(ctx, &job, childWorkloads)
-	if err != nil {
-		log.Error(err, "Getting existing workloads")
-		return ctrl.Result{}, err
-	}
-
-	jobFinishedCond, jobFinished := jobFinishedCondition(&job)
-	// 2. create new workload if none exists
-	if wl == nil {
-		// Nothing to do if the job is finished
-		if jobFinished {
-			return ctrl.Result{}, nil
-		}
-		if pwName != "" {
-			return ctrl.Result{}, nil
-		}
-		err := r.handleJobWithNoWorkload(ctx, &job)
-		if err != nil {
-			log.Error(err, "Handling job with no workload")
-		}
-		return ctrl.Result{}, err
-	}
-
-	if pwName == "" {
-		// 3. handle a finished job, if it's the main job.
-		if jobFinished {
-			if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
-				return ctrl.Result{}, nil
-			}
-			condition := generateFinishedCondition(jobFinishedCond)
-			apimeta.SetStatusCondition(&wl.Status.Conditions, condition)
-			err := r.client.Status().Update(ctx, wl)
-			if err != nil {
-				log.Error(err, "Updating workload status")
-			}
-			return ctrl.Result{}, err
-		}
+// Job is a batch/v1 Job, managed through the job framework.
+type Job batchv1.Job
 
-		// handle a job when waitForPodsReady is enabled, and it is the main job
-		if r.waitForPodsReady {
-			log.V(5).Info("Handling a job when waitForPodsReady is enabled")
-			condition := generatePodsReadyCondition(&job, wl)
-			// optimization to avoid sending the update request if the status didn't change
-			if !apimeta.IsStatusConditionPresentAndEqual(wl.Status.Conditions, condition.Type, condition.Status) {
-				log.V(3).Info(fmt.Sprintf("Updating the PodsReady condition with status: %v", condition.Status))
-				apimeta.SetStatusCondition(&wl.Status.Conditions, condition)
-				if err := r.client.Status().Update(ctx, wl); err != nil {
-					log.Error(err, "Updating workload status")
-				}
-			}
-		}
-	}
-
-	// 4. Handle a not finished job
-	if jobSuspended(&job) {
-		// start the job if the workload has been admitted, and the job is still suspended
-		if wl.Spec.Admission != nil {
-			log.V(2).Info("Job admitted, unsuspending")
-			err := r.startJob(ctx, wl, &job)
-			if err != nil {
-				log.Error(err, "Unsuspending job")
-			}
-			return ctrl.Result{}, err
-		}
-
-		// update queue name if changed.
-		q := queueName(&job)
-		if wl.Spec.QueueName != q {
-			log.V(2).Info("Job changed queues, updating workload")
-			wl.Spec.QueueName = q
-			err := r.client.Update(ctx, wl)
-			if err != nil {
-				log.Error(err, "Updating workload queue")
-			}
-			return ctrl.Result{}, err
-		}
-		log.V(3).Info("Job is suspended and workload not yet admitted by a clusterQueue, nothing to do")
-		return ctrl.Result{}, nil
-	}
-
-	if wl.Spec.Admission == nil {
-		// the job must be suspended if the workload is not yet admitted.
-		log.V(2).Info("Running job is not admitted by a cluster queue, suspending")
-		err := r.stopJob(ctx, wl, &job, "Not admitted by cluster queue")
-		if err != nil {
-			log.Error(err, "Suspending job with non admitted workload")
-		}
-		return ctrl.Result{}, err
-	}
+var (
+	_ jobframework.GenericJob                = &Job{}
+	_ jobframework.JobWithReclaimablePods    = &Job{}
+	_ jobframework.JobWithStatusReset        = &Job{}
+	_ jobframework.JobWithParentWorkload     = &Job{}
+	_ jobframework.JobWithCustomWorkloadName = &Job{}
+	_ jobframework.JobWithSlices             = &Job{}
+	_ jobframework.JobWithFailure            = &Job{}
+)
 
-	// workload is admitted and job is running, nothing to do.
-	log.V(3).Info("Job running with admitted workload, nothing to do")
-	return ctrl.Result{}, nil
+// NewJob returns an empty Job.
+func NewJob() jobframework.GenericJob {
+	return &Job{}
 }
 
-// podsReady checks if all pods are ready or succeeded
-func podsReady(job *batchv1.Job) bool {
-	ready := pointer.Int32Deref(job.Status.Ready, 0)
-	return job.Status.Succeeded+ready >= podsCount(&job.Spec)
+func (j *Job) Object() client.Object {
+	return (*batchv1.Job)(j)
 }
 
-// stopJob sends updates to suspend the job, reset the startTime so we can update the scheduling directives
-// later when unsuspending and resets the nodeSelector to its previous state based on what is available in
-// the workload (which should include the original affinities that the job had).
-func (r *JobReconciler) stopJob(ctx context.Context, w *kueue.Workload,
-	job *batchv1.Job, eventMsg string) error {
-	job.Spec.Suspend = pointer.Bool(true)
-	if err := r.client.Update(ctx, job); err != nil {
-		return err
-	}
-	r.record.Eventf(job, corev1.EventTypeNormal, "Stopped", eventMsg)
-
-	// Reset start time so we can update the scheduling directives later when unsuspending.
-	if job.Status.StartTime != nil {
-		job.Status.StartTime = nil
-		if err := r.client.Status().Update(ctx, job); err != nil {
-			return err
-		}
-	}
+func (j *Job) GVK() schema.GroupVersionKind {
+	return gvk
+}
 
-	if w != nil && !equality.Semantic.DeepEqual(job.Spec.Template.Spec.NodeSelector,
-		w.Spec.PodSets[0].Spec.NodeSelector) {
-		job.Spec.Template.Spec.NodeSelector = map[string]string{}
-		for k, v := range w.Spec.PodSets[0].Spec.NodeSelector {
-			job.Spec.Template.Spec.NodeSelector[k] = v
-		}
-		return r.client.Update(ctx, job)
-	}
+func (j *Job) IsSuspended() bool {
+	return j.Spec.Suspend != nil && *j.Spec.Suspend
+}
 
+func (j *Job) Suspend() error {
+	j.Spec.Suspend = pointer.Bool(true)
 	return nil
 }
 
-func (r *JobReconciler) startJob(ctx context.Context, w *kueue.Workload, job *batchv1.Job) error {
-	log := ctrl.LoggerFrom(ctx)
+// RunWithPodSetsInfo unsuspends the job, injecting the node selector,
+// tolerations, labels and annotations of the info into its pod template. The parallelism is
+// reduced if the job was partially admitted. The completions are kept, so an
+// Indexed Job runs all its indexes with fewer pods at a time.
+func (j *Job) RunWithPodSetsInfo(infos []jobframework.PodSetInfo) error {
+	if len(infos) != 1 {
+		return fmt.Errorf("one podset must exist, found %d", len(infos))
+	}
+	info := &infos[0]
+	template := &j.Spec.Template
+	template.Labels = jobframework.MergeMaps(template.Labels, info.Labels)
+	template.Annotations = jobframework.MergeMaps(template.Annotations, info.Annotations)
+	template.Spec.NodeSelector = jobframework.MergeMaps(template.Spec.NodeSelector, info.NodeSelector)
+	template.Spec.Tolerations = jobframework.MergeTolerations(template.Spec.Tolerations, info.Tolerations)
+	if info.Count != nil && *info.Count != podsCount(&j.Spec) {
+		j.Spec.Parallelism = pointer.Int32(*info.Count)
+	}
+	j.Spec.Suspend = pointer.Bool(false)
+	return nil
+}
 
-	if len(w.Spec.PodSets) != 1 {
-		return fmt.Errorf("one podset must exist, found %d", len(w.Spec.PodSets))
+// RestorePodSetsInfo resets the node selector and the tolerations of the pod
+// template to their original state, which is the one in the workload, and the parallelism, in
+// case the job was partially admitted.
+func (j *Job) RestorePodSetsInfo(infos []jobframework.PodSetInfo) (bool, error) {
+	if len(infos) != 1 {
+		return false, fmt.Errorf("one podset must exist, found %d", len(infos))
+	}
+	info := &infos[0]
+	changed := false
+	if !equality.Semantic.DeepEqual(j.Spec.Template.Spec.NodeSelector, info.NodeSelector) {
+		j.Spec.Template.Spec.NodeSelector = make(map[string]string, len(info.NodeSelector))
+		for k, v := range info.NodeSelector {
+			j.Spec.Template.Spec.NodeSelector[k] = v
+		}
+		changed = true
 	}
-	nodeSelector, err := r.getNodeSelectors(ctx, w)
-	if err != nil {
-		return err
+	if !equality.Semantic.DeepEqual(j.Spec.Template.Spec.Tolerations, info.Tolerations) {
+		j.Spec.Template.Spec.Tolerations = append([]corev1.Toleration(nil), info.Tolerations...)
+		changed = true
 	}
-	if len(nodeSelector) != 0 {
-		if job.Spec.Template.Spec.NodeSelector == nil {
-			job.Spec.Template.Spec.NodeSelector = nodeSelector
-		} else {
-			for k, v := range nodeSelector {
-				job.Spec.Template.Spec.NodeSelector[k] = v
-			}
-		}
-	} else {
-		log.V(3).Info("no nodeSelectors to inject")
+	if info.Count != nil && podsCount(&j.Spec) != *info.Count {
+		j.Spec.Parallelism = pointer.Int32(*info.Count)
+		changed = true
 	}
+	return changed, nil
+}
 
-	job.Spec.Suspend = pointer.Bool(false)
-	if err := r.client.Update(ctx, job); err != nil {
-		return err
+// ResetStatus resets the start time, so that the scheduling directives can be
+// updated when unsuspending.
+func (j *Job) ResetStatus() bool {
+	if j.Status.StartTime == nil {
+		return false
 	}
+	j.Status.StartTime = nil
+	return true
+}
 
-	r.record.Eventf(job, corev1.EventTypeNormal, "Started",
-		"Admitted by clusterQueue %v", w.Spec.Admission.ClusterQueue)
-	return nil
+// Fail adds the Failed condition to the job, which stops the Job controller
+// from creating pods for it.
+func (j *Job) Fail(reason, message string) {
+	now := metav1.Now()
+	j.Status.Conditions = append(j.Status.Conditions, batchv1.JobCondition{
+		Type:               batchv1.JobFailed,
+		Status:             corev1.ConditionTrue,
+		LastProbeTime:      now,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
 }
 
-func (r *JobReconciler) getNodeSelectors(ctx context.Context, w *kueue.Workload) (map[string]string, error) {
-	if len(w.Spec.Admission.PodSetFlavors[0].Flavors) == 0 {
-		return nil, nil
+func (j *Job) Finished() (metav1.Condition, bool) {
+	jobStatus, finished := jobFinishedCondition((*batchv1.Job)(j))
+	if !finished {
+		return metav1.Condition{}, false
 	}
+	return generateFinishedCondition(jobStatus), true
+}
 
-	processedFlvs := sets.NewString()
-	nodeSelector := map[string]string{}
-	for _, flvName := range w.Spec.Admission.PodSetFlavors[0].Flavors {
-		if processedFlvs.Has(flvName) {
-			continue
-		}
-		// Lookup the ResourceFlavors to fetch the node affinity labels to apply on the job.
-		flv := kueue.ResourceFlavor{}
-		if err := r.client.Get(ctx, types.NamespacedName{Name: flvName}, &flv); err != nil {
-			return nil, err
-		}
-		for k, v := range flv.NodeSelector {
-			nodeSelector[k] = v
-		}
-		processedFlvs.Insert(flvName)
-	}
-	return nodeSelector, nil
+func (j *Job) PodSets() ([]kueue.PodSet, error) {
+	return []kueue.PodSet{{
+		Name:            kueue.DefaultPodSetName,
+		Spec:            *j.Spec.Template.Spec.DeepCopy(),
+		Count:           podsCount(&j.Spec),
+		MinCount:        minPodsCount((*batchv1.Job)(j)),
+		TopologyRequest: jobframework.TopologyRequest(j.Spec.Template.Annotations),
+		FlavorAffinity:  jobframework.FlavorAffinity(j.Spec.Template.Annotations),
+	}}, nil
 }
 
-func (r *JobReconciler) handleJobWithNoWorkload(ctx context.Context, job *batchv1.Job) error {
-	log := ctrl.LoggerFrom(ctx)
+func (j *Job) IsActive() bool {
+	return j.Status.Active != 0
+}
 
-	// Wait until there are no active pods.
-	if job.Status.Active != 0 {
-		log.V(2).Info("Job is suspended but still has active pods, waiting")
-		return nil
-	}
+func (j *Job) PodsReady() bool {
+	return podsReady((*batchv1.Job)(j))
+}
 
-	// Create the corresponding workload.
-	wl, err := ConstructWorkloadFor(ctx, r.client, job, r.scheme)
-	if err != nil {
-		return err
-	}
-	if err = r.client.Create(ctx, wl); err != nil {
-		return err
+// EquivalentToWorkload returns whether the pod set of the workload matches the
+// one of the job. The parallelism is only reduced while the workload is
+// admitted, hence a different count of a pending workload means that the
+// parallelism of the suspended job was mutated, and the workload has to be
+// recreated.
+func (j *Job) EquivalentToWorkload(wl *kueue.Workload) bool {
+	podSets, _ := j.PodSets()
+	if !jobframework.PodSetsEquivalent(wl.Spec.PodSets, podSets) {
+		return false
 	}
-
-	r.record.Eventf(job, corev1.EventTypeNormal, "CreatedWorkload",
-		"Created Workload: %v", workload.Key(wl))
-	return nil
+	return wl.Spec.Admission != nil || !j.IsSuspended() || wl.Spec.PodSets[0].Count == podSets[0].Count
 }
 
-// ensureAtMostOneWorkload finds a matching workload and deletes redundant ones.
-func (r *JobReconciler) ensureAtMostOneWorkload(ctx context.Context, job *batchv1.Job, workloads kueue.WorkloadList) (*kueue.Workload, error) {
-	log := ctrl.LoggerFrom(ctx)
-
-	// Find a matching workload first if there is one.
-	var toDelete []*kueue.Workload
-	var match *kueue.Workload
-
-	pwName := parentWorkload(job)
-	if pwName != "" {
-		pw := kueue.Workload{}
-		NamespacedName := types.NamespacedName{
-			Name:      pwName,
-			Namespace: job.Namespace,
-		}
-		if err := r.client.Get(ctx, NamespacedName, &pw); err != nil {
-			if !apierrors.IsNotFound(err) {
-				return nil, err
-			}
-			log.V(2).Info("job with no matching parent workload", "parent-workload", pwName)
-		} else {
-			match = &pw
-		}
-	}
+func (j *Job) ReclaimablePods() []kueue.ReclaimablePod {
+	return reclaimablePods((*batchv1.Job)(j))
+}
 
-	for i := range workloads.Items {
-		w := &workloads.Items[i]
-		owner := metav1.GetControllerOf(w)
-		// Indexes don't work in unit tests, so we explicitly check for the
-		// owner here.
-		if owner.Name != job.Name {
-			continue
-		}
-		if match == nil && jobAndWorkloadEqual(job, w) {
-			match = w
-		} else {
-			toDelete = append(toDelete, w)
-		}
-	}
+// ParentWorkload returns the workload set in the parent-workload annotation.
+func (j *Job) ParentWorkload() string {
+	return parentWorkload((*batchv1.Job)(j))
+}
 
-	// If there is no matching workload and the job is running, suspend it.
-	if match == nil && !jobSuspended(job) {
-		log.V(2).Info("job with no matching workload, suspending")
-		var w *kueue.Workload
-		if len(workloads.Items) == 1 {
-			// The job may have been modified and hence the existing workload
-			// doesn't match the job anymore. All bets are off if there are more
-			// than one workload...
-			w = &workloads.Items[0]
-		}
-		if err := r.stopJob(ctx, w, job, "No matching Workload"); err != nil {
-			log.Error(err, "stopping job")
-		}
-	}
+// WorkloadName returns the name of the job, which is the name that the
+// workloads of the Jobs had before the job framework.
+func (j *Job) WorkloadName() string {
+	return j.Name
+}
 
-	// Delete duplicate workload instances.
-	existedWls := 0
-	for i := range toDelete {
-		err := r.client.Delete(ctx, toDelete[i])
-		if err == nil || !apierrors.IsNotFound(err) {
-			existedWls++
-		}
-		if err != nil && !apierrors.IsNotFound(err) {
-			log.Error(err, "Failed to delete workload")
-		}
-		if err == nil {
-			r.record.Eventf(job, corev1.EventTypeNormal, "DeletedWorkload",
-				"Deleted not matching Workload: %v", workload.Key(toDelete[i]))
-		}
-	}
+// SliceSize returns the maximum number of pods of each Workload slice of the
+// job, as specified in the slice-size annotation, or 0 if the job isn't split.
+func (j *Job) SliceSize() int32 {
+	return sliceSize((*batchv1.Job)(j))
+}
 
-	if existedWls != 0 {
-		if match == nil {
-			return nil, fmt.Errorf("no matching workload was found, tried deleting %d existing workload(s)", existedWls)
-		}
-		return nil, fmt.Errorf("only one workload should exist, found %d", len(workloads.Items))
-	}
+// podsReady checks if all pods are ready or succeeded
+func podsReady(job *batchv1.Job) bool {
+	ready := pointer.Int32Deref(job.Status.Ready, 0)
+	return job.Status.Succeeded+ready >= podsCount(&job.Spec)
+}
 
-	return match, nil
-}
-
-func ConstructWorkloadFor(ctx context.Context, client client.Client,
-	job *batchv1.Job, scheme *runtime.Scheme) (*kueue.Workload, error) {
-	w := &kueue.Workload{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      job.Name,
-			Namespace: job.Namespace,
-		},
-		Spec: kueue.WorkloadSpec{
-			PodSets: []kueue.PodSet{
-				{
-					Spec:  *job.Spec.Template.Spec.DeepCopy(),
-					Count: podsCount(&job.Spec),
-				},
-			},
-			QueueName: queueName(job),
-		},
+// podsCount returns the number of pods that the job runs at a time, which is
+// its parallelism, defaulted to 1, unless it has fewer completions. For an
+// Indexed Job, the completions are the number of indexes.
+func podsCount(jobSpec *batchv1.JobSpec) int32 {
+	podsCount := pointer.Int32Deref(jobSpec.Parallelism, 1)
+	if jobSpec.Completions != nil && *jobSpec.Completions < podsCount {
+		podsCount = *jobSpec.Completions
 	}
+	return podsCount
+}
 
-	// Populate priority from priority class.
-	priorityClassName, p, err := utilpriority.GetPriorityFromPriorityClass(
-		ctx, client, job.Spec.Template.Spec.PriorityClassName)
-	if err != nil {
-		return nil, err
+// minPodsCount returns the minimum number of pods the job can run with, as
+// specified in the min-parallelism annotation, or nil if the job doesn't
+// support partial admission.
+func minPodsCount(job *batchv1.Job) *int32 {
+	value, found := job.Annotations[constants.JobMinParallelismAnnotation]
+	if !found {
+		return nil
 	}
-	w.Spec.Priority = &p
-	w.Spec.PriorityClassName = priorityClassName
-
-	if err := ctrl.SetControllerReference(job, w, scheme); err != nil {
-		return nil, err
+	v, err := strconv.Atoi(value)
+	if err != nil || v <= 0 || int32(v) >= podsCount(&job.Spec) {
+		return nil
 	}
-
-	return w, nil
+	return pointer.Int32(int32(v))
 }
 
-func podsCount(jobSpec *batchv1.JobSpec) int32 {
-	// parallelism is always set as it is otherwise defaulted by k8s to 1
-	podsCount := *(jobSpec.Parallelism)
-	if jobSpec.Completions != nil && *jobSpec.Completions < podsCount {
-		podsCount = *jobSpec.Completions
+func sliceSize(job *batchv1.Job) int32 {
+	v, err := strconv.Atoi(job.Annotations[constants.JobSliceSizeAnnotation])
+	if err != nil || v <= 0 {
+		return 0
 	}
-	return podsCount
+	return int32(v)
 }
 
-func generatePodsReadyCondition(job *batchv1.Job, wl *kueue.Workload) metav1.Condition {
-	conditionStatus := metav1.ConditionFalse
-	message := "Not all pods are ready or succeeded"
-	// Once PodsReady=True it stays as long as the workload remains admitted to
-	// avoid unnecessary flickering the the condition when the pods transition
-	// Ready to Completed. As pods finish, they transition first into the
-	// uncountedTerminatedPods staging area, before passing to the
-	// succeeded/failed counters.
-	if wl.Spec.Admission != nil && (podsReady(job) || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadPodsReady)) {
-		conditionStatus = metav1.ConditionTrue
-		message = "All pods were ready or succeeded since the workload admission"
+// reclaimablePods returns the number of pods of the job that are no longer
+// needed, because the remaining completions are fewer than the parallelism, or
+// nil if there are none.
+func reclaimablePods(job *batchv1.Job) []kueue.ReclaimablePod {
+	parallelism := pointer.Int32Deref(job.Spec.Parallelism, 1)
+	if job.Spec.Completions == nil || job.Status.Succeeded == 0 {
+		return nil
 	}
-	return metav1.Condition{
-		Type:    kueue.WorkloadPodsReady,
-		Status:  conditionStatus,
-		Reason:  "PodsReady",
-		Message: message,
+	remaining := *job.Spec.Completions - job.Status.Succeeded
+	if remaining < 0 {
+		remaining = 0
 	}
+	if remaining >= parallelism {
+		return nil
+	}
+	return []kueue.ReclaimablePod{{
+		Name:  kueue.DefaultPodSetName,
+		Count: parallelism - remaining,
+	}}
 }
 
 func generateFinishedCondition(jobStatus batchv1.JobConditionType) metav1.Condition {
@@ -607,28 +425,6 @@ func jobFinishedCondition(j *batchv1.Job) (batchv1.JobConditionType, bool) {
 	return "", false
 }
 
-func jobSuspended(j *batchv1.Job) bool {
-	return j.Spec.Suspend != nil && *j.Spec.Suspend
-}
-
-func jobAndWorkloadEqual(job *batchv1.Job, wl *kueue.Workload) bool {
-	if len(wl.Spec.PodSets) != 1 {
-		return false
-	}
-	if *job.Spec.Parallelism != wl.Spec.PodSets[0].Count {
-		return false
-	}
-
-	// nodeSelector may change, hence we are not checking for
-	// equality of the whole job.Spec.Template.Spec.
-	if !equality.Semantic.DeepEqual(job.Spec.Template.Spec.InitContainers,
-		wl.Spec.PodSets[0].Spec.InitContainers) {
-		return false
-	}
-	return equality.Semantic.DeepEqual(job.Spec.Template.Spec.Containers,
-		wl.Spec.PodSets[0].Spec.Containers)
-}
-
 func queueName(job *batchv1.Job) string {
 	return job.Annotations[constants.QueueAnnotation]
 }
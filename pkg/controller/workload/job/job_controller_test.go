@@ -19,9 +19,15 @@ package job
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
 	"sigs.k8s.io/kueue/pkg/util/pointer"
+	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
 )
 
 func TestPodsReady(t *testing.T) {
@@ -147,3 +153,272 @@ func TestPodsReady(t *testing.T) {
 		})
 	}
 }
+
+func TestPodsCount(t *testing.T) {
+	testcases := map[string]struct {
+		job  *batchv1.Job
+		want int32
+	}{
+		"parallelism only": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(4).Obj(),
+			want: 4,
+		},
+		"no parallelism": {
+			job: func() *batchv1.Job {
+				j := testingutil.MakeJob("job", "default").Obj()
+				j.Spec.Parallelism = nil
+				return j
+			}(),
+			want: 1,
+		},
+		"indexed job with more completions than parallelism": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(4).Completions(10).CompletionMode(batchv1.IndexedCompletion).Obj(),
+			want: 4,
+		},
+		"indexed job with fewer completions than parallelism": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(4).Completions(2).CompletionMode(batchv1.IndexedCompletion).Obj(),
+			want: 2,
+		},
+		"zero parallelism": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(0).Completions(2).Obj(),
+			want: 0,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := podsCount(&tc.job.Spec); got != tc.want {
+				t.Errorf("podsCount() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEquivalentToWorkload(t *testing.T) {
+	baseJob := func() *testingutil.JobWrapper {
+		return testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2)
+	}
+	pendingWl := func(count int32) *kueue.Workload {
+		return testingutil.MakeWorkload("job", "default").
+			PodSets([]kueue.PodSet{{
+				Name:     kueue.DefaultPodSetName,
+				Count:    count,
+				MinCount: pointer.Int32(2),
+				Spec:     *baseJob().Obj().Spec.Template.Spec.DeepCopy(),
+			}}).
+			Obj()
+	}
+	testcases := map[string]struct {
+		job  *batchv1.Job
+		wl   *kueue.Workload
+		want bool
+	}{
+		"same parallelism": {
+			job:  baseJob().Obj(),
+			wl:   pendingWl(4),
+			want: true,
+		},
+		"parallelism of a suspended job reduced": {
+			job: baseJob().Parallelism(3).Obj(),
+			wl:  pendingWl(4),
+		},
+		"parallelism of a suspended job increased": {
+			job: baseJob().Parallelism(5).Obj(),
+			wl:  pendingWl(4),
+		},
+		"running job partially admitted": {
+			job: baseJob().Parallelism(3).Suspend(false).Obj(),
+			wl: func() *kueue.Workload {
+				wl := pendingWl(4)
+				wl.Spec.Admission = testingutil.MakeAdmission("cq").Obj()
+				wl.Spec.Admission.PodSetFlavors[0].Count = pointer.Int32(3)
+				return wl
+			}(),
+			want: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := (*Job)(tc.job).EquivalentToWorkload(tc.wl); got != tc.want {
+				t.Errorf("EquivalentToWorkload() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMinPodsCount(t *testing.T) {
+	testcases := map[string]struct {
+		job  *batchv1.Job
+		want *int32
+	}{
+		"no annotation": {
+			job: testingutil.MakeJob("job", "default").Parallelism(4).Obj(),
+		},
+		"valid annotation": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).Obj(),
+			want: pointer.Int32(2),
+		},
+		"annotation equal to parallelism": {
+			job: testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(4).Obj(),
+		},
+		"annotation not a number": {
+			job: func() *batchv1.Job {
+				j := testingutil.MakeJob("job", "default").Parallelism(4).Obj()
+				j.Annotations[constants.JobMinParallelismAnnotation] = "two"
+				return j
+			}(),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got := minPodsCount(tc.job)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected minPodsCount (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSliceSize(t *testing.T) {
+	testcases := map[string]struct {
+		job  *batchv1.Job
+		want int32
+	}{
+		"no annotation": {
+			job: testingutil.MakeJob("job", "default").Parallelism(10).Obj(),
+		},
+		"valid annotation": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(10).SliceSize(4).Obj(),
+			want: 4,
+		},
+		"annotation not positive": {
+			job: testingutil.MakeJob("job", "default").Parallelism(10).SliceSize(-1).Obj(),
+		},
+		"annotation not a number": {
+			job: func() *batchv1.Job {
+				j := testingutil.MakeJob("job", "default").Parallelism(10).Obj()
+				j.Annotations[constants.JobSliceSizeAnnotation] = "four"
+				return j
+			}(),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := (*Job)(tc.job).SliceSize(); got != tc.want {
+				t.Errorf("SliceSize() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReclaimablePods(t *testing.T) {
+	testcases := map[string]struct {
+		job  *batchv1.Job
+		want []kueue.ReclaimablePod
+	}{
+		"no succeeded pods": {
+			job: testingutil.MakeJob("job", "default").Parallelism(4).Completions(6).Obj(),
+		},
+		"remaining completions greater than parallelism": {
+			job: testingutil.MakeJob("job", "default").Parallelism(4).Completions(6).Succeeded(2).Obj(),
+		},
+		"remaining completions fewer than parallelism": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(4).Completions(6).Succeeded(3).Obj(),
+			want: []kueue.ReclaimablePod{{Name: kueue.DefaultPodSetName, Count: 1}},
+		},
+		"all completions succeeded": {
+			job:  testingutil.MakeJob("job", "default").Parallelism(4).Completions(6).Succeeded(6).Obj(),
+			want: []kueue.ReclaimablePod{{Name: kueue.DefaultPodSetName, Count: 4}},
+		},
+		"no completions": {
+			job: testingutil.MakeJob("job", "default").Parallelism(4).Succeeded(2).Obj(),
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got := reclaimablePods(tc.job)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected reclaimablePods (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunAndRestorePodSetsInfo(t *testing.T) {
+	job := (*Job)(testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).NodeSelector("zone", "a").Obj())
+	spotToleration := corev1.Toleration{
+		Key:      "instance",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "spot",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+	info := jobframework.PodSetInfo{
+		Name:         kueue.DefaultPodSetName,
+		NodeSelector: map[string]string{"instance": "spot"},
+		Tolerations:  []corev1.Toleration{spotToleration},
+		Labels:       map[string]string{"key": "value"},
+		Count:        pointer.Int32(3),
+	}
+	if err := job.RunWithPodSetsInfo([]jobframework.PodSetInfo{info}); err != nil {
+		t.Fatalf("RunWithPodSetsInfo() returned error: %v", err)
+	}
+	if job.IsSuspended() {
+		t.Errorf("The job is still suspended")
+	}
+	if diff := cmp.Diff(map[string]string{"zone": "a", "instance": "spot"}, job.Spec.Template.Spec.NodeSelector); diff != "" {
+		t.Errorf("Unexpected node selector (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]corev1.Toleration{spotToleration}, job.Spec.Template.Spec.Tolerations); diff != "" {
+		t.Errorf("Unexpected tolerations (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(info.Labels, job.Spec.Template.Labels); diff != "" {
+		t.Errorf("Unexpected labels (-want,+got):\n%s", diff)
+	}
+	if got := *job.Spec.Parallelism; got != 3 {
+		t.Errorf("Parallelism is %d, want 3", got)
+	}
+
+	changed, err := job.RestorePodSetsInfo([]jobframework.PodSetInfo{{
+		Name:         kueue.DefaultPodSetName,
+		NodeSelector: map[string]string{"zone": "a"},
+		Count:        pointer.Int32(4),
+	}})
+	if err != nil {
+		t.Fatalf("RestorePodSetsInfo() returned error: %v", err)
+	}
+	if !changed {
+		t.Errorf("RestorePodSetsInfo() didn't change the job")
+	}
+	if diff := cmp.Diff(map[string]string{"zone": "a"}, job.Spec.Template.Spec.NodeSelector); diff != "" {
+		t.Errorf("Unexpected node selector (-want,+got):\n%s", diff)
+	}
+	if len(job.Spec.Template.Spec.Tolerations) != 0 {
+		t.Errorf("Unexpected tolerations: %v", job.Spec.Template.Spec.Tolerations)
+	}
+	if got := *job.Spec.Parallelism; got != 4 {
+		t.Errorf("Parallelism is %d, want 4", got)
+	}
+}
+
+func TestRunAndRestorePodSetsInfoIndexedJob(t *testing.T) {
+	job := (*Job)(testingutil.MakeJob("job", "default").Parallelism(10).Completions(5).CompletionMode(batchv1.IndexedCompletion).MinParallelism(2).Obj())
+	if err := job.RunWithPodSetsInfo([]jobframework.PodSetInfo{{Name: kueue.DefaultPodSetName, Count: pointer.Int32(3)}}); err != nil {
+		t.Fatalf("RunWithPodSetsInfo() returned error: %v", err)
+	}
+	if got := *job.Spec.Parallelism; got != 3 {
+		t.Errorf("Parallelism is %d, want 3", got)
+	}
+	if got := *job.Spec.Completions; got != 5 {
+		t.Errorf("Completions is %d, want 5", got)
+	}
+	if _, err := job.RestorePodSetsInfo([]jobframework.PodSetInfo{{Name: kueue.DefaultPodSetName, Count: pointer.Int32(5)}}); err != nil {
+		t.Fatalf("RestorePodSetsInfo() returned error: %v", err)
+	}
+	if got := *job.Spec.Parallelism; got != 5 {
+		t.Errorf("Parallelism is %d, want 5", got)
+	}
+}
@@ -18,33 +18,45 @@ package job
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
 	"sigs.k8s.io/kueue/pkg/util/pointer"
 )
 
 type JobWebhook struct {
-	manageJobsWithoutQueueName bool
+	client                       client.Client
+	enabled                      bool
+	manageJobsWithoutQueueName   bool
+	managedJobsNamespaceSelector labels.Selector
+	defaultQueueName             string
+	admissionScope               []string
 }
 
-// SetupWebhook configures the webhook for batchJob.
-func SetupWebhook(mgr ctrl.Manager, opts ...Option) error {
-	options := defaultOptions
-	for _, opt := range opts {
-		opt(&options)
-	}
+// SetupWebhook configures the webhook for batchJob. The webhook only handles
+// the Jobs when the integration is enabled.
+func SetupWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
+	options := jobframework.ProcessOptions(opts...)
 	wh := &JobWebhook{
-		manageJobsWithoutQueueName: options.manageJobsWithoutQueueName,
+		client:                       mgr.GetClient(),
+		enabled:                      options.Enabled,
+		manageJobsWithoutQueueName:   options.ManageJobsWithoutQueueName,
+		managedJobsNamespaceSelector: options.ManagedJobsNamespaceSelector,
+		defaultQueueName:             options.DefaultQueueName,
+		admissionScope:               options.AdmissionScope,
 	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&batchv1.Job{}).
@@ -59,6 +71,9 @@ var _ webhook.CustomDefaulter = &JobWebhook{}
 
 var (
 	parentWorkloadKeyPath = field.NewPath("metadata", "annotations").Key(constants.ParentWorkloadAnnotation)
+	minParallelismKeyPath = field.NewPath("metadata", "annotations").Key(constants.JobMinParallelismAnnotation)
+	sliceSizeKeyPath      = field.NewPath("metadata", "annotations").Key(constants.JobSliceSizeAnnotation)
+	parallelismPath       = field.NewPath("spec", "parallelism")
 )
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the type
@@ -67,10 +82,39 @@ func (w *JobWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	log := ctrl.LoggerFrom(ctx).WithName("job-webhook")
 	log.V(5).Info("Applying defaults", "job", klog.KObj(job))
 
-	if queueName(job) == "" && !w.manageJobsWithoutQueueName {
+	if !w.enabled {
 		return nil
 	}
 
+	inScope, err := jobframework.InAdmissionScope((*Job)(job), w.admissionScope)
+	if err != nil || !inScope {
+		return err
+	}
+
+	if queueName(job) == "" {
+		name, err := jobframework.DefaultQueueName(ctx, w.client, job.Namespace, w.defaultQueueName)
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			log.V(5).Info("Assigning the default LocalQueue", "job", klog.KObj(job), "queue", name)
+			if job.Annotations == nil {
+				job.Annotations = make(map[string]string, 1)
+			}
+			job.Annotations[constants.QueueAnnotation] = name
+		}
+	}
+
+	if queueName(job) == "" {
+		if !w.manageJobsWithoutQueueName {
+			return nil
+		}
+		managed, err := jobframework.NamespaceManaged(ctx, w.client, w.managedJobsNamespaceSelector, job.Namespace)
+		if err != nil || !managed {
+			return err
+		}
+	}
+
 	if !(*job.Spec.Suspend) {
 		job.Spec.Suspend = pointer.Bool(true)
 	}
@@ -84,6 +128,9 @@ var _ webhook.CustomValidator = &JobWebhook{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
 func (w *JobWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	if !w.enabled {
+		return nil
+	}
 	job := obj.(*batchv1.Job)
 	return validateCreate(job)
 }
@@ -94,11 +141,71 @@ func validateCreate(job *batchv1.Job) error {
 			return field.Invalid(parentWorkloadKeyPath, value, strings.Join(errs, ","))
 		}
 	}
+	if err := validateMinParallelism(job); err != nil {
+		return err
+	}
+	return validateSliceSize(job)
+}
+
+func validateMinParallelism(job *batchv1.Job) error {
+	value, exists := job.Annotations[constants.JobMinParallelismAnnotation]
+	if !exists {
+		return nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return field.Invalid(minParallelismKeyPath, value, err.Error())
+	}
+	if v <= 0 || int32(v) >= podsCount(&job.Spec) {
+		return field.Invalid(minParallelismKeyPath, value, "should be positive and less than the job parallelism and completions")
+	}
+	return nil
+}
+
+func validateSliceSize(job *batchv1.Job) error {
+	value, exists := job.Annotations[constants.JobSliceSizeAnnotation]
+	if !exists {
+		return nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return field.Invalid(sliceSizeKeyPath, value, err.Error())
+	}
+	if v <= 0 {
+		return field.Invalid(sliceSizeKeyPath, value, "should be positive")
+	}
+	if _, found := job.Annotations[constants.JobMinParallelismAnnotation]; found {
+		return field.Invalid(sliceSizeKeyPath, value, "cannot be combined with partial admission")
+	}
+	if _, found := job.Annotations[constants.ParentWorkloadAnnotation]; found {
+		return field.Invalid(sliceSizeKeyPath, value, "cannot be set in a job with a parent workload")
+	}
+	return nil
+}
+
+// validatePartialAdmissionUpdate forbids changing the parallelism of a running
+// job that supports partial admission, since the parallelism is set by Kueue
+// to the admitted count, and changing the size of the Workload slices of a
+// running job. They can change when the job is suspended or unsuspended.
+func validatePartialAdmissionUpdate(oldJob, newJob *batchv1.Job) error {
+	if (*Job)(oldJob).IsSuspended() || (*Job)(newJob).IsSuspended() {
+		return nil
+	}
+	if oldJob.Annotations[constants.JobSliceSizeAnnotation] != newJob.Annotations[constants.JobSliceSizeAnnotation] {
+		return field.Forbidden(sliceSizeKeyPath, "cannot change when the job is not suspended")
+	}
+	if _, found := oldJob.Annotations[constants.JobMinParallelismAnnotation]; found &&
+		pointer.Int32Deref(oldJob.Spec.Parallelism, 1) != pointer.Int32Deref(newJob.Spec.Parallelism, 1) {
+		return field.Forbidden(parallelismPath, "cannot change when partial admission is enabled and the job is not suspended")
+	}
 	return nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
 func (w *JobWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	if !w.enabled {
+		return nil
+	}
 	oldJob := oldObj.(*batchv1.Job)
 	newJob := newObj.(*batchv1.Job)
 	log := ctrl.LoggerFrom(ctx).WithName("job-webhook")
@@ -110,18 +217,32 @@ func (w *JobWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.
 func validateUpdate(oldJob, newJob *batchv1.Job) error {
 	suspendPath := field.NewPath("job", "spec", "suspend")
 
-	if queueName(oldJob) == "" && queueName(newJob) != "" && !*newJob.Spec.Suspend {
+	// The importer adds the queue name to the running jobs that it adopts,
+	// once they have an admitted Workload.
+	imported := queueName(oldJob) == "" && newJob.Annotations[constants.ImportedAnnotation] == "true"
+
+	if queueName(oldJob) == "" && queueName(newJob) != "" && !*newJob.Spec.Suspend && !imported {
 		return field.Forbidden(suspendPath, "suspend should be true when adding the queue name")
 	}
 
-	if !*newJob.Spec.Suspend && (queueName(oldJob) != queueName(newJob)) {
+	if !*newJob.Spec.Suspend && (queueName(oldJob) != queueName(newJob)) && !imported {
 		return field.Forbidden(suspendPath, "should not update queue name when job is unsuspend")
 	}
 	if errList := apivalidation.ValidateImmutableField(newJob.Annotations[constants.ParentWorkloadAnnotation],
 		oldJob.Annotations[constants.ParentWorkloadAnnotation], parentWorkloadKeyPath); len(errList) > 0 {
 		return field.Forbidden(parentWorkloadKeyPath, "this annotation is immutable")
 	}
-	return nil
+	if err := validatePartialAdmissionUpdate(oldJob, newJob); err != nil {
+		return err
+	}
+	// The parallelism of a running job is the admitted count, which can be
+	// the min-parallelism.
+	if (*Job)(newJob).IsSuspended() {
+		if err := validateMinParallelism(newJob); err != nil {
+			return err
+		}
+	}
+	return validateSliceSize(newJob)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
@@ -48,6 +48,46 @@ func TestValidateCreate(t *testing.T) {
 			job:     testingutil.MakeJob("job", "default").ParentWorkload("parent workload name").Queue("queue").Obj(),
 			wantErr: field.Invalid(parentWorkloadKeyPath, "parent workload name", `a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
 		},
+		{
+			name:    "valid min-parallelism annotation",
+			job:     testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).Queue("queue").Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "min-parallelism annotation greater than parallelism",
+			job:     testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(5).Queue("queue").Obj(),
+			wantErr: field.Invalid(minParallelismKeyPath, "5", "should be positive and less than the job parallelism and completions"),
+		},
+		{
+			name:    "min-parallelism annotation equal to parallelism",
+			job:     testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(4).Queue("queue").Obj(),
+			wantErr: field.Invalid(minParallelismKeyPath, "4", "should be positive and less than the job parallelism and completions"),
+		},
+		{
+			name:    "min-parallelism annotation greater than the completions of an indexed job",
+			job:     testingutil.MakeJob("job", "default").Parallelism(4).Completions(2).CompletionMode(batchv1.IndexedCompletion).MinParallelism(3).Queue("queue").Obj(),
+			wantErr: field.Invalid(minParallelismKeyPath, "3", "should be positive and less than the job parallelism and completions"),
+		},
+		{
+			name:    "valid slice-size annotation",
+			job:     testingutil.MakeJob("job", "default").Parallelism(10).SliceSize(4).Queue("queue").Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "slice-size annotation not positive",
+			job:     testingutil.MakeJob("job", "default").Parallelism(10).SliceSize(0).Queue("queue").Obj(),
+			wantErr: field.Invalid(sliceSizeKeyPath, "0", "should be positive"),
+		},
+		{
+			name:    "slice-size annotation with min-parallelism annotation",
+			job:     testingutil.MakeJob("job", "default").Parallelism(10).MinParallelism(2).SliceSize(4).Queue("queue").Obj(),
+			wantErr: field.Invalid(sliceSizeKeyPath, "4", "cannot be combined with partial admission"),
+		},
+		{
+			name:    "slice-size annotation with parent-workload annotation",
+			job:     testingutil.MakeJob("job", "default").Parallelism(10).ParentWorkload("parent").SliceSize(4).Obj(),
+			wantErr: field.Invalid(sliceSizeKeyPath, "4", "cannot be set in a job with a parent workload"),
+		},
 	}
 
 	for _, tc := range testcases {
@@ -82,6 +122,18 @@ func TestValidateUpdate(t *testing.T) {
 			newJob:  testingutil.MakeJob("job", "default").Queue("queue").Suspend(false).Obj(),
 			wantErr: field.Forbidden(suspendPath, "suspend should be true when adding the queue name"),
 		},
+		{
+			name:    "add queue name to an imported job with suspend is false",
+			oldJob:  testingutil.MakeJob("job", "default").Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Queue("queue").Imported().Suspend(false).Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "change queue name of an imported job with suspend is false",
+			oldJob:  testingutil.MakeJob("job", "default").Queue("queue").Imported().Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Queue("queue2").Imported().Suspend(false).Obj(),
+			wantErr: field.Forbidden(suspendPath, "should not update queue name when job is unsuspend"),
+		},
 		{
 			name:    "add queue name with suspend is true",
 			oldJob:  testingutil.MakeJob("job", "default").Obj(),
@@ -112,6 +164,60 @@ func TestValidateUpdate(t *testing.T) {
 			newJob:  testingutil.MakeJob("job", "default").Obj(),
 			wantErr: field.Forbidden(parentWorkloadKeyPath, "this annotation is immutable"),
 		},
+		{
+			name:    "change the parallelism of a suspended job with partial admission",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).Queue("queue").Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(6).MinParallelism(2).Queue("queue").Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "reduce the parallelism when unsuspending a job with partial admission",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).Queue("queue").Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(3).MinParallelism(2).Queue("queue").Suspend(false).Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "unsuspend a job with partial admission admitted with its min-parallelism",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).Queue("queue").Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(2).MinParallelism(2).Queue("queue").Suspend(false).Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "reduce the parallelism of a suspended job to its min-parallelism",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).Queue("queue").Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(2).MinParallelism(2).Queue("queue").Obj(),
+			wantErr: field.Invalid(minParallelismKeyPath, "2", "should be positive and less than the job parallelism and completions"),
+		},
+		{
+			name:    "change the parallelism of a running job with partial admission",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(3).MinParallelism(2).Queue("queue").Suspend(false).Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(4).MinParallelism(2).Queue("queue").Suspend(false).Obj(),
+			wantErr: field.Forbidden(parallelismPath, "cannot change when partial admission is enabled and the job is not suspended"),
+		},
+		{
+			name:    "change the slice size of a suspended job",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(10).SliceSize(4).Queue("queue").Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(10).SliceSize(5).Queue("queue").Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "change the slice size of a running job",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(8).SliceSize(4).Queue("queue").Suspend(false).Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(8).SliceSize(5).Queue("queue").Suspend(false).Obj(),
+			wantErr: field.Forbidden(sliceSizeKeyPath, "cannot change when the job is not suspended"),
+		},
+		{
+			name:    "change the parallelism of a running job with workload slices",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(4).SliceSize(4).Queue("queue").Suspend(false).Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(8).SliceSize(4).Queue("queue").Suspend(false).Obj(),
+			wantErr: nil,
+		},
+		{
+			name:    "change the parallelism of a running job without partial admission",
+			oldJob:  testingutil.MakeJob("job", "default").Parallelism(3).Queue("queue").Suspend(false).Obj(),
+			newJob:  testingutil.MakeJob("job", "default").Parallelism(4).Queue("queue").Suspend(false).Obj(),
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IntegrationCallbacks are the functions through which Kueue sets up an
+// integration.
+type IntegrationCallbacks struct {
+	// NewJob returns a new empty job of the integration. Required.
+	NewJob func() GenericJob
+	// SetupIndexes registers the indexes that the controller of the
+	// integration uses. It defaults to the index of the Workloads by their
+	// owner job, which a custom function must register too.
+	SetupIndexes func(ctx context.Context, indexer client.FieldIndexer) error
+	// SetupController sets up the controller of the integration. It defaults
+	// to SetupController for NewJob.
+	SetupController func(mgr ctrl.Manager, opts ...Option) error
+	// SetupWebhook registers the webhook of the integration. The webhook is
+	// always registered, as it's part of the manifests, and only handles the
+	// jobs when the Enabled option is set. Required.
+	SetupWebhook func(mgr ctrl.Manager, opts ...Option) error
+}
+
+var integrations = make(map[string]IntegrationCallbacks)
+
+// RegisterIntegration registers the integration with the given name, which
+// is the name used in the integrations of the configuration. It's meant to be
+// called from the init function of the package of the integration.
+func RegisterIntegration(name string, cb IntegrationCallbacks) error {
+	if _, exists := integrations[name]; exists {
+		return fmt.Errorf("integration %q is already registered", name)
+	}
+	if cb.NewJob == nil {
+		return errors.New("NewJob is required")
+	}
+	if cb.SetupWebhook == nil {
+		return errors.New("SetupWebhook is required")
+	}
+	if cb.SetupIndexes == nil {
+		gvk := cb.NewJob().GVK()
+		cb.SetupIndexes = func(ctx context.Context, indexer client.FieldIndexer) error {
+			return SetupWorkloadOwnerIndex(ctx, indexer, gvk)
+		}
+	}
+	if cb.SetupController == nil {
+		newJob := cb.NewJob
+		cb.SetupController = func(mgr ctrl.Manager, opts ...Option) error {
+			return SetupController(mgr, newJob, opts...)
+		}
+	}
+	integrations[name] = cb
+	return nil
+}
+
+// GetIntegration returns the callbacks of the integration with the given
+// name, and whether it's registered.
+func GetIntegration(name string) (IntegrationCallbacks, bool) {
+	cb, found := integrations[name]
+	return cb, found
+}
+
+// ForEachIntegration calls f for each registered integration, in the order of
+// their names, until it returns an error.
+func ForEachIntegration(f func(name string, cb IntegrationCallbacks) error) error {
+	names := make([]string, 0, len(integrations))
+	for name := range integrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := f(name, integrations[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestRegisterIntegration(t *testing.T) {
+	defer func(saved map[string]IntegrationCallbacks) { integrations = saved }(integrations)
+	integrations = make(map[string]IntegrationCallbacks)
+
+	setupWebhook := func(ctrl.Manager, ...Option) error { return nil }
+	if err := RegisterIntegration("example.com/testjob", IntegrationCallbacks{SetupWebhook: setupWebhook}); err == nil {
+		t.Errorf("RegisterIntegration() didn't fail without NewJob")
+	}
+	if err := RegisterIntegration("example.com/testjob", IntegrationCallbacks{NewJob: newTestJob}); err == nil {
+		t.Errorf("RegisterIntegration() didn't fail without SetupWebhook")
+	}
+	if err := RegisterIntegration("example.com/testjob", IntegrationCallbacks{NewJob: newTestJob, SetupWebhook: setupWebhook}); err != nil {
+		t.Fatalf("RegisterIntegration() returned error: %v", err)
+	}
+	if err := RegisterIntegration("example.com/testjob", IntegrationCallbacks{NewJob: newTestJob, SetupWebhook: setupWebhook}); err == nil {
+		t.Errorf("RegisterIntegration() didn't fail for a registered name")
+	}
+	if err := RegisterIntegration("example.com/otherjob", IntegrationCallbacks{NewJob: newTestJob, SetupWebhook: setupWebhook}); err != nil {
+		t.Fatalf("RegisterIntegration() returned error: %v", err)
+	}
+
+	cb, found := GetIntegration("example.com/testjob")
+	if !found {
+		t.Fatalf("GetIntegration() didn't find the integration")
+	}
+	if cb.SetupIndexes == nil || cb.SetupController == nil {
+		t.Errorf("The callbacks weren't defaulted")
+	}
+	if _, found := GetIntegration("example.com/unknown"); found {
+		t.Errorf("GetIntegration() found an unregistered integration")
+	}
+
+	var names []string
+	if err := ForEachIntegration(func(name string, _ IntegrationCallbacks) error {
+		names = append(names, name)
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEachIntegration() returned error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"example.com/otherjob", "example.com/testjob"}, names); diff != "" {
+		t.Errorf("Unexpected integrations (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jobframework implements the logic that is common to the
+// integrations of the job kinds with Kueue: the creation of a Workload for each
+// job, the suspension of the jobs until their Workload is admitted, the
+// injection of the node selectors and tolerations of the assigned flavors, and the sync of the
+// completion back to the Workload. Each integration only implements the
+// GenericJob interface for its kind, and registers itself with
+// RegisterIntegration, so that it can be enabled in the configuration.
+package jobframework
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+// GenericJob is the interface that the jobs managed by the JobReconciler
+// implement.
+type GenericJob interface {
+	// Object returns the object of the job, which the reconciler reads into.
+	Object() client.Object
+	// GVK returns the GroupVersionKind of the job.
+	GVK() schema.GroupVersionKind
+	// IsSuspended returns whether the job is suspended.
+	IsSuspended() bool
+	// Suspend sets the job as suspended, without updating it.
+	Suspend() error
+	// RunWithPodSetsInfo unsuspends the job, injecting the node selectors,
+	// labels and annotations of the infos into the pod templates of the pod
+	// sets with the same index, without updating it.
+	RunWithPodSetsInfo(infos []PodSetInfo) error
+	// RestorePodSetsInfo restores the node selectors of the pod templates to
+	// the ones of the infos, which are the ones the job was created with. It
+	// returns whether the job changed.
+	RestorePodSetsInfo(infos []PodSetInfo) (bool, error)
+	// Finished returns the condition to set in the Workload when the job
+	// finished, and whether it finished.
+	Finished() (metav1.Condition, bool)
+	// PodSets returns the pod sets of the job, in a stable order.
+	PodSets() ([]kueue.PodSet, error)
+	// IsActive returns whether the job has running pods.
+	IsActive() bool
+	// PodsReady returns whether all the pods of the job are ready.
+	PodsReady() bool
+	// EquivalentToWorkload returns whether the workload was created for the
+	// current spec of the job. Otherwise, the workload is replaced.
+	EquivalentToWorkload(wl *kueue.Workload) bool
+}
+
+// JobWithValidation is implemented by the jobs whose spec has to meet
+// additional requirements to be managed by Kueue. The webhook rejects the jobs
+// that don't meet them.
+type JobWithValidation interface {
+	ValidateCreate() field.ErrorList
+}
+
+// JobWithSkip is implemented by the jobs that Kueue doesn't manage in some
+// cases, for example because they are managed through their owner.
+type JobWithSkip interface {
+	Skip() bool
+}
+
+// JobWithReclaimablePods is implemented by the jobs whose pods can finish
+// before the job does. The quota of the pods that finished and won't be
+// replaced is released.
+type JobWithReclaimablePods interface {
+	ReclaimablePods() []kueue.ReclaimablePod
+}
+
+// JobWithStatusReset is implemented by the jobs whose status has to be reset
+// when they are suspended. ResetStatus returns whether the status changed,
+// in which case the reconciler updates it.
+type JobWithStatusReset interface {
+	ResetStatus() bool
+}
+
+// JobWithFailure is implemented by the jobs that Kueue can mark as failed,
+// when their workloads are deactivated for exceeding the requeuing limit.
+// Fail sets the failure in the status of the job, which the reconciler
+// updates.
+type JobWithFailure interface {
+	Fail(reason, message string)
+}
+
+// JobWithParentWorkload is implemented by the jobs that can run as part of the
+// Workload of another job, instead of having their own. ParentWorkload returns
+// the name of that Workload, in the namespace of the job, or an empty string
+// if the job has its own Workload.
+type JobWithParentWorkload interface {
+	ParentWorkload() string
+}
+
+// JobWithCustomWorkloadName is implemented by the jobs whose Workload isn't
+// named after the kind and the name of the job, as returned by WorkloadName.
+type JobWithCustomWorkloadName interface {
+	WorkloadName() string
+}
+
+// JobWithCustomQueueName is implemented by the jobs whose queue name isn't
+// in the queue-name annotation.
+type JobWithCustomQueueName interface {
+	QueueName() string
+}
+
+// JobWithSlices is implemented by the jobs whose only pod set can be split into
+// multiple Workload slices, each with a part of its pods, that are admitted
+// independently. The job runs with the pods of the admitted slices, which it
+// receives as the count of the info in RunWithPodSetsInfo.
+type JobWithSlices interface {
+	// SliceSize returns the maximum number of pods of each slice, or 0 if the
+	// job isn't split.
+	SliceSize() int32
+}
+
+// PodSetInfo holds the changes to apply to the pod template of a pod set on
+// admission.
+type PodSetInfo struct {
+	Name         string
+	NodeSelector map[string]string
+	// Tolerations are added to the pod template on admission. On restore,
+	// they are the original tolerations of the pod template.
+	Tolerations []corev1.Toleration
+	Labels      map[string]string
+	Annotations map[string]string
+	// Count is the number of pods that the pod set has to run with, when it
+	// differs from the count of its pod set in the Workload, because it was
+	// partially admitted. On restore, it's the original count.
+	Count *int32
+}
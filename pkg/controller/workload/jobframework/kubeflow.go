@@ -0,0 +1,220 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+// KubeflowJob is an unstructured job of the Kubeflow operators. Their jobs
+// hold the specs of their replicas in a map keyed by replica type, and are
+// suspended through their run policy. Each replica type is a pod set, named
+// after the type in lowercase.
+type KubeflowJob struct {
+	u                 unstructured.Unstructured
+	gvk               schema.GroupVersionKind
+	replicaSpecsField string
+	replicaTypes      []string
+}
+
+var _ GenericJob = &KubeflowJob{}
+
+// NewKubeflowJob returns an empty job of the given kind, whose replica specs
+// are in the given field of the spec. The replica types are in the order of
+// their pod sets.
+func NewKubeflowJob(gvk schema.GroupVersionKind, replicaSpecsField string, replicaTypes []string) *KubeflowJob {
+	j := &KubeflowJob{
+		gvk:               gvk,
+		replicaSpecsField: replicaSpecsField,
+		replicaTypes:      replicaTypes,
+	}
+	j.u.SetGroupVersionKind(gvk)
+	return j
+}
+
+func (j *KubeflowJob) Object() client.Object {
+	return &j.u
+}
+
+func (j *KubeflowJob) GVK() schema.GroupVersionKind {
+	return j.gvk
+}
+
+func (j *KubeflowJob) IsSuspended() bool {
+	suspend, _, _ := unstructured.NestedBool(j.u.Object, "spec", "runPolicy", "suspend")
+	return suspend
+}
+
+func (j *KubeflowJob) Suspend() error {
+	return unstructured.SetNestedField(j.u.Object, true, "spec", "runPolicy", "suspend")
+}
+
+func (j *KubeflowJob) RunWithPodSetsInfo(infos []PodSetInfo) error {
+	templates, _, _, err := j.templates()
+	if err != nil {
+		return err
+	}
+	if len(templates) != len(infos) {
+		return fmt.Errorf("expecting %d pod sets, got %d", len(templates), len(infos))
+	}
+	for i := range templates {
+		if err := ApplyPodSetInfo(templates[i], &infos[i]); err != nil {
+			return err
+		}
+	}
+	return unstructured.SetNestedField(j.u.Object, false, "spec", "runPolicy", "suspend")
+}
+
+func (j *KubeflowJob) RestorePodSetsInfo(infos []PodSetInfo) (bool, error) {
+	templates, _, _, err := j.templates()
+	if err != nil {
+		return false, err
+	}
+	if len(templates) != len(infos) {
+		return false, fmt.Errorf("expecting %d pod sets, got %d", len(templates), len(infos))
+	}
+	changed := false
+	for i := range templates {
+		c, err := RestorePodSetInfo(templates[i], &infos[i])
+		if err != nil {
+			return false, err
+		}
+		changed = changed || c
+	}
+	return changed, nil
+}
+
+// Finished returns whether the job has the Succeeded or Failed condition.
+func (j *KubeflowJob) Finished() (metav1.Condition, bool) {
+	conditions, _, _ := unstructured.NestedSlice(j.u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		switch condition["type"] {
+		case "Succeeded":
+			if message == "" {
+				message = "Job finished successfully"
+			}
+		case "Failed":
+			if message == "" {
+				message = "Job failed"
+			}
+		default:
+			continue
+		}
+		return metav1.Condition{
+			Type:    kueue.WorkloadFinished,
+			Status:  metav1.ConditionTrue,
+			Reason:  "JobFinished",
+			Message: message,
+		}, true
+	}
+	return metav1.Condition{}, false
+}
+
+func (j *KubeflowJob) PodSets() ([]kueue.PodSet, error) {
+	templates, rts, counts, err := j.templates()
+	if err != nil {
+		return nil, err
+	}
+	podSets := make([]kueue.PodSet, len(templates))
+	for i := range templates {
+		if podSets[i], err = PodSetFromTemplate(templates[i], strings.ToLower(rts[i]), counts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return podSets, nil
+}
+
+func (j *KubeflowJob) IsActive() bool {
+	for _, rt := range j.replicaTypes {
+		if active, _, _ := unstructured.NestedInt64(j.u.Object, "status", "replicaStatuses", rt, "active"); active > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// PodsReady returns whether the pods of all the replicas are running or
+// succeeded.
+func (j *KubeflowJob) PodsReady() bool {
+	_, rts, counts, err := j.templates()
+	if err != nil {
+		return false
+	}
+	for i, rt := range rts {
+		active, _, _ := unstructured.NestedInt64(j.u.Object, "status", "replicaStatuses", rt, "active")
+		succeeded, _, _ := unstructured.NestedInt64(j.u.Object, "status", "replicaStatuses", rt, "succeeded")
+		if int32(active+succeeded) < counts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (j *KubeflowJob) EquivalentToWorkload(wl *kueue.Workload) bool {
+	podSets, err := j.PodSets()
+	return err == nil && PodSetsEquivalent(wl.Spec.PodSets, podSets)
+}
+
+// templates returns the pod templates of the replicas of the job, without
+// copying them, and their replica types and counts. The pod sets are named
+// after the replica types, in lowercase.
+func (j *KubeflowJob) templates() ([]map[string]interface{}, []string, []int32, error) {
+	var templates []map[string]interface{}
+	var rts []string
+	var counts []int32
+	for _, rt := range j.replicaTypes {
+		spec, found, err := unstructured.NestedFieldNoCopy(j.u.Object, "spec", j.replicaSpecsField, rt)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !found {
+			continue
+		}
+		replicaSpec, ok := spec.(map[string]interface{})
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("invalid %s.%s", j.replicaSpecsField, rt)
+		}
+		template, ok := replicaSpec["template"].(map[string]interface{})
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("missing %s.%s.template", j.replicaSpecsField, rt)
+		}
+		replicas, found, err := unstructured.NestedInt64(replicaSpec, "replicas")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !found {
+			replicas = 1
+		}
+		templates = append(templates, template)
+		rts = append(rts, rt)
+		counts = append(counts, int32(replicas))
+	}
+	return templates, rts, counts, nil
+}
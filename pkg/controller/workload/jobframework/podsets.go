@@ -0,0 +1,273 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+)
+
+// PodSetFromTemplate returns the pod set with the given name and count for the
+// pod template of an unstructured job.
+func PodSetFromTemplate(template map[string]interface{}, name string, count int32) (kueue.PodSet, error) {
+	var t corev1.PodTemplateSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, &t); err != nil {
+		return kueue.PodSet{}, err
+	}
+	return kueue.PodSet{
+		Name:            name,
+		Spec:            t.Spec,
+		Count:           count,
+		TopologyRequest: TopologyRequest(t.Annotations),
+		FlavorAffinity:  FlavorAffinity(t.Annotations),
+	}, nil
+}
+
+// TopologyRequest returns the topology requested in the annotations of a pod
+// template, or nil if there is none. The required topology takes precedence
+// over the preferred one.
+func TopologyRequest(annotations map[string]string) *kueue.PodSetTopologyRequest {
+	if level, found := annotations[constants.PodSetRequiredTopologyAnnotation]; found {
+		return &kueue.PodSetTopologyRequest{Required: &level}
+	}
+	if level, found := annotations[constants.PodSetPreferredTopologyAnnotation]; found {
+		return &kueue.PodSetTopologyRequest{Preferred: &level}
+	}
+	return nil
+}
+
+// FlavorAffinity returns the flavors required or preferred in the annotations
+// of a pod template, or nil if there are none.
+func FlavorAffinity(annotations map[string]string) *kueue.PodSetFlavorAffinity {
+	affinity := kueue.PodSetFlavorAffinity{
+		Required:  splitFlavors(annotations[constants.PodSetRequiredFlavorsAnnotation]),
+		Preferred: splitFlavors(annotations[constants.PodSetPreferredFlavorsAnnotation]),
+	}
+	if len(affinity.Required) == 0 && len(affinity.Preferred) == 0 {
+		return nil
+	}
+	return &affinity
+}
+
+func splitFlavors(value string) []string {
+	var flavors []string
+	for _, f := range strings.Split(value, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			flavors = append(flavors, f)
+		}
+	}
+	return flavors
+}
+
+// ApplyPodSetInfo merges the node selector, tolerations, labels and
+// annotations of the info into the pod template of an unstructured job.
+func ApplyPodSetInfo(template map[string]interface{}, info *PodSetInfo) error {
+	if err := mergeNestedStringMap(template, info.Labels, "metadata", "labels"); err != nil {
+		return err
+	}
+	if err := mergeNestedStringMap(template, info.Annotations, "metadata", "annotations"); err != nil {
+		return err
+	}
+	if err := MergeNestedTolerations(template, info.Tolerations); err != nil {
+		return err
+	}
+	return mergeNestedStringMap(template, info.NodeSelector, "spec", "nodeSelector")
+}
+
+// RestorePodSetInfo sets the node selector and the tolerations of the pod
+// template of an unstructured job to the ones of the info. It returns whether
+// the pod template changed.
+func RestorePodSetInfo(template map[string]interface{}, info *PodSetInfo) (bool, error) {
+	changed, err := RestoreNodeSelector(template, info.NodeSelector)
+	if err != nil {
+		return false, err
+	}
+	c, err := restoreTolerations(template, info.Tolerations)
+	return changed || c, err
+}
+
+// RestoreNodeSelector sets the node selector of the pod template of an
+// unstructured job. It returns whether the node selector changed.
+func RestoreNodeSelector(template map[string]interface{}, nodeSelector map[string]string) (bool, error) {
+	current, _, err := unstructured.NestedStringMap(template, "spec", "nodeSelector")
+	if err != nil {
+		return false, err
+	}
+	if len(current) == 0 && len(nodeSelector) == 0 || equality.Semantic.DeepEqual(current, nodeSelector) {
+		return false, nil
+	}
+	if len(nodeSelector) == 0 {
+		unstructured.RemoveNestedField(template, "spec", "nodeSelector")
+		return true, nil
+	}
+	return true, unstructured.SetNestedStringMap(template, nodeSelector, "spec", "nodeSelector")
+}
+
+// PodSetsEquivalent returns whether the pod sets of a workload match the ones
+// of its job. The node selectors may change on admission, hence only the
+// counts and the containers are compared. The count of a pod set of the job
+// may also be reduced on admission, if the pod set supports partial admission.
+func PodSetsEquivalent(wlPodSets, jobPodSets []kueue.PodSet) bool {
+	if len(wlPodSets) != len(jobPodSets) {
+		return false
+	}
+	for i := range wlPodSets {
+		a, b := &wlPodSets[i], &jobPodSets[i]
+		if a.Name != b.Name {
+			return false
+		}
+		if a.Count != b.Count && !withinPartialAdmissionRange(a, b.Count) {
+			return false
+		}
+		if !equality.Semantic.DeepEqual(a.Spec.InitContainers, b.Spec.InitContainers) ||
+			!equality.Semantic.DeepEqual(a.Spec.Containers, b.Spec.Containers) {
+			return false
+		}
+	}
+	return true
+}
+
+// PodSetsHash returns a hash of the pod sets of a job, ignoring their counts,
+// which may be reduced on admission. It captures the changes to the pod
+// templates that PodSetsEquivalent doesn't compare, like the priority class or
+// the node selector, and it is immune to the defaults that the Workload webhook
+// sets in the containers.
+func PodSetsHash(podSets []kueue.PodSet) string {
+	h := sha256.New()
+	for i := range podSets {
+		ps := podSets[i]
+		ps.Count = 0
+		// The pod sets come from the API objects, so they can always be
+		// encoded.
+		b, _ := json.Marshal(&ps)
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// withinPartialAdmissionRange returns whether the podSet supports partial
+// admission and the given count is within the accepted range, which means
+// that the count of the job could have been reduced on admission.
+func withinPartialAdmissionRange(ps *kueue.PodSet, count int32) bool {
+	return ps.MinCount != nil && *ps.MinCount <= count && count <= ps.Count
+}
+
+// MergeTolerations returns the tolerations with the extra ones that they don't
+// have already.
+func MergeTolerations(tolerations, extra []corev1.Toleration) []corev1.Toleration {
+	for _, e := range extra {
+		found := false
+		for i := range tolerations {
+			if tolerations[i].MatchToleration(&e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tolerations = append(tolerations, e)
+		}
+	}
+	return tolerations
+}
+
+// MergeNestedTolerations adds the tolerations to the spec of an unstructured
+// pod or pod template, unless it has them already.
+func MergeNestedTolerations(obj map[string]interface{}, tolerations []corev1.Toleration) error {
+	if len(tolerations) == 0 {
+		return nil
+	}
+	current, err := nestedTolerations(obj)
+	if err != nil {
+		return err
+	}
+	return setNestedTolerations(obj, MergeTolerations(current, tolerations))
+}
+
+func restoreTolerations(template map[string]interface{}, tolerations []corev1.Toleration) (bool, error) {
+	current, err := nestedTolerations(template)
+	if err != nil {
+		return false, err
+	}
+	if len(current) == 0 && len(tolerations) == 0 || equality.Semantic.DeepEqual(current, tolerations) {
+		return false, nil
+	}
+	if len(tolerations) == 0 {
+		unstructured.RemoveNestedField(template, "spec", "tolerations")
+		return true, nil
+	}
+	return true, setNestedTolerations(template, tolerations)
+}
+
+func nestedTolerations(obj map[string]interface{}) ([]corev1.Toleration, error) {
+	values, _, err := unstructured.NestedSlice(obj, "spec", "tolerations")
+	if err != nil {
+		return nil, err
+	}
+	var tolerations []corev1.Toleration
+	for _, v := range values {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected toleration %v", v)
+		}
+		var t corev1.Toleration
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &t); err != nil {
+			return nil, err
+		}
+		tolerations = append(tolerations, t)
+	}
+	return tolerations, nil
+}
+
+func setNestedTolerations(obj map[string]interface{}, tolerations []corev1.Toleration) error {
+	values := make([]interface{}, len(tolerations))
+	for i := range tolerations {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&tolerations[i])
+		if err != nil {
+			return err
+		}
+		values[i] = m
+	}
+	return unstructured.SetNestedSlice(obj, values, "spec", "tolerations")
+}
+
+func mergeNestedStringMap(obj map[string]interface{}, values map[string]string, fields ...string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	m, _, err := unstructured.NestedStringMap(obj, fields...)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = make(map[string]string, len(values))
+	}
+	for k, v := range values {
+		m[k] = v
+	}
+	return unstructured.SetNestedStringMap(obj, m, fields...)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/util/pointer"
+)
+
+func TestTopologyRequest(t *testing.T) {
+	testcases := map[string]struct {
+		annotations map[string]string
+		want        *kueue.PodSetTopologyRequest
+	}{
+		"no annotations": {},
+		"required topology": {
+			annotations: map[string]string{constants.PodSetRequiredTopologyAnnotation: "cloud.provider.com/rack"},
+			want:        &kueue.PodSetTopologyRequest{Required: pointer.String("cloud.provider.com/rack")},
+		},
+		"preferred topology": {
+			annotations: map[string]string{constants.PodSetPreferredTopologyAnnotation: "cloud.provider.com/rack"},
+			want:        &kueue.PodSetTopologyRequest{Preferred: pointer.String("cloud.provider.com/rack")},
+		},
+		"required topology takes precedence": {
+			annotations: map[string]string{
+				constants.PodSetRequiredTopologyAnnotation:  "cloud.provider.com/block",
+				constants.PodSetPreferredTopologyAnnotation: "cloud.provider.com/rack",
+			},
+			want: &kueue.PodSetTopologyRequest{Required: pointer.String("cloud.provider.com/block")},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got := TopologyRequest(tc.annotations)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected TopologyRequest (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFlavorAffinity(t *testing.T) {
+	testcases := map[string]struct {
+		annotations map[string]string
+		want        *kueue.PodSetFlavorAffinity
+	}{
+		"no annotations": {},
+		"required flavors": {
+			annotations: map[string]string{constants.PodSetRequiredFlavorsAnnotation: "a100, h100"},
+			want:        &kueue.PodSetFlavorAffinity{Required: []string{"a100", "h100"}},
+		},
+		"required and preferred flavors": {
+			annotations: map[string]string{
+				constants.PodSetRequiredFlavorsAnnotation:  "a100,h100",
+				constants.PodSetPreferredFlavorsAnnotation: "h100",
+			},
+			want: &kueue.PodSetFlavorAffinity{
+				Required:  []string{"a100", "h100"},
+				Preferred: []string{"h100"},
+			},
+		},
+		"empty annotation": {
+			annotations: map[string]string{constants.PodSetPreferredFlavorsAnnotation: " , "},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got := FlavorAffinity(tc.annotations)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected FlavorAffinity (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPodSetsEquivalent(t *testing.T) {
+	podSet := func(name string, count int32, image string) kueue.PodSet {
+		return kueue.PodSet{
+			Name:  name,
+			Count: count,
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "c", Image: image}},
+			},
+		}
+	}
+	wlPodSet := podSet("main", 4, "img")
+	wlPodSet.MinCount = pointer.Int32(2)
+	wlPodSets := []kueue.PodSet{wlPodSet}
+	testcases := map[string]struct {
+		jobPodSets []kueue.PodSet
+		want       bool
+	}{
+		"same pod sets": {
+			jobPodSets: []kueue.PodSet{podSet("main", 4, "img")},
+			want:       true,
+		},
+		"count reduced on admission": {
+			jobPodSets: []kueue.PodSet{podSet("main", 3, "img")},
+			want:       true,
+		},
+		"count below the minimum": {
+			jobPodSets: []kueue.PodSet{podSet("main", 1, "img")},
+		},
+		"different name": {
+			jobPodSets: []kueue.PodSet{podSet("other", 4, "img")},
+		},
+		"different containers": {
+			jobPodSets: []kueue.PodSet{podSet("main", 4, "other-img")},
+		},
+		"different number of pod sets": {
+			jobPodSets: []kueue.PodSet{podSet("main", 4, "img"), podSet("other", 1, "img")},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := PodSetsEquivalent(wlPodSets, tc.jobPodSets); got != tc.want {
+				t.Errorf("PodSetsEquivalent() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyAndRestorePodSetInfo(t *testing.T) {
+	original := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"nodeSelector": map[string]interface{}{"zone": "a"},
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "team", "operator": "Exists", "effect": "NoSchedule"},
+			},
+		},
+	}
+	teamToleration := corev1.Toleration{Key: "team", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	poolToleration := corev1.Toleration{Key: "pool", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	template := runtime.DeepCopyJSON(original)
+
+	err := ApplyPodSetInfo(template, &PodSetInfo{
+		NodeSelector: map[string]string{"instance": "spot"},
+		Tolerations:  []corev1.Toleration{teamToleration, poolToleration},
+	})
+	if err != nil {
+		t.Fatalf("ApplyPodSetInfo() returned error: %v", err)
+	}
+	gotTolerations, err := nestedTolerations(template)
+	if err != nil {
+		t.Fatalf("Reading the tolerations: %v", err)
+	}
+	if diff := cmp.Diff([]corev1.Toleration{teamToleration, poolToleration}, gotTolerations); diff != "" {
+		t.Errorf("Unexpected tolerations after applying (-want,+got):\n%s", diff)
+	}
+
+	changed, err := RestorePodSetInfo(template, &PodSetInfo{
+		NodeSelector: map[string]string{"zone": "a"},
+		Tolerations:  []corev1.Toleration{teamToleration},
+	})
+	if err != nil {
+		t.Fatalf("RestorePodSetInfo() returned error: %v", err)
+	}
+	if !changed {
+		t.Error("RestorePodSetInfo() didn't change the template")
+	}
+	if diff := cmp.Diff(original, template); diff != "" {
+		t.Errorf("Unexpected template after restoring (-want,+got):\n%s", diff)
+	}
+}
+
+func TestPodSetsHash(t *testing.T) {
+	podSet := kueue.PodSet{
+		Name:  kueue.DefaultPodSetName,
+		Count: 4,
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "c", Image: "img"}},
+		},
+	}
+	base := PodSetsHash([]kueue.PodSet{podSet})
+	cases := map[string]struct {
+		mutate   func(*kueue.PodSet)
+		wantSame bool
+	}{
+		"same pod set": {
+			mutate:   func(*kueue.PodSet) {},
+			wantSame: true,
+		},
+		"count reduced": {
+			mutate:   func(ps *kueue.PodSet) { ps.Count = 2 },
+			wantSame: true,
+		},
+		"empty node selector": {
+			mutate:   func(ps *kueue.PodSet) { ps.Spec.NodeSelector = map[string]string{} },
+			wantSame: true,
+		},
+		"node selector added": {
+			mutate: func(ps *kueue.PodSet) { ps.Spec.NodeSelector = map[string]string{"instance": "spot"} },
+		},
+		"priority class changed": {
+			mutate: func(ps *kueue.PodSet) { ps.Spec.PriorityClassName = "high" },
+		},
+		"image changed": {
+			mutate: func(ps *kueue.PodSet) { ps.Spec.Containers[0].Image = "img:v2" },
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ps := *podSet.DeepCopy()
+			tc.mutate(&ps)
+			if got := PodSetsHash([]kueue.PodSet{ps}) == base; got != tc.wantSame {
+				t.Errorf("Same hash is %t, want %t", got, tc.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,926 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// JobReconciler reconciles the jobs of a kind that implements GenericJob.
+type JobReconciler struct {
+	client                       client.Client
+	scheme                       *runtime.Scheme
+	record                       record.EventRecorder
+	newJob                       func() GenericJob
+	manageJobsWithoutQueueName   bool
+	managedJobsNamespaceSelector labels.Selector
+	waitForPodsReady             bool
+	deactivatedJobPolicy         config.DeactivatedJobPolicy
+	admissionScope               []string
+}
+
+// Options are the options of the reconcilers and the webhooks of the
+// integrations.
+type Options struct {
+	ManageJobsWithoutQueueName   bool
+	ManagedJobsNamespaceSelector labels.Selector
+	WaitForPodsReady             bool
+	Enabled                      bool
+	DefaultQueueName             string
+	DeactivatedJobPolicy         config.DeactivatedJobPolicy
+	AdmissionScope               []string
+}
+
+// Option configures the reconciler or the webhook.
+type Option func(*Options)
+
+// WithManageJobsWithoutQueueName indicates if the controller should reconcile
+// jobs that don't set the queue name annotation.
+func WithManageJobsWithoutQueueName(f bool) Option {
+	return func(o *Options) {
+		o.ManageJobsWithoutQueueName = f
+	}
+}
+
+// WithManagedJobsNamespaceSelector limits the namespaces whose jobs without
+// queue name are managed, when manageJobsWithoutQueueName is enabled, to the
+// ones whose labels match the selector. All the namespaces match a nil
+// selector.
+func WithManagedJobsNamespaceSelector(s labels.Selector) Option {
+	return func(o *Options) {
+		o.ManagedJobsNamespaceSelector = s
+	}
+}
+
+// WithWaitForPodsReady indicates if the controller should add the PodsReady
+// condition to the workload when the corresponding job has all pods ready.
+func WithWaitForPodsReady(f bool) Option {
+	return func(o *Options) {
+		o.WaitForPodsReady = f
+	}
+}
+
+// WithEnabled indicates if the webhook should handle the jobs, which is the
+// case when the integration is enabled.
+func WithEnabled(f bool) Option {
+	return func(o *Options) {
+		o.Enabled = f
+	}
+}
+
+// WithDefaultQueueName sets the name of the LocalQueue that the webhook
+// assigns to the jobs without queue name, in the namespaces that have a
+// LocalQueue with that name.
+func WithDefaultQueueName(name string) Option {
+	return func(o *Options) {
+		o.DefaultQueueName = name
+	}
+}
+
+// WithDeactivatedJobPolicy sets what the controller does with the jobs whose
+// workloads are deactivated for exceeding the requeuing limit.
+func WithDeactivatedJobPolicy(p config.DeactivatedJobPolicy) Option {
+	return func(o *Options) {
+		o.DeactivatedJobPolicy = p
+	}
+}
+
+// WithAdmissionScope limits the jobs that are managed to the ones that request
+// a resource whose name starts with one of the given prefixes. All the jobs
+// are in the scope of an empty list.
+func WithAdmissionScope(resourcePrefixes []string) Option {
+	return func(o *Options) {
+		o.AdmissionScope = resourcePrefixes
+	}
+}
+
+var defaultOptions = Options{}
+
+// ProcessOptions returns the options resulting of applying opts to the
+// defaults.
+func ProcessOptions(opts ...Option) Options {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// NewReconciler returns a reconciler for the jobs returned by newJob, which
+// must return a new empty job on each call.
+func NewReconciler(
+	scheme *runtime.Scheme,
+	client client.Client,
+	record record.EventRecorder,
+	newJob func() GenericJob,
+	opts ...Option) *JobReconciler {
+	options := ProcessOptions(opts...)
+	return &JobReconciler{
+		scheme:                       scheme,
+		client:                       client,
+		record:                       record,
+		newJob:                       newJob,
+		manageJobsWithoutQueueName:   options.ManageJobsWithoutQueueName,
+		managedJobsNamespaceSelector: options.ManagedJobsNamespaceSelector,
+		waitForPodsReady:             options.WaitForPodsReady,
+		deactivatedJobPolicy:         options.DeactivatedJobPolicy,
+		admissionScope:               options.AdmissionScope,
+	}
+}
+
+// OwnerReferenceIndexKey returns the key of the index of the Workloads by the
+// name of the job of the given kind that owns them.
+func OwnerReferenceIndexKey(gvk schema.GroupVersionKind) string {
+	return ".metadata.ownerReferences[" + gvk.Group + "." + gvk.Kind + "]"
+}
+
+// SetupWorkloadOwnerIndex indexes the Workloads by the name of the job of the
+// given kind that owns them, which the reconciler uses to find the Workloads
+// of a job.
+func SetupWorkloadOwnerIndex(ctx context.Context, indexer client.FieldIndexer, gvk schema.GroupVersionKind) error {
+	return indexer.IndexField(ctx, &kueue.Workload{}, OwnerReferenceIndexKey(gvk), func(o client.Object) []string {
+		wl := o.(*kueue.Workload)
+		owner := metav1.GetControllerOf(wl)
+		if owner == nil || owner.APIVersion != gvk.GroupVersion().String() || owner.Kind != gvk.Kind {
+			return nil
+		}
+		return []string{owner.Name}
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *JobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	job := r.newJob()
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(job.GVK().Kind)).
+		For(job.Object()).
+		Owns(&kueue.Workload{}).
+		Complete(r)
+}
+
+// SetupController sets up the reconciler of the jobs returned by newJob, if
+// the API server serves their kind.
+func SetupController(mgr ctrl.Manager, newJob func() GenericJob, opts ...Option) error {
+	gvk := newJob().GVK()
+	available, err := IsAPIAvailable(mgr, gvk)
+	if err != nil {
+		return err
+	}
+	if !available {
+		ctrl.Log.WithName("jobframework").Info("The API is not served, skipping the controller", "kind", gvk)
+		return nil
+	}
+	return NewReconciler(mgr.GetScheme(),
+		mgr.GetClient(),
+		mgr.GetEventRecorderFor(ControllerName(gvk)),
+		newJob,
+		opts...,
+	).SetupWithManager(mgr)
+}
+
+// WorkloadName returns the name of the Workload of the job with the given
+// name and kind. The kind is part of the name, so that jobs of different kinds
+// can have the same name.
+func WorkloadName(gvk schema.GroupVersionKind, name string) string {
+	return strings.ToLower(gvk.Kind) + "-" + name
+}
+
+func workloadName(job GenericJob) string {
+	if j, ok := job.(JobWithCustomWorkloadName); ok {
+		return j.WorkloadName()
+	}
+	return WorkloadName(job.GVK(), job.Object().GetName())
+}
+
+// ControllerName returns the name of the controller of the jobs of the given
+// kind, which is used as the name of its event recorder.
+func ControllerName(gvk schema.GroupVersionKind) string {
+	return constants.KueueName + "-" + strings.ToLower(gvk.Kind) + "-controller"
+}
+
+func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	job := r.newJob()
+	object := job.Object()
+	if err := r.client.Get(ctx, req.NamespacedName, object); err != nil {
+		// we'll ignore not-found errors, since there is nothing to do.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log := ctrl.LoggerFrom(ctx).WithValues(strings.ToLower(job.GVK().Kind), klog.KObj(object))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if j, ok := job.(JobWithSkip); ok && j.Skip() {
+		log.V(3).Info("Job not managed by Kueue, ignoring")
+		return ctrl.Result{}, nil
+	}
+	pwName := parentWorkloadName(job)
+
+	// when manageJobsWithoutQueueName is disabled we only reconcile jobs that have either
+	// queue-name or the parent-workload annotation set. When it's enabled, the
+	// jobs in the namespaces that opted out are not reconciled either.
+	if QueueName(job) == "" && pwName == "" {
+		if !r.manageJobsWithoutQueueName {
+			log.V(3).Info(fmt.Sprintf("Neither %s, nor %s annotation is set, ignoring the job", constants.QueueAnnotation, constants.ParentWorkloadAnnotation))
+			return ctrl.Result{}, nil
+		}
+		managed, err := NamespaceManaged(ctx, r.client, r.managedJobsNamespaceSelector, object.GetNamespace())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !managed {
+			log.V(3).Info("The jobs without queue name in the namespace are not managed, ignoring the job")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if pwName == "" {
+		inScope, err := InAdmissionScope(job, r.admissionScope)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !inScope {
+			log.V(3).Info("The job doesn't request any resource in the admission scope, ignoring the job")
+			return ctrl.Result{}, nil
+		}
+	}
+
+	log.V(2).Info("Reconciling Job")
+
+	if j, ok := job.(JobWithSlices); ok && pwName == "" {
+		if size := j.SliceSize(); size > 0 {
+			err := r.reconcileSlices(ctx, job, size)
+			if err != nil {
+				log.Error(err, "Reconciling workload slices")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	// 1. make sure there is only a single existing instance of the workload.
+	wl, err := r.ensureOneWorkload(ctx, job)
+	if err != nil {
+		log.Error(err, "Getting existing workloads")
+		return ctrl.Result{}, err
+	}
+
+	finishedCond, finished := job.Finished()
+	// 2. create a new workload if none exists.
+	if wl == nil {
+		// Nothing to do if the job is finished, or if it waits for its parent
+		// workload.
+		if finished || pwName != "" {
+			return ctrl.Result{}, nil
+		}
+		err := r.handleJobWithNoWorkload(ctx, job)
+		if err != nil {
+			log.Error(err, "Handling job with no workload")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if pwName == "" {
+		// 3. handle a finished job, if it's the main job.
+		if finished {
+			if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+				return ctrl.Result{}, nil
+			}
+			apimeta.SetStatusCondition(&wl.Status.Conditions, finishedCond)
+			err := r.client.Status().Update(ctx, wl)
+			if err != nil {
+				log.Error(err, "Updating workload status")
+			}
+			return ctrl.Result{}, err
+		}
+
+		// handle an evicted workload, if it's the main job. The child jobs
+		// are suspended once the admission of the workload is cleared.
+		if workload.IsEvicted(wl) && wl.Spec.Admission != nil {
+			err := r.handleEvictedWorkload(ctx, job, wl)
+			if err != nil {
+				log.Error(err, "Handling evicted workload")
+			}
+			return ctrl.Result{}, err
+		}
+
+		// handle a job whose workload was deactivated for exceeding the
+		// requeuing limit, if the policy doesn't keep it suspended.
+		if job.IsSuspended() && workload.IsDeactivatedByRequeuingLimit(wl) &&
+			(r.deactivatedJobPolicy == config.DeactivatedJobFail || r.deactivatedJobPolicy == config.DeactivatedJobDelete) {
+			err := r.handleDeactivatedJob(ctx, job, wl)
+			if err != nil {
+				log.Error(err, "Handling job with a deactivated workload")
+			}
+			return ctrl.Result{}, err
+		}
+
+		// handle a job when waitForPodsReady is enabled, and it is the main job
+		if r.waitForPodsReady {
+			log.V(5).Info("Handling a job when waitForPodsReady is enabled")
+			condition := podsReadyCondition(job, wl)
+			// optimization to avoid sending the update request if the status didn't change
+			if !apimeta.IsStatusConditionPresentAndEqual(wl.Status.Conditions, condition.Type, condition.Status) {
+				log.V(3).Info(fmt.Sprintf("Updating the PodsReady condition with status: %v", condition.Status))
+				apimeta.SetStatusCondition(&wl.Status.Conditions, condition)
+				if err := r.client.Status().Update(ctx, wl); err != nil {
+					log.Error(err, "Updating workload status")
+				}
+			}
+		}
+
+		// release the quota of the pods that succeeded and won't be replaced.
+		if j, ok := job.(JobWithReclaimablePods); ok && wl.Spec.Admission != nil && !job.IsSuspended() {
+			if reclaimable := j.ReclaimablePods(); !equality.Semantic.DeepEqual(wl.Status.ReclaimablePods, reclaimable) {
+				log.V(3).Info("Updating the reclaimable pods", "reclaimablePods", reclaimable)
+				wl.Status.ReclaimablePods = reclaimable
+				if err := r.client.Status().Update(ctx, wl); err != nil {
+					log.Error(err, "Updating workload status")
+					return ctrl.Result{}, err
+				}
+			}
+		}
+	}
+
+	// 4. handle a not finished job.
+	if job.IsSuspended() {
+		if wl.Spec.Admission != nil && workload.IsReservation(wl) {
+			log.V(3).Info("Job is suspended and workload only reserves quota, nothing to do")
+			return ctrl.Result{}, nil
+		}
+		// start the job if the workload has been admitted, and the job is still suspended
+		if workload.IsAdmitted(wl) && workload.IsActive(wl) && !workload.IsEvicted(wl) {
+			log.V(2).Info("Job admitted, unsuspending")
+			err := r.startJob(ctx, job, wl)
+			if err != nil {
+				log.Error(err, "Unsuspending job")
+			}
+			return ctrl.Result{}, err
+		}
+		if wl.Spec.Admission != nil {
+			log.V(3).Info("Job is suspended and workload is waiting for the admission checks, nothing to do")
+			return ctrl.Result{}, nil
+		}
+
+		// update queue name if changed.
+		if q := QueueName(job); wl.Spec.QueueName != q {
+			log.V(2).Info("Job changed queues, updating workload")
+			wl.Spec.QueueName = q
+			err := r.client.Update(ctx, wl)
+			if err != nil {
+				log.Error(err, "Updating workload queue")
+			}
+			return ctrl.Result{}, err
+		}
+
+		// update priority if the WorkloadPriorityClass of the job changed.
+		if changed, err := r.updatePriority(ctx, job, wl); err != nil || changed {
+			if err == nil {
+				log.V(2).Info("Job changed priority, updating workload", "priority", *wl.Spec.Priority)
+				err = r.client.Update(ctx, wl)
+			}
+			if err != nil {
+				log.Error(err, "Updating workload priority")
+			}
+			return ctrl.Result{}, err
+		}
+		log.V(3).Info("Job is suspended and workload not yet admitted by a clusterQueue, nothing to do")
+		return ctrl.Result{}, nil
+	}
+
+	if wl.Spec.Admission == nil {
+		// the job must be suspended if the workload is not yet admitted.
+		log.V(2).Info("Running job is not admitted by a cluster queue, suspending")
+		err := r.stopJob(ctx, job, originalPodSetsInfo(wl), "Not admitted by cluster queue")
+		if err != nil {
+			log.Error(err, "Suspending job with non admitted workload")
+		}
+		return ctrl.Result{}, err
+	}
+	if workload.IsReservation(wl) {
+		log.V(2).Info("Running job has a workload that only reserves quota, suspending")
+		err := r.stopJob(ctx, job, originalPodSetsInfo(wl), "The workload only reserves quota")
+		if err != nil {
+			log.Error(err, "Suspending job with a reservation workload")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// workload is admitted and job is running, nothing to do.
+	log.V(3).Info("Job running with admitted workload, nothing to do")
+	return ctrl.Result{}, nil
+}
+
+// handleDeactivatedJob deletes the job, or marks it as failed, according to
+// the policy for the jobs whose workloads exceeded the requeuing limit.
+func (r *JobReconciler) handleDeactivatedJob(ctx context.Context, job GenericJob, wl *kueue.Workload) error {
+	log := ctrl.LoggerFrom(ctx)
+	object := job.Object()
+	msg := "The workload was deactivated for exceeding the requeuing limit"
+	if cond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadAdmitted); cond != nil {
+		msg = cond.Message
+	}
+	switch r.deactivatedJobPolicy {
+	case config.DeactivatedJobDelete:
+		log.V(2).Info("Deleting the job of the workload deactivated for exceeding the requeuing limit")
+		if err := r.client.Delete(ctx, object, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		r.record.Eventf(object, corev1.EventTypeWarning, kueue.WorkloadRequeuingLimitExceeded, "Deleted the job: %s", msg)
+	case config.DeactivatedJobFail:
+		j, ok := job.(JobWithFailure)
+		if !ok {
+			log.V(3).Info("The job can't be marked as failed, keeping it suspended")
+			return nil
+		}
+		log.V(2).Info("Marking as failed the job of the workload deactivated for exceeding the requeuing limit")
+		j.Fail(kueue.WorkloadRequeuingLimitExceeded, msg)
+		if err := r.client.Status().Update(ctx, object); err != nil {
+			return err
+		}
+		r.record.Eventf(object, corev1.EventTypeWarning, kueue.WorkloadRequeuingLimitExceeded, "Marked the job as failed: %s", msg)
+	}
+	return nil
+}
+
+// ensureOneWorkload returns the workload of the job, or nil if there is none,
+// deleting the workloads that don't match the job. For a job with a parent
+// workload, it returns the parent workload. If the job is running and there
+// is no matching workload, the job is suspended.
+func (r *JobReconciler) ensureOneWorkload(ctx context.Context, job GenericJob) (*kueue.Workload, error) {
+	log := ctrl.LoggerFrom(ctx)
+	object := job.Object()
+
+	// Find a matching workload first if there is one.
+	var toDelete []*kueue.Workload
+	var match *kueue.Workload
+
+	if pwName := parentWorkloadName(job); pwName != "" {
+		var pw kueue.Workload
+		key := types.NamespacedName{Name: pwName, Namespace: object.GetNamespace()}
+		if err := r.client.Get(ctx, key, &pw); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			log.V(2).Info("job with no matching parent workload", "parent-workload", pwName)
+		} else {
+			match = &pw
+		}
+	}
+
+	var workloads kueue.WorkloadList
+	if err := r.client.List(ctx, &workloads, client.InNamespace(object.GetNamespace()),
+		client.MatchingFields{OwnerReferenceIndexKey(job.GVK()): object.GetName()}); err != nil {
+		return nil, err
+	}
+	for i := range workloads.Items {
+		w := &workloads.Items[i]
+		// Indexes don't work in unit tests, so we explicitly check for the
+		// owner here. The workloads of a deleted job with the same name are
+		// garbage collected.
+		if !metav1.IsControlledBy(w, object) {
+			continue
+		}
+		if match == nil && equivalentToWorkload(job, w) {
+			match = w
+		} else {
+			toDelete = append(toDelete, w)
+		}
+	}
+
+	// If there is no matching workload and the job is running, suspend it.
+	if _, finished := job.Finished(); match == nil && !finished && !job.IsSuspended() {
+		log.V(2).Info("job with no matching workload, suspending")
+		var w *kueue.Workload
+		if len(toDelete) == 1 {
+			// The job may have been modified and hence the existing workload
+			// doesn't match the job anymore. All bets are off if there are more
+			// than one workload...
+			w = toDelete[0]
+		}
+		if err := r.stopJob(ctx, job, originalPodSetsInfo(w), "No matching Workload"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Delete duplicate workload instances.
+	existedWls := 0
+	for i := range toDelete {
+		err := r.client.Delete(ctx, toDelete[i])
+		if err == nil || !apierrors.IsNotFound(err) {
+			existedWls++
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to delete workload")
+		}
+		if err == nil {
+			r.record.Eventf(object, corev1.EventTypeNormal, "DeletedWorkload",
+				"Deleted not matching Workload: %v", workload.Key(toDelete[i]))
+		}
+	}
+
+	if existedWls != 0 {
+		// The workload is recreated once the job is read again, with the
+		// restored node selectors.
+		if match == nil {
+			return nil, fmt.Errorf("no matching workload was found, tried deleting %d existing workload(s)", existedWls)
+		}
+		return nil, fmt.Errorf("only one workload should exist, found %d", len(toDelete)+1)
+	}
+
+	return match, nil
+}
+
+// equivalentToWorkload returns whether the workload was created for the
+// current spec of the job. The pod templates of a suspended job whose workload
+// is pending are the original ones, so they must also have the hash recorded
+// in the workload, if any, to detect the mutations that the job doesn't
+// compare.
+func equivalentToWorkload(job GenericJob, wl *kueue.Workload) bool {
+	if !job.EquivalentToWorkload(wl) {
+		return false
+	}
+	hash, found := wl.Annotations[constants.PodSetsHashAnnotation]
+	if !found || !job.IsSuspended() || wl.Spec.Admission != nil {
+		return true
+	}
+	podSets, err := job.PodSets()
+	return err == nil && PodSetsHash(podSets) == hash
+}
+
+func (r *JobReconciler) handleJobWithNoWorkload(ctx context.Context, job GenericJob) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Wait until there are no active pods.
+	if job.IsActive() {
+		log.V(2).Info("Job is suspended but still has active pods, waiting")
+		return nil
+	}
+
+	// Create the corresponding workload.
+	wl, err := ConstructWorkload(ctx, r.client, job, r.scheme)
+	if err != nil {
+		return err
+	}
+	if err = r.client.Create(ctx, wl); err != nil {
+		return err
+	}
+
+	r.record.Eventf(job.Object(), corev1.EventTypeNormal, "CreatedWorkload",
+		"Created Workload: %v", workload.Key(wl))
+	return nil
+}
+
+// ConstructWorkload returns the Workload for the job, owned by it.
+func ConstructWorkload(ctx context.Context, c client.Client, job GenericJob, scheme *runtime.Scheme) (*kueue.Workload, error) {
+	podSets, err := job.PodSets()
+	if err != nil {
+		return nil, err
+	}
+	object := job.Object()
+	wl := &kueue.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        workloadName(job),
+			Namespace:   object.GetNamespace(),
+			Annotations: workloadGroupAnnotations(object),
+		},
+		Spec: kueue.WorkloadSpec{
+			PodSets:   podSets,
+			QueueName: QueueName(job),
+		},
+	}
+
+	if wl.Annotations == nil {
+		wl.Annotations = make(map[string]string, 1)
+	}
+	wl.Annotations[constants.PodSetsHashAnnotation] = PodSetsHash(podSets)
+
+	// Populate priority from the WorkloadPriorityClass of the job or,
+	// otherwise, from the priority class of the first pod set that sets one.
+	priorityClassName, source, p, err := utilpriority.GetPriority(ctx, c,
+		object.GetLabels()[constants.WorkloadPriorityClassLabel], podSetsPriorityClassName(podSets))
+	if err != nil {
+		return nil, err
+	}
+	wl.Spec.Priority = &p
+	wl.Spec.PriorityClassName = priorityClassName
+	wl.Spec.PriorityClassSource = source
+
+	if err := ctrl.SetControllerReference(object, wl, scheme); err != nil {
+		return nil, err
+	}
+	return wl, nil
+}
+
+// podSetsPriorityClassName returns the priority class of the first pod set
+// that sets one.
+func podSetsPriorityClassName(podSets []kueue.PodSet) string {
+	for i := range podSets {
+		if name := podSets[i].Spec.PriorityClassName; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// updatePriority sets the priority of the pending workload from the
+// WorkloadPriorityClass in the label of the job, if it was added, removed or
+// changed since the workload was created, without updating the workload. It
+// returns whether the workload changed.
+func (r *JobReconciler) updatePriority(ctx context.Context, job GenericJob, wl *kueue.Workload) (bool, error) {
+	wpc := job.Object().GetLabels()[constants.WorkloadPriorityClassLabel]
+	fromWPC := wl.Spec.PriorityClassSource == kueue.WorkloadPriorityClassSource
+	if wpc == "" && !fromWPC || wpc != "" && fromWPC && wl.Spec.PriorityClassName == wpc {
+		return false, nil
+	}
+	name, source, p, err := utilpriority.GetPriority(ctx, r.client, wpc, podSetsPriorityClassName(wl.Spec.PodSets))
+	if err != nil {
+		return false, err
+	}
+	wl.Spec.PriorityClassName = name
+	wl.Spec.PriorityClassSource = source
+	wl.Spec.Priority = &p
+	return true, nil
+}
+
+// workloadGroupAnnotations returns the annotations of the job that place its
+// workload in a group of workloads, or nil if there are none.
+func workloadGroupAnnotations(object client.Object) map[string]string {
+	var annotations map[string]string
+	for _, k := range []string{constants.WorkloadGroupAnnotation, constants.WorkloadGroupSizeAnnotation} {
+		if v, found := object.GetAnnotations()[k]; found {
+			if annotations == nil {
+				annotations = make(map[string]string, 2)
+			}
+			annotations[k] = v
+		}
+	}
+	return annotations
+}
+
+// startJob unsuspends the job, injecting the node selectors of the flavors
+// assigned to each pod set and the changes required by the admission checks.
+func (r *JobReconciler) startJob(ctx context.Context, job GenericJob, wl *kueue.Workload) error {
+	infos, err := r.podSetsInfo(ctx, wl)
+	if err != nil {
+		return err
+	}
+	if err := job.RunWithPodSetsInfo(infos); err != nil {
+		return err
+	}
+	if err := r.client.Update(ctx, job.Object()); err != nil {
+		return err
+	}
+	r.record.Eventf(job.Object(), corev1.EventTypeNormal, "Started",
+		"Admitted by clusterQueue %v", wl.Spec.Admission.ClusterQueue)
+	return nil
+}
+
+// handleEvictedWorkload suspends the job of an evicted workload and then
+// clears the admission of the workload, which releases its quota.
+func (r *JobReconciler) handleEvictedWorkload(ctx context.Context, job GenericJob, wl *kueue.Workload) error {
+	log := ctrl.LoggerFrom(ctx)
+	if !job.IsSuspended() {
+		evicted := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadEvicted)
+		log.V(2).Info("Workload evicted, suspending the job", "reason", evicted.Reason)
+		if err := r.stopJob(ctx, job, originalPodSetsInfo(wl), evicted.Message); err != nil {
+			return err
+		}
+	}
+	log.V(2).Info("Clearing the admission of the evicted workload")
+	err := workload.ClearAdmission(ctx, r.client, wl)
+	return client.IgnoreNotFound(err)
+}
+
+// originalPodSetsInfo returns the infos to restore the pod templates of the
+// job to the ones in the workload, which are the original ones, or nil if
+// there is no workload.
+func originalPodSetsInfo(wl *kueue.Workload) []PodSetInfo {
+	if wl == nil {
+		return nil
+	}
+	infos := make([]PodSetInfo, len(wl.Spec.PodSets))
+	for i, ps := range wl.Spec.PodSets {
+		infos[i] = PodSetInfo{Name: ps.Name, NodeSelector: ps.Spec.NodeSelector, Tolerations: ps.Spec.Tolerations}
+		// Restore the original count, in case the pod set was partially
+		// admitted.
+		if ps.MinCount != nil {
+			infos[i].Count = pointer.Int32(ps.Count)
+		}
+	}
+	return infos
+}
+
+// stopJob suspends the job and restores its pod templates with the infos, if
+// any.
+func (r *JobReconciler) stopJob(ctx context.Context, job GenericJob, infos []PodSetInfo, eventMsg string) error {
+	if err := job.Suspend(); err != nil {
+		return err
+	}
+	if infos != nil {
+		if _, err := job.RestorePodSetsInfo(infos); err != nil {
+			return err
+		}
+	}
+	if err := r.client.Update(ctx, job.Object()); err != nil {
+		return err
+	}
+	r.record.Eventf(job.Object(), corev1.EventTypeNormal, "Stopped", eventMsg)
+
+	if j, ok := job.(JobWithStatusReset); ok && j.ResetStatus() {
+		return r.client.Status().Update(ctx, job.Object())
+	}
+	return nil
+}
+
+// podSetsInfo returns, for each pod set of the workload, the node labels and
+// tolerations of the assigned flavors, the node labels of the topology domain,
+// and the changes required by the admission checks.
+func (r *JobReconciler) podSetsInfo(ctx context.Context, wl *kueue.Workload) ([]PodSetInfo, error) {
+	flavors := make(map[string]*kueue.ResourceFlavor)
+	infos := make([]PodSetInfo, len(wl.Spec.PodSets))
+	for i, ps := range wl.Spec.PodSets {
+		info := PodSetInfo{Name: ps.Name, NodeSelector: make(map[string]string)}
+		if psFlavors := workload.FindPodSetFlavors(wl.Spec.Admission, ps.Name); psFlavors != nil {
+			for _, flvName := range psFlavors.Flavors {
+				flv, found := flavors[flvName]
+				if !found {
+					// Lookup the ResourceFlavors to fetch the node affinity labels and
+					// tolerations to apply on the job.
+					flv = &kueue.ResourceFlavor{}
+					if err := r.client.Get(ctx, types.NamespacedName{Name: flvName}, flv); err != nil {
+						return nil, err
+					}
+					flavors[flvName] = flv
+				}
+				info.NodeSelector = MergeMaps(info.NodeSelector, flv.NodeSelector)
+				info.Tolerations = MergeTolerations(info.Tolerations, flv.Tolerations)
+			}
+			info.NodeSelector = MergeMaps(info.NodeSelector, psFlavors.TopologyDomain)
+			info.Count = psFlavors.Count
+		}
+		for _, check := range wl.Status.AdmissionChecks {
+			for _, update := range check.PodSetUpdates {
+				if update.Name != ps.Name {
+					continue
+				}
+				info.Labels = MergeMaps(info.Labels, update.Labels)
+				info.Annotations = MergeMaps(info.Annotations, update.Annotations)
+				info.NodeSelector = MergeMaps(info.NodeSelector, update.NodeSelector)
+			}
+		}
+		infos[i] = info
+	}
+	return infos, nil
+}
+
+func podsReadyCondition(job GenericJob, wl *kueue.Workload) metav1.Condition {
+	conditionStatus := metav1.ConditionFalse
+	message := "Not all pods are ready or succeeded"
+	// Once PodsReady=True it stays as long as the workload remains admitted to
+	// avoid unnecessary flickering of the condition.
+	if wl.Spec.Admission != nil && (job.PodsReady() || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadPodsReady)) {
+		conditionStatus = metav1.ConditionTrue
+		message = "All pods were ready or succeeded since the workload admission"
+	}
+	return metav1.Condition{
+		Type:    kueue.WorkloadPodsReady,
+		Status:  conditionStatus,
+		Reason:  "PodsReady",
+		Message: message,
+	}
+}
+
+func parentWorkloadName(job GenericJob) string {
+	if j, ok := job.(JobWithParentWorkload); ok {
+		return j.ParentWorkload()
+	}
+	return ""
+}
+
+// QueueName returns the queue name of the job.
+func QueueName(job GenericJob) string {
+	if j, ok := job.(JobWithCustomQueueName); ok {
+		return j.QueueName()
+	}
+	return job.Object().GetAnnotations()[constants.QueueAnnotation]
+}
+
+// NamespaceManaged returns whether the jobs without queue name in the
+// namespace are managed, when manageJobsWithoutQueueName is enabled, which is
+// the case if the labels of the namespace match the selector.
+func NamespaceManaged(ctx context.Context, c client.Client, selector labels.Selector, namespace string) (bool, error) {
+	if selector == nil || selector.Empty() {
+		return true, nil
+	}
+	var ns corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return false, fmt.Errorf("getting the namespace: %w", err)
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// InAdmissionScope returns whether the job is subject to queueing in an
+// admission scope limited to the resources whose names start with one of the
+// given prefixes, which is the case if any container of its pod sets requests
+// any of them. All the jobs are in the scope of an empty list.
+func InAdmissionScope(job GenericJob, resourcePrefixes []string) (bool, error) {
+	if len(resourcePrefixes) == 0 {
+		return true, nil
+	}
+	podSets, err := job.PodSets()
+	if err != nil {
+		return false, err
+	}
+	for i := range podSets {
+		spec := &podSets[i].Spec
+		for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+			for j := range containers {
+				if requestsAnyPrefix(&containers[j].Resources, resourcePrefixes) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// requestsAnyPrefix returns whether the requests or limits, which default the
+// requests, include a resource whose name starts with one of the prefixes.
+func requestsAnyPrefix(resources *corev1.ResourceRequirements, prefixes []string) bool {
+	for _, list := range []corev1.ResourceList{resources.Requests, resources.Limits} {
+		for name, q := range list {
+			if q.IsZero() {
+				continue
+			}
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(string(name), prefix) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MergeMaps copies the entries of src into dst, which is created if nil and
+// src isn't empty, and returns dst.
+func MergeMaps(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// IsAPIAvailable returns whether the API server serves the kind of the jobs,
+// which isn't the case when the CRD of the integration is not installed.
+func IsAPIAvailable(mgr ctrl.Manager, gvk schema.GroupVersionKind) (bool, error) {
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
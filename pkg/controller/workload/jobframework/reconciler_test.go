@@ -0,0 +1,720 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+var testJobGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "TestJob"}
+
+// testJob is a job with a single pod template, at spec.template, whose count
+// is spec.count.
+type testJob struct {
+	u unstructured.Unstructured
+}
+
+func newTestJob() GenericJob {
+	j := &testJob{}
+	j.u.SetGroupVersionKind(testJobGVK)
+	return j
+}
+
+func (j *testJob) Object() client.Object        { return &j.u }
+func (j *testJob) GVK() schema.GroupVersionKind { return testJobGVK }
+
+func (j *testJob) IsSuspended() bool {
+	suspend, _, _ := unstructured.NestedBool(j.u.Object, "spec", "suspend")
+	return suspend
+}
+
+func (j *testJob) Suspend() error {
+	return unstructured.SetNestedField(j.u.Object, true, "spec", "suspend")
+}
+
+func (j *testJob) RunWithPodSetsInfo(infos []PodSetInfo) error {
+	if err := ApplyPodSetInfo(j.template(), &infos[0]); err != nil {
+		return err
+	}
+	if infos[0].Count != nil {
+		if err := unstructured.SetNestedField(j.u.Object, int64(*infos[0].Count), "spec", "count"); err != nil {
+			return err
+		}
+	}
+	return unstructured.SetNestedField(j.u.Object, false, "spec", "suspend")
+}
+
+func (j *testJob) RestorePodSetsInfo(infos []PodSetInfo) (bool, error) {
+	if infos[0].Count != nil {
+		if err := unstructured.SetNestedField(j.u.Object, int64(*infos[0].Count), "spec", "count"); err != nil {
+			return false, err
+		}
+	}
+	return RestoreNodeSelector(j.template(), infos[0].NodeSelector)
+}
+
+func (j *testJob) Finished() (metav1.Condition, bool) {
+	finished, _, _ := unstructured.NestedBool(j.u.Object, "status", "finished")
+	return metav1.Condition{
+		Type:    kueue.WorkloadFinished,
+		Status:  metav1.ConditionTrue,
+		Reason:  "JobFinished",
+		Message: "Job finished successfully",
+	}, finished
+}
+
+func (j *testJob) PodSets() ([]kueue.PodSet, error) {
+	count, _, _ := unstructured.NestedInt64(j.u.Object, "spec", "count")
+	ps, err := PodSetFromTemplate(j.template(), kueue.DefaultPodSetName, int32(count))
+	if err != nil {
+		return nil, err
+	}
+	return []kueue.PodSet{ps}, nil
+}
+
+func (j *testJob) IsActive() bool {
+	active, _, _ := unstructured.NestedBool(j.u.Object, "status", "active")
+	return active
+}
+
+func (j *testJob) PodsReady() bool {
+	return false
+}
+
+func (j *testJob) EquivalentToWorkload(wl *kueue.Workload) bool {
+	podSets, err := j.PodSets()
+	return err == nil && PodSetsEquivalent(wl.Spec.PodSets, podSets)
+}
+
+func (j *testJob) Fail(reason, message string) {
+	_ = unstructured.SetNestedField(j.u.Object, reason, "status", "failureReason")
+}
+
+func (j *testJob) SliceSize() int32 {
+	size, _, _ := unstructured.NestedInt64(j.u.Object, "spec", "sliceSize")
+	return int32(size)
+}
+
+func (j *testJob) template() map[string]interface{} {
+	return j.u.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})
+}
+
+func makeTestJob(suspend bool, count int64, nodeSelector map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "job",
+			"namespace":   "ns",
+			"uid":         "job-uid",
+			"annotations": map[string]interface{}{"kueue.x-k8s.io/queue-name": "queue"},
+		},
+		"spec": map[string]interface{}{
+			"suspend": suspend,
+			"count":   count,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "c", "image": "img"}},
+				},
+			},
+		},
+	}}
+	if nodeSelector != nil {
+		u.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["nodeSelector"] = nodeSelector
+	}
+	u.SetGroupVersionKind(testJobGVK)
+	return u
+}
+
+func TestReconcile(t *testing.T) {
+	podSets := []kueue.PodSet{{
+		Name:  kueue.DefaultPodSetName,
+		Count: 2,
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "c", Image: "img"}},
+		},
+	}}
+	finishedJob := makeTestJob(false, 2, nil)
+	finishedJob.Object["status"] = map[string]interface{}{"finished": true}
+	highPriorityJob := makeTestJob(true, 2, nil)
+	highPriorityJob.SetLabels(map[string]string{"kueue.x-k8s.io/priority-class": "high"})
+
+	testcases := map[string]struct {
+		job          *unstructured.Unstructured
+		workload     *kueue.Workload
+		wantJob      *unstructured.Unstructured
+		wantWorkload *kueue.Workload
+		wantErr      bool
+	}{
+		"create workload for suspended job": {
+			job:     makeTestJob(true, 2, nil),
+			wantJob: makeTestJob(true, 2, nil),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				Annotation(constants.PodSetsHashAnnotation, PodSetsHash(podSets)).
+				PodSets(podSets).
+				Queue("queue").
+				Priority(0).
+				Obj(),
+		},
+		"suspend running job with no workload": {
+			job:     makeTestJob(false, 2, nil),
+			wantJob: makeTestJob(true, 2, nil),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				Annotation(constants.PodSetsHashAnnotation, PodSetsHash(podSets)).
+				PodSets(podSets).
+				Queue("queue").
+				Priority(0).
+				Obj(),
+		},
+		"create workload with WorkloadPriorityClass": {
+			job:     highPriorityJob,
+			wantJob: makeTestJob(true, 2, nil),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				Annotation(constants.PodSetsHashAnnotation, PodSetsHash(podSets)).
+				PodSets(podSets).
+				Queue("queue").
+				PriorityClass("high").
+				PriorityClassSource(kueue.WorkloadPriorityClassSource).
+				Priority(100).
+				Obj(),
+		},
+		"update priority of pending workload when the WorkloadPriorityClass changes": {
+			job: highPriorityJob,
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				PriorityClass("low").
+				PriorityClassSource(kueue.WorkloadPriorityClassSource).
+				Priority(10).
+				Obj(),
+			wantJob: makeTestJob(true, 2, nil),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				PriorityClass("high").
+				PriorityClassSource(kueue.WorkloadPriorityClassSource).
+				Priority(100).
+				Obj(),
+		},
+		"reset priority of pending workload when the WorkloadPriorityClass is removed": {
+			job: makeTestJob(true, 2, nil),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				PriorityClass("low").
+				PriorityClassSource(kueue.WorkloadPriorityClassSource).
+				Priority(10).
+				Obj(),
+			wantJob: makeTestJob(true, 2, nil),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Priority(0).
+				Obj(),
+		},
+		"start admitted job": {
+			job: makeTestJob(true, 2, nil),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+				Obj(),
+			wantJob: makeTestJob(false, 2, map[string]interface{}{"instance": "on-demand"}),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+				Obj(),
+		},
+		"keep job with an admitted reservation workload suspended": {
+			job: makeTestJob(true, 2, nil),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Reservation(nil).
+				Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+				Obj(),
+			wantJob: makeTestJob(true, 2, nil),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Reservation(nil).
+				Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+				Obj(),
+		},
+		"stop job whose admission was cancelled": {
+			job: makeTestJob(false, 2, map[string]interface{}{"instance": "on-demand"}),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Obj(),
+			wantJob: makeTestJob(true, 2, nil),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Obj(),
+		},
+		"finished job": {
+			job: finishedJob,
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Admit(utiltesting.MakeAdmission("cq").Obj()).
+				Obj(),
+			wantJob: finishedJob,
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Admit(utiltesting.MakeAdmission("cq").Obj()).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadFinished,
+					Status:  metav1.ConditionTrue,
+					Reason:  "JobFinished",
+					Message: "Job finished successfully",
+				}).
+				Obj(),
+		},
+		"recreate pending workload when the pod template of the suspended job changed": {
+			job: makeTestJob(true, 2, map[string]interface{}{"instance": "spot"}),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				Annotation(constants.PodSetsHashAnnotation, PodSetsHash(podSets)).
+				PodSets(podSets).
+				Queue("queue").
+				Obj(),
+			wantJob: makeTestJob(true, 2, map[string]interface{}{"instance": "spot"}),
+			wantErr: true,
+		},
+		"keep pending workload without the hash of the pod sets": {
+			job: makeTestJob(true, 2, map[string]interface{}{"instance": "spot"}),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Obj(),
+			wantJob: makeTestJob(true, 2, map[string]interface{}{"instance": "spot"}),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Obj(),
+		},
+		"keep admitted workload of the running job": {
+			job: makeTestJob(false, 2, map[string]interface{}{"instance": "on-demand"}),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				Annotation(constants.PodSetsHashAnnotation, PodSetsHash(podSets)).
+				PodSets(podSets).
+				Queue("queue").
+				Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+				Obj(),
+			wantJob: makeTestJob(false, 2, map[string]interface{}{"instance": "on-demand"}),
+			wantWorkload: utiltesting.MakeWorkload("testjob-job", "ns").
+				Annotation(constants.PodSetsHashAnnotation, PodSetsHash(podSets)).
+				PodSets(podSets).
+				Queue("queue").
+				Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+				Obj(),
+		},
+		"delete workload that doesn't match the job": {
+			job: makeTestJob(false, 3, map[string]interface{}{"instance": "on-demand"}),
+			workload: utiltesting.MakeWorkload("testjob-job", "ns").
+				PodSets(podSets).
+				Queue("queue").
+				Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()).
+				Obj(),
+			wantJob: makeTestJob(true, 3, nil),
+			wantErr: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding client-go scheme: %v", err)
+			}
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				tc.job.DeepCopy(),
+				utiltesting.MakeResourceFlavor("on-demand").Label("instance", "on-demand").Obj(),
+				&kueue.WorkloadPriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}, Value: 100},
+			)
+			if tc.workload != nil {
+				wl := tc.workload.DeepCopy()
+				wl.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: testJobGVK.GroupVersion().String(),
+					Kind:       testJobGVK.Kind,
+					Name:       "job",
+					UID:        "job-uid",
+					Controller: pointer.Bool(true),
+				}}
+				builder = builder.WithObjects(wl)
+			}
+			cl := builder.Build()
+			r := NewReconciler(scheme, cl, record.NewFakeRecorder(10), newTestJob)
+			ctx := ctrl.LoggerInto(context.Background(), ctrl.Log)
+			key := types.NamespacedName{Name: "job", Namespace: "ns"}
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); (err != nil) != tc.wantErr {
+				t.Fatalf("Reconcile() returned error: %v, want error: %t", err, tc.wantErr)
+			}
+
+			gotJob := newTestJob().Object()
+			if err := cl.Get(ctx, key, gotJob); err != nil {
+				t.Fatalf("Couldn't get the job: %v", err)
+			}
+			gotSpec := gotJob.(*unstructured.Unstructured).Object["spec"]
+			if diff := cmp.Diff(tc.wantJob.Object["spec"], gotSpec); diff != "" {
+				t.Errorf("Unexpected job spec (-want,+got):\n%s", diff)
+			}
+
+			var workloads kueue.WorkloadList
+			if err := cl.List(ctx, &workloads); err != nil {
+				t.Fatalf("Couldn't list the workloads: %v", err)
+			}
+			var wantWorkloads []kueue.Workload
+			if tc.wantWorkload != nil {
+				wantWorkloads = append(wantWorkloads, *tc.wantWorkload)
+			}
+			if diff := cmp.Diff(wantWorkloads, workloads.Items, cmpopts.EquateEmpty(),
+				cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion", "OwnerReferences"),
+				cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"),
+				cmpopts.IgnoreTypes(metav1.TypeMeta{})); diff != "" {
+				t.Errorf("Unexpected workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileDeactivatedJob(t *testing.T) {
+	podSets := []kueue.PodSet{{
+		Name:  kueue.DefaultPodSetName,
+		Count: 2,
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "c", Image: "img"}},
+		},
+	}}
+	deactivated := utiltesting.MakeWorkload("testjob-job", "ns").
+		PodSets(podSets).
+		Queue("queue").
+		Active(false).
+		Condition(metav1.Condition{
+			Type:    kueue.WorkloadAdmitted,
+			Status:  metav1.ConditionFalse,
+			Reason:  kueue.WorkloadRequeuingLimitExceeded,
+			Message: "Deactivated after being evicted 3 times",
+		}).
+		Obj()
+	deactivated.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: testJobGVK.GroupVersion().String(),
+		Kind:       testJobGVK.Kind,
+		Name:       "job",
+		UID:        "job-uid",
+		Controller: pointer.Bool(true),
+	}}
+
+	testcases := map[string]struct {
+		policy            config.DeactivatedJobPolicy
+		workload          *kueue.Workload
+		wantDeleted       bool
+		wantFailureReason string
+	}{
+		"keep the job": {
+			policy:   config.DeactivatedJobKeep,
+			workload: deactivated,
+		},
+		"delete the job": {
+			policy:      config.DeactivatedJobDelete,
+			workload:    deactivated,
+			wantDeleted: true,
+		},
+		"fail the job": {
+			policy:            config.DeactivatedJobFail,
+			workload:          deactivated,
+			wantFailureReason: kueue.WorkloadRequeuingLimitExceeded,
+		},
+		"keep the job of a workload deactivated by the user": {
+			policy: config.DeactivatedJobDelete,
+			workload: func() *kueue.Workload {
+				wl := deactivated.DeepCopy()
+				wl.Status.Conditions[0].Reason = "Inactive"
+				return wl
+			}(),
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding client-go scheme: %v", err)
+			}
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(makeTestJob(true, 2, nil), tc.workload.DeepCopy()).
+				Build()
+			r := NewReconciler(scheme, cl, record.NewFakeRecorder(10), newTestJob, WithDeactivatedJobPolicy(tc.policy))
+			ctx := ctrl.LoggerInto(context.Background(), ctrl.Log)
+			key := types.NamespacedName{Name: "job", Namespace: "ns"}
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() returned error: %v", err)
+			}
+
+			gotJob := newTestJob().Object()
+			err := cl.Get(ctx, key, gotJob)
+			if gotDeleted := apierrors.IsNotFound(err); gotDeleted != tc.wantDeleted {
+				t.Fatalf("Job deleted: %t, want %t (error: %v)", gotDeleted, tc.wantDeleted, err)
+			}
+			if tc.wantDeleted {
+				return
+			}
+			gotReason, _, _ := unstructured.NestedString(gotJob.(*unstructured.Unstructured).Object, "status", "failureReason")
+			if gotReason != tc.wantFailureReason {
+				t.Errorf("Unexpected failure reason of the job: %q, want %q", gotReason, tc.wantFailureReason)
+			}
+			if suspended := (&testJob{u: *gotJob.(*unstructured.Unstructured)}).IsSuspended(); !suspended {
+				t.Error("The job was unsuspended")
+			}
+		})
+	}
+}
+
+func TestReconcileSlices(t *testing.T) {
+	podSet := func(count int32) []kueue.PodSet {
+		return []kueue.PodSet{{
+			Name:  kueue.DefaultPodSetName,
+			Count: count,
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "c", Image: "img"}},
+			},
+		}}
+	}
+	hash := PodSetsHash(podSet(5))
+	slicedJob := func(suspend bool, count int64, nodeSelector map[string]interface{}) *unstructured.Unstructured {
+		u := makeTestJob(suspend, count, nodeSelector)
+		u.Object["spec"].(map[string]interface{})["sliceSize"] = int64(2)
+		return u
+	}
+	slice := func(i int, count int32) *utiltesting.WorkloadWrapper {
+		return utiltesting.MakeWorkload(fmt.Sprintf("testjob-job-slice-%d", i), "ns").
+			Annotation(constants.PodSetsHashAnnotation, hash).
+			Annotation(constants.WorkloadSliceAnnotation, strconv.Itoa(i)).
+			PodSets(podSet(count)).
+			Queue("queue")
+	}
+	onDemand := utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "on-demand").Obj()
+
+	testcases := map[string]struct {
+		job           *unstructured.Unstructured
+		workloads     []*kueue.Workload
+		wantJob       *unstructured.Unstructured
+		wantWorkloads []*kueue.Workload
+	}{
+		"create workload slices for suspended job": {
+			job:     slicedJob(true, 5, nil),
+			wantJob: slicedJob(true, 5, nil),
+			wantWorkloads: []*kueue.Workload{
+				slice(0, 2).Priority(0).Obj(),
+				slice(1, 2).Priority(0).Obj(),
+				slice(2, 1).Priority(0).Obj(),
+			},
+		},
+		"run job with the pods of the admitted slices": {
+			job: slicedJob(true, 5, nil),
+			workloads: []*kueue.Workload{
+				slice(0, 2).Admit(onDemand).Obj(),
+				slice(1, 2).Admit(onDemand).Obj(),
+				slice(2, 1).Obj(),
+			},
+			wantJob: slicedJob(false, 4, map[string]interface{}{"instance": "on-demand"}),
+			wantWorkloads: []*kueue.Workload{
+				slice(0, 2).Admit(onDemand).Obj(),
+				slice(1, 2).Admit(onDemand).Obj(),
+				slice(2, 1).Obj(),
+			},
+		},
+		"add the pods of a slice admitted later": {
+			job: slicedJob(false, 2, map[string]interface{}{"instance": "on-demand"}),
+			workloads: []*kueue.Workload{
+				slice(0, 2).Admit(onDemand).Obj(),
+				slice(1, 2).Obj(),
+				slice(2, 1).Admit(onDemand).Obj(),
+			},
+			wantJob: slicedJob(false, 3, map[string]interface{}{"instance": "on-demand"}),
+			wantWorkloads: []*kueue.Workload{
+				slice(0, 2).Admit(onDemand).Obj(),
+				slice(1, 2).Obj(),
+				slice(2, 1).Admit(onDemand).Obj(),
+			},
+		},
+		"suspend job when no slice is admitted": {
+			job: slicedJob(false, 2, map[string]interface{}{"instance": "on-demand"}),
+			workloads: []*kueue.Workload{
+				slice(0, 2).Obj(),
+				slice(1, 2).Obj(),
+				slice(2, 1).Obj(),
+			},
+			wantJob: slicedJob(true, 5, nil),
+			wantWorkloads: []*kueue.Workload{
+				slice(0, 2).Obj(),
+				slice(1, 2).Obj(),
+				slice(2, 1).Obj(),
+			},
+		},
+		"delete the slices that don't match the suspended job": {
+			job: slicedJob(true, 6, nil),
+			workloads: []*kueue.Workload{
+				slice(0, 2).Obj(),
+				slice(1, 2).Obj(),
+				slice(2, 1).Obj(),
+			},
+			wantJob: slicedJob(true, 6, nil),
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding client-go scheme: %v", err)
+			}
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				tc.job.DeepCopy(),
+				utiltesting.MakeResourceFlavor("on-demand").Label("instance", "on-demand").Obj(),
+			)
+			for _, wl := range tc.workloads {
+				wl = wl.DeepCopy()
+				wl.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: testJobGVK.GroupVersion().String(),
+					Kind:       testJobGVK.Kind,
+					Name:       "job",
+					UID:        "job-uid",
+					Controller: pointer.Bool(true),
+				}}
+				builder = builder.WithObjects(wl)
+			}
+			cl := builder.Build()
+			r := NewReconciler(scheme, cl, record.NewFakeRecorder(10), newTestJob)
+			ctx := ctrl.LoggerInto(context.Background(), ctrl.Log)
+			key := types.NamespacedName{Name: "job", Namespace: "ns"}
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() returned error: %v", err)
+			}
+
+			gotJob := newTestJob().Object()
+			if err := cl.Get(ctx, key, gotJob); err != nil {
+				t.Fatalf("Couldn't get the job: %v", err)
+			}
+			gotSpec := gotJob.(*unstructured.Unstructured).Object["spec"]
+			if diff := cmp.Diff(tc.wantJob.Object["spec"], gotSpec); diff != "" {
+				t.Errorf("Unexpected job spec (-want,+got):\n%s", diff)
+			}
+
+			var workloads kueue.WorkloadList
+			if err := cl.List(ctx, &workloads); err != nil {
+				t.Fatalf("Couldn't list the workloads: %v", err)
+			}
+			var wantWorkloads []kueue.Workload
+			for _, wl := range tc.wantWorkloads {
+				wantWorkloads = append(wantWorkloads, *wl)
+			}
+			if diff := cmp.Diff(wantWorkloads, workloads.Items, cmpopts.EquateEmpty(),
+				cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion", "OwnerReferences"),
+				cmpopts.IgnoreTypes(metav1.TypeMeta{})); diff != "" {
+				t.Errorf("Unexpected workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInAdmissionScope(t *testing.T) {
+	cases := map[string]struct {
+		resources      map[string]interface{}
+		initResources  map[string]interface{}
+		resourcePrefix []string
+		want           bool
+	}{
+		"empty scope": {
+			resources: map[string]interface{}{"requests": map[string]interface{}{"cpu": "1"}},
+			want:      true,
+		},
+		"requests a GPU": {
+			resources:      map[string]interface{}{"requests": map[string]interface{}{"cpu": "1", "nvidia.com/gpu": "1"}},
+			resourcePrefix: []string{"amd.com/", "nvidia.com/"},
+			want:           true,
+		},
+		"limits a GPU": {
+			resources:      map[string]interface{}{"limits": map[string]interface{}{"nvidia.com/gpu": "2"}},
+			resourcePrefix: []string{"nvidia.com/"},
+			want:           true,
+		},
+		"init container requests a GPU": {
+			initResources:  map[string]interface{}{"requests": map[string]interface{}{"nvidia.com/gpu": "1"}},
+			resourcePrefix: []string{"nvidia.com/"},
+			want:           true,
+		},
+		"requests only CPU": {
+			resources:      map[string]interface{}{"requests": map[string]interface{}{"cpu": "1"}},
+			resourcePrefix: []string{"nvidia.com/"},
+		},
+		"requests zero GPUs": {
+			resources:      map[string]interface{}{"requests": map[string]interface{}{"nvidia.com/gpu": "0"}},
+			resourcePrefix: []string{"nvidia.com/"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := makeTestJob(false, 1, nil)
+			spec := u.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+			if tc.resources != nil {
+				spec["containers"].([]interface{})[0].(map[string]interface{})["resources"] = tc.resources
+			}
+			if tc.initResources != nil {
+				spec["initContainers"] = []interface{}{map[string]interface{}{"name": "init", "image": "img", "resources": tc.initResources}}
+			}
+			got, err := InAdmissionScope(&testJob{u: *u}, tc.resourcePrefix)
+			if err != nil {
+				t.Fatalf("InAdmissionScope failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("InAdmissionScope() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
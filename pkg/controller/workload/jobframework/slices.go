@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// reconcileSlices reconciles a job that is split into Workload slices. The job
+// runs with the pods of the admitted slices, and it's suspended while none of
+// them is admitted.
+func (r *JobReconciler) reconcileSlices(ctx context.Context, job GenericJob, size int32) error {
+	log := ctrl.LoggerFrom(ctx)
+	object := job.Object()
+	slices, err := r.workloadSlices(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	if finishedCond, finished := job.Finished(); finished {
+		for i := range slices {
+			wl := &slices[i]
+			if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+				continue
+			}
+			apimeta.SetStatusCondition(&wl.Status.Conditions, finishedCond)
+			if err := r.client.Status().Update(ctx, wl); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(slices) > 0 && job.IsSuspended() && !anySliceAdmitted(slices) && !slicesMatch(job, slices) {
+		log.V(2).Info("The workload slices don't match the job, deleting them")
+		for i := range slices {
+			if err := r.client.Delete(ctx, &slices[i]); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+		r.record.Eventf(object, corev1.EventTypeNormal, "DeletedWorkload",
+			"Deleted %d not matching Workload slices", len(slices))
+		return nil
+	}
+
+	if len(slices) == 0 {
+		if !job.IsSuspended() {
+			log.V(2).Info("job with no workload slices, suspending")
+			return r.stopJob(ctx, job, nil, "No matching Workload")
+		}
+		if job.IsActive() {
+			log.V(2).Info("Job is suspended but still has active pods, waiting")
+			return nil
+		}
+		wls, err := ConstructWorkloadSlices(ctx, r.client, job, r.scheme, size)
+		if err != nil {
+			return err
+		}
+		for i := range wls {
+			if err := r.client.Create(ctx, wls[i]); err != nil {
+				return err
+			}
+		}
+		r.record.Eventf(object, corev1.EventTypeNormal, "CreatedWorkload",
+			"Created %d Workload slices of up to %d pods", len(wls), size)
+		return nil
+	}
+
+	// The job runs with the pods of the admitted slices. The evicted slices
+	// release their quota once the job runs without their pods.
+	var running []*kueue.Workload
+	var evicted []*kueue.Workload
+	count := int32(0)
+	total := int32(0)
+	for i := range slices {
+		wl := &slices[i]
+		ps := &wl.Spec.PodSets[0]
+		total += ps.Count
+		switch {
+		case wl.Spec.Admission != nil && workload.IsEvicted(wl):
+			evicted = append(evicted, wl)
+		case workload.IsAdmitted(wl) && workload.IsActive(wl):
+			running = append(running, wl)
+			count += workload.AdmittedCount(ps, workload.FindPodSetFlavors(wl.Spec.Admission, ps.Name))
+		}
+	}
+
+	if len(running) == 0 {
+		if !job.IsSuspended() {
+			log.V(2).Info("No workload slice is admitted, suspending the job")
+			infos := originalPodSetsInfo(&slices[0])
+			infos[0].Count = pointer.Int32(total)
+			if err := r.stopJob(ctx, job, infos, "No Workload slice is admitted"); err != nil {
+				return err
+			}
+		}
+	} else if podSets, err := job.PodSets(); err != nil {
+		return err
+	} else if job.IsSuspended() || podSets[0].Count != count {
+		log.V(2).Info("Running the job with the pods of the admitted workload slices", "count", count, "slices", len(running))
+		infos, err := r.podSetsInfo(ctx, running[0])
+		if err != nil {
+			return err
+		}
+		infos[0].Count = pointer.Int32(count)
+		if err := job.RunWithPodSetsInfo(infos); err != nil {
+			return err
+		}
+		if err := r.client.Update(ctx, object); err != nil {
+			return err
+		}
+		r.record.Eventf(object, corev1.EventTypeNormal, "Started",
+			"Running with %d pods of %d admitted Workload slices", count, len(running))
+	}
+
+	for _, wl := range evicted {
+		log.V(2).Info("Clearing the admission of the evicted workload slice", "workload", klog.KObj(wl))
+		err := workload.ClearAdmission(ctx, r.client, wl)
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	if r.waitForPodsReady {
+		for _, wl := range running {
+			condition := podsReadyCondition(job, wl)
+			if !apimeta.IsStatusConditionPresentAndEqual(wl.Status.Conditions, condition.Type, condition.Status) {
+				apimeta.SetStatusCondition(&wl.Status.Conditions, condition)
+				if err := r.client.Status().Update(ctx, wl); client.IgnoreNotFound(err) != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// workloadSlices returns the workloads owned by the job, sorted by name.
+func (r *JobReconciler) workloadSlices(ctx context.Context, job GenericJob) ([]kueue.Workload, error) {
+	object := job.Object()
+	var workloads kueue.WorkloadList
+	if err := r.client.List(ctx, &workloads, client.InNamespace(object.GetNamespace()),
+		client.MatchingFields{OwnerReferenceIndexKey(job.GVK()): object.GetName()}); err != nil {
+		return nil, err
+	}
+	slices := make([]kueue.Workload, 0, len(workloads.Items))
+	for i := range workloads.Items {
+		// Indexes don't work in unit tests, so we explicitly check for the
+		// owner here.
+		if metav1.IsControlledBy(&workloads.Items[i], object) {
+			slices = append(slices, workloads.Items[i])
+		}
+	}
+	sort.Slice(slices, func(i, j int) bool {
+		return slices[i].Name < slices[j].Name
+	})
+	return slices, nil
+}
+
+func anySliceAdmitted(slices []kueue.Workload) bool {
+	for i := range slices {
+		if slices[i].Spec.Admission != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// slicesMatch returns whether the workloads are slices of the current spec of
+// the suspended job, which has the original pod template and count.
+func slicesMatch(job GenericJob, slices []kueue.Workload) bool {
+	podSets, err := job.PodSets()
+	if err != nil || len(podSets) != 1 {
+		return false
+	}
+	hash := PodSetsHash(podSets)
+	total := int32(0)
+	for i := range slices {
+		wl := &slices[i]
+		if !workload.IsSlice(wl) || len(wl.Spec.PodSets) != 1 || wl.Annotations[constants.PodSetsHashAnnotation] != hash {
+			return false
+		}
+		total += wl.Spec.PodSets[0].Count
+	}
+	return total == podSets[0].Count
+}
+
+// ConstructWorkloadSlices returns the Workload slices for the job, owned by
+// it, each with up to size pods of its only pod set.
+func ConstructWorkloadSlices(ctx context.Context, c client.Client, job GenericJob, scheme *runtime.Scheme, size int32) ([]*kueue.Workload, error) {
+	base, err := ConstructWorkload(ctx, c, job, scheme)
+	if err != nil {
+		return nil, err
+	}
+	if len(base.Spec.PodSets) != 1 {
+		return nil, fmt.Errorf("workload slices require one podset, found %d", len(base.Spec.PodSets))
+	}
+	count := base.Spec.PodSets[0].Count
+	var slices []*kueue.Workload
+	for i := int32(0); i*size < count || i == 0; i++ {
+		wl := base.DeepCopy()
+		wl.Name = fmt.Sprintf("%s-slice-%d", base.Name, i)
+		wl.Annotations[constants.WorkloadSliceAnnotation] = strconv.Itoa(int(i))
+		ps := &wl.Spec.PodSets[0]
+		ps.Count = size
+		if remaining := count - i*size; remaining < size {
+			ps.Count = remaining
+		}
+		// Each slice is admitted with all its pods.
+		ps.MinCount = nil
+		slices = append(slices, wl)
+	}
+	return slices, nil
+}
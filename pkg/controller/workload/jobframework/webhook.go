@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+)
+
+// JobWebhook suspends the jobs that Kueue manages on creation, and validates
+// them. The jobs should be unstructured, so that the fields that the job
+// types don't know about are preserved in the patches.
+type JobWebhook struct {
+	client                       client.Client
+	newJob                       func() GenericJob
+	enabled                      bool
+	manageJobsWithoutQueueName   bool
+	managedJobsNamespaceSelector labels.Selector
+	defaultQueueName             string
+	admissionScope               []string
+}
+
+var _ admission.Handler = &JobWebhook{}
+
+// SetupWebhook registers the webhook for the jobs returned by newJob on the
+// given path, which must match the path of the kubebuilder marker of the
+// integration. The webhook is registered even if the integration is disabled,
+// since it's part of the manifests, but it only handles the jobs when it's
+// enabled.
+func SetupWebhook(mgr ctrl.Manager, path string, newJob func() GenericJob, opts ...Option) error {
+	options := ProcessOptions(opts...)
+	mgr.GetWebhookServer().Register(path, &webhook.Admission{
+		Handler: &JobWebhook{
+			client:                       mgr.GetClient(),
+			newJob:                       newJob,
+			enabled:                      options.Enabled,
+			manageJobsWithoutQueueName:   options.ManageJobsWithoutQueueName,
+			managedJobsNamespaceSelector: options.ManagedJobsNamespaceSelector,
+			defaultQueueName:             options.DefaultQueueName,
+			admissionScope:               options.AdmissionScope,
+		},
+	})
+	return nil
+}
+
+var suspendPath = field.NewPath("spec", "suspend")
+
+// Handle assigns the default LocalQueue of the namespace to the jobs without
+// queue name, and suspends the managed jobs, on creation. It also prevents the
+// changes of queue name of the jobs that aren't suspended.
+func (w *JobWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !w.enabled {
+		return admission.Allowed("")
+	}
+	job := w.newJob()
+	if err := json.Unmarshal(req.Object.Raw, job.Object()); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if j, ok := job.(JobWithSkip); ok && j.Skip() {
+		return admission.Allowed("")
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("job-webhook")
+
+	if req.Operation == admissionv1.Update {
+		oldJob := w.newJob()
+		if err := json.Unmarshal(req.OldObject.Raw, oldJob.Object()); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		log.V(5).Info("Validating update", "job", klog.KObj(job.Object()))
+		if QueueName(oldJob) != QueueName(job) && !job.IsSuspended() {
+			return admission.Denied(field.Forbidden(suspendPath, "should not update queue name when job is unsuspend").Error())
+		}
+		return admission.Allowed("")
+	}
+
+	inScope, err := InAdmissionScope(job, w.admissionScope)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !inScope {
+		return admission.Allowed("")
+	}
+
+	defaulted := false
+	if _, customQueueName := job.(JobWithCustomQueueName); QueueName(job) == "" && !customQueueName {
+		name, err := DefaultQueueName(ctx, w.client, req.Namespace, w.defaultQueueName)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if name != "" {
+			log.V(5).Info("Assigning the default LocalQueue", "job", klog.KObj(job.Object()), "queue", name)
+			setQueueName(job.Object(), name)
+			defaulted = true
+		}
+	}
+	if QueueName(job) == "" {
+		if !w.manageJobsWithoutQueueName {
+			return admission.Allowed("")
+		}
+		managed, err := NamespaceManaged(ctx, w.client, w.managedJobsNamespaceSelector, req.Namespace)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if !managed {
+			return admission.Allowed("")
+		}
+	}
+	if j, ok := job.(JobWithValidation); ok {
+		if errs := j.ValidateCreate(); len(errs) > 0 {
+			return admission.Denied(errs.ToAggregate().Error())
+		}
+	}
+	if job.IsSuspended() && !defaulted {
+		return admission.Allowed("")
+	}
+	if !job.IsSuspended() {
+		log.V(5).Info("Applying defaults", "job", klog.KObj(job.Object()))
+		if err := job.Suspend(); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	marshaled, err := json.Marshal(job.Object())
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// DefaultQueueName returns the name of the LocalQueue to assign to the jobs
+// created in the namespace without a queue name. It's the one in the
+// kueue.x-k8s.io/default-queue-name annotation of the namespace or, if the
+// namespace doesn't set it, the given name, if the namespace has a LocalQueue
+// with that name. It returns an empty name if there is no default LocalQueue.
+func DefaultQueueName(ctx context.Context, c client.Client, namespace, name string) (string, error) {
+	var ns corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("getting the namespace: %w", err)
+	}
+	if annotated := ns.Annotations[constants.DefaultQueueAnnotation]; annotated != "" {
+		return annotated, nil
+	}
+	if name == "" {
+		return "", nil
+	}
+	var q kueue.LocalQueue
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &q); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("getting the default LocalQueue: %w", err)
+	}
+	return name, nil
+}
+
+func setQueueName(obj client.Object, name string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[constants.QueueAnnotation] = name
+	obj.SetAnnotations(annotations)
+}
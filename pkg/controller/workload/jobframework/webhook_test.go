@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobframework
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/kueue/pkg/constants"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestHandle(t *testing.T) {
+	testcases := map[string]struct {
+		enabled                      bool
+		manageJobsWithoutQueueName   bool
+		managedJobsNamespaceSelector labels.Selector
+		defaultQueueName             string
+		admissionScope               []string
+		namespaceAnnotations         map[string]string
+		operation                    admissionv1.Operation
+		job                          string
+		oldJob                       string
+		wantAllowed                  bool
+		wantPatches                  []jsonpatch.JsonPatchOperation
+	}{
+		"integration disabled": {
+			operation:   admissionv1.Create,
+			job:         `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{}}`,
+			wantAllowed: true,
+		},
+		"job without queue name": {
+			enabled:     true,
+			operation:   admissionv1.Create,
+			job:         `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job"},"spec":{"suspend":false}}`,
+			wantAllowed: true,
+		},
+		"job without queue name with manageJobsWithoutQueueName": {
+			manageJobsWithoutQueueName: true,
+			enabled:                    true,
+			operation:                  admissionv1.Create,
+			job:                        `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job"},"spec":{"suspend":false}}`,
+			wantAllowed:                true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("replace", "/spec/suspend", true),
+			},
+		},
+		"job without queue name with manageJobsWithoutQueueName in managed namespace": {
+			manageJobsWithoutQueueName:   true,
+			managedJobsNamespaceSelector: labels.SelectorFromSet(labels.Set{corev1.LabelMetadataName: "ns"}),
+			enabled:                      true,
+			operation:                    admissionv1.Create,
+			job:                          `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","namespace":"ns"},"spec":{"suspend":false}}`,
+			wantAllowed:                  true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("replace", "/spec/suspend", true),
+			},
+		},
+		"job without queue name with manageJobsWithoutQueueName in opted-out namespace": {
+			manageJobsWithoutQueueName:   true,
+			managedJobsNamespaceSelector: labels.SelectorFromSet(labels.Set{corev1.LabelMetadataName: "other"}),
+			enabled:                      true,
+			operation:                    admissionv1.Create,
+			job:                          `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","namespace":"ns"},"spec":{"suspend":false}}`,
+			wantAllowed:                  true,
+		},
+		"job with queue name in opted-out namespace": {
+			manageJobsWithoutQueueName:   true,
+			managedJobsNamespaceSelector: labels.SelectorFromSet(labels.Set{corev1.LabelMetadataName: "other"}),
+			enabled:                      true,
+			operation:                    admissionv1.Create,
+			job:                          `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","namespace":"ns","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"suspend":false}}`,
+			wantAllowed:                  true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("replace", "/spec/suspend", true),
+			},
+		},
+		"job with queue name": {
+			enabled:     true,
+			operation:   admissionv1.Create,
+			job:         `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"unknownField":"value"}}`,
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/spec/suspend", true),
+			},
+		},
+		"suspended job with queue name": {
+			enabled:     true,
+			operation:   admissionv1.Create,
+			job:         `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"suspend":true}}`,
+			wantAllowed: true,
+		},
+		"job without queue name in namespace with default LocalQueue": {
+			enabled:              true,
+			namespaceAnnotations: map[string]string{constants.DefaultQueueAnnotation: "team-queue"},
+			operation:            admissionv1.Create,
+			job:                  `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","namespace":"ns"},"spec":{"suspend":false}}`,
+			wantAllowed:          true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/metadata/annotations", map[string]interface{}{constants.QueueAnnotation: "team-queue"}),
+				jsonpatch.NewOperation("replace", "/spec/suspend", true),
+			},
+		},
+		"suspended job without queue name with existing default LocalQueue": {
+			enabled:          true,
+			defaultQueueName: "default",
+			operation:        admissionv1.Create,
+			job:              `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","namespace":"ns"},"spec":{"suspend":true}}`,
+			wantAllowed:      true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/metadata/annotations", map[string]interface{}{constants.QueueAnnotation: "default"}),
+			},
+		},
+		"job without queue name with missing default LocalQueue": {
+			enabled:          true,
+			defaultQueueName: "other",
+			operation:        admissionv1.Create,
+			job:              `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","namespace":"ns"},"spec":{"suspend":false}}`,
+			wantAllowed:      true,
+		},
+		"job with queue name in namespace with default LocalQueue": {
+			enabled:              true,
+			namespaceAnnotations: map[string]string{constants.DefaultQueueAnnotation: "team-queue"},
+			operation:            admissionv1.Create,
+			job:                  `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","namespace":"ns","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"suspend":true}}`,
+			wantAllowed:          true,
+		},
+		"job with queue name outside the admission scope": {
+			enabled:        true,
+			admissionScope: []string{"nvidia.com/"},
+			operation:      admissionv1.Create,
+			job:            `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"suspend":false,"template":{"spec":{"containers":[{"name":"c","resources":{"requests":{"cpu":"1"}}}]}}}}`,
+			wantAllowed:    true,
+		},
+		"job with queue name in the admission scope": {
+			enabled:        true,
+			admissionScope: []string{"nvidia.com/"},
+			operation:      admissionv1.Create,
+			job:            `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"suspend":false,"template":{"spec":{"containers":[{"name":"c","resources":{"limits":{"nvidia.com/gpu":"1"}}}]}}}}`,
+			wantAllowed:    true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("replace", "/spec/suspend", true),
+			},
+		},
+		"change queue name of suspended job": {
+			enabled:     true,
+			operation:   admissionv1.Update,
+			job:         `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"other"}},"spec":{"suspend":true}}`,
+			oldJob:      `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"suspend":true}}`,
+			wantAllowed: true,
+		},
+		"change queue name of running job": {
+			enabled:   true,
+			operation: admissionv1.Update,
+			job:       `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"other"}},"spec":{"suspend":false}}`,
+			oldJob:    `{"apiVersion":"example.com/v1","kind":"TestJob","metadata":{"name":"job","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"suspend":false}}`,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).WithObjects([]client.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:        "ns",
+					Labels:      map[string]string{corev1.LabelMetadataName: "ns"},
+					Annotations: tc.namespaceAnnotations,
+				}},
+				utiltesting.MakeLocalQueue("default", "ns").Obj(),
+			}...).Build()
+			w := &JobWebhook{
+				client:                       cl,
+				newJob:                       newTestJob,
+				enabled:                      tc.enabled,
+				manageJobsWithoutQueueName:   tc.manageJobsWithoutQueueName,
+				managedJobsNamespaceSelector: tc.managedJobsNamespaceSelector,
+				defaultQueueName:             tc.defaultQueueName,
+				admissionScope:               tc.admissionScope,
+			}
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Namespace: "ns",
+				Object:    runtime.RawExtension{Raw: []byte(tc.job)},
+			}}
+			if tc.oldJob != "" {
+				req.OldObject = runtime.RawExtension{Raw: []byte(tc.oldJob)}
+			}
+			resp := w.Handle(context.Background(), req)
+			if resp.Allowed != tc.wantAllowed {
+				t.Errorf("Handle() allowed = %t, want %t", resp.Allowed, tc.wantAllowed)
+			}
+			if diff := cmp.Diff(tc.wantPatches, resp.Patches, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(a, b jsonpatch.JsonPatchOperation) bool { return a.Path < b.Path })); diff != "" {
+				t.Errorf("Unexpected patches (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jobs imports the packages of the integrations with the job
+// frameworks, so that they register in the jobframework package.
+package jobs
+
+import (
+	// Register the integrations.
+	_ "sigs.k8s.io/kueue/pkg/controller/workload/job"
+	_ "sigs.k8s.io/kueue/pkg/controller/workload/mpijob"
+	_ "sigs.k8s.io/kueue/pkg/controller/workload/pytorchjob"
+	_ "sigs.k8s.io/kueue/pkg/controller/workload/ray"
+	_ "sigs.k8s.io/kueue/pkg/controller/workload/tfjob"
+	_ "sigs.k8s.io/kueue/pkg/controller/workload/xgboostjob"
+)
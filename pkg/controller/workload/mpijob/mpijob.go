@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mpijob integrates the MPIJobs of the Kubeflow MPI operator with
+// Kueue. The MPIJobs are handled as unstructured, so that Kueue doesn't depend
+// on the MPI operator API.
+package mpijob
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+// FrameworkName is the name of the integration in the configuration.
+const FrameworkName = "kubeflow.org/mpijob"
+
+var GVK = schema.GroupVersionKind{Group: "kubeflow.org", Version: "v2beta1", Kind: "MPIJob"}
+
+func init() {
+	if err := jobframework.RegisterIntegration(FrameworkName, jobframework.IntegrationCallbacks{
+		NewJob:       NewMPIJob,
+		SetupWebhook: SetupWebhook,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+//+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloadpriorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update
+//+kubebuilder:rbac:groups=kubeflow.org,resources=mpijobs,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=kubeflow.org,resources=mpijobs/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+
+// +kubebuilder:webhook:path=/mutate-kubeflow-org-v2beta1-mpijob,mutating=true,failurePolicy=fail,sideEffects=None,groups=kubeflow.org,resources=mpijobs,verbs=create;update,versions=v2beta1,name=mmpijob.kb.io,admissionReviewVersions=v1
+
+// SetupWebhook configures the webhook for MPIJobs.
+func SetupWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
+	return jobframework.SetupWebhook(mgr, "/mutate-kubeflow-org-v2beta1-mpijob", NewMPIJob, opts...)
+}
+
+// NewMPIJob returns an empty MPIJob, with a pod set for the launcher and one
+// for the workers.
+func NewMPIJob() jobframework.GenericJob {
+	return jobframework.NewKubeflowJob(GVK, "mpiReplicaSpecs", []string{"Launcher", "Worker"})
+}
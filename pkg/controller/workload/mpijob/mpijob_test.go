@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mpijob
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+const testSpec = `{
+	"runPolicy": {"suspend": true},
+	"mpiReplicaSpecs": {
+		"Launcher": {"replicas": 1, "template": {"spec": {"containers": [{"name": "launcher"}]}}},
+		"Worker": {"replicas": 4, "template": {"spec": {"containers": [{"name": "worker"}]}}}
+	}
+}`
+
+func makeMPIJob(t *testing.T, spec, status string) jobframework.GenericJob {
+	t.Helper()
+	j := NewMPIJob()
+	raw := `{"apiVersion": "kubeflow.org/v2beta1", "kind": "MPIJob", "metadata": {"name": "job", "namespace": "ns"}, "spec": ` + spec
+	if status != "" {
+		raw += `, "status": ` + status
+	}
+	if err := json.Unmarshal([]byte(raw+"}"), j.Object()); err != nil {
+		t.Fatalf("Invalid MPIJob: %v", err)
+	}
+	return j
+}
+
+func TestPodSets(t *testing.T) {
+	testcases := map[string]struct {
+		spec string
+		want []kueue.PodSet
+	}{
+		"launcher and workers": {
+			spec: testSpec,
+			want: []kueue.PodSet{
+				{
+					Name:  "launcher",
+					Count: 1,
+					Spec:  corev1.PodSpec{Containers: []corev1.Container{{Name: "launcher"}}},
+				},
+				{
+					Name:  "worker",
+					Count: 4,
+					Spec:  corev1.PodSpec{Containers: []corev1.Container{{Name: "worker"}}},
+				},
+			},
+		},
+		"only launcher": {
+			spec: `{"mpiReplicaSpecs": {"Launcher": {"template": {"spec": {"containers": [{"name": "launcher"}]}}}}}`,
+			want: []kueue.PodSet{
+				{
+					Name:  "launcher",
+					Count: 1,
+					Spec:  corev1.PodSpec{Containers: []corev1.Container{{Name: "launcher"}}},
+				},
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			got, err := makeMPIJob(t, tc.spec, "").PodSets()
+			if err != nil {
+				t.Fatalf("PodSets() returned error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected pod sets (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunAndRestore(t *testing.T) {
+	j := makeMPIJob(t, testSpec, "")
+	infos := []jobframework.PodSetInfo{
+		{Name: "launcher", NodeSelector: map[string]string{"instance": "on-demand"}},
+		{Name: "worker", NodeSelector: map[string]string{"instance": "spot"}},
+	}
+	if err := j.RunWithPodSetsInfo(infos); err != nil {
+		t.Fatalf("RunWithPodSetsInfo() returned error: %v", err)
+	}
+	if j.IsSuspended() {
+		t.Errorf("The job is still suspended")
+	}
+	podSets, err := j.PodSets()
+	if err != nil {
+		t.Fatalf("PodSets() returned error: %v", err)
+	}
+	for i := range podSets {
+		if diff := cmp.Diff(infos[i].NodeSelector, podSets[i].Spec.NodeSelector); diff != "" {
+			t.Errorf("Unexpected node selector of pod set %s (-want,+got):\n%s", podSets[i].Name, diff)
+		}
+	}
+
+	if err := j.Suspend(); err != nil {
+		t.Fatalf("Suspend() returned error: %v", err)
+	}
+	changed, err := j.RestorePodSetsInfo(make([]jobframework.PodSetInfo, 2))
+	if err != nil {
+		t.Fatalf("RestorePodSetsInfo() returned error: %v", err)
+	}
+	if !changed {
+		t.Errorf("RestorePodSetsInfo() didn't change the job")
+	}
+	if !j.IsSuspended() {
+		t.Errorf("The job isn't suspended")
+	}
+	if _, err := j.RestorePodSetsInfo(make([]jobframework.PodSetInfo, 1)); err == nil {
+		t.Errorf("RestorePodSetsInfo() didn't fail with a wrong number of pod sets")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	testcases := map[string]struct {
+		status        string
+		wantActive    bool
+		wantPodsReady bool
+		wantFinished  bool
+		wantCondition metav1.Condition
+	}{
+		"no status": {},
+		"starting": {
+			status:     `{"replicaStatuses": {"Launcher": {"active": 1}, "Worker": {"active": 2}}}`,
+			wantActive: true,
+		},
+		"running": {
+			status:        `{"conditions": [{"type": "Running", "status": "True"}], "replicaStatuses": {"Launcher": {"active": 1}, "Worker": {"active": 4}}}`,
+			wantActive:    true,
+			wantPodsReady: true,
+		},
+		"succeeded": {
+			status:        `{"conditions": [{"type": "Running", "status": "False"}, {"type": "Succeeded", "status": "True"}], "replicaStatuses": {"Launcher": {"succeeded": 1}, "Worker": {"succeeded": 4}}}`,
+			wantPodsReady: true,
+			wantFinished:  true,
+			wantCondition: metav1.Condition{
+				Type:    kueue.WorkloadFinished,
+				Status:  metav1.ConditionTrue,
+				Reason:  "JobFinished",
+				Message: "Job finished successfully",
+			},
+		},
+		"failed": {
+			status:       `{"conditions": [{"type": "Failed", "status": "True", "message": "launcher failed"}]}`,
+			wantFinished: true,
+			wantCondition: metav1.Condition{
+				Type:    kueue.WorkloadFinished,
+				Status:  metav1.ConditionTrue,
+				Reason:  "JobFinished",
+				Message: "launcher failed",
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			j := makeMPIJob(t, testSpec, tc.status)
+			if got := j.IsActive(); got != tc.wantActive {
+				t.Errorf("IsActive() = %t, want %t", got, tc.wantActive)
+			}
+			if got := j.PodsReady(); got != tc.wantPodsReady {
+				t.Errorf("PodsReady() = %t, want %t", got, tc.wantPodsReady)
+			}
+			condition, finished := j.Finished()
+			if finished != tc.wantFinished {
+				t.Errorf("Finished() = %t, want %t", finished, tc.wantFinished)
+			}
+			if diff := cmp.Diff(tc.wantCondition, condition); diff != "" {
+				t.Errorf("Unexpected condition (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
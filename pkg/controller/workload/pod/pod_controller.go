@@ -0,0 +1,496 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+const (
+	// SchedulingGate is the scheduling gate that keeps the Pods managed by
+	// Kueue from being scheduled until their Workload is admitted.
+	SchedulingGate = "kueue.x-k8s.io/admission"
+
+	// ManagedLabel is the label that the webhook adds to the Pods that it
+	// gates.
+	ManagedLabel = "kueue.x-k8s.io/managed"
+
+	// GroupNameLabel is the label of the Pods that are admitted together,
+	// with a single Workload. The value is the name of the group, unique in
+	// the namespace.
+	GroupNameLabel = "kueue.x-k8s.io/pod-group-name"
+
+	// GroupTotalCountAnnotation is the annotation of the Pods of a group that
+	// holds the number of Pods in the group. The Workload of the group is not
+	// created until that many Pods exist.
+	GroupTotalCountAnnotation = "kueue.x-k8s.io/pod-group-total-count"
+)
+
+// Reconciler reconciles the Pods managed by Kueue.
+type Reconciler struct {
+	client client.Client
+	scheme *runtime.Scheme
+	record record.EventRecorder
+}
+
+type options struct {
+	enabled bool
+}
+
+// Option configures the webhook.
+type Option func(*options)
+
+// WithEnabled indicates if the webhook should gate the Pods that set the
+// queue name annotation.
+func WithEnabled(f bool) Option {
+	return func(o *options) {
+		o.enabled = f
+	}
+}
+
+var defaultOptions = options{}
+
+func NewReconciler(scheme *runtime.Scheme, client client.Client, record record.EventRecorder) *Reconciler {
+	return &Reconciler{
+		scheme: scheme,
+		client: client,
+		record: record,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager. The Pods are
+// reconciled in reaction to the events of the Workloads that they own.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("pod").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetLabels()[ManagedLabel] == "true"
+		}))).
+		Watches(&source.Kind{Type: &kueue.Workload{}}, handler.EnqueueRequestsFromMapFunc(ownerPods)).
+		Complete(r)
+}
+
+// ownerPods returns the requests for the Pods that own a Workload.
+func ownerPods(o client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, owner := range o.GetOwnerReferences() {
+		if owner.APIVersion == "v1" && owner.Kind == "Pod" {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: owner.Name, Namespace: o.GetNamespace()},
+			})
+		}
+	}
+	return requests
+}
+
+//+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloadpriorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update;patch;delete
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pod corev1.Pod
+	if err := r.client.Get(ctx, req.NamespacedName, &pod); err != nil {
+		// we'll ignore not-found errors, since there is nothing to do.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if pod.Labels[ManagedLabel] != "true" {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithValues("pod", klog.KObj(&pod))
+	ctx = ctrl.LoggerInto(ctx, log)
+	log.V(2).Info("Reconciling Pod")
+
+	pods, err := r.groupPods(ctx, &pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var wl kueue.Workload
+	if err := r.client.Get(ctx, types.NamespacedName{Name: WorkloadName(&pod), Namespace: pod.Namespace}, &wl); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.handlePodsWithNoWorkload(ctx, &pod, pods)
+	}
+
+	// Pods can join a group after its workload is created.
+	if !ownedBy(&wl, &pod) {
+		log.V(2).Info("Adding the pod to the owners of the workload", "workload", klog.KObj(&wl))
+		if err := controllerutil.SetOwnerReference(&pod, &wl, r.scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(r.client.Update(ctx, &wl))
+	}
+
+	if condition, finished := podsFinishedCondition(&pod, pods); finished {
+		if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+			return ctrl.Result{}, nil
+		}
+		apimeta.SetStatusCondition(&wl.Status.Conditions, condition)
+		return ctrl.Result{}, client.IgnoreNotFound(r.client.Status().Update(ctx, &wl))
+	}
+
+	if workload.IsEvicted(&wl) && wl.Spec.Admission != nil {
+		return ctrl.Result{}, r.handleEvictedWorkload(ctx, &wl, &pod)
+	}
+
+	if workload.IsAdmitted(&wl) && workload.IsActive(&wl) {
+		if pod.Spec.NodeName == "" && pod.Status.Phase == corev1.PodPending {
+			return ctrl.Result{}, r.ungate(ctx, &wl, &pod)
+		}
+		log.V(3).Info("Pod running with admitted workload, nothing to do")
+		return ctrl.Result{}, nil
+	}
+
+	if wl.Spec.Admission == nil && pod.Spec.NodeName != "" && !podFinished(&pod) {
+		// Pods can't be suspended, so the running pods of a workload whose
+		// admission was cancelled are deleted.
+		log.V(2).Info("Running pod is not admitted by a cluster queue, deleting")
+		return ctrl.Result{}, r.stopPod(ctx, &pod, "Not admitted by cluster queue")
+	}
+	log.V(3).Info("Pod is gated and workload not yet admitted by a clusterQueue, nothing to do")
+	return ctrl.Result{}, nil
+}
+
+// groupPods returns the managed pods of the group of the pod, or only the pod
+// if it doesn't belong to a group.
+func (r *Reconciler) groupPods(ctx context.Context, pod *corev1.Pod) ([]corev1.Pod, error) {
+	name := groupName(pod)
+	if name == "" {
+		return []corev1.Pod{*pod}, nil
+	}
+	var pods corev1.PodList
+	if err := r.client.List(ctx, &pods, client.InNamespace(pod.Namespace),
+		client.MatchingLabels{GroupNameLabel: name, ManagedLabel: "true"}); err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func (r *Reconciler) handlePodsWithNoWorkload(ctx context.Context, pod *corev1.Pod, pods []corev1.Pod) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if podFinished(pod) {
+		return nil
+	}
+	if pod.Spec.NodeName != "" {
+		log.V(2).Info("Pod with no matching workload, deleting")
+		return r.stopPod(ctx, pod, "No matching Workload")
+	}
+	if total := groupTotalCount(pod); int32(len(pods)) < total {
+		log.V(3).Info("Waiting for the rest of the pods of the group", "pods", len(pods), "totalCount", total)
+		return nil
+	}
+
+	// Create the corresponding workload.
+	wl, err := ConstructWorkloadFor(ctx, r.client, pod, pods, r.scheme)
+	if err != nil {
+		return err
+	}
+	if err = r.client.Create(ctx, wl); err != nil {
+		return client.IgnoreAlreadyExists(err)
+	}
+	r.record.Eventf(pod, corev1.EventTypeNormal, "CreatedWorkload",
+		"Created Workload: %v", workload.Key(wl))
+	return nil
+}
+
+// ungate removes the scheduling gate of Kueue from the pod, and injects the
+// node selectors and tolerations of the assigned flavors and the changes
+// required by the admission checks. The pod is handled as unstructured to preserve the
+// scheduling gates, which the Pod type of this version of the Kubernetes API
+// doesn't know about.
+func (r *Reconciler) ungate(ctx context.Context, wl *kueue.Workload, pod *corev1.Pod) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(pod), u); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	gates, _, err := unstructured.NestedSlice(u.Object, "spec", "schedulingGates")
+	if err != nil {
+		return err
+	}
+	i := schedulingGateIndex(gates)
+	if i < 0 {
+		log.V(3).Info("Pod already ungated, nothing to do")
+		return nil
+	}
+	gates = append(gates[:i], gates[i+1:]...)
+	if len(gates) == 0 {
+		unstructured.RemoveNestedField(u.Object, "spec", "schedulingGates")
+	} else if err := unstructured.SetNestedSlice(u.Object, gates, "spec", "schedulingGates"); err != nil {
+		return err
+	}
+
+	nodeSelector, tolerations, err := r.flavorsSchedulingDirectives(ctx, wl)
+	if err != nil {
+		return err
+	}
+	labels, annotations := u.GetLabels(), u.GetAnnotations()
+	for _, check := range wl.Status.AdmissionChecks {
+		for _, update := range check.PodSetUpdates {
+			if update.Name != wl.Spec.PodSets[0].Name {
+				continue
+			}
+			labels = jobframework.MergeMaps(labels, update.Labels)
+			annotations = jobframework.MergeMaps(annotations, update.Annotations)
+			nodeSelector = jobframework.MergeMaps(nodeSelector, update.NodeSelector)
+		}
+	}
+	u.SetLabels(labels)
+	u.SetAnnotations(annotations)
+	if len(nodeSelector) != 0 {
+		podNodeSelector, _, err := unstructured.NestedStringMap(u.Object, "spec", "nodeSelector")
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedStringMap(u.Object, jobframework.MergeMaps(podNodeSelector, nodeSelector), "spec", "nodeSelector"); err != nil {
+			return err
+		}
+	}
+	if err := jobframework.MergeNestedTolerations(u.Object, tolerations); err != nil {
+		return err
+	}
+
+	log.V(2).Info("Pod admitted, ungating")
+	if err := r.client.Update(ctx, u); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.record.Eventf(pod, corev1.EventTypeNormal, "Started",
+		"Admitted by clusterQueue %v", wl.Spec.Admission.ClusterQueue)
+	return nil
+}
+
+// flavorsSchedulingDirectives returns the node labels of the flavors and of
+// the topology domain that the pods of the workload were admitted with, and
+// the tolerations of the flavors.
+func (r *Reconciler) flavorsSchedulingDirectives(ctx context.Context, wl *kueue.Workload) (map[string]string, []corev1.Toleration, error) {
+	psFlavors := wl.Spec.Admission.PodSetFlavors[0]
+	nodeSelector := map[string]string{}
+	var tolerations []corev1.Toleration
+	processedFlvs := sets.NewString()
+	for _, flvName := range psFlavors.Flavors {
+		if processedFlvs.Has(flvName) {
+			continue
+		}
+		flv := kueue.ResourceFlavor{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: flvName}, &flv); err != nil {
+			return nil, nil, err
+		}
+		for k, v := range flv.NodeSelector {
+			nodeSelector[k] = v
+		}
+		tolerations = jobframework.MergeTolerations(tolerations, flv.Tolerations)
+		processedFlvs.Insert(flvName)
+	}
+	for k, v := range psFlavors.TopologyDomain {
+		nodeSelector[k] = v
+	}
+	return nodeSelector, tolerations, nil
+}
+
+// stopPod deletes a running pod that is not admitted.
+func (r *Reconciler) stopPod(ctx context.Context, pod *corev1.Pod, eventMsg string) error {
+	if err := r.client.Delete(ctx, pod); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.record.Eventf(pod, corev1.EventTypeNormal, "Stopped", eventMsg)
+	return nil
+}
+
+// handleEvictedWorkload deletes the pod of an evicted workload, if it's
+// running, and then clears the admission of the workload, which releases its
+// quota. The rest of the pods of a group are deleted once the admission is
+// cleared.
+func (r *Reconciler) handleEvictedWorkload(ctx context.Context, wl *kueue.Workload, pod *corev1.Pod) error {
+	log := ctrl.LoggerFrom(ctx)
+	if pod.Spec.NodeName != "" && !podFinished(pod) {
+		evicted := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadEvicted)
+		log.V(2).Info("Workload evicted, deleting the pod", "reason", evicted.Reason)
+		if err := r.stopPod(ctx, pod, evicted.Message); err != nil {
+			return err
+		}
+	}
+	log.V(2).Info("Clearing the admission of the evicted workload")
+	err := workload.ClearAdmission(ctx, r.client, wl)
+	return client.IgnoreNotFound(err)
+}
+
+// ConstructWorkloadFor returns the workload for the pods of a group, or for a
+// single pod. The workload has a single podSet with the spec of the given
+// pod, since the pods of a group are expected to have the same requests.
+func ConstructWorkloadFor(ctx context.Context, client client.Client,
+	pod *corev1.Pod, pods []corev1.Pod, scheme *runtime.Scheme) (*kueue.Workload, error) {
+	w := &kueue.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WorkloadName(pod),
+			Namespace: pod.Namespace,
+		},
+		Spec: kueue.WorkloadSpec{
+			PodSets: []kueue.PodSet{
+				{
+					Name:  kueue.DefaultPodSetName,
+					Spec:  *pod.Spec.DeepCopy(),
+					Count: groupTotalCount(pod),
+
+					TopologyRequest: topologyRequest(pod),
+					FlavorAffinity:  jobframework.FlavorAffinity(pod.Annotations),
+				},
+			},
+			QueueName: queueName(pod),
+		},
+	}
+
+	// Populate priority from the WorkloadPriorityClass or the priority class.
+	priorityClassName, source, p, err := utilpriority.GetPriority(
+		ctx, client, pod.Labels[constants.WorkloadPriorityClassLabel], pod.Spec.PriorityClassName)
+	if err != nil {
+		return nil, err
+	}
+	w.Spec.Priority = &p
+	w.Spec.PriorityClassName = priorityClassName
+	w.Spec.PriorityClassSource = source
+
+	if groupName(pod) == "" {
+		if err := ctrl.SetControllerReference(pod, w, scheme); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	// The workload of a group is garbage collected once all its pods are
+	// deleted.
+	for i := range pods {
+		if err := controllerutil.SetOwnerReference(&pods[i], w, scheme); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// WorkloadName returns the name of the workload of the pod, which is based on
+// the name of its group, or on the name of the pod if it doesn't belong to a
+// group.
+func WorkloadName(pod *corev1.Pod) string {
+	if name := groupName(pod); name != "" {
+		return "pod-group-" + name
+	}
+	return "pod-" + pod.Name
+}
+
+// podsFinishedCondition returns the Finished condition of the workload and
+// whether all the pods of the group, or the single pod, finished.
+func podsFinishedCondition(pod *corev1.Pod, pods []corev1.Pod) (metav1.Condition, bool) {
+	if int32(len(pods)) < groupTotalCount(pod) {
+		return metav1.Condition{}, false
+	}
+	failed := false
+	for i := range pods {
+		if !podFinished(&pods[i]) {
+			return metav1.Condition{}, false
+		}
+		failed = failed || pods[i].Status.Phase == corev1.PodFailed
+	}
+	message := "Pods finished successfully"
+	if failed {
+		message = "Some pods failed"
+	}
+	return metav1.Condition{
+		Type:    kueue.WorkloadFinished,
+		Status:  metav1.ConditionTrue,
+		Reason:  "PodsFinished",
+		Message: message,
+	}, true
+}
+
+func podFinished(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func ownedBy(wl *kueue.Workload, pod *corev1.Pod) bool {
+	for _, owner := range wl.OwnerReferences {
+		if owner.UID == pod.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// topologyRequest returns the topology requested in the annotations of the
+// pod, or nil if there is none. The required topology takes precedence over
+// the preferred one.
+func topologyRequest(pod *corev1.Pod) *kueue.PodSetTopologyRequest {
+	if level, found := pod.Annotations[constants.PodSetRequiredTopologyAnnotation]; found {
+		return &kueue.PodSetTopologyRequest{Required: &level}
+	}
+	if level, found := pod.Annotations[constants.PodSetPreferredTopologyAnnotation]; found {
+		return &kueue.PodSetTopologyRequest{Preferred: &level}
+	}
+	return nil
+}
+
+// groupTotalCount returns the number of pods of the group of the pod, or 1 if
+// it doesn't belong to a group.
+func groupTotalCount(pod *corev1.Pod) int32 {
+	if groupName(pod) == "" {
+		return 1
+	}
+	v, err := strconv.Atoi(pod.Annotations[GroupTotalCountAnnotation])
+	if err != nil || v <= 0 {
+		return 1
+	}
+	return int32(v)
+}
+
+func groupName(pod *corev1.Pod) string {
+	return pod.Labels[GroupNameLabel]
+}
+
+func queueName(pod *corev1.Pod) string {
+	return pod.Annotations[constants.QueueAnnotation]
+}
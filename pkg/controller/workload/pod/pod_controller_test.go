@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	testingutil "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestReconcile(t *testing.T) {
+	groupPod := func(name string) *testingutil.PodWrapper {
+		return testingutil.MakePod(name, "ns").
+			Queue("queue").
+			Label(ManagedLabel, "true").
+			Label(GroupNameLabel, "group").
+			Annotation(GroupTotalCountAnnotation, "2").
+			Request(corev1.ResourceCPU, "1")
+	}
+	testcases := map[string]struct {
+		pods          []*corev1.Pod
+		workload      *kueue.Workload
+		reconciled    string
+		wantWorkload  *kueue.Workload
+		wantPodsCount int
+	}{
+		"creates the workload of a single pod": {
+			pods: []*corev1.Pod{
+				testingutil.MakePod("a", "ns").Queue("queue").Label(ManagedLabel, "true").Request(corev1.ResourceCPU, "1").Obj(),
+			},
+			reconciled: "a",
+			wantWorkload: testingutil.MakeWorkload("pod-a", "ns").
+				Queue("queue").
+				PodSets([]kueue.PodSet{{
+					Name:  kueue.DefaultPodSetName,
+					Count: 1,
+					Spec:  testingutil.MakePod("a", "ns").Request(corev1.ResourceCPU, "1").Obj().Spec,
+				}}).
+				Priority(0).
+				Obj(),
+			wantPodsCount: 1,
+		},
+		"waits for the rest of the group": {
+			pods:          []*corev1.Pod{groupPod("a").Obj()},
+			reconciled:    "a",
+			wantPodsCount: 1,
+		},
+		"creates the workload of a group": {
+			pods:       []*corev1.Pod{groupPod("a").Obj(), groupPod("b").Obj()},
+			reconciled: "b",
+			wantWorkload: testingutil.MakeWorkload("pod-group-group", "ns").
+				Queue("queue").
+				PodSets([]kueue.PodSet{{
+					Name:  kueue.DefaultPodSetName,
+					Count: 2,
+					Spec:  groupPod("b").Obj().Spec,
+				}}).
+				Priority(0).
+				Obj(),
+			wantPodsCount: 2,
+		},
+		"finishes the workload of a group": {
+			pods: []*corev1.Pod{
+				groupPod("a").Phase(corev1.PodSucceeded).Obj(),
+				groupPod("b").Phase(corev1.PodFailed).Obj(),
+			},
+			workload: testingutil.MakeWorkload("pod-group-group", "ns").
+				Admit(testingutil.MakeAdmission("cq").Obj()).
+				Obj(),
+			reconciled: "a",
+			wantWorkload: testingutil.MakeWorkload("pod-group-group", "ns").
+				Admit(testingutil.MakeAdmission("cq").Obj()).
+				Condition(metav1.Condition{
+					Type:    kueue.WorkloadFinished,
+					Status:  metav1.ConditionTrue,
+					Reason:  "PodsFinished",
+					Message: "Some pods failed",
+				}).
+				Obj(),
+			wantPodsCount: 2,
+		},
+		"deletes a running pod whose admission was cancelled": {
+			pods: []*corev1.Pod{
+				testingutil.MakePod("a", "ns").Queue("queue").Label(ManagedLabel, "true").NodeName("node").Phase(corev1.PodRunning).Obj(),
+			},
+			workload:     testingutil.MakeWorkload("pod-a", "ns").Obj(),
+			reconciled:   "a",
+			wantWorkload: testingutil.MakeWorkload("pod-a", "ns").Obj(),
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding client-go scheme: %v", err)
+			}
+			if err := kueue.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding kueue scheme: %v", err)
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, p := range tc.pods {
+				builder = builder.WithObjects(p)
+			}
+			if tc.workload != nil {
+				wl := tc.workload.DeepCopy()
+				for _, p := range tc.pods {
+					wl.OwnerReferences = append(wl.OwnerReferences, metav1.OwnerReference{
+						APIVersion: "v1",
+						Kind:       "Pod",
+						Name:       p.Name,
+						UID:        p.UID,
+					})
+				}
+				builder = builder.WithObjects(wl)
+			}
+			cl := builder.Build()
+			r := NewReconciler(scheme, cl, record.NewFakeRecorder(10))
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: tc.reconciled, Namespace: "ns"}}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			var workloads kueue.WorkloadList
+			if err := cl.List(ctx, &workloads, client.InNamespace("ns")); err != nil {
+				t.Fatalf("Failed listing workloads: %v", err)
+			}
+			var gotWorkload *kueue.Workload
+			if len(workloads.Items) > 0 {
+				gotWorkload = &workloads.Items[0]
+			}
+			if diff := cmp.Diff(tc.wantWorkload, gotWorkload, cmpopts.EquateEmpty(),
+				cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion", "OwnerReferences"),
+				cmpopts.IgnoreTypes(metav1.TypeMeta{}),
+				cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected workload (-want,+got):\n%s", diff)
+			}
+			var pods corev1.PodList
+			if err := cl.List(ctx, &pods, client.InNamespace("ns")); err != nil && !apierrors.IsNotFound(err) {
+				t.Fatalf("Failed listing pods: %v", err)
+			}
+			if len(pods.Items) != tc.wantPodsCount {
+				t.Errorf("Got %d pods, want %d", len(pods.Items), tc.wantPodsCount)
+			}
+		})
+	}
+}
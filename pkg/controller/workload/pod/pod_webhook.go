@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/kueue/pkg/constants"
+)
+
+// PodWebhook gates the Pods managed by Kueue. The Pods are handled as raw
+// JSON, so that the fields that are unknown to the Pod type of this version
+// of the Kubernetes API, like the scheduling gates, are preserved.
+type PodWebhook struct {
+	enabled bool
+}
+
+// SetupWebhook configures the webhook for Pods. The webhook is always
+// registered, since it's part of the manifests, but it only gates Pods when
+// the integration is enabled.
+func SetupWebhook(mgr ctrl.Manager, opts ...Option) error {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	mgr.GetWebhookServer().Register("/mutate--v1-pod", &webhook.Admission{
+		Handler: &PodWebhook{enabled: options.enabled},
+	})
+	return nil
+}
+
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod.kb.io,admissionReviewVersions=v1
+
+var _ admission.Handler = &PodWebhook{}
+
+var groupTotalCountKeyPath = field.NewPath("metadata", "annotations").Key(GroupTotalCountAnnotation)
+
+// Handle adds the scheduling gate and the managed label to the Pods that set
+// the queue name annotation and aren't owned by a batch/v1.Job.
+func (w *PodWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !w.enabled {
+		return admission.Allowed("")
+	}
+	var pod corev1.Pod
+	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !managedByKueue(&pod) {
+		return admission.Allowed("")
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("pod-webhook")
+	log.V(5).Info("Applying defaults", "pod", klog.KObj(&pod))
+	if err := validateGroup(&pod); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	labels := u.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[ManagedLabel] = "true"
+	u.SetLabels(labels)
+	if err := addSchedulingGate(u); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	marshaled, err := json.Marshal(u.Object)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// managedByKueue returns whether the pod sets the queue name annotation and
+// isn't owned by a batch/v1.Job, whose pods are handled through the Job.
+func managedByKueue(pod *corev1.Pod) bool {
+	if pod.Annotations[constants.QueueAnnotation] == "" {
+		return false
+	}
+	owner := metav1.GetControllerOf(pod)
+	return owner == nil || owner.APIVersion != "batch/v1" || owner.Kind != "Job"
+}
+
+func validateGroup(pod *corev1.Pod) error {
+	if groupName(pod) == "" {
+		return nil
+	}
+	value := pod.Annotations[GroupTotalCountAnnotation]
+	v, err := strconv.Atoi(value)
+	if err != nil || v <= 0 {
+		return field.Invalid(groupTotalCountKeyPath, value, "should be a positive integer when the pod belongs to a group")
+	}
+	return nil
+}
+
+// addSchedulingGate adds the scheduling gate of Kueue to the pod, unless it's
+// already there.
+func addSchedulingGate(u *unstructured.Unstructured) error {
+	gates, _, err := unstructured.NestedSlice(u.Object, "spec", "schedulingGates")
+	if err != nil {
+		return err
+	}
+	if schedulingGateIndex(gates) >= 0 {
+		return nil
+	}
+	gates = append(gates, map[string]interface{}{"name": SchedulingGate})
+	return unstructured.SetNestedSlice(u.Object, gates, "spec", "schedulingGates")
+}
+
+// schedulingGateIndex returns the index of the scheduling gate of Kueue in
+// the gates, or -1 if it's not there.
+func schedulingGateIndex(gates []interface{}) int {
+	for i, g := range gates {
+		if gate, ok := g.(map[string]interface{}); ok && gate["name"] == SchedulingGate {
+			return i
+		}
+	}
+	return -1
+}
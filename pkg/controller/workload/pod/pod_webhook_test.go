@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestHandle(t *testing.T) {
+	testcases := map[string]struct {
+		enabled     bool
+		pod         string
+		wantAllowed bool
+		wantPatches []jsonpatch.JsonPatchOperation
+	}{
+		"integration disabled": {
+			pod:         `{"metadata":{"name":"pod","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{}}`,
+			wantAllowed: true,
+		},
+		"pod without queue name": {
+			enabled:     true,
+			pod:         `{"metadata":{"name":"pod"},"spec":{}}`,
+			wantAllowed: true,
+		},
+		"pod of a job": {
+			enabled:     true,
+			pod:         `{"metadata":{"name":"pod","annotations":{"kueue.x-k8s.io/queue-name":"queue"},"ownerReferences":[{"apiVersion":"batch/v1","kind":"Job","name":"job","uid":"uid","controller":true}]},"spec":{}}`,
+			wantAllowed: true,
+		},
+		"pod with queue name": {
+			enabled:     true,
+			pod:         `{"metadata":{"name":"pod","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{}}`,
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/metadata/labels", map[string]interface{}{ManagedLabel: "true"}),
+				jsonpatch.NewOperation("add", "/spec/schedulingGates", []interface{}{map[string]interface{}{"name": SchedulingGate}}),
+			},
+		},
+		"pod with other scheduling gates": {
+			enabled:     true,
+			pod:         `{"metadata":{"name":"pod","labels":{"app":"a"},"annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"schedulingGates":[{"name":"example.com/gate"}]}}`,
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/metadata/labels/kueue.x-k8s.io~1managed", "true"),
+				jsonpatch.NewOperation("add", "/spec/schedulingGates/1", map[string]interface{}{"name": SchedulingGate}),
+			},
+		},
+		"pod of a group without total count": {
+			enabled: true,
+			pod:     `{"metadata":{"name":"pod","labels":{"kueue.x-k8s.io/pod-group-name":"group"},"annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{}}`,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			w := &PodWebhook{enabled: tc.enabled}
+			resp := w.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: []byte(tc.pod)},
+			}})
+			if resp.Allowed != tc.wantAllowed {
+				t.Errorf("Handle() allowed = %t, want %t", resp.Allowed, tc.wantAllowed)
+			}
+			if diff := cmp.Diff(tc.wantPatches, resp.Patches, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(a, b jsonpatch.JsonPatchOperation) bool {
+				return a.Path < b.Path
+			})); diff != "" {
+				t.Errorf("Unexpected patches (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
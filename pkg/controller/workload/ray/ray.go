@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ray integrates the RayJobs and RayClusters of KubeRay with Kueue.
+// The objects are handled as unstructured, so that Kueue doesn't depend on the
+// KubeRay API.
+package ray
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+var (
+	RayJobGVK     = schema.GroupVersionKind{Group: "ray.io", Version: "v1", Kind: "RayJob"}
+	RayClusterGVK = schema.GroupVersionKind{Group: "ray.io", Version: "v1", Kind: "RayCluster"}
+)
+
+func init() {
+	if err := jobframework.RegisterIntegration(RayJobFrameworkName, jobframework.IntegrationCallbacks{
+		NewJob:       NewRayJob,
+		SetupWebhook: SetupRayJobWebhook,
+	}); err != nil {
+		panic(err)
+	}
+	if err := jobframework.RegisterIntegration(RayClusterFrameworkName, jobframework.IntegrationCallbacks{
+		NewJob:       NewRayCluster,
+		SetupWebhook: SetupRayClusterWebhook,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+const (
+	// headGroupPodSetName is the name of the pod set of the head of a cluster.
+	// The pod sets of the worker groups are named after the groups.
+	headGroupPodSetName = "head"
+
+	// maxWorkerGroups is the maximum number of worker groups of a cluster, so
+	// that its pod sets, including the head, fit in a Workload.
+	maxWorkerGroups = 7
+)
+
+//+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloadpriorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update
+//+kubebuilder:rbac:groups=ray.io,resources=rayjobs,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=ray.io,resources=rayclusters,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=ray.io,resources=rayjobs/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=ray.io,resources=rayclusters/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+
+// clusterTemplates returns the pod templates of the head and the worker
+// groups of a RayCluster spec, without copying them, and the names of their
+// pod sets and their counts.
+func clusterTemplates(spec map[string]interface{}) ([]map[string]interface{}, []string, []int32, error) {
+	head, _, err := unstructured.NestedFieldNoCopy(spec, "headGroupSpec", "template")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	headTemplate, ok := head.(map[string]interface{})
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("missing headGroupSpec.template")
+	}
+	templates := []map[string]interface{}{headTemplate}
+	names := []string{headGroupPodSetName}
+	counts := []int32{1}
+
+	groups, _, err := unstructured.NestedFieldNoCopy(spec, "workerGroupSpecs")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	groupList, _ := groups.([]interface{})
+	for i := range groupList {
+		group, ok := groupList[i].(map[string]interface{})
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("invalid workerGroupSpecs[%d]", i)
+		}
+		template, ok := group["template"].(map[string]interface{})
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("missing workerGroupSpecs[%d].template", i)
+		}
+		name, _, err := unstructured.NestedString(group, "groupName")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		replicas, found, err := unstructured.NestedInt64(group, "replicas")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !found {
+			replicas = 1
+		}
+		if hosts, found, err := unstructured.NestedInt64(group, "numOfHosts"); err != nil {
+			return nil, nil, nil, err
+		} else if found && hosts > 1 {
+			replicas *= hosts
+		}
+		templates = append(templates, template)
+		names = append(names, name)
+		counts = append(counts, int32(replicas))
+	}
+	return templates, names, counts, nil
+}
+
+func clusterPodSets(spec map[string]interface{}) ([]kueue.PodSet, error) {
+	templates, names, counts, err := clusterTemplates(spec)
+	if err != nil {
+		return nil, err
+	}
+	podSets := make([]kueue.PodSet, len(templates))
+	for i := range templates {
+		if podSets[i], err = jobframework.PodSetFromTemplate(templates[i], names[i], counts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return podSets, nil
+}
+
+func runCluster(spec map[string]interface{}, infos []jobframework.PodSetInfo) error {
+	templates, _, _, err := clusterTemplates(spec)
+	if err != nil {
+		return err
+	}
+	if len(templates) != len(infos) {
+		return fmt.Errorf("expecting %d pod sets, got %d", len(templates), len(infos))
+	}
+	for i := range templates {
+		if err := jobframework.ApplyPodSetInfo(templates[i], &infos[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreCluster(spec map[string]interface{}, infos []jobframework.PodSetInfo) (bool, error) {
+	templates, _, _, err := clusterTemplates(spec)
+	if err != nil {
+		return false, err
+	}
+	if len(templates) != len(infos) {
+		return false, fmt.Errorf("expecting %d pod sets, got %d", len(templates), len(infos))
+	}
+	changed := false
+	for i := range templates {
+		c, err := jobframework.RestorePodSetInfo(templates[i], &infos[i])
+		if err != nil {
+			return false, err
+		}
+		changed = changed || c
+	}
+	return changed, nil
+}
+
+func validateCluster(spec map[string]interface{}, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec == nil {
+		return append(allErrs, field.Required(path, "is required when the job is managed by Kueue"))
+	}
+	if autoscaling, _, _ := unstructured.NestedBool(spec, "enableInTreeAutoscaling"); autoscaling {
+		allErrs = append(allErrs, field.Invalid(path.Child("enableInTreeAutoscaling"), autoscaling, "is not supported by Kueue"))
+	}
+	if groups, _, _ := unstructured.NestedSlice(spec, "workerGroupSpecs"); len(groups) > maxWorkerGroups {
+		allErrs = append(allErrs, field.TooMany(path.Child("workerGroupSpecs"), len(groups), maxWorkerGroups))
+	}
+	if _, _, _, err := clusterTemplates(spec); err != nil {
+		allErrs = append(allErrs, field.Invalid(path, "", err.Error()))
+	}
+	return allErrs
+}
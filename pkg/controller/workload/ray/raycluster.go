@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ray
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+// RayClusterFrameworkName is the name of the integration of RayClusters in
+// the configuration.
+const RayClusterFrameworkName = "ray.io/raycluster"
+
+// +kubebuilder:webhook:path=/mutate-ray-io-v1-raycluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=ray.io,resources=rayclusters,verbs=create;update,versions=v1,name=mraycluster.kb.io,admissionReviewVersions=v1
+
+// SetupRayClusterWebhook configures the webhook for RayClusters.
+func SetupRayClusterWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
+	return jobframework.SetupWebhook(mgr, "/mutate-ray-io-v1-raycluster", NewRayCluster, opts...)
+}
+
+// RayCluster is a ray.io/v1 RayCluster. The clusters created by a RayJob are
+// managed through the RayJob.
+type RayCluster struct {
+	u unstructured.Unstructured
+}
+
+var (
+	_ jobframework.GenericJob        = &RayCluster{}
+	_ jobframework.JobWithValidation = &RayCluster{}
+	_ jobframework.JobWithSkip       = &RayCluster{}
+)
+
+// NewRayCluster returns an empty RayCluster.
+func NewRayCluster() jobframework.GenericJob {
+	c := &RayCluster{}
+	c.u.SetGroupVersionKind(RayClusterGVK)
+	return c
+}
+
+func (c *RayCluster) Object() client.Object {
+	return &c.u
+}
+
+func (c *RayCluster) GVK() schema.GroupVersionKind {
+	return RayClusterGVK
+}
+
+func (c *RayCluster) IsSuspended() bool {
+	suspend, _, _ := unstructured.NestedBool(c.u.Object, "spec", "suspend")
+	return suspend
+}
+
+func (c *RayCluster) Suspend() error {
+	return unstructured.SetNestedField(c.u.Object, true, "spec", "suspend")
+}
+
+func (c *RayCluster) RunWithPodSetsInfo(infos []jobframework.PodSetInfo) error {
+	if err := runCluster(c.spec(), infos); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(c.u.Object, false, "spec", "suspend")
+}
+
+func (c *RayCluster) RestorePodSetsInfo(infos []jobframework.PodSetInfo) (bool, error) {
+	return restoreCluster(c.spec(), infos)
+}
+
+// Finished returns false, since clusters run until they are deleted.
+func (c *RayCluster) Finished() (metav1.Condition, bool) {
+	return metav1.Condition{}, false
+}
+
+func (c *RayCluster) PodSets() ([]kueue.PodSet, error) {
+	return clusterPodSets(c.spec())
+}
+
+// IsActive returns whether the cluster has, or is creating, pods.
+func (c *RayCluster) IsActive() bool {
+	state, _, _ := unstructured.NestedString(c.u.Object, "status", "state")
+	replicas, _, _ := unstructured.NestedInt64(c.u.Object, "status", "desiredWorkerReplicas")
+	return state == "ready" || replicas > 0
+}
+
+func (c *RayCluster) PodsReady() bool {
+	state, _, _ := unstructured.NestedString(c.u.Object, "status", "state")
+	return state == "ready"
+}
+
+func (c *RayCluster) EquivalentToWorkload(wl *kueue.Workload) bool {
+	podSets, err := c.PodSets()
+	return err == nil && jobframework.PodSetsEquivalent(wl.Spec.PodSets, podSets)
+}
+
+// ValidateCreate rejects the clusters that Kueue can't represent as a
+// Workload.
+func (c *RayCluster) ValidateCreate() field.ErrorList {
+	return validateCluster(c.spec(), field.NewPath("spec"))
+}
+
+// Skip returns whether the cluster is owned by a RayJob.
+func (c *RayCluster) Skip() bool {
+	owner := metav1.GetControllerOf(&c.u)
+	return owner != nil && owner.Kind == RayJobGVK.Kind && schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind).Group == RayJobGVK.Group
+}
+
+func (c *RayCluster) spec() map[string]interface{} {
+	spec, _ := c.u.Object["spec"].(map[string]interface{})
+	return spec
+}
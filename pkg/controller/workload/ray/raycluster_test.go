@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ray
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func makeRayCluster(t *testing.T, metadata, spec string) *RayCluster {
+	t.Helper()
+	c := NewRayCluster().(*RayCluster)
+	if err := json.Unmarshal([]byte(`{"apiVersion": "ray.io/v1", "kind": "RayCluster", "metadata": `+metadata+`, "spec": `+spec+`}`), c.Object()); err != nil {
+		t.Fatalf("Invalid RayCluster: %v", err)
+	}
+	return c
+}
+
+func TestRayClusterSkip(t *testing.T) {
+	testcases := map[string]struct {
+		metadata string
+		wantSkip bool
+	}{
+		"standalone cluster": {
+			metadata: `{"name": "cluster"}`,
+		},
+		"cluster of a RayJob": {
+			metadata: `{"name": "cluster", "ownerReferences": [{"apiVersion": "ray.io/v1", "kind": "RayJob", "name": "job", "uid": "uid", "controller": true}]}`,
+			wantSkip: true,
+		},
+		"cluster owned by another kind": {
+			metadata: `{"name": "cluster", "ownerReferences": [{"apiVersion": "example.com/v1", "kind": "RayJob", "name": "job", "uid": "uid", "controller": true}]}`,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			c := makeRayCluster(t, tc.metadata, testClusterSpec)
+			if got := c.Skip(); got != tc.wantSkip {
+				t.Errorf("Skip() = %t, want %t", got, tc.wantSkip)
+			}
+		})
+	}
+}
+
+func TestRayClusterValidateCreate(t *testing.T) {
+	testcases := map[string]struct {
+		spec     string
+		wantErrs field.ErrorList
+	}{
+		"valid": {
+			spec: testClusterSpec,
+		},
+		"too many worker groups": {
+			spec: `{"headGroupSpec": {"template": {}}, "workerGroupSpecs": [{"template": {}}, {"template": {}}, {"template": {}}, {"template": {}}, {"template": {}}, {"template": {}}, {"template": {}}, {"template": {}}]}`,
+			wantErrs: field.ErrorList{
+				field.TooMany(field.NewPath("spec", "workerGroupSpecs"), 8, maxWorkerGroups),
+			},
+		},
+		"missing head template": {
+			spec: `{"headGroupSpec": {}}`,
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec"), "", ""),
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			c := makeRayCluster(t, `{"name": "cluster"}`, tc.spec)
+			if diff := cmp.Diff(tc.wantErrs, c.ValidateCreate(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("Unexpected errors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
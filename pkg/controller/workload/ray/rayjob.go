@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ray
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+// RayJobFrameworkName is the name of the integration of RayJobs in the
+// configuration.
+const RayJobFrameworkName = "ray.io/rayjob"
+
+// +kubebuilder:webhook:path=/mutate-ray-io-v1-rayjob,mutating=true,failurePolicy=fail,sideEffects=None,groups=ray.io,resources=rayjobs,verbs=create;update,versions=v1,name=mrayjob.kb.io,admissionReviewVersions=v1
+
+// SetupRayJobWebhook configures the webhook for RayJobs.
+func SetupRayJobWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
+	return jobframework.SetupWebhook(mgr, "/mutate-ray-io-v1-rayjob", NewRayJob, opts...)
+}
+
+// RayJob is a ray.io/v1 RayJob. Its pod sets are the ones of the RayCluster
+// that it creates.
+type RayJob struct {
+	u unstructured.Unstructured
+}
+
+var (
+	_ jobframework.GenericJob        = &RayJob{}
+	_ jobframework.JobWithValidation = &RayJob{}
+
+	rayClusterSpecPath = field.NewPath("spec", "rayClusterSpec")
+)
+
+// NewRayJob returns an empty RayJob.
+func NewRayJob() jobframework.GenericJob {
+	j := &RayJob{}
+	j.u.SetGroupVersionKind(RayJobGVK)
+	return j
+}
+
+func (j *RayJob) Object() client.Object {
+	return &j.u
+}
+
+func (j *RayJob) GVK() schema.GroupVersionKind {
+	return RayJobGVK
+}
+
+func (j *RayJob) IsSuspended() bool {
+	suspend, _, _ := unstructured.NestedBool(j.u.Object, "spec", "suspend")
+	return suspend
+}
+
+func (j *RayJob) Suspend() error {
+	return unstructured.SetNestedField(j.u.Object, true, "spec", "suspend")
+}
+
+func (j *RayJob) RunWithPodSetsInfo(infos []jobframework.PodSetInfo) error {
+	if err := runCluster(j.clusterSpec(), infos); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(j.u.Object, false, "spec", "suspend")
+}
+
+func (j *RayJob) RestorePodSetsInfo(infos []jobframework.PodSetInfo) (bool, error) {
+	return restoreCluster(j.clusterSpec(), infos)
+}
+
+// Finished returns whether the Ray job reached a terminal status.
+func (j *RayJob) Finished() (metav1.Condition, bool) {
+	status, _, _ := unstructured.NestedString(j.u.Object, "status", "jobStatus")
+	message, _, _ := unstructured.NestedString(j.u.Object, "status", "message")
+	condition := metav1.Condition{
+		Type:    kueue.WorkloadFinished,
+		Status:  metav1.ConditionTrue,
+		Reason:  "JobFinished",
+		Message: message,
+	}
+	switch status {
+	case "SUCCEEDED":
+		if condition.Message == "" {
+			condition.Message = "Job finished successfully"
+		}
+	case "FAILED", "STOPPED":
+		if condition.Message == "" {
+			condition.Message = "Job failed"
+		}
+	default:
+		return metav1.Condition{}, false
+	}
+	return condition, true
+}
+
+func (j *RayJob) PodSets() ([]kueue.PodSet, error) {
+	return clusterPodSets(j.clusterSpec())
+}
+
+// IsActive returns whether the RayCluster of the job may have pods, which is
+// the case from its initialization until the job is suspended or finishes.
+func (j *RayJob) IsActive() bool {
+	status, _, _ := unstructured.NestedString(j.u.Object, "status", "jobDeploymentStatus")
+	switch status {
+	case "Initializing", "Running", "Suspending":
+		return true
+	}
+	return false
+}
+
+func (j *RayJob) PodsReady() bool {
+	state, _, _ := unstructured.NestedString(j.u.Object, "status", "rayClusterStatus", "state")
+	return state == "ready"
+}
+
+func (j *RayJob) EquivalentToWorkload(wl *kueue.Workload) bool {
+	podSets, err := j.PodSets()
+	return err == nil && jobframework.PodSetsEquivalent(wl.Spec.PodSets, podSets)
+}
+
+// ValidateCreate requires the job to create its own RayCluster, and to delete
+// it when it finishes, so that the quota is released.
+func (j *RayJob) ValidateCreate() field.ErrorList {
+	var allErrs field.ErrorList
+	if selector, _, _ := unstructured.NestedStringMap(j.u.Object, "spec", "clusterSelector"); len(selector) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "clusterSelector"), "a RayJob using an existing RayCluster can't be managed by Kueue"))
+	}
+	if shutdown, _, _ := unstructured.NestedBool(j.u.Object, "spec", "shutdownAfterJobFinishes"); !shutdown {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "shutdownAfterJobFinishes"), shutdown, "should be true when the job is managed by Kueue"))
+	}
+	return append(allErrs, validateCluster(j.clusterSpec(), rayClusterSpecPath)...)
+}
+
+func (j *RayJob) clusterSpec() map[string]interface{} {
+	spec, _, _ := unstructured.NestedFieldNoCopy(j.u.Object, "spec", "rayClusterSpec")
+	m, _ := spec.(map[string]interface{})
+	return m
+}
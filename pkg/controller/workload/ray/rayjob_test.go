@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ray
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+const testClusterSpec = `{
+	"headGroupSpec": {"template": {"spec": {"containers": [{"name": "head", "resources": {"requests": {"cpu": "1"}}}]}}},
+	"workerGroupSpecs": [
+		{"groupName": "small", "replicas": 2, "template": {"spec": {"containers": [{"name": "worker", "resources": {"requests": {"cpu": "2"}}}]}}},
+		{"groupName": "multi-host", "replicas": 2, "numOfHosts": 4, "template": {"metadata": {"annotations": {"kueue.x-k8s.io/podset-required-topology": "rack"}}, "spec": {"containers": [{"name": "worker"}]}}}
+	]
+}`
+
+func makeRayJob(t *testing.T, spec string) *RayJob {
+	t.Helper()
+	j := NewRayJob().(*RayJob)
+	if err := json.Unmarshal([]byte(`{"apiVersion": "ray.io/v1", "kind": "RayJob", "metadata": {"name": "job", "namespace": "ns"}, "spec": `+spec+`}`), j.Object()); err != nil {
+		t.Fatalf("Invalid RayJob: %v", err)
+	}
+	return j
+}
+
+func TestRayJobPodSets(t *testing.T) {
+	j := makeRayJob(t, `{"rayClusterSpec": `+testClusterSpec+`}`)
+	got, err := j.PodSets()
+	if err != nil {
+		t.Fatalf("PodSets() returned error: %v", err)
+	}
+	rack := "rack"
+	want := []kueue.PodSet{
+		{
+			Name:  "head",
+			Count: 1,
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:      "head",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+			}}},
+		},
+		{
+			Name:  "small",
+			Count: 2,
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:      "worker",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+			}}},
+		},
+		{
+			Name:            "multi-host",
+			Count:           8,
+			Spec:            corev1.PodSpec{Containers: []corev1.Container{{Name: "worker"}}},
+			TopologyRequest: &kueue.PodSetTopologyRequest{Required: &rack},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Unexpected pod sets (-want,+got):\n%s", diff)
+	}
+}
+
+func TestRayJobRunAndRestore(t *testing.T) {
+	j := makeRayJob(t, `{"suspend": true, "rayClusterSpec": `+testClusterSpec+`}`)
+	infos := []jobframework.PodSetInfo{
+		{Name: "head", NodeSelector: map[string]string{"instance": "on-demand"}},
+		{Name: "small", NodeSelector: map[string]string{"instance": "spot"}, Labels: map[string]string{"l": "v"}},
+		{Name: "multi-host", NodeSelector: map[string]string{"instance": "spot", "rack": "r1"}},
+	}
+	if err := j.RunWithPodSetsInfo(infos); err != nil {
+		t.Fatalf("RunWithPodSetsInfo() returned error: %v", err)
+	}
+	if j.IsSuspended() {
+		t.Errorf("The job is still suspended")
+	}
+	podSets, err := j.PodSets()
+	if err != nil {
+		t.Fatalf("PodSets() returned error: %v", err)
+	}
+	for i := range podSets {
+		if diff := cmp.Diff(infos[i].NodeSelector, podSets[i].Spec.NodeSelector); diff != "" {
+			t.Errorf("Unexpected node selector of pod set %s (-want,+got):\n%s", podSets[i].Name, diff)
+		}
+	}
+
+	if err := j.Suspend(); err != nil {
+		t.Fatalf("Suspend() returned error: %v", err)
+	}
+	changed, err := j.RestorePodSetsInfo(make([]jobframework.PodSetInfo, 3))
+	if err != nil {
+		t.Fatalf("RestorePodSetsInfo() returned error: %v", err)
+	}
+	if !changed {
+		t.Errorf("RestorePodSetsInfo() didn't change the job")
+	}
+	if !j.IsSuspended() {
+		t.Errorf("The job isn't suspended")
+	}
+	if podSets, err = j.PodSets(); err != nil {
+		t.Fatalf("PodSets() returned error: %v", err)
+	}
+	for _, ps := range podSets {
+		if len(ps.Spec.NodeSelector) != 0 {
+			t.Errorf("Pod set %s has node selector %v after restoring", ps.Name, ps.Spec.NodeSelector)
+		}
+	}
+}
+
+func TestRayJobFinished(t *testing.T) {
+	testcases := map[string]struct {
+		status        string
+		wantFinished  bool
+		wantCondition metav1.Condition
+	}{
+		"running": {
+			status: `{"jobStatus": "RUNNING", "jobDeploymentStatus": "Running"}`,
+		},
+		"succeeded": {
+			status:       `{"jobStatus": "SUCCEEDED", "jobDeploymentStatus": "Complete"}`,
+			wantFinished: true,
+			wantCondition: metav1.Condition{
+				Type:    kueue.WorkloadFinished,
+				Status:  metav1.ConditionTrue,
+				Reason:  "JobFinished",
+				Message: "Job finished successfully",
+			},
+		},
+		"failed with message": {
+			status:       `{"jobStatus": "FAILED", "jobDeploymentStatus": "Failed", "message": "entrypoint failed"}`,
+			wantFinished: true,
+			wantCondition: metav1.Condition{
+				Type:    kueue.WorkloadFinished,
+				Status:  metav1.ConditionTrue,
+				Reason:  "JobFinished",
+				Message: "entrypoint failed",
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			j := NewRayJob().(*RayJob)
+			if err := json.Unmarshal([]byte(`{"apiVersion": "ray.io/v1", "kind": "RayJob", "metadata": {"name": "job"}, "status": `+tc.status+`}`), j.Object()); err != nil {
+				t.Fatalf("Invalid RayJob: %v", err)
+			}
+			condition, finished := j.Finished()
+			if finished != tc.wantFinished {
+				t.Errorf("Finished() = %t, want %t", finished, tc.wantFinished)
+			}
+			if diff := cmp.Diff(tc.wantCondition, condition); diff != "" {
+				t.Errorf("Unexpected condition (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRayJobValidateCreate(t *testing.T) {
+	testcases := map[string]struct {
+		spec     string
+		wantErrs field.ErrorList
+	}{
+		"valid": {
+			spec: `{"shutdownAfterJobFinishes": true, "rayClusterSpec": ` + testClusterSpec + `}`,
+		},
+		"existing cluster": {
+			spec: `{"shutdownAfterJobFinishes": true, "clusterSelector": {"ray.io/cluster": "cluster"}}`,
+			wantErrs: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "clusterSelector"), ""),
+				field.Required(field.NewPath("spec", "rayClusterSpec"), ""),
+			},
+		},
+		"cluster not deleted": {
+			spec: `{"rayClusterSpec": ` + testClusterSpec + `}`,
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "shutdownAfterJobFinishes"), false, ""),
+			},
+		},
+		"autoscaling": {
+			spec: `{"shutdownAfterJobFinishes": true, "rayClusterSpec": {"enableInTreeAutoscaling": true, "headGroupSpec": {"template": {"spec": {}}}}}`,
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "rayClusterSpec", "enableInTreeAutoscaling"), true, ""),
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			j := makeRayJob(t, tc.spec)
+			if diff := cmp.Diff(tc.wantErrs, j.ValidateCreate(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("Unexpected errors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/controller/workload/pod"
+)
+
+// StatefulSetWebhook turns the Pods of the StatefulSets that set the queue
+// name annotation into a Pod group, so that all the replicas are admitted
+// together with a single Workload by the Pod integration. The StatefulSets
+// are handled as raw JSON, so that the fields that are unknown to this
+// version of the Kubernetes API are preserved.
+type StatefulSetWebhook struct {
+	enabled bool
+}
+
+type options struct {
+	enabled bool
+}
+
+// Option configures the webhook.
+type Option func(*options)
+
+// WithEnabled indicates if the webhook should manage the StatefulSets that
+// set the queue name annotation. It requires the Pod integration.
+func WithEnabled(f bool) Option {
+	return func(o *options) {
+		o.enabled = f
+	}
+}
+
+var defaultOptions = options{}
+
+// SetupWebhook configures the webhook for StatefulSets.
+func SetupWebhook(mgr ctrl.Manager, opts ...Option) error {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	mgr.GetWebhookServer().Register("/mutate-apps-v1-statefulset", &webhook.Admission{
+		Handler: &StatefulSetWebhook{enabled: options.enabled},
+	})
+	return nil
+}
+
+// +kubebuilder:webhook:path=/mutate-apps-v1-statefulset,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps,resources=statefulsets,verbs=create;update,versions=v1,name=mstatefulset.kb.io,admissionReviewVersions=v1
+
+var _ admission.Handler = &StatefulSetWebhook{}
+
+var (
+	queueNameKeyPath = field.NewPath("metadata", "annotations").Key(constants.QueueAnnotation)
+	replicasPath     = field.NewPath("spec", "replicas")
+)
+
+// Handle sets the queue name, the WorkloadPriorityClass and the Pod group of
+// the pod template of the StatefulSets that set the queue name annotation. The Pods are created in
+// parallel, as they can't become ready until all of them are admitted.
+// The queue name and the replicas are immutable, since the Pod group is
+// admitted as a whole.
+func (w *StatefulSetWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !w.enabled {
+		return admission.Allowed("")
+	}
+	var sts appsv1.StatefulSet
+	if err := json.Unmarshal(req.Object.Raw, &sts); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update {
+		var oldSts appsv1.StatefulSet
+		if err := json.Unmarshal(req.OldObject.Raw, &oldSts); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if errs := validateUpdate(&sts, &oldSts); len(errs) > 0 {
+			return admission.Denied(errs.ToAggregate().Error())
+		}
+	}
+	queueName := sts.Annotations[constants.QueueAnnotation]
+	if queueName == "" {
+		return admission.Allowed("")
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("statefulset-webhook")
+	log.V(5).Info("Applying defaults", "statefulset", klog.KObj(&sts))
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	if err := setPodGroup(u, queueName, sts.Name, replicas(&sts)); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if priorityClass := sts.Labels[constants.WorkloadPriorityClassLabel]; priorityClass != "" {
+		if err := unstructured.SetNestedField(u.Object, priorityClass, "spec", "template", "metadata", "labels", constants.WorkloadPriorityClassLabel); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	if req.Operation == admissionv1.Create {
+		// The policy is immutable, so it's only set on creation.
+		if err := unstructured.SetNestedField(u.Object, string(appsv1.ParallelPodManagement), "spec", "podManagementPolicy"); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	marshaled, err := json.Marshal(u.Object)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+func validateUpdate(sts, oldSts *appsv1.StatefulSet) field.ErrorList {
+	var allErrs field.ErrorList
+	oldQueueName := oldSts.Annotations[constants.QueueAnnotation]
+	queueName := sts.Annotations[constants.QueueAnnotation]
+	if queueName != oldQueueName {
+		allErrs = append(allErrs, field.Forbidden(queueNameKeyPath, "is immutable"))
+	}
+	if queueName != "" && replicas(sts) != replicas(oldSts) {
+		allErrs = append(allErrs, field.Forbidden(replicasPath, "can't be changed when the StatefulSet is managed by Kueue"))
+	}
+	return allErrs
+}
+
+// setPodGroup sets the queue name and the Pod group in the pod template.
+func setPodGroup(u *unstructured.Unstructured, queueName, groupName string, count int32) error {
+	if err := unstructured.SetNestedField(u.Object, queueName, "spec", "template", "metadata", "annotations", constants.QueueAnnotation); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(u.Object, strconv.Itoa(int(count)), "spec", "template", "metadata", "annotations", pod.GroupTotalCountAnnotation); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(u.Object, groupName, "spec", "template", "metadata", "labels", pod.GroupNameLabel)
+}
+
+func replicas(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.Replicas == nil {
+		return 1
+	}
+	return *sts.Spec.Replicas
+}
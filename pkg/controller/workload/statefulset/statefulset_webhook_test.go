@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestHandle(t *testing.T) {
+	testcases := map[string]struct {
+		enabled     bool
+		operation   admissionv1.Operation
+		sts         string
+		oldSts      string
+		wantAllowed bool
+		wantPatches []jsonpatch.JsonPatchOperation
+	}{
+		"integration disabled": {
+			operation:   admissionv1.Create,
+			sts:         `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+			wantAllowed: true,
+		},
+		"statefulset without queue name": {
+			enabled:     true,
+			operation:   admissionv1.Create,
+			sts:         `{"metadata":{"name":"sts"},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+			wantAllowed: true,
+		},
+		"create statefulset with queue name": {
+			enabled:     true,
+			operation:   admissionv1.Create,
+			sts:         `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"replicas":3,"podManagementPolicy":"OrderedReady","template":{"spec":{}}}}`,
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("replace", "/spec/podManagementPolicy", "Parallel"),
+				jsonpatch.NewOperation("add", "/spec/template/metadata", map[string]interface{}{
+					"annotations": map[string]interface{}{
+						"kueue.x-k8s.io/queue-name":            "queue",
+						"kueue.x-k8s.io/pod-group-total-count": "3",
+					},
+					"labels": map[string]interface{}{"kueue.x-k8s.io/pod-group-name": "sts"},
+				}),
+			},
+		},
+		"create statefulset with queue name and priority class": {
+			enabled:     true,
+			operation:   admissionv1.Create,
+			sts:         `{"metadata":{"name":"sts","labels":{"kueue.x-k8s.io/priority-class":"high"},"annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"replicas":3,"podManagementPolicy":"Parallel","template":{"spec":{}}}}`,
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				jsonpatch.NewOperation("add", "/spec/template/metadata", map[string]interface{}{
+					"annotations": map[string]interface{}{
+						"kueue.x-k8s.io/queue-name":            "queue",
+						"kueue.x-k8s.io/pod-group-total-count": "3",
+					},
+					"labels": map[string]interface{}{
+						"kueue.x-k8s.io/pod-group-name": "sts",
+						"kueue.x-k8s.io/priority-class": "high",
+					},
+				}),
+			},
+		},
+		"update statefulset with queue name": {
+			enabled:     true,
+			operation:   admissionv1.Update,
+			sts:         `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"template":{"metadata":{"labels":{"kueue.x-k8s.io/pod-group-name":"sts"},"annotations":{"kueue.x-k8s.io/queue-name":"queue","kueue.x-k8s.io/pod-group-total-count":"1"}},"spec":{"containers":[{"name":"c","image":"new"}]}}}}`,
+			oldSts:      `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"template":{"metadata":{"labels":{"kueue.x-k8s.io/pod-group-name":"sts"},"annotations":{"kueue.x-k8s.io/queue-name":"queue","kueue.x-k8s.io/pod-group-total-count":"1"}},"spec":{"containers":[{"name":"c","image":"old"}]}}}}`,
+			wantAllowed: true,
+		},
+		"change queue name": {
+			enabled:   true,
+			operation: admissionv1.Update,
+			sts:       `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"other"}},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+			oldSts:    `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+		},
+		"add queue name": {
+			enabled:   true,
+			operation: admissionv1.Update,
+			sts:       `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+			oldSts:    `{"metadata":{"name":"sts"},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+		},
+		"scale statefulset with queue name": {
+			enabled:   true,
+			operation: admissionv1.Update,
+			sts:       `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"replicas":4,"template":{"spec":{}}}}`,
+			oldSts:    `{"metadata":{"name":"sts","annotations":{"kueue.x-k8s.io/queue-name":"queue"}},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+		},
+		"scale statefulset without queue name": {
+			enabled:     true,
+			operation:   admissionv1.Update,
+			sts:         `{"metadata":{"name":"sts"},"spec":{"replicas":4,"template":{"spec":{}}}}`,
+			oldSts:      `{"metadata":{"name":"sts"},"spec":{"replicas":3,"template":{"spec":{}}}}`,
+			wantAllowed: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			w := &StatefulSetWebhook{enabled: tc.enabled}
+			resp := w.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Object:    runtime.RawExtension{Raw: []byte(tc.sts)},
+				OldObject: runtime.RawExtension{Raw: []byte(tc.oldSts)},
+			}})
+			if resp.Allowed != tc.wantAllowed {
+				t.Errorf("Handle() allowed = %t, want %t", resp.Allowed, tc.wantAllowed)
+			}
+			if diff := cmp.Diff(tc.wantPatches, resp.Patches, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(a, b jsonpatch.JsonPatchOperation) bool {
+				return a.Path < b.Path
+			})); diff != "" {
+				t.Errorf("Unexpected patches (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
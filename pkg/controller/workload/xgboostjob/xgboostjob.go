@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xgboostjob integrates the XGBoostJobs of the Kubeflow training operator with Kueue.
+package xgboostjob
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
+)
+
+// FrameworkName is the name of the integration in the configuration.
+const FrameworkName = "kubeflow.org/xgboostjob"
+
+var GVK = schema.GroupVersionKind{Group: "kubeflow.org", Version: "v1", Kind: "XGBoostJob"}
+
+func init() {
+	if err := jobframework.RegisterIntegration(FrameworkName, jobframework.IntegrationCallbacks{
+		NewJob:       NewXGBoostJob,
+		SetupWebhook: SetupWebhook,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+//+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloadpriorityclasses,verbs=list;get;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;watch;update
+//+kubebuilder:rbac:groups=kubeflow.org,resources=xgboostjobs,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=kubeflow.org,resources=xgboostjobs/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=resourceflavors,verbs=get;list;watch
+
+// +kubebuilder:webhook:path=/mutate-kubeflow-org-v1-xgboostjob,mutating=true,failurePolicy=fail,sideEffects=None,groups=kubeflow.org,resources=xgboostjobs,verbs=create;update,versions=v1,name=mxgboostjob.kb.io,admissionReviewVersions=v1
+
+// SetupWebhook configures the webhook for XGBoostJobs.
+func SetupWebhook(mgr ctrl.Manager, opts ...jobframework.Option) error {
+	return jobframework.SetupWebhook(mgr, "/mutate-kubeflow-org-v1-xgboostjob", NewXGBoostJob, opts...)
+}
+
+// NewXGBoostJob returns an empty XGBoostJob, with a pod set for the master and
+// one for the workers.
+func NewXGBoostJob() jobframework.GenericJob {
+	return jobframework.NewKubeflowJob(GVK, "xgbReplicaSpecs", []string{"Master", "Worker"})
+}
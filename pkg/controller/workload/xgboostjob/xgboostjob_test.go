@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xgboostjob
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+)
+
+func TestPodSets(t *testing.T) {
+	j := NewXGBoostJob()
+	raw := `{
+	"apiVersion": "kubeflow.org/v1",
+	"kind": "XGBoostJob",
+	"metadata": {"name": "job", "namespace": "ns"},
+	"spec": {"runPolicy": {"suspend": true}, "xgbReplicaSpecs": {
+		"Worker": {"replicas": 2, "template": {"spec": {"containers": [{"name": "worker"}]}}},
+		"Master": {"replicas": 1, "template": {"spec": {"containers": [{"name": "master"}]}}}
+	}}
+}`
+	if err := json.Unmarshal([]byte(raw), j.Object()); err != nil {
+		t.Fatalf("Invalid XGBoostJob: %v", err)
+	}
+	if !j.IsSuspended() {
+		t.Errorf("The job isn't suspended")
+	}
+	got, err := j.PodSets()
+	if err != nil {
+		t.Fatalf("PodSets() returned error: %v", err)
+	}
+	want := []kueue.PodSet{
+		{
+			Name:  "master",
+			Count: 1,
+			Spec:  corev1.PodSpec{Containers: []corev1.Container{{Name: "master"}}},
+		},
+		{
+			Name:  "worker",
+			Count: 2,
+			Spec:  corev1.PodSpec{Containers: []corev1.Container{{Name: "worker"}}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected pod sets (-want,+got):\n%s", diff)
+	}
+}
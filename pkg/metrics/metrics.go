@@ -28,11 +28,29 @@ import (
 
 type AdmissionResult string
 type ClusterQueueStatus string
+type PriorityBucket string
+type SchedulingPhase string
+type SchedulingResult string
 
 const (
 	AdmissionResultSuccess      AdmissionResult = "success"
 	AdmissionResultInadmissible AdmissionResult = "inadmissible"
 
+	// SchedulingPhaseSnapshot is the phase of a scheduling cycle that takes a
+	// snapshot of the cache.
+	SchedulingPhaseSnapshot SchedulingPhase = "snapshot"
+	// SchedulingPhaseNomination is the phase of a scheduling cycle that assigns
+	// flavors to the workloads.
+	SchedulingPhaseNomination SchedulingPhase = "nomination"
+	// SchedulingPhaseAdmission is the phase of a scheduling cycle that preempts
+	// or admits the nominated workloads.
+	SchedulingPhaseAdmission SchedulingPhase = "admission"
+
+	SchedulingResultAdmitted     SchedulingResult = "admitted"
+	SchedulingResultSkipped      SchedulingResult = "skipped"
+	SchedulingResultPreempting   SchedulingResult = "preempting"
+	SchedulingResultInadmissible SchedulingResult = "inadmissible"
+
 	PendingStatusActive       = "active"
 	PendingStatusInadmissible = "inadmissible"
 
@@ -46,8 +64,28 @@ const (
 	CQStatusActive ClusterQueueStatus = "active"
 	// CQStatusTerminating means the clusterQueue is in pending deletion.
 	CQStatusTerminating ClusterQueueStatus = "terminating"
+
+	// PriorityBucketLow is the bucket of the workloads with a negative priority.
+	PriorityBucketLow PriorityBucket = "low"
+	// PriorityBucketDefault is the bucket of the workloads with a priority of
+	// zero, which is the priority of the workloads without a priority class.
+	PriorityBucketDefault PriorityBucket = "default"
+	// PriorityBucketHigh is the bucket of the workloads with a positive
+	// priority, below the priorities reserved for the system.
+	PriorityBucketHigh PriorityBucket = "high"
+	// PriorityBucketSystem is the bucket of the workloads with a priority
+	// reserved for the system, such as system-cluster-critical.
+	PriorityBucketSystem PriorityBucket = "system"
+
+	// highestUserDefinablePriority is the highest priority that a
+	// PriorityClass can have without being reserved for the system.
+	highestUserDefinablePriority = 1000000000
 )
 
+// waitTimeBuckets range from 1 second to about 9 hours, as workloads can be
+// queued for long periods.
+var waitTimeBuckets = prometheus.ExponentialBuckets(1, 2, 16)
+
 var (
 	CQStatuses = []ClusterQueueStatus{CQStatusPending, CQStatusActive, CQStatusTerminating}
 
@@ -74,6 +112,40 @@ The label 'result' can have the following values:
 		}, []string{"result"},
 	)
 
+	schedulingPhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: constants.KueueName,
+			Name:      "scheduling_phase_duration_seconds",
+			Help: `The latency of each phase of a scheduling cycle.
+The label 'phase' can have the following values:
+- 'snapshot' means taking a snapshot of the cache.
+- 'nomination' means assigning flavors to the workloads.
+- 'admission' means preempting or admitting the nominated workloads.`,
+		}, []string{"phase"},
+	)
+
+	workloadSchedulingDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: constants.KueueName,
+			Name:      "workload_scheduling_duration_seconds",
+			Help: `The latency of the nomination and admission phases for a workload in a scheduling cycle, per 'cluster_queue' and 'phase'.
+The label 'phase' can have the values 'nomination' or 'admission'.`,
+		}, []string{"cluster_queue", "phase"},
+	)
+
+	ScheduledWorkloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "scheduled_workloads_total",
+			Help: `The total number of workloads evaluated in scheduling cycles, per 'cluster_queue' and 'result'.
+The label 'result' can have the following values:
+- 'admitted' means that the workload was admitted.
+- 'skipped' means that the workload was skipped because other workloads in the cohort were prioritized.
+- 'preempting' means that the workload is waiting for preempted workloads to release their quota.
+- 'inadmissible' means that the workload doesn't fit.`,
+		}, []string{"cluster_queue", "result"},
+	)
+
 	// Metrics tied to the queue system.
 
 	PendingWorkloads = prometheus.NewGaugeVec(
@@ -87,11 +159,27 @@ The label 'result' can have the following values:
 		}, []string{"cluster_queue", "status"},
 	)
 
+	QuotaReservedWorkloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "quota_reserved_workloads_total",
+			Help:      "The total number of workloads that reserved quota, per 'cluster_queue'",
+		}, []string{"cluster_queue"},
+	)
+
 	AdmittedWorkloadsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Subsystem: constants.KueueName,
 			Name:      "admitted_workloads_total",
-			Help:      "The total number of admitted workloads per 'cluster_queue'",
+			Help:      "The total number of admitted workloads, that is, workloads that reserved quota and passed their admission checks, per 'cluster_queue'",
+		}, []string{"cluster_queue"},
+	)
+
+	RequeuedWorkloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "requeued_workloads_total",
+			Help:      "The total number of times that workloads were requeued with a backoff after failing admission, per 'cluster_queue'",
 		}, []string{"cluster_queue"},
 	)
 
@@ -103,8 +191,46 @@ The label 'result' can have the following values:
 		}, []string{"cluster_queue"},
 	)
 
+	quotaReservedWaitTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: constants.KueueName,
+			Name:      "workload_quota_reserved_wait_time_seconds",
+			Help: `The time between a Workload was created until it got the quota reserved, per 'cluster_queue' and 'priority'.
+The label 'priority' can have the values 'low', 'default', 'high' or 'system'.`,
+			Buckets: waitTimeBuckets,
+		}, []string{"cluster_queue", "priority"},
+	)
+
+	admittedWaitTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: constants.KueueName,
+			Name:      "workload_admitted_wait_time_seconds",
+			Help: `The time between a Workload was created until it was admitted, including the time waiting for its admission checks, per 'cluster_queue' and 'priority'.
+The label 'priority' can have the values 'low', 'default', 'high' or 'system'.`,
+			Buckets: waitTimeBuckets,
+		}, []string{"cluster_queue", "priority"},
+	)
+
+	readyWaitTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: constants.KueueName,
+			Name:      "workload_ready_wait_time_seconds",
+			Help: `The time between a Workload was admitted until all its pods were ready, per 'cluster_queue' and 'priority'.
+The label 'priority' can have the values 'low', 'default', 'high' or 'system'.`,
+			Buckets: waitTimeBuckets,
+		}, []string{"cluster_queue", "priority"},
+	)
+
 	// Metrics tied to the cache.
 
+	ReservingActiveWorkloads = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "reserving_active_workloads",
+			Help:      "The number of Workloads that reserve quota and are active (unsuspended and not finished), per 'cluster_queue'",
+		}, []string{"cluster_queue"},
+	)
+
 	AdmittedActiveWorkloads = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: constants.KueueName,
@@ -113,6 +239,27 @@ The label 'result' can have the following values:
 		}, []string{"cluster_queue"},
 	)
 
+	CacheInconsistenciesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cache_inconsistencies_total",
+			Help: `The total number of inconsistencies of the cache with the Workloads in the cluster that were detected and repaired, per 'cluster_queue' and 'kind'.
+The label 'kind' can have the following values:
+- 'missing' means that an admitted workload was not in the cache.
+- 'unexpected' means that the cache held a workload that was not admitted.
+- 'stale' means that the cache held outdated requests of an admitted workload.
+- 'usage' means that the usage of the ClusterQueue didn't add up to the requests of its workloads.`,
+		}, []string{"cluster_queue", "kind"},
+	)
+
+	AdmissionApplyConflictsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: constants.KueueName,
+			Name:      "admission_apply_conflicts_total",
+			Help:      "The total number of admissions that failed to be applied because fields of the Workload are owned by another 'field_manager'",
+		}, []string{"field_manager"},
+	)
+
 	ClusterQueueByStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Subsystem: constants.KueueName,
@@ -121,6 +268,39 @@ The label 'result' can have the following values:
 For a ClusterQueue, the metric only reports a value of 1 for one of the statuses.`,
 		}, []string{"cluster_queue", "status"},
 	)
+
+	ClusterQueueResourceUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_resource_usage",
+			Help:      "Reports the quota of each 'resource' and 'flavor' that is used by the Workloads admitted by the 'cluster_queue'",
+		}, []string{"cluster_queue", "flavor", "resource"},
+	)
+
+	ClusterQueueResourceBorrowing = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_resource_borrowing",
+			Help:      "Reports the quota of each 'resource' and 'flavor' that the 'cluster_queue' is borrowing from its cohort, that is, the usage above the nominal quota",
+		}, []string{"cluster_queue", "flavor", "resource"},
+	)
+
+	ClusterQueueNominalQuota = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_nominal_quota",
+			Help:      "Reports the nominal (min) quota of each 'resource' and 'flavor' of the 'cluster_queue'",
+		}, []string{"cluster_queue", "flavor", "resource"},
+	)
+
+	ClusterQueueBorrowingLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: constants.KueueName,
+			Name:      "cluster_queue_borrowing_limit",
+			Help: `Reports the maximum quota of each 'resource' and 'flavor' that the 'cluster_queue' can borrow from its cohort.
+Only reported when the max quota or the borrowing limit of the flavor is set.`,
+		}, []string{"cluster_queue", "flavor", "resource"},
+	)
 )
 
 func AdmissionAttempt(result AdmissionResult, duration time.Duration) {
@@ -128,11 +308,58 @@ func AdmissionAttempt(result AdmissionResult, duration time.Duration) {
 	admissionAttemptDuration.WithLabelValues(string(result)).Observe(duration.Seconds())
 }
 
+func SchedulingPhaseCompleted(phase SchedulingPhase, duration time.Duration) {
+	schedulingPhaseDuration.WithLabelValues(string(phase)).Observe(duration.Seconds())
+}
+
+func WorkloadSchedulingPhaseCompleted(cqName string, phase SchedulingPhase, duration time.Duration) {
+	workloadSchedulingDuration.WithLabelValues(cqName, string(phase)).Observe(duration.Seconds())
+}
+
+func WorkloadScheduled(cqName string, result SchedulingResult) {
+	ScheduledWorkloadsTotal.WithLabelValues(cqName, string(result)).Inc()
+}
+
+func QuotaReservedWorkload(cqName kueue.ClusterQueueReference) {
+	QuotaReservedWorkloadsTotal.WithLabelValues(string(cqName)).Inc()
+}
+
 func AdmittedWorkload(cqName kueue.ClusterQueueReference, waitTime time.Duration) {
 	AdmittedWorkloadsTotal.WithLabelValues(string(cqName)).Inc()
 	admissionWaitTime.WithLabelValues(string(cqName)).Observe(waitTime.Seconds())
 }
 
+// PriorityBucketFor returns the bucket of a workload priority, to keep the
+// cardinality of the metrics bounded.
+func PriorityBucketFor(priority int32) PriorityBucket {
+	switch {
+	case priority < 0:
+		return PriorityBucketLow
+	case priority == 0:
+		return PriorityBucketDefault
+	case priority <= highestUserDefinablePriority:
+		return PriorityBucketHigh
+	default:
+		return PriorityBucketSystem
+	}
+}
+
+func QuotaReservedWorkloadWaitTime(cqName string, priority PriorityBucket, waitTime time.Duration) {
+	quotaReservedWaitTime.WithLabelValues(cqName, string(priority)).Observe(waitTime.Seconds())
+}
+
+func AdmittedWorkloadWaitTime(cqName string, priority PriorityBucket, waitTime time.Duration) {
+	admittedWaitTime.WithLabelValues(cqName, string(priority)).Observe(waitTime.Seconds())
+}
+
+func ReadyWorkloadWaitTime(cqName string, priority PriorityBucket, waitTime time.Duration) {
+	readyWaitTime.WithLabelValues(cqName, string(priority)).Observe(waitTime.Seconds())
+}
+
+func RequeuedWorkload(cqName string) {
+	RequeuedWorkloadsTotal.WithLabelValues(cqName).Inc()
+}
+
 func ReportPendingWorkloads(cqName string, active, inadmissible int) {
 	PendingWorkloads.WithLabelValues(cqName, PendingStatusActive).Set(float64(active))
 	PendingWorkloads.WithLabelValues(cqName, PendingStatusInadmissible).Set(float64(inadmissible))
@@ -141,8 +368,15 @@ func ReportPendingWorkloads(cqName string, active, inadmissible int) {
 func ClearQueueSystemMetrics(cqName string) {
 	PendingWorkloads.DeleteLabelValues(cqName, PendingStatusActive)
 	PendingWorkloads.DeleteLabelValues(cqName, PendingStatusInadmissible)
+	QuotaReservedWorkloadsTotal.DeleteLabelValues(cqName)
 	AdmittedWorkloadsTotal.DeleteLabelValues(cqName)
+	RequeuedWorkloadsTotal.DeleteLabelValues(cqName)
 	admissionWaitTime.DeleteLabelValues(cqName)
+	quotaReservedWaitTime.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
+	admittedWaitTime.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
+	readyWaitTime.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
+	workloadSchedulingDuration.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
+	ScheduledWorkloadsTotal.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
 }
 
 func ReportClusterQueueStatus(cqName string, cqStatus ClusterQueueStatus) {
@@ -155,20 +389,80 @@ func ReportClusterQueueStatus(cqName string, cqStatus ClusterQueueStatus) {
 	}
 }
 
+// ReportClusterQueueQuotas reports the nominal quota and, if not nil, the
+// borrowing limit of a resource flavor of the ClusterQueue.
+func ReportClusterQueueQuotas(cqName, flavor, resource string, nominal float64, borrowingLimit *float64) {
+	ClusterQueueNominalQuota.WithLabelValues(cqName, flavor, resource).Set(nominal)
+	if borrowingLimit != nil {
+		ClusterQueueBorrowingLimit.WithLabelValues(cqName, flavor, resource).Set(*borrowingLimit)
+	}
+}
+
+// ReportClusterQueueResourceUsage reports the usage of a resource flavor of
+// the ClusterQueue and the part of it that is borrowed from the cohort.
+func ReportClusterQueueResourceUsage(cqName, flavor, resource string, usage, borrowing float64) {
+	ClusterQueueResourceUsage.WithLabelValues(cqName, flavor, resource).Set(usage)
+	ClusterQueueResourceBorrowing.WithLabelValues(cqName, flavor, resource).Set(borrowing)
+}
+
+// ClearClusterQueueResourceMetrics removes the quota and usage of all the
+// resource flavors of the ClusterQueue.
+func ClearClusterQueueResourceMetrics(cqName string) {
+	lbls := prometheus.Labels{"cluster_queue": cqName}
+	ClusterQueueResourceUsage.DeletePartialMatch(lbls)
+	ClusterQueueResourceBorrowing.DeletePartialMatch(lbls)
+	ClusterQueueNominalQuota.DeletePartialMatch(lbls)
+	ClusterQueueBorrowingLimit.DeletePartialMatch(lbls)
+}
+
+// ReportActiveWorkloads reports the number of active workloads that reserve
+// quota in the ClusterQueue and the number of them that are admitted.
+func ReportActiveWorkloads(cqName string, reserving, admitted int) {
+	ReservingActiveWorkloads.WithLabelValues(cqName).Set(float64(reserving))
+	AdmittedActiveWorkloads.WithLabelValues(cqName).Set(float64(admitted))
+}
+
+func CacheInconsistencyRepaired(cqName, kind string) {
+	CacheInconsistenciesTotal.WithLabelValues(cqName, kind).Inc()
+}
+
+func AdmissionApplyConflict(fieldManager string) {
+	AdmissionApplyConflictsTotal.WithLabelValues(fieldManager).Inc()
+}
+
 func ClearCacheMetrics(cqName string) {
+	ReservingActiveWorkloads.DeleteLabelValues(cqName)
 	AdmittedActiveWorkloads.DeleteLabelValues(cqName)
+	CacheInconsistenciesTotal.DeletePartialMatch(prometheus.Labels{"cluster_queue": cqName})
 	for _, status := range CQStatuses {
 		ClusterQueueByStatus.DeleteLabelValues(cqName, string(status))
 	}
+	ClearClusterQueueResourceMetrics(cqName)
 }
 
 func Register() {
 	metrics.Registry.MustRegister(
 		admissionAttemptsTotal,
 		admissionAttemptDuration,
+		schedulingPhaseDuration,
+		workloadSchedulingDuration,
+		ScheduledWorkloadsTotal,
 		PendingWorkloads,
+		ReservingActiveWorkloads,
 		AdmittedActiveWorkloads,
+		CacheInconsistenciesTotal,
+		AdmissionApplyConflictsTotal,
+		QuotaReservedWorkloadsTotal,
 		AdmittedWorkloadsTotal,
+		RequeuedWorkloadsTotal,
 		admissionWaitTime,
+		quotaReservedWaitTime,
+		admittedWaitTime,
+		readyWaitTime,
+		ClusterQueueByStatus,
+		ClusterQueueResourceUsage,
+		ClusterQueueResourceBorrowing,
+		ClusterQueueNominalQuota,
+		ClusterQueueBorrowingLimit,
 	)
 }
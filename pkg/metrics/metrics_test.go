@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPriorityBucketFor(t *testing.T) {
+	cases := map[int32]PriorityBucket{
+		-10:        PriorityBucketLow,
+		0:          PriorityBucketDefault,
+		1:          PriorityBucketHigh,
+		1000000000: PriorityBucketHigh,
+		2000000000: PriorityBucketSystem,
+	}
+	for priority, want := range cases {
+		if got := PriorityBucketFor(priority); got != want {
+			t.Errorf("PriorityBucketFor(%d) = %s, want %s", priority, got, want)
+		}
+	}
+}
+
+func TestSchedulingMetrics(t *testing.T) {
+	SchedulingPhaseCompleted(SchedulingPhaseNomination, time.Second)
+	WorkloadSchedulingPhaseCompleted("scheduling-metrics", SchedulingPhaseNomination, time.Second)
+	WorkloadSchedulingPhaseCompleted("scheduling-metrics", SchedulingPhaseAdmission, time.Second)
+	WorkloadScheduled("scheduling-metrics", SchedulingResultAdmitted)
+	WorkloadScheduled("scheduling-metrics", SchedulingResultInadmissible)
+	WorkloadScheduled("scheduling-metrics", SchedulingResultInadmissible)
+	WorkloadScheduled("other", SchedulingResultSkipped)
+
+	if got := testutil.CollectAndCount(schedulingPhaseDuration, "kueue_scheduling_phase_duration_seconds"); got == 0 {
+		t.Error("No series for the scheduling phases")
+	}
+	if got := testutil.CollectAndCount(workloadSchedulingDuration, "kueue_workload_scheduling_duration_seconds"); got != 2 {
+		t.Errorf("Got %d series for the workload scheduling phases, want 2", got)
+	}
+	wantScheduled := map[SchedulingResult]float64{
+		SchedulingResultAdmitted:     1,
+		SchedulingResultSkipped:      0,
+		SchedulingResultPreempting:   0,
+		SchedulingResultInadmissible: 2,
+	}
+	for result, want := range wantScheduled {
+		if got := testutil.ToFloat64(ScheduledWorkloadsTotal.WithLabelValues("scheduling-metrics", string(result))); got != want {
+			t.Errorf("Got %v workloads scheduled with result %s, want %v", got, result, want)
+		}
+	}
+
+	ClearQueueSystemMetrics("scheduling-metrics")
+	if got := testutil.CollectAndCount(workloadSchedulingDuration, "kueue_workload_scheduling_duration_seconds"); got != 0 {
+		t.Errorf("Got %d series for the workload scheduling phases after clearing, want 0", got)
+	}
+	if got := testutil.CollectAndCount(ScheduledWorkloadsTotal, "kueue_scheduled_workloads_total"); got != 1 {
+		t.Errorf("Got %d series for the scheduled workloads after clearing, want 1", got)
+	}
+}
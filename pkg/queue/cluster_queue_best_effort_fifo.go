@@ -32,7 +32,7 @@ var _ ClusterQueue = &ClusterQueueBestEffortFIFO{}
 const BestEffortFIFO = kueue.BestEffortFIFO
 
 func newClusterQueueBestEffortFIFO(cq *kueue.ClusterQueue) (ClusterQueue, error) {
-	cqImpl := newClusterQueueImpl(keyFunc, byCreationTime)
+	cqImpl := newClusterQueueImpl(keyFunc, byPriority)
 	cqBE := &ClusterQueueBestEffortFIFO{
 		clusterQueueBase: cqImpl,
 	}
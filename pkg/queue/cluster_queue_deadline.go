@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// ClusterQueueEarliestDeadlineFirst is the implementation for the
+// ClusterQueue for EarliestDeadlineFirst.
+type ClusterQueueEarliestDeadlineFirst struct {
+	*clusterQueueBase
+}
+
+var _ ClusterQueue = &ClusterQueueEarliestDeadlineFirst{}
+
+const EarliestDeadlineFirst = kueue.EarliestDeadlineFirst
+
+func newClusterQueueEarliestDeadlineFirst(cq *kueue.ClusterQueue) (ClusterQueue, error) {
+	cqImpl := newClusterQueueImpl(keyFunc, byAdmissionDeadline)
+	cqEDF := &ClusterQueueEarliestDeadlineFirst{
+		clusterQueueBase: cqImpl,
+	}
+
+	err := cqEDF.Update(cq)
+	return cqEDF, err
+}
+
+// byAdmissionDeadline is the function used by the clusterQueue heap algorithm
+// to sort workloads. It sorts workloads based on their admission deadline,
+// earliest first, followed by the workloads without a deadline.
+// When deadlines are equal, it uses byPriority.
+func byAdmissionDeadline(a, b interface{}) bool {
+	objA := a.(*workload.Info)
+	objB := b.(*workload.Info)
+	d1 := objA.Obj.Spec.AdmissionDeadline
+	d2 := objB.Obj.Spec.AdmissionDeadline
+
+	if (d1 == nil) != (d2 == nil) {
+		return d1 != nil
+	}
+	if d1 != nil && !d1.Equal(d2) {
+		return d1.Before(d2)
+	}
+	return byPriority(a, b)
+}
+
+// RequeueIfNotPresent requeues if the workload is not present.
+// The requeue is only immediate if the workload failed after being nominated,
+// so that workloads that can't be admitted don't block the rest of the queue.
+func (cq *ClusterQueueEarliestDeadlineFirst) RequeueIfNotPresent(wInfo *workload.Info, reason RequeueReason) bool {
+	return cq.requeueIfNotPresent(wInfo, reason == RequeueReasonFailedAfterNomination)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+func TestEarliestDeadlineFirst(t *testing.T) {
+	now := time.Now()
+	q, err := newClusterQueue(&kueue.ClusterQueue{
+		Spec: kueue.ClusterQueueSpec{
+			QueueingStrategy: kueue.EarliestDeadlineFirst,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed creating ClusterQueue %v", err)
+	}
+	for _, wl := range []*kueue.Workload{
+		utiltesting.MakeWorkload("no-deadline-high", defaultNamespace).
+			Priority(highPriority).Creation(now).Obj(),
+		utiltesting.MakeWorkload("no-deadline-low", defaultNamespace).
+			Priority(lowPriority).Creation(now.Add(-time.Minute)).Obj(),
+		utiltesting.MakeWorkload("late-deadline", defaultNamespace).
+			AdmissionDeadline(now.Add(time.Hour)).Creation(now.Add(-time.Minute)).Obj(),
+		utiltesting.MakeWorkload("early-deadline", defaultNamespace).
+			AdmissionDeadline(now.Add(time.Minute)).Creation(now).Obj(),
+		utiltesting.MakeWorkload("early-deadline-high", defaultNamespace).
+			Priority(highPriority).AdmissionDeadline(now.Add(time.Minute)).Creation(now).Obj(),
+	} {
+		q.PushOrUpdate(workload.NewInfo(wl))
+	}
+
+	var got []string
+	for w := q.Pop(); w != nil; w = q.Pop() {
+		got = append(got, w.Obj.Name)
+	}
+	want := []string{"early-deadline-high", "early-deadline", "late-deadline", "no-deadline-high", "no-deadline-low"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected order of the popped workloads (-want,+got):\n%s", diff)
+	}
+}
+
+func TestEarliestDeadlineFirstRequeueIfNotPresent(t *testing.T) {
+	tests := map[RequeueReason]struct {
+		wantInadmissible bool
+	}{
+		RequeueReasonFailedAfterNomination: {
+			wantInadmissible: false,
+		},
+		RequeueReasonNamespaceMismatch: {
+			wantInadmissible: true,
+		},
+		RequeueReasonGeneric: {
+			wantInadmissible: true,
+		},
+	}
+
+	for reason, test := range tests {
+		t.Run(string(reason), func(t *testing.T) {
+			cq, _ := newClusterQueueEarliestDeadlineFirst(&kueue.ClusterQueue{
+				Spec: kueue.ClusterQueueSpec{
+					QueueingStrategy: kueue.EarliestDeadlineFirst,
+				},
+			})
+			wl := utiltesting.MakeWorkload("workload-1", defaultNamespace).Obj()
+			if ok := cq.RequeueIfNotPresent(workload.NewInfo(wl), reason); !ok {
+				t.Error("failed to requeue nonexistent workload")
+			}
+
+			_, gotInadmissible := cq.(*ClusterQueueEarliestDeadlineFirst).inadmissibleWorkloads[workload.Key(wl)]
+			if diff := cmp.Diff(test.wantInadmissible, gotInadmissible); diff != "" {
+				t.Errorf("Unexpected inadmissible status (-want,+got):\n%s", diff)
+			}
+
+			if ok := cq.RequeueIfNotPresent(workload.NewInfo(wl), reason); ok {
+				t.Error("Re-queued a workload that was already present")
+			}
+		})
+	}
+}
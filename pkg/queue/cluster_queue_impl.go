@@ -18,6 +18,8 @@ package queue
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -32,13 +34,58 @@ import (
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
+// workloadHeap holds the pending workloads of a ClusterQueue.
+type workloadHeap interface {
+	PushOrUpdate(obj interface{})
+	PushIfNotPresent(obj interface{}) bool
+	Delete(key string)
+	Pop() interface{}
+	Reorder()
+	GetByKey(key string) interface{}
+	Len() int
+	List() []interface{}
+	// Sorted returns all the workloads in the order in which Pop would
+	// return them, without modifying the heap.
+	Sorted() []interface{}
+}
+
+// singleHeap orders the pending workloads of all the LocalQueues together.
+type singleHeap struct {
+	heap.Heap
+	lessFunc func(a, b interface{}) bool
+}
+
+func newSingleHeap(keyFunc func(obj interface{}) string, lessFunc func(a, b interface{}) bool) *singleHeap {
+	return &singleHeap{
+		Heap:     heap.New(keyFunc, lessFunc),
+		lessFunc: lessFunc,
+	}
+}
+
+func (h *singleHeap) Sorted() []interface{} {
+	items := h.List()
+	sort.Slice(items, func(i, j int) bool {
+		return h.lessFunc(items[i], items[j])
+	})
+	return items
+}
+
 // clusterQueueBase is an incomplete base implementation of ClusterQueue
 // interface. It can be inherited and overwritten by other types.
 type clusterQueueBase struct {
-	heap              heap.Heap
+	heap              workloadHeap
+	keyFunc           func(obj interface{}) string
+	lessFunc          func(a, b interface{}) bool
 	cohort            string
 	namespaceSelector labels.Selector
 
+	// localQueueSharing is the sharing of the admission attempts between the
+	// LocalQueues. The workloads are held in a localQueueHeaps when it's
+	// Weighted, and in a singleHeap otherwise.
+	localQueueSharing kueue.LocalQueueSharing
+	// localQueues are the LocalQueues feeding the ClusterQueue.
+	localQueues map[string]*LocalQueue
+
 	// inadmissibleWorkloads are workloads that have been tried at least once and couldn't be admitted.
 	inadmissibleWorkloads map[string]*workload.Info
 
@@ -54,7 +101,11 @@ type clusterQueueBase struct {
 
 func newClusterQueueImpl(keyFunc func(obj interface{}) string, lessFunc func(a, b interface{}) bool) *clusterQueueBase {
 	return &clusterQueueBase{
-		heap:                   heap.New(keyFunc, lessFunc),
+		heap:                   newSingleHeap(keyFunc, lessFunc),
+		keyFunc:                keyFunc,
+		lessFunc:               lessFunc,
+		localQueueSharing:      kueue.LocalQueueSharingNone,
+		localQueues:            make(map[string]*LocalQueue),
 		inadmissibleWorkloads:  make(map[string]*workload.Info),
 		queueInadmissibleCycle: -1,
 	}
@@ -67,14 +118,45 @@ func (c *clusterQueueBase) Update(apiCQ *kueue.ClusterQueue) error {
 		return err
 	}
 	c.namespaceSelector = nsSelector
+	sharing := kueue.LocalQueueSharingNone
+	if apiCQ.Spec.LocalQueueSharing != nil {
+		sharing = *apiCQ.Spec.LocalQueueSharing
+	}
+	if sharing != c.localQueueSharing {
+		c.localQueueSharing = sharing
+		c.rebuildHeap()
+	}
 	return nil
 }
 
+// rebuildHeap moves the pending workloads to a new heap for the current
+// localQueueSharing.
+func (c *clusterQueueBase) rebuildHeap() {
+	var h workloadHeap
+	if c.localQueueSharing == kueue.LocalQueueSharingWeighted {
+		lqHeaps := newLocalQueueHeaps(c.keyFunc, c.lessFunc)
+		for _, q := range c.localQueues {
+			lqHeaps.setLocalQueue(q)
+		}
+		h = lqHeaps
+	} else {
+		h = newSingleHeap(c.keyFunc, c.lessFunc)
+	}
+	for _, obj := range c.heap.List() {
+		h.PushIfNotPresent(obj)
+	}
+	c.heap = h
+}
+
 func (c *clusterQueueBase) Cohort() string {
 	return c.cohort
 }
 
 func (c *clusterQueueBase) AddFromLocalQueue(q *LocalQueue) bool {
+	c.localQueues[q.Key] = q
+	if lqHeaps, ok := c.heap.(*localQueueHeaps); ok {
+		lqHeaps.setLocalQueue(q)
+	}
 	added := false
 	for _, info := range q.items {
 		if c.heap.PushIfNotPresent(info) {
@@ -86,11 +168,18 @@ func (c *clusterQueueBase) AddFromLocalQueue(q *LocalQueue) bool {
 
 func (c *clusterQueueBase) PushOrUpdate(wInfo *workload.Info) {
 	key := workload.Key(wInfo.Obj)
+	if workload.BackoffRemaining(wInfo.Obj, time.Now()) > 0 {
+		// the workload can't be admitted until its backoff expires.
+		c.heap.Delete(key)
+		c.inadmissibleWorkloads[key] = wInfo
+		return
+	}
 	oldInfo := c.inadmissibleWorkloads[key]
 	if oldInfo != nil {
 		// update in place if the workload was inadmissible and didn't change
 		// to potentially become admissible.
-		if equality.Semantic.DeepEqual(oldInfo.Obj.Spec, wInfo.Obj.Spec) {
+		if equality.Semantic.DeepEqual(oldInfo.Obj.Spec, wInfo.Obj.Spec) &&
+			equality.Semantic.DeepEqual(oldInfo.Obj.Status.RequeueState, wInfo.Obj.Status.RequeueState) {
 			c.inadmissibleWorkloads[key] = wInfo
 			return
 		}
@@ -116,16 +205,22 @@ func (c *clusterQueueBase) DeleteFromLocalQueue(q *LocalQueue) {
 	for _, w := range q.items {
 		c.Delete(w.Obj)
 	}
+	delete(c.localQueues, q.Key)
+	if lqHeaps, ok := c.heap.(*localQueueHeaps); ok {
+		lqHeaps.deleteLocalQueue(q.Key)
+	}
 }
 
 // requeueIfNotPresent inserts a workload that cannot be admitted into
 // ClusterQueue, unless it is already in the queue. If immediate is true
 // or if there was a call to QueueInadmissibleWorkloads after a call to Pop,
-// the workload will be pushed back to heap directly. Otherwise, the workload
+// the workload will be pushed back to heap directly. Otherwise, or if the
+// workload is waiting for its requeuing backoff to expire, the workload
 // will be put into the inadmissibleWorkloads.
 func (c *clusterQueueBase) requeueIfNotPresent(wInfo *workload.Info, immediate bool) bool {
 	key := workload.Key(wInfo.Obj)
-	if immediate || c.queueInadmissibleCycle >= c.popCycle {
+	backingOff := workload.BackoffRemaining(wInfo.Obj, time.Now()) > 0
+	if !backingOff && (immediate || c.queueInadmissibleCycle >= c.popCycle) {
 		// If the workload was inadmissible, move it back into the queue.
 		inadmissibleWl := c.inadmissibleWorkloads[key]
 		if inadmissibleWl != nil {
@@ -148,9 +243,11 @@ func (c *clusterQueueBase) requeueIfNotPresent(wInfo *workload.Info, immediate b
 	return true
 }
 
-// QueueInadmissibleWorkloads moves all workloads from inadmissibleWorkloads to heap.
+// QueueInadmissibleWorkloads moves the workloads for which the event is
+// relevant from inadmissibleWorkloads to heap, except the ones waiting for
+// their requeuing backoff to expire.
 // If at least one workload is moved, returns true. Otherwise returns false.
-func (c *clusterQueueBase) QueueInadmissibleWorkloads(ctx context.Context, client client.Client) bool {
+func (c *clusterQueueBase) QueueInadmissibleWorkloads(ctx context.Context, client client.Client, event RequeueEvent) bool {
 	c.queueInadmissibleCycle = c.popCycle
 	if len(c.inadmissibleWorkloads) == 0 {
 		return false
@@ -158,7 +255,12 @@ func (c *clusterQueueBase) QueueInadmissibleWorkloads(ctx context.Context, clien
 
 	inadmissibleWorkloads := make(map[string]*workload.Info)
 	moved := false
+	now := time.Now()
 	for key, wInfo := range c.inadmissibleWorkloads {
+		if workload.BackoffRemaining(wInfo.Obj, now) > 0 || !event.Relevant(wInfo.Obj) {
+			inadmissibleWorkloads[key] = wInfo
+			continue
+		}
 		ns := corev1.Namespace{}
 		err := client.Get(ctx, types.NamespacedName{Name: wInfo.Obj.Namespace}, &ns)
 		if err != nil || !c.namespaceSelector.Matches(labels.Set(ns.Labels)) {
@@ -217,6 +319,30 @@ func (c *clusterQueueBase) DumpInadmissible() (sets.Set[string], bool) {
 	return elements, true
 }
 
+func (c *clusterQueueBase) Snapshot(maxCount int) []*workload.Info {
+	inadmissible := make([]*workload.Info, 0, len(c.inadmissibleWorkloads))
+	for _, info := range c.inadmissibleWorkloads {
+		inadmissible = append(inadmissible, info)
+	}
+	sort.Slice(inadmissible, func(i, j int) bool {
+		return c.lessFunc(inadmissible[i], inadmissible[j])
+	})
+	elements := make([]*workload.Info, 0, maxCount)
+	for _, e := range c.heap.Sorted() {
+		if len(elements) == maxCount {
+			return elements
+		}
+		elements = append(elements, e.(*workload.Info))
+	}
+	for _, info := range inadmissible {
+		if len(elements) == maxCount {
+			break
+		}
+		elements = append(elements, info)
+	}
+	return elements
+}
+
 func (c *clusterQueueBase) Info(key string) *workload.Info {
 	info := c.heap.GetByKey(key)
 	if info == nil {
@@ -306,7 +306,7 @@ func TestClusterQueueImpl(t *testing.T) {
 
 			if test.queueInadmissibleWorkloads {
 				if diff := cmp.Diff(test.wantInadmissibleWorkloadsRequeued,
-					cq.QueueInadmissibleWorkloads(context.Background(), cl)); diff != "" {
+					cq.QueueInadmissibleWorkloads(context.Background(), cl, RequeueEvent{})); diff != "" {
 					t.Errorf("Unexpected requeueing of inadmissible workloads (-want,+got):\n%s", diff)
 				}
 			}
@@ -322,6 +322,283 @@ func TestClusterQueueImpl(t *testing.T) {
 	}
 }
 
+func TestPopAcrossLocalQueues(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		strategy  kueue.QueueingStrategy
+		sharing   kueue.LocalQueueSharing
+		queues    []*kueue.LocalQueue
+		workloads []*kueue.Workload
+		wantPops  []string
+	}{
+		"single queue pops in order": {
+			strategy: kueue.BestEffortFIFO,
+			sharing:  kueue.LocalQueueSharingWeighted,
+			queues: []*kueue.LocalQueue{
+				utiltesting.MakeLocalQueue("a", defaultNamespace).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Creation(now).Obj(),
+				utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Creation(now.Add(time.Second)).Obj(),
+			},
+			wantPops: []string{"a1", "a2"},
+		},
+		"no sharing orders the queues together": {
+			strategy: kueue.BestEffortFIFO,
+			sharing:  kueue.LocalQueueSharingNone,
+			queues: []*kueue.LocalQueue{
+				utiltesting.MakeLocalQueue("a", defaultNamespace).Obj(),
+				utiltesting.MakeLocalQueue("b", defaultNamespace).Weight(2).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Creation(now).Obj(),
+				utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Creation(now.Add(time.Second)).Obj(),
+				utiltesting.MakeWorkload("b1", defaultNamespace).Queue("b").Creation(now.Add(2 * time.Second)).Obj(),
+				utiltesting.MakeWorkload("b2", defaultNamespace).Queue("b").Creation(now.Add(3 * time.Second)).Obj(),
+			},
+			wantPops: []string{"a1", "a2", "b1", "b2"},
+		},
+		"round-robin across queues": {
+			strategy: kueue.BestEffortFIFO,
+			sharing:  kueue.LocalQueueSharingWeighted,
+			queues: []*kueue.LocalQueue{
+				utiltesting.MakeLocalQueue("a", defaultNamespace).Obj(),
+				utiltesting.MakeLocalQueue("b", defaultNamespace).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Creation(now).Obj(),
+				utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Creation(now.Add(time.Second)).Obj(),
+				utiltesting.MakeWorkload("a3", defaultNamespace).Queue("a").Creation(now.Add(2 * time.Second)).Obj(),
+				utiltesting.MakeWorkload("a4", defaultNamespace).Queue("a").Creation(now.Add(3 * time.Second)).Obj(),
+				utiltesting.MakeWorkload("b1", defaultNamespace).Queue("b").Creation(now.Add(4 * time.Second)).Obj(),
+				utiltesting.MakeWorkload("b2", defaultNamespace).Queue("b").Creation(now.Add(5 * time.Second)).Obj(),
+			},
+			wantPops: []string{"a1", "b1", "a2", "b2", "a3", "a4"},
+		},
+		"weighted round-robin across queues": {
+			strategy: kueue.BestEffortFIFO,
+			sharing:  kueue.LocalQueueSharingWeighted,
+			queues: []*kueue.LocalQueue{
+				utiltesting.MakeLocalQueue("a", defaultNamespace).Obj(),
+				utiltesting.MakeLocalQueue("b", defaultNamespace).Weight(2).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Creation(now).Obj(),
+				utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Creation(now.Add(time.Second)).Obj(),
+				utiltesting.MakeWorkload("b1", defaultNamespace).Queue("b").Creation(now.Add(2 * time.Second)).Obj(),
+				utiltesting.MakeWorkload("b2", defaultNamespace).Queue("b").Creation(now.Add(3 * time.Second)).Obj(),
+				utiltesting.MakeWorkload("b3", defaultNamespace).Queue("b").Creation(now.Add(4 * time.Second)).Obj(),
+				utiltesting.MakeWorkload("b4", defaultNamespace).Queue("b").Creation(now.Add(5 * time.Second)).Obj(),
+			},
+			wantPops: []string{"a1", "b1", "b2", "a2", "b3", "b4"},
+		},
+		"priority without sharing": {
+			strategy: kueue.Priority,
+			sharing:  kueue.LocalQueueSharingNone,
+			queues: []*kueue.LocalQueue{
+				utiltesting.MakeLocalQueue("a", defaultNamespace).Obj(),
+				utiltesting.MakeLocalQueue("b", defaultNamespace).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Priority(100).Creation(now).Obj(),
+				utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Priority(50).Creation(now).Obj(),
+				utiltesting.MakeWorkload("b1", defaultNamespace).Queue("b").Priority(10).Creation(now).Obj(),
+			},
+			wantPops: []string{"a1", "a2", "b1"},
+		},
+		"priority within the queues with weighted sharing": {
+			strategy: kueue.Priority,
+			sharing:  kueue.LocalQueueSharingWeighted,
+			queues: []*kueue.LocalQueue{
+				utiltesting.MakeLocalQueue("a", defaultNamespace).Obj(),
+				utiltesting.MakeLocalQueue("b", defaultNamespace).Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Priority(100).Creation(now).Obj(),
+				utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Priority(50).Creation(now).Obj(),
+				utiltesting.MakeWorkload("a3", defaultNamespace).Queue("a").Priority(1).Creation(now).Obj(),
+				utiltesting.MakeWorkload("b1", defaultNamespace).Queue("b").Priority(10).Creation(now).Obj(),
+			},
+			wantPops: []string{"a1", "b1", "a2", "a3"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq, err := newClusterQueue(utiltesting.MakeClusterQueue("cq").
+				QueueingStrategy(tc.strategy).
+				LocalQueueSharing(tc.sharing).
+				Obj())
+			if err != nil {
+				t.Fatalf("Failed creating ClusterQueue: %v", err)
+			}
+			for _, q := range tc.queues {
+				cq.AddFromLocalQueue(newLocalQueue(q))
+			}
+			for _, w := range tc.workloads {
+				cq.PushOrUpdate(workload.NewInfo(w))
+			}
+			var gotSnapshot []string
+			for _, info := range cq.Snapshot(len(tc.workloads)) {
+				gotSnapshot = append(gotSnapshot, info.Obj.Name)
+			}
+			if diff := cmp.Diff(tc.wantPops, gotSnapshot); diff != "" {
+				t.Errorf("Unexpected snapshot (-want,+got):\n%s", diff)
+			}
+			var gotPops []string
+			for info := cq.Pop(); info != nil; info = cq.Pop() {
+				gotPops = append(gotPops, info.Obj.Name)
+			}
+			if diff := cmp.Diff(tc.wantPops, gotPops); diff != "" {
+				t.Errorf("Unexpected popped workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStrictFIFOAcrossLocalQueues(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		sharing kueue.LocalQueueSharing
+		// wantPops are the workloads popped after popping and requeueing
+		// the head of the ClusterQueue, which can't be admitted.
+		wantPops []string
+	}{
+		"the head blocks all the queues without sharing": {
+			sharing:  kueue.LocalQueueSharingNone,
+			wantPops: []string{"a1", "a1"},
+		},
+		"the head only blocks its queue with weighted sharing": {
+			sharing:  kueue.LocalQueueSharingWeighted,
+			wantPops: []string{"b1", "a1"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq, err := newClusterQueue(utiltesting.MakeClusterQueue("cq").
+				QueueingStrategy(kueue.StrictFIFO).
+				LocalQueueSharing(tc.sharing).
+				Obj())
+			if err != nil {
+				t.Fatalf("Failed creating ClusterQueue: %v", err)
+			}
+			cq.AddFromLocalQueue(newLocalQueue(utiltesting.MakeLocalQueue("a", defaultNamespace).Obj()))
+			cq.AddFromLocalQueue(newLocalQueue(utiltesting.MakeLocalQueue("b", defaultNamespace).Obj()))
+			for _, w := range []*kueue.Workload{
+				utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Creation(now).Obj(),
+				utiltesting.MakeWorkload("b1", defaultNamespace).Queue("b").Creation(now.Add(time.Second)).Obj(),
+			} {
+				cq.PushOrUpdate(workload.NewInfo(w))
+			}
+			head := cq.Pop()
+			if head == nil || head.Obj.Name != "a1" {
+				t.Fatalf("Popped %v, want a1", head)
+			}
+			cq.RequeueIfNotPresent(head, RequeueReasonGeneric)
+			var gotPops []string
+			for i := 0; i < len(tc.wantPops); i++ {
+				if info := cq.Pop(); info != nil {
+					gotPops = append(gotPops, info.Obj.Name)
+					if info.Obj.Name == "a1" {
+						cq.RequeueIfNotPresent(info, RequeueReasonGeneric)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.wantPops, gotPops); diff != "" {
+				t.Errorf("Unexpected popped workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateLocalQueueSharing(t *testing.T) {
+	now := time.Now()
+	cq, err := newClusterQueue(utiltesting.MakeClusterQueue("cq").Obj())
+	if err != nil {
+		t.Fatalf("Failed creating ClusterQueue: %v", err)
+	}
+	cq.AddFromLocalQueue(newLocalQueue(utiltesting.MakeLocalQueue("a", defaultNamespace).Obj()))
+	cq.AddFromLocalQueue(newLocalQueue(utiltesting.MakeLocalQueue("b", defaultNamespace).Obj()))
+	for _, w := range []*kueue.Workload{
+		utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Creation(now).Obj(),
+		utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Creation(now.Add(time.Second)).Obj(),
+		utiltesting.MakeWorkload("b1", defaultNamespace).Queue("b").Creation(now.Add(2 * time.Second)).Obj(),
+	} {
+		cq.PushOrUpdate(workload.NewInfo(w))
+	}
+	snapshot := func() []string {
+		var got []string
+		for _, info := range cq.Snapshot(10) {
+			got = append(got, info.Obj.Name)
+		}
+		return got
+	}
+	if diff := cmp.Diff([]string{"a1", "a2", "b1"}, snapshot()); diff != "" {
+		t.Errorf("Unexpected workloads without sharing (-want,+got):\n%s", diff)
+	}
+	if err := cq.Update(utiltesting.MakeClusterQueue("cq").LocalQueueSharing(kueue.LocalQueueSharingWeighted).Obj()); err != nil {
+		t.Fatalf("Failed updating ClusterQueue: %v", err)
+	}
+	if diff := cmp.Diff([]string{"a1", "b1", "a2"}, snapshot()); diff != "" {
+		t.Errorf("Unexpected workloads with weighted sharing (-want,+got):\n%s", diff)
+	}
+	if err := cq.Update(utiltesting.MakeClusterQueue("cq").Obj()); err != nil {
+		t.Fatalf("Failed updating ClusterQueue: %v", err)
+	}
+	if diff := cmp.Diff([]string{"a1", "a2", "b1"}, snapshot()); diff != "" {
+		t.Errorf("Unexpected workloads after disabling sharing (-want,+got):\n%s", diff)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	now := time.Now()
+	cq := newClusterQueueImpl(keyFunc, byCreationTime)
+	cq.AddFromLocalQueue(newLocalQueue(utiltesting.MakeLocalQueue("a", defaultNamespace).Obj()))
+	for _, w := range []*kueue.Workload{
+		utiltesting.MakeWorkload("a1", defaultNamespace).Queue("a").Creation(now).Obj(),
+		utiltesting.MakeWorkload("a3", defaultNamespace).Queue("a").Creation(now.Add(2 * time.Second)).Obj(),
+	} {
+		cq.PushOrUpdate(workload.NewInfo(w))
+	}
+	for _, w := range []*kueue.Workload{
+		utiltesting.MakeWorkload("a4", defaultNamespace).Queue("a").Creation(now.Add(3 * time.Second)).Obj(),
+		utiltesting.MakeWorkload("a2", defaultNamespace).Queue("a").Creation(now.Add(time.Second)).Obj(),
+	} {
+		cq.requeueIfNotPresent(workload.NewInfo(w), false)
+	}
+
+	cases := map[string]struct {
+		maxCount int
+		want     []string
+	}{
+		"inadmissible workloads go last": {
+			maxCount: 10,
+			want:     []string{"a1", "a3", "a2", "a4"},
+		},
+		"limited to maxCount": {
+			maxCount: 3,
+			want:     []string{"a1", "a3", "a2"},
+		},
+		"limited to maxCount within the heap": {
+			maxCount: 1,
+			want:     []string{"a1"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, info := range cq.Snapshot(tc.maxCount) {
+				got = append(got, info.Obj.Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected snapshot (-want,+got):\n%s", diff)
+			}
+		})
+	}
+	if got := cq.Pending(); got != 4 {
+		t.Errorf("Snapshot modified the queue, got %d pending workloads, want 4", got)
+	}
+}
+
 func TestQueueInadmissibleWorkloadsDuringScheduling(t *testing.T) {
 	cq := newClusterQueueImpl(keyFunc, byCreationTime)
 	cq.namespaceSelector = labels.Everything()
@@ -345,7 +622,7 @@ func TestQueueInadmissibleWorkloadsDuringScheduling(t *testing.T) {
 
 	// Simulate requeueing during scheduling attempt.
 	head := cq.Pop()
-	cq.QueueInadmissibleWorkloads(ctx, cl)
+	cq.QueueInadmissibleWorkloads(ctx, cl, RequeueEvent{})
 	cq.requeueIfNotPresent(head, false)
 
 	activeWorkloads, _ = cq.Dump()
@@ -363,3 +640,119 @@ func TestQueueInadmissibleWorkloadsDuringScheduling(t *testing.T) {
 		t.Errorf("Unexpected active workloads after scheduling (-want,+got):\n%s", diff)
 	}
 }
+
+func TestRequeuingBackoff(t *testing.T) {
+	cq := newClusterQueueImpl(keyFunc, byCreationTime)
+	cq.namespaceSelector = labels.Everything()
+	requeueAt := metav1.NewTime(time.Now().Add(time.Hour))
+	wl := utiltesting.MakeWorkload("workload-1", defaultNamespace).RequeueState(1, &requeueAt).Obj()
+	scheme := utiltesting.MustGetScheme(t)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		wl,
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace},
+		},
+	).Build()
+	ctx := context.Background()
+
+	// A workload waiting for its backoff is kept aside, even if the requeue is immediate.
+	cq.requeueIfNotPresent(workload.NewInfo(wl), true)
+	if cq.PendingActive() != 0 || cq.PendingInadmissible() != 1 {
+		t.Fatalf("Workload waiting for its backoff should be inadmissible, got %d active and %d inadmissible", cq.PendingActive(), cq.PendingInadmissible())
+	}
+
+	cq.QueueInadmissibleWorkloads(ctx, cl, RequeueEvent{})
+	if cq.PendingActive() != 0 || cq.PendingInadmissible() != 1 {
+		t.Fatalf("Workload waiting for its backoff shouldn't be moved to the heap, got %d active and %d inadmissible", cq.PendingActive(), cq.PendingInadmissible())
+	}
+
+	// Once the backoff expires, the update moves the workload back to the heap.
+	wl = wl.DeepCopy()
+	wl.Status.RequeueState.RequeueAt = nil
+	cq.PushOrUpdate(workload.NewInfo(wl))
+	if cq.PendingActive() != 1 || cq.PendingInadmissible() != 0 {
+		t.Fatalf("Workload whose backoff expired should be active, got %d active and %d inadmissible", cq.PendingActive(), cq.PendingInadmissible())
+	}
+
+	// A new backoff moves the workload aside again.
+	wl = wl.DeepCopy()
+	wl.Status.RequeueState.RequeueAt = &requeueAt
+	cq.PushOrUpdate(workload.NewInfo(wl))
+	if cq.PendingActive() != 0 || cq.PendingInadmissible() != 1 {
+		t.Fatalf("Workload waiting for its backoff should be inadmissible, got %d active and %d inadmissible", cq.PendingActive(), cq.PendingInadmissible())
+	}
+}
+
+func TestQueueInadmissibleWorkloadsOnRelevantEvents(t *testing.T) {
+	noReasons := utiltesting.MakeWorkload("no-reasons", defaultNamespace).Obj()
+	lackOfQuota := utiltesting.MakeWorkload("lack-of-quota", defaultNamespace).Obj()
+	lackOfQuota.Status.InadmissibleReasons = []kueue.InadmissibleReason{
+		{
+			PodSet:   "main",
+			Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+			Resource: corev1.ResourceCPU,
+			Flavor:   "on-demand",
+			Message:  "insufficient unused quota for cpu flavor on-demand, 1 more needed",
+		},
+		{
+			PodSet:  "main",
+			Type:    kueue.InadmissibleReasonUntoleratedTaint,
+			Flavor:  "spot",
+			Message: "untolerated taint {instance spot NoSchedule <nil>} in flavor spot",
+		},
+	}
+	untolerated := utiltesting.MakeWorkload("untolerated", defaultNamespace).Obj()
+	untolerated.Status.InadmissibleReasons = []kueue.InadmissibleReason{
+		{
+			PodSet:  "main",
+			Type:    kueue.InadmissibleReasonUntoleratedTaint,
+			Flavor:  "spot",
+			Message: "untolerated taint {instance spot NoSchedule <nil>} in flavor spot",
+		},
+	}
+	scheme := utiltesting.MustGetScheme(t)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace},
+		},
+	).Build()
+
+	cases := map[string]struct {
+		event            RequeueEvent
+		wantActive       sets.Set[string]
+		wantInadmissible sets.Set[string]
+	}{
+		"any change": {
+			event:      RequeueEvent{},
+			wantActive: sets.New("default/no-reasons", "default/lack-of-quota", "default/untolerated"),
+		},
+		"quota released in the flavor lacking quota": {
+			event:            RequeueEvent{ReleasedFlavors: sets.New("on-demand")},
+			wantActive:       sets.New("default/no-reasons", "default/lack-of-quota"),
+			wantInadmissible: sets.New("default/untolerated"),
+		},
+		"quota released in a flavor with an untolerated taint": {
+			event:            RequeueEvent{ReleasedFlavors: sets.New("spot")},
+			wantActive:       sets.New("default/no-reasons"),
+			wantInadmissible: sets.New("default/lack-of-quota", "default/untolerated"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cq := newClusterQueueImpl(keyFunc, byCreationTime)
+			cq.namespaceSelector = labels.Everything()
+			for _, wl := range []*kueue.Workload{noReasons, lackOfQuota, untolerated} {
+				cq.requeueIfNotPresent(workload.NewInfo(wl), false)
+			}
+			cq.QueueInadmissibleWorkloads(context.Background(), cl, tc.event)
+			active, _ := cq.Dump()
+			if diff := cmp.Diff(tc.wantActive, active); diff != "" {
+				t.Errorf("Unexpected active workloads (-want,+got):\n%s", diff)
+			}
+			inadmissible, _ := cq.DumpInadmissible()
+			if diff := cmp.Diff(tc.wantInadmissible, inadmissible); diff != "" {
+				t.Errorf("Unexpected inadmissible workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -32,9 +32,60 @@ type RequeueReason string
 const (
 	RequeueReasonFailedAfterNomination RequeueReason = "FailedAfterNomination"
 	RequeueReasonNamespaceMismatch     RequeueReason = "NamespaceMismatch"
+	RequeueReasonPendingPreemption     RequeueReason = "PendingPreemption"
 	RequeueReasonGeneric               RequeueReason = ""
 )
 
+// quotaUsageReasons are the types of inadmissibility reasons that can go away
+// when other workloads release quota in the flavor of the reason.
+var quotaUsageReasons = sets.New(
+	kueue.InadmissibleReasonInsufficientUnusedQuota,
+	kueue.InadmissibleReasonBorrowingLimitExceeded,
+	kueue.InadmissibleReasonCohortLimitExceeded,
+	kueue.InadmissibleReasonNamespaceLimitExceeded,
+	kueue.InadmissibleReasonTopologyMismatch,
+)
+
+// RequeueEvent describes a change in the cluster that could make inadmissible
+// workloads admissible.
+type RequeueEvent struct {
+	// ReleasedFlavors are the flavors in which an admitted workload released
+	// quota. If nil, the change could affect any inadmissible workload, like
+	// when quotas, flavors or nodes change.
+	ReleasedFlavors sets.Set[string]
+}
+
+// Relevant returns whether the event could make the workload admissible,
+// based on the reasons recorded in its status the last time it was found
+// inadmissible. Workloads without recorded reasons are always relevant.
+func (e *RequeueEvent) Relevant(wl *kueue.Workload) bool {
+	reasons := wl.Status.InadmissibleReasons
+	if e.ReleasedFlavors == nil || len(reasons) == 0 || len(reasons) >= workload.MaxInadmissibleReasons {
+		return true
+	}
+	for _, r := range reasons {
+		if quotaUsageReasons.Has(r.Type) && (r.Flavor == "" || r.Flavor == string(kueue.AnyFlavor) || e.ReleasedFlavors.Has(r.Flavor)) {
+			return true
+		}
+	}
+	return false
+}
+
+// releasedQuotaEvent returns the event of the workload releasing the quota of
+// its admission.
+func releasedQuotaEvent(wl *kueue.Workload) RequeueEvent {
+	if wl.Spec.Admission == nil {
+		return RequeueEvent{}
+	}
+	flavors := sets.New[string]()
+	for _, ps := range wl.Spec.Admission.PodSetFlavors {
+		for _, f := range ps.Flavors {
+			flavors.Insert(f)
+		}
+	}
+	return RequeueEvent{ReleasedFlavors: flavors}
+}
+
 // ClusterQueue is an interface for a cluster queue to store workloads waiting
 // to be scheduled.
 type ClusterQueue interface {
@@ -70,10 +121,10 @@ type ClusterQueue interface {
 	// The workload should not be reinserted if it's already in the ClusterQueue.
 	// Returns true if the workload was inserted.
 	RequeueIfNotPresent(*workload.Info, RequeueReason) bool
-	// QueueInadmissibleWorkloads moves all workloads put in temporary placeholder stage
-	// to the ClusterQueue. If at least one workload is moved,
-	// returns true. Otherwise returns false.
-	QueueInadmissibleWorkloads(ctx context.Context, client client.Client) bool
+	// QueueInadmissibleWorkloads moves the workloads put in temporary placeholder
+	// stage for which the event is relevant to the ClusterQueue. If at least one
+	// workload is moved, returns true. Otherwise returns false.
+	QueueInadmissibleWorkloads(ctx context.Context, client client.Client, event RequeueEvent) bool
 
 	// Pending returns the total number of pending workloads.
 	Pending() int
@@ -91,14 +142,21 @@ type ClusterQueue interface {
 	// Otherwise returns true.
 	Dump() (sets.Set[string], bool)
 	DumpInadmissible() (sets.Set[string], bool)
+	// Snapshot returns up to maxCount pending workloads, in the order in which
+	// they would be considered for admission. The workloads in the heap come
+	// before the inadmissible ones.
+	// Users of this method should not modify the returned objects.
+	Snapshot(maxCount int) []*workload.Info
 	// Info returns workload.Info for the workload key.
 	// Users of this method should not modify the returned object.
 	Info(string) *workload.Info
 }
 
 var registry = map[kueue.QueueingStrategy]func(cq *kueue.ClusterQueue) (ClusterQueue, error){
-	StrictFIFO:     newClusterQueueStrictFIFO,
-	BestEffortFIFO: newClusterQueueBestEffortFIFO,
+	StrictFIFO:            newClusterQueueStrictFIFO,
+	BestEffortFIFO:        newClusterQueueBestEffortFIFO,
+	Priority:              newClusterQueuePriority,
+	EarliestDeadlineFirst: newClusterQueueEarliestDeadlineFirst,
 }
 
 func newClusterQueue(cq *kueue.ClusterQueue) (ClusterQueue, error) {
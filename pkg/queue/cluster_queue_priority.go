@@ -0,0 +1,105 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// ClusterQueuePriority is the implementation for the ClusterQueue for
+// Priority.
+type ClusterQueuePriority struct {
+	*clusterQueueBase
+
+	// priorityAging is the policy to increase the effective priority of the
+	// workloads as they wait.
+	priorityAging *kueue.PriorityAging
+}
+
+var _ ClusterQueue = &ClusterQueuePriority{}
+
+const Priority = kueue.Priority
+
+func newClusterQueuePriority(cq *kueue.ClusterQueue) (ClusterQueue, error) {
+	cqPriority := &ClusterQueuePriority{}
+	cqPriority.clusterQueueBase = newClusterQueueImpl(keyFunc, cqPriority.byEffectivePriority)
+
+	err := cqPriority.Update(cq)
+	return cqPriority, err
+}
+
+func (cq *ClusterQueuePriority) Update(apiCQ *kueue.ClusterQueue) error {
+	if !equality.Semantic.DeepEqual(cq.priorityAging, apiCQ.Spec.PriorityAging) {
+		cq.priorityAging = apiCQ.Spec.PriorityAging.DeepCopy()
+		cq.heap.Reorder()
+	}
+	return cq.clusterQueueBase.Update(apiCQ)
+}
+
+// Pop returns the head of the queue. The effective priorities of the
+// workloads increase as they wait, so the relative order of the workloads
+// can change, and the queue is reordered first.
+func (cq *ClusterQueuePriority) Pop() *workload.Info {
+	if cq.priorityAging != nil {
+		cq.heap.Reorder()
+	}
+	return cq.clusterQueueBase.Pop()
+}
+
+// byPriority is the function used by the clusterQueue heap algorithm to sort
+// workloads. It sorts workloads based on their priority.
+// When priorities are equal, it uses workloads.creationTimestamp.
+func byPriority(a, b interface{}) bool {
+	objA := a.(*workload.Info)
+	objB := b.(*workload.Info)
+	p1 := utilpriority.Priority(objA.Obj)
+	p2 := utilpriority.Priority(objB.Obj)
+
+	if p1 != p2 {
+		return p1 > p2
+	}
+	return byCreationTime(a, b)
+}
+
+// byEffectivePriority sorts workloads like byPriority, but based on their
+// effective priority, given the priority aging policy of the ClusterQueue.
+func (cq *ClusterQueuePriority) byEffectivePriority(a, b interface{}) bool {
+	if cq.priorityAging == nil {
+		return byPriority(a, b)
+	}
+	now := time.Now()
+	p1 := utilpriority.EffectivePriority(a.(*workload.Info).Obj, cq.priorityAging, now)
+	p2 := utilpriority.EffectivePriority(b.(*workload.Info).Obj, cq.priorityAging, now)
+
+	if p1 != p2 {
+		return p1 > p2
+	}
+	return byCreationTime(a, b)
+}
+
+// RequeueIfNotPresent requeues if the workload is not present.
+// The requeue is only immediate if the workload failed after being nominated,
+// so that workloads that can't be admitted don't block the rest of the queue.
+func (cq *ClusterQueuePriority) RequeueIfNotPresent(wInfo *workload.Info, reason RequeueReason) bool {
+	return cq.requeueIfNotPresent(wInfo, reason == RequeueReasonFailedAfterNomination)
+}
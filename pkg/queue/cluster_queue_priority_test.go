@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+func TestPriority(t *testing.T) {
+	t1 := time.Now()
+	t2 := t1.Add(time.Second)
+	for _, tt := range []struct {
+		name     string
+		w1       *kueue.Workload
+		w2       *kueue.Workload
+		expected string
+	}{
+		{
+			name: "w1.priority is higher than w2.priority",
+			w1: &kueue.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "w1",
+					CreationTimestamp: metav1.NewTime(t1),
+				},
+				Spec: kueue.WorkloadSpec{
+					PriorityClassName: "highPriority",
+					Priority:          pointer.Int32(highPriority),
+				},
+			},
+			w2: &kueue.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "w2",
+					CreationTimestamp: metav1.NewTime(t2),
+				},
+				Spec: kueue.WorkloadSpec{
+					PriorityClassName: "lowPriority",
+					Priority:          pointer.Int32(lowPriority),
+				},
+			},
+			expected: "w1",
+		},
+		{
+			name: "w1.priority equals w2.priority and w1.create time is earlier than w2.create time",
+			w1: &kueue.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "w1",
+					CreationTimestamp: metav1.NewTime(t1),
+				},
+			},
+			w2: &kueue.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "w2",
+					CreationTimestamp: metav1.NewTime(t2),
+				},
+			},
+			expected: "w1",
+		},
+		{
+			name: "p1.priority is lower than p2.priority and w1.create time is earlier than w2.create time",
+			w1: &kueue.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "w1",
+					CreationTimestamp: metav1.NewTime(t1),
+				},
+				Spec: kueue.WorkloadSpec{
+					PriorityClassName: "lowPriority",
+					Priority:          pointer.Int32(lowPriority),
+				},
+			},
+			w2: &kueue.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "w2",
+					CreationTimestamp: metav1.NewTime(t2),
+				},
+				Spec: kueue.WorkloadSpec{
+					PriorityClassName: "highPriority",
+					Priority:          pointer.Int32(highPriority),
+				},
+			},
+			expected: "w2",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := newClusterQueue(&kueue.ClusterQueue{
+				Spec: kueue.ClusterQueueSpec{
+					QueueingStrategy: kueue.Priority,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed creating ClusterQueue %v", err)
+			}
+
+			q.PushOrUpdate(workload.NewInfo(tt.w1))
+			q.PushOrUpdate(workload.NewInfo(tt.w2))
+
+			got := q.Pop()
+			if got == nil {
+				t.Fatal("Queue is empty")
+			}
+			if got.Obj.Name != tt.expected {
+				t.Errorf("Popped workload %q want %q", got.Obj.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPriorityRequeueIfNotPresent(t *testing.T) {
+	tests := map[RequeueReason]struct {
+		wantInadmissible bool
+	}{
+		RequeueReasonFailedAfterNomination: {
+			wantInadmissible: false,
+		},
+		RequeueReasonNamespaceMismatch: {
+			wantInadmissible: true,
+		},
+		RequeueReasonGeneric: {
+			wantInadmissible: true,
+		},
+	}
+
+	for reason, test := range tests {
+		t.Run(string(reason), func(t *testing.T) {
+			cq, _ := newClusterQueuePriority(&kueue.ClusterQueue{
+				Spec: kueue.ClusterQueueSpec{
+					QueueingStrategy: kueue.Priority,
+				},
+			})
+			wl := utiltesting.MakeWorkload("workload-1", defaultNamespace).Obj()
+			if ok := cq.RequeueIfNotPresent(workload.NewInfo(wl), reason); !ok {
+				t.Error("failed to requeue nonexistent workload")
+			}
+
+			_, gotInadmissible := cq.(*ClusterQueuePriority).inadmissibleWorkloads[workload.Key(wl)]
+			if diff := cmp.Diff(test.wantInadmissible, gotInadmissible); diff != "" {
+				t.Errorf("Unexpected inadmissible status (-want,+got):\n%s", diff)
+			}
+
+			if ok := cq.RequeueIfNotPresent(workload.NewInfo(wl), reason); ok {
+				t.Error("Re-queued a workload that was already present")
+			}
+		})
+	}
+}
+
+func TestPriorityAging(t *testing.T) {
+	now := time.Now()
+	aging := &kueue.PriorityAging{IntervalSeconds: 60, Step: 100}
+	for _, tt := range []struct {
+		name     string
+		aging    *kueue.PriorityAging
+		expected []string
+	}{
+		{
+			name:     "without aging",
+			expected: []string{"high", "old-low", "low"},
+		},
+		{
+			name:     "with aging",
+			aging:    aging,
+			expected: []string{"old-low", "high", "low"},
+		},
+		{
+			name:     "with the boost limited",
+			aging:    &kueue.PriorityAging{IntervalSeconds: 60, Step: 100, MaxBoost: pointer.Int32(500)},
+			expected: []string{"high", "old-low", "low"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := newClusterQueue(&kueue.ClusterQueue{
+				Spec: kueue.ClusterQueueSpec{
+					QueueingStrategy: kueue.Priority,
+					PriorityAging:    tt.aging,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed creating ClusterQueue %v", err)
+			}
+
+			q.PushOrUpdate(workload.NewInfo(utiltesting.MakeWorkload("high", defaultNamespace).
+				Priority(highPriority).Creation(now.Add(-time.Minute)).Obj()))
+			q.PushOrUpdate(workload.NewInfo(utiltesting.MakeWorkload("old-low", defaultNamespace).
+				Priority(lowPriority).Creation(now.Add(-time.Hour)).Obj()))
+			q.PushOrUpdate(workload.NewInfo(utiltesting.MakeWorkload("low", defaultNamespace).
+				Priority(lowPriority).Creation(now).Obj()))
+
+			var got []string
+			for w := q.Pop(); w != nil; w = q.Pop() {
+				got = append(got, w.Obj.Name)
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("Unexpected order of the popped workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPriorityAgingUpdate(t *testing.T) {
+	now := time.Now()
+	cq := &kueue.ClusterQueue{
+		Spec: kueue.ClusterQueueSpec{
+			QueueingStrategy: kueue.Priority,
+		},
+	}
+	q, err := newClusterQueue(cq)
+	if err != nil {
+		t.Fatalf("Failed creating ClusterQueue %v", err)
+	}
+	q.PushOrUpdate(workload.NewInfo(utiltesting.MakeWorkload("high", defaultNamespace).
+		Priority(highPriority).Creation(now).Obj()))
+	q.PushOrUpdate(workload.NewInfo(utiltesting.MakeWorkload("old-low", defaultNamespace).
+		Priority(lowPriority).Creation(now.Add(-time.Hour)).Obj()))
+
+	cq.Spec.PriorityAging = &kueue.PriorityAging{IntervalSeconds: 60, Step: 100}
+	if err := q.Update(cq); err != nil {
+		t.Fatalf("Failed updating ClusterQueue %v", err)
+	}
+	if got := q.Pop(); got == nil || got.Obj.Name != "old-low" {
+		t.Errorf("Popped workload %v, want old-low", got)
+	}
+}
@@ -18,7 +18,6 @@ package queue
 
 import (
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
-	utilpriority "sigs.k8s.io/kueue/pkg/util/priority"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -33,7 +32,7 @@ var _ ClusterQueue = &ClusterQueueStrictFIFO{}
 const StrictFIFO = kueue.StrictFIFO
 
 func newClusterQueueStrictFIFO(cq *kueue.ClusterQueue) (ClusterQueue, error) {
-	cqImpl := newClusterQueueImpl(keyFunc, byCreationTime)
+	cqImpl := newClusterQueueImpl(keyFunc, byPriority)
 	cqStrict := &ClusterQueueStrictFIFO{
 		clusterQueueBase: cqImpl,
 	}
@@ -42,18 +41,11 @@ func newClusterQueueStrictFIFO(cq *kueue.ClusterQueue) (ClusterQueue, error) {
 	return cqStrict, err
 }
 
-// byCreationTime is the function used by the clusterQueue heap algorithm to sort
-// workloads. It sorts workloads based on their priority.
-// When priorities are equal, it uses workloads.creationTimestamp.
+// byCreationTime sorts workloads based on their creationTimestamp. It breaks
+// the ties of the orderings by priority.
 func byCreationTime(a, b interface{}) bool {
 	objA := a.(*workload.Info)
 	objB := b.(*workload.Info)
-	p1 := utilpriority.Priority(objA.Obj)
-	p2 := utilpriority.Priority(objB.Obj)
-
-	if p1 != p2 {
-		return p1 > p2
-	}
 	return objA.Obj.CreationTimestamp.Before(&objB.Obj.CreationTimestamp)
 }
 
@@ -37,6 +37,7 @@ func Key(q *kueue.LocalQueue) string {
 type LocalQueue struct {
 	Key          string
 	ClusterQueue string
+	Weight       int32
 
 	items map[string]*workload.Info
 }
@@ -52,6 +53,7 @@ func newLocalQueue(q *kueue.LocalQueue) *LocalQueue {
 
 func (q *LocalQueue) update(apiQueue *kueue.LocalQueue) {
 	q.ClusterQueue = string(apiQueue.Spec.ClusterQueue)
+	q.Weight = apiQueue.Spec.Weight
 }
 
 func (q *LocalQueue) AddOrUpdate(info *workload.Info) {
@@ -0,0 +1,260 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"sigs.k8s.io/kueue/pkg/util/heap"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// localQueueHeaps holds the pending workloads of a ClusterQueue in one heap
+// per LocalQueue, for the Weighted localQueueSharing. Workloads are popped
+// across the LocalQueues using stride scheduling: each LocalQueue has a pass
+// that advances by 1/weight every time one of its workloads is popped, and the
+// LocalQueue with the lowest pass goes next. This way, every LocalQueue gets a
+// share of the admission attempts proportional to its weight, no matter how
+// many workloads it has pending.
+type localQueueHeaps struct {
+	keyFunc  func(obj interface{}) string
+	lessFunc func(a, b interface{}) bool
+
+	heaps map[string]*localQueueHeap
+	// active holds the LocalQueue heaps with pending workloads, ordered by
+	// goesBefore, so that Pop doesn't need to go through all of them.
+	active heap.Heap
+	// workloadQueues maps the key of every workload in the heaps to the key
+	// of the LocalQueue heap holding it.
+	workloadQueues map[string]string
+	// localQueues are the LocalQueues feeding the ClusterQueue, used to look
+	// up their weights.
+	localQueues map[string]*LocalQueue
+	// globalPass is the pass of the last LocalQueue heap that was popped.
+	// LocalQueues that become active again start from it, so they can't
+	// accumulate credit while they had no pending workloads.
+	globalPass float64
+}
+
+type localQueueHeap struct {
+	heap.Heap
+	qKey string
+	pass float64
+}
+
+func newLocalQueueHeaps(keyFunc func(obj interface{}) string, lessFunc func(a, b interface{}) bool) *localQueueHeaps {
+	h := &localQueueHeaps{
+		keyFunc:        keyFunc,
+		lessFunc:       lessFunc,
+		heaps:          make(map[string]*localQueueHeap),
+		workloadQueues: make(map[string]string),
+		localQueues:    make(map[string]*LocalQueue),
+	}
+	h.active = heap.New(
+		func(obj interface{}) string { return obj.(*localQueueHeap).qKey },
+		func(a, b interface{}) bool { return h.goesBefore(a.(*localQueueHeap), b.(*localQueueHeap)) },
+	)
+	return h
+}
+
+// setLocalQueue registers a LocalQueue feeding the ClusterQueue.
+func (h *localQueueHeaps) setLocalQueue(q *LocalQueue) {
+	h.localQueues[q.Key] = q
+}
+
+// deleteLocalQueue unregisters a LocalQueue. Its workloads are expected to be
+// deleted separately.
+func (h *localQueueHeaps) deleteLocalQueue(qKey string) {
+	delete(h.localQueues, qKey)
+	if lqHeap := h.heaps[qKey]; lqHeap != nil && lqHeap.Len() == 0 {
+		delete(h.heaps, qKey)
+	}
+}
+
+// PushOrUpdate inserts a workload in the heap of its LocalQueue.
+// The workload will be updated if it already exists.
+func (h *localQueueHeaps) PushOrUpdate(obj interface{}) {
+	key := h.keyFunc(obj)
+	qKey := workload.QueueKey(obj.(*workload.Info).Obj)
+	if oldQKey, exists := h.workloadQueues[key]; exists && oldQKey != qKey {
+		h.Delete(key)
+	}
+	lqHeap := h.heapFor(qKey)
+	lqHeap.PushOrUpdate(obj)
+	h.workloadQueues[key] = qKey
+	h.active.PushOrUpdate(lqHeap)
+}
+
+// PushIfNotPresent inserts a workload in the heap of its LocalQueue, unless
+// it's already present.
+func (h *localQueueHeaps) PushIfNotPresent(obj interface{}) bool {
+	key := h.keyFunc(obj)
+	if _, exists := h.workloadQueues[key]; exists {
+		return false
+	}
+	qKey := workload.QueueKey(obj.(*workload.Info).Obj)
+	lqHeap := h.heapFor(qKey)
+	lqHeap.PushIfNotPresent(obj)
+	h.workloadQueues[key] = qKey
+	h.active.PushOrUpdate(lqHeap)
+	return true
+}
+
+// Delete removes a workload.
+func (h *localQueueHeaps) Delete(key string) {
+	qKey, exists := h.workloadQueues[key]
+	if !exists {
+		return
+	}
+	delete(h.workloadQueues, key)
+	lqHeap := h.heaps[qKey]
+	lqHeap.Delete(key)
+	h.updateActive(lqHeap)
+}
+
+// Pop removes and returns the head of the LocalQueue heap with the lowest
+// pass. It returns nil if there are no workloads.
+func (h *localQueueHeaps) Pop() interface{} {
+	if h.active.Len() == 0 {
+		return nil
+	}
+	best := h.active.Peek().(*localQueueHeap)
+	h.globalPass = best.pass
+	best.pass += 1 / float64(h.weight(best.qKey))
+	obj := best.Pop()
+	delete(h.workloadQueues, h.keyFunc(obj))
+	h.updateActive(best)
+	return obj
+}
+
+// Sorted returns all the workloads in the order in which Pop would return
+// them, without modifying the heaps.
+func (h *localQueueHeaps) Sorted() []interface{} {
+	cursors := make([]*localQueueHeap, 0, h.active.Len())
+	for _, obj := range h.active.List() {
+		// Pop from copies of the heaps.
+		lqHeap := obj.(*localQueueHeap)
+		cursor := &localQueueHeap{
+			Heap: heap.New(h.keyFunc, h.lessFunc),
+			qKey: lqHeap.qKey,
+			pass: lqHeap.pass,
+		}
+		for _, item := range lqHeap.List() {
+			cursor.PushIfNotPresent(item)
+		}
+		cursors = append(cursors, cursor)
+	}
+	sorted := make([]interface{}, 0, h.Len())
+	for len(cursors) > 0 {
+		best := 0
+		for i := 1; i < len(cursors); i++ {
+			if h.goesBefore(cursors[i], cursors[best]) {
+				best = i
+			}
+		}
+		c := cursors[best]
+		sorted = append(sorted, c.Pop())
+		c.pass += 1 / float64(h.weight(c.qKey))
+		if c.Len() == 0 {
+			cursors = append(cursors[:best], cursors[best+1:]...)
+		}
+	}
+	return sorted
+}
+
+// Reorder restores the ordering of the heaps after the ordering of their
+// workloads changed.
+func (h *localQueueHeaps) Reorder() {
+	for _, lqHeap := range h.heaps {
+		lqHeap.Reorder()
+	}
+	h.active.Reorder()
+}
+
+// GetByKey returns the requested workload, or nil if it doesn't exist.
+func (h *localQueueHeaps) GetByKey(key string) interface{} {
+	qKey, exists := h.workloadQueues[key]
+	if !exists {
+		return nil
+	}
+	return h.heaps[qKey].GetByKey(key)
+}
+
+// Len returns the number of workloads in all the heaps.
+func (h *localQueueHeaps) Len() int {
+	return len(h.workloadQueues)
+}
+
+// List returns a list of all the workloads.
+func (h *localQueueHeaps) List() []interface{} {
+	list := make([]interface{}, 0, h.Len())
+	for _, lqHeap := range h.heaps {
+		list = append(list, lqHeap.List()...)
+	}
+	return list
+}
+
+func (h *localQueueHeaps) heapFor(qKey string) *localQueueHeap {
+	lqHeap := h.heaps[qKey]
+	if lqHeap == nil {
+		lqHeap = &localQueueHeap{
+			Heap: heap.New(h.keyFunc, h.lessFunc),
+			qKey: qKey,
+			pass: h.globalPass,
+		}
+		h.heaps[qKey] = lqHeap
+	} else if lqHeap.Len() == 0 && lqHeap.pass < h.globalPass {
+		lqHeap.pass = h.globalPass
+	}
+	return lqHeap
+}
+
+// updateActive restores the position of a LocalQueue heap in the active heap
+// after its pass or its head changed. Empty heaps are removed from the active
+// heap, and dropped if their LocalQueue is no longer registered.
+func (h *localQueueHeaps) updateActive(lqHeap *localQueueHeap) {
+	if lqHeap.Len() > 0 {
+		h.active.PushOrUpdate(lqHeap)
+		return
+	}
+	h.active.Delete(lqHeap.qKey)
+	if _, registered := h.localQueues[lqHeap.qKey]; !registered {
+		delete(h.heaps, lqHeap.qKey)
+	}
+}
+
+// goesBefore returns whether the heap a should be popped before the heap b.
+// The heap with the lowest pass goes first; ties are broken by comparing the
+// heads of the heaps and, lastly, the LocalQueue keys.
+func (h *localQueueHeaps) goesBefore(a, b *localQueueHeap) bool {
+	if a.pass != b.pass {
+		return a.pass < b.pass
+	}
+	aHead, bHead := a.Peek(), b.Peek()
+	if h.lessFunc(aHead, bHead) {
+		return true
+	}
+	if h.lessFunc(bHead, aHead) {
+		return false
+	}
+	return a.qKey < b.qKey
+}
+
+func (h *localQueueHeaps) weight(qKey string) int32 {
+	if q := h.localQueues[qKey]; q != nil && q.Weight > 0 {
+		return q.Weight
+	}
+	return 1
+}
@@ -20,12 +20,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"net/http"
 	"sync"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/metrics"
@@ -35,6 +39,8 @@ import (
 const (
 	workloadQueueKey     = "spec.queueName"
 	queueClusterQueueKey = "spec.clusterQueue"
+
+	allShards = -1
 )
 
 var (
@@ -43,26 +49,73 @@ var (
 	errClusterQueueAlreadyExists = errors.New("clusterQueue already exists")
 )
 
+type options struct {
+	workloadInfoOpts []workload.InfoOption
+	shards           int
+}
+
+// Option configures the manager.
+type Option func(*options)
+
+// WithWorkloadInfoOptions indicates how the requests of the pending workloads
+// are computed before they are considered for admission, such as the resource
+// transformations.
+func WithWorkloadInfoOptions(opts ...workload.InfoOption) Option {
+	return func(o *options) {
+		o.workloadInfoOpts = append(o.workloadInfoOpts, opts...)
+	}
+}
+
+// WithShards indicates the number of shards in which the ClusterQueues are
+// split, so that the heads of each shard can be obtained independently.
+// The ClusterQueues that belong to the same hierarchy of cohorts are always
+// in the same shard.
+func WithShards(n int) Option {
+	return func(o *options) {
+		o.shards = n
+	}
+}
+
+var defaultOptions = options{
+	shards: 1,
+}
+
 type Manager struct {
 	sync.RWMutex
 	cond sync.Cond
 
-	client        client.Client
-	statusChecker StatusChecker
-	clusterQueues map[string]ClusterQueue
-	localQueues   map[string]*LocalQueue
+	client           client.Client
+	statusChecker    StatusChecker
+	workloadInfoOpts []workload.InfoOption
+	clusterQueues    map[string]ClusterQueue
+	localQueues      map[string]*LocalQueue
 
 	// Key is cohort's name. Value is a set of associated ClusterQueue names.
 	cohorts map[string]sets.Set[string]
+	// Key is cohort's name. Value is the name of its parent cohort.
+	cohortParents map[string]string
+
+	shards int
+	// shardClusterQueues holds the names of the ClusterQueues of each shard.
+	// It's reset when the ClusterQueues or the cohorts change, and built
+	// again when the heads of a shard are requested.
+	shardClusterQueues [][]string
 }
 
-func NewManager(client client.Client, checker StatusChecker) *Manager {
+func NewManager(client client.Client, checker StatusChecker, opts ...Option) *Manager {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	m := &Manager{
-		client:        client,
-		statusChecker: checker,
-		localQueues:   make(map[string]*LocalQueue),
-		clusterQueues: make(map[string]ClusterQueue),
-		cohorts:       make(map[string]sets.Set[string]),
+		client:           client,
+		statusChecker:    checker,
+		workloadInfoOpts: options.workloadInfoOpts,
+		localQueues:      make(map[string]*LocalQueue),
+		clusterQueues:    make(map[string]ClusterQueue),
+		cohorts:          make(map[string]sets.Set[string]),
+		cohortParents:    make(map[string]string),
+		shards:           options.shards,
 	}
 	m.cond.L = &m.RWMutex
 	return m
@@ -81,6 +134,7 @@ func (m *Manager) AddClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) e
 		return err
 	}
 	m.clusterQueues[cq.Name] = cqImpl
+	m.shardClusterQueues = nil
 
 	cohort := cq.Spec.Cohort
 	if cohort != "" {
@@ -106,7 +160,7 @@ func (m *Manager) AddClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) e
 		}
 	}
 
-	queued := m.queueAllInadmissibleWorkloadsInCohort(ctx, cqImpl)
+	queued := m.queueAllInadmissibleWorkloadsInCohort(ctx, cqImpl, RequeueEvent{})
 	m.reportPendingWorkloads(cq.Name, cqImpl)
 	if queued || addedWorkloads {
 		m.Broadcast()
@@ -130,10 +184,11 @@ func (m *Manager) UpdateClusterQueue(ctx context.Context, cq *kueue.ClusterQueue
 	newCohort := cqImpl.Cohort()
 	if oldCohort != newCohort {
 		m.updateCohort(oldCohort, newCohort, cq.Name)
+		m.shardClusterQueues = nil
 	}
 
 	// TODO(#8): Selectively move workloads based on the exact event.
-	if m.queueAllInadmissibleWorkloadsInCohort(ctx, cqImpl) {
+	if m.queueAllInadmissibleWorkloadsInCohort(ctx, cqImpl, RequeueEvent{}) {
 		m.reportPendingWorkloads(cq.Name, cqImpl)
 		m.Broadcast()
 	}
@@ -149,6 +204,7 @@ func (m *Manager) DeleteClusterQueue(cq *kueue.ClusterQueue) {
 		return
 	}
 	delete(m.clusterQueues, cq.Name)
+	m.shardClusterQueues = nil
 	metrics.ClearQueueSystemMetrics(cq.Name)
 
 	cohort := cq.Spec.Cohort
@@ -174,10 +230,10 @@ func (m *Manager) AddLocalQueue(ctx context.Context, q *kueue.LocalQueue) error
 	for _, w := range workloads.Items {
 		w := w
 		// Checking queue name again because the field index is not available in tests.
-		if w.Spec.QueueName != q.Name || w.Spec.Admission != nil {
+		if w.Spec.QueueName != q.Name || (w.Spec.Admission != nil && !workload.HasPendingResize(&w)) || !workload.IsActive(&w) {
 			continue
 		}
-		qImpl.AddOrUpdate(workload.NewInfo(&w))
+		qImpl.AddOrUpdate(workload.NewInfo(&w, m.workloadInfoOpts...))
 	}
 	cq := m.clusterQueues[qImpl.ClusterQueue]
 	if cq != nil && cq.AddFromLocalQueue(qImpl) {
@@ -240,6 +296,43 @@ func (m *Manager) Pending(cq *kueue.ClusterQueue) int {
 	return m.clusterQueues[cq.Name].Pending()
 }
 
+// PendingWorkloadsHead returns up to maxCount pending workloads of the
+// ClusterQueue, in the order in which they would be considered for admission.
+func (m *Manager) PendingWorkloadsHead(cqName string, maxCount int) []*workload.Info {
+	m.RLock()
+	defer m.RUnlock()
+	cq := m.clusterQueues[cqName]
+	if cq == nil {
+		return nil
+	}
+	return cq.Snapshot(maxCount)
+}
+
+// PendingWorkloadsInfo returns all the pending workloads of the ClusterQueue,
+// in the order in which they would be considered for admission, and whether
+// the ClusterQueue exists.
+func (m *Manager) PendingWorkloadsInfo(cqName string) ([]*workload.Info, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	cq := m.clusterQueues[cqName]
+	if cq == nil {
+		return nil, false
+	}
+	return cq.Snapshot(cq.Pending()), true
+}
+
+// ClusterQueueFromLocalQueue returns the name of the ClusterQueue of the
+// LocalQueue with the given key, and whether the LocalQueue exists.
+func (m *Manager) ClusterQueueFromLocalQueue(lqKey string) (string, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	q, ok := m.localQueues[lqKey]
+	if !ok {
+		return "", false
+	}
+	return q.ClusterQueue, true
+}
+
 func (m *Manager) QueueForWorkloadExists(wl *kueue.Workload) bool {
 	m.RLock()
 	defer m.RUnlock()
@@ -261,6 +354,30 @@ func (m *Manager) ClusterQueueForWorkload(wl *kueue.Workload) (string, bool) {
 	return q.ClusterQueue, ok
 }
 
+// GroupMembers returns the workloads that belong to the same group as the
+// given workload and are pending in the given ClusterQueue, excluding the
+// given workload.
+func (m *Manager) GroupMembers(w *kueue.Workload, cqName string) []*workload.Info {
+	m.RLock()
+	defer m.RUnlock()
+	group := workload.GroupName(w)
+	key := workload.Key(w)
+	var members []*workload.Info
+	for _, q := range m.localQueues {
+		if q.ClusterQueue != cqName {
+			continue
+		}
+		for k, info := range q.items {
+			if k != key && info.Obj.Namespace == w.Namespace && workload.GroupName(info.Obj) == group {
+				infoCopy := *info
+				infoCopy.ClusterQueue = cqName
+				members = append(members, &infoCopy)
+			}
+		}
+	}
+	return members
+}
+
 // AddOrUpdateWorkload adds or updates workload to the corresponding queue.
 // Returns whether the queue existed.
 func (m *Manager) AddOrUpdateWorkload(w *kueue.Workload) bool {
@@ -275,7 +392,13 @@ func (m *Manager) addOrUpdateWorkload(w *kueue.Workload) bool {
 	if q == nil {
 		return false
 	}
-	wInfo := workload.NewInfo(w)
+	if !workload.IsActive(w) {
+		// Deactivated workloads are kept out of the queues until they are
+		// activated again.
+		m.deleteWorkloadFromQueueAndClusterQueue(w, qKey)
+		return true
+	}
+	wInfo := workload.NewInfo(w, m.workloadInfoOpts...)
 	q.AddOrUpdate(wInfo)
 	cq := m.clusterQueues[q.ClusterQueue]
 	if cq == nil {
@@ -288,7 +411,8 @@ func (m *Manager) addOrUpdateWorkload(w *kueue.Workload) bool {
 }
 
 // RequeueWorkload requeues the workload ensuring that the queue and the
-// workload still exist in the client cache and it's not admitted. It won't
+// workload still exist in the client cache, it's active and it's not admitted,
+// unless it has a pending resize. It won't
 // requeue if the workload is already in the queue (possible if the workload was updated).
 func (m *Manager) RequeueWorkload(ctx context.Context, info *workload.Info, reason RequeueReason) bool {
 	m.Lock()
@@ -298,7 +422,7 @@ func (m *Manager) RequeueWorkload(ctx context.Context, info *workload.Info, reas
 	// Always get the newest workload to avoid requeuing the out-of-date obj.
 	err := m.client.Get(ctx, client.ObjectKeyFromObject(info.Obj), &w)
 	// Since the client is cached, the only possible error is NotFound
-	if apierrors.IsNotFound(err) || w.Spec.Admission != nil {
+	if apierrors.IsNotFound(err) || (w.Spec.Admission != nil && !workload.HasPendingResize(&w)) || !workload.IsActive(&w) {
 		return false
 	}
 
@@ -306,6 +430,13 @@ func (m *Manager) RequeueWorkload(ctx context.Context, info *workload.Info, reas
 	if q == nil {
 		return false
 	}
+	// Keep the reasons why the workload is inadmissible and the backoff from
+	// the requeued object, as the update of its status might not be in the
+	// cache yet.
+	w.Status.InadmissibleReasons = info.Obj.Status.InadmissibleReasons
+	if now := time.Now(); workload.BackoffRemaining(info.Obj, now) > workload.BackoffRemaining(&w, now) {
+		w.Status.RequeueState = info.Obj.Status.RequeueState
+	}
 	info.Update(&w)
 	q.AddOrUpdate(info)
 	cq := m.clusterQueues[q.ClusterQueue]
@@ -340,9 +471,10 @@ func (m *Manager) deleteWorkloadFromQueueAndClusterQueue(w *kueue.Workload, qKey
 	}
 }
 
-// QueueAssociatedInadmissibleWorkloadsAfter requeues into the heaps all
+// QueueAssociatedInadmissibleWorkloadsAfter requeues into the heaps the
 // previously inadmissible workloads in the same ClusterQueue and cohort (if
-// they exist) as the provided admitted workload to the heaps.
+// they exist) as the provided admitted workload, if they were inadmissible
+// for lack of quota in the flavors that the workload releases.
 // An optional action can be executed at the beginning of the function,
 // while holding the lock, to provide atomicity with the operations in the
 // queues.
@@ -362,7 +494,7 @@ func (m *Manager) QueueAssociatedInadmissibleWorkloadsAfter(ctx context.Context,
 		return
 	}
 
-	if m.queueAllInadmissibleWorkloadsInCohort(ctx, cq) {
+	if m.queueAllInadmissibleWorkloadsInCohort(ctx, cq, releasedQuotaEvent(w)) {
 		m.Broadcast()
 	}
 }
@@ -383,7 +515,7 @@ func (m *Manager) QueueInadmissibleWorkloads(ctx context.Context, cqNames sets.S
 		if !exists {
 			continue
 		}
-		if m.queueAllInadmissibleWorkloadsInCohort(ctx, cq) {
+		if m.queueAllInadmissibleWorkloadsInCohort(ctx, cq, RequeueEvent{}) {
 			queued = true
 		}
 	}
@@ -394,30 +526,97 @@ func (m *Manager) QueueInadmissibleWorkloads(ctx context.Context, cqNames sets.S
 }
 
 // queueAllInadmissibleWorkloadsInCohort moves all workloads in the same
-// cohort with this ClusterQueue from inadmissibleWorkloads to heap. If the
-// cohort of this ClusterQueue is empty, it just moves all workloads in this
-// ClusterQueue. If at least one workload is moved, returns true. Otherwise
-// returns false.
+// cohort with this ClusterQueue for which the event is relevant from
+// inadmissibleWorkloads to heap. If the cohort of this ClusterQueue is empty,
+// it just moves the workloads in this ClusterQueue. If at least one workload
+// is moved, returns true. Otherwise returns false.
 // The events listed below could make workloads in the same cohort admissible.
 // Then queueAllInadmissibleWorkloadsInCohort need to be invoked.
 // 1. delete events for any admitted workload in the cohort.
 // 2. add events of any cluster queue in the cohort.
 // 3. update events of any cluster queue in the cohort.
-func (m *Manager) queueAllInadmissibleWorkloadsInCohort(ctx context.Context, cq ClusterQueue) bool {
+// The cohort includes all the cohorts in the same hierarchy of cohorts.
+func (m *Manager) queueAllInadmissibleWorkloadsInCohort(ctx context.Context, cq ClusterQueue, event RequeueEvent) bool {
 	cohort := cq.Cohort()
 	if cohort == "" {
-		return cq.QueueInadmissibleWorkloads(ctx, m.client)
+		return cq.QueueInadmissibleWorkloads(ctx, m.client, event)
 	}
+	return m.queueAllInadmissibleWorkloadsInHierarchy(ctx, cohort, event)
+}
 
+// queueAllInadmissibleWorkloadsInHierarchy moves all workloads in the
+// ClusterQueues under the root of the hierarchy of the cohort for which the
+// event is relevant from inadmissibleWorkloads to heap. If at least one workload is moved, returns
+// true. Otherwise returns false.
+func (m *Manager) queueAllInadmissibleWorkloadsInHierarchy(ctx context.Context, cohort string, event RequeueEvent) bool {
+	root := m.rootCohort(cohort)
 	queued := false
-	for cqName := range m.cohorts[cohort] {
-		if clusterQueue, ok := m.clusterQueues[cqName]; ok {
-			queued = clusterQueue.QueueInadmissibleWorkloads(ctx, m.client) || queued
+	for c, cqNames := range m.cohorts {
+		if c != cohort && m.rootCohort(c) != root {
+			continue
+		}
+		for cqName := range cqNames {
+			if clusterQueue, ok := m.clusterQueues[cqName]; ok {
+				queued = clusterQueue.QueueInadmissibleWorkloads(ctx, m.client, event) || queued
+			}
 		}
 	}
 	return queued
 }
 
+// rootCohort returns the root of the hierarchy of cohorts that the cohort
+// belongs to.
+func (m *Manager) rootCohort(cohort string) string {
+	visited := sets.New(cohort)
+	for {
+		parent := m.cohortParents[cohort]
+		if parent == "" || visited.Has(parent) {
+			return cohort
+		}
+		visited.Insert(parent)
+		cohort = parent
+	}
+}
+
+// AddOrUpdateCohort sets the parent of the cohort with the name of the given
+// Cohort object and moves the inadmissible workloads in its hierarchy back to
+// the heaps, as the quotas in the hierarchy could have changed.
+func (m *Manager) AddOrUpdateCohort(ctx context.Context, cohort *kueue.Cohort) {
+	m.Lock()
+	defer m.Unlock()
+	oldRoot := m.rootCohort(cohort.Name)
+	if cohort.Spec.Parent == "" {
+		delete(m.cohortParents, cohort.Name)
+	} else {
+		m.cohortParents[cohort.Name] = cohort.Spec.Parent
+	}
+	m.shardClusterQueues = nil
+	queued := m.queueAllInadmissibleWorkloadsInHierarchy(ctx, cohort.Name, RequeueEvent{})
+	if oldRoot != m.rootCohort(cohort.Name) {
+		queued = m.queueAllInadmissibleWorkloadsInHierarchy(ctx, oldRoot, RequeueEvent{}) || queued
+	}
+	if queued {
+		m.Broadcast()
+	}
+}
+
+// DeleteCohort removes the parent of the cohort with the name of the given
+// Cohort object.
+func (m *Manager) DeleteCohort(ctx context.Context, cohort *kueue.Cohort) {
+	m.Lock()
+	defer m.Unlock()
+	root := m.rootCohort(cohort.Name)
+	delete(m.cohortParents, cohort.Name)
+	m.shardClusterQueues = nil
+	queued := m.queueAllInadmissibleWorkloadsInHierarchy(ctx, cohort.Name, RequeueEvent{})
+	if root != cohort.Name {
+		queued = m.queueAllInadmissibleWorkloadsInHierarchy(ctx, root, RequeueEvent{}) || queued
+	}
+	if queued {
+		m.Broadcast()
+	}
+}
+
 // UpdateWorkload updates the workload to the corresponding queue or adds it if
 // it didn't exist. Returns whether the queue existed.
 func (m *Manager) UpdateWorkload(oldW, w *kueue.Workload) bool {
@@ -437,14 +636,64 @@ func (m *Manager) CleanUpOnContext(ctx context.Context) {
 	m.Broadcast()
 }
 
+// ReadyzCheck returns a checker that fails until the manager holds all the
+// ClusterQueues and LocalQueues of the cluster, that is, until the pending
+// workloads are queued again after a restart. Listing the queues from the
+// informer cache also waits for the cache to be synced.
+func (m *Manager) ReadyzCheck() healthz.Checker {
+	return func(req *http.Request) error {
+		return m.queuesSynced(req.Context())
+	}
+}
+
+func (m *Manager) queuesSynced(ctx context.Context) error {
+	var cqs kueue.ClusterQueueList
+	if err := m.client.List(ctx, &cqs); err != nil {
+		return fmt.Errorf("listing the ClusterQueues: %w", err)
+	}
+	var lqs kueue.LocalQueueList
+	if err := m.client.List(ctx, &lqs); err != nil {
+		return fmt.Errorf("listing the LocalQueues: %w", err)
+	}
+	m.RLock()
+	defer m.RUnlock()
+	for i := range cqs.Items {
+		if _, ok := m.clusterQueues[cqs.Items[i].Name]; !ok {
+			return fmt.Errorf("ClusterQueue %q is not queued yet", cqs.Items[i].Name)
+		}
+	}
+	for i := range lqs.Items {
+		if key := Key(&lqs.Items[i]); m.localQueues[key] == nil {
+			return fmt.Errorf("LocalQueue %q is not queued yet", key)
+		}
+	}
+	return nil
+}
+
 // Heads returns the heads of the queues, along with their associated ClusterQueue.
 // It blocks if the queues empty until they have elements or the context terminates.
 func (m *Manager) Heads(ctx context.Context) []workload.Info {
+	return m.headsOfShard(ctx, allShards)
+}
+
+// Shards returns the number of shards in which the ClusterQueues are split.
+func (m *Manager) Shards() int {
+	return m.shards
+}
+
+// HeadsOfShard returns the heads of the queues of the ClusterQueues in the
+// shard, along with their associated ClusterQueue. It blocks if the queues
+// of the shard are empty until they have elements or the context terminates.
+func (m *Manager) HeadsOfShard(ctx context.Context, shard int) []workload.Info {
+	return m.headsOfShard(ctx, shard)
+}
+
+func (m *Manager) headsOfShard(ctx context.Context, shard int) []workload.Info {
 	m.Lock()
 	defer m.Unlock()
 	log := ctrl.LoggerFrom(ctx)
 	for {
-		workloads := m.heads()
+		workloads := m.heads(shard)
 		log.V(3).Info("Obtained ClusterQueue heads", "count", len(workloads))
 		if len(workloads) != 0 {
 			return workloads
@@ -498,9 +747,10 @@ func (m *Manager) DumpInadmissible() map[string]sets.Set[string] {
 	return dump
 }
 
-func (m *Manager) heads() []workload.Info {
+func (m *Manager) heads(shard int) []workload.Info {
 	var workloads []workload.Info
-	for cqName, cq := range m.clusterQueues {
+	for _, cqName := range m.clusterQueuesOfShard(shard) {
+		cq := m.clusterQueues[cqName]
 		// Cache might be nil in tests, if cache is nil, we'll skip the check.
 		if m.statusChecker != nil && !m.statusChecker.ClusterQueueActive(cqName) {
 			continue
@@ -519,6 +769,42 @@ func (m *Manager) heads() []workload.Info {
 	return workloads
 }
 
+// clusterQueuesOfShard returns the names of the ClusterQueues in the shard,
+// or of all the ClusterQueues for allShards.
+func (m *Manager) clusterQueuesOfShard(shard int) []string {
+	if shard == allShards {
+		names := make([]string, 0, len(m.clusterQueues))
+		for name := range m.clusterQueues {
+			names = append(names, name)
+		}
+		return names
+	}
+	if m.shardClusterQueues == nil {
+		m.shardClusterQueues = make([][]string, m.shards)
+		for name, cq := range m.clusterQueues {
+			s := m.shardOf(name, cq)
+			m.shardClusterQueues[s] = append(m.shardClusterQueues[s], name)
+		}
+	}
+	return m.shardClusterQueues[shard]
+}
+
+// shardOf returns the shard of the ClusterQueue, which is given by the root of
+// its hierarchy of cohorts, so that the ClusterQueues that can share quota are
+// in the same shard.
+func (m *Manager) shardOf(cqName string, cq ClusterQueue) int {
+	if m.shards <= 1 {
+		return 0
+	}
+	key := cqName
+	if cohort := cq.Cohort(); cohort != "" {
+		key = m.rootCohort(cohort)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(m.shards))
+}
+
 func (m *Manager) addCohort(cohort string, cqName string) {
 	if m.cohorts[cohort] == nil {
 		m.cohorts[cohort] = make(sets.Set[string])
@@ -19,6 +19,9 @@ package queue
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -29,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -66,6 +70,36 @@ func TestAddLocalQueueOrphans(t *testing.T) {
 	}
 }
 
+func TestReadyzCheck(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %s", err)
+	}
+	cq := utiltesting.MakeClusterQueue("cq").Obj()
+	q := utiltesting.MakeLocalQueue("foo", "earth").ClusterQueue("cq").Obj()
+	kClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cq, q).Build()
+	manager := NewManager(kClient, nil)
+	ctx := context.Background()
+	check := manager.ReadyzCheck()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx)
+
+	if err := check(req); err == nil {
+		t.Error("The check passed before the ClusterQueue was added")
+	}
+	if err := manager.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	if err := check(req); err == nil {
+		t.Error("The check passed before the LocalQueue was added")
+	}
+	if err := manager.AddLocalQueue(ctx, q); err != nil {
+		t.Fatalf("Failed adding queue: %v", err)
+	}
+	if err := check(req); err != nil {
+		t.Errorf("The check failed after the queues were added: %v", err)
+	}
+}
+
 // TestAddClusterQueueOrphans verifies that when a ClusterQueue is recreated,
 // it adopts the existing workloads.
 func TestAddClusterQueueOrphans(t *testing.T) {
@@ -541,6 +575,47 @@ func TestRequeueWorkloadStrictFIFO(t *testing.T) {
 	}
 }
 
+func TestRequeueWorkloadWithBackoff(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %s", err)
+	}
+	ctx := context.Background()
+	cq := utiltesting.MakeClusterQueue("cq").QueueingStrategy(kueue.StrictFIFO).Obj()
+	q := utiltesting.MakeLocalQueue("foo", "").ClusterQueue("cq").Obj()
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	manager := NewManager(cl, nil)
+	if err := manager.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding cluster queue %s: %v", cq.Name, err)
+	}
+	if err := manager.AddLocalQueue(ctx, q); err != nil {
+		t.Fatalf("Failed adding queue %s: %v", q.Name, err)
+	}
+	wl := utiltesting.MakeWorkload("a", "").Queue("foo").Obj()
+	if err := cl.Create(ctx, wl); err != nil {
+		t.Fatalf("Failed adding workload to client: %v", err)
+	}
+
+	// The client doesn't have the requeue state yet.
+	requeued := wl.DeepCopy()
+	requeued.Status.RequeueState = &kueue.RequeueState{
+		Count:     pointer.Int32(1),
+		RequeueAt: &metav1.Time{Time: time.Now().Add(time.Minute)},
+	}
+	if !manager.RequeueWorkload(ctx, workload.NewInfo(requeued), RequeueReasonGeneric) {
+		t.Error("RequeueWorkload returned false, want true")
+	}
+	wantInadmissible := map[string]sets.Set[string]{
+		"cq": sets.New(workload.Key(wl)),
+	}
+	if diff := cmp.Diff(wantInadmissible, manager.DumpInadmissible()); diff != "" {
+		t.Errorf("Unexpected inadmissible workloads (-want,+got):\n%s", diff)
+	}
+	if dump := manager.Dump(); len(dump) != 0 {
+		t.Errorf("Unexpected workloads in the heap: %v", dump)
+	}
+}
+
 func TestUpdateWorkload(t *testing.T) {
 	scheme := runtime.NewScheme()
 	if err := kueue.AddToScheme(scheme); err != nil {
@@ -578,6 +653,28 @@ func TestUpdateWorkload(t *testing.T) {
 				"/foo": sets.New("/a", "/b"),
 			},
 		},
+		"priority changed in queue": {
+			clusterQueues: []*kueue.ClusterQueue{
+				utiltesting.MakeClusterQueue("cq").Obj(),
+			},
+			queues: []*kueue.LocalQueue{
+				utiltesting.MakeLocalQueue("foo", "").ClusterQueue("cq").Obj(),
+			},
+			workloads: []*kueue.Workload{
+				utiltesting.MakeWorkload("a", "").Queue("foo").Priority(0).Creation(now).Obj(),
+				utiltesting.MakeWorkload("b", "").Queue("foo").Priority(10).Creation(now).Obj(),
+			},
+			update: func(w *kueue.Workload) {
+				w.Spec.Priority = pointer.Int32(20)
+			},
+			wantUpdated: true,
+			wantQueueOrder: map[string][]string{
+				"cq": {"/a", "/b"},
+			},
+			wantQueueMembers: map[string]sets.Set[string]{
+				"/foo": sets.New("/a", "/b"),
+			},
+		},
 		"between queues": {
 			clusterQueues: []*kueue.ClusterQueue{
 				utiltesting.MakeClusterQueue("cq").Obj(),
@@ -727,6 +824,44 @@ func TestUpdateWorkload(t *testing.T) {
 	}
 }
 
+func TestDeactivateWorkload(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %s", err)
+	}
+	ctx := context.Background()
+	manager := NewManager(fake.NewClientBuilder().WithScheme(scheme).Build(), nil)
+	if err := manager.AddClusterQueue(ctx, utiltesting.MakeClusterQueue("cq").Obj()); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	if err := manager.AddLocalQueue(ctx, utiltesting.MakeLocalQueue("foo", "").ClusterQueue("cq").Obj()); err != nil {
+		t.Fatalf("Failed adding queue: %v", err)
+	}
+	wl := utiltesting.MakeWorkload("a", "").Queue("foo").Obj()
+	if !manager.AddOrUpdateWorkload(wl) {
+		t.Fatalf("Failed adding workload")
+	}
+
+	inactiveWl := wl.DeepCopy()
+	inactiveWl.Spec.Active = pointer.Bool(false)
+	if !manager.UpdateWorkload(wl, inactiveWl) {
+		t.Errorf("UpdateWorkload returned false for an existing queue")
+	}
+	if dump := manager.Dump(); dump != nil {
+		t.Errorf("Deactivated workload still in the queues: %v", dump)
+	}
+
+	activeWl := inactiveWl.DeepCopy()
+	activeWl.Spec.Active = pointer.Bool(true)
+	if !manager.UpdateWorkload(inactiveWl, activeWl) {
+		t.Errorf("UpdateWorkload returned false for an existing queue")
+	}
+	wantDump := map[string]sets.Set[string]{"cq": sets.New("/a")}
+	if diff := cmp.Diff(wantDump, manager.Dump()); diff != "" {
+		t.Errorf("Unexpected workloads in the queues after reactivation (-want,+got):\n%s", diff)
+	}
+}
+
 func TestHeads(t *testing.T) {
 	scheme := runtime.NewScheme()
 	if err := kueue.AddToScheme(scheme); err != nil {
@@ -815,6 +950,62 @@ func TestHeads(t *testing.T) {
 	}
 }
 
+// TestHeadsOfShard ensures that the heads of each ClusterQueue are returned
+// for exactly one shard, and that the ClusterQueues in the same hierarchy of
+// cohorts are in the same shard.
+func TestHeadsOfShard(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), headsTimeout)
+	defer cancel()
+	manager := NewManager(fake.NewClientBuilder().WithScheme(scheme).Build(), nil, WithShards(4))
+	go manager.CleanUpOnContext(ctx)
+	clusterQueues := []*kueue.ClusterQueue{
+		utiltesting.MakeClusterQueue("alpha-1").Cohort("alpha").Obj(),
+		utiltesting.MakeClusterQueue("alpha-2").Cohort("alpha").Obj(),
+		utiltesting.MakeClusterQueue("beta").Cohort("beta").Obj(),
+	}
+	for i := 0; i < 8; i++ {
+		clusterQueues = append(clusterQueues, utiltesting.MakeClusterQueue(fmt.Sprintf("standalone-%d", i)).Obj())
+	}
+	for _, cq := range clusterQueues {
+		if err := manager.AddClusterQueue(ctx, cq); err != nil {
+			t.Fatalf("Failed adding clusterQueue %s to manager: %v", cq.Name, err)
+		}
+		q := utiltesting.MakeLocalQueue(cq.Name, "").ClusterQueue(cq.Name).Obj()
+		if err := manager.AddLocalQueue(ctx, q); err != nil {
+			t.Fatalf("Failed adding queue %s: %s", q.Name, err)
+		}
+		manager.AddOrUpdateWorkload(utiltesting.MakeWorkload(cq.Name, "").Queue(cq.Name).Obj())
+	}
+	// The beta cohort joins the hierarchy of alpha.
+	manager.AddOrUpdateCohort(ctx, &kueue.Cohort{
+		ObjectMeta: metav1.ObjectMeta{Name: "beta"},
+		Spec:       kueue.CohortSpec{Parent: "alpha"},
+	})
+
+	gotShards := make(map[string]int)
+	for shard := 0; shard < manager.Shards(); shard++ {
+		if len(manager.clusterQueuesOfShard(shard)) == 0 {
+			continue
+		}
+		for _, h := range manager.HeadsOfShard(ctx, shard) {
+			if prev, found := gotShards[h.ClusterQueue]; found {
+				t.Errorf("Got the head of %s in shards %d and %d", h.ClusterQueue, prev, shard)
+			}
+			gotShards[h.ClusterQueue] = shard
+		}
+	}
+	if len(gotShards) != len(clusterQueues) {
+		t.Errorf("Got the heads of %d ClusterQueues, want %d", len(gotShards), len(clusterQueues))
+	}
+	if gotShards["alpha-1"] != gotShards["alpha-2"] || gotShards["alpha-1"] != gotShards["beta"] {
+		t.Errorf("The ClusterQueues of the alpha hierarchy are in different shards: %v", gotShards)
+	}
+}
+
 var ignoreTypeMeta = cmpopts.IgnoreTypes(metav1.TypeMeta{})
 
 // TestHeadAsync ensures that Heads call is blocked until the queues are filled
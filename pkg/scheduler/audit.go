@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/kueue/pkg/metrics"
+	"sigs.k8s.io/kueue/pkg/util/api"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+const (
+	// DecisionAnnotation is the annotation of the AdmissionDecision events
+	// that holds the decision as JSON.
+	DecisionAnnotation = "kueue.x-k8s.io/admission-decision"
+
+	decisionEventReason = "AdmissionDecision"
+)
+
+// Decision is the outcome of the evaluation of a workload in a scheduling
+// cycle, with the reasoning behind it.
+type Decision struct {
+	Time         time.Time                `json:"time"`
+	Workload     string                   `json:"workload"`
+	UID          types.UID                `json:"uid"`
+	ClusterQueue string                   `json:"clusterQueue"`
+	Result       metrics.SchedulingResult `json:"result"`
+	Message      string                   `json:"message,omitempty"`
+	// PodSets are the flavors assigned to the resources of each pod set.
+	PodSets []PodSetDecision `json:"podSets,omitempty"`
+	// Borrowing is the quota that the workload borrows from the cohort.
+	Borrowing []BorrowedQuota `json:"borrowing,omitempty"`
+	// Victims are the workloads that were preempted to make room for the
+	// workload.
+	Victims []string `json:"victims,omitempty"`
+}
+
+type PodSetDecision struct {
+	Name    string                                 `json:"name"`
+	Count   int32                                  `json:"count"`
+	Flavors map[corev1.ResourceName]FlavorDecision `json:"flavors,omitempty"`
+}
+
+type FlavorDecision struct {
+	Flavor string `json:"flavor"`
+	// Mode is Fit, Preempt or NoFit.
+	Mode string `json:"mode"`
+}
+
+type BorrowedQuota struct {
+	Resource corev1.ResourceName `json:"resource"`
+	Flavor   string              `json:"flavor"`
+	Quantity resource.Quantity   `json:"quantity"`
+}
+
+// auditor records the admission decisions as events of the workloads and,
+// optionally, as JSON lines in a decision log.
+type auditor struct {
+	recorder record.EventRecorder
+
+	mu          sync.Mutex
+	decisionLog io.Writer
+}
+
+// decision returns the decision for the entry, evaluated at the given time.
+func (e *entry) decision(now time.Time) *Decision {
+	d := &Decision{
+		Time:         now,
+		Workload:     workload.Key(e.Obj),
+		UID:          e.Obj.UID,
+		ClusterQueue: e.ClusterQueue,
+		Result:       e.schedulingResult(),
+		Message:      e.inadmissibleMsg,
+	}
+	for _, ps := range e.assignment.PodSets {
+		psd := PodSetDecision{Name: ps.Name, Count: ps.Count}
+		if len(ps.Flavors) > 0 {
+			psd.Flavors = make(map[corev1.ResourceName]FlavorDecision, len(ps.Flavors))
+			for res, flv := range ps.Flavors {
+				psd.Flavors[res] = FlavorDecision{Flavor: flv.Name, Mode: flv.Mode.String()}
+			}
+		}
+		d.PodSets = append(d.PodSets, psd)
+	}
+	for res, flavors := range e.assignment.TotalBorrow {
+		for flavor, v := range flavors {
+			d.Borrowing = append(d.Borrowing, BorrowedQuota{
+				Resource: res,
+				Flavor:   flavor,
+				Quantity: workload.ResourceQuantity(res, v),
+			})
+		}
+	}
+	sort.Slice(d.Borrowing, func(i, j int) bool {
+		if d.Borrowing[i].Resource != d.Borrowing[j].Resource {
+			return d.Borrowing[i].Resource < d.Borrowing[j].Resource
+		}
+		return d.Borrowing[i].Flavor < d.Borrowing[j].Flavor
+	})
+	for _, v := range e.preempted {
+		d.Victims = append(d.Victims, workload.Key(v.Obj))
+	}
+	return d
+}
+
+// record emits an AdmissionDecision event for the workload of the entry, with
+// the decision in an annotation, and appends the decision to the decision
+// log, if any.
+func (a *auditor) record(e *entry, now time.Time) error {
+	d := e.decision(now)
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	msg := string(d.Result) + " in ClusterQueue " + d.ClusterQueue
+	if d.Message != "" {
+		msg += ": " + d.Message
+	}
+	a.recorder.AnnotatedEventf(e.Obj, map[string]string{DecisionAnnotation: string(data)},
+		corev1.EventTypeNormal, decisionEventReason, "%s", api.TruncateEventMessage(msg))
+	if a.decisionLog == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.decisionLog.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/metrics"
+	"sigs.k8s.io/kueue/pkg/queue"
+	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+func TestAuditorRecord(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		entry        entry
+		wantDecision Decision
+		wantEvent    string
+	}{
+		"admitted with borrowing": {
+			entry: entry{
+				Info:   *workload.NewInfo(utiltesting.MakeWorkload("foo", "sales").Obj()),
+				status: assumed,
+				assignment: flavorassigner.Assignment{
+					PodSets: []flavorassigner.PodSetAssignment{{
+						Name:  "main",
+						Count: 2,
+						Flavors: flavorassigner.ResourceAssignment{
+							corev1.ResourceCPU: {Name: "on-demand", Mode: flavorassigner.Fit},
+						},
+					}},
+					TotalBorrow: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"on-demand": 2000},
+					},
+				},
+			},
+			wantDecision: Decision{
+				Time:         now,
+				Workload:     "sales/foo",
+				ClusterQueue: "sales",
+				Result:       metrics.SchedulingResultAdmitted,
+				PodSets: []PodSetDecision{{
+					Name:  "main",
+					Count: 2,
+					Flavors: map[corev1.ResourceName]FlavorDecision{
+						corev1.ResourceCPU: {Flavor: "on-demand", Mode: "Fit"},
+					},
+				}},
+				Borrowing: []BorrowedQuota{
+					{Resource: corev1.ResourceCPU, Flavor: "on-demand", Quantity: resource.MustParse("2")},
+				},
+			},
+			wantEvent: "Normal AdmissionDecision admitted in ClusterQueue sales",
+		},
+		"preempting": {
+			entry: entry{
+				Info:            *workload.NewInfo(utiltesting.MakeWorkload("foo", "sales").Obj()),
+				status:          nominated,
+				requeueReason:   queue.RequeueReasonPendingPreemption,
+				inadmissibleMsg: "insufficient unused quota for cpu in flavor on-demand. Preempted 1 workload(s)",
+				assignment: flavorassigner.Assignment{
+					PodSets: []flavorassigner.PodSetAssignment{{
+						Name:  "main",
+						Count: 1,
+						Flavors: flavorassigner.ResourceAssignment{
+							corev1.ResourceCPU: {Name: "on-demand", Mode: flavorassigner.Preempt},
+						},
+					}},
+				},
+				preempted: []*workload.Info{
+					workload.NewInfo(utiltesting.MakeWorkload("low", "sales").Obj()),
+				},
+			},
+			wantDecision: Decision{
+				Time:         now,
+				Workload:     "sales/foo",
+				ClusterQueue: "sales",
+				Result:       metrics.SchedulingResultPreempting,
+				Message:      "insufficient unused quota for cpu in flavor on-demand. Preempted 1 workload(s)",
+				PodSets: []PodSetDecision{{
+					Name:  "main",
+					Count: 1,
+					Flavors: map[corev1.ResourceName]FlavorDecision{
+						corev1.ResourceCPU: {Flavor: "on-demand", Mode: "Preempt"},
+					},
+				}},
+				Victims: []string{"sales/low"},
+			},
+			wantEvent: "Normal AdmissionDecision preempting in ClusterQueue sales: insufficient unused quota for cpu in flavor on-demand. Preempted 1 workload(s)",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.entry.ClusterQueue = "sales"
+			recorder := record.NewFakeRecorder(1)
+			var decisionLog bytes.Buffer
+			a := &auditor{recorder: recorder, decisionLog: &decisionLog}
+			if err := a.record(&tc.entry, now); err != nil {
+				t.Fatalf("record failed: %v", err)
+			}
+
+			var got Decision
+			if err := json.Unmarshal(decisionLog.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshalling the decision log: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantDecision, got); diff != "" {
+				t.Errorf("Unexpected decision (-want,+got):\n%s", diff)
+			}
+			select {
+			case event := <-recorder.Events:
+				if diff := cmp.Diff(tc.wantEvent, event); diff != "" {
+					t.Errorf("Unexpected event (-want,+got):\n%s", diff)
+				}
+			default:
+				t.Error("No event was recorded")
+			}
+		})
+	}
+}
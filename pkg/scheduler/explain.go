@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
+	"sigs.k8s.io/kueue/pkg/scheduler/preemption"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// ErrWorkloadAdmitted is returned when explaining the admission of a workload
+// that is already admitted.
+var ErrWorkloadAdmitted = errors.New("the workload is already admitted")
+
+// Explanation is the evaluation of a pending workload for admission against
+// the current usage of the quota, as done in a scheduling cycle.
+type Explanation struct {
+	// ClusterQueue is the ClusterQueue in which the workload is pending, or
+	// empty if its LocalQueue doesn't exist.
+	ClusterQueue string
+	// Assignment holds the flavors that the workload would be assigned and the
+	// reasons why the other flavors don't fit.
+	Assignment flavorassigner.Assignment
+	// Message explains why the workload can't be admitted, empty if it fits.
+	Message string
+	// PreemptionTargets are the workloads that would be preempted to admit the
+	// workload.
+	PreemptionTargets []*workload.Info
+}
+
+// Explain evaluates the workload for admission against a snapshot of the
+// cache, without waiting for the next scheduling cycle. The workload is
+// neither admitted nor requeued, and no workloads are preempted.
+func (s *Scheduler) Explain(ctx context.Context, key types.NamespacedName) (*Explanation, error) {
+	var wl kueue.Workload
+	if err := s.client.Get(ctx, key, &wl); err != nil {
+		return nil, err
+	}
+	if wl.Spec.Admission != nil && !workload.HasPendingResize(&wl) {
+		return nil, ErrWorkloadAdmitted
+	}
+	cqName, _ := s.queues.ClusterQueueForWorkload(&wl)
+	if cqName == "" {
+		return &Explanation{Message: fmt.Sprintf("LocalQueue %s doesn't exist", wl.Spec.QueueName)}, nil
+	}
+	log := ctrl.LoggerFrom(ctx).WithName("scheduler").WithValues("workload", klog.KObj(&wl), "clusterQueue", klog.KRef("", cqName))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	info := workload.NewInfo(&wl, s.workloadInfoOpts...)
+	info.ClusterQueue = cqName
+	snapshot := s.cache.Snapshot()
+	e := s.nominateWorkload(ctx, *info, snapshot, time.Now())
+	explanation := &Explanation{
+		ClusterQueue: cqName,
+		Assignment:   e.assignment,
+		Message:      e.inadmissibleMsg,
+	}
+	if e.assignment.RepresentativeMode() == flavorassigner.Preempt && !e.isResize() {
+		explanation.PreemptionTargets = preemption.Targets(ctx, *e.admissionInfo(), e.assignment, &snapshot)
+		if len(explanation.PreemptionTargets) == 0 {
+			explanation.Message += ". Preempting the workloads allowed for preemption wouldn't free enough quota"
+		}
+	}
+	return explanation, nil
+}
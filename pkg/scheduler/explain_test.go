@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/queue"
+	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+func TestExplain(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	cq := utiltesting.MakeClusterQueue("cq").
+		NamespaceSelector(&metav1.LabelSelector{}).
+		Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+			Flavor(utiltesting.MakeFlavor("default", "4").Obj()).
+			Obj()).
+		Preemption(kueue.ClusterQueuePreemption{
+			WithinClusterQueue: kueue.PreemptionPolicyLowerPriority,
+		}).
+		Obj()
+	lq := utiltesting.MakeLocalQueue("main", "default").ClusterQueue("cq").Obj()
+	admitted := utiltesting.MakeWorkload("low", "default").
+		Queue("main").
+		Request(corev1.ResourceCPU, "3").
+		Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "default").Obj()).
+		Obj()
+
+	cases := map[string]struct {
+		workload        *kueue.Workload
+		wantErr         error
+		wantNotFound    bool
+		wantCQ          string
+		wantMode        flavorassigner.FlavorAssignmentMode
+		wantFlavors     []kueue.PodSetFlavors
+		wantMessage     string
+		wantPreemptions []string
+	}{
+		"fits": {
+			workload: utiltesting.MakeWorkload("foo", "default").
+				Queue("main").
+				Request(corev1.ResourceCPU, "1").
+				Obj(),
+			wantCQ:   "cq",
+			wantMode: flavorassigner.Fit,
+			wantFlavors: []kueue.PodSetFlavors{
+				{Name: "main", Flavors: map[corev1.ResourceName]string{corev1.ResourceCPU: "default"}},
+			},
+		},
+		"requires preemption": {
+			workload: utiltesting.MakeWorkload("foo", "default").
+				Queue("main").
+				Priority(10).
+				Request(corev1.ResourceCPU, "2").
+				Obj(),
+			wantCQ:   "cq",
+			wantMode: flavorassigner.Preempt,
+			wantFlavors: []kueue.PodSetFlavors{
+				{Name: "main", Flavors: map[corev1.ResourceName]string{corev1.ResourceCPU: "default"}},
+			},
+			wantMessage:     "couldn't assign flavors to pod set main: insufficient unused quota for cpu flavor default, 1 more needed",
+			wantPreemptions: []string{"default/low"},
+		},
+		"preemption doesn't free enough quota": {
+			workload: utiltesting.MakeWorkload("foo", "default").
+				Queue("main").
+				Request(corev1.ResourceCPU, "2").
+				Obj(),
+			wantCQ:   "cq",
+			wantMode: flavorassigner.Preempt,
+			wantFlavors: []kueue.PodSetFlavors{
+				{Name: "main", Flavors: map[corev1.ResourceName]string{corev1.ResourceCPU: "default"}},
+			},
+			wantMessage: "couldn't assign flavors to pod set main: insufficient unused quota for cpu flavor default, 1 more needed. Preempting the workloads allowed for preemption wouldn't free enough quota",
+		},
+		"doesn't fit": {
+			workload: utiltesting.MakeWorkload("foo", "default").
+				Queue("main").
+				Request(corev1.ResourceCPU, "5").
+				Obj(),
+			wantCQ:   "cq",
+			wantMode: flavorassigner.NoFit,
+			wantFlavors: []kueue.PodSetFlavors{
+				{Name: "main"},
+			},
+			wantMessage: "couldn't assign flavors to pod set main: insufficient quota for cpu flavor default in ClusterQueue",
+		},
+		"missing LocalQueue": {
+			workload: utiltesting.MakeWorkload("foo", "default").
+				Queue("other").
+				Request(corev1.ResourceCPU, "1").
+				Obj(),
+			wantMode:    flavorassigner.NoFit,
+			wantMessage: "LocalQueue other doesn't exist",
+		},
+		"admitted": {
+			workload: admitted,
+			wantErr:  ErrWorkloadAdmitted,
+		},
+		"not found": {
+			wantNotFound: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			builder := fake.NewClientBuilder().
+				WithScheme(utiltesting.MustGetScheme(t)).
+				WithObjects(ns).
+				WithLists(&kueue.WorkloadList{Items: []kueue.Workload{*admitted}})
+			key := types.NamespacedName{Namespace: "default", Name: "foo"}
+			if tc.workload != nil {
+				key = types.NamespacedName{Namespace: tc.workload.Namespace, Name: tc.workload.Name}
+				if tc.workload != admitted {
+					builder = builder.WithObjects(tc.workload)
+				}
+			}
+			cl := builder.Build()
+			cqCache := cache.New(cl)
+			cqCache.AddOrUpdateResourceFlavor(utiltesting.MakeResourceFlavor("default").Obj())
+			if err := cqCache.AddClusterQueue(ctx, cq); err != nil {
+				t.Fatalf("Inserting clusterQueue in cache: %v", err)
+			}
+			qManager := queue.NewManager(cl, cqCache)
+			if err := qManager.AddClusterQueue(ctx, cq); err != nil {
+				t.Fatalf("Inserting clusterQueue in manager: %v", err)
+			}
+			if err := qManager.AddLocalQueue(ctx, lq); err != nil {
+				t.Fatalf("Inserting queue in manager: %v", err)
+			}
+			scheduler := New(qManager, cqCache, cl, record.NewFakeRecorder(1))
+
+			got, err := scheduler.Explain(ctx, key)
+			if tc.wantNotFound {
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("Explain returned error %v, want not found", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Explain returned error %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.ClusterQueue != tc.wantCQ {
+				t.Errorf("Got ClusterQueue %q, want %q", got.ClusterQueue, tc.wantCQ)
+			}
+			if mode := got.Assignment.RepresentativeMode(); mode != tc.wantMode {
+				t.Errorf("Got mode %s, want %s", mode, tc.wantMode)
+			}
+			if diff := cmp.Diff(tc.wantFlavors, got.Assignment.ToAPI(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected flavors (-want,+got):\n%s", diff)
+			}
+			if got.Message != tc.wantMessage {
+				t.Errorf("Got message %q, want %q", got.Message, tc.wantMessage)
+			}
+			var gotPreemptions []string
+			for _, target := range got.PreemptionTargets {
+				gotPreemptions = append(gotPreemptions, workload.Key(target.Obj))
+			}
+			if diff := cmp.Diff(tc.wantPreemptions, gotPreemptions); diff != "" {
+				t.Errorf("Unexpected preemption targets (-want,+got):\n%s", diff)
+			}
+
+			// The explanation doesn't admit the workload nor preempts other
+			// workloads.
+			snapshot := cqCache.Snapshot()
+			if diff := cmp.Diff([]string{"default/low"}, workloadKeys(snapshot.ClusterQueues["cq"].Workloads)); diff != "" {
+				t.Errorf("Unexpected admitted workloads in the cache (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func workloadKeys(workloads map[string]*workload.Info) []string {
+	keys := make([]string, 0, len(workloads))
+	for k := range workloads {
+		keys = append(keys, k)
+	}
+	return keys
+}
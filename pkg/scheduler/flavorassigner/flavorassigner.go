@@ -33,6 +33,7 @@ import (
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/util/pointer"
 	"sigs.k8s.io/kueue/pkg/workload"
 )
 
@@ -44,6 +45,22 @@ type Assignment struct {
 	// flavors assigned.
 	usage cache.ResourceQuantities
 
+	// namespaceUsage is the usage of resources by the workloads in the
+	// namespace of the workload, if the ClusterQueue limits it.
+	namespaceUsage cache.ResourceQuantities
+
+	// topologyUsage is the accumulated usage of the topology domains as pod
+	// sets get domains assigned.
+	topologyUsage map[*cache.TopologyDomain]workload.Requests
+
+	// excludedFlavors are the flavors that the workload can't be assigned,
+	// because their nodes were reclaimed while it was running on them.
+	excludedFlavors sets.Set[string]
+
+	// pinnedFlavors are the only flavors that the resources can get, to
+	// assign the same flavors to all the pod sets.
+	pinnedFlavors map[corev1.ResourceName]string
+
 	// representativeMode is the cached representative mode for this assignment.
 	representativeMode *FlavorAssignmentMode
 }
@@ -101,8 +118,53 @@ func (a *Assignment) ToAPI() []kueue.PodSetFlavors {
 	return psFlavors
 }
 
+// InadmissibleReasons returns the reasons why flavors couldn't be assigned to
+// the pod sets, sorted by pod set and message, up to the limit of the
+// Workload API.
+func (a *Assignment) InadmissibleReasons() []kueue.InadmissibleReason {
+	var reasons []kueue.InadmissibleReason
+	for _, ps := range a.PodSets {
+		if ps.Status == nil || ps.Status.IsError() {
+			continue
+		}
+		for _, r := range ps.Status.sortedReasons() {
+			if len(reasons) == workload.MaxInadmissibleReasons {
+				return reasons
+			}
+			reasons = append(reasons, r.toAPI(ps.Name))
+		}
+	}
+	return reasons
+}
+
+// Reason is a reason why a flavor can't be assigned to the resources of a
+// pod set.
+type Reason struct {
+	Type     kueue.InadmissibleReasonType
+	Resource corev1.ResourceName
+	Flavor   string
+	// Missing is the quantity of the resource that doesn't fit in the unused
+	// quota, or zero if unknown.
+	Missing int64
+	Message string
+}
+
+func (r *Reason) toAPI(podSet string) kueue.InadmissibleReason {
+	reason := kueue.InadmissibleReason{
+		PodSet:   podSet,
+		Type:     r.Type,
+		Resource: r.Resource,
+		Flavor:   r.Flavor,
+		Message:  r.Message,
+	}
+	if r.Missing > 0 {
+		reason.Missing = pointer.Quantity(workload.ResourceQuantity(r.Resource, r.Missing))
+	}
+	return reason
+}
+
 type Status struct {
-	reasons []string
+	reasons []Reason
 	err     error
 }
 
@@ -110,11 +172,27 @@ func (s *Status) IsError() bool {
 	return s != nil && s.err != nil
 }
 
-func (s *Status) append(r ...string) *Status {
+func (s *Status) append(r ...Reason) *Status {
 	s.reasons = append(s.reasons, r...)
 	return s
 }
 
+func (s *Status) sortedReasons() []Reason {
+	sort.Slice(s.reasons, func(i, j int) bool {
+		return s.reasons[i].Message < s.reasons[j].Message
+	})
+	return s.reasons
+}
+
+// Reasons returns the reasons why flavors can't be assigned, sorted by
+// message.
+func (s *Status) Reasons() []Reason {
+	if s == nil {
+		return nil
+	}
+	return s.sortedReasons()
+}
+
 func (s *Status) Message() string {
 	if s == nil {
 		return ""
@@ -122,8 +200,11 @@ func (s *Status) Message() string {
 	if s.err != nil {
 		return s.err.Error()
 	}
-	sort.Strings(s.reasons)
-	return strings.Join(s.reasons, ", ")
+	msgs := make([]string, len(s.reasons))
+	for i, r := range s.sortedReasons() {
+		msgs[i] = r.Message
+	}
+	return strings.Join(msgs, ", ")
 }
 
 func (s *Status) Equal(o *Status) bool {
@@ -133,8 +214,8 @@ func (s *Status) Equal(o *Status) bool {
 	if s.err != nil {
 		return errors.Is(s.err, o.err)
 	}
-	return cmp.Equal(s.reasons, o.reasons, cmpopts.SortSlices(func(a, b string) bool {
-		return a < b
+	return cmp.Equal(s.reasons, o.reasons, cmpopts.SortSlices(func(a, b Reason) bool {
+		return a.Message < b.Message
 	}))
 }
 
@@ -149,6 +230,15 @@ type PodSetAssignment struct {
 	Name    string
 	Flavors ResourceAssignment
 	Status  *Status
+	// Count is the number of pods of the pod set taken into account for this
+	// assignment.
+	Count int32
+	// TopologyDomain holds the node labels of the topology domain assigned to
+	// the pod set, if any.
+	TopologyDomain map[string]string
+
+	// reduced indicates that Count is lower than the count in the pod set spec.
+	reduced bool
 }
 
 // RepresentativeMode calculates the representative mode for this assignment as
@@ -176,10 +266,40 @@ func (psa *PodSetAssignment) toAPI() kueue.PodSetFlavors {
 	for res, flvAssignment := range psa.Flavors {
 		flavors[res] = flvAssignment.Name
 	}
-	return kueue.PodSetFlavors{
-		Name:    psa.Name,
-		Flavors: flavors,
+	if resolved := psa.resolvedAnyFlavor(); resolved != "" {
+		for res, flv := range flavors {
+			if flv == string(kueue.AnyFlavor) {
+				flavors[res] = resolved
+			}
+		}
+	}
+	psFlavors := kueue.PodSetFlavors{
+		Name:           psa.Name,
+		Flavors:        flavors,
+		TopologyDomain: psa.TopologyDomain,
 	}
+	if psa.reduced {
+		psFlavors.Count = pointer.Int32(psa.Count)
+	}
+	return psFlavors
+}
+
+// resolvedAnyFlavor returns the flavor that the resources covered by the
+// wildcard flavor get in the admission, which is the first flavor, in
+// alphabetical order of the resources, assigned to the other resources.
+func (psa *PodSetAssignment) resolvedAnyFlavor() string {
+	var resolved string
+	var resolvedRes corev1.ResourceName
+	for res, flvAssignment := range psa.Flavors {
+		if flvAssignment.Name == string(kueue.AnyFlavor) {
+			continue
+		}
+		if resolved == "" || res < resolvedRes {
+			resolved = flvAssignment.Name
+			resolvedRes = res
+		}
+	}
+	return resolved
 }
 
 // FlavorAssignmentMode describes whether the flavor can be assigned immediately
@@ -222,28 +342,84 @@ type FlavorAssignment struct {
 // The result for each pod set is accompanied with reasons why the flavor can't
 // be assigned immediately. Each assigned flavor is accompanied with a
 // FlavorAssignmentMode.
-func AssignFlavors(log logr.Logger, wl *workload.Info, resourceFlavors map[string]*kueue.ResourceFlavor, cq *cache.ClusterQueue) Assignment {
+// If counts is not nil, it holds the number of pods to consider for each pod
+// set, instead of the counts in the workload spec.
+// If the ClusterQueue prefers a homogeneous placement, the pod sets get the
+// same flavor for each resource, unless they fit better with mixed flavors.
+func AssignFlavors(log logr.Logger, wl *workload.Info, resourceFlavors map[string]*kueue.ResourceFlavor, cq *cache.ClusterQueue, counts []int32) Assignment {
+	assignment := assignFlavors(log, wl, resourceFlavors, cq, counts, nil)
+	if cq.FlavorFungibility.PodSetPlacement != kueue.PodSetPlacementPolicyPreferHomogeneous || assignment.homogeneous() {
+		return assignment
+	}
+	for i := range wl.TotalRequests {
+		if len(wl.TotalRequests[i].Flavors) > 0 {
+			// The pod sets of an admitted workload keep their flavors.
+			return assignment
+		}
+	}
+	var best *Assignment
+	for _, pinned := range homogeneousCandidates(wl, cq, &assignment) {
+		candidate := assignFlavors(log, wl, resourceFlavors, cq, counts, pinned)
+		if !candidate.homogeneous() {
+			continue
+		}
+		if best == nil || candidate.RepresentativeMode() > best.RepresentativeMode() {
+			best = &candidate
+		}
+		if best.RepresentativeMode() == Fit {
+			break
+		}
+	}
+	if best == nil || best.RepresentativeMode() == NoFit || best.RepresentativeMode() < assignment.RepresentativeMode() {
+		log.V(3).Info("Falling back to mixed flavors for the pod sets")
+		return assignment
+	}
+	return *best
+}
+
+func assignFlavors(log logr.Logger, wl *workload.Info, resourceFlavors map[string]*kueue.ResourceFlavor, cq *cache.ClusterQueue, counts []int32, pinnedFlavors map[corev1.ResourceName]string) Assignment {
 	assignment := Assignment{
-		TotalBorrow: make(cache.ResourceQuantities),
-		PodSets:     make([]PodSetAssignment, 0, len(wl.TotalRequests)),
-		usage:       make(cache.ResourceQuantities),
+		TotalBorrow:     make(cache.ResourceQuantities),
+		PodSets:         make([]PodSetAssignment, 0, len(wl.TotalRequests)),
+		usage:           make(cache.ResourceQuantities),
+		excludedFlavors: workload.ExcludedFlavors(wl.Obj),
+		pinnedFlavors:   pinnedFlavors,
+	}
+	if cq.HasNamespaceLimits() {
+		assignment.namespaceUsage = cq.NamespaceUsage(wl.Obj.Namespace)
 	}
-	for i, podSet := range wl.TotalRequests {
+	for i := range wl.TotalRequests {
+		podSet := &wl.TotalRequests[i]
+		reduced := false
+		if counts != nil && counts[i] != podSet.Count {
+			podSet = podSet.ScaledTo(counts[i])
+			reduced = true
+		}
 		psAssignment := PodSetAssignment{
 			Name:    podSet.Name,
 			Flavors: make(ResourceAssignment, len(podSet.Requests)),
+			Count:   podSet.Count,
+			reduced: reduced,
 		}
+		var anyFlavorResources []corev1.ResourceName
 		for resName := range podSet.Requests {
 			if _, found := psAssignment.Flavors[resName]; found {
 				// This resource got assigned the same flavor as a codependent resource.
 				// No need to compute again.
 				continue
 			}
+			if cq.CoveredByAnyFlavor(resName) {
+				// Resolved once the other resources of the pod set get flavors.
+				anyFlavorResources = append(anyFlavorResources, resName)
+				continue
+			}
 			if _, ok := cq.RequestableResources[resName]; !ok {
 				psAssignment.Flavors = nil
-				psAssignment.Status = &Status{
-					reasons: []string{fmt.Sprintf("resource %s unavailable in ClusterQueue", resName)},
-				}
+				psAssignment.Status = (&Status{}).append(Reason{
+					Type:     kueue.InadmissibleReasonResourceUnavailable,
+					Resource: resName,
+					Message:  fmt.Sprintf("resource %s unavailable in ClusterQueue", resName),
+				})
 				break
 			}
 			codepResources := cq.RequestableResources[resName].CodependentResources
@@ -251,7 +427,8 @@ func AssignFlavors(log logr.Logger, wl *workload.Info, resourceFlavors map[strin
 				codepResources = sets.New(resName)
 			}
 			codepReq := filterRequestedResources(podSet.Requests, codepResources)
-			flavors, status := assignment.findFlavorForCodepResources(log, codepReq, resourceFlavors, cq, &wl.Obj.Spec.PodSets[i].Spec)
+			// The pod sets of an admitted workload keep their flavors.
+			flavors, status := assignment.findFlavorForCodepResources(log, codepReq, resourceFlavors, cq, &wl.Obj.Spec.PodSets[i], podSet.Flavors[resName])
 			if status.IsError() || len(flavors) == 0 {
 				psAssignment.Flavors = nil
 				psAssignment.Status = status
@@ -259,6 +436,12 @@ func AssignFlavors(log logr.Logger, wl *workload.Info, resourceFlavors map[strin
 			}
 			psAssignment.append(flavors, status)
 		}
+		if psAssignment.Flavors != nil && len(anyFlavorResources) > 0 {
+			assignment.assignAnyFlavor(podSet.Requests, anyFlavorResources, cq, &psAssignment)
+		}
+		if len(psAssignment.Flavors) > 0 {
+			assignment.assignTopologyDomain(podSet.Requests, &wl.Obj.Spec.PodSets[i], podSet.TopologyDomain, cq, &psAssignment)
+		}
 
 		assignment.append(podSet.Requests, &psAssignment)
 		if psAssignment.Status.IsError() || (len(podSet.Requests) > 0 && len(psAssignment.Flavors) == 0) {
@@ -273,6 +456,72 @@ func AssignFlavors(log logr.Logger, wl *workload.Info, resourceFlavors map[strin
 	return assignment
 }
 
+// homogeneous returns whether all the pod sets got the same flavor for each
+// resource.
+func (a *Assignment) homogeneous() bool {
+	flavors := make(map[corev1.ResourceName]string)
+	for _, ps := range a.PodSets {
+		for res, flvAssignment := range ps.Flavors {
+			if flavor, found := flavors[res]; found && flavor != flvAssignment.Name {
+				return false
+			}
+			flavors[res] = flvAssignment.Name
+		}
+	}
+	return true
+}
+
+// homogeneousCandidates returns the flavors to pin, in order of preference, to
+// look for a homogeneous assignment: first, the flavors that each pod set got
+// in the mixed assignment and then, each flavor of the ClusterQueue for all
+// the resources that can use it.
+func homogeneousCandidates(wl *workload.Info, cq *cache.ClusterQueue, mixed *Assignment) []map[corev1.ResourceName]string {
+	var candidates []map[corev1.ResourceName]string
+	seen := sets.New[string]()
+	add := func(pinned map[corev1.ResourceName]string) {
+		// fmt prints the maps with sorted keys.
+		key := fmt.Sprint(pinned)
+		if len(pinned) == 0 || seen.Has(key) {
+			return
+		}
+		seen.Insert(key)
+		candidates = append(candidates, pinned)
+	}
+	for _, ps := range mixed.PodSets {
+		pinned := make(map[corev1.ResourceName]string, len(ps.Flavors))
+		for res, flvAssignment := range ps.Flavors {
+			if flvAssignment.Name != string(kueue.AnyFlavor) {
+				pinned[res] = flvAssignment.Name
+			}
+		}
+		add(pinned)
+	}
+	requested := sets.New[corev1.ResourceName]()
+	for _, ps := range wl.TotalRequests {
+		for res := range ps.Requests {
+			if _, ok := cq.RequestableResources[res]; ok && !cq.CoveredByAnyFlavor(res) {
+				requested.Insert(res)
+			}
+		}
+	}
+	resources := sets.List(requested)
+	for _, res := range resources {
+		for _, flavor := range cq.RequestableResources[res].Flavors {
+			pinned := make(map[corev1.ResourceName]string, len(resources))
+			for _, other := range resources {
+				for _, otherFlavor := range cq.RequestableResources[other].Flavors {
+					if otherFlavor.Name == flavor.Name {
+						pinned[other] = flavor.Name
+						break
+					}
+				}
+			}
+			add(pinned)
+		}
+	}
+	return candidates
+}
+
 func (psa *PodSetAssignment) append(flavors ResourceAssignment, status *Status) {
 	for resource, assignment := range flavors {
 		psa.Flavors[resource] = assignment
@@ -304,6 +553,8 @@ func (a *Assignment) append(requests workload.Requests, psAssignment *PodSetAssi
 
 // findFlavorForCodepResources finds the flavor which can satisfy the resource
 // request, along with the information about resources that need to be borrowed.
+// If requiredFlavor is not empty, only that flavor is considered. Otherwise,
+// the flavors are restricted and ordered by the flavor affinity of the pod set.
 // If the flavor cannot be immediately assigned, it returns a status with
 // reasons or failure.
 func (a *Assignment) findFlavorForCodepResources(
@@ -311,8 +562,10 @@ func (a *Assignment) findFlavorForCodepResources(
 	requests workload.Requests,
 	resourceFlavors map[string]*kueue.ResourceFlavor,
 	cq *cache.ClusterQueue,
-	spec *corev1.PodSpec) (ResourceAssignment, *Status) {
+	podSet *kueue.PodSet,
+	requiredFlavor string) (ResourceAssignment, *Status) {
 	status := &Status{}
+	spec := &podSet.Spec
 
 	// Keep any resource name as an anchor to gather flavors for.
 	var rName corev1.ResourceName
@@ -321,22 +574,67 @@ func (a *Assignment) findFlavorForCodepResources(
 	}
 	var bestAssignment ResourceAssignment
 	bestAssignmentMode := NoFit
+	bestAssignmentBorrows := false
 
 	// We will only check against the flavors' labels for the resource.
 	// Since all the resources share the same flavors, they use the same selector.
 	selector := flavorSelector(spec, cq.LabelKeys[rName])
-	for i, flvLimit := range cq.RequestableResources[rName].Flavors {
+	order := a.flavorOrder(rName, requests, resourceFlavors, cq)
+	var affinityRequired sets.Set[string]
+	if affinity := podSet.FlavorAffinity; affinity != nil {
+		flavors := cq.RequestableResources[rName].Flavors
+		affinityRequired = flavorsIn(affinity.Required, flavors)
+		order = preferredFirst(order, flavors, flavorsIn(affinity.Preferred, flavors))
+	}
+	for _, i := range order {
+		flvLimit := cq.RequestableResources[rName].Flavors[i]
+		if requiredFlavor != "" && flvLimit.Name != requiredFlavor {
+			continue
+		}
+		if requiredFlavor == "" && affinityRequired.Len() > 0 && !affinityRequired.Has(flvLimit.Name) {
+			status.append(Reason{
+				Type:    kueue.InadmissibleReasonFlavorAffinityMismatch,
+				Flavor:  flvLimit.Name,
+				Message: fmt.Sprintf("flavor %s is not required by the pod set", flvLimit.Name),
+			})
+			continue
+		}
+		if !a.canUseFlavor(requests, flvLimit.Name) {
+			continue
+		}
+		if requiredFlavor == "" && a.excludedFlavors.Has(flvLimit.Name) {
+			status.append(Reason{
+				Type:    kueue.InadmissibleReasonFlavorExcluded,
+				Flavor:  flvLimit.Name,
+				Message: fmt.Sprintf("flavor %s is excluded after its nodes were reclaimed", flvLimit.Name),
+			})
+			continue
+		}
 		flavor, exist := resourceFlavors[flvLimit.Name]
 		if !exist {
 			log.Error(nil, "Flavor not found", "Flavor", flvLimit.Name)
-			status.append(fmt.Sprintf("flavor %s not found", flvLimit.Name))
+			status.append(Reason{
+				Type:    kueue.InadmissibleReasonFlavorNotFound,
+				Flavor:  flvLimit.Name,
+				Message: fmt.Sprintf("flavor %s not found", flvLimit.Name),
+			})
 			continue
 		}
-		taint, untolerated := corev1helpers.FindMatchingUntoleratedTaint(flavor.Taints, spec.Tolerations, func(t *corev1.Taint) bool {
+		// The tolerations of the flavor are added to the pods when the workload
+		// is admitted, so they also tolerate the taints of the flavor.
+		tolerations := spec.Tolerations
+		if len(flavor.Tolerations) > 0 {
+			tolerations = append(append(make([]corev1.Toleration, 0, len(spec.Tolerations)+len(flavor.Tolerations)), spec.Tolerations...), flavor.Tolerations...)
+		}
+		taint, untolerated := corev1helpers.FindMatchingUntoleratedTaint(flavor.Taints, tolerations, func(t *corev1.Taint) bool {
 			return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
 		})
 		if untolerated {
-			status.append(fmt.Sprintf("untolerated taint %s in flavor %s", taint, flvLimit.Name))
+			status.append(Reason{
+				Type:    kueue.InadmissibleReasonUntoleratedTaint,
+				Flavor:  flvLimit.Name,
+				Message: fmt.Sprintf("untolerated taint %s in flavor %s", taint, flvLimit.Name),
+			})
 			continue
 		}
 		if match, err := selector.Match(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: flavor.NodeSelector}}); !match || err != nil {
@@ -344,15 +642,39 @@ func (a *Assignment) findFlavorForCodepResources(
 				status.err = err
 				return nil, status
 			}
-			status.append(fmt.Sprintf("flavor %s doesn't match with node affinity", flvLimit.Name))
+			status.append(Reason{
+				Type:    kueue.InadmissibleReasonNodeAffinityMismatch,
+				Flavor:  flvLimit.Name,
+				Message: fmt.Sprintf("flavor %s doesn't match with node affinity", flvLimit.Name),
+			})
 			continue
 		}
+		if cq.FlavorNodeResources != nil {
+			if missing := missingNodeResources(requests, cq.FlavorNodeResources[flvLimit.Name]); len(missing) > 0 {
+				status.append(Reason{
+					Type:    kueue.InadmissibleReasonMissingNodeResources,
+					Flavor:  flvLimit.Name,
+					Message: fmt.Sprintf("flavor %s doesn't select nodes with %s", flvLimit.Name, strings.Join(missing, ", ")),
+				})
+				continue
+			}
+		}
 
 		assignments := make(ResourceAssignment, len(requests))
 		// Calculate representativeMode for this assignment as the worst mode among all requests.
 		representativeMode := Fit
 		for name, val := range requests {
 			codepFlvLimit := cq.RequestableResources[name].Flavors[i]
+			if limit := codepFlvLimit.MaxPerNamespace; limit != nil && a.namespaceUsage[name][flavor.Name]+val+a.usage[name][flavor.Name] > *limit {
+				status.append(Reason{
+					Type:     kueue.InadmissibleReasonNamespaceLimitExceeded,
+					Resource: name,
+					Flavor:   flavor.Name,
+					Message:  fmt.Sprintf("namespace limit for %s flavor %s exceeded", name, flavor.Name),
+				})
+				representativeMode = NoFit
+				break
+			}
 			// Check considering the flavor usage by previous pod sets.
 			mode, borrow, s := fitsFlavorLimits(name, val+a.usage[name][flavor.Name], cq, &codepFlvLimit)
 			if s != nil {
@@ -373,18 +695,292 @@ func (a *Assignment) findFlavorForCodepResources(
 			}
 		}
 
-		if representativeMode > bestAssignmentMode {
+		borrows := assignments.borrows()
+		if representativeMode > bestAssignmentMode || (representativeMode == Fit && bestAssignmentBorrows && !borrows) {
 			bestAssignment = assignments
 			bestAssignmentMode = representativeMode
-			if bestAssignmentMode == Fit {
-				// All the resources fit in the cohort, no need to check more flavors.
-				return bestAssignment, nil
-			}
+			bestAssignmentBorrows = borrows
+		}
+		if shouldStopSearch(bestAssignmentMode, bestAssignmentBorrows, cq.FlavorFungibility) {
+			break
 		}
 	}
+	if bestAssignmentMode == Fit {
+		return bestAssignment, nil
+	}
 	return bestAssignment, status
 }
 
+// flavorsIn returns the names that are flavors of the resource group.
+func flavorsIn(names []string, flavors []cache.FlavorLimits) sets.Set[string] {
+	in := sets.New[string]()
+	for _, name := range names {
+		for _, flavor := range flavors {
+			if flavor.Name == name {
+				in.Insert(name)
+				break
+			}
+		}
+	}
+	return in
+}
+
+// canUseFlavor returns whether the flavor isn't excluded by the flavors pinned
+// for any of the requested resources.
+func (a *Assignment) canUseFlavor(requests workload.Requests, flavor string) bool {
+	for name := range requests {
+		if pinned, found := a.pinnedFlavors[name]; found && pinned != flavor {
+			return false
+		}
+	}
+	return true
+}
+
+// assignAnyFlavor assigns the wildcard flavor to the resources covered by it,
+// once the other resources of the pod set got flavors. The pod set gets no
+// flavors if it only requests resources covered by the wildcard flavor, or if
+// they don't fit in its quota.
+func (a *Assignment) assignAnyFlavor(requests workload.Requests, resources []corev1.ResourceName, cq *cache.ClusterQueue, psAssignment *PodSetAssignment) {
+	sort.Slice(resources, func(i, j int) bool { return resources[i] < resources[j] })
+	if len(psAssignment.Flavors) == 0 {
+		psAssignment.Flavors = nil
+		psAssignment.Status = (&Status{}).append(Reason{
+			Type:     kueue.InadmissibleReasonResourceUnavailable,
+			Resource: resources[0],
+			Message:  fmt.Sprintf("resource %s can only use the flavor of other resources, which the pod set doesn't request", resources[0]),
+		})
+		return
+	}
+	flavors := make(ResourceAssignment, len(resources))
+	status := &Status{}
+	for _, name := range resources {
+		flvLimit := cq.RequestableResources[name].Flavors[0]
+		val := requests[name]
+		if limit := flvLimit.MaxPerNamespace; limit != nil && a.namespaceUsage[name][flvLimit.Name]+val+a.usage[name][flvLimit.Name] > *limit {
+			psAssignment.Flavors = nil
+			psAssignment.Status = status.append(Reason{
+				Type:     kueue.InadmissibleReasonNamespaceLimitExceeded,
+				Resource: name,
+				Flavor:   flvLimit.Name,
+				Message:  fmt.Sprintf("namespace limit for %s flavor %s exceeded", name, flvLimit.Name),
+			})
+			return
+		}
+		mode, borrow, s := fitsFlavorLimits(name, val+a.usage[name][flvLimit.Name], cq, &flvLimit)
+		if s != nil {
+			status.reasons = append(status.reasons, s.reasons...)
+		}
+		if mode == NoFit {
+			psAssignment.Flavors = nil
+			psAssignment.Status = status
+			return
+		}
+		flavors[name] = &FlavorAssignment{
+			Name:   flvLimit.Name,
+			Mode:   mode,
+			borrow: borrow,
+		}
+	}
+	if len(status.reasons) == 0 {
+		status = nil
+	}
+	psAssignment.append(flavors, status)
+}
+
+// assignTopologyDomain assigns a domain of the topology of the flavor assigned
+// to the pod set, if the pod set requests a topology. The pod set gets no
+// flavors if it requires a topology level where no domain fits it, or if it
+// doesn't fit in the domain where it was already admitted, if any.
+// Only the domains that match the node selector and the required node affinity
+// of the pod set are considered for new admissions.
+func (a *Assignment) assignTopologyDomain(requests workload.Requests, podSet *kueue.PodSet, admittedDomain map[string]string, cq *cache.ClusterQueue, psAssignment *PodSetAssignment) {
+	req := podSet.TopologyRequest
+	if (req == nil && admittedDomain == nil) || cq.FlavorTopologies == nil {
+		return
+	}
+	flavors := sets.New[string]()
+	for _, flvAssignment := range psAssignment.Flavors {
+		if cq.FlavorTopologies[flvAssignment.Name] != nil {
+			flavors.Insert(flvAssignment.Name)
+		}
+	}
+	if flavors.Len() == 0 {
+		return
+	}
+	fail := func(flavor, reason string) {
+		psAssignment.Flavors = nil
+		psAssignment.Status = (&Status{}).append(Reason{
+			Type:    kueue.InadmissibleReasonTopologyMismatch,
+			Flavor:  flavor,
+			Message: reason,
+		})
+	}
+	if flavors.Len() > 1 {
+		fail("", fmt.Sprintf("flavors %s with a topology are assigned to the same pod set", strings.Join(sets.List(flavors), ", ")))
+		return
+	}
+	flavor := sets.List(flavors)[0]
+	topology := cq.FlavorTopologies[flavor]
+	flvRequests := make(workload.Requests)
+	for name, flvAssignment := range psAssignment.Flavors {
+		if flvAssignment.Name == flavor {
+			flvRequests[name] = requests[name]
+		}
+	}
+
+	var domain *cache.TopologyDomain
+	if admittedDomain != nil {
+		domain = topology.Domain(admittedDomain)
+		if domain == nil || a.bestFitDomain(map[string]*cache.TopologyDomain{"": domain}, flvRequests) == nil {
+			fail(flavor, fmt.Sprintf("the pod set doesn't fit in its topology domain in flavor %s", flavor))
+			return
+		}
+	} else if req.Required != nil {
+		level := topology.Level(*req.Required)
+		if level < 0 {
+			fail(flavor, fmt.Sprintf("topology of flavor %s doesn't have the level %s", flavor, *req.Required))
+			return
+		}
+		domains := matchingDomains(topology, level, &podSet.Spec)
+		if len(domains) == 0 && len(topology.Domains[level]) > 0 {
+			fail(flavor, fmt.Sprintf("no domain of level %s in flavor %s matches the node affinity of the pod set", *req.Required, flavor))
+			return
+		}
+		if domain = a.bestFitDomain(domains, flvRequests); domain == nil {
+			fail(flavor, fmt.Sprintf("no domain of level %s in flavor %s fits the pod set", *req.Required, flavor))
+			return
+		}
+	} else {
+		// Fall back to the levels above the preferred one.
+		for level := topology.Level(*req.Preferred); level >= 0 && domain == nil; level-- {
+			domain = a.bestFitDomain(matchingDomains(topology, level, &podSet.Spec), flvRequests)
+		}
+		if domain == nil {
+			return
+		}
+	}
+	psAssignment.TopologyDomain = domain.NodeLabels(topology.Levels)
+	if a.topologyUsage == nil {
+		a.topologyUsage = make(map[*cache.TopologyDomain]workload.Requests)
+	}
+	for d := domain; d != nil; d = d.Parent {
+		if a.topologyUsage[d] == nil {
+			a.topologyUsage[d] = make(workload.Requests)
+		}
+		for name, val := range flvRequests {
+			a.topologyUsage[d][name] += val
+		}
+	}
+}
+
+// matchingDomains returns the domains of the level whose node labels match the
+// node selector and the required node affinity of the pod spec. Only the
+// labels of the level and the levels above are checked, as the lower levels
+// don't identify the domain.
+func matchingDomains(topology *cache.FlavorTopology, level int, spec *corev1.PodSpec) map[string]*cache.TopologyDomain {
+	selector := flavorSelector(spec, sets.New(topology.Levels[:level+1]...))
+	domains := make(map[string]*cache.TopologyDomain, len(topology.Domains[level]))
+	for k, d := range topology.Domains[level] {
+		if match, err := selector.Match(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: d.NodeLabels(topology.Levels)}}); match && err == nil {
+			domains[k] = d
+		}
+	}
+	return domains
+}
+
+// bestFitDomain returns the domain with the least free capacity, among the
+// ones that fit the requests, considering the usage by previous pod sets.
+// The free capacity is compared for each resource in alphabetical order.
+func (a *Assignment) bestFitDomain(domains map[string]*cache.TopologyDomain, requests workload.Requests) *cache.TopologyDomain {
+	resources := make([]corev1.ResourceName, 0, len(requests))
+	for name := range requests {
+		resources = append(resources, name)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i] < resources[j] })
+	keys := make([]string, 0, len(domains))
+	for k := range domains {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var best *cache.TopologyDomain
+	var bestFree []int64
+	for _, k := range keys {
+		d := domains[k]
+		free := make([]int64, len(resources))
+		fits := true
+		for i, name := range resources {
+			free[i] = a.domainFree(d, name)
+			if free[i] < requests[name] {
+				fits = false
+				break
+			}
+		}
+		if fits && (best == nil || lessFree(free, bestFree)) {
+			best = d
+			bestFree = free
+		}
+	}
+	return best
+}
+
+// domainFree returns the capacity of the resource that is not used in the
+// domain nor in any of its ancestors.
+func (a *Assignment) domainFree(d *cache.TopologyDomain, name corev1.ResourceName) int64 {
+	free := d.Capacity[name] - d.Usage[name] - a.topologyUsage[d][name]
+	for p := d.Parent; p != nil; p = p.Parent {
+		if pFree := p.Capacity[name] - p.Usage[name] - a.topologyUsage[p][name]; pFree < free {
+			free = pFree
+		}
+	}
+	return free
+}
+
+func lessFree(a, b []int64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// missingNodeResources returns the extended resources in the requests that are
+// not exposed by the nodes.
+func missingNodeResources(requests workload.Requests, nodeResources sets.Set[corev1.ResourceName]) []string {
+	var missing []string
+	for name := range requests {
+		if workload.IsExtendedResource(name) && !nodeResources.Has(name) {
+			missing = append(missing, string(name))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// shouldStopSearch returns whether the flavor assignment found so far is
+// good enough, according to the flavor fungibility policies, so that there is
+// no need to check more flavors.
+func shouldStopSearch(mode FlavorAssignmentMode, borrows bool, fungibility kueue.FlavorFungibility) bool {
+	switch mode {
+	case Fit:
+		// All the resources fit in the cohort.
+		return !borrows || fungibility.WhenCanBorrow != kueue.FlavorFungibilityPolicyTryNextFlavor
+	case Preempt:
+		return fungibility.WhenCanPreempt == kueue.FlavorFungibilityPolicyPreempt
+	}
+	return false
+}
+
+func (ra ResourceAssignment) borrows() bool {
+	for _, a := range ra {
+		if a.borrow > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func flavorSelector(spec *corev1.PodSpec, allowedKeys sets.Set[string]) nodeaffinity.RequiredNodeAffinity {
 	// This function generally replicates the implementation of kube-scheduler's NodeAffintiy
 	// Filter plugin as of v1.24.
@@ -447,18 +1043,41 @@ func fitsFlavorLimits(rName corev1.ResourceName, val int64, cq *cache.ClusterQue
 		mode = Preempt
 	}
 	if flavor.Max != nil && used+val > *flavor.Max {
-		status.append(fmt.Sprintf("borrowing limit for %s flavor %s exceeded", rName, flavor.Name))
+		status.append(Reason{
+			Type:     kueue.InadmissibleReasonBorrowingLimitExceeded,
+			Resource: rName,
+			Flavor:   flavor.Name,
+			Message:  fmt.Sprintf("borrowing limit for %s flavor %s exceeded", rName, flavor.Name),
+		})
 		return mode, 0, &status
 	}
 
+	// Only the usage beyond the guaranteed quota is accounted in the cohort.
+	cohortVal := flavor.LentUsage(used+val) - flavor.LentUsage(used)
+	for cohort := cq.Cohort; cohort != nil; cohort = cohort.Parent {
+		if limit, found := cohort.Limits[rName][flavor.Name]; found && cohort.UsedResources[rName][flavor.Name]+cohortVal > limit {
+			status.append(Reason{
+				Type:     kueue.InadmissibleReasonCohortLimitExceeded,
+				Resource: rName,
+				Flavor:   flavor.Name,
+				Message:  fmt.Sprintf("limit of cohort %s for %s flavor %s exceeded", cohort.Name, rName, flavor.Name),
+			})
+			return mode, 0, &status
+		}
+	}
+
 	cohortUsed := used
 	cohortAvailable := flavor.Min
-	if cq.Cohort != nil {
-		cohortUsed = cq.Cohort.UsedResources[rName][flavor.Name]
-		cohortAvailable = cq.Cohort.RequestableResources[rName][flavor.Name]
+	if cq.Cohort == nil {
+		cohortVal = val
+	} else {
+		// Unused quota can be borrowed from anywhere in the hierarchy.
+		root := cq.Cohort.Root()
+		cohortUsed = root.UsedResources[rName][flavor.Name]
+		cohortAvailable = root.RequestableResources[rName][flavor.Name]
 	}
 
-	lack := cohortUsed + val - cohortAvailable
+	lack := cohortUsed + cohortVal - cohortAvailable
 	if lack <= 0 {
 		borrow := used + val - flavor.Min
 		if borrow < 0 {
@@ -468,15 +1087,27 @@ func fitsFlavorLimits(rName corev1.ResourceName, val int64, cq *cache.ClusterQue
 	}
 
 	lackQuantity := workload.ResourceQuantity(rName, lack)
-	msg := fmt.Sprintf("insufficient unused quota in cohort for %s flavor %s, %s more needed", rName, flavor.Name, &lackQuantity)
+	reason := Reason{
+		Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+		Resource: rName,
+		Flavor:   flavor.Name,
+		Missing:  lack,
+		Message:  fmt.Sprintf("insufficient unused quota in cohort for %s flavor %s, %s more needed", rName, flavor.Name, &lackQuantity),
+	}
 	if cq.Cohort == nil {
 		if mode == NoFit {
-			msg = fmt.Sprintf("insufficient quota for %s flavor %s in ClusterQueue", rName, flavor.Name)
+			reason.Type = kueue.InadmissibleReasonInsufficientQuota
+			reason.Missing = 0
+			reason.Message = fmt.Sprintf("insufficient quota for %s flavor %s in ClusterQueue", rName, flavor.Name)
+			if flavor.Min == 0 {
+				// Zero-quota flavors can only be used with quota borrowed from a cohort.
+				reason.Message = fmt.Sprintf("no quota for %s flavor %s in ClusterQueue and no cohort to borrow from", rName, flavor.Name)
+			}
 		} else {
-			msg = fmt.Sprintf("insufficient unused quota for %s flavor %s, %s more needed", rName, flavor.Name, &lackQuantity)
+			reason.Message = fmt.Sprintf("insufficient unused quota for %s flavor %s, %s more needed", rName, flavor.Name, &lackQuantity)
 		}
 	}
-	status.append(msg)
+	status.append(reason)
 	return mode, 0, &status
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package flavorassigner
 
 import (
+	"math"
 	"testing"
 
 	"github.com/go-logr/logr/testr"
@@ -41,13 +42,15 @@ func TestAssignFlavors(t *testing.T) {
 		},
 		"one": {
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "one",
+				Name:        "one",
+				Annotations: map[string]string{kueue.ResourceFlavorCostAnnotation: "3.5"},
 			},
 			NodeSelector: map[string]string{"type": "one"},
 		},
 		"two": {
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "two",
+				Name:        "two",
+				Annotations: map[string]string{kueue.ResourceFlavorCostAnnotation: "1"},
 			},
 			NodeSelector: map[string]string{"type": "two"},
 		},
@@ -71,13 +74,30 @@ func TestAssignFlavors(t *testing.T) {
 				Effect: corev1.TaintEffectNoSchedule,
 			}},
 		},
+		"dedicated": {
+			ObjectMeta: metav1.ObjectMeta{Name: "dedicated"},
+			Taints: []corev1.Taint{{
+				Key:    "pool",
+				Value:  "dedicated",
+				Effect: corev1.TaintEffectNoSchedule,
+			}},
+			Tolerations: []corev1.Toleration{{
+				Key:      "pool",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "dedicated",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+		},
 	}
 
 	cases := map[string]struct {
 		wlPods         []kueue.PodSet
+		wlExcluded     []kueue.ExcludedFlavor
 		clusterQueue   cache.ClusterQueue
 		wantRepMode    FlavorAssignmentMode
 		wantAssignment Assignment
+		// wantAPI is only checked if not nil.
+		wantAPI []kueue.PodSetFlavors
 	}{
 		"single flavor, fits": {
 			wlPods: []kueue.PodSet{
@@ -99,7 +119,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU:    {Name: "default", Mode: Fit},
 						corev1.ResourceMemory: {Name: "default", Mode: Fit},
@@ -139,13 +160,42 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU: {Name: "tainted", Mode: Fit},
 					},
 				}},
 			},
 		},
+		"single flavor, taints tolerated by the flavor": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "1",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{{Name: "dedicated", Min: 4000}},
+					},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "dedicated", Mode: Fit},
+					},
+				}},
+			},
+		},
 		"single flavor, used resources, doesn't fit": {
 			wlPods: []kueue.PodSet{
 				{
@@ -169,12 +219,21 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Preempt,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU: {Name: "default", Mode: Preempt},
 					},
 					Status: &Status{
-						reasons: []string{"insufficient unused quota for cpu flavor default, 1 more needed"},
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "default",
+								Missing:  1000,
+								Message:  "insufficient unused quota for cpu flavor default, 1 more needed",
+							},
+						},
 					},
 				}},
 			},
@@ -209,7 +268,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU:    {Name: "two", Mode: Fit},
 						corev1.ResourceMemory: {Name: "b_one", Mode: Fit},
@@ -249,10 +309,16 @@ func TestAssignFlavors(t *testing.T) {
 			},
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Status: &Status{
-						reasons: []string{
-							"insufficient quota for memory flavor b_one in ClusterQueue",
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientQuota,
+								Resource: corev1.ResourceMemory,
+								Flavor:   "b_one",
+								Message:  "insufficient quota for memory flavor b_one in ClusterQueue",
+							},
 						},
 					},
 				}},
@@ -295,7 +361,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU:    {Name: "two", Mode: Fit},
 						corev1.ResourceMemory: {Name: "two", Mode: Fit},
@@ -368,17 +435,36 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Preempt,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU:    {Name: "two", Mode: Fit},
 						corev1.ResourceMemory: {Name: "two", Mode: Preempt},
 						"example.com/gpu":     {Name: "b_one", Mode: Preempt},
 					},
 					Status: &Status{
-						reasons: []string{
-							"insufficient unused quota in cohort for cpu flavor one, 1 more needed",
-							"insufficient unused quota in cohort for memory flavor two, 5Mi more needed",
-							"insufficient unused quota in cohort for example.com/gpu flavor b_one, 1 more needed",
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Missing:  1000,
+								Message:  "insufficient unused quota in cohort for cpu flavor one, 1 more needed",
+							},
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceMemory,
+								Flavor:   "two",
+								Missing:  5242880,
+								Message:  "insufficient unused quota in cohort for memory flavor two, 5Mi more needed",
+							},
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: "example.com/gpu",
+								Flavor:   "b_one",
+								Missing:  1,
+								Message:  "insufficient unused quota in cohort for example.com/gpu flavor b_one, 1 more needed",
+							},
 						},
 					},
 				}},
@@ -413,11 +499,22 @@ func TestAssignFlavors(t *testing.T) {
 			},
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Status: &Status{
-						reasons: []string{
-							"insufficient quota for cpu flavor one in ClusterQueue",
-							"insufficient quota for memory flavor two in ClusterQueue",
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Message:  "insufficient quota for cpu flavor one in ClusterQueue",
+							},
+							{
+								Type:     kueue.InadmissibleReasonInsufficientQuota,
+								Resource: corev1.ResourceMemory,
+								Flavor:   "two",
+								Message:  "insufficient quota for memory flavor two in ClusterQueue",
+							},
 						},
 					},
 				}},
@@ -446,7 +543,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU: {Name: "two", Mode: Fit},
 					},
@@ -476,7 +574,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU: {Name: "two", Mode: Fit},
 					},
@@ -532,7 +631,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU: {Name: "two", Mode: Fit},
 					},
@@ -593,7 +693,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU:    {Name: "two", Mode: Fit},
 						corev1.ResourceMemory: {Name: "two", Mode: Fit},
@@ -658,7 +759,8 @@ func TestAssignFlavors(t *testing.T) {
 			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU: {Name: "one", Mode: Fit},
 					},
@@ -709,11 +811,20 @@ func TestAssignFlavors(t *testing.T) {
 			},
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Status: &Status{
-						reasons: []string{
-							"flavor one doesn't match with node affinity",
-							"flavor two doesn't match with node affinity",
+						reasons: []Reason{
+							{
+								Type:    kueue.InadmissibleReasonNodeAffinityMismatch,
+								Flavor:  "one",
+								Message: "flavor one doesn't match with node affinity",
+							},
+							{
+								Type:    kueue.InadmissibleReasonNodeAffinityMismatch,
+								Flavor:  "two",
+								Message: "flavor two doesn't match with node affinity",
+							},
 						},
 					},
 				}},
@@ -750,13 +861,115 @@ func TestAssignFlavors(t *testing.T) {
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{
 					{
-						Name: "driver",
+						Name:  "driver",
+						Count: 1,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "two", Mode: Fit},
+						},
+					},
+					{
+						Name:  "worker",
+						Count: 1,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "one", Mode: Fit},
+						},
+					},
+				},
+			},
+		},
+		"multiple specs, prefer homogeneous placement": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "driver",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "5",
+					}),
+				},
+				{
+					Count: 1,
+					Name:  "worker",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "3",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 10_000},
+						},
+					},
+				},
+				FlavorFungibility: kueue.FlavorFungibility{
+					PodSetPlacement: kueue.PodSetPlacementPolicyPreferHomogeneous,
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{
+					{
+						Name:  "driver",
+						Count: 1,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "two", Mode: Fit},
+						},
+					},
+					{
+						Name:  "worker",
+						Count: 1,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "two", Mode: Fit},
+						},
+					},
+				},
+			},
+		},
+		"multiple specs, prefer homogeneous placement, falls back to mixed flavors": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "driver",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "5",
+					}),
+				},
+				{
+					Count: 1,
+					Name:  "worker",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "3",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 6000},
+						},
+					},
+				},
+				FlavorFungibility: kueue.FlavorFungibility{
+					PodSetPlacement: kueue.PodSetPlacementPolicyPreferHomogeneous,
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{
+					{
+						Name:  "driver",
+						Count: 1,
 						Flavors: ResourceAssignment{
 							corev1.ResourceCPU: {Name: "two", Mode: Fit},
 						},
 					},
 					{
-						Name: "worker",
+						Name:  "worker",
+						Count: 1,
 						Flavors: ResourceAssignment{
 							corev1.ResourceCPU: {Name: "one", Mode: Fit},
 						},
@@ -819,14 +1032,16 @@ func TestAssignFlavors(t *testing.T) {
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{
 					{
-						Name: "driver",
+						Name:  "driver",
+						Count: 1,
 						Flavors: ResourceAssignment{
 							corev1.ResourceCPU:    {Name: "default", Mode: Fit},
 							corev1.ResourceMemory: {Name: "default", Mode: Fit},
 						},
 					},
 					{
-						Name: "worker",
+						Name:  "worker",
+						Count: 1,
 						Flavors: ResourceAssignment{
 							corev1.ResourceCPU:    {Name: "default", Mode: Fit},
 							corev1.ResourceMemory: {Name: "default", Mode: Fit},
@@ -843,7 +1058,7 @@ func TestAssignFlavors(t *testing.T) {
 				},
 			},
 		},
-		"not enough space to borrow": {
+		"multiple flavors, try next flavor before borrowing": {
 			wlPods: []kueue.PodSet{
 				{
 					Count: 1,
@@ -857,33 +1072,33 @@ func TestAssignFlavors(t *testing.T) {
 				RequestableResources: map[corev1.ResourceName]*cache.Resource{
 					corev1.ResourceCPU: {
 						Flavors: []cache.FlavorLimits{
-							{
-								Name: "one",
-								Min:  1000,
-								// No max.
-							},
+							{Name: "one", Min: 1000},
+							{Name: "two", Min: 2000},
 						},
 					},
 				},
 				Cohort: &cache.Cohort{
 					RequestableResources: cache.ResourceQuantities{
-						corev1.ResourceCPU: {"one": 10_000},
-					},
-					UsedResources: cache.ResourceQuantities{
-						corev1.ResourceCPU: {"one": 9_000},
+						corev1.ResourceCPU: {"one": 10_000, "two": 10_000},
 					},
 				},
+				FlavorFungibility: kueue.FlavorFungibility{
+					WhenCanBorrow:  kueue.FlavorFungibilityPolicyTryNextFlavor,
+					WhenCanPreempt: kueue.FlavorFungibilityPolicyTryNextFlavor,
+				},
 			},
+			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
-					Status: &Status{
-						reasons: []string{"insufficient unused quota in cohort for cpu flavor one, 1 more needed"},
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "two", Mode: Fit},
 					},
 				}},
 			},
 		},
-		"past max, but can preempt in ClusterQueue": {
+		"multiple flavors, borrow in first flavor by default": {
 			wlPods: []kueue.PodSet{
 				{
 					Count: 1,
@@ -897,40 +1112,32 @@ func TestAssignFlavors(t *testing.T) {
 				RequestableResources: map[corev1.ResourceName]*cache.Resource{
 					corev1.ResourceCPU: {
 						Flavors: []cache.FlavorLimits{
-							{
-								Name: "one",
-								Min:  2000,
-								Max:  pointer.Int64(10_000),
-							},
+							{Name: "one", Min: 1000},
+							{Name: "two", Min: 2000},
 						},
 					},
 				},
-				UsedResources: cache.ResourceQuantities{
-					corev1.ResourceCPU: {"one": 9_000},
-				},
 				Cohort: &cache.Cohort{
 					RequestableResources: cache.ResourceQuantities{
-						corev1.ResourceCPU: {"one": 100_000},
-					},
-					UsedResources: cache.ResourceQuantities{
-						corev1.ResourceCPU: {"one": 9_000},
+						corev1.ResourceCPU: {"one": 10_000, "two": 10_000},
 					},
 				},
 			},
-			wantRepMode: Preempt,
+			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
-						corev1.ResourceCPU: {Name: "one", Mode: Preempt},
-					},
-					Status: &Status{
-						reasons: []string{"borrowing limit for cpu flavor one exceeded"},
+						corev1.ResourceCPU: {Name: "one", Mode: Fit},
 					},
 				}},
+				TotalBorrow: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 1_000},
+				},
 			},
 		},
-		"past min, but can preempt in ClusterQueue": {
+		"multiple flavors, preempt in first flavor before trying the next one": {
 			wlPods: []kueue.PodSet{
 				{
 					Count: 1,
@@ -944,144 +1151,1513 @@ func TestAssignFlavors(t *testing.T) {
 				RequestableResources: map[corev1.ResourceName]*cache.Resource{
 					corev1.ResourceCPU: {
 						Flavors: []cache.FlavorLimits{
-							{
-								Name: "one",
-								Min:  2000,
-							},
+							{Name: "one", Min: 2000},
+							{Name: "two", Min: 2000},
 						},
 					},
 				},
 				UsedResources: cache.ResourceQuantities{
 					corev1.ResourceCPU: {"one": 1_000},
 				},
+				FlavorFungibility: kueue.FlavorFungibility{
+					WhenCanBorrow:  kueue.FlavorFungibilityPolicyBorrow,
+					WhenCanPreempt: kueue.FlavorFungibilityPolicyPreempt,
+				},
 			},
 			wantRepMode: Preempt,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
 						corev1.ResourceCPU: {Name: "one", Mode: Preempt},
 					},
 					Status: &Status{
-						reasons: []string{"insufficient unused quota for cpu flavor one, 1 more needed"},
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Missing:  1000,
+								Message:  "insufficient unused quota for cpu flavor one, 1 more needed",
+							},
+						},
 					},
 				}},
 			},
 		},
-		"past min, but can preempt in cohort and ClusterQueue": {
+		"extended resource, flavor nodes don't expose it, fits second flavor": {
 			wlPods: []kueue.PodSet{
 				{
 					Count: 1,
 					Name:  "main",
 					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
-						corev1.ResourceCPU: "2",
+						"example.com/gpu": "1",
 					}),
 				},
 			},
 			clusterQueue: cache.ClusterQueue{
 				RequestableResources: map[corev1.ResourceName]*cache.Resource{
-					corev1.ResourceCPU: {
+					"example.com/gpu": {
 						Flavors: []cache.FlavorLimits{
-							{
-								Name: "one",
-								Min:  3000,
-							},
+							{Name: "one", Min: 4},
+							{Name: "two", Min: 4},
 						},
 					},
 				},
-				UsedResources: cache.ResourceQuantities{
-					corev1.ResourceCPU: {"one": 2_000},
-				},
-				Cohort: &cache.Cohort{
-					RequestableResources: cache.ResourceQuantities{
-						corev1.ResourceCPU: {"one": 10_000},
-					},
-					UsedResources: cache.ResourceQuantities{
-						corev1.ResourceCPU: {"one": 10_000},
-					},
+				FlavorNodeResources: map[string]sets.Set[corev1.ResourceName]{
+					"one": sets.New[corev1.ResourceName](),
+					"two": sets.New[corev1.ResourceName]("example.com/gpu"),
 				},
 			},
-			wantRepMode: Preempt,
+			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
-						corev1.ResourceCPU: {Name: "one", Mode: Preempt},
-					},
-					Status: &Status{
-						reasons: []string{"insufficient unused quota in cohort for cpu flavor one, 2 more needed"},
+						"example.com/gpu": {Name: "two", Mode: Fit},
 					},
 				}},
 			},
 		},
-		"can only preempt flavors that match affinity": {
+		"extended resource, no flavor nodes expose it": {
 			wlPods: []kueue.PodSet{
 				{
 					Count: 1,
 					Name:  "main",
-					Spec: corev1.PodSpec{
-						Containers: utiltesting.SingleContainerForRequest(map[corev1.ResourceName]string{
-							corev1.ResourceCPU: "2",
-						}),
-						NodeSelector: map[string]string{"type": "two"},
-					},
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						"example.com/gpu": "1",
+					}),
 				},
 			},
 			clusterQueue: cache.ClusterQueue{
 				RequestableResources: map[corev1.ResourceName]*cache.Resource{
-					corev1.ResourceCPU: {
+					"example.com/gpu": {
 						Flavors: []cache.FlavorLimits{
-							{Name: "one", Min: 4000},
-							{Name: "two", Min: 4000},
+							{Name: "one", Min: 4},
 						},
 					},
 				},
-				UsedResources: cache.ResourceQuantities{
+				FlavorNodeResources: map[string]sets.Set[corev1.ResourceName]{
+					"one": sets.New[corev1.ResourceName]("example.com/other"),
+				},
+			},
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:    kueue.InadmissibleReasonMissingNodeResources,
+								Flavor:  "one",
+								Message: "flavor one doesn't select nodes with example.com/gpu",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"required topology, best fit domain": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "3",
+					}),
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Required: pointer.String("rack"),
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 20_000},
+						},
+					},
+				},
+				FlavorTopologies: map[string]*cache.FlavorTopology{
+					"one": testTopology(),
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Fit},
+					},
+					TopologyDomain: map[string]string{"block": "b1", "rack": "r2"},
+				}},
+			},
+		},
+		"required topology, no domain fits": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "6",
+					}),
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Required: pointer.String("rack"),
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 20_000},
+						},
+					},
+				},
+				FlavorTopologies: map[string]*cache.FlavorTopology{
+					"one": testTopology(),
+				},
+			},
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:    kueue.InadmissibleReasonTopologyMismatch,
+								Flavor:  "one",
+								Message: "no domain of level rack in flavor one fits the pod set",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"required topology, domain matches the node selector": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: func() corev1.PodSpec {
+						spec := utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "3",
+						})
+						spec.NodeSelector = map[string]string{"block": "b2"}
+						return spec
+					}(),
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Required: pointer.String("rack"),
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 20_000},
+						},
+					},
+				},
+				FlavorTopologies: map[string]*cache.FlavorTopology{
+					"one": testTopology(),
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Fit},
+					},
+					TopologyDomain: map[string]string{"block": "b2", "rack": "r3"},
+				}},
+			},
+		},
+		"required topology, no domain matches the node affinity": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: func() corev1.PodSpec {
+						spec := utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "1",
+						})
+						spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+								NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+									MatchExpressions: []corev1.NodeSelectorRequirement{{
+										Key:      "rack",
+										Operator: corev1.NodeSelectorOpIn,
+										Values:   []string{"r5"},
+									}},
+								}},
+							},
+						}}
+						return spec
+					}(),
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Required: pointer.String("rack"),
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 20_000},
+						},
+					},
+				},
+				FlavorTopologies: map[string]*cache.FlavorTopology{
+					"one": testTopology(),
+				},
+			},
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:    kueue.InadmissibleReasonTopologyMismatch,
+								Flavor:  "one",
+								Message: "no domain of level rack in flavor one matches the node affinity of the pod set",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"preferred topology, falls back to a higher level": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "6",
+					}),
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Preferred: pointer.String("rack"),
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 20_000},
+						},
+					},
+				},
+				FlavorTopologies: map[string]*cache.FlavorTopology{
+					"one": testTopology(),
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Fit},
+					},
+					TopologyDomain: map[string]string{"block": "b1"},
+				}},
+			},
+		},
+		"required topology, multiple pod sets": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "driver",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "3",
+					}),
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Required: pointer.String("rack"),
+					},
+				},
+				{
+					Count: 1,
+					Name:  "workers",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "3",
+					}),
+					TopologyRequest: &kueue.PodSetTopologyRequest{
+						Required: pointer.String("rack"),
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 20_000},
+						},
+					},
+				},
+				FlavorTopologies: map[string]*cache.FlavorTopology{
+					"one": testTopology(),
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{
+					{
+						Name:  "driver",
+						Count: 1,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "one", Mode: Fit},
+						},
+						TopologyDomain: map[string]string{"block": "b1", "rack": "r2"},
+					},
+					{
+						Name:  "workers",
+						Count: 1,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "one", Mode: Fit},
+						},
+						TopologyDomain: map[string]string{"block": "b2", "rack": "r3"},
+					},
+				},
+			},
+		},
+		"guaranteed quota is available even if the cohort is full": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name:         "one",
+								Min:          4000,
+								LendingLimit: pointer.Int64(1000),
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 2_000},
+				},
+				Cohort: &cache.Cohort{
+					RequestableResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 5_000},
+					},
+					UsedResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 4_000},
+					},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"not enough space to borrow": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  1000,
+								// No max.
+							},
+						},
+					},
+				},
+				Cohort: &cache.Cohort{
+					RequestableResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 10_000},
+					},
+					UsedResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 9_000},
+					},
+				},
+			},
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Missing:  1000,
+								Message:  "insufficient unused quota in cohort for cpu flavor one, 1 more needed",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"zero-quota flavor, fits borrowing from the cohort": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  0,
+								Max:  pointer.Int64(4000),
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 1_000},
+				},
+				Cohort: &cache.Cohort{
+					RequestableResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 10_000},
+					},
+					UsedResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 5_000},
+					},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Fit},
+					},
+				}},
+				TotalBorrow: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 3_000},
+				},
+			},
+		},
+		"zero-quota flavor, borrowing ceiling exceeded": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  0,
+								Max:  pointer.Int64(2000),
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 1_000},
+				},
+				Cohort: &cache.Cohort{
+					RequestableResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 10_000},
+					},
+					UsedResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 1_000},
+					},
+				},
+			},
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonBorrowingLimitExceeded,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Message:  "borrowing limit for cpu flavor one exceeded",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"zero-quota flavor without cohort, fits second flavor": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  0,
+							},
+							{
+								Name: "two",
+								Min:  4000,
+							},
+						},
+					},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "two", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"zero-quota flavor without cohort, doesn't fit": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  0,
+							},
+						},
+					},
+				},
+			},
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Message:  "no quota for cpu flavor one in ClusterQueue and no cohort to borrow from",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"past max, but can preempt in ClusterQueue": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  2000,
+								Max:  pointer.Int64(10_000),
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 9_000},
+				},
+				Cohort: &cache.Cohort{
+					RequestableResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 100_000},
+					},
+					UsedResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 9_000},
+					},
+				},
+			},
+			wantRepMode: Preempt,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Preempt},
+					},
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonBorrowingLimitExceeded,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Message:  "borrowing limit for cpu flavor one exceeded",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"namespace limit exceeded in first flavor, fits in second": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name:            "one",
+								Min:             10_000,
+								MaxPerNamespace: pointer.Int64(4_000),
+							},
+							{
+								Name: "two",
+								Min:  10_000,
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 3_000, "two": 0},
+				},
+				Workloads: map[string]*workload.Info{
+					"/existing": workload.NewInfo(utiltesting.MakeWorkload("existing", "").
+						Request(corev1.ResourceCPU, "3").
+						Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "one").Obj()).
+						Obj()),
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "two", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"namespace limit exceeded": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name:            "one",
+								Min:             10_000,
+								MaxPerNamespace: pointer.Int64(4_000),
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 3_000},
+				},
+				Workloads: map[string]*workload.Info{
+					"/existing": workload.NewInfo(utiltesting.MakeWorkload("existing", "").
+						Request(corev1.ResourceCPU, "3").
+						Admit(utiltesting.MakeAdmission("cq").Flavor(corev1.ResourceCPU, "one").Obj()).
+						Obj()),
+				},
+			},
+			wantRepMode: NoFit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonNamespaceLimitExceeded,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Message:  "namespace limit for cpu flavor one exceeded",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"past min, but can preempt in ClusterQueue": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  2000,
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 1_000},
+				},
+			},
+			wantRepMode: Preempt,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Preempt},
+					},
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Missing:  1000,
+								Message:  "insufficient unused quota for cpu flavor one, 1 more needed",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"past min, but can preempt in cohort and ClusterQueue": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{
+								Name: "one",
+								Min:  3000,
+							},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {"one": 2_000},
+				},
+				Cohort: &cache.Cohort{
+					RequestableResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 10_000},
+					},
+					UsedResources: cache.ResourceQuantities{
+						corev1.ResourceCPU: {"one": 10_000},
+					},
+				},
+			},
+			wantRepMode: Preempt,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Preempt},
+					},
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Missing:  2000,
+								Message:  "insufficient unused quota in cohort for cpu flavor one, 2 more needed",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"can only preempt flavors that match affinity": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: corev1.PodSpec{
+						Containers: utiltesting.SingleContainerForRequest(map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "2",
+						}),
+						NodeSelector: map[string]string{"type": "two"},
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {
+						"one": 3000,
+						"two": 3000,
+					},
+				},
+			},
+			wantRepMode: Preempt,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "two", Mode: Preempt},
+					},
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:    kueue.InadmissibleReasonNodeAffinityMismatch,
+								Flavor:  "one",
+								Message: "flavor one doesn't match with node affinity",
+							},
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "two",
+								Missing:  1000,
+								Message:  "insufficient unused quota for cpu flavor two, 1 more needed",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"each podset requires preemption on a different flavor": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "launcher",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "2",
+					}),
+				},
+				{
+					Count: 10,
+					Name:  "workers",
+					Spec: corev1.PodSpec{
+						Containers: utiltesting.SingleContainerForRequest(map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "1",
+						}),
+						Tolerations: []corev1.Toleration{{
+							Key:      "instance",
+							Operator: corev1.TolerationOpEqual,
+							Value:    "spot",
+							Effect:   corev1.TaintEffectNoSchedule,
+						}},
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+							{Name: "tainted", Min: 10_000},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU: {
+						"one":     3000,
+						"tainted": 3000,
+					},
+				},
+			},
+			wantRepMode: Preempt,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{
+					{
+						Name:  "launcher",
+						Count: 1,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "one", Mode: Preempt},
+						},
+						Status: &Status{
+							reasons: []Reason{
+								{
+									Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+									Resource: corev1.ResourceCPU,
+									Flavor:   "one",
+									Missing:  1000,
+									Message:  "insufficient unused quota for cpu flavor one, 1 more needed",
+								},
+								{
+									Type:    kueue.InadmissibleReasonUntoleratedTaint,
+									Flavor:  "tainted",
+									Message: "untolerated taint {instance spot NoSchedule <nil>} in flavor tainted",
+								},
+							},
+						},
+					},
+					{
+						Name:  "workers",
+						Count: 10,
+						Flavors: ResourceAssignment{
+							corev1.ResourceCPU: {Name: "tainted", Mode: Preempt},
+						},
+						Status: &Status{
+							reasons: []Reason{
+								{
+									Type:     kueue.InadmissibleReasonInsufficientQuota,
+									Resource: corev1.ResourceCPU,
+									Flavor:   "one",
+									Message:  "insufficient quota for cpu flavor one in ClusterQueue",
+								},
+								{
+									Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+									Resource: corev1.ResourceCPU,
+									Flavor:   "tainted",
+									Missing:  3000,
+									Message:  "insufficient unused quota for cpu flavor tainted, 3 more needed",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"wildcard flavor, resolved to the flavor of other resources": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU:              "2",
+						corev1.ResourceEphemeralStorage: "1Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
 					corev1.ResourceCPU: {
-						"one": 3000,
-						"two": 3000,
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 1000},
+							{Name: "two", Min: 4000},
+						},
+					},
+					corev1.ResourceEphemeralStorage: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "*", Min: 10 * utiltesting.Gi},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceEphemeralStorage: {"*": 8 * utiltesting.Gi},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU:              {Name: "two", Mode: Fit},
+						corev1.ResourceEphemeralStorage: {Name: "*", Mode: Fit},
+					},
+				}},
+			},
+			wantAPI: []kueue.PodSetFlavors{{
+				Name: "main",
+				Flavors: map[corev1.ResourceName]string{
+					corev1.ResourceCPU:              "two",
+					corev1.ResourceEphemeralStorage: "two",
+				},
+			}},
+		},
+		"wildcard flavor, doesn't fit": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU:              "2",
+						corev1.ResourceEphemeralStorage: "3Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+						},
+					},
+					corev1.ResourceEphemeralStorage: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "*", Min: 10 * utiltesting.Gi},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceEphemeralStorage: {"*": 8 * utiltesting.Gi},
+				},
+			},
+			wantRepMode: Preempt,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU:              {Name: "one", Mode: Fit},
+						corev1.ResourceEphemeralStorage: {Name: "*", Mode: Preempt},
+					},
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+								Resource: corev1.ResourceEphemeralStorage,
+								Flavor:   "*",
+								Missing:  1 * utiltesting.Gi,
+								Message:  "insufficient unused quota for ephemeral-storage flavor *, 1Gi more needed",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"wildcard flavor, no other resources requested": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceEphemeralStorage: "1Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+						},
+					},
+					corev1.ResourceEphemeralStorage: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "*", Min: 10 * utiltesting.Gi},
+						},
+					},
+				},
+			},
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonResourceUnavailable,
+								Resource: corev1.ResourceEphemeralStorage,
+								Message:  "resource ephemeral-storage can only use the flavor of other resources, which the pod set doesn't request",
+							},
+						},
+					},
+				}},
+			},
+		},
+		"best fit profile, flavor with the least unused quota": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "1",
+						corev1.ResourceMemory: "1Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				SchedulingProfile: kueue.SchedulingProfileBestFit,
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4000},
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
+					},
+					corev1.ResourceMemory: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4 * utiltesting.Gi},
+							{Name: "one", Min: 4 * utiltesting.Gi},
+							{Name: "two", Min: 4 * utiltesting.Gi},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU:    {"default": 1_000, "one": 3_000, "two": 2_000},
+					corev1.ResourceMemory: {"default": 0, "one": 1 * utiltesting.Gi, "two": 3 * utiltesting.Gi},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU:    {Name: "one", Mode: Fit},
+						corev1.ResourceMemory: {Name: "one", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"spread profile, flavor with the most unused quota": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "1",
+						corev1.ResourceMemory: "1Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				SchedulingProfile: kueue.SchedulingProfileSpread,
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4000},
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
+					},
+					corev1.ResourceMemory: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4 * utiltesting.Gi},
+							{Name: "one", Min: 4 * utiltesting.Gi},
+							{Name: "two", Min: 4 * utiltesting.Gi},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU:    {"default": 1_000, "one": 3_000, "two": 2_000},
+					corev1.ResourceMemory: {"default": 0, "one": 1 * utiltesting.Gi, "two": 3 * utiltesting.Gi},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU:    {Name: "default", Mode: Fit},
+						corev1.ResourceMemory: {Name: "default", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"cheapest profile, cheapest flavor": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "1",
+						corev1.ResourceMemory: "1Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				SchedulingProfile: kueue.SchedulingProfileCheapest,
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4000},
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
+					},
+					corev1.ResourceMemory: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4 * utiltesting.Gi},
+							{Name: "one", Min: 4 * utiltesting.Gi},
+							{Name: "two", Min: 4 * utiltesting.Gi},
+						},
+					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU:    {"default": 1_000, "one": 3_000, "two": 2_000},
+					corev1.ResourceMemory: {"default": 0, "one": 1 * utiltesting.Gi, "two": 3 * utiltesting.Gi},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU:    {Name: "two", Mode: Fit},
+						corev1.ResourceMemory: {Name: "two", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"cheapest profile, cheapest flavor that fits": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "1",
+						corev1.ResourceMemory: "1Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				SchedulingProfile: kueue.SchedulingProfileCheapest,
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4000},
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
+					},
+					corev1.ResourceMemory: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4 * utiltesting.Gi},
+							{Name: "one", Min: 4 * utiltesting.Gi},
+							{Name: "two", Min: 4 * utiltesting.Gi},
+						},
 					},
 				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU:    {"default": 1_000, "one": 3_000, "two": 4_000},
+					corev1.ResourceMemory: {"default": 0, "one": 1 * utiltesting.Gi, "two": 3 * utiltesting.Gi},
+				},
 			},
-			wantRepMode: Preempt,
+			wantRepMode: Fit,
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Flavors: ResourceAssignment{
-						corev1.ResourceCPU: {Name: "two", Mode: Preempt},
+						corev1.ResourceCPU:    {Name: "one", Mode: Fit},
+						corev1.ResourceMemory: {Name: "one", Mode: Fit},
 					},
-					Status: &Status{
-						reasons: []string{
-							"flavor one doesn't match with node affinity",
-							"insufficient unused quota for cpu flavor two, 1 more needed",
+				}},
+			},
+		},
+		"ordered profile, first flavor": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "1",
+						corev1.ResourceMemory: "1Gi",
+					}),
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				SchedulingProfile: kueue.SchedulingProfileOrdered,
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4000},
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
+					},
+					corev1.ResourceMemory: {
+						CodependentResources: sets.New(corev1.ResourceCPU, corev1.ResourceMemory),
+						Flavors: []cache.FlavorLimits{
+							{Name: "default", Min: 4 * utiltesting.Gi},
+							{Name: "one", Min: 4 * utiltesting.Gi},
+							{Name: "two", Min: 4 * utiltesting.Gi},
 						},
 					},
+				},
+				UsedResources: cache.ResourceQuantities{
+					corev1.ResourceCPU:    {"default": 1_000, "one": 3_000, "two": 2_000},
+					corev1.ResourceMemory: {"default": 0, "one": 1 * utiltesting.Gi, "two": 3 * utiltesting.Gi},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU:    {Name: "default", Mode: Fit},
+						corev1.ResourceMemory: {Name: "default", Mode: Fit},
+					},
 				}},
 			},
 		},
-		"each podset requires preemption on a different flavor": {
+		"excluded flavor, fits in the next flavor": {
 			wlPods: []kueue.PodSet{
 				{
 					Count: 1,
-					Name:  "launcher",
+					Name:  "main",
 					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
-						corev1.ResourceCPU: "2",
+						corev1.ResourceCPU: "1",
 					}),
 				},
+			},
+			wlExcluded: []kueue.ExcludedFlavor{{Name: "one", RemainingRetries: 1}},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
+					},
+				},
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "two", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"excluded flavor, no other flavor": {
+			wlPods: []kueue.PodSet{
 				{
-					Count: 10,
-					Name:  "workers",
-					Spec: corev1.PodSpec{
-						Containers: utiltesting.SingleContainerForRequest(map[corev1.ResourceName]string{
-							corev1.ResourceCPU: "1",
-						}),
-						Tolerations: []corev1.Toleration{{
-							Key:      "instance",
-							Operator: corev1.TolerationOpEqual,
-							Value:    "spot",
-							Effect:   corev1.TaintEffectNoSchedule,
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "1",
+					}),
+				},
+			},
+			wlExcluded: []kueue.ExcludedFlavor{{Name: "one", RemainingRetries: 2}},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+						},
+					},
+				},
+			},
+			wantRepMode: NoFit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{{
+							Type:    kueue.InadmissibleReasonFlavorExcluded,
+							Flavor:  "one",
+							Message: "flavor one is excluded after its nodes were reclaimed",
 						}},
 					},
+				}},
+			},
+		},
+		"flavor affinity, required flavor for one resource group": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "1",
+						"example.com/gpu":  "1",
+					}),
+					FlavorAffinity: &kueue.PodSetFlavorAffinity{
+						Required: []string{"b_two"},
+					},
 				},
 			},
 			clusterQueue: cache.ClusterQueue{
@@ -1089,45 +2665,107 @@ func TestAssignFlavors(t *testing.T) {
 					corev1.ResourceCPU: {
 						Flavors: []cache.FlavorLimits{
 							{Name: "one", Min: 4000},
-							{Name: "tainted", Min: 10_000},
+							{Name: "two", Min: 4000},
+						},
+					},
+					"example.com/gpu": {
+						Flavors: []cache.FlavorLimits{
+							{Name: "b_one", Min: 4},
+							{Name: "b_two", Min: 4},
 						},
 					},
 				},
-				UsedResources: cache.ResourceQuantities{
+			},
+			wantRepMode: Fit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "one", Mode: Fit},
+						"example.com/gpu":  {Name: "b_two", Mode: Fit},
+					},
+				}},
+			},
+		},
+		"flavor affinity, preferred flavor": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "1",
+					}),
+					FlavorAffinity: &kueue.PodSetFlavorAffinity{
+						Preferred: []string{"two"},
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
 					corev1.ResourceCPU: {
-						"one":     3000,
-						"tainted": 3000,
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 4000},
+						},
 					},
 				},
 			},
-			wantRepMode: Preempt,
+			wantRepMode: Fit,
 			wantAssignment: Assignment{
-				PodSets: []PodSetAssignment{
-					{
-						Name: "launcher",
-						Flavors: ResourceAssignment{
-							corev1.ResourceCPU: {Name: "one", Mode: Preempt},
-						},
-						Status: &Status{
-							reasons: []string{
-								"insufficient unused quota for cpu flavor one, 1 more needed",
-								"untolerated taint {instance spot NoSchedule <nil>} in flavor tainted",
-							},
-						},
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Flavors: ResourceAssignment{
+						corev1.ResourceCPU: {Name: "two", Mode: Fit},
 					},
-					{
-						Name: "workers",
-						Flavors: ResourceAssignment{
-							corev1.ResourceCPU: {Name: "tainted", Mode: Preempt},
+				}},
+			},
+		},
+		"flavor affinity, required flavor doesn't fit": {
+			wlPods: []kueue.PodSet{
+				{
+					Count: 1,
+					Name:  "main",
+					Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "5",
+					}),
+					FlavorAffinity: &kueue.PodSetFlavorAffinity{
+						Required: []string{"one"},
+					},
+				},
+			},
+			clusterQueue: cache.ClusterQueue{
+				RequestableResources: map[corev1.ResourceName]*cache.Resource{
+					corev1.ResourceCPU: {
+						Flavors: []cache.FlavorLimits{
+							{Name: "one", Min: 4000},
+							{Name: "two", Min: 10_000},
 						},
-						Status: &Status{
-							reasons: []string{
-								"insufficient quota for cpu flavor one in ClusterQueue",
-								"insufficient unused quota for cpu flavor tainted, 3 more needed",
+					},
+				},
+			},
+			wantRepMode: NoFit,
+			wantAssignment: Assignment{
+				PodSets: []PodSetAssignment{{
+					Name:  "main",
+					Count: 1,
+					Status: &Status{
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonInsufficientQuota,
+								Resource: corev1.ResourceCPU,
+								Flavor:   "one",
+								Message:  "insufficient quota for cpu flavor one in ClusterQueue",
+							},
+							{
+								Type:    kueue.InadmissibleReasonFlavorAffinityMismatch,
+								Flavor:  "two",
+								Message: "flavor two is not required by the pod set",
 							},
 						},
 					},
-				},
+				}},
 			},
 		},
 		"resource not listed in clusterQueue": {
@@ -1151,9 +2789,16 @@ func TestAssignFlavors(t *testing.T) {
 			},
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Status: &Status{
-						reasons: []string{"resource example.com/gpu unavailable in ClusterQueue"},
+						reasons: []Reason{
+							{
+								Type:     kueue.InadmissibleReasonResourceUnavailable,
+								Resource: "example.com/gpu",
+								Message:  "resource example.com/gpu unavailable in ClusterQueue",
+							},
+						},
 					},
 				}},
 			},
@@ -1175,9 +2820,16 @@ func TestAssignFlavors(t *testing.T) {
 			},
 			wantAssignment: Assignment{
 				PodSets: []PodSetAssignment{{
-					Name: "main",
+					Name:  "main",
+					Count: 1,
 					Status: &Status{
-						reasons: []string{"flavor nonexistent-flavor not found"},
+						reasons: []Reason{
+							{
+								Type:    kueue.InadmissibleReasonFlavorNotFound,
+								Flavor:  "nonexistent-flavor",
+								Message: "flavor nonexistent-flavor not found",
+							},
+						},
 					},
 				}},
 			},
@@ -1193,15 +2845,144 @@ func TestAssignFlavors(t *testing.T) {
 				Spec: kueue.WorkloadSpec{
 					PodSets: tc.wlPods,
 				},
+				Status: kueue.WorkloadStatus{
+					ExcludedFlavors: tc.wlExcluded,
+				},
 			})
 			tc.clusterQueue.UpdateWithFlavors(resourceFlavors)
-			assignment := AssignFlavors(log, wlInfo, resourceFlavors, &tc.clusterQueue)
+			assignment := AssignFlavors(log, wlInfo, resourceFlavors, &tc.clusterQueue, nil)
 			if repMode := assignment.RepresentativeMode(); repMode != tc.wantRepMode {
 				t.Errorf("e.assignFlavors(_).RepresentativeMode()=%s, want %s", repMode, tc.wantRepMode)
 			}
-			if diff := cmp.Diff(tc.wantAssignment, assignment, cmpopts.IgnoreUnexported(Assignment{}, FlavorAssignment{})); diff != "" {
+			if diff := cmp.Diff(tc.wantAssignment, assignment, cmpopts.IgnoreUnexported(Assignment{}, PodSetAssignment{}, FlavorAssignment{})); diff != "" {
 				t.Errorf("Unexpected assignment (-want,+got):\n%s", diff)
 			}
+			if tc.wantAPI != nil {
+				if diff := cmp.Diff(tc.wantAPI, assignment.ToAPI()); diff != "" {
+					t.Errorf("Unexpected admission flavors (-want,+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// testTopology returns a topology with two blocks of two racks each:
+// b1 has r1, with 2 of its 4 CPUs used, and r2, with 4 free CPUs; b2 has r3
+// and r4, with 5 free CPUs each.
+func testTopology() *cache.FlavorTopology {
+	b1 := &cache.TopologyDomain{
+		Values:   []string{"b1"},
+		Capacity: workload.Requests{corev1.ResourceCPU: 8_000},
+		Usage:    workload.Requests{corev1.ResourceCPU: 2_000},
+	}
+	b2 := &cache.TopologyDomain{
+		Values:   []string{"b2"},
+		Capacity: workload.Requests{corev1.ResourceCPU: 10_000},
+		Usage:    workload.Requests{},
+	}
+	rack := func(parent *cache.TopologyDomain, name string, capacity, usage int64) *cache.TopologyDomain {
+		return &cache.TopologyDomain{
+			Values:   []string{parent.Values[0], name},
+			Parent:   parent,
+			Capacity: workload.Requests{corev1.ResourceCPU: capacity},
+			Usage:    workload.Requests{corev1.ResourceCPU: usage},
+		}
+	}
+	return &cache.FlavorTopology{
+		Levels: []string{"block", "rack"},
+		Domains: []map[string]*cache.TopologyDomain{
+			{"b1": b1, "b2": b2},
+			{
+				"b1,r1": rack(b1, "r1", 4_000, 2_000),
+				"b1,r2": rack(b1, "r2", 4_000, 0),
+				"b2,r3": rack(b2, "r3", 5_000, 0),
+				"b2,r4": rack(b2, "r4", 5_000, 0),
+			},
+		},
+	}
+}
+
+func TestInadmissibleReasons(t *testing.T) {
+	assignment := Assignment{
+		PodSets: []PodSetAssignment{
+			{
+				Name: "driver",
+				Flavors: ResourceAssignment{
+					corev1.ResourceCPU: {Name: "default", Mode: Fit},
+				},
+			},
+			{
+				Name: "workers",
+				Status: &Status{
+					reasons: []Reason{
+						{
+							Type:    kueue.InadmissibleReasonUntoleratedTaint,
+							Flavor:  "spot",
+							Message: "untolerated taint {instance spot NoSchedule <nil>} in flavor spot",
+						},
+						{
+							Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+							Resource: corev1.ResourceCPU,
+							Flavor:   "default",
+							Missing:  3500,
+							Message:  "insufficient unused quota for cpu flavor default, 3500m more needed",
+						},
+					},
+				},
+			},
+		},
+	}
+	want := []kueue.InadmissibleReason{
+		{
+			PodSet:   "workers",
+			Type:     kueue.InadmissibleReasonInsufficientUnusedQuota,
+			Resource: corev1.ResourceCPU,
+			Flavor:   "default",
+			Missing:  pointer.Quantity(resource.MustParse("3500m")),
+			Message:  "insufficient unused quota for cpu flavor default, 3500m more needed",
+		},
+		{
+			PodSet:  "workers",
+			Type:    kueue.InadmissibleReasonUntoleratedTaint,
+			Flavor:  "spot",
+			Message: "untolerated taint {instance spot NoSchedule <nil>} in flavor spot",
+		},
+	}
+	if diff := cmp.Diff(want, assignment.InadmissibleReasons()); diff != "" {
+		t.Errorf("Unexpected inadmissible reasons (-want,+got):\n%s", diff)
+	}
+}
+
+func TestFlavorCost(t *testing.T) {
+	cases := map[string]struct {
+		rf   *kueue.ResourceFlavor
+		want float64
+	}{
+		"no flavor": {
+			want: math.Inf(1),
+		},
+		"no annotation": {
+			rf:   utiltesting.MakeResourceFlavor("default").Obj(),
+			want: math.Inf(1),
+		},
+		"valid cost": {
+			rf:   utiltesting.MakeResourceFlavor("spot").Cost("0.3").Obj(),
+			want: 0.3,
+		},
+		"invalid cost": {
+			rf:   utiltesting.MakeResourceFlavor("spot").Cost("cheap").Obj(),
+			want: math.Inf(1),
+		},
+		"negative cost": {
+			rf:   utiltesting.MakeResourceFlavor("spot").Cost("-1").Obj(),
+			want: math.Inf(1),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := FlavorCost(tc.rf); got != tc.want {
+				t.Errorf("FlavorCost() = %v, want %v", got, tc.want)
+			}
 		})
 	}
 }
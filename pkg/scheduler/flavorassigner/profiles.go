@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flavorassigner
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/cache"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// FlavorCandidate is a flavor of a resource group that is considered for the
+// resources requested by a pod set.
+type FlavorCandidate struct {
+	// Index is the position of the flavor in the resource group.
+	Index int
+	// Flavor is the ResourceFlavor, or nil if it doesn't exist.
+	Flavor *kueue.ResourceFlavor
+	// Unused is the lowest fraction of min quota of the flavor that is unused,
+	// among the requested resources, considering the usage by the previous
+	// pod sets of the workload. It's negative if the ClusterQueue borrows
+	// quota, and 0 if the flavor has no min quota.
+	Unused float64
+}
+
+// Strategy reports whether the candidate a should be tried before b. The
+// candidates that are equally preferred are tried in the order in which they
+// are listed. A nil Strategy keeps the order of the list.
+type Strategy func(a, b *FlavorCandidate) bool
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[kueue.SchedulingProfile]Strategy{
+		kueue.SchedulingProfileOrdered:  nil,
+		kueue.SchedulingProfileBestFit:  bestFit,
+		kueue.SchedulingProfileCheapest: cheapest,
+		kueue.SchedulingProfileSpread:   spread,
+	}
+)
+
+// RegisterStrategy registers the strategy of a scheduling profile, replacing
+// any strategy registered for it.
+func RegisterStrategy(profile kueue.SchedulingProfile, s Strategy) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	strategies[profile] = s
+}
+
+func strategyFor(profile kueue.SchedulingProfile) Strategy {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	return strategies[profile]
+}
+
+func bestFit(a, b *FlavorCandidate) bool {
+	return a.Unused < b.Unused
+}
+
+func spread(a, b *FlavorCandidate) bool {
+	return a.Unused > b.Unused
+}
+
+func cheapest(a, b *FlavorCandidate) bool {
+	return FlavorCost(a.Flavor) < FlavorCost(b.Flavor)
+}
+
+// FlavorCost returns the cost declared in the annotation of the flavor, or
+// +Inf if it doesn't declare a valid one.
+func FlavorCost(rf *kueue.ResourceFlavor) float64 {
+	if rf == nil {
+		return math.Inf(1)
+	}
+	v, found := rf.Annotations[kueue.ResourceFlavorCostAnnotation]
+	if !found {
+		return math.Inf(1)
+	}
+	cost, err := strconv.ParseFloat(v, 64)
+	if err != nil || cost < 0 || math.IsNaN(cost) {
+		return math.Inf(1)
+	}
+	return cost
+}
+
+// flavorOrder returns the indexes of the flavors of the resource, in the order
+// in which the scheduling profile of the ClusterQueue tries them for the
+// requests.
+func (a *Assignment) flavorOrder(rName corev1.ResourceName, requests workload.Requests, resourceFlavors map[string]*kueue.ResourceFlavor, cq *cache.ClusterQueue) []int {
+	flavors := cq.RequestableResources[rName].Flavors
+	order := make([]int, len(flavors))
+	for i := range order {
+		order[i] = i
+	}
+	s := strategyFor(cq.SchedulingProfile)
+	if s == nil {
+		return order
+	}
+	candidates := make([]FlavorCandidate, len(flavors))
+	for i, flv := range flavors {
+		candidates[i] = FlavorCandidate{
+			Index:  i,
+			Flavor: resourceFlavors[flv.Name],
+			Unused: math.Inf(1),
+		}
+		for name := range requests {
+			limits := cq.RequestableResources[name].Flavors[i]
+			unused := 0.0
+			if limits.Min > 0 {
+				used := cq.UsedResources[name][limits.Name] + a.usage[name][limits.Name]
+				unused = float64(limits.Min-used) / float64(limits.Min)
+			}
+			candidates[i].Unused = math.Min(candidates[i].Unused, unused)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return s(&candidates[i], &candidates[j])
+	})
+	for i := range candidates {
+		order[i] = candidates[i].Index
+	}
+	return order
+}
+
+// preferredFirst returns the order of the flavors with the preferred ones
+// moved to the front, keeping the relative order of both groups.
+func preferredFirst(order []int, flavors []cache.FlavorLimits, preferred sets.Set[string]) []int {
+	if preferred.Len() == 0 {
+		return order
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return preferred.Has(flavors[order[i]].Name) && !preferred.Has(flavors[order[j]].Name)
+	})
+	return order
+}
@@ -18,8 +18,8 @@ package preemption
 
 import (
 	"context"
+	"fmt"
 	"sort"
-	"sync/atomic"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,8 +34,8 @@ import (
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/cache"
-	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
+	"sigs.k8s.io/kueue/pkg/util/api"
 	"sigs.k8s.io/kueue/pkg/util/priority"
 	"sigs.k8s.io/kueue/pkg/util/routine"
 	"sigs.k8s.io/kueue/pkg/workload"
@@ -46,15 +46,27 @@ const parallelPreemptions = 8
 type Preemptor struct {
 	client   client.Client
 	recorder record.EventRecorder
+	// fieldManager is the field manager of the admissions and preemptions of
+	// the scheduler.
+	fieldManager string
+	// fieldOwner returns the field owner of the preemptions, which is the one
+	// of the admissions of the scheduler.
+	fieldOwner FieldOwnerFunc
 
 	// stubs
 	applyPreemption func(context.Context, *kueue.Workload) error
 }
 
-func New(cl client.Client, recorder record.EventRecorder) *Preemptor {
+// FieldOwnerFunc returns the field owner with which the preemptions are
+// applied, or an error if they must not be applied.
+type FieldOwnerFunc func(context.Context) (string, error)
+
+func New(cl client.Client, recorder record.EventRecorder, fieldManager string, fieldOwner FieldOwnerFunc) *Preemptor {
 	p := &Preemptor{
-		client:   cl,
-		recorder: recorder,
+		client:       cl,
+		recorder:     recorder,
+		fieldManager: fieldManager,
+		fieldOwner:   fieldOwner,
 	}
 	p.applyPreemption = p.applyPreemptionWithSSA
 	return p
@@ -64,55 +76,122 @@ func (p *Preemptor) OverrideApply(f func(context.Context, *kueue.Workload) error
 	p.applyPreemption = f
 }
 
-func (p *Preemptor) Do(ctx context.Context, wl workload.Info, assignment flavorassigner.Assignment, snapshot *cache.Snapshot) (int, error) {
-	log := ctrl.LoggerFrom(ctx)
+func (p *Preemptor) Do(ctx context.Context, wl workload.Info, assignment flavorassigner.Assignment, snapshot *cache.Snapshot) ([]*workload.Info, error) {
+	log := ctrl.LoggerFrom(ctx).WithName("preemption")
+	ctx = ctrl.LoggerInto(ctx, log)
 
+	targets := Targets(ctx, wl, assignment, snapshot)
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	return p.issuePreemptions(ctx, &wl, targets, snapshot.ClusterQueues[wl.ClusterQueue])
+}
+
+// Targets returns the workloads that need to be preempted for the workload to
+// fit with the assignment, or none if preempting the workloads allowed for
+// preemption doesn't free enough quota. The targets are removed from the
+// snapshot.
+func Targets(ctx context.Context, wl workload.Info, assignment flavorassigner.Assignment, snapshot *cache.Snapshot) []*workload.Info {
+	log := ctrl.LoggerFrom(ctx)
 	flavors := flavorsRequiringPreemption(assignment)
 	cq := snapshot.ClusterQueues[wl.ClusterQueue]
 
-	candidates := findCandidates(wl.Obj, cq, flavors)
+	now := time.Now()
+	candidates := findCandidates(wl.Obj, cq, flavors, now)
 	if len(candidates) == 0 {
 		log.V(2).Info("Workload requires preemption, but there are no candidate workloads allowed for preemption", "preemptionReclaimWithinCohort", cq.Preemption.ReclaimWithinCohort, "preemptionWithinClusterQueue", cq.Preemption.WithinClusterQueue)
-		return 0, nil
+		return nil
+	}
+	var borrowing sets.Set[string]
+	if cq.Preemption.VictimOrder == kueue.PreemptionVictimOrderBorrowingFirst {
+		borrowing = cq.BorrowingWorkloads()
 	}
-	sort.Slice(candidates, candidatesOrdering(candidates, cq.Name, time.Now()))
+	sort.Slice(candidates, candidatesOrdering(candidates, cq.Name, borrowing, now))
 
 	targets := minimalPreemptions(&wl, assignment, snapshot, flavors, candidates)
-
 	if len(targets) == 0 {
 		log.V(2).Info("Workload requires preemption, but there are not enough candidate workloads allowed for preemption")
-		return 0, nil
 	}
-
-	return p.issuePreemptions(ctx, targets, cq)
+	return targets
 }
 
-func (p *Preemptor) issuePreemptions(ctx context.Context, targets []*workload.Info, cq *cache.ClusterQueue) (int, error) {
+// issuePreemptions evicts the targets, and returns the ones that were
+// successfully preempted.
+func (p *Preemptor) issuePreemptions(ctx context.Context, preemptor *workload.Info, targets []*workload.Info, cq *cache.ClusterQueue) ([]*workload.Info, error) {
 	log := ctrl.LoggerFrom(ctx)
 	errCh := routine.NewErrorChannel()
 	ctx, cancel := context.WithCancel(ctx)
-	var successfullyPreempted int64
+	preempted := make([]bool, len(targets))
 	defer cancel()
 	workqueue.ParallelizeUntil(ctx, parallelPreemptions, len(targets), func(i int) {
 		target := targets[i]
-		err := p.applyPreemption(ctx, workload.ClearAdmissionPatch(target.Obj))
-		if err != nil {
-			errCh.SendErrorWithCancel(err, cancel)
+		if workload.IsEvicted(target.Obj) {
+			// The target is already being evicted; its quota is released once
+			// its job is suspended.
+			preempted[i] = true
 			return
 		}
 		origin := "ClusterQueue"
 		if cq.Name != target.ClusterQueue {
 			origin = "cohort"
 		}
-		log.V(3).Info("Preempted", "targetWorkload", klog.KObj(target.Obj))
-		p.recorder.Eventf(target.Obj, corev1.EventTypeNormal, "Preempted", "Preempted by another workload in the %s", origin)
-		atomic.AddInt64(&successfullyPreempted, 1)
+		msg := fmt.Sprintf("Preempted by workload %s (UID: %s) in the %s", workload.Key(preemptor.Obj), preemptor.Obj.UID, origin)
+		err := p.applyPreemption(ctx, workload.PreemptionPatch(target.Obj, preemptor.Obj, msg))
+		if err != nil {
+			errCh.SendErrorWithCancel(err, cancel)
+			return
+		}
+		log.V(3).Info("Preempted", "targetWorkload", klog.KObj(target.Obj), "preemptor", klog.KObj(preemptor.Obj))
+		p.recorder.Event(target.Obj, corev1.EventTypeNormal, "Preempted", msg)
+		preempted[i] = true
 	})
-	return int(successfullyPreempted), errCh.ReceiveError()
+	var successfullyPreempted []*workload.Info
+	for i, target := range targets {
+		if preempted[i] {
+			successfullyPreempted = append(successfullyPreempted, target)
+		}
+	}
+	return successfullyPreempted, errCh.ReceiveError()
 }
 
 func (p *Preemptor) applyPreemptionWithSSA(ctx context.Context, w *kueue.Workload) error {
-	return p.client.Patch(ctx, w, client.Apply, client.FieldOwner(constants.AdmissionName))
+	fieldOwner, err := p.fieldOwner(ctx)
+	if err != nil {
+		return err
+	}
+	if err := p.applyFenced(ctx, p.client.Status(), w, fieldOwner); err != nil {
+		return err
+	}
+	// The workload is evicted once its status is patched, so failing to
+	// annotate it with its preemptor doesn't fail the preemption.
+	if err := p.applyFenced(ctx, p.client, workload.AnnotationsPatch(w), fieldOwner); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Could not annotate the preempted workload with its preemptor", "targetWorkload", klog.KObj(w))
+	}
+	return nil
+}
+
+type patcher interface {
+	Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error
+}
+
+// applyFenced applies the patch without forcing the ownership of the fields,
+// so that a deposed leader can't overwrite the evictions of the current one.
+// Only the conflicts with the field owners that aren't a newer leadership
+// term of the scheduler, such as the controller that requeues the evicted
+// workloads or the scheduler of a previous term, are then forced.
+func (p *Preemptor) applyFenced(ctx context.Context, c patcher, obj client.Object, fieldOwner string) error {
+	err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldOwner))
+	conflicts := api.ApplyConflicts(err)
+	if len(conflicts) == 0 {
+		return err
+	}
+	token, fenced := workload.FencingToken(p.fieldManager, fieldOwner)
+	for _, conflict := range conflicts {
+		if otherToken, ok := workload.FencingToken(p.fieldManager, conflict.Manager); ok && (!fenced || otherToken > token) {
+			return err
+		}
+	}
+	return c.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldOwner), client.ForceOwnership)
 }
 
 // minimalPreemptions implements a heuristic to find a minimal set of Workloads
@@ -131,7 +210,7 @@ func minimalPreemptions(wl *workload.Info, assignment flavorassigner.Assignment,
 	fits := false
 	for _, candWl := range candidates {
 		candCQ := snapshot.ClusterQueues[candWl.ClusterQueue]
-		if cq != candCQ && !cqIsBorrowing(candCQ, flavors) {
+		if cq != candCQ && !cqIsBorrowing(candCQ, cq.Cohort, flavors) {
 			continue
 		}
 		snapshot.RemoveWorkload(candWl)
@@ -180,20 +259,29 @@ func flavorsRequiringPreemption(assignment flavorassigner.Assignment) flavorsPer
 
 // findCandidates obtains candidates for preemption within the ClusterQueue and
 // cohort that respect the preemption policy and are using a flavor that the
-// preempting workload needs.
-func findCandidates(wl *kueue.Workload, cq *cache.ClusterQueue, flavors flavorsPerResource) []*workload.Info {
+// preempting workload needs. The priority of the preempting workload is its
+// effective priority, given the priority aging policy of its ClusterQueue.
+// With the LowerOrNewerEqualPriority policy, the workloads in the ClusterQueue
+// with the same priority are also candidates if they were created after the
+// preempting workload. Comparing the creation instead of the admission times
+// keeps two workloads from preempting each other after each requeue.
+func findCandidates(wl *kueue.Workload, cq *cache.ClusterQueue, flavors flavorsPerResource, now time.Time) []*workload.Info {
 	var candidates []*workload.Info
+	wlPriority := priority.EffectivePriority(wl, cq.PriorityAging, now)
 	cqs := sets.New(cq)
 	if cq.Cohort != nil && cq.Preemption.ReclaimWithinCohort != kueue.PreemptionPolicyNever {
-		cqs = cq.Cohort.Members
+		cqs = cq.Cohort.Root().AllMembers()
 	}
 	if cq.Preemption.WithinClusterQueue == kueue.PreemptionPolicyNever {
 		cqs.Delete(cq)
 	}
 	for cohortCQ := range cqs {
 		onlyLowerPrio := true
-		if cq != cohortCQ {
-			if !cqIsBorrowing(cohortCQ, flavors) {
+		newerEqualPrio := false
+		if cq == cohortCQ {
+			newerEqualPrio = cq.Preemption.WithinClusterQueue == kueue.PreemptionPolicyLowerOrNewerEqualPriority
+		} else {
+			if !cqIsBorrowing(cohortCQ, cq.Cohort, flavors) {
 				// Can't reclaim quota from ClusterQueues that are not borrowing.
 				continue
 			}
@@ -202,7 +290,7 @@ func findCandidates(wl *kueue.Workload, cq *cache.ClusterQueue, flavors flavorsP
 			}
 		}
 		for _, candidateWl := range cohortCQ.Workloads {
-			if onlyLowerPrio && priority.Priority(candidateWl.Obj) >= priority.Priority(wl) {
+			if onlyLowerPrio && !lowerOrNewerEqualPriority(candidateWl.Obj, wlPriority, wl, newerEqualPrio) {
 				continue
 			}
 			if !workloadUsesFlavors(candidateWl, flavors) {
@@ -214,7 +302,45 @@ func findCandidates(wl *kueue.Workload, cq *cache.ClusterQueue, flavors flavorsP
 	return candidates
 }
 
-func cqIsBorrowing(cq *cache.ClusterQueue, flavors flavorsPerResource) bool {
+// lowerOrNewerEqualPriority returns whether the candidate has lower priority
+// than the preemptor or, if newerEqualPrio, the same priority and a later
+// creation time.
+func lowerOrNewerEqualPriority(candidate *kueue.Workload, preemptorPriority int32, preemptor *kueue.Workload, newerEqualPrio bool) bool {
+	candidatePriority := priority.Priority(candidate)
+	if candidatePriority < preemptorPriority {
+		return true
+	}
+	return newerEqualPrio && candidatePriority == preemptorPriority && preemptor.CreationTimestamp.Before(&candidate.CreationTimestamp)
+}
+
+// cqIsBorrowing returns whether the ClusterQueue is borrowing any of the
+// flavors from the given cohort. That is, whether the ClusterQueue, and each
+// cohort between the ClusterQueue and the given cohort, use more than their
+// quota.
+func cqIsBorrowing(cq *cache.ClusterQueue, from *cache.Cohort, flavors flavorsPerResource) bool {
+	if !cqIsBorrowingFlavors(cq, flavors) {
+		return false
+	}
+	for cohort := cq.Cohort; cohort != nil && !cohort.HasDescendant(from); cohort = cohort.Parent {
+		if !cohortIsBorrowing(cohort, flavors) {
+			return false
+		}
+	}
+	return true
+}
+
+func cohortIsBorrowing(cohort *cache.Cohort, flavors flavorsPerResource) bool {
+	for res, rFlavors := range flavors {
+		for flavor := range rFlavors {
+			if cohort.UsedResources[res][flavor] > cohort.RequestableResources[res][flavor] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func cqIsBorrowingFlavors(cq *cache.ClusterQueue, flavors flavorsPerResource) bool {
 	for res, rFlavors := range flavors {
 		fUsage := cq.UsedResources[res]
 		requestable := cq.RequestableResources[res]
@@ -234,7 +360,7 @@ func cqIsBorrowing(cq *cache.ClusterQueue, flavors flavorsPerResource) bool {
 func workloadUsesFlavors(wl *workload.Info, flavors flavorsPerResource) bool {
 	for _, ps := range wl.TotalRequests {
 		for res, flv := range ps.Flavors {
-			if flavors[res].Has(flv) {
+			if flavors[res].Has(flv) || flavors[res].Has(string(kueue.AnyFlavor)) {
 				return true
 			}
 		}
@@ -268,8 +394,9 @@ func workloadFits(wlReq cache.ResourceQuantities, cq *cache.ClusterQueue) bool {
 		cqResRequestable := cq.RequestableResources[res]
 		var cohortFlvsUsage, cohortFlvsRequestable map[string]int64
 		if cq.Cohort != nil {
-			cohortFlvsUsage = cq.Cohort.UsedResources[res]
-			cohortFlvsRequestable = cq.Cohort.RequestableResources[res]
+			root := cq.Cohort.Root()
+			cohortFlvsUsage = root.UsedResources[res]
+			cohortFlvsRequestable = root.RequestableResources[res]
 		}
 		for _, flvLimits := range cqResRequestable.Flavors {
 			flvReq, ok := flvReqs[flvLimits.Name]
@@ -277,13 +404,21 @@ func workloadFits(wlReq cache.ResourceQuantities, cq *cache.ClusterQueue) bool {
 				// Workload doesn't request this flavor.
 				continue
 			}
-			if cqFlvsUsage[flvLimits.Name]+flvReq > flvLimits.Min {
+			used := cqFlvsUsage[flvLimits.Name]
+			if used+flvReq > flvLimits.Min {
 				return false
 			}
+			// Only the usage beyond the guaranteed quota is accounted in the cohort.
+			cohortReq := flvLimits.LentUsage(used+flvReq) - flvLimits.LentUsage(used)
+			for cohort := cq.Cohort; cohort != nil; cohort = cohort.Parent {
+				if limit, found := cohort.Limits[res][flvLimits.Name]; found && cohort.UsedResources[res][flvLimits.Name]+cohortReq > limit {
+					return false
+				}
+			}
 			if len(cohortFlvsRequestable) == 0 {
 				continue
 			}
-			if cohortFlvsUsage[flvLimits.Name]+flvReq > cohortFlvsRequestable[flvLimits.Name] {
+			if cohortFlvsUsage[flvLimits.Name]+cohortReq > cohortFlvsRequestable[flvLimits.Name] {
 				return false
 			}
 		}
@@ -292,14 +427,21 @@ func workloadFits(wlReq cache.ResourceQuantities, cq *cache.ClusterQueue) bool {
 }
 
 // candidatesOrdering criteria:
-// 1. Workloads from other ClusterQueues in the cohort before the ones in the
+// 1. Workloads in the same ClusterQueue as the preemptor that are in the
+// borrowing set, which makes the ClusterQueue borrow quota, first.
+// 2. Workloads from other ClusterQueues in the cohort before the ones in the
 // same ClusterQueue as the preemptor.
-// 2. Workloads with lower priority first.
-// 3. Workloads admited more recently first.
-func candidatesOrdering(candidates []*workload.Info, cq string, now time.Time) func(int, int) bool {
+// 3. Workloads with lower priority first.
+// 4. Workloads admited more recently first.
+func candidatesOrdering(candidates []*workload.Info, cq string, borrowing sets.Set[string], now time.Time) func(int, int) bool {
 	return func(i, j int) bool {
 		a := candidates[i]
 		b := candidates[j]
+		aBorrowing := a.ClusterQueue == cq && borrowing.Has(workload.Key(a.Obj))
+		bBorrowing := b.ClusterQueue == cq && borrowing.Has(workload.Key(b.Obj))
+		if aBorrowing != bBorrowing {
+			return aBorrowing
+		}
 		aInCQ := a.ClusterQueue == cq
 		bInCQ := b.ClusterQueue == cq
 		if aInCQ != bInCQ {
@@ -18,7 +18,10 @@ package preemption
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -27,10 +30,14 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
@@ -42,6 +49,7 @@ import (
 )
 
 func TestPreemption(t *testing.T) {
+	now := time.Now()
 	flavors := []*kueue.ResourceFlavor{
 		utiltesting.MakeResourceFlavor("default").Obj(),
 		utiltesting.MakeResourceFlavor("alpha").Obj(),
@@ -80,6 +88,39 @@ func TestPreemption(t *testing.T) {
 				ReclaimWithinCohort: kueue.PreemptionPolicyAny,
 			}).
 			Obj(),
+		utiltesting.MakeClusterQueue("aging").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "6").Obj()).
+				Obj()).
+			Preemption(kueue.ClusterQueuePreemption{
+				WithinClusterQueue: kueue.PreemptionPolicyLowerPriority,
+			}).
+			PriorityAging(kueue.PriorityAging{IntervalSeconds: 60, Step: 1}).
+			Obj(),
+		utiltesting.MakeClusterQueue("newer").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "6").Obj()).
+				Obj()).
+			Preemption(kueue.ClusterQueuePreemption{
+				WithinClusterQueue: kueue.PreemptionPolicyLowerOrNewerEqualPriority,
+			}).
+			Obj(),
+		utiltesting.MakeClusterQueue("borrowing-first").
+			Cohort("cohort-bf").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "4").Obj()).
+				Obj()).
+			Preemption(kueue.ClusterQueuePreemption{
+				WithinClusterQueue: kueue.PreemptionPolicyLowerPriority,
+				VictimOrder:        kueue.PreemptionVictimOrderBorrowingFirst,
+			}).
+			Obj(),
+		utiltesting.MakeClusterQueue("lender").
+			Cohort("cohort-bf").
+			Resource(utiltesting.MakeResource(corev1.ResourceCPU).
+				Flavor(utiltesting.MakeFlavor("default", "4").Obj()).
+				Obj()).
+			Obj(),
 	}
 	cases := map[string]struct {
 		admitted      []kueue.Workload
@@ -173,6 +214,119 @@ func TestPreemption(t *testing.T) {
 				},
 			}),
 		},
+		"preempt lower than the effective priority": {
+			admitted: []kueue.Workload{
+				*utiltesting.MakeWorkload("mid", "").
+					Request(corev1.ResourceCPU, "3").
+					Admit(utiltesting.MakeAdmission("aging").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("high", "").
+					Priority(2).
+					Request(corev1.ResourceCPU, "3").
+					Admit(utiltesting.MakeAdmission("aging").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Obj(),
+			},
+			incoming: utiltesting.MakeWorkload("in", "").
+				Priority(-1).
+				Creation(time.Now().Add(-150*time.Second)).
+				Request(corev1.ResourceCPU, "1").
+				Obj(),
+			targetCQ: "aging",
+			assignment: singlePodSetAssignment(flavorassigner.ResourceAssignment{
+				corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+					Name: "default",
+					Mode: flavorassigner.Preempt,
+				},
+			}),
+			wantPreempted: sets.New("/mid"),
+		},
+		"preempt newer workloads with the same priority": {
+			admitted: []kueue.Workload{
+				*utiltesting.MakeWorkload("older", "").
+					Creation(now.Add(-2*time.Minute)).
+					Request(corev1.ResourceCPU, "3").
+					Admit(utiltesting.MakeAdmission("newer").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("newer", "").
+					Creation(now).
+					Request(corev1.ResourceCPU, "3").
+					Admit(utiltesting.MakeAdmission("newer").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Obj(),
+			},
+			incoming: utiltesting.MakeWorkload("in", "").
+				Creation(now.Add(-time.Minute)).
+				Request(corev1.ResourceCPU, "2").
+				Obj(),
+			targetCQ: "newer",
+			assignment: singlePodSetAssignment(flavorassigner.ResourceAssignment{
+				corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+					Name: "default",
+					Mode: flavorassigner.Preempt,
+				},
+			}),
+			wantPreempted: sets.New("/newer"),
+		},
+		"can't preempt older workloads with the same priority": {
+			admitted: []kueue.Workload{
+				*utiltesting.MakeWorkload("older", "").
+					Creation(now.Add(-2*time.Minute)).
+					Request(corev1.ResourceCPU, "3").
+					Admit(utiltesting.MakeAdmission("newer").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("high", "").
+					Priority(1).
+					Creation(now).
+					Request(corev1.ResourceCPU, "3").
+					Admit(utiltesting.MakeAdmission("newer").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Obj(),
+			},
+			incoming: utiltesting.MakeWorkload("in", "").
+				Creation(now.Add(-time.Minute)).
+				Request(corev1.ResourceCPU, "2").
+				Obj(),
+			targetCQ: "newer",
+			assignment: singlePodSetAssignment(flavorassigner.ResourceAssignment{
+				corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+					Name: "default",
+					Mode: flavorassigner.Preempt,
+				},
+			}),
+		},
+		"preempt the borrowing workloads first": {
+			admitted: []kueue.Workload{
+				*utiltesting.MakeWorkload("low", "").
+					Priority(-1).
+					Request(corev1.ResourceCPU, "3").
+					Admit(utiltesting.MakeAdmission("borrowing-first").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Condition(metav1.Condition{
+						Type:               kueue.WorkloadAdmitted,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+					}).
+					Obj(),
+				*utiltesting.MakeWorkload("borrowing", "").
+					Request(corev1.ResourceCPU, "2").
+					Admit(utiltesting.MakeAdmission("borrowing-first").Flavor(corev1.ResourceCPU, "default").Obj()).
+					Condition(metav1.Condition{
+						Type:               kueue.WorkloadAdmitted,
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(now.Add(-time.Minute)),
+					}).
+					Obj(),
+			},
+			incoming: utiltesting.MakeWorkload("in", "").
+				Priority(1).
+				Request(corev1.ResourceCPU, "1").
+				Obj(),
+			targetCQ: "borrowing-first",
+			assignment: singlePodSetAssignment(flavorassigner.ResourceAssignment{
+				corev1.ResourceCPU: &flavorassigner.FlavorAssignment{
+					Name: "default",
+					Mode: flavorassigner.Preempt,
+				},
+			}),
+			wantPreempted: sets.New("/borrowing"),
+		},
 		"not enough low priority workloads": {
 			admitted: []kueue.Workload{
 				*utiltesting.MakeWorkload("low", "").
@@ -568,20 +722,34 @@ func TestPreemption(t *testing.T) {
 				}
 			}
 
+			incoming := tc.incoming.DeepCopy()
+			incoming.UID = types.UID("uid-" + incoming.Name)
 			var lock sync.Mutex
 			gotPreempted := sets.New[string]()
 			broadcaster := record.NewBroadcaster()
 			recorder := broadcaster.NewRecorder(scheme, corev1.EventSource{Component: constants.AdmissionName})
-			preemptor := New(cl, recorder)
+			preemptor := New(cl, recorder, constants.AdmissionName, func(context.Context) (string, error) {
+				return constants.AdmissionName, nil
+			})
 			preemptor.applyPreemption = func(ctx context.Context, w *kueue.Workload) error {
 				lock.Lock()
 				gotPreempted.Insert(workload.Key(w))
 				lock.Unlock()
+				evicted := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadEvicted)
+				if evicted == nil || evicted.Reason != kueue.WorkloadEvictedByPreemption || !strings.Contains(evicted.Message, workload.Key(incoming)) {
+					t.Errorf("Preempted workload %s has Evicted condition %v, want reason %s naming the preemptor %s", workload.Key(w), evicted, kueue.WorkloadEvictedByPreemption, workload.Key(incoming))
+				}
+				if got, want := w.Annotations[constants.PreemptorAnnotation], workload.Key(incoming); got != want {
+					t.Errorf("Preempted workload %s annotated with preemptor %q, want %q", workload.Key(w), got, want)
+				}
+				if got, want := w.Annotations[constants.PreemptorUIDAnnotation], string(incoming.UID); got != want {
+					t.Errorf("Preempted workload %s annotated with preemptor UID %q, want %q", workload.Key(w), got, want)
+				}
 				return nil
 			}
 
 			snapshot := cqCache.Snapshot()
-			wlInfo := workload.NewInfo(tc.incoming)
+			wlInfo := workload.NewInfo(incoming)
 			wlInfo.ClusterQueue = tc.targetCQ
 			preempted, err := preemptor.Do(ctx, *wlInfo, tc.assignment, &snapshot)
 			if err != nil {
@@ -590,8 +758,135 @@ func TestPreemption(t *testing.T) {
 			if diff := cmp.Diff(tc.wantPreempted, gotPreempted, cmpopts.EquateEmpty()); diff != "" {
 				t.Errorf("Issued preemptions (-want,+got):\n%s", diff)
 			}
-			if preempted != tc.wantPreempted.Len() {
-				t.Errorf("Reported %d preemptions, want %d", preempted, tc.wantPreempted.Len())
+			if len(preempted) != tc.wantPreempted.Len() {
+				t.Errorf("Reported %d preemptions, want %d", len(preempted), tc.wantPreempted.Len())
+			}
+		})
+	}
+}
+
+// conflictingPatcher fails the patches that don't force the ownership of the
+// fields with a conflict with the given field manager.
+type conflictingPatcher struct {
+	conflictManager string
+	forced          []bool
+}
+
+func (p *conflictingPatcher) Patch(_ context.Context, _ client.Object, _ client.Patch, opts ...client.PatchOption) error {
+	patchOpts := &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	force := patchOpts.Force != nil && *patchOpts.Force
+	p.forced = append(p.forced, force)
+	if p.conflictManager == "" || force {
+		return nil
+	}
+	return apierrors.NewApplyConflict([]metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: fmt.Sprintf("conflict with %q with subresource \"status\" using kueue.x-k8s.io/v1alpha2", p.conflictManager),
+		Field:   ".status.conditions",
+	}}, "Apply failed with 1 conflict")
+}
+
+func TestApplyFenced(t *testing.T) {
+	cases := map[string]struct {
+		fieldOwner      string
+		conflictManager string
+		wantForced      []bool
+		wantErr         bool
+	}{
+		"no conflict": {
+			fieldOwner: "kueue-admission-3",
+			wantForced: []bool{false},
+		},
+		"conflict with another controller": {
+			fieldOwner:      "kueue-admission-3",
+			conflictManager: "kueue",
+			wantForced:      []bool{false, true},
+		},
+		"conflict with a previous term": {
+			fieldOwner:      "kueue-admission-3",
+			conflictManager: "kueue-admission-2",
+			wantForced:      []bool{false, true},
+		},
+		"conflict with a newer term": {
+			fieldOwner:      "kueue-admission-3",
+			conflictManager: "kueue-admission-4",
+			wantForced:      []bool{false},
+			wantErr:         true,
+		},
+		"conflict with a term without fencing": {
+			fieldOwner:      "kueue-admission",
+			conflictManager: "kueue-admission-4",
+			wantForced:      []bool{false},
+			wantErr:         true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			preemptor := New(nil, nil, constants.AdmissionName, nil)
+			c := &conflictingPatcher{conflictManager: tc.conflictManager}
+			err := preemptor.applyFenced(context.Background(), c, utiltesting.MakeWorkload("wl", "ns").Obj(), tc.fieldOwner)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("applyFenced() returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.wantForced, c.forced); diff != "" {
+				t.Errorf("Unexpected forced patches (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// preemptionClient fails the patches of the status or of the annotations of
+// the preempted workloads.
+type preemptionClient struct {
+	client.Client
+	statusErr      error
+	annotationsErr error
+}
+
+func (c *preemptionClient) Patch(context.Context, client.Object, client.Patch, ...client.PatchOption) error {
+	return c.annotationsErr
+}
+
+func (c *preemptionClient) Status() client.StatusWriter {
+	return &preemptionStatusWriter{c: c}
+}
+
+type preemptionStatusWriter struct {
+	client.StatusWriter
+	c *preemptionClient
+}
+
+func (w *preemptionStatusWriter) Patch(context.Context, client.Object, client.Patch, ...client.PatchOption) error {
+	return w.c.statusErr
+}
+
+func TestApplyPreemptionWithSSA(t *testing.T) {
+	cases := map[string]struct {
+		statusErr      error
+		annotationsErr error
+		wantErr        bool
+	}{
+		"preempted": {},
+		"status patch failed": {
+			statusErr: errors.New("status patch failed"),
+			wantErr:   true,
+		},
+		"annotations patch failed": {
+			annotationsErr: errors.New("annotations patch failed"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cl := &preemptionClient{statusErr: tc.statusErr, annotationsErr: tc.annotationsErr}
+			preemptor := New(cl, nil, constants.AdmissionName, func(context.Context) (string, error) {
+				return constants.AdmissionName, nil
+			})
+			target := utiltesting.MakeWorkload("target", "ns").Obj()
+			preemptorWl := utiltesting.MakeWorkload("preemptor", "ns").Obj()
+			err := preemptor.applyPreemption(context.Background(), workload.PreemptionPatch(target, preemptorWl, "Preempted"))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("applyPreemption() returned error %v, want error: %t", err, tc.wantErr)
 			}
 		})
 	}
@@ -625,14 +920,30 @@ func TestCandidatesOrdering(t *testing.T) {
 			Admit(utiltesting.MakeAdmission("self").Obj()).
 			Obj()),
 	}
-	sort.Slice(candidates, candidatesOrdering(candidates, "self", now))
-	gotNames := make([]string, len(candidates))
-	for i, c := range candidates {
-		gotNames[i] = workload.Key(c.Obj)
+	cases := map[string]struct {
+		borrowing      sets.Set[string]
+		wantCandidates []string
+	}{
+		"by priority": {
+			wantCandidates: []string{"/other", "/low", "/current", "/old", "/high"},
+		},
+		"borrowing first": {
+			borrowing:      sets.New("/high", "/other"),
+			wantCandidates: []string{"/high", "/other", "/low", "/current", "/old"},
+		},
 	}
-	wantCandidates := []string{"/other", "/low", "/current", "/old", "/high"}
-	if diff := cmp.Diff(wantCandidates, gotNames); diff != "" {
-		t.Errorf("Sorted with wrong order (-want,+got):\n%s", diff)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			candidates := append([]*workload.Info(nil), candidates...)
+			sort.Slice(candidates, candidatesOrdering(candidates, "self", tc.borrowing, now))
+			gotNames := make([]string, len(candidates))
+			for i, c := range candidates {
+				gotNames[i] = workload.Key(c.Obj)
+			}
+			if diff := cmp.Diff(tc.wantCandidates, gotNames); diff != "" {
+				t.Errorf("Sorted with wrong order (-want,+got):\n%s", diff)
+			}
+		})
 	}
 }
 
@@ -18,19 +18,24 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,6 +54,14 @@ import (
 
 const (
 	errCouldNotAdmitWL = "Could not admit Workload and assign flavors in apiserver"
+
+	// DefaultAdmissionPatchWorkers is the default maximum number of admission
+	// patches that are applied concurrently.
+	DefaultAdmissionPatchWorkers = 8
+
+	// preemptionReservationTimeout is how long the quota needed by a workload
+	// that preempted other workloads is reserved for it.
+	preemptionReservationTimeout = time.Minute
 )
 
 type Scheduler struct {
@@ -59,13 +72,46 @@ type Scheduler struct {
 	admissionRoutineWrapper routine.Wrapper
 	preemptor               *preemption.Preemptor
 	waitForPodsReady        bool
+	requeuingBackoff        *requeuingBackoff
+	workloadInfoOpts        []workload.InfoOption
+	auditor                 *auditor
+	cohortConcurrency       int
+	// admissionWorkers bounds the number of admission patches that are
+	// applied concurrently.
+	admissionWorkers chan struct{}
+	// fieldManager is the configured field manager of the admissions.
+	fieldManager string
+	// fieldOwner is the field owner of the admissions, which is the field
+	// manager along with the fencing token of the leadership term when
+	// fencing is enabled.
+	fieldOwner   string
+	fencingLease *types.NamespacedName
+	leaseReader  client.Reader
+	// term is the leadership term in which the scheduler started, when
+	// fencing is enabled.
+	term *leadershipTerm
 
 	// Stubs.
 	applyAdmission func(context.Context, *kueue.Workload) error
 }
 
 type options struct {
-	waitForPodsReady bool
+	waitForPodsReady  bool
+	requeuingBackoff  *requeuingBackoff
+	workloadInfoOpts  []workload.InfoOption
+	audit             bool
+	decisionLog       io.Writer
+	fencingLease      *types.NamespacedName
+	leaseReader       client.Reader
+	cohortConcurrency int
+	admissionWorkers  int
+	fieldManager      string
+}
+
+type requeuingBackoff struct {
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	jitter    float64
 }
 
 // Option configures the reconciler.
@@ -79,7 +125,81 @@ func WithWaitForPodsReady(f bool) Option {
 	}
 }
 
-var defaultOptions = options{}
+// WithRequeuingBackoff indicates that a workload that couldn't be admitted
+// shouldn't be considered again for admission until a backoff expires.
+// The backoff starts at baseDelay and doubles with every consecutive failed
+// admission attempt, up to maxDelay. A random fraction of the backoff, up to
+// jitter, is added to it.
+func WithRequeuingBackoff(baseDelay, maxDelay time.Duration, jitter float64) Option {
+	return func(o *options) {
+		o.requeuingBackoff = &requeuingBackoff{
+			baseDelay: baseDelay,
+			maxDelay:  maxDelay,
+			jitter:    jitter,
+		}
+	}
+}
+
+// WithWorkloadInfoOptions indicates how the requests of the workloads are
+// computed before they are admitted, such as the resource transformations.
+func WithWorkloadInfoOptions(opts ...workload.InfoOption) Option {
+	return func(o *options) {
+		o.workloadInfoOpts = append(o.workloadInfoOpts, opts...)
+	}
+}
+
+// WithAdmissionAudit indicates that every admission decision is recorded, with
+// its reasoning, as an event of the workload and, if decisionLog is not nil,
+// as a JSON line in it.
+func WithAdmissionAudit(decisionLog io.Writer) Option {
+	return func(o *options) {
+		o.audit = true
+		o.decisionLog = decisionLog
+	}
+}
+
+// WithLeaderElectionFencing indicates that the field owner of the admissions
+// and preemptions includes the number of transitions of the leader election
+// lease when the scheduler starts, and that the lease is re-read before each
+// of them, so that a deposed leader doesn't apply its in-flight admissions
+// and preemptions.
+func WithLeaderElectionFencing(reader client.Reader, lease types.NamespacedName) Option {
+	return func(o *options) {
+		o.leaseReader = reader
+		o.fencingLease = &lease
+	}
+}
+
+// WithCohortConcurrency indicates the number of hierarchies of cohorts whose
+// head workloads are nominated and admitted concurrently in each scheduling
+// cycle.
+func WithCohortConcurrency(n int) Option {
+	return func(o *options) {
+		o.cohortConcurrency = n
+	}
+}
+
+// WithAdmissionPatchWorkers indicates the maximum number of admission patches
+// that are applied concurrently.
+func WithAdmissionPatchWorkers(n int) Option {
+	return func(o *options) {
+		o.admissionWorkers = n
+	}
+}
+
+// WithFieldManager indicates the field manager with which the admissions and
+// the preemptions are applied.
+func WithFieldManager(name string) Option {
+	return func(o *options) {
+		o.fieldManager = name
+	}
+}
+
+var defaultOptions = options{
+	cohortConcurrency: 1,
+	admissionWorkers:  DefaultAdmissionPatchWorkers,
+	fieldManager:      constants.AdmissionName,
+}
 
 func New(queues *queue.Manager, cache *cache.Cache, cl client.Client, recorder record.EventRecorder, opts ...Option) *Scheduler {
 	options := defaultOptions
@@ -91,18 +211,114 @@ func New(queues *queue.Manager, cache *cache.Cache, cl client.Client, recorder r
 		cache:                   cache,
 		client:                  cl,
 		recorder:                recorder,
-		preemptor:               preemption.New(cl, recorder),
 		admissionRoutineWrapper: routine.DefaultWrapper,
 		waitForPodsReady:        options.waitForPodsReady,
+		requeuingBackoff:        options.requeuingBackoff,
+		workloadInfoOpts:        options.workloadInfoOpts,
+		fieldManager:            options.fieldManager,
+		fieldOwner:              options.fieldManager,
+		fencingLease:            options.fencingLease,
+		leaseReader:             options.leaseReader,
+		cohortConcurrency:       options.cohortConcurrency,
+		admissionWorkers:        make(chan struct{}, options.admissionWorkers),
 	}
+	if options.audit {
+		s.auditor = &auditor{recorder: recorder, decisionLog: options.decisionLog}
+	}
+	s.preemptor = preemption.New(cl, recorder, options.fieldManager, s.admissionFieldOwner)
 	s.applyAdmission = s.applyAdmissionWithSSA
 	return s
 }
 
-func (s *Scheduler) Start(ctx context.Context) {
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that only
+// the leader admits workloads and issues preemptions.
+func (s *Scheduler) NeedLeaderElection() bool {
+	return true
+}
+
+func (s *Scheduler) Start(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx).WithName("scheduler")
 	ctx = ctrl.LoggerInto(ctx, log)
-	wait.UntilWithContext(ctx, s.schedule, 0)
+	if s.fencingLease != nil {
+		term, err := currentTerm(ctx, s.leaseReader, *s.fencingLease)
+		if err != nil {
+			return fmt.Errorf("getting the leadership term: %w", err)
+		}
+		s.term = term
+		s.fieldOwner = workload.AdmissionFieldOwner(s.fieldManager, term.transitions)
+		log.V(2).Info("Fencing the admissions of the leadership term", "fieldOwner", s.fieldOwner, "holder", term.holder)
+	}
+	shards := s.queues.Shards()
+	if shards <= 1 {
+		wait.UntilWithContext(ctx, s.schedule, 0)
+		return nil
+	}
+	// The shards don't share quota, so their cycles run concurrently.
+	var wg sync.WaitGroup
+	for i := 0; i < shards; i++ {
+		shard := i
+		ctx := ctrl.LoggerInto(ctx, log.WithValues("shard", shard))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.UntilWithContext(ctx, func(ctx context.Context) {
+				s.scheduleHeads(ctx, func(ctx context.Context) []workload.Info {
+					return s.queues.HeadsOfShard(ctx, shard)
+				})
+			}, 0)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// errLeadershipLost is returned when applying an admission or a preemption
+// after the leadership term in which the scheduler started ended.
+var errLeadershipLost = errors.New("the leadership term of the scheduler ended")
+
+// leadershipTerm identifies a leadership term by the holder of the leader
+// election lease and its number of transitions, which increases with every
+// new term and is the fencing token of the term.
+type leadershipTerm struct {
+	holder      string
+	transitions int32
+}
+
+// currentTerm returns the leadership term recorded in the leader election
+// lease.
+func currentTerm(ctx context.Context, r client.Reader, key types.NamespacedName) (*leadershipTerm, error) {
+	var lease coordinationv1.Lease
+	if err := r.Get(ctx, key, &lease); err != nil {
+		return nil, err
+	}
+	term := &leadershipTerm{}
+	if lease.Spec.HolderIdentity != nil {
+		term.holder = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.LeaseTransitions != nil {
+		term.transitions = *lease.Spec.LeaseTransitions
+	}
+	return term, nil
+}
+
+// admissionFieldOwner returns the field owner with which the admissions and
+// the preemptions are applied. When fencing is enabled, it re-reads the
+// leader election lease first, and fails if another replica took over the
+// leadership since the scheduler started, so that a deposed leader doesn't
+// apply its in-flight admissions, nor co-own the admissions of the current
+// leader by applying the same values.
+func (s *Scheduler) admissionFieldOwner(ctx context.Context) (string, error) {
+	if s.term == nil {
+		return s.fieldOwner, nil
+	}
+	term, err := currentTerm(ctx, s.leaseReader, *s.fencingLease)
+	if err != nil {
+		return "", fmt.Errorf("getting the leadership term: %w", err)
+	}
+	if *term != *s.term {
+		return "", errLeadershipLost
+	}
+	return s.fieldOwner, nil
 }
 
 func (s *Scheduler) setAdmissionRoutineWrapper(wrapper routine.Wrapper) {
@@ -110,11 +326,16 @@ func (s *Scheduler) setAdmissionRoutineWrapper(wrapper routine.Wrapper) {
 }
 
 func (s *Scheduler) schedule(ctx context.Context) {
+	s.scheduleHeads(ctx, s.queues.Heads)
+}
+
+// scheduleHeads runs a scheduling cycle for the workloads returned by heads.
+func (s *Scheduler) scheduleHeads(ctx context.Context, heads func(context.Context) []workload.Info) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// 1. Get the heads from the queues, including their desired clusterQueue.
 	// This operation blocks while the queues are empty.
-	headWorkloads := s.queues.Heads(ctx)
+	headWorkloads := heads(ctx)
 	// No elements means the program is finishing.
 	if len(headWorkloads) == 0 {
 		return
@@ -123,9 +344,59 @@ func (s *Scheduler) schedule(ctx context.Context) {
 
 	// 2. Take a snapshot of the cache.
 	snapshot := s.cache.Snapshot()
+	metrics.SchedulingPhaseCompleted(metrics.SchedulingPhaseSnapshot, time.Since(startTime))
+
+	// 3-5. Nominate and admit the workloads. The workloads in different
+	// hierarchies of cohorts don't compete for quota, so, with cohort
+	// concurrency, they are nominated and admitted concurrently, each group
+	// with its slice of the snapshot.
+	var entries []entry
+	if s.cohortConcurrency <= 1 {
+		entries = s.nominateAndAdmit(ctx, headWorkloads, snapshot)
+	} else {
+		groups := groupByCohort(headWorkloads, &snapshot)
+		groupEntries := make([][]entry, len(groups))
+		workqueue.ParallelizeUntil(ctx, s.cohortConcurrency, len(groups), func(i int) {
+			groupEntries[i] = s.nominateAndAdmit(ctx, groups[i].workloads, *groups[i].snapshot)
+		})
+		for _, ge := range groupEntries {
+			entries = append(entries, ge...)
+		}
+	}
+
+	// 6. Requeue the heads that were not scheduled.
+	result := metrics.AdmissionResultInadmissible
+	for _, e := range entries {
+		log.V(3).Info("Workload evaluated for admission",
+			"workload", klog.KObj(e.Obj),
+			"clusterQueue", klog.KRef("", e.ClusterQueue),
+			"status", e.status,
+			"reason", e.inadmissibleMsg)
+		if s.auditor != nil {
+			if err := s.auditor.record(&e, startTime); err != nil {
+				log.Error(err, "Could not record the admission decision", "workload", klog.KObj(e.Obj))
+			}
+		}
+		if e.status != assumed {
+			s.requeueAndUpdate(log, ctx, e)
+		} else {
+			result = metrics.AdmissionResultSuccess
+		}
+		metrics.WorkloadScheduled(e.ClusterQueue, e.schedulingResult())
+	}
+	metrics.AdmissionAttempt(result, time.Since(startTime))
+}
+
+// nominateAndAdmit calculates the requirements (resource flavors, borrowing)
+// for admitting the workloads and admits the ones that fit, preempting other
+// workloads if needed. It returns the entries of all the workloads.
+func (s *Scheduler) nominateAndAdmit(ctx context.Context, headWorkloads []workload.Info, snapshot cache.Snapshot) []entry {
+	log := ctrl.LoggerFrom(ctx)
 
 	// 3. Calculate requirements (resource flavors, borrowing) for admitting workloads.
+	phaseStart := time.Now()
 	entries := s.nominate(ctx, headWorkloads, snapshot)
+	metrics.SchedulingPhaseCompleted(metrics.SchedulingPhaseNomination, time.Since(phaseStart))
 
 	// 4. Sort entries based on borrowing and timestamps.
 	sort.Sort(entryOrdering(entries))
@@ -135,36 +406,73 @@ func (s *Scheduler) schedule(ctx context.Context) {
 	// This is because there can be other workloads deeper in a clusterQueue whose
 	// head got admitted that should be scheduled in the cohort before the heads
 	// of other clusterQueues.
+	phaseStart = time.Now()
 	usedCohorts := sets.New[string]()
+	// The usage of the topology domains in the snapshot is not updated as
+	// workloads are admitted, so only one workload is admitted into the
+	// domains of each flavor per cycle.
+	usedTopologyFlavors := sets.New[string]()
 	for i := range entries {
 		e := &entries[i]
+		reserved := false
+		for _, m := range e.members() {
+			if _, found := snapshot.Reservations[workload.Key(m.Obj)]; found {
+				reserved = true
+			}
+		}
 		if e.assignment.RepresentativeMode() == flavorassigner.NoFit {
+			if reserved {
+				for _, m := range e.members() {
+					s.cache.ReleaseQuota(m.Obj)
+				}
+			}
 			continue
 		}
 		cq := snapshot.ClusterQueues[e.ClusterQueue]
-		if e.assignment.Borrows() && cq.Cohort != nil && usedCohorts.Has(cq.Cohort.Name) {
+		if e.assignment.Borrows() && cq.Cohort != nil && usedCohorts.Has(cq.Cohort.Root().Name) {
 			e.status = skipped
 			e.inadmissibleMsg = "workloads in the cohort that don't require borrowing were prioritized and admitted first"
 			continue
 		}
+		topologyFlavors := e.topologyFlavors()
+		if usedTopologyFlavors.HasAny(topologyFlavors...) {
+			e.status = skipped
+			e.inadmissibleMsg = "other workloads were admitted into the topology domains of the same flavor"
+			continue
+		}
 		// Even if there was a failure, we shouldn't admit other workloads to this
 		// cohort.
 		if cq.Cohort != nil {
-			usedCohorts.Insert(cq.Cohort.Name)
+			usedCohorts.Insert(cq.Cohort.Root().Name)
 		}
+		usedTopologyFlavors.Insert(topologyFlavors...)
 		log := log.WithValues("workload", klog.KObj(e.Obj), "clusterQueue", klog.KRef("", e.ClusterQueue))
 		ctx := ctrl.LoggerInto(ctx, log)
+		admissionStart := time.Now()
 		if e.assignment.RepresentativeMode() != flavorassigner.Fit {
-			preempted, err := s.preemptor.Do(ctx, e.Info, e.assignment, &snapshot)
+			if e.isResize() {
+				// Resizes wait for quota to be released instead of preempting.
+				metrics.WorkloadSchedulingPhaseCompleted(e.ClusterQueue, metrics.SchedulingPhaseAdmission, time.Since(admissionStart))
+				continue
+			}
+			preempted, err := s.preemptor.Do(ctx, *e.admissionInfo(), e.assignment, &snapshot)
 			if err != nil {
 				log.Error(err, "Failed to preempt workloads")
 			}
-			if preempted != 0 {
-				e.inadmissibleMsg += fmt.Sprintf(". Preempted %d workload(s)", preempted)
+			e.preempted = preempted
+			if len(preempted) != 0 {
+				e.inadmissibleMsg += fmt.Sprintf(". Preempted %d workload(s)", len(preempted))
+				s.reserveQuota(ctx, e)
+				reserved = true
+			}
+			if reserved {
+				// Wait for the preempted workloads to release their quota.
+				e.requeueReason = queue.RequeueReasonPendingPreemption
 			}
+			metrics.WorkloadSchedulingPhaseCompleted(e.ClusterQueue, metrics.SchedulingPhaseAdmission, time.Since(admissionStart))
 			continue
 		}
-		if s.waitForPodsReady {
+		if s.waitForPodsReady && !e.isResize() {
 			if !s.cache.PodsReadyForAllAdmittedWorkloads(ctx) {
 				log.V(5).Info("Waiting for all admitted workloads to be in the PodsReady condition")
 				// Block admission until all currently admitted workloads are in
@@ -177,26 +485,49 @@ func (s *Scheduler) schedule(ctx context.Context) {
 			}
 		}
 		e.status = nominated
-		if err := s.admit(ctx, e); err != nil {
+		if e.isResize() {
+			if err := s.admitResize(ctx, e); err != nil {
+				e.inadmissibleMsg = fmt.Sprintf("Failed to resize workload: %v", err)
+			}
+		} else if err := s.admit(ctx, e, cq); err != nil {
 			e.inadmissibleMsg = fmt.Sprintf("Failed to admit workload: %v", err)
 		}
+		metrics.WorkloadSchedulingPhaseCompleted(e.ClusterQueue, metrics.SchedulingPhaseAdmission, time.Since(admissionStart))
 	}
+	metrics.SchedulingPhaseCompleted(metrics.SchedulingPhaseAdmission, time.Since(phaseStart))
+	return entries
+}
 
-	// 6. Requeue the heads that were not scheduled.
-	result := metrics.AdmissionResultInadmissible
-	for _, e := range entries {
-		log.V(3).Info("Workload evaluated for admission",
-			"workload", klog.KObj(e.Obj),
-			"clusterQueue", klog.KRef("", e.ClusterQueue),
-			"status", e.status,
-			"reason", e.inadmissibleMsg)
-		if e.status != assumed {
-			s.requeueAndUpdate(log, ctx, e)
-		} else {
-			result = metrics.AdmissionResultSuccess
+// cohortGroup holds the head workloads of the ClusterQueues in a hierarchy of
+// cohorts, or of a ClusterQueue without cohort, with the slice of the
+// snapshot for them.
+type cohortGroup struct {
+	workloads []workload.Info
+	snapshot  *cache.Snapshot
+}
+
+// groupByCohort groups the workloads by the hierarchy of cohorts of their
+// ClusterQueues.
+func groupByCohort(workloads []workload.Info, snapshot *cache.Snapshot) []cohortGroup {
+	slices := snapshot.SlicesByCohort()
+	index := make(map[string]int)
+	var groups []cohortGroup
+	for _, w := range workloads {
+		key := snapshot.SliceKey(w.ClusterQueue)
+		i, found := index[key]
+		if !found {
+			slice := slices[key]
+			if slice == nil {
+				// The ClusterQueue isn't active.
+				slice = snapshot.EmptySlice()
+			}
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, cohortGroup{snapshot: slice})
 		}
+		groups[i].workloads = append(groups[i].workloads, w)
 	}
-	metrics.AdmissionAttempt(result, time.Since(startTime))
+	return groups
 }
 
 type entryStatus string
@@ -221,6 +552,67 @@ type entry struct {
 	status          entryStatus
 	inadmissibleMsg string
 	requeueReason   queue.RequeueReason
+	// preempted are the workloads that were preempted for the entry.
+	preempted []*workload.Info
+
+	// group holds the workloads of the group of the workload, starting with
+	// the workload itself, if it belongs to a group.
+	group []*workload.Info
+	// groupInfo holds the pod sets of all the workloads of the group, which
+	// are assigned flavors as a single workload.
+	groupInfo *workload.Info
+}
+
+// schedulingResult returns the result of the scheduling cycle for the entry.
+func (e *entry) schedulingResult() metrics.SchedulingResult {
+	switch {
+	case e.status == assumed:
+		return metrics.SchedulingResultAdmitted
+	case e.status == skipped:
+		return metrics.SchedulingResultSkipped
+	case e.requeueReason == queue.RequeueReasonPendingPreemption:
+		return metrics.SchedulingResultPreempting
+	default:
+		return metrics.SchedulingResultInadmissible
+	}
+}
+
+// topologyFlavors returns the flavors of the pod sets that were assigned a
+// topology domain.
+func (e *entry) topologyFlavors() []string {
+	var flavors []string
+	for _, ps := range e.assignment.PodSets {
+		if ps.TopologyDomain == nil {
+			continue
+		}
+		for _, flvAssignment := range ps.Flavors {
+			flavors = append(flavors, flvAssignment.Name)
+		}
+	}
+	return flavors
+}
+
+// isResize returns whether the entry is for the additional pods requested by an
+// admitted workload.
+func (e *entry) isResize() bool {
+	return e.Obj.Spec.Admission != nil
+}
+
+// members returns the workloads that are admitted together with the entry.
+func (e *entry) members() []*workload.Info {
+	if e.group != nil {
+		return e.group
+	}
+	return []*workload.Info{&e.Info}
+}
+
+// admissionInfo returns the workload information used to assign flavors to
+// the entry.
+func (e *entry) admissionInfo() *workload.Info {
+	if e.groupInfo != nil {
+		return e.groupInfo
+	}
+	return &e.Info
 }
 
 // nominate returns the workloads with their requirements (resource flavors, borrowing) if
@@ -230,70 +622,351 @@ func (s *Scheduler) nominate(ctx context.Context, workloads []workload.Info, sna
 	entries := make([]entry, 0, len(workloads))
 	for _, w := range workloads {
 		log := log.WithValues("workload", klog.KObj(w.Obj), "clusterQueue", klog.KRef("", w.ClusterQueue))
-		cq := snap.ClusterQueues[w.ClusterQueue]
-		ns := corev1.Namespace{}
-		e := entry{Info: w}
-		if snap.InactiveClusterQueueSets.Has(w.ClusterQueue) {
-			e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s is inactive", w.ClusterQueue)
-		} else if cq == nil {
-			e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s not found", w.ClusterQueue)
-		} else if err := s.client.Get(ctx, types.NamespacedName{Name: w.Obj.Namespace}, &ns); err != nil {
-			e.inadmissibleMsg = fmt.Sprintf("Could not obtain workload namespace: %v", err)
-		} else if !cq.NamespaceSelector.Matches(labels.Set(ns.Labels)) {
-			e.inadmissibleMsg = "Workload namespace doesn't match ClusterQueue selector"
-			e.requeueReason = queue.RequeueReasonNamespaceMismatch
-		} else {
-			e.assignment = flavorassigner.AssignFlavors(log, &e.Info, snap.ResourceFlavors, cq)
-			e.inadmissibleMsg = e.assignment.Message()
-		}
-		entries = append(entries, e)
+		start := time.Now()
+		entries = append(entries, s.nominateWorkload(ctrl.LoggerInto(ctx, log), w, snap, start))
+		metrics.WorkloadSchedulingPhaseCompleted(w.ClusterQueue, metrics.SchedulingPhaseNomination, time.Since(start))
 	}
 	return entries
 }
 
-// admit sets the admitting clusterQueue and flavors into the workload of
-// the entry, and asynchronously updates the object in the apiserver after
-// assuming it in the cache.
-func (s *Scheduler) admit(ctx context.Context, e *entry) error {
+// nominateWorkload returns the entry of the workload with its requirements,
+// or the reason why it can't be admitted by its clusterQueue in the snapshot
+// at the given time.
+func (s *Scheduler) nominateWorkload(ctx context.Context, w workload.Info, snap cache.Snapshot, now time.Time) entry {
 	log := ctrl.LoggerFrom(ctx)
-	newWorkload := e.Obj.DeepCopy()
-	admission := &kueue.Admission{
+	cq := snap.ClusterQueues[w.ClusterQueue]
+	ns := corev1.Namespace{}
+	e := entry{Info: w}
+	if !workload.IsActive(w.Obj) {
+		e.inadmissibleMsg = "The workload is deactivated"
+	} else if e.isResize() {
+		s.nominateResize(log, &e, cq, snap)
+	} else if d := w.Obj.Spec.AdmissionDeadline; d != nil && !now.Before(d.Time) {
+		e.inadmissibleMsg = "The admission deadline of the workload passed"
+	} else if snap.InactiveClusterQueueSets.Has(w.ClusterQueue) {
+		e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s is inactive", w.ClusterQueue)
+	} else if cq == nil {
+		e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s not found", w.ClusterQueue)
+	} else if err := s.client.Get(ctx, types.NamespacedName{Name: w.Obj.Namespace}, &ns); err != nil {
+		e.inadmissibleMsg = fmt.Sprintf("Could not obtain workload namespace: %v", err)
+	} else if !cq.NamespaceSelector.Matches(labels.Set(ns.Labels)) {
+		e.inadmissibleMsg = "Workload namespace doesn't match ClusterQueue selector"
+		e.requeueReason = queue.RequeueReasonNamespaceMismatch
+	} else if msg := s.setWorkloadGroup(&e); msg != "" {
+		e.inadmissibleMsg = msg
+	} else {
+		if workload.IsSlice(w.Obj) {
+			pinSliceFlavors(&e, cq)
+		}
+		// The quota reserved for the workload is available to it.
+		var reservations []*workload.Info
+		for _, m := range e.members() {
+			if r := snap.RemoveReservation(workload.Key(m.Obj)); r != nil {
+				reservations = append(reservations, r)
+			}
+		}
+		e.assignment = flavorassigner.AssignFlavors(log, e.admissionInfo(), snap.ResourceFlavors, cq, nil)
+		if e.assignment.RepresentativeMode() != flavorassigner.Fit && e.group == nil && e.CanBePartiallyAdmitted() {
+			if assignment, found := partialAssignment(log, &e.Info, snap.ResourceFlavors, cq); found {
+				e.assignment = assignment
+			}
+		}
+		for _, r := range reservations {
+			snap.AddReservation(r)
+		}
+		e.inadmissibleMsg = e.assignment.Message()
+	}
+	return e
+}
+
+// nominateResize sets the assignment for the pods that an admitted workload
+// requests in addition to the admitted ones, with the flavors of its admission.
+func (s *Scheduler) nominateResize(log logr.Logger, e *entry, cq *cache.ClusterQueue, snap cache.Snapshot) {
+	if cq == nil || string(e.Obj.Spec.Admission.ClusterQueue) != e.ClusterQueue {
+		e.inadmissibleMsg = fmt.Sprintf("ClusterQueue %s is not active or didn't admit the workload", e.ClusterQueue)
+		return
+	}
+	e.assignment = flavorassigner.AssignFlavors(log, workload.NewResizeInfo(e.Obj, s.workloadInfoOpts...), snap.ResourceFlavors, cq, nil)
+	e.inadmissibleMsg = e.assignment.Message()
+}
+
+// pinSliceFlavors sets, as the flavors of the pod sets of a workload slice, the
+// flavors and topology domains of a slice of the same job that the ClusterQueue
+// already admitted, since all the slices run with the same pod template.
+func pinSliceFlavors(e *entry, cq *cache.ClusterQueue) {
+	for _, admitted := range cq.Workloads {
+		if !workload.SameJobSlices(e.Obj, admitted.Obj) {
+			continue
+		}
+		// The pod sets are shared with the queues.
+		requests := make([]workload.PodSetResources, len(e.TotalRequests))
+		copy(requests, e.TotalRequests)
+		for i := range requests {
+			for _, ps := range admitted.TotalRequests {
+				if ps.Name == requests[i].Name {
+					requests[i].Flavors = ps.Flavors
+					requests[i].TopologyDomain = ps.TopologyDomain
+				}
+			}
+		}
+		e.TotalRequests = requests
+		return
+	}
+}
+
+// setWorkloadGroup sets the workloads of the group of the entry, if the
+// workload belongs to a group. It returns a message if the group can't be
+// admitted yet.
+func (s *Scheduler) setWorkloadGroup(e *entry) string {
+	group := workload.GroupName(e.Obj)
+	if group == "" {
+		return ""
+	}
+	size := workload.GroupSize(e.Obj)
+	if size == 0 {
+		return fmt.Sprintf("Invalid size for the workload group %s", group)
+	}
+	members := s.queues.GroupMembers(e.Obj, e.ClusterQueue)
+	if len(members)+1 < size {
+		return fmt.Sprintf("Waiting for %d more workload(s) of the workload group %s", size-len(members)-1, group)
+	}
+	if len(members)+1 > size {
+		return fmt.Sprintf("The workload group %s has more than %d workloads", group, size)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return workload.Key(members[i].Obj) < workload.Key(members[j].Obj)
+	})
+	head := e.Info
+	e.group = append([]*workload.Info{&head}, members...)
+	e.groupInfo = workload.NewGroupInfo(e.group, s.workloadInfoOpts...)
+	return ""
+}
+
+// partialAssignment looks for the highest count, not lower than minCount, for
+// the pod set that supports partial admission, such that the workload fits
+// without borrowing beyond the available quota or preempting other workloads.
+func partialAssignment(log logr.Logger, wl *workload.Info, resourceFlavors map[string]*kueue.ResourceFlavor, cq *cache.ClusterQueue) (flavorassigner.Assignment, bool) {
+	psIdx := -1
+	counts := make([]int32, len(wl.TotalRequests))
+	for i := range wl.TotalRequests {
+		counts[i] = wl.TotalRequests[i].Count
+		if ps := &wl.Obj.Spec.PodSets[i]; psIdx == -1 && ps.MinCount != nil && *ps.MinCount < ps.Count {
+			psIdx = i
+		}
+	}
+	if psIdx == -1 {
+		return flavorassigner.Assignment{}, false
+	}
+	maxCount := counts[psIdx]
+	minCount := *wl.Obj.Spec.PodSets[psIdx].MinCount
+	var bestAssignment flavorassigner.Assignment
+	found := false
+	// Binary search for the smallest reduction that makes the workload fit,
+	// assuming that smaller counts are more likely to fit. The last assignment
+	// that fits is the one for the returned reduction.
+	sort.Search(int(maxCount-minCount), func(i int) bool {
+		counts[psIdx] = maxCount - 1 - int32(i)
+		assignment := flavorassigner.AssignFlavors(log, wl, resourceFlavors, cq, counts)
+		if assignment.RepresentativeMode() == flavorassigner.Fit {
+			bestAssignment = assignment
+			found = true
+			return true
+		}
+		return false
+	})
+	return bestAssignment, found
+}
+
+// reserveQuota reserves the quota of the assignment of the entry in the cache,
+// so that the quota released by the workloads that the entry preempted is not
+// taken by other workloads before the entry is admitted.
+func (s *Scheduler) reserveQuota(ctx context.Context, e *entry) {
+	log := ctrl.LoggerFrom(ctx)
+	wl := e.admissionInfo().Obj.DeepCopy()
+	wl.Spec.Admission = &kueue.Admission{
 		ClusterQueue:  kueue.ClusterQueueReference(e.ClusterQueue),
 		PodSetFlavors: e.assignment.ToAPI(),
 	}
-	newWorkload.Spec.Admission = admission
-	if err := s.cache.AssumeWorkload(newWorkload); err != nil {
-		return err
+	if err := s.cache.ReserveQuota(wl, time.Now().Add(preemptionReservationTimeout)); err != nil {
+		log.Error(err, "Failed to reserve quota for the preemptor workload")
+		return
+	}
+	log.V(2).Info("Quota reserved until the preempted workloads release it")
+}
+
+// admit sets the admitting clusterQueue, flavors and admission checks into the
+// workload of the entry, and asynchronously updates the object in the apiserver after
+// assuming it in the cache. The workloads of the group of the entry, if any,
+// are admitted together with it.
+func (s *Scheduler) admit(ctx context.Context, e *entry, cq *cache.ClusterQueue) error {
+	log := ctrl.LoggerFrom(ctx)
+	members := e.members()
+	psFlavors := e.assignment.ToAPI()
+	newWorkloads := make([]*kueue.Workload, 0, len(members))
+	for _, m := range members {
+		newWorkload := m.Obj.DeepCopy()
+		n := len(newWorkload.Spec.PodSets)
+		newWorkload.Spec.Admission = &kueue.Admission{
+			ClusterQueue:    kueue.ClusterQueueReference(e.ClusterQueue),
+			PodSetFlavors:   psFlavors[:n],
+			AdmissionChecks: cq.AdmissionChecks,
+		}
+		psFlavors = psFlavors[n:]
+		if err := s.cache.AssumeWorkload(newWorkload); err != nil {
+			for _, w := range newWorkloads {
+				_ = s.cache.ForgetWorkload(w)
+			}
+			return err
+		}
+		newWorkloads = append(newWorkloads, newWorkload)
 	}
 	e.status = assumed
 	log.V(2).Info("Workload assumed in the cache")
+	// The rest of the group is no longer pending.
+	for _, m := range members[1:] {
+		s.queues.DeleteWorkload(m.Obj)
+	}
+
+	s.admissionRoutineWrapper.Run(func() {
+		for i, newWorkload := range newWorkloads {
+			log := log
+			if i > 0 {
+				log = log.WithValues("groupMember", klog.KObj(newWorkload))
+			}
+			err := s.applyAdmissionPatch(ctx, workload.AdmissionPatch(newWorkload))
+			if err == nil {
+				admission := newWorkload.Spec.Admission
+				waitTime := time.Since(newWorkload.CreationTimestamp.Time)
+				if len(admission.AdmissionChecks) > 0 {
+					s.recorder.Eventf(newWorkload, corev1.EventTypeNormal, "QuotaReserved", "Quota reserved in ClusterQueue %v, wait time was %.3fs", admission.ClusterQueue, waitTime.Seconds())
+				} else {
+					s.recorder.Eventf(newWorkload, corev1.EventTypeNormal, "Admitted", "Admitted by ClusterQueue %v, wait time was %.3fs", admission.ClusterQueue, waitTime.Seconds())
+				}
+				metrics.QuotaReservedWorkload(admission.ClusterQueue)
+				log.V(2).Info("Workload successfully admitted and assigned flavors")
+				continue
+			}
+			// Ignore errors because the workload or clusterQueue could have been deleted
+			// by an event.
+			_ = s.cache.ForgetWorkload(newWorkload)
+			deleted := apierrors.IsNotFound(err)
+			if deleted {
+				log.V(2).Info("Workload not admitted because it was deleted")
+			} else {
+				logApplyError(log, err, errCouldNotAdmitWL)
+			}
+			// The group is admitted as a whole or not at all.
+			s.rollbackGroupAdmission(ctx, e, newWorkloads, i, !deleted)
+			return
+		}
+	})
+
+	return nil
+}
+
+// admitResize applies the counts requested in the podSetResizes of an admitted
+// workload to its admission, and asynchronously updates the object in the
+// apiserver after updating it in the cache.
+func (s *Scheduler) admitResize(ctx context.Context, e *entry) error {
+	log := ctrl.LoggerFrom(ctx)
+	newWorkload := e.Obj.DeepCopy()
+	newWorkload.Spec.Admission = workload.ResizedAdmission(e.Obj)
+	if err := s.cache.UpdateWorkload(e.Obj, newWorkload); err != nil {
+		return err
+	}
+	e.status = assumed
+	log.V(2).Info("Workload resize assumed in the cache")
 
 	s.admissionRoutineWrapper.Run(func() {
-		err := s.applyAdmission(ctx, workload.AdmissionPatch(newWorkload))
+		err := s.applyAdmissionPatch(ctx, workload.AdmissionPatch(newWorkload))
 		if err == nil {
-			waitTime := time.Since(e.Obj.CreationTimestamp.Time)
-			s.recorder.Eventf(newWorkload, corev1.EventTypeNormal, "Admitted", "Admitted by ClusterQueue %v, wait time was %.3fs", admission.ClusterQueue, waitTime.Seconds())
-			metrics.AdmittedWorkload(admission.ClusterQueue, waitTime)
-			log.V(2).Info("Workload successfully admitted and assigned flavors")
+			s.recorder.Eventf(newWorkload, corev1.EventTypeNormal, "Resized", "Resized by ClusterQueue %v", newWorkload.Spec.Admission.ClusterQueue)
+			log.V(2).Info("Workload successfully resized")
 			return
 		}
 		// Ignore errors because the workload or clusterQueue could have been deleted
 		// by an event.
-		_ = s.cache.ForgetWorkload(newWorkload)
-		if errors.IsNotFound(err) {
-			log.V(2).Info("Workload not admitted because it was deleted")
+		_ = s.cache.UpdateWorkload(newWorkload, e.Obj)
+		if apierrors.IsNotFound(err) {
+			log.V(2).Info("Workload not resized because it was deleted")
 			return
 		}
-
-		log.Error(err, errCouldNotAdmitWL)
+		logApplyError(log, err, "Could not resize workload")
 		s.requeueAndUpdate(log, ctx, *e)
 	})
-
 	return nil
 }
 
+// rollbackGroupAdmission undoes the admission of the workloads of an entry
+// after the admission of the workload at the given index failed. The
+// workloads before it are already admitted in the apiserver, so their
+// admission is cleared; the rest are forgotten in the cache and requeued.
+// The failed workload is requeued if requeueFailed is true.
+func (s *Scheduler) rollbackGroupAdmission(ctx context.Context, e *entry, newWorkloads []*kueue.Workload, failed int, requeueFailed bool) {
+	log := ctrl.LoggerFrom(ctx)
+	members := e.members()
+	for i, newWorkload := range newWorkloads {
+		switch {
+		case i < failed:
+			err := s.clearAdmission(ctx, newWorkload)
+			if client.IgnoreNotFound(err) != nil {
+				log.Error(err, "Could not cancel the admission of the workload group", "groupMember", klog.KObj(newWorkload))
+			}
+			continue
+		case i == failed && !requeueFailed:
+			continue
+		case i > failed:
+			_ = s.cache.ForgetWorkload(newWorkload)
+		}
+		memberEntry := *e
+		memberEntry.Info = *members[i]
+		s.requeueAndUpdate(log, ctx, memberEntry)
+	}
+}
+
+// logApplyError logs an error applying an admission. When other field managers
+// own fields of the admission, they are logged along with the fields, and
+// counted, to find the controllers that conflict with the scheduler.
+func logApplyError(log logr.Logger, err error, msg string) {
+	if conflicts := api.ApplyConflicts(err); len(conflicts) > 0 {
+		managers := sets.New[string]()
+		for _, c := range conflicts {
+			managers.Insert(c.Manager)
+		}
+		for _, m := range sets.List(managers) {
+			metrics.AdmissionApplyConflict(m)
+		}
+		log = log.WithValues("conflicts", conflicts)
+	}
+	log.Error(err, msg)
+}
+
+// applyAdmissionPatch applies the admission patch once one of the admission
+// patch workers is free.
+func (s *Scheduler) applyAdmissionPatch(ctx context.Context, w *kueue.Workload) error {
+	select {
+	case s.admissionWorkers <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-s.admissionWorkers }()
+	return s.applyAdmission(ctx, w)
+}
+
 func (s *Scheduler) applyAdmissionWithSSA(ctx context.Context, w *kueue.Workload) error {
-	return s.client.Patch(ctx, w, client.Apply, client.FieldOwner(constants.AdmissionName))
+	fieldOwner, err := s.admissionFieldOwner(ctx)
+	if err != nil {
+		return err
+	}
+	return s.client.Patch(ctx, w, client.Apply, client.FieldOwner(fieldOwner))
+}
+
+func (s *Scheduler) clearAdmission(ctx context.Context, w *kueue.Workload) error {
+	fieldOwner, err := s.admissionFieldOwner(ctx)
+	if err != nil {
+		return err
+	}
+	return s.client.Patch(ctx, workload.ClearAdmissionPatch(w), client.Apply, client.FieldOwner(fieldOwner))
 }
 
 type entryOrdering []entry
@@ -327,14 +1000,57 @@ func (s *Scheduler) requeueAndUpdate(log logr.Logger, ctx context.Context, e ent
 		// Failed after nomination is the only reason why a workload would be requeued downstream.
 		e.requeueReason = queue.RequeueReasonFailedAfterNomination
 	}
-	added := s.queues.RequeueWorkload(ctx, &e.Info, e.requeueReason)
-	log.V(2).Info("Workload re-queued", "workload", klog.KObj(e.Obj), "clusterQueue", klog.KRef("", e.ClusterQueue), "queue", klog.KRef(e.Obj.Namespace, e.Obj.Spec.QueueName), "requeueReason", e.requeueReason, "added", added)
-
-	if e.status == notNominated {
-		err := workload.UpdateStatus(ctx, s.client, e.Obj, kueue.WorkloadAdmitted, metav1.ConditionFalse, "Pending", e.inadmissibleMsg)
+	if e.status == notNominated && e.isResize() {
+		// The workload is still admitted, so its status is not updated.
+		s.recorder.Eventf(e.Obj, corev1.EventTypeNormal, "ResizePending", api.TruncateEventMessage(e.inadmissibleMsg))
+	} else if e.status == notNominated {
+		wl := e.Obj.DeepCopy()
+		wl.Status.InadmissibleReasons = e.assignment.InadmissibleReasons()
+		if s.requeuingBackoff != nil && e.requeueReason != queue.RequeueReasonPendingPreemption {
+			// Record the backoff before requeueing, so that the queues keep
+			// the workload aside until it expires.
+			wl.Status.RequeueState = s.requeuingBackoff.next(wl.Status.RequeueState, time.Now())
+			metrics.RequeuedWorkload(e.ClusterQueue)
+		}
+		err := workload.UpdateStatus(ctx, s.client, wl, kueue.WorkloadAdmitted, metav1.ConditionFalse, "Pending", e.inadmissibleMsg)
 		if err != nil {
 			log.Error(err, "Could not update Workload status")
 		}
 		s.recorder.Eventf(e.Obj, corev1.EventTypeNormal, "Pending", api.TruncateEventMessage(e.inadmissibleMsg))
+		// Requeue with the reasons why the workload is inadmissible and its
+		// requeue state, so that the queues know them before the update event
+		// arrives and keep the workload aside during the backoff.
+		e.Obj = wl
+	}
+
+	added := s.queues.RequeueWorkload(ctx, &e.Info, e.requeueReason)
+	log.V(2).Info("Workload re-queued", "workload", klog.KObj(e.Obj), "clusterQueue", klog.KRef("", e.ClusterQueue), "queue", klog.KRef(e.Obj.Namespace, e.Obj.Spec.QueueName), "requeueReason", e.requeueReason, "added", added)
+}
+
+// next returns the requeue state of a workload that failed one more admission
+// attempt at the given time.
+func (b *requeuingBackoff) next(state *kueue.RequeueState, now time.Time) *kueue.RequeueState {
+	var count int32
+	if state != nil && state.Count != nil {
+		count = *state.Count
+	}
+	count++
+	delay := b.baseDelay
+	for i := int32(1); i < count && delay < b.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > b.maxDelay {
+		delay = b.maxDelay
+	}
+	if b.jitter > 0 {
+		delay = wait.Jitter(delay, b.jitter)
+	}
+	next := &kueue.RequeueState{
+		Count:     &count,
+		RequeueAt: &metav1.Time{Time: now.Add(delay)},
+	}
+	if state != nil {
+		next.Evictions = state.Evictions
 	}
+	return next
 }
@@ -19,6 +19,7 @@ package scheduler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"sync"
 	"testing"
@@ -27,10 +28,14 @@ import (
 	"github.com/go-logr/logr/testr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,6 +45,7 @@ import (
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/cache"
 	"sigs.k8s.io/kueue/pkg/constants"
+	"sigs.k8s.io/kueue/pkg/metrics"
 	"sigs.k8s.io/kueue/pkg/queue"
 	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
 	"sigs.k8s.io/kueue/pkg/util/pointer"
@@ -73,15 +79,20 @@ func TestSchedule(t *testing.T) {
 					},
 				},
 				QueueingStrategy: kueue.StrictFIFO,
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+						Flavors: []kueue.FlavorQuotas{
 							{
 								Name: "default",
-								Quota: kueue.Quota{
-									Min: resource.MustParse("50"),
-									Max: pointer.Quantity(resource.MustParse("50")),
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("50"),
+											Max: pointer.Quantity(resource.MustParse("50")),
+										},
+									},
 								},
 							},
 						},
@@ -103,22 +114,32 @@ func TestSchedule(t *testing.T) {
 					},
 				},
 				QueueingStrategy: kueue.StrictFIFO,
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+						Flavors: []kueue.FlavorQuotas{
 							{
 								Name: "on-demand",
-								Quota: kueue.Quota{
-									Min: resource.MustParse("50"),
-									Max: pointer.Quantity(resource.MustParse("100")),
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("50"),
+											Max: pointer.Quantity(resource.MustParse("100")),
+										},
+									},
 								},
 							},
 							{
 								Name: "spot",
-								Quota: kueue.Quota{
-									Min: resource.MustParse("100"),
-									Max: pointer.Quantity(resource.MustParse("100")),
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("100"),
+											Max: pointer.Quantity(resource.MustParse("100")),
+										},
+									},
 								},
 							},
 						},
@@ -144,34 +165,49 @@ func TestSchedule(t *testing.T) {
 					WithinClusterQueue:  kueue.PreemptionPolicyLowerPriority,
 				},
 				QueueingStrategy: kueue.StrictFIFO,
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+						Flavors: []kueue.FlavorQuotas{
 							{
 								Name: "on-demand",
-								Quota: kueue.Quota{
-									Min: resource.MustParse("50"),
-									Max: pointer.Quantity(resource.MustParse("60")),
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("50"),
+											Max: pointer.Quantity(resource.MustParse("60")),
+										},
+									},
 								},
 							},
 							{
 								Name: "spot",
-								Quota: kueue.Quota{
-									Min: resource.MustParse("0"),
-									Max: pointer.Quantity(resource.MustParse("100")),
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("0"),
+											Max: pointer.Quantity(resource.MustParse("100")),
+										},
+									},
 								},
 							},
 						},
 					},
 					{
-						Name: "example.com/gpu",
-						Flavors: []kueue.Flavor{
+						CoveredResources: []corev1.ResourceName{"example.com/gpu"},
+						Flavors: []kueue.FlavorQuotas{
 							{
 								Name: "model-a",
-								Quota: kueue.Quota{
-									Min: resource.MustParse("20"),
-									Max: pointer.Quantity(resource.MustParse("20")),
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: "example.com/gpu",
+										Quota: kueue.Quota{
+											Min: resource.MustParse("20"),
+											Max: pointer.Quantity(resource.MustParse("20")),
+										},
+									},
 								},
 							},
 						},
@@ -183,14 +219,19 @@ func TestSchedule(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{Name: "flavor-nonexistent-cq"},
 			Spec: kueue.ClusterQueueSpec{
 				QueueingStrategy: kueue.StrictFIFO,
-				Resources: []kueue.Resource{
+				ResourceGroups: []kueue.ResourceGroup{
 					{
-						Name: corev1.ResourceCPU,
-						Flavors: []kueue.Flavor{
+						CoveredResources: []corev1.ResourceName{corev1.ResourceCPU},
+						Flavors: []kueue.FlavorQuotas{
 							{
 								Name: "nonexistent-flavor",
-								Quota: kueue.Quota{
-									Min: resource.MustParse("50"),
+								Resources: []kueue.ResourceQuota{
+									{
+										Name: corev1.ResourceCPU,
+										Quota: kueue.Quota{
+											Min: resource.MustParse("50"),
+										},
+									},
 								},
 							},
 						},
@@ -268,6 +309,9 @@ func TestSchedule(t *testing.T) {
 		wantInadmissibleLeft map[string]sets.Set[string]
 		// wantPreempted is the keys of the workloads that get preempted in the scheduling cycle.
 		wantPreempted sets.Set[string]
+		// wantScheduledWorkloads is the number of workloads evaluated in the
+		// scheduling cycle, per clusterQueue and result, if set.
+		wantScheduledWorkloads map[string]map[metrics.SchedulingResult]int
 	}{
 		"workload fits in single clusterQueue": {
 			workloads: []kueue.Workload{
@@ -304,6 +348,99 @@ func TestSchedule(t *testing.T) {
 				},
 			},
 			wantScheduled: []string{"sales/foo"},
+			wantScheduledWorkloads: map[string]map[metrics.SchedulingResult]int{
+				"sales": {metrics.SchedulingResultAdmitted: 1},
+			},
+		},
+		"workload partially admitted in single clusterQueue": {
+			workloads: []kueue.Workload{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "sales",
+						Name:      "foo",
+					},
+					Spec: kueue.WorkloadSpec{
+						QueueName: "main",
+						PodSets: []kueue.PodSet{
+							{
+								Name:     "one",
+								Count:    60,
+								MinCount: pointer.Int32(40),
+								Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+									corev1.ResourceCPU: "1",
+								}),
+							},
+						},
+					},
+				},
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/foo": {
+					ClusterQueue: "sales",
+					PodSetFlavors: []kueue.PodSetFlavors{
+						{
+							Name: "one",
+							Flavors: map[corev1.ResourceName]string{
+								corev1.ResourceCPU: "default",
+							},
+							Count: pointer.Int32(50),
+						},
+					},
+				},
+			},
+			wantScheduled: []string{"sales/foo"},
+		},
+		"workload doesn't fit with partial admission below minCount": {
+			workloads: []kueue.Workload{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "sales",
+						Name:      "foo",
+					},
+					Spec: kueue.WorkloadSpec{
+						QueueName: "main",
+						PodSets: []kueue.PodSet{
+							{
+								Name:     "one",
+								Count:    60,
+								MinCount: pointer.Int32(55),
+								Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+									corev1.ResourceCPU: "1",
+								}),
+							},
+						},
+					},
+				},
+			},
+			wantLeft: map[string]sets.Set[string]{
+				"sales": sets.New("sales/foo"),
+			},
+		},
+		"workload past its admission deadline": {
+			workloads: []kueue.Workload{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "sales",
+						Name:      "foo",
+					},
+					Spec: kueue.WorkloadSpec{
+						QueueName:         "main",
+						AdmissionDeadline: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+						PodSets: []kueue.PodSet{
+							{
+								Name:  "one",
+								Count: 10,
+								Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+									corev1.ResourceCPU: "1",
+								}),
+							},
+						},
+					},
+				},
+			},
+			wantLeft: map[string]sets.Set[string]{
+				"sales": sets.New("sales/foo"),
+			},
 		},
 		"error during admission": {
 			workloads: []kueue.Workload{
@@ -396,6 +533,9 @@ func TestSchedule(t *testing.T) {
 			wantLeft: map[string]sets.Set[string]{
 				"sales": sets.New("sales/new"),
 			},
+			wantScheduledWorkloads: map[string]map[metrics.SchedulingResult]int{
+				"sales": {metrics.SchedulingResultInadmissible: 1},
+			},
 		},
 		"failed to match clusterQueue selector": {
 			workloads: []kueue.Workload{
@@ -659,6 +799,10 @@ func TestSchedule(t *testing.T) {
 			wantLeft: map[string]sets.Set[string]{
 				"eng-beta": sets.New("eng-beta/new"),
 			},
+			wantScheduledWorkloads: map[string]map[metrics.SchedulingResult]int{
+				"eng-alpha": {metrics.SchedulingResultAdmitted: 1},
+				"eng-beta":  {metrics.SchedulingResultSkipped: 1},
+			},
 		},
 		"cannot borrow if needs reclaim from cohort": {
 			workloads: []kueue.Workload{
@@ -688,6 +832,86 @@ func TestSchedule(t *testing.T) {
 				"eng-beta/user-on-demand": *utiltesting.MakeAdmission("eng-beta").Flavor(corev1.ResourceCPU, "on-demand").Obj(),
 			},
 		},
+		"workload slice admitted with the flavors of the admitted slices of its job": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("job-slice-0", "eng-beta").
+					Annotation(constants.WorkloadSliceAnnotation, "0").
+					ControllerReference(batchv1.SchemeGroupVersion.WithKind("Job"), "job", "job-uid").
+					Request(corev1.ResourceCPU, "1").
+					Admit(utiltesting.MakeAdmission("eng-beta").Flavor(corev1.ResourceCPU, "spot").Obj()).
+					Obj(),
+				*utiltesting.MakeWorkload("job-slice-1", "eng-beta").
+					Annotation(constants.WorkloadSliceAnnotation, "1").
+					ControllerReference(batchv1.SchemeGroupVersion.WithKind("Job"), "job", "job-uid").
+					Queue("main").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+			},
+			wantScheduled: []string{"eng-beta/job-slice-1"},
+			wantAssignments: map[string]kueue.Admission{
+				"eng-beta/job-slice-0": *utiltesting.MakeAdmission("eng-beta").Flavor(corev1.ResourceCPU, "spot").Obj(),
+				"eng-beta/job-slice-1": *utiltesting.MakeAdmission("eng-beta").Flavor(corev1.ResourceCPU, "spot").Obj(),
+			},
+		},
+		"workload group admitted together": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("driver", "sales").
+					Queue("main").
+					Annotation(constants.WorkloadGroupAnnotation, "group").
+					Annotation(constants.WorkloadGroupSizeAnnotation, "2").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+				*utiltesting.MakeWorkload("executors", "sales").
+					Queue("main").
+					Annotation(constants.WorkloadGroupAnnotation, "group").
+					Annotation(constants.WorkloadGroupSizeAnnotation, "2").
+					Request(corev1.ResourceCPU, "40").
+					Obj(),
+			},
+			wantScheduled: []string{"sales/driver", "sales/executors"},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/driver":    *utiltesting.MakeAdmission("sales").Flavor(corev1.ResourceCPU, "default").Obj(),
+				"sales/executors": *utiltesting.MakeAdmission("sales").Flavor(corev1.ResourceCPU, "default").Obj(),
+			},
+		},
+		"workload group waits for all its workloads": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("driver", "sales").
+					Queue("main").
+					Annotation(constants.WorkloadGroupAnnotation, "group").
+					Annotation(constants.WorkloadGroupSizeAnnotation, "3").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+				*utiltesting.MakeWorkload("executors", "sales").
+					Queue("main").
+					Annotation(constants.WorkloadGroupAnnotation, "group").
+					Annotation(constants.WorkloadGroupSizeAnnotation, "3").
+					Request(corev1.ResourceCPU, "1").
+					Obj(),
+			},
+			wantLeft: map[string]sets.Set[string]{
+				"sales": sets.New("sales/driver", "sales/executors"),
+			},
+		},
+		"workload group doesn't fit as a whole": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("driver", "sales").
+					Queue("main").
+					Annotation(constants.WorkloadGroupAnnotation, "group").
+					Annotation(constants.WorkloadGroupSizeAnnotation, "2").
+					Request(corev1.ResourceCPU, "30").
+					Obj(),
+				*utiltesting.MakeWorkload("executors", "sales").
+					Queue("main").
+					Annotation(constants.WorkloadGroupAnnotation, "group").
+					Annotation(constants.WorkloadGroupSizeAnnotation, "2").
+					Request(corev1.ResourceCPU, "30").
+					Obj(),
+			},
+			wantLeft: map[string]sets.Set[string]{
+				"sales": sets.New("sales/driver", "sales/executors"),
+			},
+		},
 		"preempt workloads in ClusterQueue and cohort": {
 			workloads: []kueue.Workload{
 				*utiltesting.MakeWorkload("preemptor", "eng-beta").
@@ -725,6 +949,9 @@ func TestSchedule(t *testing.T) {
 				"eng-beta/low-2":     *utiltesting.MakeAdmission("eng-beta").Flavor(corev1.ResourceCPU, "on-demand").Obj(),
 				"eng-alpha/borrower": *utiltesting.MakeAdmission("eng-alpha").Flavor(corev1.ResourceCPU, "on-demand").Obj(),
 			},
+			wantScheduledWorkloads: map[string]map[metrics.SchedulingResult]int{
+				"eng-beta": {metrics.SchedulingResultPreempting: 1},
+			},
 		},
 		"cannot borrow resource not listed in clusterQueue": {
 			workloads: []kueue.Workload{
@@ -848,6 +1075,73 @@ func TestSchedule(t *testing.T) {
 				},
 			},
 		},
+		"admitted workload grows in place": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("foo", "sales").
+					Queue("main").
+					PodSets([]kueue.PodSet{
+						{
+							Name:  "one",
+							Count: 10,
+							Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+								corev1.ResourceCPU: "1",
+							}),
+						},
+					}).
+					Admit(utiltesting.MakeAdmission("sales", "one").Flavor(corev1.ResourceCPU, "default").Obj()).
+					PodSetResizes(kueue.PodSetResize{Name: "one", Count: 30}).
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/foo": {
+					ClusterQueue: "sales",
+					PodSetFlavors: []kueue.PodSetFlavors{
+						{
+							Name: "one",
+							Flavors: map[corev1.ResourceName]string{
+								corev1.ResourceCPU: "default",
+							},
+							Count: pointer.Int32(30),
+						},
+					},
+				},
+			},
+			wantScheduled: []string{"sales/foo"},
+		},
+		"admitted workload doesn't grow beyond the quota": {
+			workloads: []kueue.Workload{
+				*utiltesting.MakeWorkload("foo", "sales").
+					Queue("main").
+					PodSets([]kueue.PodSet{
+						{
+							Name:  "one",
+							Count: 10,
+							Spec: utiltesting.PodSpecForRequest(map[corev1.ResourceName]string{
+								corev1.ResourceCPU: "1",
+							}),
+						},
+					}).
+					Admit(utiltesting.MakeAdmission("sales", "one").Flavor(corev1.ResourceCPU, "default").Obj()).
+					PodSetResizes(kueue.PodSetResize{Name: "one", Count: 60}).
+					Obj(),
+			},
+			wantAssignments: map[string]kueue.Admission{
+				"sales/foo": {
+					ClusterQueue: "sales",
+					PodSetFlavors: []kueue.PodSetFlavors{
+						{
+							Name: "one",
+							Flavors: map[corev1.ResourceName]string{
+								corev1.ResourceCPU: "default",
+							},
+						},
+					},
+				},
+			},
+			wantLeft: map[string]sets.Set[string]{
+				"sales": sets.New("sales/foo"),
+			},
+		},
 		"workload should not fit in flavor nonexistent clusterQueue": {
 			workloads: []kueue.Workload{
 				{
@@ -875,121 +1169,149 @@ func TestSchedule(t *testing.T) {
 		},
 	}
 	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			log := testr.NewWithOptions(t, testr.Options{
-				Verbosity: 2,
-			})
-			ctx := ctrl.LoggerInto(context.Background(), log)
-			scheme := runtime.NewScheme()
-			if err := kueue.AddToScheme(scheme); err != nil {
-				t.Fatalf("Failed adding kueue scheme: %v", err)
-			}
-			if err := corev1.AddToScheme(scheme); err != nil {
-				t.Fatalf("Failed adding kueue scheme: %v", err)
-			}
-			clientBuilder := fake.NewClientBuilder().WithScheme(scheme).
-				WithLists(&kueue.WorkloadList{Items: tc.workloads}, &kueue.LocalQueueList{Items: queues}).
-				WithObjects(
-					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "eng-alpha", Labels: map[string]string{"dep": "eng"}}},
-					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "eng-beta", Labels: map[string]string{"dep": "eng"}}},
-					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sales", Labels: map[string]string{"dep": "sales"}}},
-				)
-			cl := clientBuilder.Build()
-			broadcaster := record.NewBroadcaster()
-			recorder := broadcaster.NewRecorder(scheme,
-				corev1.EventSource{Component: constants.AdmissionName})
-			cqCache := cache.New(cl)
-			qManager := queue.NewManager(cl, cqCache)
-			// Workloads are loaded into queues or clusterQueues as we add them.
-			for _, q := range queues {
-				if err := qManager.AddLocalQueue(ctx, &q); err != nil {
-					t.Fatalf("Inserting queue %s/%s in manager: %v", q.Namespace, q.Name, err)
+		// The results don't depend on the cohort concurrency.
+		for _, concurrency := range []int{1, 4} {
+			t.Run(fmt.Sprintf("%s with cohort concurrency %d", name, concurrency), func(t *testing.T) {
+				log := testr.NewWithOptions(t, testr.Options{
+					Verbosity: 2,
+				})
+				ctx := ctrl.LoggerInto(context.Background(), log)
+				scheme := runtime.NewScheme()
+				if err := kueue.AddToScheme(scheme); err != nil {
+					t.Fatalf("Failed adding kueue scheme: %v", err)
 				}
-			}
-			for i := range resourceFlavors {
-				cqCache.AddOrUpdateResourceFlavor(resourceFlavors[i])
-			}
-			for _, cq := range clusterQueues {
-				if err := cqCache.AddClusterQueue(ctx, &cq); err != nil {
-					t.Fatalf("Inserting clusterQueue %s in cache: %v", cq.Name, err)
+				if err := corev1.AddToScheme(scheme); err != nil {
+					t.Fatalf("Failed adding kueue scheme: %v", err)
+				}
+				clientBuilder := fake.NewClientBuilder().WithScheme(scheme).
+					WithLists(&kueue.WorkloadList{Items: tc.workloads}, &kueue.LocalQueueList{Items: queues}).
+					WithObjects(
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "eng-alpha", Labels: map[string]string{"dep": "eng"}}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "eng-beta", Labels: map[string]string{"dep": "eng"}}},
+						&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sales", Labels: map[string]string{"dep": "sales"}}},
+					)
+				cl := clientBuilder.Build()
+				broadcaster := record.NewBroadcaster()
+				recorder := broadcaster.NewRecorder(scheme,
+					corev1.EventSource{Component: constants.AdmissionName})
+				cqCache := cache.New(cl)
+				qManager := queue.NewManager(cl, cqCache)
+				// Workloads are loaded into queues or clusterQueues as we add them.
+				for _, q := range queues {
+					if err := qManager.AddLocalQueue(ctx, &q); err != nil {
+						t.Fatalf("Inserting queue %s/%s in manager: %v", q.Namespace, q.Name, err)
+					}
 				}
-				if err := qManager.AddClusterQueue(ctx, &cq); err != nil {
-					t.Fatalf("Inserting clusterQueue %s in manager: %v", cq.Name, err)
+				for i := range resourceFlavors {
+					cqCache.AddOrUpdateResourceFlavor(resourceFlavors[i])
 				}
-			}
-			scheduler := New(qManager, cqCache, cl, recorder)
-			gotScheduled := make(map[string]kueue.Admission)
-			var mu sync.Mutex
-			scheduler.applyAdmission = func(ctx context.Context, w *kueue.Workload) error {
-				if tc.admissionError != nil {
-					return tc.admissionError
+				for _, cq := range clusterQueues {
+					if err := cqCache.AddClusterQueue(ctx, &cq); err != nil {
+						t.Fatalf("Inserting clusterQueue %s in cache: %v", cq.Name, err)
+					}
+					if err := qManager.AddClusterQueue(ctx, &cq); err != nil {
+						t.Fatalf("Inserting clusterQueue %s in manager: %v", cq.Name, err)
+					}
 				}
-				mu.Lock()
-				gotScheduled[workload.Key(w)] = *w.Spec.Admission
-				mu.Unlock()
-				return nil
-			}
-			wg := sync.WaitGroup{}
-			scheduler.setAdmissionRoutineWrapper(routine.NewWrapper(
-				func() { wg.Add(1) },
-				func() { wg.Done() },
-			))
-			gotPreempted := sets.New[string]()
-			scheduler.preemptor.OverrideApply(func(_ context.Context, w *kueue.Workload) error {
-				mu.Lock()
-				gotPreempted.Insert(workload.Key(w))
-				mu.Unlock()
-				return nil
-			})
+				scheduler := New(qManager, cqCache, cl, recorder, WithCohortConcurrency(concurrency))
+				gotScheduled := make(map[string]kueue.Admission)
+				var mu sync.Mutex
+				scheduler.applyAdmission = func(ctx context.Context, w *kueue.Workload) error {
+					if tc.admissionError != nil {
+						return tc.admissionError
+					}
+					mu.Lock()
+					gotScheduled[workload.Key(w)] = *w.Spec.Admission
+					mu.Unlock()
+					return nil
+				}
+				wg := sync.WaitGroup{}
+				scheduler.setAdmissionRoutineWrapper(routine.NewWrapper(
+					func() { wg.Add(1) },
+					func() { wg.Done() },
+				))
+				gotPreempted := sets.New[string]()
+				scheduler.preemptor.OverrideApply(func(_ context.Context, w *kueue.Workload) error {
+					mu.Lock()
+					gotPreempted.Insert(workload.Key(w))
+					mu.Unlock()
+					return nil
+				})
 
-			ctx, cancel := context.WithTimeout(ctx, queueingTimeout)
-			go qManager.CleanUpOnContext(ctx)
-			defer cancel()
+				ctx, cancel := context.WithTimeout(ctx, queueingTimeout)
+				go qManager.CleanUpOnContext(ctx)
+				defer cancel()
 
-			scheduler.schedule(ctx)
-			wg.Wait()
+				metrics.ScheduledWorkloadsTotal.Reset()
+				scheduler.schedule(ctx)
+				wg.Wait()
 
-			wantScheduled := make(map[string]kueue.Admission)
-			for _, key := range tc.wantScheduled {
-				wantScheduled[key] = tc.wantAssignments[key]
-			}
-			if diff := cmp.Diff(wantScheduled, gotScheduled); diff != "" {
-				t.Errorf("Unexpected scheduled workloads (-want,+got):\n%s", diff)
-			}
+				wantScheduled := make(map[string]kueue.Admission)
+				for _, key := range tc.wantScheduled {
+					wantScheduled[key] = tc.wantAssignments[key]
+				}
+				if diff := cmp.Diff(wantScheduled, gotScheduled); diff != "" {
+					t.Errorf("Unexpected scheduled workloads (-want,+got):\n%s", diff)
+				}
 
-			if diff := cmp.Diff(tc.wantPreempted, gotPreempted); diff != "" {
-				t.Errorf("Unexpected preemptions (-want,+got):\n%s", diff)
-			}
+				if diff := cmp.Diff(tc.wantPreempted, gotPreempted); diff != "" {
+					t.Errorf("Unexpected preemptions (-want,+got):\n%s", diff)
+				}
 
-			// Verify assignments in cache.
-			gotAssignments := make(map[string]kueue.Admission)
-			snapshot := cqCache.Snapshot()
-			for cqName, c := range snapshot.ClusterQueues {
-				for name, w := range c.Workloads {
-					if w.Obj.Spec.Admission == nil {
-						t.Errorf("Workload %s is not admitted by a clusterQueue, but it is found as member of clusterQueue %s in the cache", name, cqName)
-					} else if string(w.Obj.Spec.Admission.ClusterQueue) != cqName {
-						t.Errorf("Workload %s is admitted by clusterQueue %s, but it is found as member of clusterQueue %s in the cache", name, w.Obj.Spec.Admission.ClusterQueue, cqName)
+				// Verify assignments in cache.
+				gotAssignments := make(map[string]kueue.Admission)
+				snapshot := cqCache.Snapshot()
+				for cqName, c := range snapshot.ClusterQueues {
+					for name, w := range c.Workloads {
+						if w.Obj.Spec.Admission == nil {
+							t.Errorf("Workload %s is not admitted by a clusterQueue, but it is found as member of clusterQueue %s in the cache", name, cqName)
+						} else if string(w.Obj.Spec.Admission.ClusterQueue) != cqName {
+							t.Errorf("Workload %s is admitted by clusterQueue %s, but it is found as member of clusterQueue %s in the cache", name, w.Obj.Spec.Admission.ClusterQueue, cqName)
+						}
+						gotAssignments[name] = *w.Obj.Spec.Admission
 					}
-					gotAssignments[name] = *w.Obj.Spec.Admission
 				}
-			}
-			if len(gotAssignments) == 0 {
-				gotAssignments = nil
-			}
-			if diff := cmp.Diff(tc.wantAssignments, gotAssignments); diff != "" {
-				t.Errorf("Unexpected assigned clusterQueues in cache (-want,+got):\n%s", diff)
-			}
+				if len(gotAssignments) == 0 {
+					gotAssignments = nil
+				}
+				if diff := cmp.Diff(tc.wantAssignments, gotAssignments); diff != "" {
+					t.Errorf("Unexpected assigned clusterQueues in cache (-want,+got):\n%s", diff)
+				}
 
-			qDump := qManager.Dump()
-			if diff := cmp.Diff(tc.wantLeft, qDump); diff != "" {
-				t.Errorf("Unexpected elements left in the queue (-want,+got):\n%s", diff)
-			}
-			qDumpInadmissible := qManager.DumpInadmissible()
-			if diff := cmp.Diff(tc.wantInadmissibleLeft, qDumpInadmissible); diff != "" {
-				t.Errorf("Unexpected elements left in inadmissible workloads (-want,+got):\n%s", diff)
-			}
-		})
+				qDump := qManager.Dump()
+				if diff := cmp.Diff(tc.wantLeft, qDump); diff != "" {
+					t.Errorf("Unexpected elements left in the queue (-want,+got):\n%s", diff)
+				}
+				qDumpInadmissible := qManager.DumpInadmissible()
+				if diff := cmp.Diff(tc.wantInadmissibleLeft, qDumpInadmissible); diff != "" {
+					t.Errorf("Unexpected elements left in inadmissible workloads (-want,+got):\n%s", diff)
+				}
+
+				if tc.wantScheduledWorkloads != nil {
+					gotScheduledWorkloads := make(map[string]map[metrics.SchedulingResult]int)
+					for _, cq := range clusterQueues {
+						for _, result := range []metrics.SchedulingResult{
+							metrics.SchedulingResultAdmitted,
+							metrics.SchedulingResultSkipped,
+							metrics.SchedulingResultPreempting,
+							metrics.SchedulingResultInadmissible,
+						} {
+							v := int(testutil.ToFloat64(metrics.ScheduledWorkloadsTotal.WithLabelValues(cq.Name, string(result))))
+							if v == 0 {
+								continue
+							}
+							if gotScheduledWorkloads[cq.Name] == nil {
+								gotScheduledWorkloads[cq.Name] = make(map[metrics.SchedulingResult]int)
+							}
+							gotScheduledWorkloads[cq.Name][result] = v
+						}
+					}
+					if diff := cmp.Diff(tc.wantScheduledWorkloads, gotScheduledWorkloads); diff != "" {
+						t.Errorf("Unexpected scheduled workloads metric (-want,+got):\n%s", diff)
+					}
+				}
+			})
+		}
 	}
 }
 
@@ -1052,14 +1374,50 @@ func TestEntryOrdering(t *testing.T) {
 
 var ignoreConditionTimestamps = cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")
 
+func TestSchedulingResult(t *testing.T) {
+	cases := map[string]struct {
+		e    entry
+		want metrics.SchedulingResult
+	}{
+		"assumed": {
+			e:    entry{status: assumed},
+			want: metrics.SchedulingResultAdmitted,
+		},
+		"skipped": {
+			e:    entry{status: skipped},
+			want: metrics.SchedulingResultSkipped,
+		},
+		"waiting for preemptions": {
+			e:    entry{requeueReason: queue.RequeueReasonPendingPreemption},
+			want: metrics.SchedulingResultPreempting,
+		},
+		"not nominated": {
+			e:    entry{status: notNominated},
+			want: metrics.SchedulingResultInadmissible,
+		},
+		"failed after nomination": {
+			e:    entry{status: nominated, requeueReason: queue.RequeueReasonFailedAfterNomination},
+			want: metrics.SchedulingResultInadmissible,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.e.schedulingResult(); got != tc.want {
+				t.Errorf("schedulingResult() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestRequeueAndUpdate(t *testing.T) {
-	cq := utiltesting.MakeClusterQueue("cq").Obj()
-	q1 := utiltesting.MakeLocalQueue("q1", "ns1").ClusterQueue(cq.Name).Obj()
+	q1 := utiltesting.MakeLocalQueue("q1", "ns1").ClusterQueue("cq").Obj()
 	w1 := utiltesting.MakeWorkload("w1", "ns1").Queue(q1.Name).Obj()
 
 	cases := []struct {
 		name             string
 		e                entry
+		strategy         kueue.QueueingStrategy
+		opts             []Option
 		wantWorkloads    map[string]sets.Set[string]
 		wantInadmissible map[string]sets.Set[string]
 		wantStatus       kueue.WorkloadStatus
@@ -1083,6 +1441,73 @@ func TestRequeueAndUpdate(t *testing.T) {
 				"cq": sets.New(workload.Key(w1)),
 			},
 		},
+		{
+			name: "workload didn't fit with requeuing backoff",
+			e: entry{
+				inadmissibleMsg: "didn't fit",
+			},
+			opts: []Option{WithRequeuingBackoff(time.Minute, time.Hour, 0)},
+			wantStatus: kueue.WorkloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    kueue.WorkloadAdmitted,
+						Status:  metav1.ConditionFalse,
+						Reason:  "Pending",
+						Message: "didn't fit",
+					},
+				},
+				RequeueState: &kueue.RequeueState{
+					Count: pointer.Int32(1),
+				},
+			},
+			wantInadmissible: map[string]sets.Set[string]{
+				"cq": sets.New(workload.Key(w1)),
+			},
+		},
+		{
+			name:     "workload didn't fit in StrictFIFO",
+			strategy: kueue.StrictFIFO,
+			e: entry{
+				inadmissibleMsg: "didn't fit",
+			},
+			wantStatus: kueue.WorkloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    kueue.WorkloadAdmitted,
+						Status:  metav1.ConditionFalse,
+						Reason:  "Pending",
+						Message: "didn't fit",
+					},
+				},
+			},
+			wantWorkloads: map[string]sets.Set[string]{
+				"cq": sets.New(workload.Key(w1)),
+			},
+		},
+		{
+			name:     "workload didn't fit in StrictFIFO with requeuing backoff",
+			strategy: kueue.StrictFIFO,
+			e: entry{
+				inadmissibleMsg: "didn't fit",
+			},
+			opts: []Option{WithRequeuingBackoff(time.Minute, time.Hour, 0)},
+			wantStatus: kueue.WorkloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    kueue.WorkloadAdmitted,
+						Status:  metav1.ConditionFalse,
+						Reason:  "Pending",
+						Message: "didn't fit",
+					},
+				},
+				RequeueState: &kueue.RequeueState{
+					Count: pointer.Int32(1),
+				},
+			},
+			wantInadmissible: map[string]sets.Set[string]{
+				"cq": sets.New(workload.Key(w1)),
+			},
+		},
 		{
 			name: "assumed",
 			e: entry{
@@ -1128,6 +1553,10 @@ func TestRequeueAndUpdate(t *testing.T) {
 			if err := corev1.AddToScheme(scheme); err != nil {
 				t.Fatalf("Failed adding kueue scheme: %v", err)
 			}
+			cq := utiltesting.MakeClusterQueue("cq").Obj()
+			if tc.strategy != "" {
+				cq.Spec.QueueingStrategy = tc.strategy
+			}
 
 			clientBuilder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(w1, q1, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}})
 			cl := clientBuilder.Build()
@@ -1135,7 +1564,7 @@ func TestRequeueAndUpdate(t *testing.T) {
 			recorder := broadcaster.NewRecorder(scheme, corev1.EventSource{Component: constants.AdmissionName})
 			cqCache := cache.New(cl)
 			qManager := queue.NewManager(cl, cqCache)
-			scheduler := New(qManager, cqCache, cl, recorder)
+			scheduler := New(qManager, cqCache, cl, recorder, tc.opts...)
 			if err := qManager.AddLocalQueue(ctx, q1); err != nil {
 				t.Fatalf("Inserting queue %s/%s in manager: %v", q1.Namespace, q1.Name, err)
 			}
@@ -1170,9 +1599,255 @@ func TestRequeueAndUpdate(t *testing.T) {
 			if err := cl.Get(ctx, client.ObjectKeyFromObject(w1), &updatedWl); err != nil {
 				t.Fatalf("Failed obtaining updated object: %v", err)
 			}
-			if diff := cmp.Diff(tc.wantStatus, updatedWl.Status, ignoreConditionTimestamps); diff != "" {
+			if diff := cmp.Diff(tc.wantStatus, updatedWl.Status, ignoreConditionTimestamps,
+				cmpopts.IgnoreFields(kueue.RequeueState{}, "RequeueAt")); diff != "" {
 				t.Errorf("Unexpected status after updating (-want,+got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestRequeuingBackoffNext(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	backoff := requeuingBackoff{
+		baseDelay: time.Second,
+		maxDelay:  time.Minute,
+	}
+	cases := map[string]struct {
+		state *kueue.RequeueState
+		want  *kueue.RequeueState
+	}{
+		"first failure": {
+			want: &kueue.RequeueState{
+				Count:     pointer.Int32(1),
+				RequeueAt: &metav1.Time{Time: now.Add(time.Second)},
+			},
+		},
+		"delay doubles": {
+			state: &kueue.RequeueState{
+				Count: pointer.Int32(3),
+			},
+			want: &kueue.RequeueState{
+				Count:     pointer.Int32(4),
+				RequeueAt: &metav1.Time{Time: now.Add(8 * time.Second)},
+			},
+		},
+		"delay capped at maxDelay": {
+			state: &kueue.RequeueState{
+				Count:     pointer.Int32(100),
+				RequeueAt: &metav1.Time{Time: now},
+			},
+			want: &kueue.RequeueState{
+				Count:     pointer.Int32(101),
+				RequeueAt: &metav1.Time{Time: now.Add(time.Minute)},
+			},
+		},
+		"evictions are kept": {
+			state: &kueue.RequeueState{
+				Evictions: pointer.Int32(2),
+			},
+			want: &kueue.RequeueState{
+				Count:     pointer.Int32(1),
+				RequeueAt: &metav1.Time{Time: now.Add(time.Second)},
+				Evictions: pointer.Int32(2),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := backoff.next(tc.state, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected requeue state (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLeaderElectionFencing(t *testing.T) {
+	leaseKey := types.NamespacedName{Namespace: "kueue-system", Name: "kueue-lock"}
+	cases := map[string]struct {
+		lease          *coordinationv1.Lease
+		wantFieldOwner string
+		wantErr        bool
+	}{
+		"first term": {
+			lease: &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Namespace: leaseKey.Namespace, Name: leaseKey.Name},
+			},
+			wantFieldOwner: "kueue-admission-0",
+		},
+		"after transitions": {
+			lease: &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Namespace: leaseKey.Namespace, Name: leaseKey.Name},
+				Spec:       coordinationv1.LeaseSpec{LeaseTransitions: pointer.Int32(3)},
+			},
+			wantFieldOwner: "kueue-admission-3",
+		},
+		"missing lease": {
+			wantFieldOwner: constants.AdmissionName,
+			wantErr:        true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scheme := utiltesting.MustGetScheme(t)
+			if err := coordinationv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding coordination to scheme: %v", err)
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.lease != nil {
+				builder = builder.WithObjects(tc.lease)
+			}
+			cl := builder.Build()
+			cqCache := cache.New(cl)
+			qManager := queue.NewManager(cl, cqCache)
+			scheduler := New(qManager, cqCache, cl, record.NewFakeRecorder(1), WithLeaderElectionFencing(cl, leaseKey))
+			if !scheduler.NeedLeaderElection() {
+				t.Error("The scheduler doesn't need leader election")
+			}
+
+			// The scheduling cycles don't run with a canceled context.
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := scheduler.Start(ctx)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Start() returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if scheduler.fieldOwner != tc.wantFieldOwner {
+				t.Errorf("Got field owner %q, want %q", scheduler.fieldOwner, tc.wantFieldOwner)
+			}
+		})
+	}
+}
+
+func TestLeadershipLost(t *testing.T) {
+	leaseKey := types.NamespacedName{Namespace: "kueue-system", Name: "kueue-lock"}
+	cases := map[string]struct {
+		holder      string
+		transitions int32
+		wantErr     error
+	}{
+		"same term": {
+			holder:      "replica-a",
+			transitions: 3,
+		},
+		"another holder": {
+			holder:      "replica-b",
+			transitions: 3,
+			wantErr:     errLeadershipLost,
+		},
+		"new term": {
+			holder:      "replica-a",
+			transitions: 4,
+			wantErr:     errLeadershipLost,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scheme := utiltesting.MustGetScheme(t)
+			if err := coordinationv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("Failed adding coordination to scheme: %v", err)
+			}
+			lease := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Namespace: leaseKey.Namespace, Name: leaseKey.Name},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:   pointer.String("replica-a"),
+					LeaseTransitions: pointer.Int32(3),
+				},
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(lease).Build()
+			cqCache := cache.New(cl)
+			qManager := queue.NewManager(cl, cqCache)
+			scheduler := New(qManager, cqCache, cl, record.NewFakeRecorder(1), WithLeaderElectionFencing(cl, leaseKey))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := scheduler.Start(ctx); err != nil {
+				t.Fatalf("Starting the scheduler: %v", err)
+			}
+
+			lease.Spec.HolderIdentity = pointer.String(tc.holder)
+			lease.Spec.LeaseTransitions = pointer.Int32(tc.transitions)
+			if err := cl.Update(context.Background(), lease); err != nil {
+				t.Fatalf("Updating the lease: %v", err)
+			}
+			fieldOwner, err := scheduler.admissionFieldOwner(context.Background())
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Getting the field owner returned error %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil {
+				if fieldOwner != "kueue-admission-3" {
+					t.Errorf("Got field owner %q, want %q", fieldOwner, "kueue-admission-3")
+				}
+				return
+			}
+			// The admission isn't applied, so the deposed leader doesn't
+			// co-own the admission with the current one.
+			wl := utiltesting.MakeWorkload("foo", "default").Admit(utiltesting.MakeAdmission("cq").Obj()).Obj()
+			if err := scheduler.applyAdmissionWithSSA(context.Background(), workload.AdmissionPatch(wl)); !errors.Is(err, errLeadershipLost) {
+				t.Errorf("Applying the admission returned error %v, want %v", err, errLeadershipLost)
+			}
+		})
+	}
+}
+
+func TestAdmissionPatchWorkers(t *testing.T) {
+	const workers = 2
+	cl := fake.NewClientBuilder().WithScheme(utiltesting.MustGetScheme(t)).Build()
+	cqCache := cache.New(cl)
+	qManager := queue.NewManager(cl, cqCache)
+	scheduler := New(qManager, cqCache, cl, record.NewFakeRecorder(1), WithAdmissionPatchWorkers(workers))
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	release := make(chan struct{})
+	scheduler.applyAdmission = func(context.Context, *kueue.Workload) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := scheduler.applyAdmissionPatch(context.Background(), utiltesting.MakeWorkload("foo", "default").Obj()); err != nil {
+				t.Errorf("Applying the admission patch: %v", err)
+			}
+		}()
+	}
+	// Wait for the workers to be busy before releasing them.
+	for {
+		mu.Lock()
+		busy := running == workers
+		mu.Unlock()
+		if busy {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if maxRunning != workers {
+		t.Errorf("Applied %d admission patches concurrently, want %d", maxRunning, workers)
+	}
+
+	// The patches waiting for a worker are canceled with the context.
+	for i := 0; i < workers; i++ {
+		scheduler.admissionWorkers <- struct{}{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := scheduler.applyAdmissionPatch(ctx, utiltesting.MakeWorkload("foo", "default").Obj()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Applying the admission patch with a canceled context returned %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyConflict is a field that a server-side apply couldn't set because
+// another field manager owns it.
+type ApplyConflict struct {
+	// Manager is the field manager that owns the field.
+	Manager string `json:"manager"`
+	// Field is the path of the field, such as .spec.admission.
+	Field string `json:"field"`
+}
+
+// ApplyConflicts returns the conflicts of a failed server-side apply, decoded
+// from the causes of the error, or nil if the error is not a conflict.
+func ApplyConflicts(err error) []ApplyConflict {
+	var statusErr *apierrors.StatusError
+	if !apierrors.IsConflict(err) || !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		return nil
+	}
+	var conflicts []ApplyConflict
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, ApplyConflict{
+			Manager: conflictManager(cause.Message),
+			Field:   cause.Field,
+		})
+	}
+	return conflicts
+}
+
+// conflictManager returns the field manager in the message of a conflict,
+// which has the form: conflict with "manager" using group/version.
+func conflictManager(message string) string {
+	quoted, err := strconv.QuotedPrefix(strings.TrimPrefix(message, "conflict with "))
+	if err != nil {
+		return ""
+	}
+	manager, err := strconv.Unquote(quoted)
+	if err != nil {
+		return ""
+	}
+	return manager
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestApplyConflicts(t *testing.T) {
+	conflictErr := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "my-controller" using kueue.x-k8s.io/v1alpha2`,
+			Field:   ".spec.admission.clusterQueue",
+		},
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "other" with subresource "status" using kueue.x-k8s.io/v1alpha2`,
+			Field:   ".status.conditions",
+		},
+	}, "Apply failed with 2 conflicts")
+	cases := map[string]struct {
+		err  error
+		want []ApplyConflict
+	}{
+		"not a conflict": {
+			err: apierrors.NewNotFound(schema.GroupResource{Resource: "workloads"}, "foo"),
+		},
+		"conflict without causes": {
+			err: apierrors.NewConflict(schema.GroupResource{Resource: "workloads"}, "foo", errors.New("the object has been modified")),
+		},
+		"apply conflict": {
+			err: fmt.Errorf("admitting: %w", conflictErr),
+			want: []ApplyConflict{
+				{Manager: "my-controller", Field: ".spec.admission.clusterQueue"},
+				{Manager: "other", Field: ".status.conditions"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ApplyConflicts(tc.err)); diff != "" {
+				t.Errorf("Unexpected conflicts (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
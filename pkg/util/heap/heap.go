@@ -149,6 +149,21 @@ func (h *Heap) Pop() interface{} {
 	return heap.Pop(&h.data)
 }
 
+// Peek returns the head of the heap without removing it.
+// It returns nil if the heap is empty.
+func (h *Heap) Peek() interface{} {
+	if h.data.Len() == 0 {
+		return nil
+	}
+	return h.data.items[h.data.keys[0]].obj
+}
+
+// Reorder restores the heap invariant after the ordering of the items changed,
+// for example, because it depends on the time.
+func (h *Heap) Reorder() {
+	heap.Init(&h.data)
+}
+
 // Get returns the requested item, exists, error.
 func (h *Heap) Get(obj interface{}) (item interface{}) {
 	key := h.data.keyFunc(obj)
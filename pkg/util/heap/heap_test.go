@@ -253,6 +253,25 @@ func TestHeap_GetByKey(t *testing.T) {
 	}
 }
 
+// TestHeap_Peek tests Heap.Peek function.
+func TestHeap_Peek(t *testing.T) {
+	h := New(testHeapObjectKeyFunc, compareInts)
+	if obj := h.Peek(); obj != nil {
+		t.Fatalf("didn't expect to get any object")
+	}
+	h.PushOrUpdate(mkHeapObj("foo", 10))
+	h.PushOrUpdate(mkHeapObj("bar", 1))
+	h.PushOrUpdate(mkHeapObj("baz", 11))
+
+	obj := h.Peek()
+	if obj == nil || obj.(testHeapObject).val != 1 {
+		t.Fatalf("unexpected head %v", obj)
+	}
+	if h.Len() != 3 {
+		t.Fatalf("expected 3 items, got %d", h.Len())
+	}
+}
+
 // TestHeap_List tests Heap.List function.
 func TestHeap_List(t *testing.T) {
 	h := New(testHeapObjectKeyFunc, compareInts)
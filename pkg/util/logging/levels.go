@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// namedLevelsCore drops the entries below the level of the name of their
+// logger, or of the closest parent name, or below the default level for the
+// loggers without a level.
+type namedLevelsCore struct {
+	zapcore.Core
+	level  zapcore.Level
+	levels map[string]zapcore.Level
+	min    zapcore.Level
+}
+
+// WithNamedLevels wraps the core so that the entries of the loggers with the
+// given names, and of the loggers nested under them, such as
+// "scheduler.preemption" under "scheduler", are logged at the level of the
+// name. The entries of the other loggers are logged at the given level.
+// The core needs to be enabled for the lowest of the levels.
+func WithNamedLevels(core zapcore.Core, level zapcore.Level, levels map[string]zapcore.Level) zapcore.Core {
+	min := level
+	for _, l := range levels {
+		if l < min {
+			min = l
+		}
+	}
+	return &namedLevelsCore{Core: core, level: level, levels: levels, min: min}
+}
+
+func (c *namedLevelsCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.min
+}
+
+func (c *namedLevelsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &namedLevelsCore{Core: c.Core.With(fields), level: c.level, levels: c.levels, min: c.min}
+}
+
+func (c *namedLevelsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.levelOf(ent.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *namedLevelsCore) levelOf(name string) zapcore.Level {
+	for name != "" {
+		if l, ok := c.levels[name]; ok {
+			return l
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return c.level
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithNamedLevels(t *testing.T) {
+	core, logs := observer.New(zapcore.Level(-5))
+	log := zap.New(WithNamedLevels(core, zapcore.InfoLevel, map[string]zapcore.Level{
+		"scheduler":            zapcore.Level(-2),
+		"scheduler.preemption": zapcore.Level(-5),
+		"workload-reconciler":  zapcore.ErrorLevel,
+	}))
+
+	log.Info("root info")
+	log.Debug("root debug")
+	scheduler := log.Named("scheduler")
+	scheduler.Log(zapcore.Level(-2), "scheduler v2")
+	scheduler.Log(zapcore.Level(-3), "scheduler v3")
+	scheduler.Named("flavorassigner").Log(zapcore.Level(-2), "flavorassigner v2")
+	scheduler.Named("preemption").With(zap.String("workload", "ns/a")).Log(zapcore.Level(-5), "preemption v5")
+	reconciler := log.Named("workload-reconciler")
+	reconciler.Info("reconciler info")
+	reconciler.Error("reconciler error")
+	log.Named("schedulers").Log(zapcore.Level(-2), "schedulers v2")
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+	want := []string{
+		"root info",
+		"scheduler v2",
+		"flavorassigner v2",
+		"preemption v5",
+		"reconciler error",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected logged messages (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pprof
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	testCases := map[string]int{
+		"/debug/pprof/":        http.StatusOK,
+		"/debug/pprof/heap":    http.StatusOK,
+		"/debug/pprof/cmdline": http.StatusOK,
+		"/metrics":             http.StatusNotFound,
+	}
+	handler := Handler()
+	for path, wantCode := range testCases {
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			if rec.Code != wantCode {
+				t.Errorf("GET %s returned %d, want %d", path, rec.Code, wantCode)
+			}
+		})
+	}
+}
@@ -18,8 +18,12 @@ package priority
 
 import (
 	"context"
+	"math"
+	"time"
 
 	schedulingv1 "k8s.io/api/scheduling/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,7 +32,9 @@ import (
 	"sigs.k8s.io/kueue/pkg/constants"
 )
 
-// Priority returns priority of the given workload.
+// Priority returns priority of the given workload. It's resolved when the
+// workload is created, from its WorkloadPriorityClass, if set, or from the
+// PriorityClass of its pods.
 func Priority(w *kueue.Workload) int32 {
 	// When priority of a running workload is nil, it means it was created at a time
 	// that there was no global default priority class and the priority class
@@ -36,6 +42,72 @@ func Priority(w *kueue.Workload) int32 {
 	return pointer.Int32Deref(w.Spec.Priority, constants.DefaultPriority)
 }
 
+// EffectivePriority returns the priority of a pending workload, increased by
+// the aging policy of its ClusterQueue according to the time that it has been
+// waiting since now. The priority of an admitted workload doesn't age.
+func EffectivePriority(w *kueue.Workload, aging *kueue.PriorityAging, now time.Time) int32 {
+	p := Priority(w)
+	if aging == nil || aging.IntervalSeconds <= 0 || w.Spec.Admission != nil {
+		return p
+	}
+	waiting := now.Sub(waitingSince(w))
+	if waiting <= 0 {
+		return p
+	}
+	step := int64(aging.Step)
+	if step <= 0 {
+		step = 1
+	}
+	boost := int64(waiting/(time.Duration(aging.IntervalSeconds)*time.Second)) * step
+	if aging.MaxBoost != nil && boost > int64(*aging.MaxBoost) {
+		boost = int64(*aging.MaxBoost)
+	}
+	if effective := int64(p) + boost; effective < math.MaxInt32 {
+		return int32(effective)
+	}
+	return math.MaxInt32
+}
+
+// waitingSince returns the time since which the workload waits for admission:
+// the time it was requeued after its last eviction or, otherwise, its
+// creation time.
+func waitingSince(w *kueue.Workload) time.Time {
+	if cond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadEvicted); cond != nil && cond.Status == metav1.ConditionFalse {
+		return cond.LastTransitionTime.Time
+	}
+	return w.CreationTimestamp.Time
+}
+
+// GetPriority returns the name, the source and the value of the priority of a
+// workload. The WorkloadPriorityClass with the given name, if any, takes
+// precedence over the PriorityClass of the pods.
+func GetPriority(ctx context.Context, client client.Client,
+	workloadPriorityClass, podPriorityClass string) (string, string, int32, error) {
+	if len(workloadPriorityClass) > 0 {
+		return GetPriorityFromWorkloadPriorityClass(ctx, client, workloadPriorityClass)
+	}
+	name, value, err := GetPriorityFromPriorityClass(ctx, client, podPriorityClass)
+	if err != nil {
+		return "", "", 0, err
+	}
+	var source string
+	if len(name) > 0 {
+		source = kueue.PodPriorityClassSource
+	}
+	return name, source, value, nil
+}
+
+// GetPriorityFromWorkloadPriorityClass returns the name, the source and the
+// value of the WorkloadPriorityClass with the given name.
+func GetPriorityFromWorkloadPriorityClass(ctx context.Context, client client.Client,
+	workloadPriorityClass string) (string, string, int32, error) {
+	wpc := &kueue.WorkloadPriorityClass{}
+	if err := client.Get(ctx, types.NamespacedName{Name: workloadPriorityClass}, wpc); err != nil {
+		return "", "", 0, err
+	}
+	return wpc.Name, kueue.WorkloadPriorityClassSource, wpc.Value, nil
+}
+
 // GetPriorityFromPriorityClass returns the priority populated from
 // priority class. If not specified, priority will be default or
 // zero if there is no default.
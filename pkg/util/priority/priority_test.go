@@ -18,12 +18,15 @@ package priority
 
 import (
 	"context"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
@@ -58,6 +61,69 @@ func TestPriority(t *testing.T) {
 	}
 }
 
+func TestEffectivePriority(t *testing.T) {
+	now := time.Now()
+	aging := &kueue.PriorityAging{IntervalSeconds: 60, Step: 10}
+	tests := map[string]struct {
+		workload *kueue.Workload
+		aging    *kueue.PriorityAging
+		want     int32
+	}{
+		"without aging": {
+			workload: utiltesting.MakeWorkload("name", "ns").Priority(100).Creation(now.Add(-time.Hour)).Obj(),
+			want:     100,
+		},
+		"pending for less than an interval": {
+			workload: utiltesting.MakeWorkload("name", "ns").Priority(100).Creation(now.Add(-59 * time.Second)).Obj(),
+			aging:    aging,
+			want:     100,
+		},
+		"pending for several intervals": {
+			workload: utiltesting.MakeWorkload("name", "ns").Priority(100).Creation(now.Add(-150 * time.Second)).Obj(),
+			aging:    aging,
+			want:     120,
+		},
+		"boost limited by maxBoost": {
+			workload: utiltesting.MakeWorkload("name", "ns").Priority(100).Creation(now.Add(-time.Hour)).Obj(),
+			aging:    &kueue.PriorityAging{IntervalSeconds: 60, Step: 10, MaxBoost: pointer.Int32(25)},
+			want:     125,
+		},
+		"waiting since requeued after an eviction": {
+			workload: utiltesting.MakeWorkload("name", "ns").Priority(100).Creation(now.Add(-time.Hour)).
+				Condition(v1.Condition{
+					Type:               kueue.WorkloadEvicted,
+					Status:             v1.ConditionFalse,
+					LastTransitionTime: v1.NewTime(now.Add(-90 * time.Second)),
+					Reason:             "Requeued",
+				}).
+				Obj(),
+			aging: aging,
+			want:  110,
+		},
+		"admitted workload": {
+			workload: utiltesting.MakeWorkload("name", "ns").Priority(100).Creation(now.Add(-time.Hour)).
+				Admit(utiltesting.MakeAdmission("cq").Obj()).
+				Obj(),
+			aging: aging,
+			want:  100,
+		},
+		"boost limited by the maximum priority": {
+			workload: utiltesting.MakeWorkload("name", "ns").Priority(math.MaxInt32 - 5).Creation(now.Add(-time.Hour)).Obj(),
+			aging:    aging,
+			want:     math.MaxInt32,
+		},
+	}
+
+	for desc, tt := range tests {
+		t.Run(desc, func(t *testing.T) {
+			got := EffectivePriority(tt.workload, tt.aging, now)
+			if got != tt.want {
+				t.Errorf("EffectivePriority does not match: got: %d, expected: %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetPriorityFromPriorityClass(t *testing.T) {
 	scheme := runtime.NewScheme()
 	if err := schedulingv1.AddToScheme(scheme); err != nil {
@@ -163,3 +229,92 @@ func TestGetPriorityFromPriorityClass(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPriority(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := schedulingv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding scheduling scheme: %v", err)
+	}
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	podPriorityClasses := &schedulingv1.PriorityClassList{
+		Items: []schedulingv1.PriorityClass{
+			{
+				ObjectMeta: v1.ObjectMeta{Name: "pod-high"},
+				Value:      1000,
+			},
+		},
+	}
+	workloadPriorityClasses := &kueue.WorkloadPriorityClassList{
+		Items: []kueue.WorkloadPriorityClass{
+			{
+				ObjectMeta: v1.ObjectMeta{Name: "workload-low"},
+				Value:      10,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		workloadPriorityClass string
+		podPriorityClass      string
+		wantName              string
+		wantSource            string
+		wantValue             int32
+		wantErr               string
+	}{
+		"workloadPriorityClass takes precedence": {
+			workloadPriorityClass: "workload-low",
+			podPriorityClass:      "pod-high",
+			wantName:              "workload-low",
+			wantSource:            kueue.WorkloadPriorityClassSource,
+			wantValue:             10,
+		},
+		"only the pod priorityClass": {
+			podPriorityClass: "pod-high",
+			wantName:         "pod-high",
+			wantSource:       kueue.PodPriorityClassSource,
+			wantValue:        1000,
+		},
+		"no priority classes": {
+			wantValue: constants.DefaultPriority,
+		},
+		"workloadPriorityClass does not exist": {
+			workloadPriorityClass: "workload-high",
+			podPriorityClass:      "pod-high",
+			wantErr:               `workloadpriorityclasses.kueue.x-k8s.io "workload-high" not found`,
+		},
+	}
+
+	for desc, tt := range tests {
+		tt := tt
+		t.Run(desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithLists(podPriorityClasses, workloadPriorityClasses).Build()
+
+			name, source, value, err := GetPriority(context.Background(), client, tt.workloadPriorityClass, tt.podPriorityClass)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				if diff := cmp.Diff(tt.wantErr, err.Error()); diff != "" {
+					t.Errorf("unexpected error (-want,+got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tt.wantName {
+				t.Errorf("unexpected name: got: %s, expected: %s", name, tt.wantName)
+			}
+			if source != tt.wantSource {
+				t.Errorf("unexpected source: got: %s, expected: %s", source, tt.wantSource)
+			}
+			if value != tt.wantValue {
+				t.Errorf("unexpected value: got: %d, expected: %d", value, tt.wantValue)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package testing
 
 import (
+	"strconv"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -26,6 +27,8 @@ import (
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/constants"
@@ -81,6 +84,24 @@ func (j *JobWrapper) Parallelism(p int32) *JobWrapper {
 	return j
 }
 
+// Completions updates job completions.
+func (j *JobWrapper) Completions(c int32) *JobWrapper {
+	j.Spec.Completions = pointer.Int32(c)
+	return j
+}
+
+// CompletionMode updates the completion mode of the job.
+func (j *JobWrapper) CompletionMode(m batchv1.CompletionMode) *JobWrapper {
+	j.Spec.CompletionMode = &m
+	return j
+}
+
+// Succeeded updates the number of succeeded pods in the job status.
+func (j *JobWrapper) Succeeded(s int32) *JobWrapper {
+	j.Status.Succeeded = s
+	return j
+}
+
 // PriorityClass updates job priorityclass.
 func (j *JobWrapper) PriorityClass(pc string) *JobWrapper {
 	j.Spec.Template.Spec.PriorityClassName = pc
@@ -93,12 +114,30 @@ func (j *JobWrapper) Queue(queue string) *JobWrapper {
 	return j
 }
 
+// Imported sets the imported annotation
+func (j *JobWrapper) Imported() *JobWrapper {
+	j.Annotations[constants.ImportedAnnotation] = "true"
+	return j
+}
+
 // ParentWorkload sets the parent-workload annotation
 func (j *JobWrapper) ParentWorkload(parentWorkload string) *JobWrapper {
 	j.Annotations[constants.ParentWorkloadAnnotation] = parentWorkload
 	return j
 }
 
+// MinParallelism sets the min-parallelism annotation
+func (j *JobWrapper) MinParallelism(p int32) *JobWrapper {
+	j.Annotations[constants.JobMinParallelismAnnotation] = strconv.Itoa(int(p))
+	return j
+}
+
+// SliceSize sets the slice-size annotation
+func (j *JobWrapper) SliceSize(s int32) *JobWrapper {
+	j.Annotations[constants.JobSliceSizeAnnotation] = strconv.Itoa(int(s))
+	return j
+}
+
 // Toleration adds a toleration to the job.
 func (j *JobWrapper) Toleration(t corev1.Toleration) *JobWrapper {
 	j.Spec.Template.Spec.Tolerations = append(j.Spec.Template.Spec.Tolerations, t)
@@ -127,6 +166,76 @@ func (j *JobWrapper) Image(name string, image string, args []string) *JobWrapper
 	return j
 }
 
+// PodWrapper wraps a Pod.
+type PodWrapper struct{ corev1.Pod }
+
+// MakePod creates a wrapper for a pending pod with a single container.
+func MakePod(name, ns string) *PodWrapper {
+	return &PodWrapper{corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   ns,
+			UID:         types.UID(name),
+			Labels:      make(map[string]string, 1),
+			Annotations: make(map[string]string, 1),
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: "Never",
+			Containers: []corev1.Container{
+				{
+					Name:      "c",
+					Image:     "pause",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{}},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}}
+}
+
+// Obj returns the inner Pod.
+func (p *PodWrapper) Obj() *corev1.Pod {
+	return &p.Pod
+}
+
+// Queue updates the queue name of the pod.
+func (p *PodWrapper) Queue(queue string) *PodWrapper {
+	p.Annotations[constants.QueueAnnotation] = queue
+	return p
+}
+
+// Label sets a label of the pod.
+func (p *PodWrapper) Label(k, v string) *PodWrapper {
+	p.Labels[k] = v
+	return p
+}
+
+// Annotation sets an annotation of the pod.
+func (p *PodWrapper) Annotation(k, v string) *PodWrapper {
+	p.Annotations[k] = v
+	return p
+}
+
+// Phase sets the phase of the pod.
+func (p *PodWrapper) Phase(phase corev1.PodPhase) *PodWrapper {
+	p.Status.Phase = phase
+	return p
+}
+
+// NodeName sets the node that the pod is scheduled to.
+func (p *PodWrapper) NodeName(name string) *PodWrapper {
+	p.Spec.NodeName = name
+	return p
+}
+
+// Request adds a resource request to the default container.
+func (p *PodWrapper) Request(r corev1.ResourceName, v string) *PodWrapper {
+	p.Spec.Containers[0].Resources.Requests[r] = resource.MustParse(v)
+	return p
+}
+
 // PriorityClassWrapper wraps a PriorityClass.
 type PriorityClassWrapper struct {
 	schedulingv1.PriorityClass
@@ -189,6 +298,15 @@ func (w *WorkloadWrapper) Request(r corev1.ResourceName, q string) *WorkloadWrap
 	return w
 }
 
+func (w *WorkloadWrapper) Limit(r corev1.ResourceName, q string) *WorkloadWrapper {
+	res := &w.Spec.PodSets[0].Spec.Containers[0].Resources
+	if res.Limits == nil {
+		res.Limits = corev1.ResourceList{}
+	}
+	res.Limits[r] = resource.MustParse(q)
+	return w
+}
+
 func (w *WorkloadWrapper) Queue(q string) *WorkloadWrapper {
 	w.Spec.QueueName = q
 	return w
@@ -204,11 +322,25 @@ func (w *WorkloadWrapper) Creation(t time.Time) *WorkloadWrapper {
 	return w
 }
 
+// RequeueState sets the requeue state of the workload.
+func (w *WorkloadWrapper) RequeueState(count int32, requeueAt *metav1.Time) *WorkloadWrapper {
+	w.Status.RequeueState = &kueue.RequeueState{
+		Count:     &count,
+		RequeueAt: requeueAt,
+	}
+	return w
+}
+
 func (w *WorkloadWrapper) PriorityClass(priorityClassName string) *WorkloadWrapper {
 	w.Spec.PriorityClassName = priorityClassName
 	return w
 }
 
+func (w *WorkloadWrapper) PriorityClassSource(source string) *WorkloadWrapper {
+	w.Spec.PriorityClassSource = source
+	return w
+}
+
 func (w *WorkloadWrapper) RuntimeClass(name string) *WorkloadWrapper {
 	for i := range w.Spec.PodSets {
 		w.Spec.PodSets[i].Spec.RuntimeClassName = &name
@@ -216,6 +348,24 @@ func (w *WorkloadWrapper) RuntimeClass(name string) *WorkloadWrapper {
 	return w
 }
 
+func (w *WorkloadWrapper) Active(active bool) *WorkloadWrapper {
+	w.Spec.Active = &active
+	return w
+}
+
+// Reservation makes the workload only reserve quota, with the given TTL after
+// its admission.
+func (w *WorkloadWrapper) Reservation(ttlSeconds *int32) *WorkloadWrapper {
+	w.Spec.Reservation = &kueue.WorkloadReservation{TTLSecondsAfterAdmitted: ttlSeconds}
+	return w
+}
+
+// AdmissionDeadline sets the admission deadline.
+func (w *WorkloadWrapper) AdmissionDeadline(t time.Time) *WorkloadWrapper {
+	w.Spec.AdmissionDeadline = &metav1.Time{Time: t}
+	return w
+}
+
 func (w *WorkloadWrapper) Priority(priority int32) *WorkloadWrapper {
 	w.Spec.Priority = &priority
 	return w
@@ -236,11 +386,65 @@ func (w *WorkloadWrapper) NodeSelector(kv map[string]string) *WorkloadWrapper {
 	return w
 }
 
+func (w *WorkloadWrapper) Annotation(k, v string) *WorkloadWrapper {
+	if w.Annotations == nil {
+		w.Annotations = make(map[string]string)
+	}
+	w.Annotations[k] = v
+	return w
+}
+
+// ControllerReference sets the controller owner of the workload.
+func (w *WorkloadWrapper) ControllerReference(gvk schema.GroupVersionKind, name, uid string) *WorkloadWrapper {
+	w.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       name,
+		UID:        types.UID(uid),
+		Controller: pointer.Bool(true),
+	}}
+	return w
+}
+
 func (w *WorkloadWrapper) Condition(condition metav1.Condition) *WorkloadWrapper {
 	apimeta.SetStatusCondition(&w.Status.Conditions, condition)
 	return w
 }
 
+// ReclaimablePods sets the reclaimable pods in the status.
+func (w *WorkloadWrapper) ReclaimablePods(rps ...kueue.ReclaimablePod) *WorkloadWrapper {
+	w.Status.ReclaimablePods = rps
+	return w
+}
+
+func (w *WorkloadWrapper) ResourceUsage(usage ...kueue.PodSetResourceUsage) *WorkloadWrapper {
+	w.Status.ResourceUsage = usage
+	return w
+}
+
+func (w *WorkloadWrapper) PodSetResizes(resizes ...kueue.PodSetResize) *WorkloadWrapper {
+	w.Spec.PodSetResizes = resizes
+	return w
+}
+
+// AdmissionCheck adds the state of an admission check to the status.
+func (w *WorkloadWrapper) AdmissionCheck(name string, state kueue.CheckState) *WorkloadWrapper {
+	w.Status.AdmissionChecks = append(w.Status.AdmissionChecks, kueue.AdmissionCheckState{
+		Name:  name,
+		State: state,
+	})
+	return w
+}
+
+// ExcludedFlavor excludes a flavor for the given number of retries.
+func (w *WorkloadWrapper) ExcludedFlavor(name string, retries int32) *WorkloadWrapper {
+	w.Status.ExcludedFlavors = append(w.Status.ExcludedFlavors, kueue.ExcludedFlavor{
+		Name:             name,
+		RemainingRetries: retries,
+	})
+	return w
+}
+
 // AdmissionWrapper wraps an Admission
 type AdmissionWrapper struct{ kueue.Admission }
 
@@ -279,6 +483,24 @@ func (w *AdmissionWrapper) Flavor(r corev1.ResourceName, f string) *AdmissionWra
 	return w
 }
 
+// Count sets the number of admitted pods for the first podSet.
+func (w *AdmissionWrapper) Count(c int32) *AdmissionWrapper {
+	w.PodSetFlavors[0].Count = &c
+	return w
+}
+
+// TopologyDomain sets the topology domain of the first podSet.
+func (w *AdmissionWrapper) TopologyDomain(domain map[string]string) *AdmissionWrapper {
+	w.PodSetFlavors[0].TopologyDomain = domain
+	return w
+}
+
+// AdmissionChecks sets the admission checks that the workload has to pass.
+func (w *AdmissionWrapper) AdmissionChecks(checks ...string) *AdmissionWrapper {
+	w.Admission.AdmissionChecks = checks
+	return w
+}
+
 // LocalQueueWrapper wraps a Queue.
 type LocalQueueWrapper struct{ kueue.LocalQueue }
 
@@ -303,6 +525,12 @@ func (q *LocalQueueWrapper) ClusterQueue(c string) *LocalQueueWrapper {
 	return q
 }
 
+// Weight sets the weight of the queue.
+func (q *LocalQueueWrapper) Weight(w int32) *LocalQueueWrapper {
+	q.Spec.Weight = w
+	return q
+}
+
 // PendingWorkloads updates the pendingWorkloads in status.
 func (q *LocalQueueWrapper) PendingWorkloads(n int32) *LocalQueueWrapper {
 	q.Status.PendingWorkloads = n
@@ -337,9 +565,10 @@ func (c *ClusterQueueWrapper) Cohort(cohort string) *ClusterQueueWrapper {
 	return c
 }
 
-// Resource adds a resource with flavors.
-func (c *ClusterQueueWrapper) Resource(r *kueue.Resource) *ClusterQueueWrapper {
-	c.Spec.Resources = append(c.Spec.Resources, *r)
+// Resource adds a resource with flavors to the resource group with the same
+// flavors, or to a new resource group.
+func (c *ClusterQueueWrapper) Resource(r *ResourceQuotas) *ClusterQueueWrapper {
+	c.Spec.ResourceGroups = kueue.AppendResource(c.Spec.ResourceGroups, r)
 	return c
 }
 
@@ -361,33 +590,87 @@ func (c *ClusterQueueWrapper) Preemption(p kueue.ClusterQueuePreemption) *Cluste
 	return c
 }
 
-// ResourceWrapper wraps a resource.
-type ResourceWrapper struct{ kueue.Resource }
+// AdmissionChecks sets the admission checks.
+func (c *ClusterQueueWrapper) AdmissionChecks(checks ...string) *ClusterQueueWrapper {
+	c.Spec.AdmissionChecks = checks
+	return c
+}
 
-// MakeResource creates a wrapper for a resource.
+// LocalQueueSharing sets the sharing of the admission attempts between the
+// LocalQueues.
+func (c *ClusterQueueWrapper) LocalQueueSharing(s kueue.LocalQueueSharing) *ClusterQueueWrapper {
+	c.Spec.LocalQueueSharing = &s
+	return c
+}
+
+// StopPolicy sets the stop policy.
+func (c *ClusterQueueWrapper) StopPolicy(p kueue.StopPolicy) *ClusterQueueWrapper {
+	c.Spec.StopPolicy = &p
+	return c
+}
+
+// OverQuotaPolicy sets the over quota policy.
+func (c *ClusterQueueWrapper) OverQuotaPolicy(p kueue.OverQuotaPolicy) *ClusterQueueWrapper {
+	c.Spec.OverQuotaPolicy = &p
+	return c
+}
+
+// PriorityAging sets the priority aging policy.
+func (c *ClusterQueueWrapper) PriorityAging(a kueue.PriorityAging) *ClusterQueueWrapper {
+	c.Spec.PriorityAging = &a
+	return c
+}
+
+// QuotaSchedule adds a quota schedule.
+func (c *ClusterQueueWrapper) QuotaSchedule(s kueue.QuotaSchedule) *ClusterQueueWrapper {
+	c.Spec.QuotaSchedules = append(c.Spec.QuotaSchedules, s)
+	return c
+}
+
+// ResourceQuotas holds the quotas of a resource in each of its flavors.
+type ResourceQuotas = kueue.Resource
+
+// FlavorQuota holds the quota of a resource in a flavor.
+type FlavorQuota = kueue.Flavor
+
+// ResourceGroups arranges the resources in resource groups, so that the
+// resources with the same flavors, in the same order, are covered by the same
+// group.
+func ResourceGroups(resources ...*ResourceQuotas) []kueue.ResourceGroup {
+	var groups []kueue.ResourceGroup
+	for _, r := range resources {
+		groups = kueue.AppendResource(groups, r)
+	}
+	return groups
+}
+
+// ResourceWrapper wraps the quotas of a resource.
+type ResourceWrapper struct{ ResourceQuotas }
+
+// MakeResource creates a wrapper for the quotas of a resource.
 func MakeResource(name corev1.ResourceName) *ResourceWrapper {
-	return &ResourceWrapper{kueue.Resource{
+	return &ResourceWrapper{ResourceQuotas{
 		Name: name,
 	}}
 }
 
-// Obj returns the inner resource.
-func (r *ResourceWrapper) Obj() *kueue.Resource {
-	return &r.Resource
+// Obj returns the inner resource quotas.
+func (r *ResourceWrapper) Obj() *ResourceQuotas {
+	return &r.ResourceQuotas
 }
 
 // Flavor appends a flavor.
-func (r *ResourceWrapper) Flavor(f *kueue.Flavor) *ResourceWrapper {
+func (r *ResourceWrapper) Flavor(f *FlavorQuota) *ResourceWrapper {
 	r.Flavors = append(r.Flavors, *f)
 	return r
 }
 
-// FlavorWrapper wraps a resource flavor.
-type FlavorWrapper struct{ kueue.Flavor }
+// FlavorWrapper wraps the quota of a resource in a flavor.
+type FlavorWrapper struct{ FlavorQuota }
 
-// MakeFlavor creates a wrapper for a resource flavor.
+// MakeFlavor creates a wrapper for the quota of a resource in a flavor.
 func MakeFlavor(rf, min string) *FlavorWrapper {
-	return &FlavorWrapper{kueue.Flavor{
+	return &FlavorWrapper{FlavorQuota{
 		Name: kueue.ResourceFlavorReference(rf),
 		Quota: kueue.Quota{
 			Min: resource.MustParse(min),
@@ -395,9 +678,9 @@ func MakeFlavor(rf, min string) *FlavorWrapper {
 	}}
 }
 
-// Obj returns the inner flavor.
-func (f *FlavorWrapper) Obj() *kueue.Flavor {
-	return &f.Flavor
+// Obj returns the inner flavor quota.
+func (f *FlavorWrapper) Obj() *FlavorQuota {
+	return &f.FlavorQuota
 }
 
 // Max updates the flavor max.
@@ -406,6 +689,30 @@ func (f *FlavorWrapper) Max(c string) *FlavorWrapper {
 	return f
 }
 
+// MinPercentage updates the flavor minPercentage.
+func (f *FlavorWrapper) MinPercentage(p int32) *FlavorWrapper {
+	f.Quota.MinPercentage = pointer.Int32(p)
+	return f
+}
+
+// BorrowingLimit updates the flavor borrowingLimit.
+func (f *FlavorWrapper) BorrowingLimit(c string) *FlavorWrapper {
+	f.Quota.BorrowingLimit = pointer.Quantity(resource.MustParse(c))
+	return f
+}
+
+// LendingLimit updates the flavor lendingLimit.
+func (f *FlavorWrapper) LendingLimit(c string) *FlavorWrapper {
+	f.Quota.LendingLimit = pointer.Quantity(resource.MustParse(c))
+	return f
+}
+
+// MaxPerNamespace updates the flavor maxPerNamespace.
+func (f *FlavorWrapper) MaxPerNamespace(c string) *FlavorWrapper {
+	f.Quota.MaxPerNamespace = pointer.Quantity(resource.MustParse(c))
+	return f
+}
+
 // ResourceFlavorWrapper wraps a ResourceFlavor.
 type ResourceFlavorWrapper struct{ kueue.ResourceFlavor }
 
@@ -444,6 +751,93 @@ func (rf *ResourceFlavorWrapper) Taint(t corev1.Taint) *ResourceFlavorWrapper {
 	return rf
 }
 
+// Toleration adds a toleration to the ResourceFlavor.
+func (rf *ResourceFlavorWrapper) Toleration(t corev1.Toleration) *ResourceFlavorWrapper {
+	rf.Tolerations = append(rf.Tolerations, t)
+	return rf
+}
+
+// Cost sets the cost annotation of the ResourceFlavor.
+func (rf *ResourceFlavorWrapper) Cost(cost string) *ResourceFlavorWrapper {
+	metav1.SetMetaDataAnnotation(&rf.ObjectMeta, kueue.ResourceFlavorCostAnnotation, cost)
+	return rf
+}
+
+// Unreliable marks the ResourceFlavor as unreliable, excluding it for the
+// given number of retries after its nodes are reclaimed.
+func (rf *ResourceFlavorWrapper) Unreliable(excludeForRetries int32) *ResourceFlavorWrapper {
+	rf.ResourceFlavor.Unreliable = &kueue.UnreliableNodes{ExcludeForRetries: excludeForRetries}
+	return rf
+}
+
+// TopologyName sets the topology of the ResourceFlavor.
+func (rf *ResourceFlavorWrapper) TopologyName(name string) *ResourceFlavorWrapper {
+	rf.ResourceFlavor.TopologyName = &name
+	return rf
+}
+
+// TopologyWrapper wraps a Topology.
+type TopologyWrapper struct{ kueue.Topology }
+
+// MakeTopology creates a wrapper for a Topology with the given levels of
+// node labels, from the highest to the lowest.
+func MakeTopology(name string, nodeLabels ...string) *TopologyWrapper {
+	t := &TopologyWrapper{kueue.Topology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}}
+	for _, l := range nodeLabels {
+		t.Spec.Levels = append(t.Spec.Levels, kueue.TopologyLevel{NodeLabel: l})
+	}
+	return t
+}
+
+// Obj returns the inner Topology.
+func (t *TopologyWrapper) Obj() *kueue.Topology {
+	return &t.Topology
+}
+
+// AdmissionCheckWrapper wraps an AdmissionCheck.
+type AdmissionCheckWrapper struct{ kueue.AdmissionCheck }
+
+// MakeAdmissionCheck creates a wrapper for an AdmissionCheck handled by the
+// given controller.
+func MakeAdmissionCheck(name, controllerName string) *AdmissionCheckWrapper {
+	return &AdmissionCheckWrapper{kueue.AdmissionCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kueue.AdmissionCheckSpec{
+			ControllerName: controllerName,
+		},
+	}}
+}
+
+// Obj returns the inner AdmissionCheck.
+func (ac *AdmissionCheckWrapper) Obj() *kueue.AdmissionCheck {
+	return &ac.AdmissionCheck
+}
+
+// Active sets the Active condition.
+func (ac *AdmissionCheckWrapper) Active(status metav1.ConditionStatus) *AdmissionCheckWrapper {
+	apimeta.SetStatusCondition(&ac.Status.Conditions, metav1.Condition{
+		Type:   kueue.AdmissionCheckActive,
+		Status: status,
+		Reason: "ByTest",
+	})
+	return ac
+}
+
+// ProvisioningRequest sets the parameters of the ProvisioningRequests.
+func (ac *AdmissionCheckWrapper) ProvisioningRequest(className string, parameters map[string]string) *AdmissionCheckWrapper {
+	ac.Spec.ProvisioningRequest = &kueue.ProvisioningRequestParameters{
+		ProvisioningClassName: className,
+		Parameters:            parameters,
+	}
+	return ac
+}
+
 // RuntimeClassWrapper wraps a RuntimeClass.
 type RuntimeClassWrapper struct{ nodev1.RuntimeClass }
 
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/queue"
+)
+
+// ClusterQueueREST is the storage of the clusterqueues resource, which only
+// exists to hold the pendingworkloads subresource.
+type ClusterQueueREST struct{}
+
+var _ rest.Storage = &ClusterQueueREST{}
+var _ rest.Scoper = &ClusterQueueREST{}
+
+func (r *ClusterQueueREST) New() runtime.Object {
+	return &v1alpha1.ClusterQueue{}
+}
+
+func (r *ClusterQueueREST) Destroy() {}
+
+func (r *ClusterQueueREST) NamespaceScoped() bool {
+	return false
+}
+
+// PendingWorkloadsInCqREST serves the pending workloads of a ClusterQueue.
+type PendingWorkloadsInCqREST struct {
+	queueMgr *queue.Manager
+}
+
+var _ rest.Storage = &PendingWorkloadsInCqREST{}
+var _ rest.GetterWithOptions = &PendingWorkloadsInCqREST{}
+var _ rest.Scoper = &PendingWorkloadsInCqREST{}
+
+func NewPendingWorkloadsInCqREST(queueMgr *queue.Manager) *PendingWorkloadsInCqREST {
+	return &PendingWorkloadsInCqREST{queueMgr: queueMgr}
+}
+
+func (r *PendingWorkloadsInCqREST) New() runtime.Object {
+	return &v1alpha1.PendingWorkloadsSummary{}
+}
+
+func (r *PendingWorkloadsInCqREST) Destroy() {}
+
+func (r *PendingWorkloadsInCqREST) NamespaceScoped() bool {
+	return false
+}
+
+func (r *PendingWorkloadsInCqREST) NewGetOptions() (runtime.Object, bool, string) {
+	return &v1alpha1.PendingWorkloadOptions{}, false, ""
+}
+
+// Get returns a page of the pending workloads of the ClusterQueue, in the
+// order in which they are considered for admission.
+func (r *PendingWorkloadsInCqREST) Get(_ context.Context, name string, options runtime.Object) (runtime.Object, error) {
+	opts, err := pendingWorkloadOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	infos, found := r.queueMgr.PendingWorkloadsInfo(name)
+	if !found {
+		return nil, errors.NewNotFound(v1alpha1.Resource("clusterqueue"), name)
+	}
+	return &v1alpha1.PendingWorkloadsSummary{
+		Items: pendingWorkloads(infos, nil, opts),
+	}, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/queue"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// LocalQueueREST is the storage of the localqueues resource, which only
+// exists to hold the pendingworkloads subresource.
+type LocalQueueREST struct{}
+
+var _ rest.Storage = &LocalQueueREST{}
+var _ rest.Scoper = &LocalQueueREST{}
+
+func (r *LocalQueueREST) New() runtime.Object {
+	return &v1alpha1.LocalQueue{}
+}
+
+func (r *LocalQueueREST) Destroy() {}
+
+func (r *LocalQueueREST) NamespaceScoped() bool {
+	return true
+}
+
+// PendingWorkloadsInLqREST serves the pending workloads of a LocalQueue.
+type PendingWorkloadsInLqREST struct {
+	queueMgr *queue.Manager
+}
+
+var _ rest.Storage = &PendingWorkloadsInLqREST{}
+var _ rest.GetterWithOptions = &PendingWorkloadsInLqREST{}
+var _ rest.Scoper = &PendingWorkloadsInLqREST{}
+
+func NewPendingWorkloadsInLqREST(queueMgr *queue.Manager) *PendingWorkloadsInLqREST {
+	return &PendingWorkloadsInLqREST{queueMgr: queueMgr}
+}
+
+func (r *PendingWorkloadsInLqREST) New() runtime.Object {
+	return &v1alpha1.PendingWorkloadsSummary{}
+}
+
+func (r *PendingWorkloadsInLqREST) Destroy() {}
+
+func (r *PendingWorkloadsInLqREST) NamespaceScoped() bool {
+	return true
+}
+
+func (r *PendingWorkloadsInLqREST) NewGetOptions() (runtime.Object, bool, string) {
+	return &v1alpha1.PendingWorkloadOptions{}, false, ""
+}
+
+// Get returns a page of the pending workloads of the LocalQueue, in the
+// order in which they are considered for admission in its ClusterQueue.
+func (r *PendingWorkloadsInLqREST) Get(ctx context.Context, name string, options runtime.Object) (runtime.Object, error) {
+	opts, err := pendingWorkloadOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	namespace := genericapirequest.NamespaceValue(ctx)
+	lqKey := fmt.Sprintf("%s/%s", namespace, name)
+	cqName, found := r.queueMgr.ClusterQueueFromLocalQueue(lqKey)
+	if !found {
+		return nil, errors.NewNotFound(v1alpha1.Resource("localqueue"), name)
+	}
+	// The LocalQueue may point to a ClusterQueue that doesn't exist yet, in
+	// which case it has no pending workloads to show.
+	infos, _ := r.queueMgr.PendingWorkloadsInfo(cqName)
+	inLocalQueue := func(wl *kueue.Workload) bool {
+		return workload.QueueKey(wl) == lqKey
+	}
+	return &v1alpha1.PendingWorkloadsSummary{
+		Items: pendingWorkloads(infos, inLocalQueue, opts),
+	}, nil
+}
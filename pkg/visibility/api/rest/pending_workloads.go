@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+func pendingWorkloadOptions(options runtime.Object) (*v1alpha1.PendingWorkloadOptions, error) {
+	opts, ok := options.(*v1alpha1.PendingWorkloadOptions)
+	if !ok {
+		return nil, fmt.Errorf("invalid options object: %#v", options)
+	}
+	if opts.Offset < 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("offset must be non-negative, got %d", opts.Offset))
+	}
+	if opts.Limit < 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("limit must be non-negative, got %d", opts.Limit))
+	}
+	return opts, nil
+}
+
+// pendingWorkloads returns the page, defined by the offset and limit of the
+// options, of the pending workloads of a ClusterQueue that are accepted by the
+// filter. The infos are all the pending workloads of the ClusterQueue, in the
+// order in which they are considered for admission. A nil filter accepts all
+// the workloads.
+func pendingWorkloads(infos []*workload.Info, filter func(*kueue.Workload) bool, opts *v1alpha1.PendingWorkloadOptions) []v1alpha1.PendingWorkload {
+	items := make([]v1alpha1.PendingWorkload, 0)
+	lqPositions := make(map[string]int32)
+	var index int64
+	for cqPosition, info := range infos {
+		if int64(len(items)) >= opts.Limit {
+			break
+		}
+		wl := info.Obj
+		lqKey := workload.QueueKey(wl)
+		lqPosition := lqPositions[lqKey]
+		lqPositions[lqKey]++
+		if filter != nil && !filter(wl) {
+			continue
+		}
+		if index >= opts.Offset {
+			items = append(items, v1alpha1.PendingWorkload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              wl.Name,
+					Namespace:         wl.Namespace,
+					CreationTimestamp: wl.CreationTimestamp,
+				},
+				Priority:               priority.Priority(wl),
+				LocalQueueName:         wl.Spec.QueueName,
+				PositionInClusterQueue: int32(cqPosition),
+				PositionInLocalQueue:   lqPosition,
+			})
+		}
+		index++
+	}
+	return items
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/queue"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+const testNamespace = "ns"
+
+func newTestManager(t *testing.T) *queue.Manager {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := kueue.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed adding kueue scheme: %v", err)
+	}
+	ctx := context.Background()
+	manager := queue.NewManager(fake.NewClientBuilder().WithScheme(scheme).Build(), nil)
+	cq := utiltesting.MakeClusterQueue("cq").QueueingStrategy(kueue.StrictFIFO).
+		LocalQueueSharing(kueue.LocalQueueSharingWeighted).
+		Obj()
+	if err := manager.AddClusterQueue(ctx, cq); err != nil {
+		t.Fatalf("Failed adding clusterQueue: %v", err)
+	}
+	for _, lq := range []*kueue.LocalQueue{
+		utiltesting.MakeLocalQueue("lq-a", testNamespace).ClusterQueue("cq").Obj(),
+		utiltesting.MakeLocalQueue("lq-b", testNamespace).ClusterQueue("cq").Obj(),
+	} {
+		if err := manager.AddLocalQueue(ctx, lq); err != nil {
+			t.Fatalf("Failed adding localQueue: %v", err)
+		}
+	}
+	now := time.Now()
+	for _, wl := range []*kueue.Workload{
+		utiltesting.MakeWorkload("a1", testNamespace).Queue("lq-a").Priority(10).Creation(now).Obj(),
+		utiltesting.MakeWorkload("a2", testNamespace).Queue("lq-a").Creation(now.Add(time.Second)).Obj(),
+		utiltesting.MakeWorkload("a3", testNamespace).Queue("lq-a").Creation(now.Add(2 * time.Second)).Obj(),
+		utiltesting.MakeWorkload("b1", testNamespace).Queue("lq-b").Creation(now.Add(3 * time.Second)).Obj(),
+	} {
+		manager.AddOrUpdateWorkload(wl)
+	}
+	return manager
+}
+
+func pendingWorkload(name, lq string, priority, cqPosition, lqPosition int32) v1alpha1.PendingWorkload {
+	return v1alpha1.PendingWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Priority:               priority,
+		LocalQueueName:         lq,
+		PositionInClusterQueue: cqPosition,
+		PositionInLocalQueue:   lqPosition,
+	}
+}
+
+func TestPendingWorkloadsInCq(t *testing.T) {
+	cases := map[string]struct {
+		cqName    string
+		opts      *v1alpha1.PendingWorkloadOptions
+		wantItems []v1alpha1.PendingWorkload
+		wantErrFn func(error) bool
+	}{
+		"all the pending workloads": {
+			cqName: "cq",
+			opts:   &v1alpha1.PendingWorkloadOptions{Limit: v1alpha1.DefaultPendingWorkloadsLimit},
+			wantItems: []v1alpha1.PendingWorkload{
+				pendingWorkload("a1", "lq-a", 10, 0, 0),
+				pendingWorkload("b1", "lq-b", 0, 1, 0),
+				pendingWorkload("a2", "lq-a", 0, 2, 1),
+				pendingWorkload("a3", "lq-a", 0, 3, 2),
+			},
+		},
+		"offset and limit": {
+			cqName: "cq",
+			opts:   &v1alpha1.PendingWorkloadOptions{Offset: 1, Limit: 2},
+			wantItems: []v1alpha1.PendingWorkload{
+				pendingWorkload("b1", "lq-b", 0, 1, 0),
+				pendingWorkload("a2", "lq-a", 0, 2, 1),
+			},
+		},
+		"offset past the end": {
+			cqName:    "cq",
+			opts:      &v1alpha1.PendingWorkloadOptions{Offset: 10, Limit: 2},
+			wantItems: []v1alpha1.PendingWorkload{},
+		},
+		"clusterQueue not found": {
+			cqName:    "missing",
+			opts:      &v1alpha1.PendingWorkloadOptions{Limit: 2},
+			wantErrFn: errors.IsNotFound,
+		},
+		"negative offset": {
+			cqName:    "cq",
+			opts:      &v1alpha1.PendingWorkloadOptions{Offset: -1, Limit: 2},
+			wantErrFn: errors.IsBadRequest,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewPendingWorkloadsInCqREST(newTestManager(t))
+			got, err := r.Get(context.Background(), tc.cqName, tc.opts)
+			if tc.wantErrFn != nil {
+				if !tc.wantErrFn(err) {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantItems, got.(*v1alpha1.PendingWorkloadsSummary).Items,
+				cmpopts.IgnoreFields(metav1.ObjectMeta{}, "CreationTimestamp")); diff != "" {
+				t.Errorf("Unexpected pending workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPendingWorkloadsInLq(t *testing.T) {
+	cases := map[string]struct {
+		namespace string
+		lqName    string
+		opts      *v1alpha1.PendingWorkloadOptions
+		wantItems []v1alpha1.PendingWorkload
+		wantErrFn func(error) bool
+	}{
+		"all the pending workloads": {
+			namespace: testNamespace,
+			lqName:    "lq-a",
+			opts:      &v1alpha1.PendingWorkloadOptions{Limit: v1alpha1.DefaultPendingWorkloadsLimit},
+			wantItems: []v1alpha1.PendingWorkload{
+				pendingWorkload("a1", "lq-a", 10, 0, 0),
+				pendingWorkload("a2", "lq-a", 0, 2, 1),
+				pendingWorkload("a3", "lq-a", 0, 3, 2),
+			},
+		},
+		"offset and limit": {
+			namespace: testNamespace,
+			lqName:    "lq-a",
+			opts:      &v1alpha1.PendingWorkloadOptions{Offset: 1, Limit: 1},
+			wantItems: []v1alpha1.PendingWorkload{
+				pendingWorkload("a2", "lq-a", 0, 2, 1),
+			},
+		},
+		"localQueue in another namespace": {
+			namespace: "other",
+			lqName:    "lq-a",
+			opts:      &v1alpha1.PendingWorkloadOptions{Limit: 2},
+			wantErrFn: errors.IsNotFound,
+		},
+		"negative limit": {
+			namespace: testNamespace,
+			lqName:    "lq-a",
+			opts:      &v1alpha1.PendingWorkloadOptions{Limit: -1},
+			wantErrFn: errors.IsBadRequest,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewPendingWorkloadsInLqREST(newTestManager(t))
+			ctx := genericapirequest.WithNamespace(context.Background(), tc.namespace)
+			got, err := r.Get(ctx, tc.lqName, tc.opts)
+			if tc.wantErrFn != nil {
+				if !tc.wantErrFn(err) {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantItems, got.(*v1alpha1.PendingWorkloadsSummary).Items,
+				cmpopts.IgnoreFields(metav1.ObjectMeta{}, "CreationTimestamp")); diff != "" {
+				t.Errorf("Unexpected pending workloads (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"sigs.k8s.io/kueue/pkg/queue"
+)
+
+// NewStorage returns the storage of the resources of the visibility API,
+// keyed by their paths.
+func NewStorage(queueMgr *queue.Manager, explainer Explainer) map[string]rest.Storage {
+	return map[string]rest.Storage{
+		"clusterqueues":                  &ClusterQueueREST{},
+		"clusterqueues/pendingworkloads": NewPendingWorkloadsInCqREST(queueMgr),
+		"localqueues":                    &LocalQueueREST{},
+		"localqueues/pendingworkloads":   NewPendingWorkloadsInLqREST(queueMgr),
+		"workloads":                      &WorkloadREST{},
+		"workloads/explanation":          NewWorkloadExplanationREST(explainer),
+	}
+}
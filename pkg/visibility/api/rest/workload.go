@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/scheduler"
+	"sigs.k8s.io/kueue/pkg/util/pointer"
+	"sigs.k8s.io/kueue/pkg/util/priority"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+// Explainer evaluates pending workloads for admission on request.
+type Explainer interface {
+	Explain(ctx context.Context, key types.NamespacedName) (*scheduler.Explanation, error)
+}
+
+// WorkloadREST is the storage of the workloads resource, which only exists to
+// hold the explanation subresource.
+type WorkloadREST struct{}
+
+var _ rest.Storage = &WorkloadREST{}
+var _ rest.Scoper = &WorkloadREST{}
+
+func (r *WorkloadREST) New() runtime.Object {
+	return &v1alpha1.Workload{}
+}
+
+func (r *WorkloadREST) Destroy() {}
+
+func (r *WorkloadREST) NamespaceScoped() bool {
+	return true
+}
+
+// WorkloadExplanationREST serves why a pending workload is not admitted.
+type WorkloadExplanationREST struct {
+	explainer Explainer
+}
+
+var _ rest.Storage = &WorkloadExplanationREST{}
+var _ rest.Getter = &WorkloadExplanationREST{}
+var _ rest.Scoper = &WorkloadExplanationREST{}
+
+func NewWorkloadExplanationREST(explainer Explainer) *WorkloadExplanationREST {
+	return &WorkloadExplanationREST{explainer: explainer}
+}
+
+func (r *WorkloadExplanationREST) New() runtime.Object {
+	return &v1alpha1.WorkloadExplanation{}
+}
+
+func (r *WorkloadExplanationREST) Destroy() {}
+
+func (r *WorkloadExplanationREST) NamespaceScoped() bool {
+	return true
+}
+
+// Get evaluates the workload for admission against the current usage of the
+// quota, without waiting for the next scheduling cycle.
+func (r *WorkloadExplanationREST) Get(ctx context.Context, name string, _ *metav1.GetOptions) (runtime.Object, error) {
+	namespace := genericapirequest.NamespaceValue(ctx)
+	explanation, err := r.explainer.Explain(ctx, types.NamespacedName{Namespace: namespace, Name: name})
+	if apierrors.IsNotFound(err) {
+		return nil, apierrors.NewNotFound(v1alpha1.Resource("workload"), name)
+	}
+	if errors.Is(err, scheduler.ErrWorkloadAdmitted) {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("workload %s is already admitted", name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return workloadExplanation(namespace, name, explanation), nil
+}
+
+func workloadExplanation(namespace, name string, e *scheduler.Explanation) *v1alpha1.WorkloadExplanation {
+	out := &v1alpha1.WorkloadExplanation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		ClusterQueue: e.ClusterQueue,
+		Mode:         e.Assignment.RepresentativeMode().String(),
+		Message:      e.Message,
+	}
+	for _, ps := range e.Assignment.PodSets {
+		psExplanation := v1alpha1.PodSetExplanation{
+			Name:  ps.Name,
+			Count: ps.Count,
+		}
+		for res, flv := range ps.Flavors {
+			psExplanation.Flavors = append(psExplanation.Flavors, v1alpha1.FlavorAssignment{
+				Resource: res,
+				Flavor:   flv.Name,
+				Mode:     flv.Mode.String(),
+			})
+		}
+		sort.Slice(psExplanation.Flavors, func(i, j int) bool {
+			return psExplanation.Flavors[i].Resource < psExplanation.Flavors[j].Resource
+		})
+		for _, r := range ps.Status.Reasons() {
+			reason := v1alpha1.FlavorReason{
+				Type:     string(r.Type),
+				Resource: r.Resource,
+				Flavor:   r.Flavor,
+				Message:  r.Message,
+			}
+			if r.Missing > 0 {
+				reason.Missing = pointer.Quantity(workload.ResourceQuantity(r.Resource, r.Missing))
+			}
+			psExplanation.Reasons = append(psExplanation.Reasons, reason)
+		}
+		out.PodSets = append(out.PodSets, psExplanation)
+	}
+	for _, target := range e.PreemptionTargets {
+		out.PreemptionTargets = append(out.PreemptionTargets, v1alpha1.PreemptionTarget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      target.Obj.Name,
+				Namespace: target.Obj.Namespace,
+			},
+			ClusterQueue: target.ClusterQueue,
+			Priority:     priority.Priority(target.Obj),
+		})
+	}
+	return out
+}
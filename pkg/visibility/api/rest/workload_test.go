@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/apis/visibility/v1alpha1"
+	"sigs.k8s.io/kueue/pkg/scheduler"
+	"sigs.k8s.io/kueue/pkg/scheduler/flavorassigner"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+	"sigs.k8s.io/kueue/pkg/workload"
+)
+
+type fakeExplainer map[types.NamespacedName]*scheduler.Explanation
+
+func (e fakeExplainer) Explain(_ context.Context, key types.NamespacedName) (*scheduler.Explanation, error) {
+	if key.Name == "admitted" {
+		return nil, scheduler.ErrWorkloadAdmitted
+	}
+	explanation, found := e[key]
+	if !found {
+		return nil, errors.NewNotFound(kueue.GroupVersion.WithResource("workloads").GroupResource(), key.Name)
+	}
+	return explanation, nil
+}
+
+func TestWorkloadExplanation(t *testing.T) {
+	low := workload.NewInfo(utiltesting.MakeWorkload("low", testNamespace).Priority(-1).Obj())
+	low.ClusterQueue = "cq"
+	explainer := fakeExplainer{
+		{Namespace: testNamespace, Name: "preempting"}: {
+			ClusterQueue: "cq",
+			Assignment: flavorassigner.Assignment{
+				PodSets: []flavorassigner.PodSetAssignment{{
+					Name: "main",
+					Flavors: flavorassigner.ResourceAssignment{
+						corev1.ResourceMemory: {Name: "default", Mode: flavorassigner.Fit},
+						corev1.ResourceCPU:    {Name: "default", Mode: flavorassigner.Preempt},
+					},
+					Status: &flavorassigner.Status{},
+					Count:  3,
+				}},
+			},
+			Message:           "couldn't assign flavors to pod set main: insufficient unused quota for cpu flavor default, 1 more needed",
+			PreemptionTargets: []*workload.Info{low},
+		},
+		{Namespace: testNamespace, Name: "orphan"}: {
+			Message: "LocalQueue lq doesn't exist",
+		},
+	}
+	cases := map[string]struct {
+		name      string
+		want      *v1alpha1.WorkloadExplanation
+		wantErrFn func(error) bool
+	}{
+		"requires preemption": {
+			name: "preempting",
+			want: &v1alpha1.WorkloadExplanation{
+				ObjectMeta:   metav1.ObjectMeta{Name: "preempting", Namespace: testNamespace},
+				ClusterQueue: "cq",
+				Mode:         "Preempt",
+				Message:      "couldn't assign flavors to pod set main: insufficient unused quota for cpu flavor default, 1 more needed",
+				PodSets: []v1alpha1.PodSetExplanation{{
+					Name:  "main",
+					Count: 3,
+					Flavors: []v1alpha1.FlavorAssignment{
+						{Resource: corev1.ResourceCPU, Flavor: "default", Mode: "Preempt"},
+						{Resource: corev1.ResourceMemory, Flavor: "default", Mode: "Fit"},
+					},
+				}},
+				PreemptionTargets: []v1alpha1.PreemptionTarget{{
+					ObjectMeta:   metav1.ObjectMeta{Name: "low", Namespace: testNamespace},
+					ClusterQueue: "cq",
+					Priority:     -1,
+				}},
+			},
+		},
+		"missing LocalQueue": {
+			name: "orphan",
+			want: &v1alpha1.WorkloadExplanation{
+				ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: testNamespace},
+				Mode:       "NoFit",
+				Message:    "LocalQueue lq doesn't exist",
+			},
+		},
+		"admitted": {
+			name:      "admitted",
+			wantErrFn: errors.IsBadRequest,
+		},
+		"not found": {
+			name:      "missing",
+			wantErrFn: errors.IsNotFound,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewWorkloadExplanationREST(explainer)
+			ctx := genericapirequest.WithNamespace(context.Background(), testNamespace)
+			got, err := r.Get(ctx, tc.name, &metav1.GetOptions{})
+			if tc.wantErrFn != nil {
+				if !tc.wantErrFn(err) {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected explanation (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
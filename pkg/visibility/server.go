@@ -0,0 +1,106 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package visibility
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+	openapinamer "k8s.io/apiserver/pkg/endpoints/openapi"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"sigs.k8s.io/kueue/pkg/queue"
+	"sigs.k8s.io/kueue/pkg/version"
+	"sigs.k8s.io/kueue/pkg/visibility/api"
+	"sigs.k8s.io/kueue/pkg/visibility/api/rest"
+	"sigs.k8s.io/kueue/pkg/visibility/openapi"
+)
+
+const serverName = "kueue-visibility-server"
+
+// The API priority and fairness of the server needs to watch its configuration.
+//+kubebuilder:rbac:groups=flowcontrol.apiserver.k8s.io,resources=flowschemas,verbs=list;watch
+//+kubebuilder:rbac:groups=flowcontrol.apiserver.k8s.io,resources=flowschemas/status,verbs=patch
+//+kubebuilder:rbac:groups=flowcontrol.apiserver.k8s.io,resources=prioritylevelconfigurations,verbs=list;watch
+
+// Server is an aggregated API server that serves the visibility API from
+// the in-memory state of the queue manager and the scheduler, so that the
+// pending workloads are always up to date and don't need to be stored in etcd.
+type Server struct {
+	queueMgr  *queue.Manager
+	explainer rest.Explainer
+	port      int
+}
+
+var _ manager.Runnable = &Server{}
+
+func NewServer(queueMgr *queue.Manager, explainer rest.Explainer, port int) *Server {
+	return &Server{
+		queueMgr:  queueMgr,
+		explainer: explainer,
+		port:      port,
+	}
+}
+
+// Start runs the server until the context is done. The server uses the
+// delegated authentication and authorization of the kube-apiserver, and a
+// self-signed certificate kept in memory.
+func (s *Server) Start(ctx context.Context) error {
+	config, err := s.newConfig()
+	if err != nil {
+		return err
+	}
+	server, err := config.Complete().New(serverName, genericapiserver.NewEmptyDelegate())
+	if err != nil {
+		return fmt.Errorf("creating the visibility server: %w", err)
+	}
+	if err := api.Install(server, s.queueMgr, s.explainer); err != nil {
+		return fmt.Errorf("installing the visibility API: %w", err)
+	}
+	return server.PrepareRun().Run(ctx.Done())
+}
+
+func (s *Server) newConfig() (*genericapiserver.RecommendedConfig, error) {
+	o := genericoptions.NewRecommendedOptions("", nil)
+	// The API is read-only and served from memory.
+	o.Etcd = nil
+	o.Admission = nil
+	o.SecureServing.BindPort = s.port
+	o.SecureServing.ServerCert.CertDirectory = ""
+	if err := o.SecureServing.MaybeDefaultWithSelfSignedCerts("localhost", nil, []net.IP{net.ParseIP("127.0.0.1")}); err != nil {
+		return nil, fmt.Errorf("creating self-signed certificates: %w", err)
+	}
+
+	config := genericapiserver.NewRecommendedConfig(api.Codecs)
+	config.Version = &apimachineryversion.Info{
+		GitVersion: version.GitVersion,
+		GitCommit:  version.GitCommit,
+	}
+	namer := openapinamer.NewDefinitionNamer(api.Scheme)
+	config.OpenAPIConfig = genericapiserver.DefaultOpenAPIConfig(openapi.GetOpenAPIDefinitions, namer)
+	config.OpenAPIConfig.Info.Title = "Kueue visibility"
+	config.OpenAPIV3Config = genericapiserver.DefaultOpenAPIV3Config(openapi.GetOpenAPIDefinitions, namer)
+	config.OpenAPIV3Config.Info.Title = "Kueue visibility"
+	if err := o.ApplyTo(config); err != nil {
+		return nil, fmt.Errorf("applying the visibility server options: %w", err)
+	}
+	return config, nil
+}
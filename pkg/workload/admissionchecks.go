@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/util/api"
+)
+
+// FindAdmissionCheck returns the state of the admission check with the given
+// name, or nil if there is no such state.
+func FindAdmissionCheck(checks []kueue.AdmissionCheckState, name string) *kueue.AdmissionCheckState {
+	for i := range checks {
+		if checks[i].Name == name {
+			return &checks[i]
+		}
+	}
+	return nil
+}
+
+// SetAdmissionCheckState sets the state of an admission check, updating the
+// lastTransitionTime only if the state changed.
+func SetAdmissionCheckState(checks *[]kueue.AdmissionCheckState, newCheck kueue.AdmissionCheckState) {
+	newCheck.Message = api.TruncateConditionMessage(newCheck.Message)
+	existing := FindAdmissionCheck(*checks, newCheck.Name)
+	if existing == nil {
+		if newCheck.LastTransitionTime.IsZero() {
+			newCheck.LastTransitionTime = metav1.Now()
+		}
+		*checks = append(*checks, newCheck)
+		return
+	}
+	if existing.State != newCheck.State {
+		existing.State = newCheck.State
+		existing.LastTransitionTime = newCheck.LastTransitionTime
+		if existing.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = metav1.Now()
+		}
+	}
+	existing.Message = newCheck.Message
+	existing.PodSetUpdates = newCheck.PodSetUpdates
+}
+
+// SyncAdmissionChecks makes the states in the status of the workload match the
+// admission checks of its admission. The missing checks are added as Pending.
+// It returns whether the states changed.
+func SyncAdmissionChecks(w *kueue.Workload) bool {
+	var names []string
+	if w.Spec.Admission != nil {
+		names = w.Spec.Admission.AdmissionChecks
+	}
+	changed := false
+	checks := make([]kueue.AdmissionCheckState, 0, len(names))
+	for _, name := range names {
+		if existing := FindAdmissionCheck(w.Status.AdmissionChecks, name); existing != nil {
+			checks = append(checks, *existing)
+			continue
+		}
+		checks = append(checks, kueue.AdmissionCheckState{
+			Name:               name,
+			State:              kueue.CheckStatePending,
+			LastTransitionTime: metav1.Now(),
+		})
+		changed = true
+	}
+	if len(checks) != len(w.Status.AdmissionChecks) {
+		changed = true
+	}
+	if len(checks) == 0 {
+		checks = nil
+	}
+	w.Status.AdmissionChecks = checks
+	return changed
+}
+
+// HasAllChecksReady returns whether all the admission checks of the admission
+// of the workload are Ready.
+func HasAllChecksReady(w *kueue.Workload) bool {
+	if w.Spec.Admission == nil {
+		return false
+	}
+	for _, name := range w.Spec.Admission.AdmissionChecks {
+		check := FindAdmissionCheck(w.Status.AdmissionChecks, name)
+		if check == nil || check.State != kueue.CheckStateReady {
+			return false
+		}
+	}
+	return true
+}
+
+// FirstCheckInState returns the first admission check of the admission of the
+// workload that is in the given state, or nil if there is none.
+func FirstCheckInState(w *kueue.Workload, state kueue.CheckState) *kueue.AdmissionCheckState {
+	if w.Spec.Admission == nil {
+		return nil
+	}
+	for _, name := range w.Spec.Admission.AdmissionChecks {
+		check := FindAdmissionCheck(w.Status.AdmissionChecks, name)
+		if check != nil && check.State == state {
+			return check
+		}
+	}
+	return nil
+}
+
+// IsAdmitted returns whether the workload reserved quota and passed all the
+// admission checks, so that it can start.
+func IsAdmitted(w *kueue.Workload) bool {
+	return w.Spec.Admission != nil && HasAllChecksReady(w)
+}
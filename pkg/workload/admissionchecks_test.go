@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
+)
+
+func TestSyncAdmissionChecks(t *testing.T) {
+	cases := map[string]struct {
+		workload    *kueue.Workload
+		wantChecks  []kueue.AdmissionCheckState
+		wantChanged bool
+	}{
+		"no admission": {
+			workload: utiltesting.MakeWorkload("foo", "").Obj(),
+		},
+		"missing checks are added as pending": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("a", "b").Obj()).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				Obj(),
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "a", State: kueue.CheckStateReady},
+				{Name: "b", State: kueue.CheckStatePending},
+			},
+			wantChanged: true,
+		},
+		"checks not in the admission are removed": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("b").Obj()).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				AdmissionCheck("b", kueue.CheckStateRetry).
+				Obj(),
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "b", State: kueue.CheckStateRetry},
+			},
+			wantChanged: true,
+		},
+		"checks in sync": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("a").Obj()).
+				AdmissionCheck("a", kueue.CheckStatePending).
+				Obj(),
+			wantChecks: []kueue.AdmissionCheckState{
+				{Name: "a", State: kueue.CheckStatePending},
+			},
+		},
+		"checks removed with the admission": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				AdmissionCheck("a", kueue.CheckStateReady).
+				Obj(),
+			wantChanged: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := SyncAdmissionChecks(tc.workload)
+			if changed != tc.wantChanged {
+				t.Errorf("SyncAdmissionChecks() = %t, want %t", changed, tc.wantChanged)
+			}
+			if diff := cmp.Diff(tc.wantChecks, tc.workload.Status.AdmissionChecks, cmpopts.IgnoreFields(kueue.AdmissionCheckState{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Unexpected admission checks (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsAdmitted(t *testing.T) {
+	cases := map[string]struct {
+		workload     *kueue.Workload
+		wantAdmitted bool
+		wantRejected string
+	}{
+		"no admission": {
+			workload: utiltesting.MakeWorkload("foo", "").Obj(),
+		},
+		"admission without checks": {
+			workload:     utiltesting.MakeWorkload("foo", "").Admit(utiltesting.MakeAdmission("cq").Obj()).Obj(),
+			wantAdmitted: true,
+		},
+		"checks pending": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("a", "b").Obj()).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				AdmissionCheck("b", kueue.CheckStatePending).
+				Obj(),
+		},
+		"check missing from the status": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("a", "b").Obj()).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				Obj(),
+		},
+		"all checks ready": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("a", "b").Obj()).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				AdmissionCheck("b", kueue.CheckStateReady).
+				Obj(),
+			wantAdmitted: true,
+		},
+		"check rejected": {
+			workload: utiltesting.MakeWorkload("foo", "").
+				Admit(utiltesting.MakeAdmission("cq").AdmissionChecks("a", "b").Obj()).
+				AdmissionCheck("a", kueue.CheckStateReady).
+				AdmissionCheck("b", kueue.CheckStateRejected).
+				Obj(),
+			wantRejected: "b",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsAdmitted(tc.workload); got != tc.wantAdmitted {
+				t.Errorf("IsAdmitted() = %t, want %t", got, tc.wantAdmitted)
+			}
+			var gotRejected string
+			if check := FirstCheckInState(tc.workload, kueue.CheckStateRejected); check != nil {
+				gotRejected = check.Name
+			}
+			if gotRejected != tc.wantRejected {
+				t.Errorf("FirstCheckInState(Rejected) = %q, want %q", gotRejected, tc.wantRejected)
+			}
+		})
+	}
+}
@@ -18,15 +18,24 @@ package workload
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"gopkg.in/inf.v0"
 	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
+	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/util/api"
 )
 
@@ -43,13 +52,68 @@ type Info struct {
 type PodSetResources struct {
 	Name     string
 	Requests Requests
+	Count    int32
 	Flavors  map[corev1.ResourceName]string
+	// TopologyDomain holds the node labels of the topology domain that the
+	// pod set was admitted into, if any.
+	TopologyDomain map[string]string
 }
 
-func NewInfo(w *kueue.Workload) *Info {
+// ScaledTo returns a copy of the PodSetResources with the requests scaled
+// to the given number of pods.
+func (psr *PodSetResources) ScaledTo(newCount int32) *PodSetResources {
+	ret := &PodSetResources{
+		Name:     psr.Name,
+		Requests: make(Requests, len(psr.Requests)),
+		Count:    newCount,
+		Flavors:  psr.Flavors,
+
+		TopologyDomain: psr.TopologyDomain,
+	}
+	for name, val := range psr.Requests {
+		if psr.Count != 0 {
+			ret.Requests[name] = val / int64(psr.Count) * int64(newCount)
+		}
+	}
+	return ret
+}
+
+// InfoOption configures how the information of a workload is computed.
+type InfoOption func(*infoOptions)
+
+type infoOptions struct {
+	excludedResourcePrefixes []string
+	resourceTransformations  map[corev1.ResourceName]*config.ResourceTransformation
+}
+
+// WithExcludedResourcePrefixes indicates the resources whose names have any of
+// the given prefixes are dropped from the requests of the pods.
+func WithExcludedResourcePrefixes(prefixes []string) InfoOption {
+	return func(o *infoOptions) {
+		o.excludedResourcePrefixes = prefixes
+	}
+}
+
+// WithResourceTransformations indicates the requests of the pods are
+// transformed with the given rules before they are accounted.
+func WithResourceTransformations(transformations []config.ResourceTransformation) InfoOption {
+	m := make(map[corev1.ResourceName]*config.ResourceTransformation, len(transformations))
+	for i := range transformations {
+		m[transformations[i].Input] = &transformations[i]
+	}
+	return func(o *infoOptions) {
+		o.resourceTransformations = m
+	}
+}
+
+func NewInfo(w *kueue.Workload, opts ...InfoOption) *Info {
+	var options infoOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	info := &Info{
 		Obj:           w,
-		TotalRequests: totalRequests(&w.Spec),
+		TotalRequests: totalRequests(&w.Spec, w.Status.ReclaimablePods, &options),
 	}
 	if w.Spec.Admission != nil {
 		info.ClusterQueue = string(w.Spec.Admission.ClusterQueue)
@@ -61,6 +125,252 @@ func (i *Info) Update(wl *kueue.Workload) {
 	i.Obj = wl
 }
 
+// UsesFlavor returns whether any of the pod sets of the workload is assigned
+// the flavor for any resource.
+func (i *Info) UsesFlavor(flavor string) bool {
+	for _, ps := range i.TotalRequests {
+		for _, f := range ps.Flavors {
+			if f == flavor {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResourceUsage returns the quota used by each pod set of the admitted
+// workload, per resource and flavor, sorted by resource name. The resources
+// without a flavor don't use quota and are omitted.
+func (i *Info) ResourceUsage() []kueue.PodSetResourceUsage {
+	usage := make([]kueue.PodSetResourceUsage, 0, len(i.TotalRequests))
+	for _, ps := range i.TotalRequests {
+		psUsage := kueue.PodSetResourceUsage{
+			Name:  ps.Name,
+			Count: ps.Count,
+		}
+		for name, v := range ps.Requests {
+			flavor, ok := ps.Flavors[name]
+			if !ok {
+				continue
+			}
+			psUsage.Resources = append(psUsage.Resources, kueue.ResourceUsage{
+				Name:   name,
+				Flavor: flavor,
+				Total:  ResourceQuantity(name, v),
+			})
+		}
+		sort.Slice(psUsage.Resources, func(a, b int) bool {
+			return psUsage.Resources[a].Name < psUsage.Resources[b].Name
+		})
+		usage = append(usage, psUsage)
+	}
+	return usage
+}
+
+// ExcludedFlavors returns the names of the flavors that the workload can't be
+// assigned, because the nodes of the flavors were reclaimed while the workload
+// was running on them.
+func ExcludedFlavors(w *kueue.Workload) sets.Set[string] {
+	excluded := sets.New[string]()
+	for _, f := range w.Status.ExcludedFlavors {
+		excluded.Insert(f.Name)
+	}
+	return excluded
+}
+
+// ExcludeFlavors excludes the flavors, keyed by name, for the number of
+// retries of each of them, keeping the largest number of retries of the
+// flavors that are already excluded. It returns whether the excluded flavors
+// of the workload changed.
+func ExcludeFlavors(w *kueue.Workload, retries map[string]int32) bool {
+	changed := false
+	for name, n := range retries {
+		if n <= 0 {
+			continue
+		}
+		i := 0
+		for i < len(w.Status.ExcludedFlavors) && w.Status.ExcludedFlavors[i].Name != name {
+			i++
+		}
+		if i == len(w.Status.ExcludedFlavors) {
+			w.Status.ExcludedFlavors = append(w.Status.ExcludedFlavors, kueue.ExcludedFlavor{Name: name})
+		}
+		if w.Status.ExcludedFlavors[i].RemainingRetries < n {
+			w.Status.ExcludedFlavors[i].RemainingRetries = n
+			changed = true
+		}
+	}
+	if changed {
+		sort.Slice(w.Status.ExcludedFlavors, func(i, j int) bool {
+			return w.Status.ExcludedFlavors[i].Name < w.Status.ExcludedFlavors[j].Name
+		})
+	}
+	return changed
+}
+
+// ConsumeExclusionRetry decrements the remaining retries of the excluded
+// flavors of an admitted workload, and removes the flavors that have none
+// left. It returns whether the excluded flavors of the workload changed.
+func ConsumeExclusionRetry(w *kueue.Workload) bool {
+	if len(w.Status.ExcludedFlavors) == 0 {
+		return false
+	}
+	remaining := w.Status.ExcludedFlavors[:0]
+	for _, f := range w.Status.ExcludedFlavors {
+		if f.RemainingRetries > 1 {
+			f.RemainingRetries--
+			remaining = append(remaining, f)
+		}
+	}
+	w.Status.ExcludedFlavors = remaining
+	if len(remaining) == 0 {
+		w.Status.ExcludedFlavors = nil
+	}
+	return true
+}
+
+// ResizeCounts returns the counts requested in the podSetResizes of the
+// workload, keyed by podSet name.
+func ResizeCounts(w *kueue.Workload) map[string]int32 {
+	if len(w.Spec.PodSetResizes) == 0 {
+		return nil
+	}
+	counts := make(map[string]int32, len(w.Spec.PodSetResizes))
+	for _, r := range w.Spec.PodSetResizes {
+		counts[r.Name] = r.Count
+	}
+	return counts
+}
+
+// AdmittedCount returns the number of pods of the podSet in the admission.
+func AdmittedCount(ps *kueue.PodSet, psFlavors *kueue.PodSetFlavors) int32 {
+	if psFlavors != nil && psFlavors.Count != nil {
+		return *psFlavors.Count
+	}
+	return ps.Count
+}
+
+// HasPendingResize returns whether the workload is admitted and requests, for
+// any of its podSets, a count different from the admitted one.
+func HasPendingResize(w *kueue.Workload) bool {
+	if w.Spec.Admission == nil {
+		return false
+	}
+	resizes := ResizeCounts(w)
+	for i := range w.Spec.PodSets {
+		ps := &w.Spec.PodSets[i]
+		if count, found := resizes[ps.Name]; found && count != AdmittedCount(ps, FindPodSetFlavors(w.Spec.Admission, ps.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResizedAdmission returns a copy of the admission of the workload with the
+// counts requested in its podSetResizes.
+func ResizedAdmission(w *kueue.Workload) *kueue.Admission {
+	admission := w.Spec.Admission.DeepCopy()
+	resizes := ResizeCounts(w)
+	for i := range admission.PodSetFlavors {
+		if count, found := resizes[admission.PodSetFlavors[i].Name]; found {
+			admission.PodSetFlavors[i].Count = &count
+		}
+	}
+	return admission
+}
+
+// NewResizeInfo returns the information of the pods that an admitted workload
+// requests in addition to the admitted ones, in its podSetResizes. The pod
+// sets keep the flavors and topology domains of the admission.
+func NewResizeInfo(w *kueue.Workload, opts ...InfoOption) *Info {
+	var options infoOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	info := NewInfo(w, opts...)
+	resizes := ResizeCounts(w)
+	for i := range w.Spec.PodSets {
+		ps := &w.Spec.PodSets[i]
+		admitted := AdmittedCount(ps, FindPodSetFlavors(w.Spec.Admission, ps.Name))
+		delta := int32(0)
+		if count, found := resizes[ps.Name]; found && count > admitted {
+			delta = count - admitted
+		}
+		psr := &info.TotalRequests[i]
+		psr.Count = delta
+		psr.Requests = podRequests(&ps.Spec, &options)
+		psr.Requests.scale(int64(delta))
+	}
+	return info
+}
+
+// FindPodSetFlavors returns the flavors assigned to the podSet with the given
+// name in the admission, or nil if there are none.
+func FindPodSetFlavors(admission *kueue.Admission, name string) *kueue.PodSetFlavors {
+	for i := range admission.PodSetFlavors {
+		if admission.PodSetFlavors[i].Name == name {
+			return &admission.PodSetFlavors[i]
+		}
+	}
+	return nil
+}
+
+// CanBePartiallyAdmitted returns true if any of the podSets of the workload
+// defines a minCount lower than its count.
+func (i *Info) CanBePartiallyAdmitted() bool {
+	for _, ps := range i.Obj.Spec.PodSets {
+		if ps.MinCount != nil && *ps.MinCount < ps.Count {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupName returns the name of the group of workloads that the workload
+// belongs to, or an empty string if it doesn't belong to a group.
+func GroupName(w *kueue.Workload) string {
+	return w.Annotations[constants.WorkloadGroupAnnotation]
+}
+
+// IsSlice returns whether the workload is one of the slices of a job.
+func IsSlice(w *kueue.Workload) bool {
+	_, found := w.Annotations[constants.WorkloadSliceAnnotation]
+	return found
+}
+
+// SameJobSlices returns whether the workloads are slices of the same job.
+func SameJobSlices(a, b *kueue.Workload) bool {
+	if !IsSlice(a) || !IsSlice(b) || a.Namespace != b.Namespace {
+		return false
+	}
+	ownerA, ownerB := metav1.GetControllerOf(a), metav1.GetControllerOf(b)
+	return ownerA != nil && ownerB != nil && ownerA.UID == ownerB.UID
+}
+
+// GroupSize returns the number of workloads in the group of the workload, or
+// 0 if the annotation holding it is missing or invalid.
+func GroupSize(w *kueue.Workload) int {
+	size, err := strconv.Atoi(w.Annotations[constants.WorkloadGroupSizeAnnotation])
+	if err != nil || size <= 0 {
+		return 0
+	}
+	return size
+}
+
+// NewGroupInfo returns the information of a workload that has the pod sets of
+// all the given workloads, in order. The first workload provides the rest of
+// the fields.
+func NewGroupInfo(members []*Info, opts ...InfoOption) *Info {
+	wl := members[0].Obj.DeepCopy()
+	wl.Spec.PodSets = nil
+	for _, m := range members {
+		wl.Spec.PodSets = append(wl.Spec.PodSets, m.Obj.Spec.PodSets...)
+	}
+	info := NewInfo(wl, opts...)
+	info.ClusterQueue = members[0].ClusterQueue
+	return info
+}
+
 func Key(w *kueue.Workload) string {
 	return fmt.Sprintf("%s/%s", w.Namespace, w.Name)
 }
@@ -69,25 +379,57 @@ func QueueKey(w *kueue.Workload) string {
 	return fmt.Sprintf("%s/%s", w.Namespace, w.Spec.QueueName)
 }
 
-func totalRequests(spec *kueue.WorkloadSpec) []PodSetResources {
+// totalRequests returns the requests of the pod sets. If the workload is
+// admitted, the counts are the admitted ones, minus the reclaimable pods.
+func totalRequests(spec *kueue.WorkloadSpec, reclaimablePods []kueue.ReclaimablePod, options *infoOptions) []PodSetResources {
 	if len(spec.PodSets) == 0 {
 		return nil
 	}
 	res := make([]PodSetResources, 0, len(spec.PodSets))
 	var podSetFlavors map[string]map[corev1.ResourceName]string
+	var podSetCounts map[string]int32
+	var podSetDomains map[string]map[string]string
 	if spec.Admission != nil {
 		podSetFlavors = make(map[string]map[corev1.ResourceName]string, len(spec.Admission.PodSetFlavors))
+		podSetCounts = make(map[string]int32, len(spec.Admission.PodSetFlavors))
 		for _, ps := range spec.Admission.PodSetFlavors {
 			podSetFlavors[ps.Name] = ps.Flavors
+			if len(ps.TopologyDomain) > 0 {
+				if podSetDomains == nil {
+					podSetDomains = make(map[string]map[string]string)
+				}
+				podSetDomains[ps.Name] = ps.TopologyDomain
+			}
+			if ps.Count != nil {
+				podSetCounts[ps.Name] = *ps.Count
+			}
+		}
+	}
+	var podSetReclaimable map[string]int32
+	if spec.Admission != nil && len(reclaimablePods) > 0 {
+		podSetReclaimable = make(map[string]int32, len(reclaimablePods))
+		for _, rp := range reclaimablePods {
+			podSetReclaimable[rp.Name] = rp.Count
 		}
 	}
 
 	for _, ps := range spec.PodSets {
+		count := ps.Count
+		if c, found := podSetCounts[ps.Name]; found {
+			count = c
+		}
+		if r := podSetReclaimable[ps.Name]; r > 0 {
+			count -= r
+			if count < 0 {
+				count = 0
+			}
+		}
 		setRes := PodSetResources{
-			Name: ps.Name,
+			Name:  ps.Name,
+			Count: count,
 		}
-		setRes.Requests = podRequests(&ps.Spec)
-		setRes.Requests.scale(int64(ps.Count))
+		setRes.Requests = podRequests(&ps.Spec, options)
+		setRes.Requests.scale(int64(count))
 		flavors := podSetFlavors[ps.Name]
 		if len(flavors) > 0 {
 			setRes.Flavors = make(map[corev1.ResourceName]string, len(flavors))
@@ -95,6 +437,12 @@ func totalRequests(spec *kueue.WorkloadSpec) []PodSetResources {
 				setRes.Flavors[r] = t
 			}
 		}
+		if domain := podSetDomains[ps.Name]; domain != nil {
+			setRes.TopologyDomain = make(map[string]string, len(domain))
+			for k, v := range domain {
+				setRes.TopologyDomain[k] = v
+			}
+		}
 		res = append(res, setRes)
 	}
 	return res
@@ -106,7 +454,7 @@ func totalRequests(spec *kueue.WorkloadSpec) []PodSetResources {
 // Requests maps ResourceName to flavor to value; for CPU it is tracked in MilliCPU.
 type Requests map[corev1.ResourceName]int64
 
-func podRequests(spec *corev1.PodSpec) Requests {
+func podRequests(spec *corev1.PodSpec, options *infoOptions) Requests {
 	res := Requests{}
 	for _, c := range spec.Containers {
 		res.add(newRequests(c.Resources.Requests))
@@ -115,9 +463,48 @@ func podRequests(spec *corev1.PodSpec) Requests {
 		res.setMax(newRequests(c.Resources.Requests))
 	}
 	res.add(newRequests(spec.Overhead))
+	res.exclude(options.excludedResourcePrefixes)
+	res.transform(options.resourceTransformations)
 	return res
 }
 
+// exclude drops the resources whose names have any of the prefixes.
+func (r Requests) exclude(prefixes []string) {
+	for name := range r {
+		for _, p := range prefixes {
+			if strings.HasPrefix(string(name), p) {
+				delete(r, name)
+				break
+			}
+		}
+	}
+}
+
+// transform applies the transformations to the requests of a pod. The outputs
+// are added after all the inputs are processed, so that they aren't
+// transformed again.
+func (r Requests) transform(transformations map[corev1.ResourceName]*config.ResourceTransformation) {
+	if len(transformations) == 0 {
+		return
+	}
+	outputs := Requests{}
+	for name, val := range r {
+		t, found := transformations[name]
+		if !found {
+			continue
+		}
+		if t.Strategy != nil && *t.Strategy == config.Replace {
+			delete(r, name)
+		}
+		in := ResourceQuantity(name, val)
+		for outName, q := range t.Outputs {
+			v := new(inf.Dec).Mul(q.AsDec(), in.AsDec())
+			outputs[outName] += ResourceValue(outName, *resource.NewDecimalQuantity(*v, q.Format))
+		}
+	}
+	r.add(outputs)
+}
+
 func newRequests(rl corev1.ResourceList) Requests {
 	r := Requests{}
 	for name, quant := range rl {
@@ -149,6 +536,13 @@ func ResourceQuantity(name corev1.ResourceName, v int64) resource.Quantity {
 	}
 }
 
+// IsExtendedResource returns whether the resource is an extended resource,
+// such as nvidia.com/gpu, which is exposed by the nodes that have it.
+func IsExtendedResource(name corev1.ResourceName) bool {
+	n := string(name)
+	return strings.Contains(n, "/") && !strings.Contains(n, corev1.ResourceDefaultNamespacePrefix) && !strings.HasPrefix(n, corev1.DefaultResourceRequestsPrefix)
+}
+
 func (r Requests) add(o Requests) {
 	for name, val := range o {
 		r[name] += val
@@ -238,6 +632,22 @@ func UpdateStatusIfChanged(ctx context.Context,
 	return UpdateStatus(ctx, c, wl, conditionType, conditionStatus, reason, message)
 }
 
+// MaxInadmissibleReasons is the maximum number of inadmissible reasons of a
+// Workload. A workload with this many reasons might have more of them.
+const MaxInadmissibleReasons = 32
+
+// BackoffRemaining returns how long, since now, the workload still has to wait
+// before it is considered again for admission after failing to be admitted.
+func BackoffRemaining(w *kueue.Workload, now time.Time) time.Duration {
+	if w.Status.RequeueState == nil || w.Status.RequeueState.RequeueAt == nil {
+		return 0
+	}
+	if remaining := w.Status.RequeueState.RequeueAt.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
 // ClearAdmissionPatch creates a new object based on the input workload that
 // doesn't contain admission. The object can be used in Server-Side-Apply.
 func ClearAdmissionPatch(w *kueue.Workload) *kueue.Workload {
@@ -259,6 +669,67 @@ func ClearAdmissionPatch(w *kueue.Workload) *kueue.Workload {
 	return wlCopy
 }
 
+// AdmissionFieldOwner returns the field owner of the admissions applied by
+// the scheduler, with the given field manager, during the leadership term
+// identified by the fencing token.
+func AdmissionFieldOwner(fieldManager string, fencingToken int32) string {
+	return fmt.Sprintf("%s-%d", fieldManager, fencingToken)
+}
+
+// FencingToken returns the fencing token of a field owner returned by
+// AdmissionFieldOwner with the given field manager, and whether the field
+// owner is one of them.
+func FencingToken(fieldManager, fieldOwner string) (int32, bool) {
+	if !strings.HasPrefix(fieldOwner, fieldManager+"-") {
+		return 0, false
+	}
+	token, err := strconv.ParseInt(strings.TrimPrefix(fieldOwner, fieldManager+"-"), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(token), true
+}
+
+// AdmissionOwners returns the field owners of the admission of the workload,
+// with which the admission has to be cleared. They are the field managers
+// that applied the admission, which is constants.AdmissionName unless the
+// scheduler is configured with another field manager or applies the
+// admissions with fencing. The admission has more than one owner when the
+// schedulers of several leadership terms applied the same admission.
+func AdmissionOwners(w *kueue.Workload) []string {
+	var owners []string
+	for _, mf := range w.ManagedFields {
+		if mf.Operation != metav1.ManagedFieldsOperationApply || mf.Subresource != "" || mf.FieldsV1 == nil {
+			continue
+		}
+		var fields struct {
+			Spec map[string]json.RawMessage `json:"f:spec"`
+		}
+		if err := json.Unmarshal(mf.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ok := fields.Spec["f:admission"]; ok {
+			owners = append(owners, mf.Manager)
+		}
+	}
+	if len(owners) == 0 {
+		return []string{constants.AdmissionName}
+	}
+	return owners
+}
+
+// ClearAdmission clears the admission of the workload with each of its
+// owners. A server-side apply only removes a field when none of the other
+// field managers own it.
+func ClearAdmission(ctx context.Context, c client.Client, w *kueue.Workload) error {
+	for _, owner := range AdmissionOwners(w) {
+		if err := c.Patch(ctx, ClearAdmissionPatch(w), client.Apply, client.FieldOwner(owner)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AdmissionPatch creates a new object based on the input workload that
 // contains the admission. The object can be used in Server-Side-Apply.
 func AdmissionPatch(w *kueue.Workload) *kueue.Workload {
@@ -266,3 +737,76 @@ func AdmissionPatch(w *kueue.Workload) *kueue.Workload {
 	wlCopy.Spec.Admission = w.Spec.Admission.DeepCopy()
 	return wlCopy
 }
+
+// IsActive returns whether the workload can be admitted. Workloads are active
+// unless .spec.active is false.
+func IsActive(w *kueue.Workload) bool {
+	return w.Spec.Active == nil || *w.Spec.Active
+}
+
+// IsDeactivatedByRequeuingLimit returns whether the workload was deactivated
+// because it exceeded the requeuing limit, and it wasn't reactivated since.
+func IsDeactivatedByRequeuingLimit(w *kueue.Workload) bool {
+	if IsActive(w) {
+		return false
+	}
+	cond := apimeta.FindStatusCondition(w.Status.Conditions, kueue.WorkloadAdmitted)
+	return cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason == kueue.WorkloadRequeuingLimitExceeded
+}
+
+// IsReservation returns whether the workload only reserves quota, without
+// running any pods.
+func IsReservation(w *kueue.Workload) bool {
+	return w.Spec.Reservation != nil
+}
+
+// IsEvicted returns whether the workload was evicted and is waiting for its
+// job to be suspended and its admission to be cleared.
+func IsEvicted(w *kueue.Workload) bool {
+	return apimeta.IsStatusConditionTrue(w.Status.Conditions, kueue.WorkloadEvicted)
+}
+
+// EvictionPatch creates a new object based on the input workload that only
+// contains the Evicted condition, set to True with the given reason and
+// message. The object can be used in Server-Side-Apply on the status.
+func EvictionPatch(w *kueue.Workload, reason, message string) *kueue.Workload {
+	wlCopy := ClearAdmissionPatch(w)
+	wlCopy.Status.Conditions = []metav1.Condition{{
+		Type:               kueue.WorkloadEvicted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            api.TruncateConditionMessage(message),
+	}}
+	return wlCopy
+}
+
+// PreemptionPatch creates a new object based on the input workload that only
+// contains the Evicted condition, with the preemption reason and the given
+// message, and the annotations that identify the preemptor workload. The
+// status subresource ignores the annotations, which need to be applied with
+// AnnotationsPatch.
+func PreemptionPatch(w, preemptor *kueue.Workload, message string) *kueue.Workload {
+	wlCopy := EvictionPatch(w, kueue.WorkloadEvictedByPreemption, message)
+	wlCopy.Annotations = map[string]string{
+		constants.PreemptorAnnotation:    Key(preemptor),
+		constants.PreemptorUIDAnnotation: string(preemptor.UID),
+	}
+	return wlCopy
+}
+
+// AnnotationsPatch creates a new object based on the input workload that only
+// contains its annotations. The object can be used in Server-Side-Apply.
+func AnnotationsPatch(w *kueue.Workload) *kueue.Workload {
+	wlCopy := ClearAdmissionPatch(w)
+	wlCopy.Annotations = w.Annotations
+	return wlCopy
+}
+
+// Evict sets the Evicted condition of the workload. The job controllers then
+// suspend the job of the workload and clear its admission, which releases
+// its quota.
+func Evict(ctx context.Context, c client.Client, w *kueue.Workload, reason, message string) error {
+	return c.Status().Patch(ctx, EvictionPatch(w, reason, message), client.Apply,
+		client.FieldOwner(constants.AdmissionName), client.ForceOwnership)
+}
@@ -18,6 +18,7 @@ package workload
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,17 +27,23 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	config "sigs.k8s.io/kueue/apis/config/v1alpha2"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	utiltesting "sigs.k8s.io/kueue/pkg/util/testing"
 )
 
 func TestPodRequests(t *testing.T) {
+	retain := config.Retain
+	replace := config.Replace
 	cases := map[string]struct {
-		spec         corev1.PodSpec
-		wantRequests Requests
+		spec             corev1.PodSpec
+		excludedPrefixes []string
+		transformations  []config.ResourceTransformation
+		wantRequests     Requests
 	}{
 		"core": {
 			spec: corev1.PodSpec{
@@ -122,10 +129,134 @@ func TestPodRequests(t *testing.T) {
 				corev1.ResourceEphemeralStorage: 1024,
 			},
 		},
+		"init containers with Pod Overhead": {
+			spec: corev1.PodSpec{
+				Containers: containersForRequests(
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "100m",
+						corev1.ResourceMemory: "1Ki",
+					},
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "100m",
+					},
+				),
+				InitContainers: containersForRequests(
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "500m",
+					},
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "50m",
+						corev1.ResourceMemory: "4Ki",
+						"ex.com/gpu":          "1",
+					},
+				),
+				Overhead: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("1Ki"),
+				},
+			},
+			wantRequests: Requests{
+				corev1.ResourceCPU:    510,
+				corev1.ResourceMemory: 5 * 1024,
+				"ex.com/gpu":          1,
+			},
+		},
+		"transformations": {
+			spec: corev1.PodSpec{
+				Containers: containersForRequests(
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU:              "1",
+						corev1.ResourceEphemeralStorage: "1Ki",
+						"nvidia.com/mig-1g.5gb":         "1",
+					},
+					map[corev1.ResourceName]string{
+						"nvidia.com/mig-1g.5gb": "2",
+						"nvidia.com/mig-2g.10g": "1",
+					},
+				),
+			},
+			transformations: []config.ResourceTransformation{
+				{
+					Input:    "nvidia.com/mig-1g.5gb",
+					Strategy: &replace,
+					Outputs: corev1.ResourceList{
+						"nvidia.com/gpu":      resource.MustParse("250m"),
+						corev1.ResourceMemory: resource.MustParse("5Gi"),
+					},
+				},
+				{
+					Input:    "nvidia.com/mig-2g.10g",
+					Strategy: &retain,
+					Outputs: corev1.ResourceList{
+						"nvidia.com/mig-1g.5gb": resource.MustParse("2"),
+					},
+				},
+				{
+					Input:    corev1.ResourceEphemeralStorage,
+					Strategy: &replace,
+				},
+			},
+			wantRequests: Requests{
+				corev1.ResourceCPU:      1000,
+				corev1.ResourceMemory:   15 * 1024 * 1024 * 1024,
+				"nvidia.com/gpu":        1,
+				"nvidia.com/mig-2g.10g": 1,
+				"nvidia.com/mig-1g.5gb": 2,
+			},
+		},
+		"excluded resources": {
+			spec: corev1.PodSpec{
+				Containers: containersForRequests(
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU:    "1",
+						"hugepages-2Mi":       "4Mi",
+						"example.com/foo":     "1",
+						"example.com/bar":     "1",
+						"nvidia.com/mig-1g.5": "1",
+					},
+				),
+			},
+			excludedPrefixes: []string{"hugepages-", "example.com/", "nvidia.com/"},
+			transformations: []config.ResourceTransformation{
+				{
+					Input: "nvidia.com/mig-1g.5",
+					Outputs: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("5Gi"),
+					},
+				},
+			},
+			wantRequests: Requests{
+				corev1.ResourceCPU: 1000,
+			},
+		},
+		"transformation into cpu": {
+			spec: corev1.PodSpec{
+				Containers: containersForRequests(
+					map[corev1.ResourceName]string{
+						"example.com/vcpu": "3",
+					},
+				),
+			},
+			transformations: []config.ResourceTransformation{
+				{
+					Input:    "example.com/vcpu",
+					Strategy: &replace,
+					Outputs: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("500m"),
+					},
+				},
+			},
+			wantRequests: Requests{
+				corev1.ResourceCPU: 1500,
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			gotRequests := podRequests(&tc.spec)
+			var options infoOptions
+			WithExcludedResourcePrefixes(tc.excludedPrefixes)(&options)
+			WithResourceTransformations(tc.transformations)(&options)
+			gotRequests := podRequests(&tc.spec, &options)
 			if diff := cmp.Diff(tc.wantRequests, gotRequests); diff != "" {
 				t.Errorf("podRequests returned unexpected requests (-want,+got):\n%s", diff)
 			}
@@ -164,6 +295,7 @@ func TestNewInfo(t *testing.T) {
 							corev1.ResourceCPU:    10,
 							corev1.ResourceMemory: 512 * 1024,
 						},
+						Count: 1,
 					},
 				},
 			},
@@ -218,6 +350,7 @@ func TestNewInfo(t *testing.T) {
 							corev1.ResourceCPU:    10,
 							corev1.ResourceMemory: 512 * 1024,
 						},
+						Count: 1,
 						Flavors: map[corev1.ResourceName]string{
 							corev1.ResourceCPU: "on-demand",
 						},
@@ -229,6 +362,136 @@ func TestNewInfo(t *testing.T) {
 							corev1.ResourceMemory: 3 * 1024 * 1024,
 							"ex.com/gpu":          3,
 						},
+						Count: 3,
+					},
+				},
+			},
+		},
+		"partially admitted": {
+			workload: kueue.Workload{
+				Spec: kueue.WorkloadSpec{
+					PodSets: []kueue.PodSet{
+						{
+							Name: "workers",
+							Spec: corev1.PodSpec{
+								Containers: containersForRequests(
+									map[corev1.ResourceName]string{
+										corev1.ResourceCPU: "5m",
+									}),
+							},
+							Count:    4,
+							MinCount: pointer.Int32(2),
+						},
+					},
+					Admission: &kueue.Admission{
+						ClusterQueue: "foo",
+						PodSetFlavors: []kueue.PodSetFlavors{
+							{
+								Name: "workers",
+								Flavors: map[corev1.ResourceName]string{
+									corev1.ResourceCPU: "on-demand",
+								},
+								Count: pointer.Int32(3),
+							},
+						},
+					},
+				},
+			},
+			wantInfo: Info{
+				ClusterQueue: "foo",
+				TotalRequests: []PodSetResources{
+					{
+						Name: "workers",
+						Requests: Requests{
+							corev1.ResourceCPU: 15,
+						},
+						Count: 3,
+						Flavors: map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "on-demand",
+						},
+					},
+				},
+			},
+		},
+		"admitted with reclaimable pods": {
+			workload: kueue.Workload{
+				Spec: kueue.WorkloadSpec{
+					PodSets: []kueue.PodSet{
+						{
+							Name: "workers",
+							Spec: corev1.PodSpec{
+								Containers: containersForRequests(
+									map[corev1.ResourceName]string{
+										corev1.ResourceCPU: "5m",
+									}),
+							},
+							Count: 4,
+						},
+					},
+					Admission: &kueue.Admission{
+						ClusterQueue: "foo",
+						PodSetFlavors: []kueue.PodSetFlavors{
+							{
+								Name: "workers",
+								Flavors: map[corev1.ResourceName]string{
+									corev1.ResourceCPU: "on-demand",
+								},
+							},
+						},
+					},
+				},
+				Status: kueue.WorkloadStatus{
+					ReclaimablePods: []kueue.ReclaimablePod{
+						{Name: "workers", Count: 3},
+					},
+				},
+			},
+			wantInfo: Info{
+				ClusterQueue: "foo",
+				TotalRequests: []PodSetResources{
+					{
+						Name: "workers",
+						Requests: Requests{
+							corev1.ResourceCPU: 5,
+						},
+						Count: 1,
+						Flavors: map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "on-demand",
+						},
+					},
+				},
+			},
+		},
+		"pending with reclaimable pods": {
+			workload: kueue.Workload{
+				Spec: kueue.WorkloadSpec{
+					PodSets: []kueue.PodSet{
+						{
+							Name: "workers",
+							Spec: corev1.PodSpec{
+								Containers: containersForRequests(
+									map[corev1.ResourceName]string{
+										corev1.ResourceCPU: "5m",
+									}),
+							},
+							Count: 4,
+						},
+					},
+				},
+				Status: kueue.WorkloadStatus{
+					ReclaimablePods: []kueue.ReclaimablePod{
+						{Name: "workers", Count: 3},
+					},
+				},
+			},
+			wantInfo: Info{
+				TotalRequests: []PodSetResources{
+					{
+						Name: "workers",
+						Requests: Requests{
+							corev1.ResourceCPU: 20,
+						},
+						Count: 4,
 					},
 				},
 			},
@@ -244,6 +507,225 @@ func TestNewInfo(t *testing.T) {
 	}
 }
 
+func TestResourceUsage(t *testing.T) {
+	wl := kueue.Workload{
+		Spec: kueue.WorkloadSpec{
+			PodSets: []kueue.PodSet{
+				{
+					Name: "driver",
+					Spec: corev1.PodSpec{
+						Containers: containersForRequests(map[corev1.ResourceName]string{
+							corev1.ResourceCPU:    "500m",
+							corev1.ResourceMemory: "1Gi",
+						}),
+					},
+					Count: 1,
+				},
+				{
+					Name: "workers",
+					Spec: corev1.PodSpec{
+						Containers: containersForRequests(map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "2",
+							"example.com/gpu":  "1",
+							"hugepages-2Mi":    "2Mi",
+						}),
+					},
+					Count: 4,
+				},
+			},
+			Admission: &kueue.Admission{
+				ClusterQueue: "cq",
+				PodSetFlavors: []kueue.PodSetFlavors{
+					{
+						Name: "driver",
+						Flavors: map[corev1.ResourceName]string{
+							corev1.ResourceCPU:    "on-demand",
+							corev1.ResourceMemory: "on-demand",
+						},
+					},
+					{
+						Name: "workers",
+						Flavors: map[corev1.ResourceName]string{
+							corev1.ResourceCPU: "spot",
+							"example.com/gpu":  "a100",
+						},
+						Count: pointer.Int32(3),
+					},
+				},
+			},
+		},
+		Status: kueue.WorkloadStatus{
+			ReclaimablePods: []kueue.ReclaimablePod{{Name: "workers", Count: 1}},
+		},
+	}
+	want := []kueue.PodSetResourceUsage{
+		{
+			Name:  "driver",
+			Count: 1,
+			Resources: []kueue.ResourceUsage{
+				{Name: corev1.ResourceCPU, Flavor: "on-demand", Total: resource.MustParse("500m")},
+				{Name: corev1.ResourceMemory, Flavor: "on-demand", Total: resource.MustParse("1Gi")},
+			},
+		},
+		{
+			Name:  "workers",
+			Count: 2,
+			Resources: []kueue.ResourceUsage{
+				{Name: corev1.ResourceCPU, Flavor: "spot", Total: resource.MustParse("4")},
+				{Name: "example.com/gpu", Flavor: "a100", Total: resource.MustParse("2")},
+			},
+		},
+	}
+	got := NewInfo(&wl, WithExcludedResourcePrefixes([]string{"hugepages-"})).ResourceUsage()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected resource usage (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNewResizeInfo(t *testing.T) {
+	podSets := []kueue.PodSet{
+		{
+			Name: "driver",
+			Spec: corev1.PodSpec{
+				Containers: containersForRequests(
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "10m",
+					}),
+			},
+			Count: 1,
+		},
+		{
+			Name: "workers",
+			Spec: corev1.PodSpec{
+				Containers: containersForRequests(
+					map[corev1.ResourceName]string{
+						corev1.ResourceCPU: "5m",
+					}),
+			},
+			Count: 4,
+		},
+	}
+	admission := &kueue.Admission{
+		ClusterQueue: "foo",
+		PodSetFlavors: []kueue.PodSetFlavors{
+			{
+				Name: "driver",
+				Flavors: map[corev1.ResourceName]string{
+					corev1.ResourceCPU: "on-demand",
+				},
+			},
+			{
+				Name: "workers",
+				Flavors: map[corev1.ResourceName]string{
+					corev1.ResourceCPU: "spot",
+				},
+				Count: pointer.Int32(2),
+			},
+		},
+	}
+	cases := map[string]struct {
+		resizes           []kueue.PodSetResize
+		wantPendingResize bool
+		wantInfo          Info
+	}{
+		"no resizes": {
+			wantInfo: Info{
+				TotalRequests: []PodSetResources{
+					{
+						Name:     "driver",
+						Requests: Requests{corev1.ResourceCPU: 0},
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "on-demand"},
+					},
+					{
+						Name:     "workers",
+						Requests: Requests{corev1.ResourceCPU: 0},
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "spot"},
+					},
+				},
+			},
+		},
+		"grow": {
+			resizes: []kueue.PodSetResize{
+				{Name: "workers", Count: 5},
+			},
+			wantPendingResize: true,
+			wantInfo: Info{
+				TotalRequests: []PodSetResources{
+					{
+						Name:     "driver",
+						Requests: Requests{corev1.ResourceCPU: 0},
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "on-demand"},
+					},
+					{
+						Name:     "workers",
+						Requests: Requests{corev1.ResourceCPU: 15},
+						Count:    3,
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "spot"},
+					},
+				},
+			},
+		},
+		"shrink": {
+			resizes: []kueue.PodSetResize{
+				{Name: "workers", Count: 1},
+			},
+			wantPendingResize: true,
+			wantInfo: Info{
+				TotalRequests: []PodSetResources{
+					{
+						Name:     "driver",
+						Requests: Requests{corev1.ResourceCPU: 0},
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "on-demand"},
+					},
+					{
+						Name:     "workers",
+						Requests: Requests{corev1.ResourceCPU: 0},
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "spot"},
+					},
+				},
+			},
+		},
+		"already resized": {
+			resizes: []kueue.PodSetResize{
+				{Name: "driver", Count: 1},
+				{Name: "workers", Count: 2},
+			},
+			wantInfo: Info{
+				TotalRequests: []PodSetResources{
+					{
+						Name:     "driver",
+						Requests: Requests{corev1.ResourceCPU: 0},
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "on-demand"},
+					},
+					{
+						Name:     "workers",
+						Requests: Requests{corev1.ResourceCPU: 0},
+						Flavors:  map[corev1.ResourceName]string{corev1.ResourceCPU: "spot"},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wl := &kueue.Workload{
+				Spec: kueue.WorkloadSpec{
+					PodSets:       podSets,
+					Admission:     admission,
+					PodSetResizes: tc.resizes,
+				},
+			}
+			if got := HasPendingResize(wl); got != tc.wantPendingResize {
+				t.Errorf("HasPendingResize(_) = %t, want %t", got, tc.wantPendingResize)
+			}
+			info := NewResizeInfo(wl)
+			if diff := cmp.Diff(&tc.wantInfo, info, cmpopts.IgnoreFields(Info{}, "Obj", "ClusterQueue")); diff != "" {
+				t.Errorf("NewResizeInfo(_) = (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
 var ignoreConditionTimestamps = cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")
 
 func TestUpdateWorkloadStatus(t *testing.T) {
@@ -358,3 +840,240 @@ func containersForRequests(requests ...map[corev1.ResourceName]string) []corev1.
 	}
 	return containers
 }
+
+func TestAdmissionOwners(t *testing.T) {
+	cases := map[string]struct {
+		managedFields []metav1.ManagedFieldsEntry
+		want          []string
+	}{
+		"no managed fields": {
+			want: []string{"kueue-admission"},
+		},
+		"admission applied with fencing": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kueue",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:queueName":{}}}`)},
+				},
+				{
+					Manager:     "kueue-admission",
+					Operation:   metav1.ManagedFieldsOperationApply,
+					Subresource: "status",
+					FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:conditions":{}}}`)},
+				},
+				{
+					Manager:   "kueue-admission-3",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:admission":{"f:clusterQueue":{}}}}`)},
+				},
+			},
+			want: []string{"kueue-admission-3"},
+		},
+		"admission applied with another field manager": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kueue",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:queueName":{}}}`)},
+				},
+				{
+					Manager:   "my-kueue-admission",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:admission":{"f:clusterQueue":{}}}}`)},
+				},
+			},
+			want: []string{"my-kueue-admission"},
+		},
+		"admission co-owned by two leadership terms": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kueue-admission-2",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:admission":{"f:clusterQueue":{}}}}`)},
+				},
+				{
+					Manager:   "kueue-admission-3",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:admission":{"f:clusterQueue":{}}}}`)},
+				},
+			},
+			want: []string{"kueue-admission-2", "kueue-admission-3"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wl := utiltesting.MakeWorkload("wl", "ns").Obj()
+			wl.ManagedFields = tc.managedFields
+			if diff := cmp.Diff(tc.want, AdmissionOwners(wl)); diff != "" {
+				t.Errorf("Unexpected admission owners (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// patchRecorder records the field owners of the patches.
+type patchRecorder struct {
+	client.Client
+	fieldOwners []string
+}
+
+func (r *patchRecorder) Patch(_ context.Context, obj client.Object, _ client.Patch, opts ...client.PatchOption) error {
+	patchOpts := &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	r.fieldOwners = append(r.fieldOwners, patchOpts.FieldManager)
+	if admission := obj.(*kueue.Workload).Spec.Admission; admission != nil {
+		return fmt.Errorf("the patch sets the admission %v", admission)
+	}
+	return nil
+}
+
+func TestClearCoOwnedAdmission(t *testing.T) {
+	wl := utiltesting.MakeWorkload("wl", "ns").Admit(utiltesting.MakeAdmission("cq").Obj()).Obj()
+	wl.ManagedFields = []metav1.ManagedFieldsEntry{
+		{
+			Manager:   "kueue-admission-2",
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:admission":{"f:clusterQueue":{}}}}`)},
+		},
+		{
+			Manager:   "kueue-admission-3",
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:admission":{"f:clusterQueue":{}}}}`)},
+		},
+	}
+	cl := &patchRecorder{}
+	if err := ClearAdmission(context.Background(), cl, wl); err != nil {
+		t.Fatalf("Clearing the admission: %v", err)
+	}
+	if diff := cmp.Diff([]string{"kueue-admission-2", "kueue-admission-3"}, cl.fieldOwners); diff != "" {
+		t.Errorf("Unexpected field owners clearing the admission (-want,+got):\n%s", diff)
+	}
+}
+
+func TestFencingToken(t *testing.T) {
+	cases := map[string]struct {
+		fieldOwner string
+		wantToken  int32
+		wantOK     bool
+	}{
+		"fenced": {
+			fieldOwner: "kueue-admission-3",
+			wantToken:  3,
+			wantOK:     true,
+		},
+		"not fenced": {
+			fieldOwner: "kueue-admission",
+		},
+		"another field manager": {
+			fieldOwner: "kueue-admission-audit",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			token, ok := FencingToken("kueue-admission", tc.fieldOwner)
+			if token != tc.wantToken || ok != tc.wantOK {
+				t.Errorf("FencingToken() = (%d, %t), want (%d, %t)", token, ok, tc.wantToken, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsExtendedResource(t *testing.T) {
+	cases := map[corev1.ResourceName]bool{
+		corev1.ResourceCPU:              false,
+		corev1.ResourceMemory:           false,
+		"hugepages-2Mi":                 false,
+		"kubernetes.io/batteries":       false,
+		"requests.example.com/foo":      false,
+		"nvidia.com/gpu":                true,
+		"rdma/hca":                      true,
+		"example.com/kubernetes.io-foo": true,
+	}
+	for name, want := range cases {
+		t.Run(string(name), func(t *testing.T) {
+			if got := IsExtendedResource(name); got != want {
+				t.Errorf("IsExtendedResource(%q) = %t, want %t", name, got, want)
+			}
+		})
+	}
+}
+
+func TestExcludeFlavors(t *testing.T) {
+	cases := map[string]struct {
+		wl          *kueue.Workload
+		retries     map[string]int32
+		wantChanged bool
+		want        []kueue.ExcludedFlavor
+	}{
+		"new flavors": {
+			wl:          utiltesting.MakeWorkload("wl", "ns").Obj(),
+			retries:     map[string]int32{"spot-b": 1, "spot-a": 2},
+			wantChanged: true,
+			want: []kueue.ExcludedFlavor{
+				{Name: "spot-a", RemainingRetries: 2},
+				{Name: "spot-b", RemainingRetries: 1},
+			},
+		},
+		"no retries": {
+			wl:      utiltesting.MakeWorkload("wl", "ns").Obj(),
+			retries: map[string]int32{"spot": 0},
+		},
+		"keeps the largest retries": {
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				ExcludedFlavor("spot-a", 1).
+				ExcludedFlavor("spot-b", 3).
+				Obj(),
+			retries:     map[string]int32{"spot-a": 2, "spot-b": 2},
+			wantChanged: true,
+			want: []kueue.ExcludedFlavor{
+				{Name: "spot-a", RemainingRetries: 2},
+				{Name: "spot-b", RemainingRetries: 3},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if changed := ExcludeFlavors(tc.wl, tc.retries); changed != tc.wantChanged {
+				t.Errorf("ExcludeFlavors() = %t, want %t", changed, tc.wantChanged)
+			}
+			if diff := cmp.Diff(tc.want, tc.wl.Status.ExcludedFlavors); diff != "" {
+				t.Errorf("Unexpected excluded flavors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConsumeExclusionRetry(t *testing.T) {
+	cases := map[string]struct {
+		wl          *kueue.Workload
+		wantChanged bool
+		want        []kueue.ExcludedFlavor
+	}{
+		"no excluded flavors": {
+			wl: utiltesting.MakeWorkload("wl", "ns").Obj(),
+		},
+		"decrements and removes the flavors without retries": {
+			wl: utiltesting.MakeWorkload("wl", "ns").
+				ExcludedFlavor("spot-a", 1).
+				ExcludedFlavor("spot-b", 3).
+				Obj(),
+			wantChanged: true,
+			want:        []kueue.ExcludedFlavor{{Name: "spot-b", RemainingRetries: 2}},
+		},
+		"last retry": {
+			wl:          utiltesting.MakeWorkload("wl", "ns").ExcludedFlavor("spot", 1).Obj(),
+			wantChanged: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if changed := ConsumeExclusionRetry(tc.wl); changed != tc.wantChanged {
+				t.Errorf("ConsumeExclusionRetry() = %t, want %t", changed, tc.wantChanged)
+			}
+			if diff := cmp.Diff(tc.want, tc.wl.Status.ExcludedFlavors); diff != "" {
+				t.Errorf("Unexpected excluded flavors (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
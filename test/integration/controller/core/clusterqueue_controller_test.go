@@ -138,7 +138,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", func() {
 						Type:    kueue.ClusterQueueActive,
 						Status:  metav1.ConditionFalse,
 						Reason:  "FlavorNotFound",
-						Message: "Can't admit new workloads; some flavors are not found",
+						Message: "Can't admit new workloads; the flavors model-a, model-b, on-demand, spot are not found",
 					},
 				},
 			}, ignoreCQConditionTimestamps))
@@ -280,7 +280,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", func() {
 					Type:    kueue.ClusterQueueActive,
 					Status:  metav1.ConditionFalse,
 					Reason:  "FlavorNotFound",
-					Message: "Can't admit new workloads; some flavors are not found",
+					Message: "Can't admit new workloads; the flavors arch-a, arch-b are not found",
 				},
 			}, ignoreCQConditionTimestamps))
 
@@ -296,7 +296,7 @@ var _ = ginkgo.Describe("ClusterQueue controller", func() {
 					Type:    kueue.ClusterQueueActive,
 					Status:  metav1.ConditionFalse,
 					Reason:  "FlavorNotFound",
-					Message: "Can't admit new workloads; some flavors are not found",
+					Message: "Can't admit new workloads; the flavors arch-b are not found",
 				},
 			}, ignoreCQConditionTimestamps))
 
@@ -49,7 +49,7 @@ var _ = ginkgo.Describe("ResourceFlavor controller", func() {
 	ginkgo.When("one clusterQueue references resourceFlavors", func() {
 		var resourceFlavor *kueue.ResourceFlavor
 		var clusterQueue *kueue.ClusterQueue
-		var flavor *kueue.Flavor
+		var flavor *utiltesting.FlavorQuota
 
 		ginkgo.BeforeEach(func() {
 			resourceFlavor = utiltesting.MakeResourceFlavor("cq-refer-resourceflavor").Obj()
@@ -88,7 +88,7 @@ var _ = ginkgo.Describe("ResourceFlavor controller", func() {
 
 			ginkgo.By("Update clusterQueue's flavor")
 			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterQueue), &cq)).To(gomega.Succeed())
-			cq.Spec.Resources[0].Flavors[0].Name = "foo-resourceflavor"
+			cq.Spec.ResourceGroups[0].Flavors[0].Name = "foo-resourceflavor"
 			gomega.Expect(k8sClient.Update(ctx, &cq)).To(gomega.Succeed())
 
 			gomega.Eventually(func() error {
@@ -111,5 +111,37 @@ var _ = ginkgo.Describe("ResourceFlavor controller", func() {
 				return k8sClient.Get(ctx, client.ObjectKeyFromObject(resourceFlavor), &rf)
 			}, util.Timeout, util.Interval).Should(utiltesting.BeNotFoundError())
 		})
+
+		ginkgo.It("Should evict the workloads admitted in the resourceFlavor before deleting it", func() {
+			defer func() { gomega.Expect(util.DeleteClusterQueue(ctx, k8sClient, clusterQueue)).To(gomega.Succeed()) }()
+
+			ginkgo.By("Admit a workload in the resourceFlavor")
+			wl := utiltesting.MakeWorkload("wl", ns.Name).Request(corev1.ResourceCPU, "1").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl)).To(gomega.Succeed())
+			var updatedWl kueue.Workload
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedWl)).To(gomega.Succeed())
+			updatedWl.Spec.Admission = utiltesting.MakeAdmission(clusterQueue.Name).
+				Flavor(corev1.ResourceCPU, resourceFlavor.Name).Obj()
+			gomega.Expect(k8sClient.Update(ctx, &updatedWl)).To(gomega.Succeed())
+
+			ginkgo.By("Delete the resourceFlavor and stop using it in the clusterQueue")
+			gomega.Expect(util.DeleteResourceFlavor(ctx, k8sClient, resourceFlavor)).To(gomega.Succeed())
+			var cq kueue.ClusterQueue
+			gomega.Eventually(func() error {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterQueue), &cq)).To(gomega.Succeed())
+				cq.Spec.ResourceGroups[0].Flavors[0].Name = "foo-resourceflavor"
+				return k8sClient.Update(ctx, &cq)
+			}, util.Timeout, util.Interval).Should(gomega.Succeed())
+
+			ginkgo.By("The workload is evicted and the resourceFlavor deleted")
+			gomega.Eventually(func() *kueue.Admission {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedWl)).To(gomega.Succeed())
+				return updatedWl.Spec.Admission
+			}, util.Timeout, util.Interval).Should(gomega.BeNil())
+			var rf kueue.ResourceFlavor
+			gomega.Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKeyFromObject(resourceFlavor), &rf)
+			}, util.Timeout, util.Interval).Should(utiltesting.BeNotFoundError())
+		})
 	})
 })
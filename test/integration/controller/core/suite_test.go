@@ -77,6 +77,8 @@ func managerSetup(mgr manager.Manager, ctx context.Context) {
 	cCache := cache.New(mgr.GetClient())
 	queues := queue.NewManager(mgr.GetClient(), cCache)
 
-	failedCtrl, err := core.SetupControllers(mgr, queues, cCache, &config.Configuration{})
+	failedCtrl, err := core.SetupControllers(mgr, queues, cCache, &config.Configuration{
+		UnreliableFlavors: &config.UnreliableFlavors{Enable: true},
+	})
 	gomega.Expect(err).ToNot(gomega.HaveOccurred(), "controller", failedCtrl)
 }
@@ -157,6 +157,207 @@ var _ = ginkgo.Describe("Workload controller", func() {
 				return apimeta.IsStatusConditionTrue(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
 			}, util.Timeout, util.Interval).Should(gomega.BeTrue())
 		})
+
+		ginkgo.It("Should evict the workload when the clusterQueue is drained", func() {
+			ginkgo.By("Create and admit workload")
+			wl = testing.MakeWorkload("one", ns.Name).Queue(localQueue.Name).Request(corev1.ResourceCPU, "1").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl)).To(gomega.Succeed())
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+			updatedQueueWorkload.Spec.Admission = testing.MakeAdmission(clusterQueue.Name).
+				Flavor(corev1.ResourceCPU, flavorOnDemand).Obj()
+			gomega.Expect(k8sClient.Update(ctx, &updatedQueueWorkload)).To(gomega.Succeed())
+			gomega.Eventually(func() bool {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				return apimeta.IsStatusConditionTrue(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeTrue())
+
+			ginkgo.By("Drain the clusterQueue")
+			gomega.Eventually(func() error {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterQueue), &updatedCQ)).To(gomega.Succeed())
+				policy := kueue.HoldAndDrain
+				updatedCQ.Spec.StopPolicy = &policy
+				return k8sClient.Update(ctx, &updatedCQ)
+			}, util.Timeout, util.Interval).Should(gomega.Succeed())
+
+			gomega.Eventually(func() *metav1.Condition {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				if updatedQueueWorkload.Spec.Admission != nil {
+					return nil
+				}
+				return apimeta.FindStatusCondition(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeComparableTo(&metav1.Condition{
+				Type:    kueue.WorkloadAdmitted,
+				Status:  metav1.ConditionFalse,
+				Reason:  "Inadmissible",
+				Message: fmt.Sprintf("ClusterQueue %s is stopped", clusterQueue.Name),
+			}, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")))
+
+			gomega.Eventually(func() []metav1.Condition {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterQueue), &updatedCQ)).To(gomega.Succeed())
+				return updatedCQ.Status.Conditions
+			}, util.Timeout, util.Interval).Should(gomega.BeComparableTo([]metav1.Condition{
+				{
+					Type:    kueue.ClusterQueueActive,
+					Status:  metav1.ConditionFalse,
+					Reason:  "Stopped",
+					Message: "Can't admit new workloads; clusterQueue is stopped with the HoldAndDrain policy",
+				},
+			}, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")))
+		})
+
+		ginkgo.It("Should evict the workload when it's deactivated", func() {
+			ginkgo.By("Create and admit workload")
+			wl = testing.MakeWorkload("one", ns.Name).Queue(localQueue.Name).Request(resourceGPU, "1").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl)).To(gomega.Succeed())
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+			updatedQueueWorkload.Spec.Admission = testing.MakeAdmission(clusterQueue.Name).
+				Flavor(resourceGPU, flavorOnDemand).Obj()
+			gomega.Expect(k8sClient.Update(ctx, &updatedQueueWorkload)).To(gomega.Succeed())
+			gomega.Eventually(func() bool {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				return apimeta.IsStatusConditionTrue(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeTrue())
+
+			ginkgo.By("Deactivate the workload")
+			gomega.Eventually(func() error {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				updatedQueueWorkload.Spec.Active = pointer.Bool(false)
+				return k8sClient.Update(ctx, &updatedQueueWorkload)
+			}, util.Timeout, util.Interval).Should(gomega.Succeed())
+
+			gomega.Eventually(func() *metav1.Condition {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				if updatedQueueWorkload.Spec.Admission != nil {
+					return nil
+				}
+				return apimeta.FindStatusCondition(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeComparableTo(&metav1.Condition{
+				Type:    kueue.WorkloadAdmitted,
+				Status:  metav1.ConditionFalse,
+				Reason:  "Inactive",
+				Message: "The workload is deactivated",
+			}, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")))
+			gomega.Expect(apimeta.IsStatusConditionFalse(updatedQueueWorkload.Status.Conditions, kueue.WorkloadEvicted)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("Should evict the workload when the quota decreases below the usage", func() {
+			ginkgo.By("Create and admit workload")
+			wl = testing.MakeWorkload("one", ns.Name).Queue(localQueue.Name).Request(resourceGPU, "2").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl)).To(gomega.Succeed())
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+			updatedQueueWorkload.Spec.Admission = testing.MakeAdmission(clusterQueue.Name).
+				Flavor(resourceGPU, flavorOnDemand).Obj()
+			gomega.Expect(k8sClient.Update(ctx, &updatedQueueWorkload)).To(gomega.Succeed())
+			gomega.Eventually(func() bool {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				return apimeta.IsStatusConditionTrue(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeTrue())
+
+			ginkgo.By("Decrease the quota of the clusterQueue")
+			gomega.Eventually(func() error {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterQueue), &updatedCQ)).To(gomega.Succeed())
+				policy := kueue.OverQuotaPolicyEvict
+				updatedCQ.Spec.OverQuotaPolicy = &policy
+				updatedCQ.Spec.ResourceGroups = testing.ResourceGroups(
+					testing.MakeResource(resourceGPU).Flavor(testing.MakeFlavor(flavorOnDemand, "1").Obj()).Obj(),
+				)
+				return k8sClient.Update(ctx, &updatedCQ)
+			}, util.Timeout, util.Interval).Should(gomega.Succeed())
+
+			gomega.Eventually(func() *metav1.Condition {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				if updatedQueueWorkload.Spec.Admission != nil {
+					return nil
+				}
+				return apimeta.FindStatusCondition(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeComparableTo(&metav1.Condition{
+				Type:    kueue.WorkloadAdmitted,
+				Status:  metav1.ConditionFalse,
+				Reason:  kueue.WorkloadEvictedByClusterQueueOverQuota,
+				Message: fmt.Sprintf("Evicted to bring the usage of the ClusterQueue %s back under its quota", clusterQueue.Name),
+			}, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")))
+			gomega.Expect(apimeta.IsStatusConditionFalse(updatedQueueWorkload.Status.Conditions, kueue.WorkloadEvicted)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.When("the workload runs on an unreliable flavor", func() {
+		var spotFlavor *kueue.ResourceFlavor
+
+		ginkgo.BeforeEach(func() {
+			spotFlavor = testing.MakeResourceFlavor(flavorSpot).Label("instance-type", flavorSpot).Unreliable(2).Obj()
+			gomega.Expect(k8sClient.Create(ctx, spotFlavor)).Should(gomega.Succeed())
+			clusterQueue = testing.MakeClusterQueue("cluster-queue").
+				Resource(testing.MakeResource(corev1.ResourceCPU).
+					Flavor(testing.MakeFlavor(flavorSpot, "5").Obj()).Obj()).
+				Obj()
+			gomega.Expect(k8sClient.Create(ctx, clusterQueue)).To(gomega.Succeed())
+			localQueue = testing.MakeLocalQueue("queue", ns.Name).ClusterQueue(clusterQueue.Name).Obj()
+			gomega.Expect(k8sClient.Create(ctx, localQueue)).To(gomega.Succeed())
+		})
+		ginkgo.AfterEach(func() {
+			gomega.Expect(util.DeleteNamespace(ctx, k8sClient, ns)).To(gomega.Succeed())
+			gomega.Expect(util.DeleteResourceFlavor(ctx, k8sClient, spotFlavor)).To(gomega.Succeed())
+			gomega.Expect(util.DeleteClusterQueue(ctx, k8sClient, clusterQueue)).To(gomega.Succeed())
+		})
+
+		ginkgo.It("Should evict the workload and exclude the flavor when its node is reclaimed", func() {
+			ginkgo.By("Create and admit workload")
+			wl = testing.MakeWorkload("one", ns.Name).Queue(localQueue.Name).Request(corev1.ResourceCPU, "1").Obj()
+			gomega.Expect(k8sClient.Create(ctx, wl)).To(gomega.Succeed())
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+			updatedQueueWorkload.Spec.Admission = testing.MakeAdmission(clusterQueue.Name).
+				Flavor(corev1.ResourceCPU, flavorSpot).Obj()
+			gomega.Expect(k8sClient.Update(ctx, &updatedQueueWorkload)).To(gomega.Succeed())
+			gomega.Eventually(func() bool {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				return apimeta.IsStatusConditionTrue(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeTrue())
+
+			ginkgo.By("Create the pod of the workload on a spot node")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "one", Namespace: ns.Name},
+				Spec: corev1.PodSpec{
+					NodeName:     "spot-1",
+					NodeSelector: map[string]string{"instance-type": flavorSpot},
+					Containers:   []corev1.Container{{Name: "c", Image: "pause"}},
+				},
+			}
+			gomega.Expect(k8sClient.Create(ctx, pod)).To(gomega.Succeed())
+			gomega.Eventually(func() error {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				updatedQueueWorkload.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Name:       pod.Name,
+					UID:        pod.UID,
+				}}
+				return k8sClient.Update(ctx, &updatedQueueWorkload)
+			}, util.Timeout, util.Interval).Should(gomega.Succeed())
+
+			ginkgo.By("Reclaim the node of the pod")
+			pod.Status.Conditions = []corev1.PodCondition{{
+				Type:   corev1.AlphaNoCompatGuaranteeDisruptionTarget,
+				Status: corev1.ConditionTrue,
+				Reason: "DeletionByTaintManager",
+			}}
+			gomega.Expect(k8sClient.Status().Update(ctx, pod)).To(gomega.Succeed())
+
+			gomega.Eventually(func() *metav1.Condition {
+				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(wl), &updatedQueueWorkload)).To(gomega.Succeed())
+				if updatedQueueWorkload.Spec.Admission != nil {
+					return nil
+				}
+				return apimeta.FindStatusCondition(updatedQueueWorkload.Status.Conditions, kueue.WorkloadAdmitted)
+			}, util.Timeout, util.Interval).Should(gomega.BeComparableTo(&metav1.Condition{
+				Type:    kueue.WorkloadAdmitted,
+				Status:  metav1.ConditionFalse,
+				Reason:  kueue.WorkloadEvictedByNodeReclaimed,
+				Message: "The pod one was disrupted because its node spot-1, of the unreliable flavor spot, was reclaimed",
+			}, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")))
+			gomega.Expect(updatedQueueWorkload.Status.ExcludedFlavors).To(gomega.BeComparableTo([]kueue.ExcludedFlavor{
+				{Name: flavorSpot, RemainingRetries: 2},
+			}))
+		})
 	})
 
 	ginkgo.When("Workload with RuntimeClass defined", func() {
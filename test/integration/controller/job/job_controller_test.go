@@ -33,6 +33,7 @@ import (
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1alpha2"
 	"sigs.k8s.io/kueue/pkg/constants"
 	workloadjob "sigs.k8s.io/kueue/pkg/controller/workload/job"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
 	"sigs.k8s.io/kueue/pkg/util/pointer"
 	"sigs.k8s.io/kueue/pkg/util/testing"
 	"sigs.k8s.io/kueue/pkg/workload"
@@ -58,7 +59,7 @@ var _ = ginkgo.Describe("Job controller", func() {
 
 	ginkgo.BeforeEach(func() {
 		fwk = &framework.Framework{
-			ManagerSetup: managerSetup(workloadjob.WithManageJobsWithoutQueueName(true)),
+			ManagerSetup: managerSetup(jobframework.WithManageJobsWithoutQueueName(true)),
 			CRDPath:      crdPath,
 		}
 		ctx, cfg, k8sClient = fwk.Setup()
@@ -107,7 +108,7 @@ var _ = ginkgo.Describe("Job controller", func() {
 		}, util.Timeout, util.Interval).Should(gomega.BeTrue())
 
 		ginkgo.By("checking a second non-matching workload is deleted")
-		secondWl, _ := workloadjob.ConstructWorkloadFor(ctx, k8sClient, createdJob, scheme.Scheme)
+		secondWl, _ := jobframework.ConstructWorkload(ctx, k8sClient, (*workloadjob.Job)(createdJob), scheme.Scheme)
 		secondWl.Name = "second-workload"
 		secondWl.Spec.PodSets[0].Count = parallelism + 1
 		gomega.Expect(k8sClient.Create(ctx, secondWl)).Should(gomega.Succeed())
@@ -233,6 +234,44 @@ var _ = ginkgo.Describe("Job controller", func() {
 				createdWorkload.Status.Conditions[0].Status == metav1.ConditionTrue
 		}, util.Timeout, util.Interval).Should(gomega.BeTrue())
 	})
+
+	ginkgo.It("Should suspend the job and clear the admission when the workload is evicted", func() {
+		job := testing.MakeJob(jobName, jobNamespace).Obj()
+		gomega.Expect(k8sClient.Create(ctx, job)).Should(gomega.Succeed())
+		lookupKey := types.NamespacedName{Name: jobName, Namespace: jobNamespace}
+		createdJob := &batchv1.Job{}
+		createdWorkload := &kueue.Workload{}
+		gomega.Eventually(func() error {
+			return k8sClient.Get(ctx, lookupKey, createdWorkload)
+		}, util.Timeout, util.Interval).Should(gomega.Succeed())
+
+		ginkgo.By("admitting the workload")
+		createdWorkload.Spec.Admission = testing.MakeAdmission("cluster-queue").Obj()
+		gomega.Expect(k8sClient.Update(ctx, createdWorkload)).Should(gomega.Succeed())
+		gomega.Eventually(func() *bool {
+			gomega.Expect(k8sClient.Get(ctx, lookupKey, createdJob)).Should(gomega.Succeed())
+			return createdJob.Spec.Suspend
+		}, util.Timeout, util.Interval).Should(gomega.Equal(pointer.Bool(false)))
+
+		ginkgo.By("evicting the workload")
+		gomega.Expect(k8sClient.Get(ctx, lookupKey, createdWorkload)).Should(gomega.Succeed())
+		gomega.Expect(workload.Evict(ctx, k8sClient, createdWorkload, kueue.WorkloadEvictedByPreemption, "Preempted by another workload")).Should(gomega.Succeed())
+
+		ginkgo.By("checking the job is suspended and the admission cleared")
+		gomega.Eventually(func() *bool {
+			gomega.Expect(k8sClient.Get(ctx, lookupKey, createdJob)).Should(gomega.Succeed())
+			return createdJob.Spec.Suspend
+		}, util.Timeout, util.Interval).Should(gomega.Equal(pointer.Bool(true)))
+		gomega.Eventually(func() bool {
+			ok, _ := testing.CheckLatestEvent(ctx, k8sClient, "Stopped", corev1.EventTypeNormal, "Preempted by another workload")
+			return ok
+		}, util.Timeout, util.Interval).Should(gomega.BeTrue())
+		gomega.Eventually(func() *kueue.Admission {
+			gomega.Expect(k8sClient.Get(ctx, lookupKey, createdWorkload)).Should(gomega.Succeed())
+			return createdWorkload.Spec.Admission
+		}, util.Timeout, util.Interval).Should(gomega.BeNil())
+	})
+
 	ginkgo.When("The parent-workload annotation is used", func() {
 
 		var (
@@ -397,7 +436,7 @@ var _ = ginkgo.Describe("Job controller when waitForPodsReady enabled", func() {
 
 	ginkgo.BeforeEach(func() {
 		fwk = &framework.Framework{
-			ManagerSetup: managerSetup(workloadjob.WithWaitForPodsReady(true)),
+			ManagerSetup: managerSetup(jobframework.WithWaitForPodsReady(true)),
 			CRDPath:      crdPath,
 		}
 		ctx, cfg, k8sClient = fwk.Setup()
@@ -25,7 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/kueue/pkg/constants"
-	"sigs.k8s.io/kueue/pkg/controller/workload/job"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
 	"sigs.k8s.io/kueue/pkg/util/pointer"
 	"sigs.k8s.io/kueue/pkg/util/testing"
 	"sigs.k8s.io/kueue/test/integration/framework"
@@ -39,7 +39,7 @@ var _ = ginkgo.Describe("Job Webhook", func() {
 
 		ginkgo.BeforeEach(func() {
 			fwk = &framework.Framework{
-				ManagerSetup: managerSetup(job.WithManageJobsWithoutQueueName(true)),
+				ManagerSetup: managerSetup(jobframework.WithManageJobsWithoutQueueName(true)),
 				CRDPath:      crdPath,
 				WebhookPath:  webhookPath,
 			}
@@ -89,7 +89,7 @@ var _ = ginkgo.Describe("Job Webhook", func() {
 
 		ginkgo.BeforeEach(func() {
 			fwk = &framework.Framework{
-				ManagerSetup: managerSetup(job.WithManageJobsWithoutQueueName(false)),
+				ManagerSetup: managerSetup(jobframework.WithManageJobsWithoutQueueName(false)),
 				CRDPath:      crdPath,
 				WebhookPath:  webhookPath,
 			}
@@ -135,6 +135,24 @@ var _ = ginkgo.Describe("Job Webhook", func() {
 			}, util.Timeout, util.Interval).Should(gomega.BeTrue())
 		})
 
+		ginkgo.It("should assign the default LocalQueue of the namespace to a Job without queue name", func() {
+			ns.Annotations = map[string]string{constants.DefaultQueueAnnotation: "team-queue"}
+			gomega.Expect(k8sClient.Update(ctx, ns)).Should(gomega.Succeed())
+
+			var createdJob *batchv1.Job
+			gomega.Eventually(func() string {
+				// The webhook might not see the annotation of the namespace
+				// right away, so every attempt creates a new Job.
+				createdJob = testing.MakeJob("", ns.Name).Suspend(false).Obj()
+				createdJob.GenerateName = "job-without-queue-name-"
+				if err := k8sClient.Create(ctx, createdJob); err != nil {
+					return ""
+				}
+				return createdJob.Annotations[constants.QueueAnnotation]
+			}, util.Timeout, util.Interval).Should(gomega.Equal("team-queue"))
+			gomega.Expect(createdJob.Spec.Suspend).Should(gomega.Equal(pointer.Bool(true)))
+		})
+
 		ginkgo.It("should not update unsuspend Job successfully when changing queue name", func() {
 			job := testing.MakeJob("job-with-queue-name", ns.Name).Queue("queue").Obj()
 			gomega.Expect(k8sClient.Create(ctx, job)).Should(gomega.Succeed())
@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/kueue/pkg/constants"
 	"sigs.k8s.io/kueue/pkg/controller/core"
 	"sigs.k8s.io/kueue/pkg/controller/workload/job"
+	"sigs.k8s.io/kueue/pkg/controller/workload/jobframework"
 	"sigs.k8s.io/kueue/pkg/queue"
 	"sigs.k8s.io/kueue/pkg/scheduler"
 	"sigs.k8s.io/kueue/test/integration/framework"
@@ -55,7 +56,7 @@ func TestAPIs(t *testing.T) {
 	)
 }
 
-func managerSetup(opts ...job.Option) framework.ManagerSetup {
+func managerSetup(opts ...jobframework.Option) framework.ManagerSetup {
 	return func(mgr manager.Manager, ctx context.Context) {
 		reconciler := job.NewReconciler(
 			mgr.GetScheme(),
@@ -66,12 +67,12 @@ func managerSetup(opts ...job.Option) framework.ManagerSetup {
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		err = reconciler.SetupWithManager(mgr)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
-		err = job.SetupWebhook(mgr, opts...)
+		err = job.SetupWebhook(mgr, append(opts, jobframework.WithEnabled(true))...)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	}
 }
 
-func managerAndSchedulerSetup(opts ...job.Option) framework.ManagerSetup {
+func managerAndSchedulerSetup(opts ...jobframework.Option) framework.ManagerSetup {
 	return func(mgr manager.Manager, ctx context.Context) {
 		err := queue.SetupIndexes(ctx, mgr.GetFieldIndexer())
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
@@ -90,7 +91,7 @@ func managerAndSchedulerSetup(opts ...job.Option) framework.ManagerSetup {
 		err = job.NewReconciler(mgr.GetScheme(), mgr.GetClient(),
 			mgr.GetEventRecorderFor(constants.JobControllerName), opts...).SetupWithManager(mgr)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
-		err = job.SetupWebhook(mgr, opts...)
+		err = job.SetupWebhook(mgr, append(opts, jobframework.WithEnabled(true))...)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
 		sched := scheduler.New(queues, cCache, mgr.GetClient(), mgr.GetEventRecorderFor(constants.AdmissionName))
@@ -83,6 +83,12 @@ var _ = ginkgo.Describe("ClusterQueue Webhook", func() {
 						Preemption: &kueue.ClusterQueuePreemption{
 							WithinClusterQueue:  kueue.PreemptionPolicyNever,
 							ReclaimWithinCohort: kueue.PreemptionPolicyNever,
+							VictimOrder:         kueue.PreemptionVictimOrderPriority,
+						},
+						FlavorFungibility: &kueue.FlavorFungibility{
+							WhenCanBorrow:   kueue.FlavorFungibilityPolicyBorrow,
+							WhenCanPreempt:  kueue.FlavorFungibilityPolicyTryNextFlavor,
+							PodSetPlacement: kueue.PodSetPlacementPolicyMixed,
 						},
 					},
 				},
@@ -109,6 +115,12 @@ var _ = ginkgo.Describe("ClusterQueue Webhook", func() {
 						Preemption: &kueue.ClusterQueuePreemption{
 							WithinClusterQueue:  kueue.PreemptionPolicyLowerPriority,
 							ReclaimWithinCohort: kueue.PreemptionPolicyAny,
+							VictimOrder:         kueue.PreemptionVictimOrderPriority,
+						},
+						FlavorFungibility: &kueue.FlavorFungibility{
+							WhenCanBorrow:   kueue.FlavorFungibilityPolicyBorrow,
+							WhenCanPreempt:  kueue.FlavorFungibilityPolicyTryNextFlavor,
+							PodSetPlacement: kueue.PodSetPlacementPolicyMixed,
 						},
 					},
 				},
@@ -127,9 +139,9 @@ var _ = ginkgo.Describe("ClusterQueue Webhook", func() {
 			gomega.Eventually(func() error {
 				var updateCQ kueue.ClusterQueue
 				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cq), &updateCQ)).Should(gomega.Succeed())
-				updateCQ.Spec.Resources = []kueue.Resource{
-					*testing.MakeResource("@cpu").Flavor(testing.MakeFlavor("x86", "5").Obj()).Obj(),
-				}
+				updateCQ.Spec.ResourceGroups = testing.ResourceGroups(
+					testing.MakeResource("@cpu").Flavor(testing.MakeFlavor("x86", "5").Obj()).Obj(),
+				)
 				return k8sClient.Update(ctx, &updateCQ)
 			}, util.Timeout, util.Interval).Should(testing.BeForbiddenError())
 		})
@@ -147,7 +159,7 @@ var _ = ginkgo.Describe("ClusterQueue Webhook", func() {
 			gomega.Eventually(func() error {
 				var updateCQ kueue.ClusterQueue
 				gomega.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cq), &updateCQ)).Should(gomega.Succeed())
-				updateCQ.Spec.Resources[0].Flavors[0].Name = "invalid_name"
+				updateCQ.Spec.ResourceGroups[0].Flavors[0].Name = "invalid_name"
 				return k8sClient.Update(ctx, &updateCQ)
 			}, util.Timeout, util.Interval).Should(testing.BeForbiddenError())
 		})